@@ -0,0 +1,402 @@
+// Comando canary verifica o caminho completo de uma mensagem contra uma
+// instância rodando, como um cliente de verdade: registra um par de usuários
+// descartáveis, conecta o destinatário no WebSocket, envia uma mensagem pela
+// API (banco + barramento) e mede quanto tempo o frame leva para chegar.
+//
+// Uso:
+//
+//	go run ./cmd/canary -api=https://chat.example.com -interval=30s -metrics=:9464
+//
+// Métricas Prometheus em -metrics (/metrics): rodadas por resultado e etapa
+// que falhou, latência por etapa e horário do último sucesso (alerta:
+// time() - chat_canary_last_success_timestamp_seconds > N). Com -once roda
+// uma única vez e sai com código 1 em falha (cron, smoke test de deploy).
+//
+// O par é trocado a cada -rotate (a API pública não remove usuários; use um
+// domínio de email reconhecível para limpar depois). Com MESSAGING_POLICY=friends
+// o envio entre o par é recusado: o canary exige a política everyone.
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"chat-kafka-go/pkg/types"
+
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Etapas de uma rodada (label stage das métricas)
+const (
+	stageRegister = "register"
+	stageRefresh  = "refresh"
+	stageConnect  = "connect"
+	stageSend     = "send"
+	stageDelivery = "delivery"
+	stageTotal    = "total"
+)
+
+var (
+	runsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "chat_canary_runs_total",
+		Help: "Rodadas do canary por resultado (success, failure) e etapa que falhou",
+	}, []string{"result", "stage"})
+
+	stageSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "chat_canary_stage_seconds",
+		Help:    "Duração de cada etapa bem-sucedida do canary (delivery: envio -> frame no WebSocket)",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 12),
+	}, []string{"stage"})
+
+	lastSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "chat_canary_last_success_timestamp_seconds",
+		Help: "Horário Unix da última rodada completa com sucesso",
+	})
+)
+
+// stageError falha atribuída a uma etapa
+type stageError struct {
+	stage string
+	err   error
+}
+
+func (e *stageError) Error() string { return e.stage + ": " + e.err.Error() }
+
+func failed(stage string, err error) error { return &stageError{stage: stage, err: err} }
+
+// account usuário descartável do par
+type account struct {
+	id       string
+	username string
+	tokens   types.TokenPair
+	issued   time.Time
+}
+
+// canary estado entre rodadas
+type canary struct {
+	api          *url.URL
+	client       *http.Client
+	timeout      time.Duration
+	rotate       time.Duration
+	refreshAfter time.Duration
+	emailDomain  string
+
+	sender, receiver *account
+	pairSince        time.Time
+}
+
+func main() {
+	apiFlag := flag.String("api", "http://localhost:8080", "URL base da API pública")
+	interval := flag.Duration("interval", 30*time.Second, "intervalo entre rodadas")
+	timeout := flag.Duration("timeout", 10*time.Second, "prazo de cada etapa (inclusive a chegada do frame)")
+	rotate := flag.Duration("rotate", 24*time.Hour, "troca o par de usuários depois desse tempo")
+	refreshAfter := flag.Duration("refresh-after", 30*time.Minute, "renova os tokens depois desse tempo (menor que a validade do access token)")
+	emailDomain := flag.String("email-domain", "canary.example.com", "domínio dos emails dos usuários descartáveis")
+	metricsAddr := flag.String("metrics", ":9464", "endereço das métricas Prometheus (vazio = desligado)")
+	once := flag.Bool("once", false, "roda uma vez e sai (código 1 em falha)")
+	flag.Parse()
+
+	api, err := url.Parse(strings.TrimRight(*apiFlag, "/"))
+	if err != nil || api.Host == "" {
+		log.Fatalf("Erro: -api inválida: %q", *apiFlag)
+	}
+
+	c := &canary{
+		api:          api,
+		client:       &http.Client{Timeout: *timeout},
+		timeout:      *timeout,
+		rotate:       *rotate,
+		refreshAfter: *refreshAfter,
+		emailDomain:  *emailDomain,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *once {
+		if err := c.round(ctx); err != nil {
+			log.Printf("ERRO: canary: %v", err)
+			os.Exit(1)
+		}
+		log.Printf("✓ Canary ok")
+		return
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(runsTotal, stageSeconds, lastSuccess)
+	if *metricsAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("GET /metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+		srv := &http.Server{Addr: *metricsAddr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			log.Printf("✓ Métricas do canary em %s/metrics", *metricsAddr)
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatalf("Erro no servidor de métricas: %v", err)
+			}
+		}()
+		defer srv.Close()
+	}
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		c.observe(ctx)
+		select {
+		case <-ctx.Done():
+			log.Println("Encerrando canary...")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// observe roda uma rodada e registra o resultado nas métricas
+func (c *canary) observe(ctx context.Context) {
+	start := time.Now()
+	err := c.round(ctx)
+	if err == nil {
+		runsTotal.WithLabelValues("success", "").Inc()
+		stageSeconds.WithLabelValues(stageTotal).Observe(time.Since(start).Seconds())
+		lastSuccess.SetToCurrentTime()
+		return
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	stage := "unknown"
+	var se *stageError
+	if errors.As(err, &se) {
+		stage = se.stage
+	}
+	runsTotal.WithLabelValues("failure", stage).Inc()
+	log.Printf("ERRO: canary: %v", err)
+
+	// Par possivelmente inválido (usuário removido, refresh revogado): troca
+	if stage == stageRegister || stage == stageRefresh {
+		c.sender, c.receiver = nil, nil
+	}
+}
+
+// round uma verificação completa: par válido, destinatário conectado,
+// envio pela API e frame "message" no WebSocket
+func (c *canary) round(ctx context.Context) error {
+	if err := c.ensurePair(ctx); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	conn, err := c.connect(ctx, c.receiver)
+	if err != nil {
+		return failed(stageConnect, err)
+	}
+	defer conn.Close()
+	stageSeconds.WithLabelValues(stageConnect).Observe(time.Since(start).Seconds())
+
+	frames := make(chan string, 16)
+	done := make(chan struct{})
+	defer close(done)
+	go readMessageIDs(conn, frames, done)
+
+	start = time.Now()
+	var sent types.MessageResponse
+	err = c.call(ctx, http.MethodPost, "/messages", c.sender.tokens.AccessToken, types.SendMessageInput{
+		ReceiverID:   c.receiver.id,
+		Content:      "canary " + start.UTC().Format(time.RFC3339Nano),
+		ClientSentAt: start.UTC().Format(time.RFC3339Nano),
+	}, &sent)
+	if err != nil {
+		return failed(stageSend, err)
+	}
+	stageSeconds.WithLabelValues(stageSend).Observe(time.Since(start).Seconds())
+
+	deadline := time.NewTimer(c.timeout)
+	defer deadline.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline.C:
+			return failed(stageDelivery, fmt.Errorf("mensagem %s não chegou em %s", sent.ID, c.timeout))
+		case id, ok := <-frames:
+			if !ok {
+				return failed(stageDelivery, fmt.Errorf("WebSocket fechado antes da mensagem %s", sent.ID))
+			}
+			if id == sent.ID {
+				stageSeconds.WithLabelValues(stageDelivery).Observe(time.Since(start).Seconds())
+				return nil
+			}
+		}
+	}
+}
+
+// ensurePair registra um par novo na primeira rodada e a cada -rotate, e
+// renova os tokens que passaram de -refresh-after
+func (c *canary) ensurePair(ctx context.Context) error {
+	if c.sender == nil || c.receiver == nil || time.Since(c.pairSince) > c.rotate {
+		sender, err := c.register(ctx)
+		if err != nil {
+			return failed(stageRegister, err)
+		}
+		receiver, err := c.register(ctx)
+		if err != nil {
+			return failed(stageRegister, err)
+		}
+		c.sender, c.receiver, c.pairSince = sender, receiver, time.Now()
+		log.Printf("✓ Par do canary: %s -> %s", sender.username, receiver.username)
+		return nil
+	}
+
+	for _, acc := range []*account{c.sender, c.receiver} {
+		if time.Since(acc.issued) < c.refreshAfter {
+			continue
+		}
+		var tokens types.TokenPair
+		err := c.call(ctx, http.MethodPost, "/auth/refresh", "", types.RefreshTokenInput{RefreshToken: acc.tokens.RefreshToken}, &tokens)
+		if err != nil {
+			return failed(stageRefresh, err)
+		}
+		acc.tokens, acc.issued = tokens, time.Now()
+	}
+	return nil
+}
+
+// register cria um usuário descartável
+func (c *canary) register(ctx context.Context) (*account, error) {
+	suffix := make([]byte, 6)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	username := "canary_" + hex.EncodeToString(suffix)
+	password := make([]byte, 16)
+	if _, err := rand.Read(password); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	var resp types.AuthResponse
+	err := c.call(ctx, http.MethodPost, "/auth/register", "", types.RegisterInput{
+		Username: username,
+		Email:    username + "@" + c.emailDomain,
+		Password: hex.EncodeToString(password),
+		DeviceID: "canary",
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.User == nil || resp.Tokens == nil {
+		return nil, fmt.Errorf("registro de %s sem usuário ou tokens", username)
+	}
+	stageSeconds.WithLabelValues(stageRegister).Observe(time.Since(start).Seconds())
+	return &account{id: resp.User.ID, username: username, tokens: *resp.Tokens, issued: time.Now()}, nil
+}
+
+// connect pede um ticket e abre o WebSocket (na instância dona, se indicada)
+func (c *canary) connect(ctx context.Context, acc *account) (*websocket.Conn, error) {
+	var ticket struct {
+		Ticket string `json:"ticket"`
+		WSURL  string `json:"ws_url"`
+	}
+	if err := c.call(ctx, http.MethodPost, "/ws/ticket", acc.tokens.AccessToken, nil, &ticket); err != nil {
+		return nil, err
+	}
+
+	target := ticket.WSURL
+	if target == "" {
+		ws := *c.api
+		ws.Scheme = strings.Replace(ws.Scheme, "http", "ws", 1)
+		ws.Path += "/ws"
+		target = ws.String()
+	}
+	target += "?ticket=" + url.QueryEscape(ticket.Ticket)
+
+	dialer := websocket.Dialer{HandshakeTimeout: c.timeout}
+	conn, _, err := dialer.DialContext(ctx, target, nil)
+	return conn, err
+}
+
+// readMessageIDs repassa os IDs dos frames "message" até a conexão fechar
+// ou a rodada terminar (done)
+func readMessageIDs(conn *websocket.Conn, ids chan<- string, done <-chan struct{}) {
+	defer close(ids)
+	for {
+		var frame struct {
+			Type string `json:"type"`
+			Data struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		}
+		if err := conn.ReadJSON(&frame); err != nil {
+			return
+		}
+		if frame.Type != "message" {
+			continue
+		}
+		select {
+		case ids <- frame.Data.ID:
+		case <-done:
+			return
+		}
+	}
+}
+
+// call faz uma requisição JSON e decodifica data da resposta padrão
+func (c *canary) call(ctx context.Context, method, path, token string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.api.String()+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "chat-canary")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var envelope struct {
+		Data  json.RawMessage `json:"data"`
+		Error string          `json:"error"`
+		Code  string          `json:"code"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&envelope); err != nil {
+		return fmt.Errorf("%s %s: resposta %d inválida: %w", method, path, resp.StatusCode, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s %s: %d %s (%s)", method, path, resp.StatusCode, envelope.Code, envelope.Error)
+	}
+	if out == nil || len(envelope.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(envelope.Data, out)
+}
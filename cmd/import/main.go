@@ -0,0 +1,206 @@
+// Comando import regrava mensagens a partir do arquivo de compliance em
+// diretório (ARCHIVE_SINK=dir, <AAAA>/<MM>/<DD>/<id>.json): recupera
+// mensagens perdidas no banco (partição removida, backup antigo) quando o
+// tópico Kafka já não tem o histórico para o rebuild -targets=messages.
+//
+// Uso:
+//
+//	go run ./cmd/import -dir=/var/lib/chat/archive -since=2026-01-01 -until=2026-03-31
+//
+// As mensagens entram via COPY em lotes de -batch como accepted (o arquivo
+// não guarda entregas e leituras); mensagens já gravadas e de usuários que
+// não existem mais são puladas. Rodar de novo é seguro. Resumos de conversa
+// não são alterados.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"chat-kafka-go/internal/archive"
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/database"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/status"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const dateLayout = "2006-01-02"
+
+func main() {
+	dir := flag.String("dir", "", "diretório do arquivo de compliance (default: ARCHIVE_DIR)")
+	sinceFlag := flag.String("since", "", "primeiro dia a importar (AAAA-MM-DD, opcional)")
+	untilFlag := flag.String("until", "", "último dia a importar (AAAA-MM-DD, opcional)")
+	batchSize := flag.Int("batch", 5000, "mensagens por lote do COPY")
+	flag.Parse()
+
+	since, until, err := parseRange(*sinceFlag, *untilFlag)
+	if err != nil {
+		log.Fatalf("Erro: %v", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Erro ao carregar config: %v", err)
+	}
+	if *dir == "" {
+		*dir = cfg.Archive.Dir
+	}
+	if *dir == "" {
+		log.Fatalf("Erro: informe -dir ou ARCHIVE_DIR")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db, err := database.New(ctx, &cfg.Database)
+	if err != nil {
+		log.Fatalf("Erro ao conectar database: %v", err)
+	}
+	defer db.Close()
+
+	started := time.Now()
+	var (
+		total database.BulkResult
+		batch []repository.Message
+	)
+	flush := func() error {
+		res, err := db.CopyMessages(ctx, batch, database.BulkOptions{BatchSize: *batchSize, OnConflict: database.ConflictSkip})
+		total.Processed += res.Processed
+		total.Inserted += res.Inserted
+		batch = batch[:0]
+		if err != nil {
+			return err
+		}
+		log.Printf("Progresso: %d lidas, %d inseridas (%s)", total.Processed, total.Inserted, time.Since(started).Round(time.Second))
+		return nil
+	}
+
+	err = filepath.WalkDir(*dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if d.IsDir() {
+			return skipDay(*dir, path, since, until)
+		}
+		if !strings.HasSuffix(d.Name(), ".json") || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+
+		m, err := readRecord(path)
+		if err != nil {
+			return err
+		}
+		batch = append(batch, m)
+		if len(batch) >= *batchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil && len(batch) > 0 {
+		err = flush()
+	}
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			log.Printf("Interrompido após %d mensagens inseridas; rode de novo para continuar", total.Inserted)
+			os.Exit(1)
+		}
+		log.Fatalf("Erro na importação: %v", err)
+	}
+
+	log.Printf("✓ Importação concluída em %s: %d lidas, %d inseridas, %d puladas",
+		time.Since(started).Round(time.Second), total.Processed, total.Inserted, total.Skipped())
+}
+
+// parseRange valida o intervalo de dias (limites vazios ficam em aberto)
+func parseRange(sinceFlag, untilFlag string) (since, until time.Time, err error) {
+	if sinceFlag != "" {
+		if since, err = time.Parse(dateLayout, sinceFlag); err != nil {
+			return since, until, fmt.Errorf("-since inválido: %w", err)
+		}
+	}
+	if untilFlag != "" {
+		if until, err = time.Parse(dateLayout, untilFlag); err != nil {
+			return since, until, fmt.Errorf("-until inválido: %w", err)
+		}
+		if !since.IsZero() && until.Before(since) {
+			return since, until, errors.New("-until antes de -since")
+		}
+	}
+	return since, until, nil
+}
+
+// skipDay pula diretórios de dia (<AAAA>/<MM>/<DD>) fora do intervalo
+func skipDay(root, path string, since, until time.Time) error {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return err
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) != 3 {
+		return nil
+	}
+	day, err := time.Parse(dateLayout, strings.Join(parts, "-"))
+	if err != nil {
+		return fs.SkipDir // Não é um dia do arquivo
+	}
+	if (!since.IsZero() && day.Before(since)) || (!until.IsZero() && day.After(until)) {
+		return fs.SkipDir
+	}
+	return nil
+}
+
+// readRecord lê um registro do arquivo como mensagem accepted
+func readRecord(path string) (repository.Message, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return repository.Message{}, fmt.Errorf("erro ao ler %s: %w", path, err)
+	}
+	var record archive.Record
+	if err := json.Unmarshal(body, &record); err != nil {
+		return repository.Message{}, fmt.Errorf("registro inválido %s: %w", path, err)
+	}
+
+	id, err := utils.StringToUUID(record.ID)
+	if err != nil {
+		return repository.Message{}, fmt.Errorf("%s: id: %w", path, err)
+	}
+	senderID, err := utils.StringToUUID(record.SenderID)
+	if err != nil {
+		return repository.Message{}, fmt.Errorf("%s: sender_id: %w", path, err)
+	}
+	receiverID, err := utils.StringToUUID(record.ReceiverID)
+	if err != nil {
+		return repository.Message{}, fmt.Errorf("%s: receiver_id: %w", path, err)
+	}
+	sentAt, err := time.Parse(time.RFC3339, record.SentAt)
+	if err != nil {
+		return repository.Message{}, fmt.Errorf("%s: sent_at: %w", path, err)
+	}
+
+	return repository.Message{
+		ID:         id,
+		SenderID:   senderID,
+		ReceiverID: receiverID,
+		Content:    record.Content,
+		Status:     status.MessageAccepted,
+		CreatedAt:  pgtype.Timestamp{Time: sentAt.UTC(), Valid: true},
+		Version:    1,
+	}, nil
+}
@@ -0,0 +1,343 @@
+// Comando rebuild reconstrói os modelos de leitura derivados dos eventos de
+// mensagem (resumos de conversa, não lidas e índice de busca) relendo o tópico
+// Kafka desde o início. O alvo archive faz o backfill do arquivo de
+// compliance (ARCHIVE_SINK); registros já arquivados não são duplicados. O
+// alvo messages regrava a tabela messages (ex: depois de restaurar um backup)
+// via COPY em lotes de -batch: mensagens que faltam voltam e as existentes só
+// avançam de status, pela versão.
+//
+// Uso:
+//
+//	go run ./cmd/rebuild -targets=summaries,search -reset
+//
+// O progresso é salvo em -checkpoint; rodar de novo retoma de onde parou
+// (até o high water mark registrado na primeira execução). -reset só tem
+// efeito numa execução nova e exige que o tópico ainda tenha todo o
+// histórico (retenção), senão conversas antigas somem dos resumos.
+// Só disponível com EVENT_BUS=kafka.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"chat-kafka-go/internal/archive"
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/database"
+	"chat-kafka-go/internal/eventbus"
+	"chat-kafka-go/internal/kafka"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/internal/search"
+	"chat-kafka-go/internal/worker"
+)
+
+// Modelos de leitura reconstruíveis
+const (
+	targetSummaries = "summaries"
+	targetSearch    = "search"
+	targetArchive   = "archive"
+	targetMessages  = "messages"
+)
+
+// checkpoint estado persistido entre execuções
+type checkpoint struct {
+	Topic   string        `json:"topic"`
+	Targets []string      `json:"targets"`
+	Reset   bool          `json:"reset"`
+	Until   kafka.Offsets `json:"until"` // High water mark no início da reconstrução
+	Next    kafka.Offsets `json:"next"`  // Próximo offset a processar
+}
+
+func main() {
+	targetsFlag := flag.String("targets", targetSummaries+","+targetSearch, "modelos a reconstruir: summaries, search, archive, messages")
+	checkpointPath := flag.String("checkpoint", "rebuild.checkpoint.json", "arquivo de checkpoint")
+	reset := flag.Bool("reset", false, "esvazia os resumos de conversa antes de reler (preserva marcações de leitura)")
+	progressEvery := flag.Duration("progress", 5*time.Second, "intervalo do relatório de progresso")
+	batchSize := flag.Int("batch", 5000, "mensagens por lote do COPY (alvo messages)")
+	flag.Parse()
+
+	targets, err := parseTargets(*targetsFlag)
+	if err != nil {
+		log.Fatalf("Erro: %v", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Erro ao carregar config: %v", err)
+	}
+	if cfg.EventBus.Backend != eventbus.BackendKafka {
+		log.Fatalf("Erro: reconstrução exige EVENT_BUS=kafka (atual: %s)", cfg.EventBus.Backend)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db, err := database.New(ctx, &cfg.Database)
+	if err != nil {
+		log.Fatalf("Erro ao conectar database: %v", err)
+	}
+	defer db.Close()
+	queries := repository.New(db.Pool)
+
+	replayer, err := kafka.NewReplayer(&cfg.Kafka)
+	if err != nil {
+		log.Fatalf("Erro ao conectar no Kafka: %v", err)
+	}
+	defer replayer.Close()
+
+	// Handlers dos modelos escolhidos, aplicados em sequência a cada evento
+	var handlers []eventbus.Handler
+	var restorer *worker.MessageRestorer
+	if slices.Contains(targets, targetMessages) {
+		restorer = worker.NewMessageRestorer(db, *batchSize)
+		handlers = append(handlers, restorer.Handle)
+	}
+	if slices.Contains(targets, targetSummaries) {
+		// Sem hub nem notifier: só os resumos, nada é reenviado aos usuários
+		handlers = append(handlers, worker.NewMessageProcessor(queries, nil, nil).Handle)
+	}
+	if slices.Contains(targets, targetSearch) {
+		index := search.New(cfg.Search.ElasticsearchURL, cfg.Search.IndexPrefix,
+			cfg.Search.Username, cfg.Search.Password, cfg.Search.Timeout)
+		if !index.Enabled() {
+			log.Fatalf("Erro: alvo search exige SEARCH_ES_URL")
+		}
+		if err := index.EnsureTemplate(ctx); err != nil {
+			log.Fatalf("Erro ao criar template do índice: %v", err)
+		}
+		handlers = append(handlers, worker.NewSearchIndexer(index, &cfg.Search).Handle)
+	}
+	if slices.Contains(targets, targetArchive) {
+		sink, err := archive.New(cfg.Archive.Sink, cfg.Archive.WebhookURL,
+			cfg.Archive.WebhookSecret, cfg.Archive.Dir, cfg.Archive.Timeout)
+		if err != nil {
+			log.Fatalf("Erro ao configurar arquivo de compliance: %v", err)
+		}
+		if !sink.Enabled() {
+			log.Fatalf("Erro: alvo archive exige ARCHIVE_SINK")
+		}
+		handlers = append(handlers, worker.NewComplianceArchiver(sink).Handle)
+	}
+
+	cp, resumed, err := loadCheckpoint(*checkpointPath, cfg.Kafka.Topic, targets)
+	if err != nil {
+		log.Fatalf("Erro ao ler checkpoint: %v", err)
+	}
+	if !resumed {
+		oldest, newest, err := replayer.Bounds()
+		if err != nil {
+			log.Fatalf("Erro: %v", err)
+		}
+		cp = &checkpoint{
+			Topic:   cfg.Kafka.Topic,
+			Targets: targets,
+			Reset:   *reset && slices.Contains(targets, targetSummaries),
+			Until:   newest,
+			Next:    oldest,
+		}
+		if cp.Reset {
+			n, err := queries.ResetConversationSummaries(ctx)
+			if err != nil {
+				log.Fatalf("Erro ao esvaziar resumos: %v", err)
+			}
+			log.Printf("✓ %d resumos de conversa esvaziados", n)
+		}
+		if err := cp.save(*checkpointPath); err != nil {
+			log.Fatalf("Erro ao salvar checkpoint: %v", err)
+		}
+	} else {
+		log.Printf("✓ Retomando do checkpoint %s", *checkpointPath)
+	}
+
+	total := cp.remaining()
+	log.Printf("Relendo %s: %d eventos em %d partições", cp.Topic, total, len(cp.Until))
+
+	var (
+		mu        sync.Mutex
+		processed atomic.Int64
+	)
+	done := func(partition int32, next int64) {
+		mu.Lock()
+		cp.Next[partition] = next
+		mu.Unlock()
+		processed.Add(1)
+	}
+	save := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		// O checkpoint só avança depois do lote acumulado estar gravado
+		if restorer != nil {
+			if err := restorer.Flush(ctx); err != nil {
+				log.Printf("ERRO: gravar mensagens: %v", err)
+				return
+			}
+		}
+		if err := cp.save(*checkpointPath); err != nil {
+			log.Printf("ERRO: salvar checkpoint: %v", err)
+		}
+	}
+
+	// Progresso + checkpoint periódico
+	started := time.Now()
+	progressCtx, stopProgress := context.WithCancel(ctx)
+	var progressWG sync.WaitGroup
+	progressWG.Add(1)
+	go func() {
+		defer progressWG.Done()
+		ticker := time.NewTicker(*progressEvery)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-progressCtx.Done():
+				return
+			case <-ticker.C:
+				save()
+				reportProgress(processed.Load(), total, started)
+			}
+		}
+	}()
+
+	handle := func(ctx context.Context, msg *eventbus.Message) error {
+		for _, h := range handlers {
+			if err := h(ctx, msg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	err = replayer.Replay(ctx, cp.Next, cp.Until, handle, done)
+
+	stopProgress()
+	progressWG.Wait()
+	save()
+	reportProgress(processed.Load(), total, started)
+
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			log.Printf("Interrompido; rode de novo para retomar de %s", *checkpointPath)
+			os.Exit(1)
+		}
+		log.Fatalf("Erro na releitura (checkpoint salvo em %s): %v", *checkpointPath, err)
+	}
+
+	if restorer != nil {
+		if err := restorer.Flush(ctx); err != nil {
+			log.Fatalf("Erro ao gravar mensagens (checkpoint salvo em %s): %v", *checkpointPath, err)
+		}
+		res := restorer.Result()
+		log.Printf("✓ Mensagens: %d eventos gravados, %d inseridas, %d atualizadas", res.Processed, res.Inserted, res.Updated)
+	}
+
+	if slices.Contains(cp.Targets, targetSummaries) {
+		if cp.Reset {
+			n, err := queries.DeleteEmptyConversationSummaries(ctx)
+			if err != nil {
+				log.Fatalf("Erro ao remover resumos vazios: %v", err)
+			}
+			log.Printf("✓ %d resumos sem eventos removidos", n)
+		}
+		// Leituras não passam pelo Kafka: não lidas vêm das marcações de leitura
+		n, err := queries.RecomputeUnreadCounts(ctx)
+		if err != nil {
+			log.Fatalf("Erro ao recalcular não lidas: %v", err)
+		}
+		log.Printf("✓ Não lidas recalculadas em %d conversas", n)
+	}
+
+	if err := os.Remove(*checkpointPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("ERRO: remover checkpoint: %v", err)
+	}
+	log.Printf("✓ Reconstrução concluída em %s", time.Since(started).Round(time.Second))
+}
+
+// parseTargets valida a lista de modelos
+func parseTargets(s string) ([]string, error) {
+	var targets []string
+	for _, t := range strings.Split(s, ",") {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		if t != targetSummaries && t != targetSearch && t != targetArchive && t != targetMessages {
+			return nil, fmt.Errorf("alvo inválido: %s", t)
+		}
+		if !slices.Contains(targets, t) {
+			targets = append(targets, t)
+		}
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("nenhum alvo informado")
+	}
+	slices.Sort(targets)
+	return targets, nil
+}
+
+// loadCheckpoint lê o checkpoint; um de outro tópico ou com outros alvos é rejeitado
+func loadCheckpoint(path, topic string, targets []string) (*checkpoint, bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var cp checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, false, fmt.Errorf("checkpoint inválido: %w", err)
+	}
+	if cp.Topic != topic || !slices.Equal(cp.Targets, targets) {
+		return nil, false, fmt.Errorf("checkpoint %s é de outra reconstrução (tópico %s, alvos %v); remova-o para recomeçar",
+			path, cp.Topic, cp.Targets)
+	}
+	if cp.Next == nil {
+		cp.Next = kafka.Offsets{}
+	}
+	return &cp, true, nil
+}
+
+// save grava o checkpoint de forma atômica (arquivo temporário + rename)
+func (c *checkpoint) save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// remaining eventos ainda não processados
+func (c *checkpoint) remaining() int64 {
+	var n int64
+	for partition, end := range c.Until {
+		if start := c.Next[partition]; start < end {
+			n += end - start
+		}
+	}
+	return n
+}
+
+// reportProgress imprime processados, percentual e taxa
+func reportProgress(processed, total int64, started time.Time) {
+	percent := 100.0
+	if total > 0 {
+		percent = float64(processed) / float64(total) * 100
+	}
+	rate := float64(processed) / time.Since(started).Seconds()
+	log.Printf("progresso: %d/%d eventos (%.1f%%), %.0f eventos/s", processed, total, percent, rate)
+}
@@ -0,0 +1,604 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"chat-kafka-go/internal/admin"
+	"chat-kafka-go/internal/antivirus"
+	"chat-kafka-go/internal/archive"
+	"chat-kafka-go/internal/billing"
+	"chat-kafka-go/internal/classifier"
+	"chat-kafka-go/internal/cluster"
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/database"
+	"chat-kafka-go/internal/disposable"
+	"chat-kafka-go/internal/eventbus"
+	"chat-kafka-go/internal/geoip"
+	"chat-kafka-go/internal/handler"
+	"chat-kafka-go/internal/kafka"
+	"chat-kafka-go/internal/logger"
+	"chat-kafka-go/internal/mailer"
+	"chat-kafka-go/internal/maintenance"
+	"chat-kafka-go/internal/reporter"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/internal/risk"
+	"chat-kafka-go/internal/search"
+	"chat-kafka-go/internal/selfcheck"
+	"chat-kafka-go/internal/server"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/internal/signedurl"
+	"chat-kafka-go/internal/slo"
+	"chat-kafka-go/internal/storage"
+	"chat-kafka-go/internal/transcoder"
+	"chat-kafka-go/internal/worker"
+	"chat-kafka-go/internal/ws"
+)
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Erro ao carregar config: %v", err)
+	}
+
+	if err := logger.Setup(&cfg.Log); err != nil {
+		log.Fatalf("Erro ao configurar logs: %v", err)
+	}
+
+	// Reporter de erros (Sentry, log...)
+	rep, err := reporter.New(&cfg.Reporter)
+	if err != nil {
+		log.Fatalf("Erro ao configurar reporter: %v", err)
+	}
+	reporter.SetDefault(rep)
+	defer rep.Flush(cfg.Server.ShutdownTimeout)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger.WatchSIGHUP(ctx)
+
+	db, err := connectDatabase(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Erro ao conectar database: %v", err)
+	}
+	defer db.Close()
+
+	admin.RegisterDump("database", func() interface{} {
+		stat := db.Pool.Stat()
+		return map[string]int32{
+			"total_conns":    stat.TotalConns(),
+			"acquired_conns": stat.AcquiredConns(),
+			"idle_conns":     stat.IdleConns(),
+		}
+	})
+
+	queries := repository.New(db.Pool)
+	readQueries := repository.New(db.Reader())
+
+	mail := mailer.New(&cfg.Mail)
+
+	// Domínios de email descartável (lista embutida + atualização remota)
+	blocklist := disposable.NewBlocklist(cfg.User.DisposableDomainsURL, cfg.User.DisposableRefreshInterval)
+	admin.RegisterDump("disposable_domains", func() interface{} { return blocklist.Stats() })
+	go blocklist.Run(ctx)
+
+	invitationService := service.NewInvitationService(queries, mail, cfg)
+	notificationService := service.NewNotificationService(queries)
+	auditService := service.NewAuditService(queries)
+
+	riskEngine, err := risk.NewEngine(cfg.Security.RiskBadIPs)
+	if err != nil {
+		log.Fatalf("Erro ao configurar risco de login: %v", err)
+	}
+	loginRiskService := service.NewLoginRiskService(queries, riskEngine, auditService, mail, cfg)
+	loginAlertService := service.NewLoginAlertService(queries, geoip.New(cfg.Security.GeoIPURL, cfg.Security.GeoIPTimeout), mail, notificationService, auditService, cfg)
+	emailVerificationService := service.NewEmailVerificationService(queries, mail, auditService, cfg)
+	passwordResetService := service.NewPasswordResetService(queries, mail, auditService, cfg)
+	authService := service.NewAuthService(queries, invitationService, blocklist, loginAlertService, loginRiskService, emailVerificationService, passwordResetService, auditService, cfg)
+	userService := service.NewUserService(queries, readQueries, invitationService, cfg)
+	contactService := service.NewContactService(queries, &cfg.Security)
+	// Hashes de contato ausentes (migração 040 ou troca do CONTACT_HASH_PEPPER)
+	go func() {
+		n, err := contactService.Backfill(ctx)
+		if err != nil {
+			log.Printf("ERRO: backfill de hashes de contato: %v", err)
+			return
+		}
+		if n > 0 {
+			log.Printf("✓ %d hashes de contato recriados", n)
+		}
+	}()
+	apiKeyService := service.NewAPIKeyService(queries)
+
+	// Barramento de eventos de mensagem (Kafka, NATS, Postgres ou memória)
+	bus, err := connectEventBus(ctx, cfg, db)
+	if err != nil {
+		log.Fatalf("Erro ao criar barramento de eventos: %v", err)
+	}
+	defer bus.Close()
+
+	// Self-check do boot: problema crítico encerra (STARTUP_SELFCHECK_STRICT)
+	checks := selfcheck.New()
+	checks.Add("database_schema", selfcheck.Schema(db))
+	if cfg.EventBus.Backend == eventbus.BackendKafka {
+		checks.Add("kafka_topics", selfcheck.Topics(&cfg.Kafka, cfg.Kafka.Topic, cfg.Kafka.BulkTopic))
+	}
+	checks.Add("jwt_secrets", selfcheck.JWTSecrets(&cfg.JWT))
+	checks.Add("clock", selfcheck.Clock(db))
+	checks.Add("config", selfcheck.ConfigWarnings(cfg))
+	bootReport := checks.Run(ctx)
+	bootReport.Log()
+	if bootReport.Critical() {
+		if cfg.Startup.SelfCheckStrict {
+			log.Fatalf("Self-check com problemas críticos (STARTUP_SELFCHECK_STRICT=false sobe mesmo assim)")
+		}
+		log.Printf("WARN: self-check com problemas críticos, subindo mesmo assim (STARTUP_SELFCHECK_STRICT=false)")
+	}
+
+	// WebSocket: hub de conexões e tickets de handshake (em memória sem
+	// cluster; com cluster ficam no Redis, ver abaixo)
+	hub := ws.NewHub(ws.DrainOptions{Delay: cfg.Server.DrainDelay, Grace: cfg.Server.WSMigrateGrace})
+	var tickets ws.TicketStore
+	var memTickets *ws.MemoryTicketStore
+	if !cfg.Cluster.Enabled() {
+		memTickets = ws.NewMemoryTicketStore()
+		go memTickets.Run(ctx)
+		tickets = memTickets
+	}
+	admin.RegisterDump("websocket", func() interface{} {
+		stats := hub.Stats()
+		if memTickets != nil {
+			stats["pending_tickets"] = memTickets.Len()
+		}
+		return stats
+	})
+
+	// Manutenção: MAINTENANCE_MODE no boot, depois pelo admin; clientes
+	// conectados recebem o evento "maintenance" a cada troca
+	maint := maintenance.New(&cfg.Maintenance)
+	maint.OnChange(func(state maintenance.State) {
+		if _, err := hub.Broadcast("maintenance", state); err != nil {
+			log.Printf("WARN: aviso de manutenção: %v", err)
+		}
+	})
+
+	// Cluster (opcional): entregas vão para a instância dona do usuário (hash
+	// consistente) ou para onde ele está conectado (registro de conexões)
+	var deliverer ws.Deliverer = hub
+	var online service.OnlineChecker = hub
+	var router, registry *cluster.Router
+	if cfg.Cluster.Enabled() {
+		if router, err = cluster.NewRouter(&cfg.Cluster, hub); err != nil {
+			log.Fatalf("Erro ao configurar roteamento do cluster: %v", err)
+		}
+		defer router.Close()
+		hub.OnDrain(router.Leave)
+		// Ticket emitido aqui é resgatado na instância dona (ws_url)
+		tickets = router.Tickets()
+		admin.RegisterDump("cluster", func() interface{} { return router.Stats() })
+		go func() {
+			if err := router.Run(ctx); err != nil {
+				log.Printf("ERRO: %v", err)
+			}
+		}()
+		deliverer = router
+		online = router
+		if cfg.Cluster.ConnectionRegistry {
+			registry = router
+		}
+	}
+
+	// Cache das mensagens recentes por conversa: cada instância consome os
+	// eventos de mensagem no próprio consumer group para invalidar o seu cache
+	// (sem tópicos de retry: seriam criados por instância e a invalidação não falha)
+	history := service.NewHistoryCache(&cfg.History)
+	if history != nil {
+		admin.RegisterDump("history_cache", func() interface{} { return history.Stats() })
+		historyConsumer, err := bus.Subscribe(cfg.Kafka.Topic, cfg.History.ConsumerGroup, history.Handle, workerPool(cfg, cfg.Kafka.Topic))
+		if err != nil {
+			log.Fatalf("Erro ao criar consumer do cache de histórico: %v", err)
+		}
+		defer historyConsumer.Close()
+
+		go func() {
+			if err := historyConsumer.Run(ctx); err != nil {
+				log.Printf("ERRO: %v", err)
+			}
+		}()
+	}
+
+	// Planos (assinatura, admin ou PLAN_DEFAULT) e as cotas de cada um
+	planService := service.NewPlanService(queries, cfg)
+	quotaService := service.NewQuotaService(queries, planService)
+
+	// Hub também entrega direto quando o barramento está degradado
+	// SLO de entrega em tempo real (consumidor e entrega direta), burn rate em /admin/slo
+	deliverySLO := slo.NewTracker("message_delivery", "mensagens entregues ao WebSocket de destinatários online",
+		cfg.SLO.DeliveryTarget, cfg.SLO.DeliveryLatency)
+
+	messageService := service.NewMessageService(queries, readQueries, bus, deliverer, service.NewPrivacyService(queries), history, quotaService, planService, cfg)
+	messageService.SetDeliverySLO(deliverySLO)
+	messageService.SetEmailVerification(emailVerificationService)
+
+	// Boas-vindas no cadastro: conversa com o usuário system
+	if cfg.User.WelcomeEnabled {
+		welcome, err := service.NewWelcomeService(messageService, cfg)
+		if err != nil {
+			log.Fatalf("Erro ao configurar boas-vindas: %v", err)
+		}
+		authService.SetWelcome(welcome)
+	}
+
+	// Caixa de suporte: chamados sobre a conversa com o usuário support
+	supportService := service.NewSupportService(queries, messageService, cfg)
+	impersonationService := service.NewImpersonationService(queries, auditService, cfg)
+
+	// Anexos: armazenamento + varredura antivírus assíncrona
+	store, err := storage.NewLocal(cfg.Storage.Dir)
+	if err != nil {
+		log.Fatalf("Erro ao configurar armazenamento: %v", err)
+	}
+	signer := signedurl.New(cfg.Storage.DownloadSigningSecret, cfg.Storage.DownloadBaseURL, cfg.Storage.DownloadURLTTL)
+	attachmentService := service.NewAttachmentService(queries, store, signer, quotaService, cfg)
+
+	// Workers de manutenção
+	partitions := worker.NewPartitionMaintainer(queries, cfg.Worker.PartitionMonthsAhead, cfg.Worker.PartitionInterval)
+	go partitions.Run(ctx)
+
+	scanner := worker.NewAttachmentScanner(queries, store,
+		antivirus.New(cfg.Security.ClamAVAddr, cfg.Security.ClamAVTimeout),
+		classifier.New(cfg.Storage.NSFWClassifierURL, cfg.Storage.NSFWClassifierTimeout),
+		notificationService, cfg)
+	go scanner.Run(ctx)
+
+	attachmentGC := worker.NewAttachmentGC(queries, store, cfg)
+	go attachmentGC.Run(ctx)
+
+	accountPurger := worker.NewAccountPurger(queries, cfg)
+	go accountPurger.Run(ctx)
+
+	// Transcodificação de vídeo: só com ffmpeg configurado
+	videoTranscoder := transcoder.New(cfg.Worker.FFmpegPath)
+	if videoTranscoder.Enabled() {
+		transcodeWorker := worker.NewTranscodeWorker(queries, store, videoTranscoder, deliverer, cfg)
+		go transcodeWorker.Run(ctx)
+	}
+
+	// Consumidor de eventos (resumos de conversa)
+	dndService := service.NewDNDService(queries)
+	notifier := worker.NewNotifier(dndService, worker.LogPushSender{})
+	processor := worker.NewMessageProcessor(queries, notifier, deliverer)
+	processor.SetDeliverySLO(deliverySLO)
+	processor.SetDeliveryReceipts(messageService)
+	consumer, err := eventbus.SubscribeWithRetry(bus, retryOptions(cfg),
+		cfg.Kafka.Topic, cfg.Kafka.ConsumerGroup, processor.Handle, workerPool(cfg, cfg.Kafka.Topic))
+	if err != nil {
+		log.Fatalf("Erro ao criar consumer: %v", err)
+	}
+	defer consumer.Close()
+
+	go func() {
+		if err := consumer.Run(ctx); err != nil {
+			log.Printf("ERRO: %v", err)
+		}
+	}()
+
+	// Respostas automáticas (férias/ausência): consumer group próprio
+	autoReplies := service.NewAutoReplyService(queries, messageService, service.NewPrivacyService(queries))
+	responder := worker.NewAutoResponder(autoReplies)
+	responderConsumer, err := eventbus.SubscribeWithRetry(bus, retryOptions(cfg),
+		cfg.Kafka.Topic, cfg.User.AutoReplyConsumerGroup, responder.Handle, workerPool(cfg, cfg.Kafka.Topic))
+	if err != nil {
+		log.Fatalf("Erro ao criar consumer de respostas automáticas: %v", err)
+	}
+	defer responderConsumer.Close()
+
+	go func() {
+		if err := responderConsumer.Run(ctx); err != nil {
+			log.Printf("ERRO: %v", err)
+		}
+	}()
+
+	// Faixa bulk: tópico e pool próprios, jobs lentos não atrasam a entrega de mensagens
+	bulkDispatcher := worker.NewBulkDispatcher()
+
+	// Anúncios do sistema: agendados pelo admin, entregues em lotes na faixa bulk
+	announcementService := service.NewAnnouncementService(queries)
+	announcements := worker.NewAnnouncementDispatcher(queries, bus, service.NewBulkPublisher(bus, cfg), planService, cfg)
+	bulkDispatcher.Register(worker.JobAnnouncementDeliver, announcements.DeliverBatch)
+	go announcements.Run(ctx)
+
+	bulkPool := workerPool(cfg, cfg.Kafka.BulkTopic)
+	bulkPool.Timeout = cfg.Worker.BulkTimeout
+	bulkConsumer, err := eventbus.SubscribeWithRetry(bus, retryOptions(cfg),
+		cfg.Kafka.BulkTopic, cfg.Kafka.BulkConsumerGroup, bulkDispatcher.Handle, bulkPool)
+	if err != nil {
+		log.Fatalf("Erro ao criar consumer bulk: %v", err)
+	}
+	defer bulkConsumer.Close()
+
+	go func() {
+		if err := bulkConsumer.Run(ctx); err != nil {
+			log.Printf("ERRO: %v", err)
+		}
+	}()
+
+	legalHolds := service.NewLegalHoldService(queries)
+
+	// Busca: índice externo opcional, alimentado por consumer group próprio
+	searchIndex := search.New(cfg.Search.ElasticsearchURL, cfg.Search.IndexPrefix,
+		cfg.Search.Username, cfg.Search.Password, cfg.Search.Timeout)
+	if searchIndex.Enabled() {
+		indexer := worker.NewSearchIndexer(searchIndex, &cfg.Search)
+		indexer.SetLegalHolds(legalHolds)
+		go indexer.Run(ctx)
+
+		indexerConsumer, err := eventbus.SubscribeWithRetry(bus, retryOptions(cfg),
+			cfg.Kafka.Topic, cfg.Search.ConsumerGroup, indexer.Handle, workerPool(cfg, cfg.Kafka.Topic))
+		if err != nil {
+			log.Fatalf("Erro ao criar consumer do indexador: %v", err)
+		}
+		defer indexerConsumer.Close()
+
+		go func() {
+			if err := indexerConsumer.Run(ctx); err != nil {
+				log.Printf("ERRO: %v", err)
+			}
+		}()
+	}
+
+	// Arquivo de compliance: opcional, consumer group próprio
+	archiveSink, err := archive.New(cfg.Archive.Sink, cfg.Archive.WebhookURL,
+		cfg.Archive.WebhookSecret, cfg.Archive.Dir, cfg.Archive.Timeout)
+	if err != nil {
+		log.Fatalf("Erro ao configurar arquivo de compliance: %v", err)
+	}
+	if archiveSink.Enabled() {
+		archiver := worker.NewComplianceArchiver(archiveSink)
+
+		archiveConsumer, err := eventbus.SubscribeWithRetry(bus, retryOptions(cfg),
+			cfg.Kafka.Topic, cfg.Archive.ConsumerGroup, archiver.Handle, workerPool(cfg, cfg.Kafka.Topic))
+		if err != nil {
+			log.Fatalf("Erro ao criar consumer do arquivo de compliance: %v", err)
+		}
+		defer archiveConsumer.Close()
+
+		go func() {
+			if err := archiveConsumer.Run(ctx); err != nil {
+				log.Printf("ERRO: %v", err)
+			}
+		}()
+		log.Printf("✓ Arquivo de compliance ativo (%s)", cfg.Archive.Sink)
+	}
+
+	// API pública
+	apiServer := server.New(cfg, server.Handlers{
+		Auth:           handler.NewAuthHandler(authService, loginAlertService),
+		Users:          handler.NewUserHandler(userService, service.NewPresenceService(service.NewPrivacyService(queries), online), quotaService, planService),
+		Contacts:       handler.NewContactHandler(contactService),
+		Invitations:    handler.NewInvitationHandler(invitationService),
+		Notifications:  handler.NewNotificationHandler(notificationService),
+		Announcements:  handler.NewAnnouncementHandler(announcementService),
+		WS:             handler.NewWSHandler(hub, tickets, router, maint, cfg.Server.WSAllowedOrigins),
+		Messages:       handler.NewMessageHandler(messageService),
+		ShareLinks:     handler.NewShareLinkHandler(service.NewShareLinkService(queries, messageService, cfg)),
+		Support:        handler.NewSupportHandler(supportService),
+		SupportAccess:  handler.NewSupportAccessHandler(impersonationService),
+		Attachments:    handler.NewAttachmentHandler(attachmentService),
+		Uploads:        handler.NewTusHandler(attachmentService, cfg.Storage.MaxAttachmentBytes),
+		Search:         handler.NewSearchHandler(service.NewSearchService(readQueries, searchIndex, cfg)),
+		Billing:        handler.NewBillingHandler(billing.New(cfg.Billing.Provider, cfg.Billing.StripeWebhookSecret, cfg.Billing.Prices()), planService),
+		AutoReplies:    handler.NewAutoReplyHandler(autoReplies),
+		Preferences:    handler.NewPreferencesHandler(service.NewPreferencesService(queries, deliverer)),
+		Privacy:        handler.NewPrivacyHandler(service.NewPrivacyService(queries)),
+		DND:            handler.NewDNDHandler(dndService),
+		Health:         handler.NewHealthHandler(db, bus, hub),
+		APIKeys:        apiKeyService,
+		Impersonations: impersonationService,
+		Maintenance:    maint,
+	})
+	// TLS nativo (opcional): HTTPS + HTTP/2 na porta da API
+	httpRedirect, err := server.ConfigureTLS(apiServer, &cfg.Server)
+	if err != nil {
+		log.Fatalf("Erro ao configurar TLS: %v", err)
+	}
+	go func() {
+		log.Printf("✓ API escutando em %s (tls=%t)", apiServer.Addr, apiServer.TLSConfig != nil)
+		if err := server.ListenAndServe(apiServer); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Erro no servidor HTTP: %v", err)
+		}
+	}()
+	if httpRedirect != nil {
+		go func() {
+			log.Printf("✓ Redirect HTTP/ACME escutando em %s", httpRedirect.Addr)
+			if err := httpRedirect.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("ERRO: servidor de redirect HTTP: %v", err)
+			}
+		}()
+	}
+
+	// Gestão de offsets pelo admin (só Kafka)
+	var offsetAdmin *kafka.OffsetAdmin
+	if cfg.Admin.Token != "" && cfg.EventBus.Backend == eventbus.BackendKafka {
+		if offsetAdmin, err = kafka.NewOffsetAdmin(&cfg.Kafka); err != nil {
+			log.Printf("ERRO: admin de offsets indisponível: %v", err)
+		} else {
+			defer offsetAdmin.Close()
+		}
+	}
+
+	// Tail de eventos para depuração: só com o servidor admin ativo
+	var eventTail *admin.Tail
+	if cfg.Admin.Token != "" {
+		eventTail = admin.NewTail(cfg.Admin.TailRedactContent)
+		admin.RegisterDump("event_tail", func() interface{} { return eventTail.Stats() })
+		tailConsumer, err := bus.Subscribe(cfg.Kafka.Topic, cfg.Admin.TailConsumerGroup, eventTail.Handle, workerPool(cfg, cfg.Kafka.Topic))
+		if err != nil {
+			log.Fatalf("Erro ao criar consumer do tail de eventos: %v", err)
+		}
+		defer tailConsumer.Close()
+
+		go func() {
+			if err := tailConsumer.Run(ctx); err != nil {
+				log.Printf("ERRO: %v", err)
+			}
+		}()
+	}
+
+	// Servidor admin (porta separada, protegido por token)
+	adminServer := admin.NewServer(&cfg.Admin, admin.Services{
+		Users:         userService,
+		Plans:         planService,
+		Announcements: announcementService,
+		Disposable:    blocklist,
+		Audit:         auditService,
+		APIKeys:       apiKeyService,
+		Offsets:       offsetAdmin,
+		Hub:           hub,
+		Cluster:       registry,
+		Maintenance:   maint,
+		SLOs:          []*slo.Tracker{deliverySLO},
+		Support:       supportService,
+		Impersonation: impersonationService,
+		LegalHolds:    legalHolds,
+		Tail:          eventTail,
+		SelfCheck:     checks,
+	})
+	if adminServer != nil {
+		if eventTail != nil {
+			adminServer.RegisterOnShutdown(eventTail.Close)
+		}
+		go func() {
+			log.Printf("✓ Admin escutando em %s", adminServer.Addr)
+			if err := adminServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("ERRO: servidor admin: %v", err)
+			}
+		}()
+	}
+
+	<-ctx.Done()
+	log.Println("Encerrando...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	// WebSocket não entra no Shutdown (conexão sequestrada): migra antes.
+	// Já drenado pelo pre-stop, retorna na hora
+	hub.Drain(shutdownCtx)
+
+	if err := apiServer.Shutdown(shutdownCtx); err != nil {
+		log.Printf("ERRO: shutdown da API: %v", err)
+	}
+	if httpRedirect != nil {
+		_ = httpRedirect.Shutdown(shutdownCtx)
+	}
+	if adminServer != nil {
+		_ = adminServer.Shutdown(shutdownCtx)
+	}
+}
+
+// connectDatabase conecta ao Postgres conforme STARTUP_DB_MODE
+func connectDatabase(ctx context.Context, cfg *config.Config) (*database.DB, error) {
+	var db *database.DB
+	connect := func() (err error) {
+		db, err = database.New(ctx, &cfg.Database)
+		return err
+	}
+
+	switch cfg.Startup.DatabaseMode {
+	case config.StartupWait:
+		err := waitFor(ctx, &cfg.Startup, "database", connect)
+		return db, err
+	case config.StartupDegraded:
+		if err := connect(); err != nil {
+			log.Printf("WARN: %v", err)
+			return database.Open(ctx, &cfg.Database)
+		}
+		return db, nil
+	default:
+		return db, connect()
+	}
+}
+
+// connectEventBus cria o barramento conforme STARTUP_EVENT_BUS_MODE
+func connectEventBus(ctx context.Context, cfg *config.Config, db *database.DB) (eventbus.Bus, error) {
+	switch cfg.Startup.EventBusMode {
+	case config.StartupWait:
+		var bus eventbus.Bus
+		err := waitFor(ctx, &cfg.Startup, "barramento de eventos", func() (err error) {
+			bus, err = newEventBus(cfg, db)
+			return err
+		})
+		return bus, err
+	case config.StartupDegraded: // Só Kafka (Validate)
+		return kafka.NewDeferredBus(&cfg.Kafka, cfg.EventBus.Delivery, cfg.Startup.RetryInterval)
+	default:
+		return newEventBus(cfg, db)
+	}
+}
+
+// waitFor repete connect até dar certo, o contexto cancelar ou estourar
+// STARTUP_WAIT_TIMEOUT
+func waitFor(ctx context.Context, cfg *config.StartupConfig, name string, connect func() error) error {
+	var deadline <-chan time.Time
+	if cfg.WaitTimeout > 0 {
+		timer := time.NewTimer(cfg.WaitTimeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	for {
+		err := connect()
+		if err == nil {
+			return nil
+		}
+		log.Printf("WARN: %s indisponível, nova tentativa em %s: %v", name, cfg.RetryInterval, err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline:
+			return fmt.Errorf("%s indisponível após %s: %w", name, cfg.WaitTimeout, err)
+		case <-time.After(cfg.RetryInterval):
+		}
+	}
+}
+
+// newEventBus escolhe o barramento de eventos conforme EVENT_BUS
+func newEventBus(cfg *config.Config, db *database.DB) (eventbus.Bus, error) {
+	switch cfg.EventBus.Backend {
+	case eventbus.BackendNATS:
+		return eventbus.NewNATSBus(&cfg.NATS, cfg.EventBus.Delivery)
+	case eventbus.BackendPostgres:
+		return eventbus.NewPostgresBus(db.Pool, &cfg.EventBus), nil
+	case eventbus.BackendMemory:
+		return eventbus.NewMemoryBus(cfg.EventBus.BufferSize), nil
+	default:
+		return kafka.NewBus(&cfg.Kafka, cfg.EventBus.Delivery)
+	}
+}
+
+// workerPool paralelismo dos consumidores do tópico (WorkerConfig)
+func workerPool(cfg *config.Config, topic string) eventbus.PoolOptions {
+	return eventbus.PoolOptions{
+		Workers: cfg.Worker.PoolSizeFor(topic),
+		Buffer:  cfg.Worker.BufferSize,
+		Timeout: cfg.Worker.ProcessTimeout,
+	}
+}
+
+// retryOptions estágios de retry e quarentena dos consumidores
+func retryOptions(cfg *config.Config) eventbus.RetryOptions {
+	return eventbus.RetryOptions{
+		Delays:          cfg.EventBus.RetryDelays,
+		PoisonThreshold: cfg.EventBus.PoisonThreshold,
+	}
+}
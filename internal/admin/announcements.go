@@ -0,0 +1,74 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// handleCreateAnnouncement agenda anúncio para todos ou um plano (sem
+// scheduled_at: enviado na próxima verificação)
+func (h *handlers) handleCreateAnnouncement(w http.ResponseWriter, r *http.Request) {
+	var input types.CreateAnnouncementInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "JSON inválido", "INVALID_JSON")
+		return
+	}
+
+	announcement, err := h.svc.Announcements.Create(r.Context(), input)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "ANNOUNCEMENT_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusCreated, announcement, "anúncio agendado")
+}
+
+// handleListAnnouncements lista anúncios com o andamento (?page=1&per_page=20)
+func (h *handlers) handleListAnnouncements(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+
+	announcements, err := h.svc.Announcements.List(r.Context(), page, perPage)
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, err.Error(), "ANNOUNCEMENTS_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, announcements, "")
+}
+
+// handleGetAnnouncement anúncio com entregas e confirmações
+func (h *handlers) handleGetAnnouncement(w http.ResponseWriter, r *http.Request) {
+	announcement, err := h.svc.Announcements.Get(r.Context(), r.PathValue("id"))
+	if errors.Is(err, service.ErrAnnouncementNotFound) {
+		utils.Error(w, http.StatusNotFound, err.Error(), "ANNOUNCEMENT_NOT_FOUND")
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "ANNOUNCEMENT_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, announcement, "")
+}
+
+// handleCancelAnnouncement cancela anúncio agendado ou interrompe o envio
+func (h *handlers) handleCancelAnnouncement(w http.ResponseWriter, r *http.Request) {
+	err := h.svc.Announcements.Cancel(r.Context(), r.PathValue("id"))
+	if errors.Is(err, service.ErrAnnouncementNotFound) {
+		utils.Error(w, http.StatusNotFound, err.Error(), "ANNOUNCEMENT_NOT_FOUND")
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "ANNOUNCEMENT_CANCEL_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, nil, "anúncio cancelado")
+}
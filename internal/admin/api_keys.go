@@ -0,0 +1,47 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// handleListAPIKeys lista as chaves de API (sem o segredo)
+func (h *handlers) handleListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.svc.APIKeys.List(r.Context())
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, err.Error(), "API_KEYS_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, keys, "")
+}
+
+// handleCreateAPIKey cria chave; o segredo só é exibido nesta resposta
+func (h *handlers) handleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var input types.CreateAPIKeyInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "JSON inválido", "INVALID_JSON")
+		return
+	}
+
+	key, err := h.svc.APIKeys.Create(r.Context(), input)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "API_KEY_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusCreated, key, "guarde a chave: ela não será exibida novamente")
+}
+
+// handleRevokeAPIKey revoga chave imediatamente
+func (h *handlers) handleRevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.APIKeys.Revoke(r.Context(), r.PathValue("id")); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "REVOKE_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, nil, "chave revogada")
+}
@@ -0,0 +1,22 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+
+	"chat-kafka-go/pkg/utils"
+)
+
+// handleListAudit GET /admin/audit?user_id=...|action=...&limit=100
+func (h *handlers) handleListAudit(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit, _ := strconv.Atoi(q.Get("limit"))
+
+	events, err := h.svc.Audit.List(r.Context(), q.Get("user_id"), q.Get("action"), limit)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "AUDIT_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, events, "")
+}
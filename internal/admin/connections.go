@@ -0,0 +1,49 @@
+package admin
+
+import (
+	"net/http"
+
+	"chat-kafka-go/pkg/utils"
+)
+
+// handleListConnections usuários e conexões de cada instância viva
+func (h *handlers) handleListConnections(w http.ResponseWriter, r *http.Request) {
+	if h.svc.Cluster == nil {
+		utils.Error(w, http.StatusNotImplemented, "registro de conexões exige CLUSTER_CONNECTION_REGISTRY=true", "REGISTRY_UNAVAILABLE")
+		return
+	}
+
+	instances, err := h.svc.Cluster.Connections(r.Context())
+	if err != nil {
+		utils.Error(w, http.StatusBadGateway, err.Error(), "CONNECTIONS_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, instances, "")
+}
+
+// handleUserConnections conexões do usuário por instância
+func (h *handlers) handleUserConnections(w http.ResponseWriter, r *http.Request) {
+	if h.svc.Cluster == nil {
+		utils.Error(w, http.StatusNotImplemented, "registro de conexões exige CLUSTER_CONNECTION_REGISTRY=true", "REGISTRY_UNAVAILABLE")
+		return
+	}
+
+	userID := r.PathValue("userID")
+	counts, err := h.svc.Cluster.UserConnections(r.Context(), userID)
+	if err != nil {
+		utils.Error(w, http.StatusBadGateway, err.Error(), "CONNECTIONS_FAILED")
+		return
+	}
+
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	utils.Success(w, http.StatusOK, map[string]interface{}{
+		"user_id":     userID,
+		"online":      total > 0,
+		"connections": total,
+		"instances":   counts,
+	}, "")
+}
@@ -0,0 +1,114 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"chat-kafka-go/internal/kafka"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/IBM/sarama"
+)
+
+// resetOffsetsInput destino do reset: offset, posição ou instante (um deles)
+type resetOffsetsInput struct {
+	Topic     string     `json:"topic"`
+	Offset    *int64     `json:"offset,omitempty"`
+	Position  string     `json:"position,omitempty"` // earliest ou latest
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+}
+
+// handleGroupOffsets offsets confirmados, high water mark e lag por partição
+func (h *handlers) handleGroupOffsets(w http.ResponseWriter, r *http.Request) {
+	if h.svc.Offsets == nil {
+		utils.Error(w, http.StatusNotImplemented, "gestão de offsets exige EVENT_BUS=kafka", "OFFSETS_UNAVAILABLE")
+		return
+	}
+	topic := r.URL.Query().Get("topic")
+	if topic == "" {
+		utils.Error(w, http.StatusBadRequest, "topic é obrigatório", "INVALID_TOPIC")
+		return
+	}
+
+	status, err := h.svc.Offsets.Status(r.PathValue("group"), topic)
+	if err != nil {
+		utils.Error(w, http.StatusBadGateway, err.Error(), "OFFSETS_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, status, "")
+}
+
+// handleResetOffsets move o grupo para um offset, início/fim ou instante
+// O grupo precisa estar vazio (consumo pausado em todas as instâncias)
+func (h *handlers) handleResetOffsets(w http.ResponseWriter, r *http.Request) {
+	if h.svc.Offsets == nil {
+		utils.Error(w, http.StatusNotImplemented, "gestão de offsets exige EVENT_BUS=kafka", "OFFSETS_UNAVAILABLE")
+		return
+	}
+
+	var input resetOffsetsInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "JSON inválido", "INVALID_JSON")
+		return
+	}
+	if input.Topic == "" {
+		utils.Error(w, http.StatusBadRequest, "topic é obrigatório", "INVALID_TOPIC")
+		return
+	}
+
+	group := r.PathValue("group")
+	var (
+		status *kafka.GroupStatus
+		err    error
+	)
+	switch {
+	case input.Offset != nil:
+		if *input.Offset < 0 {
+			utils.Error(w, http.StatusBadRequest, "offset deve ser >= 0", "INVALID_OFFSET")
+			return
+		}
+		status, err = h.svc.Offsets.ResetToOffset(group, input.Topic, *input.Offset)
+	case input.Position == "earliest":
+		status, err = h.svc.Offsets.ResetToOffset(group, input.Topic, sarama.OffsetOldest)
+	case input.Position == "latest":
+		status, err = h.svc.Offsets.ResetToOffset(group, input.Topic, sarama.OffsetNewest)
+	case input.Timestamp != nil:
+		status, err = h.svc.Offsets.ResetToTime(group, input.Topic, *input.Timestamp)
+	default:
+		utils.Error(w, http.StatusBadRequest, "informe offset, position (earliest/latest) ou timestamp", "INVALID_TARGET")
+		return
+	}
+	if errors.Is(err, kafka.ErrGroupActive) {
+		utils.Error(w, http.StatusConflict, err.Error(), "GROUP_ACTIVE")
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadGateway, err.Error(), "RESET_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, status, "offsets redefinidos")
+}
+
+// handlePauseGroup pausa o consumo do grupo nesta instância
+func (h *handlers) handlePauseGroup(w http.ResponseWriter, r *http.Request) {
+	if kafka.PauseGroup(r.PathValue("group")) == 0 {
+		utils.Error(w, http.StatusNotFound, "grupo sem consumers nesta instância", "GROUP_NOT_FOUND")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, nil, "consumo pausado")
+}
+
+// handleResumeGroup retoma o consumo do grupo nesta instância
+func (h *handlers) handleResumeGroup(w http.ResponseWriter, r *http.Request) {
+	if kafka.ResumeGroup(r.PathValue("group")) == 0 {
+		utils.Error(w, http.StatusNotFound, "grupo sem consumers nesta instância", "GROUP_NOT_FOUND")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, nil, "consumo retomado")
+}
@@ -0,0 +1,45 @@
+package admin
+
+import (
+	"net/http"
+
+	"chat-kafka-go/pkg/utils"
+)
+
+// handleListDisposable lista os domínios de email descartável bloqueados
+func (h *handlers) handleListDisposable(w http.ResponseWriter, r *http.Request) {
+	utils.Success(w, http.StatusOK, map[string]interface{}{
+		"domains": h.svc.Disposable.Domains(),
+		"stats":   h.svc.Disposable.Stats(),
+	}, "")
+}
+
+// handleAddDisposable bloqueia domínio em runtime
+func (h *handlers) handleAddDisposable(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.Disposable.Add(r.PathValue("domain")); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_DOMAIN")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, nil, "domínio bloqueado")
+}
+
+// handleRemoveDisposable libera domínio em runtime
+func (h *handlers) handleRemoveDisposable(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.Disposable.Remove(r.PathValue("domain")); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_DOMAIN")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, nil, "domínio liberado")
+}
+
+// handleRefreshDisposable força atualização da lista remota
+func (h *handlers) handleRefreshDisposable(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.Disposable.Refresh(r.Context()); err != nil {
+		utils.Error(w, http.StatusBadGateway, err.Error(), "REFRESH_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, h.svc.Disposable.Stats(), "lista atualizada")
+}
@@ -0,0 +1,60 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// handleStartImpersonation emite token de suporte somente leitura (exige
+// autorização do usuário em PUT /users/me/support-access)
+func (h *handlers) handleStartImpersonation(w http.ResponseWriter, r *http.Request) {
+	var input types.StartImpersonationInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "JSON inválido", "INVALID_JSON")
+		return
+	}
+
+	resp, err := h.svc.Impersonation.Start(r.Context(), r.PathValue("id"), input, utils.ClientIP(r))
+	if errors.Is(err, service.ErrSupportAccessRequired) {
+		utils.Error(w, http.StatusForbidden, err.Error(), "SUPPORT_ACCESS_REQUIRED")
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "IMPERSONATION_FAILED")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	utils.Success(w, http.StatusCreated, resp, "sessão de suporte iniciada")
+}
+
+// handleListImpersonations últimas sessões de suporte do usuário
+func (h *handlers) handleListImpersonations(w http.ResponseWriter, r *http.Request) {
+	sessions, err := h.svc.Impersonation.ListForUser(r.Context(), r.PathValue("id"))
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "IMPERSONATIONS_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, sessions, "")
+}
+
+// handleRevokeImpersonation encerra a sessão; o token deixa de valer na hora
+func (h *handlers) handleRevokeImpersonation(w http.ResponseWriter, r *http.Request) {
+	session, err := h.svc.Impersonation.Revoke(r.Context(), r.PathValue("id"), utils.ClientIP(r))
+	if errors.Is(err, service.ErrImpersonationNotFound) {
+		utils.Error(w, http.StatusNotFound, err.Error(), "IMPERSONATION_NOT_FOUND")
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "IMPERSONATION_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, session, "sessão de suporte encerrada")
+}
@@ -0,0 +1,73 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// handleListLegalHolds retenções legais ativas (?all=true inclui liberadas)
+func (h *handlers) handleListLegalHolds(w http.ResponseWriter, r *http.Request) {
+	holds, err := h.svc.LegalHolds.List(r.Context(), r.URL.Query().Get("all") != "true")
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, err.Error(), "LEGAL_HOLDS_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, holds, "")
+}
+
+// handlePlaceLegalHold coloca usuário ou conversa sob retenção legal
+func (h *handlers) handlePlaceLegalHold(w http.ResponseWriter, r *http.Request) {
+	var input types.PlaceLegalHoldInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "JSON inválido", "INVALID_JSON")
+		return
+	}
+
+	hold, err := h.svc.LegalHolds.Place(r.Context(), input)
+	if errors.Is(err, service.ErrLegalHoldExists) {
+		utils.Error(w, http.StatusConflict, err.Error(), "LEGAL_HOLD_EXISTS")
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "LEGAL_HOLD_FAILED")
+		return
+	}
+
+	h.auditLegalHold(r, hold, service.AuditLegalHold)
+	utils.Success(w, http.StatusCreated, hold, "retenção legal aplicada")
+}
+
+// handleReleaseLegalHold libera a retenção (o registro é mantido)
+func (h *handlers) handleReleaseLegalHold(w http.ResponseWriter, r *http.Request) {
+	hold, err := h.svc.LegalHolds.Release(r.Context(), r.PathValue("id"))
+	if errors.Is(err, service.ErrLegalHoldNotFound) {
+		utils.Error(w, http.StatusNotFound, err.Error(), "LEGAL_HOLD_NOT_FOUND")
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "LEGAL_HOLD_FAILED")
+		return
+	}
+
+	h.auditLegalHold(r, hold, service.AuditLegalHoldLift)
+	utils.Success(w, http.StatusOK, hold, "retenção legal liberada")
+}
+
+// auditLegalHold registra a operação com o alvo e o motivo
+func (h *handlers) auditLegalHold(r *http.Request, hold *types.LegalHoldResponse, action string) {
+	uuid, err := utils.StringToUUID(hold.UserID)
+	if err != nil {
+		return
+	}
+	h.svc.Audit.Record(r.Context(), uuid, action, utils.ClientIP(r), 0, map[string]string{
+		"hold_id": hold.ID,
+		"peer_id": hold.PeerID,
+		"reason":  hold.Reason,
+	})
+}
@@ -0,0 +1,34 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"chat-kafka-go/internal/logger"
+	"chat-kafka-go/pkg/utils"
+)
+
+type logLevelInput struct {
+	Level string `json:"level"`
+}
+
+// handleGetLogLevel retorna o nível de log atual
+func handleGetLogLevel(w http.ResponseWriter, r *http.Request) {
+	utils.Success(w, http.StatusOK, map[string]string{"level": logger.Level().String()}, "")
+}
+
+// handleSetLogLevel altera o nível de log em runtime
+func handleSetLogLevel(w http.ResponseWriter, r *http.Request) {
+	var input logLevelInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "JSON inválido", "INVALID_JSON")
+		return
+	}
+
+	if err := logger.SetLevel(input.Level); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_LOG_LEVEL")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, map[string]string{"level": logger.Level().String()}, "nível de log atualizado")
+}
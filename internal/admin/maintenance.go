@@ -0,0 +1,41 @@
+package admin
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"chat-kafka-go/pkg/utils"
+)
+
+// maintenanceInput novo modo; retry_after em segundos (0 mantém o atual)
+type maintenanceInput struct {
+	Mode       string `json:"mode"` // off, read_only ou full
+	RetryAfter int    `json:"retry_after,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+// handleGetMaintenance modo de manutenção desta instância
+func (h *handlers) handleGetMaintenance(w http.ResponseWriter, r *http.Request) {
+	utils.Success(w, http.StatusOK, h.svc.Maintenance.State(), "")
+}
+
+// handleSetMaintenance troca o modo desta instância e avisa os clientes
+// WebSocket conectados a ela (repetir em cada instância)
+func (h *handlers) handleSetMaintenance(w http.ResponseWriter, r *http.Request) {
+	var input maintenanceInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "JSON inválido", "INVALID_JSON")
+		return
+	}
+
+	state, err := h.svc.Maintenance.Set(input.Mode, time.Duration(input.RetryAfter)*time.Second, input.Message)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_MAINTENANCE_MODE")
+		return
+	}
+	log.Printf("✓ Modo de manutenção: %s", state.Mode)
+
+	utils.Success(w, http.StatusOK, state, "modo de manutenção atualizado")
+}
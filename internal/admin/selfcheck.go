@@ -0,0 +1,13 @@
+package admin
+
+import (
+	"net/http"
+
+	"chat-kafka-go/pkg/utils"
+)
+
+// handleSelfCheck executa de novo as verificações do boot nesta instância
+// (status "critical" aqui não derruba o processo, só informa)
+func (h *handlers) handleSelfCheck(w http.ResponseWriter, r *http.Request) {
+	utils.Success(w, http.StatusOK, h.svc.SelfCheck.Run(r.Context()), "")
+}
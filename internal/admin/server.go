@@ -0,0 +1,215 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"chat-kafka-go/internal/cluster"
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/disposable"
+	"chat-kafka-go/internal/kafka"
+	"chat-kafka-go/internal/maintenance"
+	"chat-kafka-go/internal/middleware"
+	"chat-kafka-go/internal/selfcheck"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/internal/slo"
+	"chat-kafka-go/internal/ws"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// DumpFunc retorna snapshot do estado de um componente (hub, consumer, pool...)
+type DumpFunc func() interface{}
+
+var (
+	dumpsMu sync.RWMutex
+	dumps   = map[string]DumpFunc{}
+)
+
+// RegisterDump registra componente exibido em /debug/dump
+func RegisterDump(name string, fn DumpFunc) {
+	dumpsMu.Lock()
+	defer dumpsMu.Unlock()
+	dumps[name] = fn
+}
+
+// Services dependências usadas pelas rotas administrativas
+type Services struct {
+	Users         *service.UserService
+	Plans         *service.PlanService
+	Announcements *service.AnnouncementService
+	Disposable    *disposable.Blocklist
+	Audit         *service.AuditService
+	APIKeys       *service.APIKeyService
+	Offsets       *kafka.OffsetAdmin // nil fora do Kafka
+	Hub           *ws.Hub            // Drenagem antes de encerrar
+	Cluster       *cluster.Router    // nil sem registro de conexões
+	Maintenance   *maintenance.Switch
+	SLOs          []*slo.Tracker // Objetivos avaliados em /admin/slo
+	Support       *service.SupportService
+	Impersonation *service.ImpersonationService
+	LegalHolds    *service.LegalHoldService
+	Tail          *Tail // nil = tail de eventos desabilitado
+	SelfCheck     *selfcheck.Runner
+}
+
+type handlers struct {
+	svc Services
+}
+
+// NewServer cria servidor HTTP administrativo em porta separada
+// Retorna nil se ADMIN_TOKEN não estiver configurado (admin desabilitado)
+func NewServer(cfg *config.AdminConfig, svc Services) *http.Server {
+	if cfg.Token == "" {
+		return nil
+	}
+
+	return &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           middleware.Recovery(requireToken(cfg.Token, NewMux(svc))),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
+
+// NewMux registra pprof, expvar, métricas, nível de log, dump de runtime
+// e operações administrativas
+func NewMux(svc Services) *http.ServeMux {
+	h := &handlers{svc: svc}
+	mux := http.NewServeMux()
+
+	// pprof (registrado explicitamente para não depender do DefaultServeMux)
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	mux.Handle("GET /debug/vars", expvar.Handler())
+	mux.HandleFunc("GET /debug/dump", handleDump)
+	mux.Handle("GET /metrics", promhttp.Handler())
+	mux.HandleFunc("GET /admin/slo", h.handleSLO)
+	mux.HandleFunc("GET /admin/selfcheck", h.handleSelfCheck)
+
+	mux.HandleFunc("GET /debug/loglevel", handleGetLogLevel)
+	mux.HandleFunc("PUT /debug/loglevel", handleSetLogLevel)
+
+	// Usuários
+	mux.HandleFunc("POST /admin/users/{id}/restore", h.handleRestoreUser)
+	mux.HandleFunc("PUT /admin/users/{id}/shadow-ban", h.handleShadowBan)
+	mux.HandleFunc("DELETE /admin/users/{id}/shadow-ban", h.handleLiftShadowBan)
+	mux.HandleFunc("PUT /admin/users/{id}/plan", h.handleSetPlan)
+
+	// Anúncios do sistema (todos os usuários ou um plano)
+	mux.HandleFunc("POST /admin/announcements", h.handleCreateAnnouncement)
+	mux.HandleFunc("GET /admin/announcements", h.handleListAnnouncements)
+	mux.HandleFunc("GET /admin/announcements/{id}", h.handleGetAnnouncement)
+	mux.HandleFunc("DELETE /admin/announcements/{id}", h.handleCancelAnnouncement)
+
+	// Agentes da caixa de suporte
+	mux.HandleFunc("GET /admin/support/agents", h.handleListSupportAgents)
+	mux.HandleFunc("PUT /admin/support/agents/{userID}", h.handleAddSupportAgent)
+	mux.HandleFunc("DELETE /admin/support/agents/{userID}", h.handleRemoveSupportAgent)
+
+	// Impersonação do suporte (somente leitura, com autorização do usuário)
+	mux.HandleFunc("POST /admin/users/{id}/impersonate", h.handleStartImpersonation)
+	mux.HandleFunc("GET /admin/users/{id}/impersonations", h.handleListImpersonations)
+	mux.HandleFunc("DELETE /admin/impersonations/{id}", h.handleRevokeImpersonation)
+
+	// Retenção legal (suspende remoção e retenção dos dados)
+	mux.HandleFunc("GET /admin/legal-holds", h.handleListLegalHolds)
+	mux.HandleFunc("POST /admin/legal-holds", h.handlePlaceLegalHold)
+	mux.HandleFunc("DELETE /admin/legal-holds/{id}", h.handleReleaseLegalHold)
+
+	// Chaves de API (integrações e bots)
+	mux.HandleFunc("GET /admin/api-keys", h.handleListAPIKeys)
+	mux.HandleFunc("POST /admin/api-keys", h.handleCreateAPIKey)
+	mux.HandleFunc("DELETE /admin/api-keys/{id}", h.handleRevokeAPIKey)
+
+	// Log de auditoria (logins, risco, step-up)
+	mux.HandleFunc("GET /admin/audit", h.handleListAudit)
+
+	// Domínios de email descartável
+	mux.HandleFunc("GET /admin/disposable-domains", h.handleListDisposable)
+	mux.HandleFunc("PUT /admin/disposable-domains/{domain}", h.handleAddDisposable)
+	mux.HandleFunc("DELETE /admin/disposable-domains/{domain}", h.handleRemoveDisposable)
+	mux.HandleFunc("POST /admin/disposable-domains/refresh", h.handleRefreshDisposable)
+
+	// Consumer groups (offsets e pausa; pausa vale só para esta instância)
+	mux.HandleFunc("GET /admin/consumers/{group}/offsets", h.handleGroupOffsets)
+	mux.HandleFunc("POST /admin/consumers/{group}/offsets/reset", h.handleResetOffsets)
+	mux.HandleFunc("POST /admin/consumers/{group}/pause", h.handlePauseGroup)
+	mux.HandleFunc("POST /admin/consumers/{group}/resume", h.handleResumeGroup)
+
+	// Drenagem (hook pre-stop): responde quando as conexões WebSocket saírem
+	mux.HandleFunc("POST /admin/drain", h.handleDrain)
+
+	// Modo de manutenção (vale só para esta instância)
+	mux.HandleFunc("GET /admin/maintenance", h.handleGetMaintenance)
+	mux.HandleFunc("PUT /admin/maintenance", h.handleSetMaintenance)
+
+	// Registro de conexões do cluster (usuários por instância)
+	mux.HandleFunc("GET /admin/connections", h.handleListConnections)
+	mux.HandleFunc("GET /admin/connections/{userID}", h.handleUserConnections)
+
+	// Tail ao vivo dos eventos de domínio (SSE; depuração de entrega)
+	mux.HandleFunc("GET /admin/events/tail", h.handleTail)
+
+	return mux
+}
+
+// handleDump retorna goroutines, memória e estado dos componentes registrados
+func handleDump(w http.ResponseWriter, r *http.Request) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	dumpsMu.RLock()
+	names := make([]string, 0, len(dumps))
+	for name := range dumps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	components := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		components[name] = dumps[name]()
+	}
+	dumpsMu.RUnlock()
+
+	utils.Success(w, http.StatusOK, map[string]interface{}{
+		"goroutines": runtime.NumGoroutine(),
+		"memory": map[string]uint64{
+			"alloc":        mem.Alloc,
+			"heap_inuse":   mem.HeapInuse,
+			"heap_objects": mem.HeapObjects,
+			"sys":          mem.Sys,
+			"num_gc":       uint64(mem.NumGC),
+		},
+		"components": components,
+	}, "")
+}
+
+// requireToken exige "Authorization: Bearer <ADMIN_TOKEN>"
+func requireToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		provided := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			utils.Error(w, http.StatusUnauthorized, "não autorizado", "UNAUTHORIZED")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleDrain tira a instância do balanceamento e migra as conexões WebSocket
+// O processo continua de pé até o SIGTERM (que espera o HTTP em andamento)
+func (h *handlers) handleDrain(w http.ResponseWriter, r *http.Request) {
+	stats := h.svc.Hub.Drain(r.Context())
+	utils.Success(w, http.StatusOK, stats, "instância drenada")
+}
@@ -0,0 +1,19 @@
+package admin
+
+import (
+	"net/http"
+
+	"chat-kafka-go/internal/slo"
+	"chat-kafka-go/pkg/utils"
+)
+
+// handleSLO burn rate atual de cada objetivo nesta instância
+// (alerta "page" ou "ticket" com firing=true pede atenção)
+func (h *handlers) handleSLO(w http.ResponseWriter, r *http.Request) {
+	reports := make([]slo.Report, 0, len(h.svc.SLOs))
+	for _, tracker := range h.svc.SLOs {
+		reports = append(reports, tracker.Report())
+	}
+
+	utils.Success(w, http.StatusOK, reports, "")
+}
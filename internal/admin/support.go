@@ -0,0 +1,47 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/utils"
+)
+
+// handleListSupportAgents usuários com papel de agente de suporte
+func (h *handlers) handleListSupportAgents(w http.ResponseWriter, r *http.Request) {
+	agents, err := h.svc.Support.ListAgents(r.Context())
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, err.Error(), "SUPPORT_AGENTS_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, agents, "")
+}
+
+// handleAddSupportAgent concede o papel de agente (idempotente)
+func (h *handlers) handleAddSupportAgent(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.Support.AddAgent(r.Context(), r.PathValue("userID")); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "SUPPORT_AGENT_FAILED")
+		return
+	}
+
+	h.auditModeration(r, r.PathValue("userID"), service.AuditSupportAgent)
+	utils.Success(w, http.StatusOK, nil, "agente adicionado")
+}
+
+// handleRemoveSupportAgent revoga o papel de agente
+func (h *handlers) handleRemoveSupportAgent(w http.ResponseWriter, r *http.Request) {
+	err := h.svc.Support.RemoveAgent(r.Context(), r.PathValue("userID"))
+	if errors.Is(err, service.ErrNotSupportAgent) {
+		utils.Error(w, http.StatusNotFound, "usuário não é agente", "SUPPORT_AGENT_NOT_FOUND")
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "SUPPORT_AGENT_FAILED")
+		return
+	}
+
+	h.auditModeration(r, r.PathValue("userID"), service.AuditSupportRevoke)
+	utils.Success(w, http.StatusOK, nil, "agente removido")
+}
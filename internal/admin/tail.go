@@ -0,0 +1,282 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"chat-kafka-go/internal/eventbus"
+	"chat-kafka-go/pkg/events"
+	"chat-kafka-go/pkg/utils"
+)
+
+// Limites do tail ao vivo
+const (
+	tailBuffer    = 256              // Eventos pendentes por assinante antes de descartar
+	tailHeartbeat = 15 * time.Second // Comentário SSE que mantém proxies com a conexão aberta
+)
+
+// participantFields campos dos eventos que identificam usuários envolvidos
+var participantFields = []string{"sender_id", "receiver_id", "user_id", "requester_id", "addressee_id"}
+
+// TailFilter recorte do tail; campos vazios não filtram
+type TailFilter struct {
+	UserID       string              // Usuário em qualquer papel (remetente, destinatário...)
+	Conversation string              // Chave da conversa (utils.ConversationKey)
+	Types        map[string]struct{} // Tipos de evento (message.sent, message.status...)
+}
+
+// TailEntry evento entregue ao assinante do tail
+type TailEntry struct {
+	Type       string          `json:"type"`
+	Version    int             `json:"version,omitempty"`
+	Topic      string          `json:"topic"`
+	Partition  int32           `json:"partition"`
+	Offset     int64           `json:"offset"`
+	Key        string          `json:"key,omitempty"`
+	ReceivedAt string          `json:"received_at"` // RFC 3339 (UTC)
+	Data       json.RawMessage `json:"data"`
+
+	participants []string
+}
+
+// tailSubscriber conexão SSE aberta
+type tailSubscriber struct {
+	filter  TailFilter
+	entries chan TailEntry
+	dropped int // Eventos descartados desde o último aviso (assinante lento)
+}
+
+// Tail transmissão ao vivo dos eventos de domínio do tópico de mensagens
+// para depuração (GET /admin/events/tail). Consumer group próprio por
+// instância; sem assinantes os eventos são só descartados
+type Tail struct {
+	redact bool
+
+	mu     sync.Mutex
+	subs   map[*tailSubscriber]struct{}
+	closed chan struct{}
+	once   sync.Once
+}
+
+// NewTail cria o tail; redact omite o conteúdo das mensagens
+func NewTail(redact bool) *Tail {
+	return &Tail{
+		redact: redact,
+		subs:   map[*tailSubscriber]struct{}{},
+		closed: make(chan struct{}),
+	}
+}
+
+// Close encerra as transmissões abertas (o Shutdown do servidor admin não
+// cancela requisições em andamento e esperaria cada SSE até o prazo)
+func (t *Tail) Close() {
+	t.once.Do(func() { close(t.closed) })
+}
+
+// Handle implementa eventbus.Handler; nunca falha (o tail não reprocessa)
+func (t *Tail) Handle(_ context.Context, msg *eventbus.Message) error {
+	t.mu.Lock()
+	idle := len(t.subs) == 0
+	t.mu.Unlock()
+	if idle {
+		return nil
+	}
+
+	entry := t.entry(msg)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for sub := range t.subs {
+		if !sub.filter.match(entry) {
+			continue
+		}
+		select {
+		case sub.entries <- entry:
+		default:
+			sub.dropped++
+		}
+	}
+	return nil
+}
+
+// subscribe registra assinante; cancel deve ser chamado ao desconectar
+func (t *Tail) subscribe(filter TailFilter) (*tailSubscriber, func()) {
+	sub := &tailSubscriber{filter: filter, entries: make(chan TailEntry, tailBuffer)}
+	t.mu.Lock()
+	t.subs[sub] = struct{}{}
+	t.mu.Unlock()
+
+	return sub, func() {
+		t.mu.Lock()
+		delete(t.subs, sub)
+		t.mu.Unlock()
+	}
+}
+
+// takeDropped zera e retorna os descartes do assinante
+func (t *Tail) takeDropped(sub *tailSubscriber) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n := sub.dropped
+	sub.dropped = 0
+	return n
+}
+
+// Stats resumo para /debug/dump
+func (t *Tail) Stats() map[string]interface{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return map[string]interface{}{
+		"subscribers":    len(t.subs),
+		"redact_content": t.redact,
+	}
+}
+
+// entry monta a entrada a partir do envelope (payload legado vai inteiro)
+func (t *Tail) entry(msg *eventbus.Message) TailEntry {
+	entry := TailEntry{
+		Type:       events.TypeOf(msg.Value),
+		Topic:      msg.Topic,
+		Partition:  msg.Partition,
+		Offset:     msg.Offset,
+		Key:        msg.Key,
+		ReceivedAt: time.Now().UTC().Format(time.RFC3339),
+		Data:       msg.Value,
+	}
+	var envelope events.Envelope
+	if err := json.Unmarshal(msg.Value, &envelope); err == nil && envelope.Type != "" {
+		entry.Version = envelope.Version
+		entry.Data = envelope.Data
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(entry.Data, &fields); err != nil {
+		return entry
+	}
+	for _, name := range participantFields {
+		var id string
+		if json.Unmarshal(fields[name], &id) == nil && id != "" {
+			entry.participants = append(entry.participants, id)
+		}
+	}
+	if content, ok := fields["content"]; ok && t.redact {
+		var text string
+		_ = json.Unmarshal(content, &text)
+		fields["content"], _ = json.Marshal(fmt.Sprintf("[omitido: %d bytes]", len(text)))
+		if data, err := json.Marshal(fields); err == nil {
+			entry.Data = data
+		}
+	}
+	return entry
+}
+
+// match indica se a entrada passa no filtro
+func (f TailFilter) match(entry TailEntry) bool {
+	if len(f.Types) > 0 {
+		if _, ok := f.Types[entry.Type]; !ok {
+			return false
+		}
+	}
+	if f.Conversation != "" && entry.Key != f.Conversation {
+		return false
+	}
+	if f.UserID != "" {
+		for _, id := range entry.participants {
+			if id == f.UserID {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// parseTailFilter lê ?user=, ?conversation=<a>:<b> e ?type=a,b
+func parseTailFilter(r *http.Request) (TailFilter, error) {
+	q := r.URL.Query()
+	filter := TailFilter{UserID: q.Get("user")}
+	if filter.UserID != "" {
+		if _, err := utils.StringToUUID(filter.UserID); err != nil {
+			return filter, fmt.Errorf("user inválido: %w", err)
+		}
+	}
+	if conversation := q.Get("conversation"); conversation != "" {
+		a, b, ok := strings.Cut(conversation, ":")
+		if !ok || a == "" || b == "" {
+			return filter, fmt.Errorf("conversation deve ser <usuário>:<usuário>")
+		}
+		filter.Conversation = utils.ConversationKey(a, b)
+	}
+	if types := q.Get("type"); types != "" {
+		filter.Types = map[string]struct{}{}
+		for _, t := range strings.Split(types, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				filter.Types[t] = struct{}{}
+			}
+		}
+	}
+	return filter, nil
+}
+
+// handleTail GET /admin/events/tail: Server-Sent Events com os eventos do
+// tópico de mensagens que passam no filtro, a partir da conexão (sem histórico)
+func (h *handlers) handleTail(w http.ResponseWriter, r *http.Request) {
+	if h.svc.Tail == nil {
+		utils.Error(w, http.StatusNotImplemented, "tail de eventos desabilitado", "TAIL_UNAVAILABLE")
+		return
+	}
+	filter, err := parseTailFilter(r)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_FILTER")
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	sub, cancel := h.svc.Tail.subscribe(filter)
+	defer cancel()
+
+	heartbeat := time.NewTicker(tailHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-h.svc.Tail.closed:
+			return
+		case entry := <-sub.entries:
+			data, err := json.Marshal(entry)
+			if err != nil {
+				continue
+			}
+			_, err = fmt.Fprintf(w, "id: %s/%d/%d\nevent: %s\ndata: %s\n\n", entry.Topic, entry.Partition, entry.Offset, entry.Type, data)
+			if err == nil {
+				err = rc.Flush()
+			}
+			if err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			msg := ": ping\n\n"
+			if dropped := h.svc.Tail.takeDropped(sub); dropped > 0 {
+				msg = fmt.Sprintf("event: dropped\ndata: {\"count\": %d}\n\n", dropped)
+			}
+			if _, err := fmt.Fprint(w, msg); err != nil || rc.Flush() != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// handleRestoreUser restaura usuário removido (dentro do período de carência)
+func (h *handlers) handleRestoreUser(w http.ResponseWriter, r *http.Request) {
+	user, err := h.svc.Users.RestoreUser(r.Context(), r.PathValue("id"))
+	if errors.Is(err, service.ErrEmailTaken) || errors.Is(err, service.ErrUsernameTaken) {
+		utils.Error(w, http.StatusConflict, err.Error(), "RESTORE_CONFLICT")
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "RESTORE_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, user, "usuário restaurado")
+}
+
+// handleShadowBan aplica shadow ban (mensagens aceitas, mas não distribuídas)
+func (h *handlers) handleShadowBan(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.svc.Users.ShadowBan(r.Context(), id); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "SHADOW_BAN_FAILED")
+		return
+	}
+	h.auditModeration(r, id, service.AuditShadowBan)
+
+	utils.Success(w, http.StatusOK, nil, "shadow ban aplicado")
+}
+
+// handleLiftShadowBan remove shadow ban
+func (h *handlers) handleLiftShadowBan(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.svc.Users.LiftShadowBan(r.Context(), id); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "SHADOW_BAN_FAILED")
+		return
+	}
+	h.auditModeration(r, id, service.AuditShadowBanLift)
+
+	utils.Success(w, http.StatusOK, nil, "shadow ban removido")
+}
+
+// handleSetPlan atribui plano sem cobrança (vale até o próximo webhook da assinatura)
+func (h *handlers) handleSetPlan(w http.ResponseWriter, r *http.Request) {
+	var input types.SetPlanInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "JSON inválido", "INVALID_JSON")
+		return
+	}
+
+	plan, err := h.svc.Plans.SetPlan(r.Context(), r.PathValue("id"), input)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "SET_PLAN_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, plan, "plano atualizado")
+}
+
+// auditModeration registra ação de moderação no log de auditoria
+func (h *handlers) auditModeration(r *http.Request, userID, action string) {
+	uuid, err := utils.StringToUUID(userID)
+	if err != nil {
+		return
+	}
+	h.svc.Audit.Record(r.Context(), uuid, action, utils.ClientIP(r), 0, nil)
+}
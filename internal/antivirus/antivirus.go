@@ -0,0 +1,125 @@
+package antivirus
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// chunkSize tamanho dos blocos enviados ao clamd (INSTREAM)
+const chunkSize = 64 << 10
+
+// Result resultado da varredura
+type Result struct {
+	Infected  bool
+	Signature string // Nome da ameaça encontrada (quando Infected)
+	Skipped   bool   // Nenhum antivírus configurado
+}
+
+// Scanner verifica conteúdo de arquivos
+type Scanner interface {
+	Scan(ctx context.Context, r io.Reader) (Result, error)
+}
+
+// New cria scanner ClamAV se o endereço estiver configurado, senão um scanner vazio
+// O endereço é o do clamd, ex: tcp://clamav:3310 ou unix:///var/run/clamav/clamd.sock
+func New(addr string, timeout time.Duration) Scanner {
+	if addr == "" {
+		return NoopScanner{}
+	}
+
+	network, address := "tcp", addr
+	if i := strings.Index(addr, "://"); i >= 0 {
+		network, address = addr[:i], addr[i+3:]
+	}
+	return &ClamAV{
+		network: network,
+		address: address,
+		timeout: timeout,
+	}
+}
+
+// NoopScanner não verifica nada (antivírus desabilitado)
+type NoopScanner struct{}
+
+// Scan implementa Scanner
+func (NoopScanner) Scan(ctx context.Context, r io.Reader) (Result, error) {
+	return Result{Skipped: true}, nil
+}
+
+// ClamAV cliente do clamd usando o comando INSTREAM
+type ClamAV struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// Scan implementa Scanner
+func (c *ClamAV) Scan(ctx context.Context, r io.Reader) (Result, error) {
+	dialer := net.Dialer{Timeout: c.timeout}
+	conn, err := dialer.DialContext(ctx, c.network, c.address)
+	if err != nil {
+		return Result{}, fmt.Errorf("erro ao conectar no clamd: %w", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return Result{}, fmt.Errorf("erro ao enviar comando ao clamd: %w", err)
+	}
+
+	// Cada bloco vai prefixado com o tamanho (uint32 big-endian); tamanho 0 encerra
+	buf := make([]byte, chunkSize)
+	size := make([]byte, 4)
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := conn.Write(size); err != nil {
+				return Result{}, fmt.Errorf("erro ao enviar arquivo ao clamd: %w", err)
+			}
+			if _, err := conn.Write(buf[:n]); err != nil {
+				return Result{}, fmt.Errorf("erro ao enviar arquivo ao clamd: %w", err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return Result{}, fmt.Errorf("erro ao ler arquivo: %w", readErr)
+		}
+	}
+	binary.BigEndian.PutUint32(size, 0)
+	if _, err := conn.Write(size); err != nil {
+		return Result{}, fmt.Errorf("erro ao enviar arquivo ao clamd: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && err != io.EOF {
+		return Result{}, fmt.Errorf("erro ao ler resposta do clamd: %w", err)
+	}
+	return parseReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+// parseReply interpreta "stream: OK", "stream: <assinatura> FOUND" ou "... ERROR"
+func parseReply(reply string) (Result, error) {
+	reply = strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case reply == "OK":
+		return Result{}, nil
+	case strings.HasSuffix(reply, " FOUND"):
+		return Result{Infected: true, Signature: strings.TrimSuffix(reply, " FOUND")}, nil
+	default:
+		return Result{}, fmt.Errorf("clamd retornou erro: %s", reply)
+	}
+}
@@ -0,0 +1,185 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// Tipos de destino do arquivamento
+const (
+	SinkNone    = "none"
+	SinkWebhook = "webhook"
+	SinkDir     = "dir"
+)
+
+// Record mensagem finalizada espelhada no arquivo de compliance
+type Record struct {
+	ID             string   `json:"id"`
+	SenderID       string   `json:"sender_id"`
+	ReceiverID     string   `json:"receiver_id"`
+	Content        string   `json:"content"`
+	SentAt         string   `json:"sent_at"` // RFC 3339 (UTC)
+	Mentions       []string `json:"mentions,omitempty"`
+	AnnouncementID string   `json:"announcement_id,omitempty"`
+
+	// Origem no transporte: permite auditar lacunas e reprocessar
+	Topic     string `json:"topic"`
+	Partition int32  `json:"partition"`
+	Offset    int64  `json:"offset"`
+
+	ArchivedAt string `json:"archived_at"` // RFC 3339 (UTC)
+}
+
+// Sink destino do arquivamento
+// Put deve ser idempotente: reentregas e backfill repetem a mesma mensagem
+type Sink interface {
+	Enabled() bool
+	Put(ctx context.Context, record Record) error
+}
+
+// New cria o destino configurado (none, webhook ou dir)
+func New(kind, webhookURL, secret, dir string, timeout time.Duration) (Sink, error) {
+	switch kind {
+	case "", SinkNone:
+		return NoopSink{}, nil
+	case SinkWebhook:
+		return &WebhookSink{
+			url:    webhookURL,
+			secret: []byte(secret),
+			client: &http.Client{Timeout: timeout},
+		}, nil
+	case SinkDir:
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return nil, fmt.Errorf("erro ao criar diretório do arquivo: %w", err)
+		}
+		return &DirSink{root: dir}, nil
+	default:
+		return nil, fmt.Errorf("destino de arquivamento inválido: %s", kind)
+	}
+}
+
+// NoopSink não arquiva (exportação de compliance desabilitada)
+type NoopSink struct{}
+
+// Enabled implementa Sink
+func (NoopSink) Enabled() bool { return false }
+
+// Put implementa Sink
+func (NoopSink) Put(ctx context.Context, record Record) error { return nil }
+
+// WebhookSink envia cada registro por POST JSON assinado: X-Archive-Signature
+// é HMAC-SHA256 (hex) de "<X-Archive-Timestamp>.<corpo>" com o segredo
+// compartilhado; o receptor deduplica pelo id da mensagem
+type WebhookSink struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+// Enabled implementa Sink
+func (s *WebhookSink) Enabled() bool { return true }
+
+// Put implementa Sink; qualquer resposta fora de 2xx é erro (o evento volta
+// para a fila de retentativas)
+func (s *WebhookSink) Put(ctx context.Context, record Record) error {
+	body, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar registro: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição do arquivo: %w", err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", record.ID)
+	req.Header.Set("X-Archive-Timestamp", timestamp)
+	req.Header.Set("X-Archive-Signature", hex.EncodeToString(mac.Sum(nil)))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao enviar ao arquivo: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("arquivo respondeu %d para mensagem %s", resp.StatusCode, record.ID)
+	}
+	return nil
+}
+
+// DirSink grava um arquivo JSON por mensagem (<AAAA>/<MM>/<DD>/<id>.json)
+// uma única vez e somente leitura; para WORM, aponte para um volume com
+// retenção imutável (ex: bucket S3 com Object Lock montado)
+type DirSink struct {
+	root string
+}
+
+// Enabled implementa Sink
+func (s *DirSink) Enabled() bool { return true }
+
+// Put implementa Sink; registro já arquivado não é sobrescrito
+func (s *DirSink) Put(ctx context.Context, record Record) error {
+	sentAt, err := time.Parse(time.RFC3339, record.SentAt)
+	if err != nil || record.ID == "" || filepath.Base(record.ID) != record.ID {
+		return fmt.Errorf("registro inválido para arquivamento: %q", record.ID)
+	}
+
+	dir := filepath.Join(s.root, sentAt.UTC().Format("2006"), sentAt.UTC().Format("01"), sentAt.UTC().Format("02"))
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("erro ao criar diretório do arquivo: %w", err)
+	}
+	path := filepath.Join(dir, record.ID+".json")
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+
+	body, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar registro: %w", err)
+	}
+
+	// Temporário + link: o arquivo final nunca aparece parcial e um
+	// concorrente que chegue antes vence (EEXIST)
+	tmp, err := os.CreateTemp(dir, ".archive-*")
+	if err != nil {
+		return fmt.Errorf("erro ao criar arquivo: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(append(body, '\n'))
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0o440)
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao gravar registro: %w", err)
+	}
+
+	if err := os.Link(tmp.Name(), path); err != nil && !errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("erro ao gravar registro: %w", err)
+	}
+	return nil
+}
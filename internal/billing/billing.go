@@ -0,0 +1,54 @@
+// Package billing recebe webhooks de provedores de cobrança e os traduz em
+// mudanças de plano. O provedor valida a assinatura; quem aplica o plano é o
+// service.PlanService
+package billing
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Status de assinatura (os do Stripe; outros provedores mapeiam para estes)
+const (
+	StatusActive   = "active"
+	StatusTrialing = "trialing"
+	StatusPastDue  = "past_due" // Cobrança falhou, provedor ainda tentando
+	StatusCanceled = "canceled"
+)
+
+var (
+	// ErrInvalidSignature webhook sem assinatura válida (ou fora da tolerância)
+	ErrInvalidSignature = errors.New("assinatura do webhook inválida")
+	// ErrIgnored evento válido que não muda plano (responder 2xx)
+	ErrIgnored = errors.New("evento ignorado")
+)
+
+// Event mudança de assinatura informada pelo provedor
+type Event struct {
+	ID             string // ID do evento no provedor
+	UserID         string // Usuário da assinatura (metadata.user_id no checkout)
+	CustomerID     string
+	SubscriptionID string
+	Plan           string // free, pro ou workspace ("" = preço sem plano configurado)
+	Status         string
+	PeriodEnd      time.Time // Fim do período pago (zero = não informado)
+	OccurredAt     time.Time // Eventos fora de ordem: mais antigo que o aplicado é descartado
+}
+
+// Provider valida e traduz webhooks de um provedor de cobrança
+type Provider interface {
+	Name() string
+	ParseWebhook(payload []byte, header http.Header) (*Event, error)
+}
+
+// New provedor configurado; nil quando a cobrança está desligada
+// prices mapeia ID de preço do provedor para plano
+func New(provider, webhookSecret string, prices map[string]string) Provider {
+	switch provider {
+	case "stripe":
+		return NewStripe(webhookSecret, prices)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,142 @@
+package billing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"chat-kafka-go/pkg/clock"
+)
+
+// stripeTolerance idade máxima do timestamp assinado (anti-replay, como a SDK)
+const stripeTolerance = 5 * time.Minute
+
+// Stripe webhooks de assinatura do Stripe (customer.subscription.*), sem SDK:
+// confere o cabeçalho Stripe-Signature (HMAC-SHA256 de "t.payload")
+type Stripe struct {
+	secret []byte
+	prices map[string]string // price ID -> plano
+	clock  clock.Clock
+}
+
+// NewStripe cria provedor Stripe com o segredo do endpoint (whsec_...)
+func NewStripe(webhookSecret string, prices map[string]string) *Stripe {
+	return &Stripe{
+		secret: []byte(webhookSecret),
+		prices: prices,
+		clock:  clock.System,
+	}
+}
+
+// SetClock troca o relógio da tolerância (testes)
+func (s *Stripe) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Name implementa Provider
+func (s *Stripe) Name() string { return "stripe" }
+
+// stripeEvent campos usados do evento e da assinatura (data.object)
+type stripeEvent struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Created int64  `json:"created"`
+	Data    struct {
+		Object struct {
+			ID               string            `json:"id"`
+			Customer         string            `json:"customer"`
+			Status           string            `json:"status"`
+			CurrentPeriodEnd int64             `json:"current_period_end"`
+			Metadata         map[string]string `json:"metadata"`
+			Items            struct {
+				Data []struct {
+					Price struct {
+						ID string `json:"id"`
+					} `json:"price"`
+				} `json:"data"`
+			} `json:"items"`
+		} `json:"object"`
+	} `json:"data"`
+}
+
+// ParseWebhook implementa Provider
+func (s *Stripe) ParseWebhook(payload []byte, header http.Header) (*Event, error) {
+	if err := s.verify(payload, header.Get("Stripe-Signature")); err != nil {
+		return nil, err
+	}
+
+	var e stripeEvent
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return nil, fmt.Errorf("evento do Stripe inválido: %w", err)
+	}
+	switch e.Type {
+	case "customer.subscription.created", "customer.subscription.updated", "customer.subscription.deleted":
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrIgnored, e.Type)
+	}
+
+	sub := e.Data.Object
+	event := &Event{
+		ID:             e.ID,
+		UserID:         sub.Metadata["user_id"],
+		CustomerID:     sub.Customer,
+		SubscriptionID: sub.ID,
+		Status:         sub.Status,
+		OccurredAt:     time.Unix(e.Created, 0),
+	}
+	if e.Type == "customer.subscription.deleted" {
+		event.Status = StatusCanceled
+	}
+	if sub.CurrentPeriodEnd > 0 {
+		event.PeriodEnd = time.Unix(sub.CurrentPeriodEnd, 0)
+	}
+	for _, item := range sub.Items.Data {
+		if plan, ok := s.prices[item.Price.ID]; ok {
+			event.Plan = plan
+			break
+		}
+	}
+	if event.UserID == "" {
+		return nil, fmt.Errorf("assinatura %s sem metadata.user_id", sub.ID)
+	}
+	return event, nil
+}
+
+// verify confere t=<unix>,v1=<hex>[,v1=...] contra o payload
+func (s *Stripe) verify(payload []byte, signature string) error {
+	var timestamp string
+	var candidates []string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			candidates = append(candidates, value)
+		}
+	}
+	t, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(candidates) == 0 {
+		return ErrInvalidSignature
+	}
+	if age := s.clock.Now().Sub(time.Unix(t, 0)); age > stripeTolerance || age < -stripeTolerance {
+		return fmt.Errorf("%w: timestamp fora da tolerância", ErrInvalidSignature)
+	}
+
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	for _, candidate := range candidates {
+		if hmac.Equal([]byte(expected), []byte(candidate)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
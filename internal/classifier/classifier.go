@@ -0,0 +1,86 @@
+package classifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Classification resultado da classificação de uma imagem
+type Classification struct {
+	Score  float64  // Probabilidade (0 a 1) de conteúdo impróprio
+	Labels []string // Categorias detectadas (ex: nudity, gore)
+}
+
+// Classifier classifica imagens quanto a conteúdo impróprio (NSFW)
+type Classifier interface {
+	Classify(ctx context.Context, contentType string, r io.Reader) (Classification, error)
+	Enabled() bool
+}
+
+// New cria classificador HTTP se a URL estiver configurada, senão um classificador vazio
+// O serviço recebe a imagem no corpo do POST e responde JSON com score e labels
+func New(url string, timeout time.Duration) Classifier {
+	if url == "" {
+		return NoopClassifier{}
+	}
+	return &HTTPClassifier{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// NoopClassifier não classifica (detecção desabilitada)
+type NoopClassifier struct{}
+
+// Classify implementa Classifier
+func (NoopClassifier) Classify(ctx context.Context, contentType string, r io.Reader) (Classification, error) {
+	return Classification{}, nil
+}
+
+// Enabled implementa Classifier
+func (NoopClassifier) Enabled() bool { return false }
+
+// HTTPClassifier envia a imagem para um serviço de classificação externo
+type HTTPClassifier struct {
+	url    string
+	client *http.Client
+}
+
+// Enabled implementa Classifier
+func (c *HTTPClassifier) Enabled() bool { return true }
+
+// Classify implementa Classifier
+func (c *HTTPClassifier) Classify(ctx context.Context, contentType string, r io.Reader) (Classification, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, r)
+	if err != nil {
+		return Classification{}, fmt.Errorf("erro ao criar requisição de classificação: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return Classification{}, fmt.Errorf("erro ao consultar classificador: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Classification{}, fmt.Errorf("classificador retornou status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Score  float64  `json:"score"`
+		Labels []string `json:"labels"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&body); err != nil {
+		return Classification{}, fmt.Errorf("resposta do classificador inválida: %w", err)
+	}
+	if body.Score < 0 || body.Score > 1 {
+		return Classification{}, fmt.Errorf("score fora do intervalo 0-1: %v", body.Score)
+	}
+
+	return Classification{Score: body.Score, Labels: body.Labels}, nil
+}
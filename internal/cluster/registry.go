@@ -0,0 +1,173 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// Registro de conexões no Redis (CLUSTER_CONNECTION_REGISTRY)
+// Cada instância grava os próprios usuários em dois hashes com TTL,
+// regravados a cada heartbeat: instância caída some sozinha
+const (
+	instanceConnsPrefix = "chat:cluster:conns:" // HASH usuário → conexões, um por instância
+	userConnsPrefix     = "chat:cluster:user:"  // HASH instância → conexões, um por usuário
+	dirtyBuffer         = 4096                  // Alterações pendentes antes de esperar o heartbeat
+)
+
+// InstanceConnections resumo das conexões de uma instância
+type InstanceConnections struct {
+	Instance    string `json:"instance"`
+	URL         string `json:"url,omitempty"`
+	Users       int    `json:"users"`
+	Connections int    `json:"connections"`
+}
+
+// markDirty agenda a gravação do usuário (callback do hub, não bloqueia)
+func (r *Router) markDirty(userID string) {
+	select {
+	case r.dirty <- userID:
+	default:
+		// Fila cheia: o próximo heartbeat regrava tudo
+	}
+}
+
+// syncUsers grava as conexões atuais dos usuários (nil = todos desta instância,
+// inclusive os que desconectaram desde a última gravação)
+func (r *Router) syncUsers(ctx context.Context, users []string) error {
+	r.pubMu.Lock()
+	defer r.pubMu.Unlock()
+	if r.left.Load() {
+		return nil
+	}
+
+	if users == nil {
+		all := map[string]bool{}
+		for _, userID := range r.hub.Users() {
+			all[userID] = true
+		}
+		for userID := range r.published {
+			all[userID] = true
+		}
+		for userID := range all {
+			users = append(users, userID)
+		}
+	}
+
+	instanceKey := instanceConnsPrefix + r.cfg.InstanceID
+	pipe := r.rdb.Pipeline()
+	counts := make(map[string]int, len(users))
+	for _, userID := range users {
+		count := r.hub.Count(userID)
+		counts[userID] = count
+		userKey := userConnsPrefix + userID
+		if count == 0 {
+			pipe.HDel(ctx, instanceKey, userID)
+			pipe.HDel(ctx, userKey, r.cfg.InstanceID)
+			continue
+		}
+		pipe.HSet(ctx, instanceKey, userID, count)
+		pipe.HSet(ctx, userKey, r.cfg.InstanceID, count)
+		pipe.Expire(ctx, userKey, r.cfg.InstanceTTL)
+	}
+	pipe.Expire(ctx, instanceKey, r.cfg.InstanceTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("erro ao gravar registro de conexões: %w", err)
+	}
+
+	for userID, count := range counts {
+		if count == 0 {
+			delete(r.published, userID)
+		} else {
+			r.published[userID] = true
+		}
+	}
+	return nil
+}
+
+// clearRegistry remove os usuários desta instância do registro (drenagem)
+func (r *Router) clearRegistry(ctx context.Context) error {
+	r.pubMu.Lock()
+	defer r.pubMu.Unlock()
+
+	pipe := r.rdb.Pipeline()
+	pipe.Del(ctx, instanceConnsPrefix+r.cfg.InstanceID)
+	for userID := range r.published {
+		pipe.HDel(ctx, userConnsPrefix+userID, r.cfg.InstanceID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+	r.published = map[string]bool{}
+	return nil
+}
+
+// UserConnections conexões do usuário por instância viva
+func (r *Router) UserConnections(ctx context.Context, userID string) (map[string]int, error) {
+	if !r.cfg.ConnectionRegistry {
+		return map[string]int{r.cfg.InstanceID: r.hub.Count(userID)}, nil
+	}
+
+	fields, err := r.rdb.HGetAll(ctx, userConnsPrefix+userID).Result()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar registro de conexões: %w", err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	counts := map[string]int{}
+	for instance, value := range fields {
+		if _, live := r.urls[instance]; !live {
+			continue // Instância caída: entrada expira com o TTL
+		}
+		if count, err := strconv.Atoi(value); err == nil && count > 0 {
+			counts[instance] = count
+		}
+	}
+	return counts, nil
+}
+
+// Connections resumo de conexões de cada instância viva
+func (r *Router) Connections(ctx context.Context) ([]InstanceConnections, error) {
+	r.mu.RLock()
+	members := append([]string(nil), r.members...)
+	urls := make(map[string]string, len(r.urls))
+	for id, url := range r.urls {
+		urls[id] = url
+	}
+	r.mu.RUnlock()
+
+	pipe := r.rdb.Pipeline()
+	values := make([]interface{ Result() ([]string, error) }, len(members))
+	for i, instance := range members {
+		values[i] = pipe.HVals(ctx, instanceConnsPrefix+instance)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("erro ao consultar registro de conexões: %w", err)
+	}
+
+	instances := []InstanceConnections{}
+	for i, instance := range members {
+		counts, _ := values[i].Result()
+		summary := InstanceConnections{Instance: instance, URL: urls[instance], Users: len(counts)}
+		for _, value := range counts {
+			count, _ := strconv.Atoi(value)
+			summary.Connections += count
+		}
+		instances = append(instances, summary)
+	}
+	return instances, nil
+}
+
+// Online indica se o usuário tem conexão em alguma instância
+// Sem registro de conexões, só esta instância é consultada
+func (r *Router) Online(ctx context.Context, userID string) (bool, error) {
+	if r.hub.IsOnline(userID) {
+		return true, nil
+	}
+	counts, err := r.UserConnections(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return len(counts) > 0, nil
+}
@@ -0,0 +1,62 @@
+package cluster
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// Ring anel de hash consistente com nós virtuais: cada instância ocupa
+// vários pontos do anel e o usuário pertence ao primeiro ponto depois do
+// hash dele. Entrada ou saída de uma instância move só os usuários dela
+type Ring struct {
+	points  []uint32
+	owners  map[uint32]string
+	members []string
+}
+
+// NewRing monta o anel com as instâncias informadas
+func NewRing(members []string, virtualNodes int) *Ring {
+	r := &Ring{
+		owners:  map[uint32]string{},
+		members: append([]string(nil), members...),
+	}
+	sort.Strings(r.members)
+	for _, member := range r.members {
+		for i := 0; i < max(virtualNodes, 1); i++ {
+			point := hashKey(member + "#" + strconv.Itoa(i))
+			if _, taken := r.owners[point]; taken {
+				continue // Colisão: mantém o primeiro (ordem estável)
+			}
+			r.owners[point] = member
+			r.points = append(r.points, point)
+		}
+	}
+	sort.Slice(r.points, func(i, j int) bool { return r.points[i] < r.points[j] })
+	return r
+}
+
+// Owner instância responsável pela chave ("" com o anel vazio)
+func (r *Ring) Owner(key string) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+	h := hashKey(key)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.owners[r.points[i]]
+}
+
+// Members instâncias do anel, em ordem
+func (r *Ring) Members() []string {
+	return r.members
+}
+
+// hashKey posição da chave no anel
+func hashKey(key string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return h.Sum32()
+}
@@ -0,0 +1,348 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/metrics"
+	"chat-kafka-go/internal/ws"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Chaves no Redis
+const (
+	instancesKey      = "chat:cluster:instances" // ZSET instância → expiração (unix ms)
+	instanceURLPrefix = "chat:cluster:instance:" // URL pública da instância (com TTL)
+	deliverPrefix     = "chat:cluster:deliver:"  // Canal pub/sub de entrega de cada instância
+	redisTimeout      = 2 * time.Second          // Prazo das operações no Redis
+)
+
+// forwardedFrame frame encaminhado para a instância dona do usuário
+type forwardedFrame struct {
+	UserID string          `json:"user_id"`
+	Type   string          `json:"type"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// Router entrega frames entre instâncias do cluster. Cada instância se
+// registra no Redis (heartbeat com TTL) e recebe entregas pelo seu próprio
+// canal pub/sub, então um frame vai só para quem tem o usuário em vez de
+// um broadcast para todas.
+// Com CLUSTER_ROUTING=consistent-hash os usuários pertencem a uma instância
+// por hash consistente (URL devolvida com o ticket); com o registro de
+// conexões, as entregas vão às instâncias onde o usuário está conectado
+type Router struct {
+	rdb *redis.Client
+	cfg *config.ClusterConfig
+	hub *ws.Hub
+
+	mu      sync.RWMutex
+	ring    *Ring             // Vazio sem CLUSTER_ROUTING=consistent-hash
+	members []string          // Instâncias vivas, em ordem
+	urls    map[string]string // Instância → URL pública
+
+	left atomic.Bool // Saiu do anel (drenando)
+
+	// Registro de conexões (CLUSTER_CONNECTION_REGISTRY)
+	dirty     chan string // Usuários com conexões alteradas a publicar
+	pubMu     sync.Mutex
+	published map[string]bool // Usuários desta instância gravados no registro
+}
+
+// NewRouter conecta ao Redis, registra a instância e carrega o anel
+func NewRouter(cfg *config.ClusterConfig, hub *ws.Hub) (*Router, error) {
+	opts, err := redis.ParseURL(cfg.RedisURL)
+	if err != nil {
+		return nil, fmt.Errorf("REDIS_URL inválida: %w", err)
+	}
+	r := &Router{
+		rdb:  redis.NewClient(opts),
+		cfg:  cfg,
+		hub:  hub,
+		ring: NewRing(nil, cfg.VirtualNodes),
+		urls: map[string]string{},
+	}
+	if cfg.ConnectionRegistry {
+		r.dirty = make(chan string, dirtyBuffer)
+		r.published = map[string]bool{}
+		hub.OnChange(r.markDirty)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	if err := r.heartbeat(ctx); err != nil {
+		r.rdb.Close()
+		return nil, fmt.Errorf("falha ao registrar instância no Redis: %w", err)
+	}
+	if err := r.refresh(ctx); err != nil {
+		r.rdb.Close()
+		return nil, err
+	}
+
+	log.Printf("✓ Cluster: instância %s, %d ativa(s) (roteamento %s, registro de conexões %t)",
+		cfg.InstanceID, len(r.members), cfg.Routing, cfg.ConnectionRegistry)
+	return r, nil
+}
+
+// Run recebe entregas encaminhadas e mantém o registro até o contexto terminar
+func (r *Router) Run(ctx context.Context) error {
+	sub := r.rdb.Subscribe(ctx, deliverPrefix+r.cfg.InstanceID)
+	defer sub.Close()
+	frames := sub.Channel()
+
+	ticker := time.NewTicker(r.cfg.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-frames:
+			if !ok {
+				return nil
+			}
+			r.deliverForwarded(msg.Payload)
+		case userID := <-r.dirty: // nil sem registro: nunca dispara
+			userCtx, cancel := context.WithTimeout(ctx, redisTimeout)
+			if err := r.syncUsers(userCtx, []string{userID}); err != nil {
+				log.Printf("ERRO: registro de conexões: %v", err)
+			}
+			cancel()
+		case <-ticker.C:
+			tickCtx, cancel := context.WithTimeout(ctx, redisTimeout)
+			if err := r.heartbeat(tickCtx); err != nil {
+				log.Printf("ERRO: heartbeat do cluster: %v", err)
+			}
+			if err := r.refresh(tickCtx); err != nil {
+				log.Printf("ERRO: leitura do anel do cluster: %v", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// SendToUser implementa ws.Deliverer: entrega às conexões locais do usuário
+// e encaminha só às outras instâncias onde ele está (registro de conexões)
+// ou à instância dona (hash consistente)
+// Retorna conexões locais + instâncias que receberam o encaminhamento
+func (r *Router) SendToUser(userID, frameType string, data interface{}) (int, error) {
+	delivered, err := r.hub.SendToUser(userID, frameType, data)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	targets, err := r.targets(ctx, userID)
+	if err != nil || len(targets) == 0 {
+		return delivered, err
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return delivered, err
+	}
+	payload, err := json.Marshal(forwardedFrame{UserID: userID, Type: frameType, Data: raw})
+	if err != nil {
+		return delivered, err
+	}
+
+	for _, instance := range targets {
+		receivers, err := r.rdb.Publish(ctx, deliverPrefix+instance, payload).Result()
+		if err != nil {
+			metrics.ClusterForwardedTotal.WithLabelValues("error").Inc()
+			return delivered, fmt.Errorf("erro ao encaminhar para a instância %s: %w", instance, err)
+		}
+		metrics.ClusterForwardedTotal.WithLabelValues("success").Inc()
+		delivered += int(receivers)
+	}
+	return delivered, nil
+}
+
+// targets outras instâncias que devem receber frames do usuário
+func (r *Router) targets(ctx context.Context, userID string) ([]string, error) {
+	if r.cfg.ConnectionRegistry {
+		counts, err := r.UserConnections(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		targets := []string{}
+		for instance := range counts {
+			if instance != r.cfg.InstanceID {
+				targets = append(targets, instance)
+			}
+		}
+		return targets, nil
+	}
+
+	owner := r.Owner(userID)
+	if owner.ID == "" || owner.ID == r.cfg.InstanceID {
+		return nil, nil
+	}
+	return []string{owner.ID}, nil
+}
+
+// Instance instância do anel
+type Instance struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+// Owner instância dona do usuário (vazia com o anel vazio)
+func (r *Router) Owner(userID string) Instance {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	id := r.ring.Owner(userID)
+	return Instance{ID: id, URL: r.urls[id]}
+}
+
+// IsLocal indica se o usuário pertence a esta instância
+func (r *Router) IsLocal(userID string) bool {
+	owner := r.Owner(userID).ID
+	return owner == "" || owner == r.cfg.InstanceID
+}
+
+// Leave tira a instância do anel (drenagem): as demais passam a rotear os
+// usuários dela para outras instâncias no próximo heartbeat
+func (r *Router) Leave(ctx context.Context) {
+	r.left.Store(true)
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+
+	pipe := r.rdb.TxPipeline()
+	pipe.ZRem(ctx, instancesKey, r.cfg.InstanceID)
+	pipe.Del(ctx, instanceURLPrefix+r.cfg.InstanceID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("ERRO: saída do anel do cluster: %v", err)
+	}
+	if r.cfg.ConnectionRegistry {
+		if err := r.clearRegistry(ctx); err != nil {
+			log.Printf("ERRO: limpeza do registro de conexões: %v", err)
+		}
+	}
+}
+
+// Close fecha a conexão com o Redis
+func (r *Router) Close() error {
+	return r.rdb.Close()
+}
+
+// Stats resumo para /debug/dump
+func (r *Router) Stats() map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return map[string]interface{}{
+		"instance":            r.cfg.InstanceID,
+		"instances":           r.members,
+		"routing":             r.cfg.Routing,
+		"connection_registry": r.cfg.ConnectionRegistry,
+		"left":                r.left.Load(),
+	}
+}
+
+// heartbeat renova o registro da instância e remove as expiradas
+func (r *Router) heartbeat(ctx context.Context) error {
+	if r.left.Load() {
+		return nil
+	}
+	now := time.Now()
+	pipe := r.rdb.TxPipeline()
+	pipe.Set(ctx, instanceURLPrefix+r.cfg.InstanceID, r.cfg.AdvertiseURL, r.cfg.InstanceTTL)
+	pipe.ZAdd(ctx, instancesKey, redis.Z{
+		Score:  float64(now.Add(r.cfg.InstanceTTL).UnixMilli()),
+		Member: r.cfg.InstanceID,
+	})
+	pipe.ZRemRangeByScore(ctx, instancesKey, "-inf", "("+strconv.FormatInt(now.UnixMilli(), 10))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	// Regrava o registro inteiro: corrige atualizações perdidas e renova o TTL
+	if r.cfg.ConnectionRegistry {
+		return r.syncUsers(ctx, nil)
+	}
+	return nil
+}
+
+// refresh relê as instâncias vivas, reconstrói o anel e pede às conexões
+// locais de usuários de outra instância que migrem
+func (r *Router) refresh(ctx context.Context) error {
+	ids, err := r.rdb.ZRangeByScore(ctx, instancesKey, &redis.ZRangeBy{
+		Min: strconv.FormatInt(time.Now().UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("erro ao listar instâncias: %w", err)
+	}
+
+	urls := map[string]string{}
+	members := []string{}
+	if len(ids) > 0 {
+		keys := make([]string, len(ids))
+		for i, id := range ids {
+			keys[i] = instanceURLPrefix + id
+		}
+		values, err := r.rdb.MGet(ctx, keys...).Result()
+		if err != nil {
+			return fmt.Errorf("erro ao buscar URLs das instâncias: %w", err)
+		}
+		for i, v := range values {
+			if url, ok := v.(string); ok {
+				urls[ids[i]] = url
+				members = append(members, ids[i])
+			}
+		}
+	}
+
+	sort.Strings(members)
+	ring := NewRing(nil, r.cfg.VirtualNodes)
+	if r.cfg.Routing == "consistent-hash" {
+		ring = NewRing(members, r.cfg.VirtualNodes)
+	}
+	r.mu.Lock()
+	changed := fmt.Sprint(members) != fmt.Sprint(r.members)
+	r.ring, r.members, r.urls = ring, members, urls
+	r.mu.Unlock()
+
+	if changed {
+		log.Printf("Cluster: %d instância(s) %v", len(members), members)
+	}
+	r.rebalance()
+	return nil
+}
+
+// rebalance avisa conexões locais de usuários que pertencem a outra instância
+// (anel mudou ou cliente conectou fora do dono); sem anel ou drenando, nada
+func (r *Router) rebalance() {
+	if r.left.Load() {
+		return
+	}
+	for _, userID := range r.hub.Users() {
+		owner := r.Owner(userID)
+		if owner.ID != "" && owner.ID != r.cfg.InstanceID && owner.URL != "" {
+			r.hub.MigrateUser(userID, owner.URL)
+		}
+	}
+}
+
+// deliverForwarded entrega às conexões locais um frame encaminhado por outra instância
+func (r *Router) deliverForwarded(payload string) {
+	var frame forwardedFrame
+	if err := json.Unmarshal([]byte(payload), &frame); err != nil {
+		log.Printf("ERRO: frame encaminhado inválido: %v", err)
+		return
+	}
+	if _, err := r.hub.SendToUser(frame.UserID, frame.Type, frame.Data); err != nil {
+		log.Printf("ERRO: entrega de frame encaminhado: %v", err)
+	}
+}
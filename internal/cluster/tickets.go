@@ -0,0 +1,76 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"chat-kafka-go/internal/ws"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ticketPrefix hash do ticket → usuário (TTL de ws.TicketTTL)
+const ticketPrefix = "chat:cluster:ticket:"
+
+// TicketStore tickets de conexão no Redis do cluster: o ticket emitido por
+// uma instância pode ser resgatado pela instância dona do usuário (ws_url)
+type TicketStore struct {
+	rdb   *redis.Client
+	clock clock.Clock // Expiração devolvida ao cliente
+}
+
+var _ ws.TicketStore = (*TicketStore)(nil)
+
+// NewTicketStore cria store sobre o Redis informado
+func NewTicketStore(rdb *redis.Client) *TicketStore {
+	return &TicketStore{rdb: rdb, clock: clock.System}
+}
+
+// Tickets store de tickets compartilhado pelo Redis do roteador
+func (r *Router) Tickets() *TicketStore {
+	return NewTicketStore(r.rdb)
+}
+
+// SetClock troca o relógio (testes)
+func (s *TicketStore) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Issue implementa ws.TicketStore
+func (s *TicketStore) Issue(ctx context.Context, userID string) (string, time.Time, error) {
+	value, err := utils.GenerateSecureToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+	if err := s.rdb.Set(ctx, ticketPrefix+utils.HashToken(value), userID, ws.TicketTTL).Err(); err != nil {
+		return "", time.Time{}, fmt.Errorf("erro ao gravar ticket: %w", err)
+	}
+	return value, s.clock.Now().Add(ws.TicketTTL), nil
+}
+
+// Redeem implementa ws.TicketStore: GETDEL garante o uso único entre instâncias
+// e o TTL do Redis descarta os expirados
+func (s *TicketStore) Redeem(ctx context.Context, value string) (string, bool) {
+	if value == "" {
+		return "", false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+	userID, err := s.rdb.GetDel(ctx, ticketPrefix+utils.HashToken(value)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("ERRO: resgate de ticket: %v", err)
+		}
+		return "", false
+	}
+	return userID, true
+}
@@ -0,0 +1,66 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"chat-kafka-go/internal/ws"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTicketStores dois stores (instâncias) sobre o mesmo Redis
+func newTicketStores(t *testing.T) (*miniredis.Miniredis, *TicketStore, *TicketStore) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	newStore := func() *TicketStore {
+		rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		t.Cleanup(func() { rdb.Close() })
+		return NewTicketStore(rdb)
+	}
+	return mr, newStore(), newStore()
+}
+
+func TestTicketStoreRedeemOnOtherInstance(t *testing.T) {
+	ctx := context.Background()
+	_, issuer, owner := newTicketStores(t)
+
+	value, _, err := issuer.Issue(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	userID, ok := owner.Redeem(ctx, value)
+	if !ok || userID != "user-1" {
+		t.Fatalf("Redeem = %q, %v; esperado user-1, true", userID, ok)
+	}
+	if _, ok := issuer.Redeem(ctx, value); ok {
+		t.Fatal("ticket resgatado duas vezes")
+	}
+}
+
+func TestTicketStoreExpiry(t *testing.T) {
+	ctx := context.Background()
+	mr, issuer, owner := newTicketStores(t)
+
+	value, _, err := issuer.Issue(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	mr.FastForward(ws.TicketTTL + time.Second)
+	if _, ok := owner.Redeem(ctx, value); ok {
+		t.Fatal("ticket expirado aceito")
+	}
+}
+
+func TestTicketStoreUnknown(t *testing.T) {
+	_, store, _ := newTicketStores(t)
+	for _, value := range []string{"", "desconhecido"} {
+		if _, ok := store.Redeem(context.Background(), value); ok {
+			t.Fatalf("ticket %q aceito", value)
+		}
+	}
+}
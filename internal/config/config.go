@@ -2,20 +2,46 @@ package config
 
 import (
 	"fmt"
+	"net/netip"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/validate"
+
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
 	Server   ServerConfig
+	Startup  StartupConfig
 	Database DatabaseConfig
+	EventBus EventBusConfig
 	Kafka    KafkaConfig
+	NATS     NATSConfig
+	Cluster  ClusterConfig
 	JWT      JWTConfig
 	Worker   WorkerConfig
+	Reporter ReporterConfig
+	Admin    AdminConfig
+	Log      LogConfig
+	User     UserConfig
+	Mail     MailConfig
+	Security SecurityConfig
+	Storage  StorageConfig
+	Search   SearchConfig
+	History  HistoryConfig
+	Quota    QuotaConfig
+	Plan     PlanConfig
+	Billing  BillingConfig
+
+	Maintenance MaintenanceConfig
+	SLO         SLOConfig
+	Support     SupportConfig
+	Archive     ArchiveConfig
 }
 
 type ServerConfig struct {
@@ -23,6 +49,71 @@ type ServerConfig struct {
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
 	ShutdownTimeout time.Duration
+
+	WSAllowedOrigins []string // Origens aceitas no handshake WebSocket (vazio = todas)
+
+	// Drenagem antes de encerrar (pre-stop ou SIGTERM)
+	DrainDelay     time.Duration // /readyz 503 por esse tempo antes de migrar as conexões
+	WSMigrateGrace time.Duration // Prazo para os clientes reconectarem antes do fechamento
+
+	// Limites por rota: prazo (context deadline) e corpo máximo; 0 = sem limite
+	RequestTimeout   time.Duration // Rotas comuns
+	TransferTimeout  time.Duration // Conteúdo de anexos (envio, download, tus)
+	AuthBodyBytes    int64         // Rotas /auth/*
+	DefaultBodyBytes int64         // Demais rotas (envio de anexos: ATTACHMENT_MAX_BYTES)
+	SlowRequest      time.Duration // Requisições mais lentas são logadas (0 = desligado)
+
+	// TLS nativo (sem proxy reverso obrigatório): certificado em arquivo ou
+	// ACME (autocert); vazio = HTTP puro
+	TLSCertFile     string
+	TLSKeyFile      string
+	TLSAutocert     []string // Domínios do certificado automático (Let's Encrypt)
+	TLSAutocertDir  string   // Cache dos certificados emitidos
+	TLSAutocertMail string   // Contato na conta ACME (opcional)
+	TLSMinVersion   string   // 1.2 ou 1.3
+	TLSHTTPPort     string   // Porta HTTP para desafio ACME e redirect (vazio = desligada)
+	HTTP2           bool     // HTTP/2 via ALPN quando TLS está ativo
+
+	// Proxies confiáveis (balanceador): o IP do cliente vem de RealIPHeader só
+	// quando a conexão chega de um deles; vazio = usa o endereço da conexão
+	TrustedProxies []string // IPs ou CIDRs
+	RealIPHeader   string   // X-Forwarded-For (padrão), X-Real-IP, CF-Connecting-IP...
+
+	// Compressão gzip das respostas (históricos, exportações)
+	Compression         bool
+	CompressionMinBytes int      // Respostas menores saem sem compressão
+	CompressionTypes    []string // Content-Types comprimidos
+	CompressionExclude  []string // Prefixos de rota sem compressão (binários já comprimidos)
+
+	// POST /batch: sub-requisições GET executadas no próprio roteador
+	BatchMaxRequests int // Itens por lote
+	BatchConcurrency int // Itens executados em paralelo
+}
+
+// TLSEnabled indica se a API serve HTTPS
+func (c *ServerConfig) TLSEnabled() bool {
+	return c.TLSCertFile != "" || len(c.TLSAutocert) > 0
+}
+
+// Modos aceitos em STARTUP_DB_MODE e STARTUP_EVENT_BUS_MODE
+const (
+	StartupFailFast = "fail-fast"
+	StartupWait     = "wait"
+	StartupDegraded = "degraded"
+)
+
+// Comportamento no boot quando uma dependência está fora
+// fail-fast: encerra o processo; wait: tenta de novo até WaitTimeout;
+// degraded: sobe mesmo assim e conecta em background (/readyz informa)
+type StartupConfig struct {
+	DatabaseMode  string        // Postgres: fail-fast, wait ou degraded
+	EventBusMode  string        // Barramento: fail-fast, wait ou degraded (degraded só com Kafka)
+	WaitTimeout   time.Duration // wait: desiste depois disso (0 = sem limite)
+	RetryInterval time.Duration // Intervalo entre tentativas (wait e degraded)
+
+	// Self-check do boot (schema, tópicos, segredos, relógio): problema
+	// crítico encerra o processo; false apenas loga
+	SelfCheckStrict bool
 }
 
 type DatabaseConfig struct {
@@ -35,28 +126,342 @@ type DatabaseConfig struct {
 	MaxOpenConns    int
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
+
+	// DSNs das réplicas de leitura (opcional); vazio = tudo no primário
+	ReplicaDSNs []string
+
+	MessageIDMode string // uuidv7 (padrão) ou ulid, ordenados pelo tempo (melhor localidade no índice), ou uuid (aleatório)
+}
+
+type EventBusConfig struct {
+	Backend      string        // kafka, nats (JetStream), postgres (LISTEN/NOTIFY) ou memory (um processo, sem durabilidade)
+	PollInterval time.Duration // postgres: busca periódica mesmo sem NOTIFY
+	Retention    time.Duration // postgres: eventos mais antigos são apagados (0 = para sempre)
+	BufferSize   int           // memory: eventos pendentes por grupo antes de bloquear o envio
+	Delivery     string        // at-least-once, at-most-once ou exactly-once (ver eventbus.DeliveryAtLeastOnce)
+
+	// Atrasos dos tópicos de retry, em ordem; depois do último o evento vai
+	// para a DLQ (vazio = sem retry, erro só é registrado)
+	RetryDelays []time.Duration
+	// Panics/timeouts do mesmo evento antes da quarentena (0 = nunca)
+	PoisonThreshold int
+}
+
+type NATSConfig struct {
+	URL        string
+	MaxAge     time.Duration // Retenção do stream (0 = para sempre)
+	AckWait    time.Duration // Sem ack nesse prazo, o evento é reentregue
+	MaxDeliver int           // Tentativas de entrega antes de descartar (-1 = sem limite)
 }
 
 type KafkaConfig struct {
 	Brokers       []string
-	Topic         string
+	Topic         string // Faixa prioritária: eventos de mensagem (entrega em tempo real)
 	ConsumerGroup string
 	RetryMax      int
+
+	// Faixa bulk: digests, importações, exportações (pool de workers separado)
+	BulkTopic         string
+	BulkConsumerGroup string
+
+	// Batching do producer assíncrono
+	Linger       time.Duration // Tempo máximo aguardando completar o lote
+	BatchBytes   int           // Tamanho alvo do lote em bytes
+	BatchMaxMsgs int           // Máximo de mensagens por lote (0 = sem limite)
+	MaxInFlight  int           // Requisições simultâneas por broker (só at-most-once; idempotente usa 1)
+
+	// exactly-once: identificador transacional, estável e único por instância
+	TransactionalID string
+
+	// Contingência: circuito do producer e buffer em disco (fora de exactly-once)
+	BreakerThreshold int           // Falhas de entrega consecutivas que abrem o circuito (0 = nunca abre)
+	BreakerCooldown  time.Duration // Tempo aberto antes de voltar a tentar o Kafka
+	SpillDir         string        // Diretório do buffer ("" = eventos não entregues são descartados)
+	SpillMaxBytes    int64         // Limite do buffer; acima disso eventos são perdidos (alerta)
+}
+
+// Roteamento de usuários entre instâncias (implantações grandes)
+type ClusterConfig struct {
+	Routing           string        // off ou consistent-hash
+	RedisURL          string        // Registro compartilhado de instâncias (redis://...)
+	InstanceID        string        // Único por instância (padrão: hostname)
+	AdvertiseURL      string        // URL WebSocket pública desta instância (ex.: wss://chat-1.example.com/ws)
+	HeartbeatInterval time.Duration // Renovação do registro e releitura do anel
+	InstanceTTL       time.Duration // Sem heartbeat nesse prazo a instância sai do anel
+	VirtualNodes      int           // Pontos de cada instância no anel
+
+	// Registro compartilhado de conexões (usuário → instâncias): entrega
+	// direcionada entre instâncias, presença e /admin/connections
+	ConnectionRegistry bool
+}
+
+// Enabled indica se a instância participa do cluster (roteamento ou registro)
+func (c *ClusterConfig) Enabled() bool {
+	return c.Routing == "consistent-hash" || c.ConnectionRegistry
 }
 
 type JWTConfig struct {
 	AccessSecret      string
 	RefreshSecret     string
-	AccessExpiration  time.Duration
-	RefreshExpiration time.Duration
+	AccessExpiration  time.Duration // Validade do access token (JWT_ACCESS_TTL)
+	RefreshExpiration time.Duration // Validade do refresh token e da sessão (JWT_REFRESH_TTL)
+	Issuer            string        // iss dos access tokens (ex: chat-api-prod)
+	Audience          string        // aud exigido na validação
+	Leeway            time.Duration // Tolerância de relógio em exp/nbf entre instâncias
+	Clock             clock.Clock   // Hora da validação (nil = relógio do sistema; testes injetam clock.Manual)
+}
+
+// Now hora usada para conferir exp/nbf dos tokens
+func (c *JWTConfig) Now() time.Time {
+	if c.Clock == nil {
+		return clock.System.Now()
+	}
+	return c.Clock.Now()
 }
 
 type WorkerConfig struct {
-	PoolSize       int
-	BufferSize     int
-	ProcessTimeout time.Duration
+	PoolSize       int            // Workers por tópico consumido (ordem preservada por conversa)
+	BufferSize     int            // Eventos enfileirados por worker
+	ProcessTimeout time.Duration  // Prazo de cada evento
+	TopicPoolSize  map[string]int // Sobrescreve PoolSize por tópico
+	BulkTimeout    time.Duration  // Prazo de cada job bulk (substitui ProcessTimeout)
+
+	PartitionMonthsAhead int           // Partições mensais criadas antecipadamente
+	PartitionInterval    time.Duration // Frequência da verificação de partições
+
+	AttachmentScanInterval time.Duration // Frequência da busca de anexos a verificar
+	AttachmentScanBatch    int           // Anexos reservados por rodada
+
+	AttachmentGCInterval time.Duration // Frequência da coleta de anexos órfãos
+	AttachmentGCBatch    int           // Anexos removidos por consulta
+	AttachmentGCDryRun   bool          // Apenas loga/conta o que seria removido
+
+	AccountPurgeInterval time.Duration // Frequência da purga de contas após a carência
+	AccountPurgeBatch    int           // Contas purgadas por consulta
+
+	FFmpegPath           string        // Binário do ffmpeg (vazio = sem transcodificação de vídeo)
+	TranscodeInterval    time.Duration // Frequência da busca de jobs de transcodificação
+	TranscodeBatch       int           // Jobs reservados por rodada
+	TranscodeTimeout     time.Duration // Tempo máximo por vídeo
+	TranscodeMaxAttempts int           // Tentativas antes de marcar o job como failed
+
+	AnnouncementInterval time.Duration // Frequência da busca de anúncios agendados vencidos
+	AnnouncementBatch    int           // Destinatários por job bulk de anúncio
+}
+
+type ReporterConfig struct {
+	Backend     string // log, sentry ou none
+	DSN         string
+	Environment string
+	Release     string
+	SampleRate  float64 // 0.0 a 1.0 (panics sempre são reportados)
+}
+
+type AdminConfig struct {
+	Port  string
+	Token string // Vazio = servidor admin desabilitado
+
+	TailConsumerGroup string // Consumer group do tail de eventos (único por instância)
+	TailRedactContent bool   // Conteúdo das mensagens omitido no tail
+}
+
+type LogConfig struct {
+	Level            string // debug, info, warn, error
+	Format           string // json ou text
+	SampleInitial    int    // Logs de debug iguais liberados por janela (0 = sem amostragem)
+	SampleThereafter int    // Depois disso, 1 a cada N
+	SampleTick       time.Duration
+}
+
+type UserConfig struct {
+	DeletionGracePeriod time.Duration // Janela para restaurar usuário removido
+	DeletedMessagesMode string        // hide (oculta mensagens) ou anonymize ("usuário removido")
+
+	UsernameChangeCooldown    time.Duration // Intervalo mínimo entre trocas de username
+	UsernameReservationPeriod time.Duration // Tempo em que o username antigo fica reservado/redireciona
+
+	// Política de username (cadastro e troca); unicidade sem diferenciar maiúsculas
+	UsernamePattern      string   // Regex dos caracteres aceitos (fora de [A-Za-z0-9_.] não vira menção)
+	UsernameLeadingDigit bool     // Aceita username começando com número
+	ReservedUsernames    []string // Nomes recusados (sem diferenciar maiúsculas, _ e .)
+
+	InvitationExpiration time.Duration // Validade dos convites
+
+	// Confirmação de email no cadastro: off (só envia o link), messaging
+	// (envio de mensagens exige email confirmado) ou login (login também exige)
+	EmailVerificationRequired       string
+	EmailVerificationTTL            time.Duration // Validade do link de confirmação
+	EmailVerificationResendInterval time.Duration // Intervalo mínimo entre reenvios do link
+
+	ShareLinkTTL    time.Duration // Validade padrão dos links de leitura de conversa
+	ShareLinkMaxTTL time.Duration // Validade máxima que o usuário pode pedir
+
+	DisposableDomainsURL      string        // Lista remota de domínios descartáveis (vazio = só a embutida)
+	DisposableRefreshInterval time.Duration // Intervalo de atualização da lista remota
+
+	// MessagingPolicy everyone (cada destinatário decide na privacidade) ou
+	// friends: DM só entre amigos aceitos, para todos os usuários
+	MessagingPolicy string
+
+	AutoReplyConsumerGroup string // Consumer group das respostas automáticas (separado dos workers)
+
+	// Conversa de boas-vindas com o usuário system no cadastro
+	WelcomeEnabled      bool
+	WelcomeMessagesFile string // Modelos text/template separados por "---" (vazio = textos padrão)
+}
+
+type MailConfig struct {
+	SMTPHost     string // Vazio = emails apenas logados
+	SMTPPort     string
+	SMTPUser     string
+	SMTPPassword string
+	From         string
+	BaseURL      string // URL pública usada em links (convites, verificação...)
+}
+
+type SecurityConfig struct {
+	GeoIPURL     string        // API de geolocalização com {ip} (vazio = desabilitada)
+	GeoIPTimeout time.Duration // Timeout da consulta (não pode atrasar o login)
+
+	RiskStepUpThreshold       int           // Score (0-100) que exige confirmação por email (0 = desabilitado)
+	RiskBadIPs                []string      // IPs/CIDRs de má reputação
+	RiskWindow                time.Duration // Janela de velocidade (falhas, troca de país)
+	LoginChallengeTTL         time.Duration // Validade do código de confirmação
+	LoginChallengeMaxAttempts int           // Tentativas de código por desafio
+
+	PasswordResetTTL            time.Duration // Validade do link de redefinição de senha
+	PasswordResetResendInterval time.Duration // Intervalo mínimo entre pedidos de redefinição
+
+	// Sincronização de contatos: chave HMAC dos hashes guardados (padrão:
+	// JWT_ACCESS_SECRET; trocar exige esvaziar contact_hashes, recriados no
+	// boot), hashes por chamada e por usuário no dia (contra enumeração)
+	ContactHashPepper      string
+	ContactSyncMaxHashes   int
+	ContactSyncDailyHashes int
+
+	ClamAVAddr    string        // Endereço do clamd, ex: tcp://clamav:3310 (vazio = sem antivírus)
+	ClamAVTimeout time.Duration // Timeout por arquivo verificado
+}
+
+type StorageConfig struct {
+	Dir string // Diretório dos anexos
+
+	MaxAttachmentBytes int64    // Limite geral por anexo
+	MaxImageBytes      int64    // Limite de image/* (0 = limite geral)
+	MaxVideoBytes      int64    // Limite de video/* (0 = limite geral)
+	MaxDocumentBytes   int64    // Limite dos demais tipos (0 = limite geral)
+	AllowedMIMETypes   []string // Tipos aceitos; aceita curinga (image/*)
+
+	UploadTTL           time.Duration // Prazo para finalizar e enviar um anexo
+	UploadSessionTTL    time.Duration // Inatividade tolerada em upload retomável (tus)
+	AttachmentRetention time.Duration // Anexos mais antigos são removidos (0 = para sempre)
+
+	DownloadBaseURL       string        // Host das URLs de download (CDN); vazio = APP_BASE_URL
+	DownloadURLTTL        time.Duration // Validade mínima das URLs assinadas
+	DownloadSigningSecret string        // Chave HMAC das URLs (padrão: JWT_ACCESS_SECRET)
+
+	NSFWPolicy            string        // off, flag (apenas sinaliza) ou blur (prévia desfocada até confirmação)
+	NSFWClassifierURL     string        // Serviço de classificação de imagens (vazio = desabilitado)
+	NSFWClassifierTimeout time.Duration // Timeout por imagem
+	NSFWThreshold         float64       // Score a partir do qual a imagem é sinalizada
+}
+
+type SearchConfig struct {
+	ElasticsearchURL string // Elasticsearch/OpenSearch (vazio = busca só no Postgres)
+	Username         string // Basic auth (opcional)
+	Password         string
+	IndexPrefix      string        // Índices mensais <prefixo>-AAAA.MM
+	Timeout          time.Duration // Timeout por requisição ao índice
+	ConsumerGroup    string        // Consumer group do indexador (separado dos workers)
+	Retention        time.Duration // Índices mais antigos são apagados (0 = para sempre)
+	RetentionCheck   time.Duration // Frequência da limpeza de índices
+}
+
+// HistoryConfig cache em memória das mensagens recentes de cada conversa
+// (histórico "abrir conversa"); invalidado pelos eventos de mensagem
+type HistoryConfig struct {
+	CacheConversations int           // Conversas no LRU (0 = cache desligado)
+	CacheMessages      int           // Mensagens recentes guardadas por conversa
+	CacheTTL           time.Duration // Idade máxima da entrada (status e anexos mudam sem evento)
+	ConsumerGroup      string        // Consumer group da invalidação (único por instância)
+
+	// ClientClockSkew diferença entre o horário enviado pelo cliente e o do
+	// servidor a partir da qual o relógio do dispositivo é considerado errado
+	ClientClockSkew time.Duration
+}
+
+// QuotaConfig cotas do plano free (0 = sem limite); o dia vira à meia-noite UTC
+type QuotaConfig struct {
+	DailyMessages    int   // Mensagens por dia enviadas pelo usuário
+	BotDailyMessages int   // Mensagens por dia enviadas com chave de API (bots)
+	StorageBytes     int64 // Soma dos anexos enviados pelo usuário
+}
+
+// PlanConfig planos (free, pro, workspace; limites em service.PlanEntitlements)
+type PlanConfig struct {
+	Default string // Plano de quem não tem assinatura (self-hosted: workspace)
+}
+
+// BillingConfig webhooks do provedor de cobrança (POST /billing/webhook)
+type BillingConfig struct {
+	Provider             string   // "" (desligado) ou stripe
+	StripeWebhookSecret  string   // Segredo do endpoint (whsec_...)
+	StripePricePro       []string // IDs de preço do plano pro (mensal, anual...)
+	StripePriceWorkspace []string // IDs de preço do plano workspace
+}
+
+// MaintenanceConfig modo de manutenção no boot (alterável em runtime pelo admin)
+type MaintenanceConfig struct {
+	Mode       string        // off, read_only (escritas 503) ou full (toda a API 503)
+	RetryAfter time.Duration // Retry-After das respostas 503 e do evento WebSocket
+	Message    string        // Texto exibido ao usuário (opcional)
+}
+
+// SLOConfig objetivos de serviço avaliados em GET /admin/slo
+type SLOConfig struct {
+	DeliveryTarget  float64       // Proporção das entregas em tempo real dentro do limite (ex.: 0.99)
+	DeliveryLatency time.Duration // Envio -> frame no WebSocket do destinatário online
+}
+
+// SupportConfig prazos (SLA) dos chamados de suporte, contados da abertura
+type SupportConfig struct {
+	FirstResponseSLA time.Duration // Até a primeira resposta de um agente
+	ResolutionSLA    time.Duration // Até o fechamento
+
+	// Acesso de suporte (impersonação somente leitura): o usuário autoriza por
+	// até AccessMaxTTL; cada token emitido pelo admin vale até ImpersonationMaxTTL
+	AccessMaxTTL        time.Duration
+	ImpersonationMaxTTL time.Duration
+}
+
+// ArchiveConfig exportação de compliance: cada mensagem finalizada é
+// espelhada no destino (consumer group próprio); backfill via cmd/rebuild
+type ArchiveConfig struct {
+	Sink          string        // none, webhook ou dir
+	WebhookURL    string        // Endpoint do arquivador (sink webhook)
+	WebhookSecret string        // Chave HMAC da assinatura dos envios
+	Dir           string        // Diretório/volume WORM (sink dir)
+	Timeout       time.Duration // Timeout por envio
+	ConsumerGroup string        // Consumer group do arquivador (separado dos workers)
+}
+
+// Prices ID de preço -> plano
+func (c BillingConfig) Prices() map[string]string {
+	prices := make(map[string]string)
+	for _, id := range c.StripePricePro {
+		prices[id] = "pro"
+	}
+	for _, id := range c.StripePriceWorkspace {
+		prices[id] = "workspace"
+	}
+	return prices
 }
 
+// defaultReservedUsernames papéis da plataforma e usuários do sistema
+// (system: anúncios; support: caixa de suporte)
+const defaultReservedUsernames = "admin,administrator,root,superuser,system,support,help,helpdesk," +
+	"moderator,mod,staff,official,security,abuse,postmaster,noreply,bot,api,www"
+
 // Load carrega as configurações do .env
 func Load() (*Config, error) {
 	_ = godotenv.Load()
@@ -68,12 +473,13 @@ func Load() (*Config, error) {
 		"DB_USER",
 		"DB_PASSWORD",
 		"DB_NAME",
-		"KAFKA_BROKERS",
-		"KAFKA_TOPIC",
-		"KAFKA_CONSUMER_GROUP",
 		"JWT_ACCESS_SECRET",
 		"JWT_REFRESH_SECRET",
 	}
+	// Kafka só é obrigatório quando é o barramento de eventos
+	if getEnv("EVENT_BUS", "kafka") == "kafka" {
+		requiredEnvVars = append(requiredEnvVars, "KAFKA_BROKERS", "KAFKA_TOPIC", "KAFKA_CONSUMER_GROUP")
+	}
 
 	for _, envVar := range requiredEnvVars {
 		if os.Getenv(envVar) == "" {
@@ -87,6 +493,45 @@ func Load() (*Config, error) {
 			ReadTimeout:     parseDuration(getEnv("SERVER_READ_TIMEOUT", "15s")),
 			WriteTimeout:    parseDuration(getEnv("SERVER_WRITE_TIMEOUT", "15s")),
 			ShutdownTimeout: parseDuration(getEnv("SHUTDOWN_TIMEOUT", "30s")),
+
+			WSAllowedOrigins: parseList(os.Getenv("WS_ALLOWED_ORIGINS")),
+
+			DrainDelay:     parseDuration(getEnv("SERVER_DRAIN_DELAY", "5s")),
+			WSMigrateGrace: parseDuration(getEnv("WS_MIGRATE_GRACE", "10s")),
+
+			RequestTimeout:   parseDuration(getEnv("SERVER_REQUEST_TIMEOUT", "10s")),
+			TransferTimeout:  parseDuration(getEnv("SERVER_TRANSFER_TIMEOUT", "10m")),
+			AuthBodyBytes:    parseInt64(getEnv("SERVER_AUTH_BODY_BYTES", "16384")),
+			DefaultBodyBytes: parseInt64(getEnv("SERVER_BODY_BYTES", "1048576")),
+			SlowRequest:      parseDuration(getEnv("SERVER_SLOW_REQUEST", "1s")),
+
+			TLSCertFile:     os.Getenv("TLS_CERT_FILE"),
+			TLSKeyFile:      os.Getenv("TLS_KEY_FILE"),
+			TLSAutocert:     parseList(os.Getenv("TLS_AUTOCERT_DOMAINS")),
+			TLSAutocertDir:  getEnv("TLS_AUTOCERT_CACHE_DIR", "data/certs"),
+			TLSAutocertMail: os.Getenv("TLS_AUTOCERT_EMAIL"),
+			TLSMinVersion:   getEnv("TLS_MIN_VERSION", "1.2"),
+			TLSHTTPPort:     os.Getenv("TLS_HTTP_PORT"),
+			HTTP2:           getEnv("SERVER_HTTP2", "true") == "true",
+
+			TrustedProxies: parseList(os.Getenv("SERVER_TRUSTED_PROXIES")),
+			RealIPHeader:   getEnv("SERVER_REAL_IP_HEADER", "X-Forwarded-For"),
+
+			Compression:         getEnv("SERVER_COMPRESSION", "true") == "true",
+			CompressionMinBytes: parseInt(getEnv("SERVER_COMPRESSION_MIN_BYTES", "1024")),
+			CompressionTypes:    parseList(getEnv("SERVER_COMPRESSION_TYPES", "application/json,text/plain,text/csv,application/x-ndjson")),
+			CompressionExclude:  parseList(getEnv("SERVER_COMPRESSION_EXCLUDE", "/attachments/,/files/,/uploads/")),
+
+			BatchMaxRequests: parseInt(getEnv("SERVER_BATCH_MAX_REQUESTS", "25")),
+			BatchConcurrency: parseInt(getEnv("SERVER_BATCH_CONCURRENCY", "4")),
+		},
+		Startup: StartupConfig{
+			DatabaseMode:  getEnv("STARTUP_DB_MODE", StartupFailFast),
+			EventBusMode:  getEnv("STARTUP_EVENT_BUS_MODE", StartupFailFast),
+			WaitTimeout:   parseDuration(getEnv("STARTUP_WAIT_TIMEOUT", "60s")),
+			RetryInterval: parseDuration(getEnv("STARTUP_RETRY_INTERVAL", "2s")),
+
+			SelfCheckStrict: getEnv("STARTUP_SELFCHECK_STRICT", "true") == "true",
 		},
 		Database: DatabaseConfig{
 			Host:            os.Getenv("DB_HOST"),
@@ -98,23 +543,249 @@ func Load() (*Config, error) {
 			MaxOpenConns:    parseInt(getEnv("DB_MAX_OPEN_CONNS", "25")),
 			MaxIdleConns:    parseInt(getEnv("DB_MAX_IDLE_CONNS", "5")),
 			ConnMaxLifetime: parseDuration(getEnv("DB_CONN_MAX_LIFETIME", "5m")),
+			ReplicaDSNs:     parseList(os.Getenv("DB_REPLICA_DSNS")),
+			MessageIDMode:   getEnv("MESSAGE_ID_MODE", "uuidv7"),
+		},
+		EventBus: EventBusConfig{
+			Backend:      getEnv("EVENT_BUS", "kafka"),
+			PollInterval: parseDuration(getEnv("EVENT_BUS_POLL_INTERVAL", "5s")),
+			Retention:    parseDuration(getEnv("EVENT_BUS_RETENTION", "168h")),
+			BufferSize:   parseInt(getEnv("EVENT_BUS_BUFFER_SIZE", "1000")),
+			Delivery:     getEnv("EVENT_BUS_DELIVERY", "at-least-once"),
+			RetryDelays:  parseDurationList(getEnv("EVENT_BUS_RETRY_DELAYS", "5s,1m,10m")),
+
+			PoisonThreshold: parseInt(getEnv("EVENT_BUS_POISON_THRESHOLD", "2")),
 		},
 		Kafka: KafkaConfig{
 			Brokers:       strings.Split(os.Getenv("KAFKA_BROKERS"), ","),
-			Topic:         os.Getenv("KAFKA_TOPIC"),
-			ConsumerGroup: os.Getenv("KAFKA_CONSUMER_GROUP"),
+			Topic:         getEnv("KAFKA_TOPIC", "chat-messages"),
+			ConsumerGroup: getEnv("KAFKA_CONSUMER_GROUP", "chat-workers"),
 			RetryMax:      parseInt(getEnv("KAFKA_RETRY_MAX", "3")),
+			Linger:        parseDuration(getEnv("KAFKA_LINGER", "5ms")),
+			BatchBytes:    parseInt(getEnv("KAFKA_BATCH_BYTES", "65536")),
+			BatchMaxMsgs:  parseInt(getEnv("KAFKA_BATCH_MAX_MESSAGES", "0")),
+			MaxInFlight:   parseInt(getEnv("KAFKA_MAX_IN_FLIGHT", "5")),
+
+			BulkTopic:         getEnv("KAFKA_BULK_TOPIC", "chat-bulk"),
+			BulkConsumerGroup: getEnv("KAFKA_BULK_CONSUMER_GROUP", "chat-bulk-workers"),
+
+			TransactionalID: os.Getenv("KAFKA_TRANSACTIONAL_ID"),
+
+			BreakerThreshold: parseInt(getEnv("KAFKA_BREAKER_THRESHOLD", "5")),
+			BreakerCooldown:  parseDuration(getEnv("KAFKA_BREAKER_COOLDOWN", "10s")),
+			SpillDir:         getEnv("KAFKA_SPILL_DIR", "data/kafka-spill"),
+			SpillMaxBytes:    parseInt64(getEnv("KAFKA_SPILL_MAX_BYTES", "268435456")),
+		},
+		NATS: NATSConfig{
+			URL:        getEnv("NATS_URL", "nats://localhost:4222"),
+			MaxAge:     parseDuration(getEnv("NATS_MAX_AGE", "168h")),
+			AckWait:    parseDuration(getEnv("NATS_ACK_WAIT", "30s")),
+			MaxDeliver: parseInt(getEnv("NATS_MAX_DELIVER", "5")),
+		},
+		Cluster: ClusterConfig{
+			Routing:           getEnv("CLUSTER_ROUTING", "off"),
+			RedisURL:          os.Getenv("REDIS_URL"),
+			InstanceID:        getEnv("CLUSTER_INSTANCE_ID", hostname()),
+			AdvertiseURL:      os.Getenv("CLUSTER_ADVERTISE_URL"),
+			HeartbeatInterval: parseDuration(getEnv("CLUSTER_HEARTBEAT_INTERVAL", "5s")),
+			InstanceTTL:       parseDuration(getEnv("CLUSTER_INSTANCE_TTL", "15s")),
+			VirtualNodes:      parseInt(getEnv("CLUSTER_VIRTUAL_NODES", "128")),
+
+			ConnectionRegistry: getEnv("CLUSTER_CONNECTION_REGISTRY", "false") == "true",
 		},
 		JWT: JWTConfig{
 			AccessSecret:      os.Getenv("JWT_ACCESS_SECRET"),
 			RefreshSecret:     os.Getenv("JWT_REFRESH_SECRET"),
-			AccessExpiration:  1 * time.Hour,
-			RefreshExpiration: 7 * 24 * time.Hour,
+			AccessExpiration:  parseDuration(getEnv("JWT_ACCESS_TTL", "1h")),
+			RefreshExpiration: parseDuration(getEnv("JWT_REFRESH_TTL", "168h")),
+			Issuer:            getEnv("JWT_ISSUER", "chat-kafka-go"),
+			Audience:          getEnv("JWT_AUDIENCE", "chat-kafka-go-api"),
+			Leeway:            parseDuration(getEnv("JWT_LEEWAY", "30s")),
 		},
 		Worker: WorkerConfig{
 			PoolSize:       parseInt(getEnv("WORKER_POOL_SIZE", "10")),
 			BufferSize:     parseInt(getEnv("WORKER_BUFFER_SIZE", "100")),
 			ProcessTimeout: parseDuration(getEnv("WORKER_TIMEOUT", "30s")),
+			TopicPoolSize:  parseIntMap(os.Getenv("WORKER_TOPIC_POOL_SIZE")),
+			BulkTimeout:    parseDuration(getEnv("WORKER_BULK_TIMEOUT", "10m")),
+
+			PartitionMonthsAhead: parseInt(getEnv("PARTITION_MONTHS_AHEAD", "2")),
+			PartitionInterval:    parseDuration(getEnv("PARTITION_CHECK_INTERVAL", "24h")),
+
+			AttachmentScanInterval: parseDuration(getEnv("ATTACHMENT_SCAN_INTERVAL", "5s")),
+			AttachmentScanBatch:    parseInt(getEnv("ATTACHMENT_SCAN_BATCH", "10")),
+
+			AttachmentGCInterval: parseDuration(getEnv("ATTACHMENT_GC_INTERVAL", "1h")),
+			AttachmentGCBatch:    parseInt(getEnv("ATTACHMENT_GC_BATCH", "100")),
+			AttachmentGCDryRun:   getEnv("ATTACHMENT_GC_DRY_RUN", "false") == "true",
+
+			AccountPurgeInterval: parseDuration(getEnv("ACCOUNT_PURGE_INTERVAL", "1h")),
+			AccountPurgeBatch:    parseInt(getEnv("ACCOUNT_PURGE_BATCH", "100")),
+
+			FFmpegPath:           os.Getenv("FFMPEG_PATH"),
+			TranscodeInterval:    parseDuration(getEnv("TRANSCODE_INTERVAL", "10s")),
+			TranscodeBatch:       parseInt(getEnv("TRANSCODE_BATCH", "2")),
+			TranscodeTimeout:     parseDuration(getEnv("TRANSCODE_TIMEOUT", "30m")),
+			TranscodeMaxAttempts: parseInt(getEnv("TRANSCODE_MAX_ATTEMPTS", "3")),
+
+			AnnouncementInterval: parseDuration(getEnv("ANNOUNCEMENT_INTERVAL", "30s")),
+			AnnouncementBatch:    parseInt(getEnv("ANNOUNCEMENT_BATCH", "500")),
+		},
+		Reporter: ReporterConfig{
+			Backend:     getEnv("ERROR_REPORTER", "log"),
+			DSN:         os.Getenv("SENTRY_DSN"),
+			Environment: getEnv("APP_ENV", "development"),
+			Release:     getEnv("APP_RELEASE", "dev"),
+			SampleRate:  parseFloat(getEnv("ERROR_SAMPLE_RATE", "1.0")),
+		},
+		Admin: AdminConfig{
+			Port:  getEnv("ADMIN_PORT", "6060"),
+			Token: os.Getenv("ADMIN_TOKEN"),
+
+			TailConsumerGroup: getEnv("ADMIN_TAIL_CONSUMER_GROUP", "chat-admin-tail-"+hostname()),
+			TailRedactContent: getEnv("ADMIN_TAIL_REDACT_CONTENT", "true") == "true",
+		},
+		Log: LogConfig{
+			Level:            getEnv("LOG_LEVEL", "info"),
+			Format:           getEnv("LOG_FORMAT", "text"),
+			SampleInitial:    parseInt(getEnv("LOG_SAMPLE_INITIAL", "100")),
+			SampleThereafter: parseInt(getEnv("LOG_SAMPLE_THEREAFTER", "100")),
+			SampleTick:       parseDuration(getEnv("LOG_SAMPLE_TICK", "1s")),
+		},
+		User: UserConfig{
+			DeletionGracePeriod: parseDuration(getEnv("USER_DELETION_GRACE_PERIOD", "720h")),
+			DeletedMessagesMode: getEnv("DELETED_USER_MESSAGES", "anonymize"),
+
+			UsernameChangeCooldown:    parseDuration(getEnv("USERNAME_CHANGE_COOLDOWN", "720h")),
+			UsernameReservationPeriod: parseDuration(getEnv("USERNAME_RESERVATION_PERIOD", "2160h")),
+			UsernamePattern:           getEnv("USERNAME_PATTERN", validate.DefaultUsernamePattern),
+			UsernameLeadingDigit:      getEnv("USERNAME_LEADING_DIGIT", "false") == "true",
+			ReservedUsernames:         parseList(getEnv("USERNAME_RESERVED", defaultReservedUsernames)),
+			InvitationExpiration:      parseDuration(getEnv("INVITATION_EXPIRATION", "168h")),
+
+			EmailVerificationRequired:       getEnv("EMAIL_VERIFICATION_REQUIRED", "off"),
+			EmailVerificationTTL:            parseDuration(getEnv("EMAIL_VERIFICATION_TTL", "48h")),
+			EmailVerificationResendInterval: parseDuration(getEnv("EMAIL_VERIFICATION_RESEND_INTERVAL", "1m")),
+
+			ShareLinkTTL:    parseDuration(getEnv("SHARE_LINK_TTL", "168h")),
+			ShareLinkMaxTTL: parseDuration(getEnv("SHARE_LINK_MAX_TTL", "720h")),
+
+			DisposableDomainsURL:      os.Getenv("DISPOSABLE_DOMAINS_URL"),
+			DisposableRefreshInterval: parseDuration(getEnv("DISPOSABLE_REFRESH_INTERVAL", "24h")),
+
+			MessagingPolicy: getEnv("MESSAGING_POLICY", "everyone"),
+
+			AutoReplyConsumerGroup: getEnv("AUTO_REPLY_CONSUMER_GROUP", "chat-auto-reply"),
+
+			WelcomeEnabled:      getEnv("WELCOME_ENABLED", "true") == "true",
+			WelcomeMessagesFile: os.Getenv("WELCOME_MESSAGES_FILE"),
+		},
+		Mail: MailConfig{
+			SMTPHost:     os.Getenv("SMTP_HOST"),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUser:     os.Getenv("SMTP_USER"),
+			SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+			From:         getEnv("MAIL_FROM", "no-reply@localhost"),
+			BaseURL:      getEnv("APP_BASE_URL", "http://localhost:8080"),
+		},
+		Security: SecurityConfig{
+			GeoIPURL:     os.Getenv("GEOIP_URL"),
+			GeoIPTimeout: parseDuration(getEnv("GEOIP_TIMEOUT", "2s")),
+
+			RiskStepUpThreshold:       parseInt(getEnv("RISK_STEP_UP_THRESHOLD", "60")),
+			RiskBadIPs:                parseList(os.Getenv("RISK_BAD_IPS")),
+			RiskWindow:                parseDuration(getEnv("RISK_WINDOW", "15m")),
+			LoginChallengeTTL:         parseDuration(getEnv("LOGIN_CHALLENGE_TTL", "10m")),
+			LoginChallengeMaxAttempts: parseInt(getEnv("LOGIN_CHALLENGE_MAX_ATTEMPTS", "5")),
+
+			PasswordResetTTL:            parseDuration(getEnv("PASSWORD_RESET_TTL", "1h")),
+			PasswordResetResendInterval: parseDuration(getEnv("PASSWORD_RESET_RESEND_INTERVAL", "1m")),
+
+			ContactHashPepper:      getEnv("CONTACT_HASH_PEPPER", os.Getenv("JWT_ACCESS_SECRET")),
+			ContactSyncMaxHashes:   parseInt(getEnv("CONTACT_SYNC_MAX_HASHES", "250")),
+			ContactSyncDailyHashes: parseInt(getEnv("CONTACT_SYNC_DAILY_HASHES", "2000")),
+
+			ClamAVAddr:    os.Getenv("CLAMAV_ADDR"),
+			ClamAVTimeout: parseDuration(getEnv("CLAMAV_TIMEOUT", "60s")),
+		},
+		Storage: StorageConfig{
+			Dir: getEnv("STORAGE_DIR", "data/attachments"),
+
+			MaxAttachmentBytes: parseInt64(getEnv("ATTACHMENT_MAX_BYTES", "26214400")),
+			MaxImageBytes:      parseInt64(getEnv("ATTACHMENT_MAX_IMAGE_BYTES", "10485760")),
+			MaxVideoBytes:      parseInt64(getEnv("ATTACHMENT_MAX_VIDEO_BYTES", "0")),
+			MaxDocumentBytes:   parseInt64(getEnv("ATTACHMENT_MAX_DOCUMENT_BYTES", "0")),
+			AllowedMIMETypes: parseList(getEnv("ATTACHMENT_ALLOWED_TYPES",
+				"image/jpeg,image/png,image/gif,image/webp,video/mp4,video/quicktime,video/webm,application/pdf,text/plain")),
+
+			UploadTTL:           parseDuration(getEnv("ATTACHMENT_UPLOAD_TTL", "24h")),
+			UploadSessionTTL:    parseDuration(getEnv("UPLOAD_SESSION_TTL", "24h")),
+			AttachmentRetention: parseDuration(getEnv("ATTACHMENT_RETENTION", "0")),
+
+			DownloadBaseURL:       getEnv("DOWNLOAD_BASE_URL", getEnv("APP_BASE_URL", "http://localhost:8080")),
+			DownloadURLTTL:        parseDuration(getEnv("DOWNLOAD_URL_TTL", "5m")),
+			DownloadSigningSecret: getEnv("DOWNLOAD_SIGNING_SECRET", os.Getenv("JWT_ACCESS_SECRET")),
+
+			NSFWPolicy:            getEnv("NSFW_POLICY", "off"),
+			NSFWClassifierURL:     os.Getenv("NSFW_CLASSIFIER_URL"),
+			NSFWClassifierTimeout: parseDuration(getEnv("NSFW_CLASSIFIER_TIMEOUT", "10s")),
+			NSFWThreshold:         parseFloat(getEnv("NSFW_THRESHOLD", "0.8")),
+		},
+		Search: SearchConfig{
+			ElasticsearchURL: os.Getenv("SEARCH_ES_URL"),
+			Username:         os.Getenv("SEARCH_ES_USERNAME"),
+			Password:         os.Getenv("SEARCH_ES_PASSWORD"),
+			IndexPrefix:      getEnv("SEARCH_INDEX_PREFIX", "chat-messages"),
+			Timeout:          parseDuration(getEnv("SEARCH_TIMEOUT", "5s")),
+			ConsumerGroup:    getEnv("SEARCH_CONSUMER_GROUP", "chat-search-indexer"),
+			Retention:        parseDuration(getEnv("SEARCH_RETENTION", "0")),
+			RetentionCheck:   parseDuration(getEnv("SEARCH_RETENTION_CHECK_INTERVAL", "24h")),
+		},
+		History: HistoryConfig{
+			CacheConversations: parseInt(getEnv("HISTORY_CACHE_CONVERSATIONS", "10000")),
+			CacheMessages:      parseInt(getEnv("HISTORY_CACHE_MESSAGES", "50")),
+			CacheTTL:           parseDuration(getEnv("HISTORY_CACHE_TTL", "5m")),
+			ConsumerGroup:      getEnv("HISTORY_CACHE_CONSUMER_GROUP", "chat-history-cache-"+hostname()),
+
+			ClientClockSkew: parseDuration(getEnv("CLIENT_CLOCK_SKEW_THRESHOLD", "2m")),
+		},
+		Quota: QuotaConfig{
+			DailyMessages:    parseInt(getEnv("QUOTA_DAILY_MESSAGES", "5000")),
+			BotDailyMessages: parseInt(getEnv("QUOTA_BOT_DAILY_MESSAGES", "1000")),
+			StorageBytes:     parseInt64(getEnv("QUOTA_STORAGE_BYTES", "1073741824")),
+		},
+		Plan: PlanConfig{
+			Default: getEnv("PLAN_DEFAULT", "free"),
+		},
+		Billing: BillingConfig{
+			Provider:             os.Getenv("BILLING_PROVIDER"),
+			StripeWebhookSecret:  os.Getenv("STRIPE_WEBHOOK_SECRET"),
+			StripePricePro:       parseList(os.Getenv("STRIPE_PRICE_PRO")),
+			StripePriceWorkspace: parseList(os.Getenv("STRIPE_PRICE_WORKSPACE")),
+		},
+		Maintenance: MaintenanceConfig{
+			Mode:       getEnv("MAINTENANCE_MODE", "off"),
+			RetryAfter: parseDuration(getEnv("MAINTENANCE_RETRY_AFTER", "5m")),
+			Message:    os.Getenv("MAINTENANCE_MESSAGE"),
+		},
+		SLO: SLOConfig{
+			DeliveryTarget:  parseFloat(getEnv("SLO_DELIVERY_TARGET", "0.99")),
+			DeliveryLatency: parseDuration(getEnv("SLO_DELIVERY_LATENCY", "500ms")),
+		},
+		Support: SupportConfig{
+			FirstResponseSLA: parseDuration(getEnv("SUPPORT_FIRST_RESPONSE_SLA", "1h")),
+			ResolutionSLA:    parseDuration(getEnv("SUPPORT_RESOLUTION_SLA", "24h")),
+
+			AccessMaxTTL:        parseDuration(getEnv("SUPPORT_ACCESS_MAX_TTL", "168h")),
+			ImpersonationMaxTTL: parseDuration(getEnv("SUPPORT_IMPERSONATION_MAX_TTL", "30m")),
+		},
+		Archive: ArchiveConfig{
+			Sink:          getEnv("ARCHIVE_SINK", "none"),
+			WebhookURL:    os.Getenv("ARCHIVE_WEBHOOK_URL"),
+			WebhookSecret: os.Getenv("ARCHIVE_WEBHOOK_SECRET"),
+			Dir:           getEnv("ARCHIVE_DIR", "./archive"),
+			Timeout:       parseDuration(getEnv("ARCHIVE_TIMEOUT", "10s")),
+			ConsumerGroup: getEnv("ARCHIVE_CONSUMER_GROUP", "chat-compliance-archiver"),
 		},
 	}
 
@@ -133,9 +804,278 @@ func (c *Config) Validate() error {
 	if c.JWT.RefreshSecret == "" {
 		return fmt.Errorf("JWT_REFRESH_SECRET é obrigatório")
 	}
+	if c.JWT.AccessExpiration < time.Minute || c.JWT.AccessExpiration > 24*time.Hour {
+		return fmt.Errorf("JWT_ACCESS_TTL deve estar entre 1m e 24h")
+	}
+	if c.JWT.RefreshExpiration < time.Hour || c.JWT.RefreshExpiration > 90*24*time.Hour {
+		return fmt.Errorf("JWT_REFRESH_TTL deve estar entre 1h e 2160h (90 dias)")
+	}
+	if c.JWT.RefreshExpiration <= c.JWT.AccessExpiration {
+		return fmt.Errorf("JWT_REFRESH_TTL deve ser maior que JWT_ACCESS_TTL")
+	}
+	if c.JWT.Leeway < 0 || c.JWT.Leeway > 5*time.Minute {
+		return fmt.Errorf("JWT_LEEWAY deve estar entre 0 e 5m")
+	}
+	if c.User.DeletedMessagesMode != "hide" && c.User.DeletedMessagesMode != "anonymize" {
+		return fmt.Errorf("DELETED_USER_MESSAGES deve ser hide ou anonymize")
+	}
+	if c.History.ClientClockSkew <= 0 {
+		return fmt.Errorf("CLIENT_CLOCK_SKEW_THRESHOLD deve ser positivo")
+	}
+	if _, err := regexp.Compile(c.User.UsernamePattern); err != nil {
+		return fmt.Errorf("USERNAME_PATTERN inválido: %w", err)
+	}
+	if c.User.MessagingPolicy != "everyone" && c.User.MessagingPolicy != "friends" {
+		return fmt.Errorf("MESSAGING_POLICY deve ser everyone ou friends")
+	}
+	switch c.User.EmailVerificationRequired {
+	case "off", "messaging", "login":
+	default:
+		return fmt.Errorf("EMAIL_VERIFICATION_REQUIRED deve ser off, messaging ou login")
+	}
+	if c.User.EmailVerificationTTL <= 0 {
+		return fmt.Errorf("EMAIL_VERIFICATION_TTL deve ser positivo")
+	}
+	if c.Support.AccessMaxTTL <= 0 {
+		return fmt.Errorf("SUPPORT_ACCESS_MAX_TTL deve ser positivo")
+	}
+	if c.Support.ImpersonationMaxTTL <= 0 || c.Support.ImpersonationMaxTTL > 4*time.Hour {
+		return fmt.Errorf("SUPPORT_IMPERSONATION_MAX_TTL deve estar entre 1s e 4h")
+	}
+	if c.Security.PasswordResetTTL <= 0 || c.Security.PasswordResetTTL > 24*time.Hour {
+		return fmt.Errorf("PASSWORD_RESET_TTL deve estar entre 1s e 24h")
+	}
+	if c.Security.ContactSyncMaxHashes <= 0 || c.Security.ContactSyncDailyHashes < c.Security.ContactSyncMaxHashes {
+		return fmt.Errorf("CONTACT_SYNC_MAX_HASHES deve ser positivo e até CONTACT_SYNC_DAILY_HASHES")
+	}
+	if c.User.ShareLinkTTL <= 0 || c.User.ShareLinkMaxTTL < c.User.ShareLinkTTL {
+		return fmt.Errorf("SHARE_LINK_TTL deve ser positivo e até SHARE_LINK_MAX_TTL")
+	}
+	switch c.EventBus.Backend {
+	case "kafka", "nats", "postgres", "memory":
+	default:
+		return fmt.Errorf("EVENT_BUS deve ser kafka, nats, postgres ou memory")
+	}
+	if c.Kafka.BulkTopic == c.Kafka.Topic {
+		return fmt.Errorf("KAFKA_BULK_TOPIC deve ser diferente de KAFKA_TOPIC")
+	}
+	switch c.EventBus.Delivery {
+	case "at-least-once", "at-most-once":
+	case "exactly-once":
+		if c.EventBus.Backend != "kafka" {
+			return fmt.Errorf("EVENT_BUS_DELIVERY=exactly-once exige EVENT_BUS=kafka")
+		}
+		if c.Kafka.TransactionalID == "" {
+			return fmt.Errorf("KAFKA_TRANSACTIONAL_ID é obrigatório com EVENT_BUS_DELIVERY=exactly-once")
+		}
+	default:
+		return fmt.Errorf("EVENT_BUS_DELIVERY deve ser at-least-once, at-most-once ou exactly-once")
+	}
+	for env, mode := range map[string]string{
+		"STARTUP_DB_MODE":        c.Startup.DatabaseMode,
+		"STARTUP_EVENT_BUS_MODE": c.Startup.EventBusMode,
+	} {
+		switch mode {
+		case StartupFailFast, StartupWait, StartupDegraded:
+		default:
+			return fmt.Errorf("%s deve ser fail-fast, wait ou degraded", env)
+		}
+	}
+	if c.Startup.EventBusMode == StartupDegraded && c.EventBus.Backend != "kafka" {
+		return fmt.Errorf("STARTUP_EVENT_BUS_MODE=degraded exige EVENT_BUS=kafka")
+	}
+	if c.Startup.RetryInterval <= 0 {
+		return fmt.Errorf("STARTUP_RETRY_INTERVAL deve ser maior que zero")
+	}
+	switch c.Cluster.Routing {
+	case "off":
+	case "consistent-hash":
+		if c.Cluster.AdvertiseURL == "" {
+			return fmt.Errorf("CLUSTER_ROUTING=consistent-hash exige CLUSTER_ADVERTISE_URL")
+		}
+	default:
+		return fmt.Errorf("CLUSTER_ROUTING deve ser off ou consistent-hash")
+	}
+	if c.Cluster.Enabled() {
+		if c.Cluster.RedisURL == "" {
+			return fmt.Errorf("CLUSTER_ROUTING e CLUSTER_CONNECTION_REGISTRY exigem REDIS_URL")
+		}
+		if c.Cluster.InstanceID == "" {
+			return fmt.Errorf("CLUSTER_INSTANCE_ID é obrigatório com o cluster habilitado")
+		}
+		if c.Cluster.HeartbeatInterval <= 0 || c.Cluster.InstanceTTL <= c.Cluster.HeartbeatInterval {
+			return fmt.Errorf("CLUSTER_INSTANCE_TTL deve ser maior que CLUSTER_HEARTBEAT_INTERVAL")
+		}
+	}
+	switch c.Database.MessageIDMode {
+	case "uuid", "uuidv7", "ulid":
+	default:
+		return fmt.Errorf("MESSAGE_ID_MODE deve ser uuid, uuidv7 ou ulid")
+	}
+	if c.History.CacheConversations > 0 && (c.History.CacheMessages < 1 || c.History.CacheMessages > 100) {
+		return fmt.Errorf("HISTORY_CACHE_MESSAGES deve estar entre 1 e 100")
+	}
+	if c.Quota.DailyMessages < 0 || c.Quota.BotDailyMessages < 0 || c.Quota.StorageBytes < 0 {
+		return fmt.Errorf("QUOTA_* não podem ser negativas (0 = sem limite)")
+	}
+	switch c.Plan.Default {
+	case "free", "pro", "workspace":
+	default:
+		return fmt.Errorf("PLAN_DEFAULT deve ser free, pro ou workspace")
+	}
+	switch c.Billing.Provider {
+	case "":
+	case "stripe":
+		if c.Billing.StripeWebhookSecret == "" {
+			return fmt.Errorf("STRIPE_WEBHOOK_SECRET é obrigatório com BILLING_PROVIDER=stripe")
+		}
+	default:
+		return fmt.Errorf("BILLING_PROVIDER deve ser vazio ou stripe")
+	}
+	if c.Server.RequestTimeout < 0 || c.Server.TransferTimeout < 0 || c.Server.SlowRequest < 0 {
+		return fmt.Errorf("SERVER_REQUEST_TIMEOUT, SERVER_TRANSFER_TIMEOUT e SERVER_SLOW_REQUEST não podem ser negativos")
+	}
+	if c.Server.AuthBodyBytes < 0 || c.Server.DefaultBodyBytes < 0 {
+		return fmt.Errorf("SERVER_AUTH_BODY_BYTES e SERVER_BODY_BYTES não podem ser negativos (0 = sem limite)")
+	}
+	if (c.Server.TLSCertFile == "") != (c.Server.TLSKeyFile == "") {
+		return fmt.Errorf("TLS_CERT_FILE e TLS_KEY_FILE devem ser informados juntos")
+	}
+	if c.Server.TLSCertFile != "" && len(c.Server.TLSAutocert) > 0 {
+		return fmt.Errorf("use TLS_CERT_FILE ou TLS_AUTOCERT_DOMAINS, não ambos")
+	}
+	if c.Server.TLSMinVersion != "1.2" && c.Server.TLSMinVersion != "1.3" {
+		return fmt.Errorf("TLS_MIN_VERSION deve ser 1.2 ou 1.3")
+	}
+	if c.Server.CompressionMinBytes < 0 {
+		return fmt.Errorf("SERVER_COMPRESSION_MIN_BYTES não pode ser negativo")
+	}
+	if c.Server.BatchMaxRequests < 1 || c.Server.BatchConcurrency < 1 {
+		return fmt.Errorf("SERVER_BATCH_MAX_REQUESTS e SERVER_BATCH_CONCURRENCY devem ser positivos")
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if _, err := ParsePrefix(proxy); err != nil {
+			return fmt.Errorf("SERVER_TRUSTED_PROXIES: %w", err)
+		}
+	}
+	switch c.Maintenance.Mode {
+	case "off", "read_only", "full":
+	default:
+		return fmt.Errorf("MAINTENANCE_MODE deve ser off, read_only ou full")
+	}
+	if c.Maintenance.RetryAfter < time.Second {
+		return fmt.Errorf("MAINTENANCE_RETRY_AFTER deve ser de pelo menos 1s")
+	}
+	if c.SLO.DeliveryTarget <= 0 || c.SLO.DeliveryTarget >= 1 {
+		return fmt.Errorf("SLO_DELIVERY_TARGET deve estar entre 0 e 1 (exclusivo)")
+	}
+	if c.SLO.DeliveryLatency <= 0 {
+		return fmt.Errorf("SLO_DELIVERY_LATENCY deve ser maior que zero")
+	}
+	if c.Support.FirstResponseSLA <= 0 || c.Support.ResolutionSLA < c.Support.FirstResponseSLA {
+		return fmt.Errorf("SUPPORT_FIRST_RESPONSE_SLA deve ser positivo e até SUPPORT_RESOLUTION_SLA")
+	}
+	switch c.Archive.Sink {
+	case "none":
+	case "webhook":
+		if c.Archive.WebhookURL == "" || c.Archive.WebhookSecret == "" {
+			return fmt.Errorf("ARCHIVE_WEBHOOK_URL e ARCHIVE_WEBHOOK_SECRET são obrigatórios com ARCHIVE_SINK=webhook")
+		}
+	case "dir":
+		if c.Archive.Dir == "" {
+			return fmt.Errorf("ARCHIVE_DIR é obrigatório com ARCHIVE_SINK=dir")
+		}
+	default:
+		return fmt.Errorf("ARCHIVE_SINK deve ser none, webhook ou dir")
+	}
+	if c.Archive.Timeout <= 0 {
+		return fmt.Errorf("ARCHIVE_TIMEOUT deve ser positivo")
+	}
+	if c.Reporter.SampleRate < 0 || c.Reporter.SampleRate > 1 {
+		return fmt.Errorf("ERROR_SAMPLE_RATE deve estar entre 0 e 1")
+	}
+	switch c.Storage.NSFWPolicy {
+	case "off", "flag", "blur":
+	default:
+		return fmt.Errorf("NSFW_POLICY deve ser off, flag ou blur")
+	}
+	if c.Storage.MaxAttachmentBytes <= 0 {
+		return fmt.Errorf("ATTACHMENT_MAX_BYTES deve ser maior que zero")
+	}
+	if len(c.Storage.AllowedMIMETypes) == 0 {
+		return fmt.Errorf("ATTACHMENT_ALLOWED_TYPES não pode ser vazio")
+	}
+	if c.Storage.NSFWThreshold < 0 || c.Storage.NSFWThreshold > 1 {
+		return fmt.Errorf("NSFW_THRESHOLD deve estar entre 0 e 1")
+	}
 	return nil
 }
 
+// Warnings configurações aceitas por Validate mas arriscadas em produção
+// (self-check do boot e /admin/selfcheck)
+func (c *Config) Warnings() []string {
+	var warnings []string
+	if len(c.Server.WSAllowedOrigins) == 0 {
+		warnings = append(warnings, "WS_ALLOWED_ORIGINS vazio: handshake WebSocket aceito de qualquer origem")
+	}
+	if c.Admin.Token != "" && len(c.Admin.Token) < 16 {
+		warnings = append(warnings, "ADMIN_TOKEN com menos de 16 caracteres")
+	}
+	if c.EventBus.Backend == "memory" && c.Cluster.Enabled() {
+		warnings = append(warnings, "EVENT_BUS=memory com cluster habilitado: eventos não chegam às outras instâncias")
+	}
+	if c.EventBus.Delivery == "at-most-once" {
+		warnings = append(warnings, "EVENT_BUS_DELIVERY=at-most-once: eventos podem ser perdidos em falhas")
+	}
+	if c.EventBus.Backend == "kafka" && c.EventBus.Delivery != "exactly-once" && c.Kafka.SpillDir == "" {
+		warnings = append(warnings, "KAFKA_SPILL_DIR vazio: eventos não entregues com o Kafka fora são descartados")
+	}
+	if c.Storage.DownloadSigningSecret == c.JWT.AccessSecret {
+		warnings = append(warnings, "DOWNLOAD_SIGNING_SECRET igual a JWT_ACCESS_SECRET: trocar um invalida o outro")
+	}
+	if c.Security.ContactHashPepper == c.JWT.AccessSecret {
+		warnings = append(warnings, "CONTACT_HASH_PEPPER igual a JWT_ACCESS_SECRET: trocar o segredo exige recriar os hashes de contato")
+	}
+	if c.Mail.SMTPHost == "" {
+		warnings = append(warnings, "SMTP_HOST vazio: emails (convites, confirmações) apenas logados")
+		if c.User.EmailVerificationRequired != "off" {
+			warnings = append(warnings, "EMAIL_VERIFICATION_REQUIRED="+c.User.EmailVerificationRequired+" sem SMTP_HOST: novos usuários não recebem o link")
+		}
+	}
+	if c.Maintenance.Mode != "off" {
+		warnings = append(warnings, "MAINTENANCE_MODE="+c.Maintenance.Mode+" no boot")
+	}
+	if c.Worker.AttachmentGCDryRun {
+		warnings = append(warnings, "ATTACHMENT_GC_DRY_RUN=true: anexos órfãos não são removidos")
+	}
+	return warnings
+}
+
+// ParsePrefix aceita CIDR (10.0.0.0/8) ou IP isolado (10.0.0.1)
+func ParsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("CIDR inválido %q", s)
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("IP inválido %q", s)
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// PoolSizeFor workers do tópico (TopicPoolSize ou PoolSize)
+func (c *WorkerConfig) PoolSizeFor(topic string) int {
+	if n, ok := c.TopicPoolSize[topic]; ok {
+		return n
+	}
+	return c.PoolSize
+}
+
 // DSN retorna string de conexão PostgreSQL
 func (c *DatabaseConfig) DSN() string {
 	return fmt.Sprintf(
@@ -157,7 +1097,57 @@ func parseInt(s string) int {
 	return i
 }
 
+func parseInt64(s string) int64 {
+	i, _ := strconv.ParseInt(s, 10, 64)
+	return i
+}
+
+// hostname nome da máquina ("" se indisponível)
+func hostname() string {
+	name, _ := os.Hostname()
+	return name
+}
+
 func parseDuration(s string) time.Duration {
 	d, _ := time.ParseDuration(s)
 	return d
 }
+
+func parseFloat(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+// parseList separa lista por vírgula ignorando itens vazios
+func parseList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseIntMap lê pares "chave=número" separados por vírgula ignorando itens inválidos
+func parseIntMap(s string) map[string]int {
+	m := map[string]int{}
+	for _, item := range parseList(s) {
+		key, value, ok := strings.Cut(item, "=")
+		if n, err := strconv.Atoi(strings.TrimSpace(value)); ok && err == nil {
+			m[strings.TrimSpace(key)] = n
+		}
+	}
+	return m
+}
+
+// parseDurationList lê durações separadas por vírgula ignorando itens inválidos
+func parseDurationList(s string) []time.Duration {
+	var durations []time.Duration
+	for _, item := range parseList(s) {
+		if d := parseDuration(item); d > 0 {
+			durations = append(durations, d)
+		}
+	}
+	return durations
+}
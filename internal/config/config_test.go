@@ -0,0 +1,77 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// setRequiredEnv variáveis obrigatórias para Load
+func setRequiredEnv(t *testing.T) {
+	t.Helper()
+	for key, value := range map[string]string{
+		"DB_HOST":            "localhost",
+		"DB_PORT":            "5432",
+		"DB_USER":            "chat",
+		"DB_PASSWORD":        "chat",
+		"DB_NAME":            "chat",
+		"JWT_ACCESS_SECRET":  "access-secret-de-teste-com-32-bytes!",
+		"JWT_REFRESH_SECRET": "refresh-secret-de-teste-com-32-bytes",
+		"EVENT_BUS":          "memory",
+	} {
+		t.Setenv(key, value)
+	}
+}
+
+func TestJWTTTLDefaults(t *testing.T) {
+	setRequiredEnv(t)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.JWT.AccessExpiration != time.Hour || cfg.JWT.RefreshExpiration != 7*24*time.Hour {
+		t.Fatalf("TTLs = %v/%v; esperado 1h/168h", cfg.JWT.AccessExpiration, cfg.JWT.RefreshExpiration)
+	}
+}
+
+func TestJWTTTLFromEnv(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("JWT_ACCESS_TTL", "15m")
+	t.Setenv("JWT_REFRESH_TTL", "720h")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.JWT.AccessExpiration != 15*time.Minute || cfg.JWT.RefreshExpiration != 30*24*time.Hour {
+		t.Fatalf("TTLs = %v/%v; esperado 15m/720h", cfg.JWT.AccessExpiration, cfg.JWT.RefreshExpiration)
+	}
+}
+
+func TestJWTTTLBounds(t *testing.T) {
+	for _, tc := range []struct {
+		access, refresh string
+		wantErr         string // "" = aceito
+	}{
+		{"1m", "1h", ""},
+		{"24h", "2160h", ""},
+		{"59s", "168h", "JWT_ACCESS_TTL"},
+		{"25h", "168h", "JWT_ACCESS_TTL"},
+		{"15m", "59m", "JWT_REFRESH_TTL"},
+		{"15m", "2161h", "JWT_REFRESH_TTL"},
+		{"2h", "1h", "maior que JWT_ACCESS_TTL"},
+		{"1h", "1h", "maior que JWT_ACCESS_TTL"},
+	} {
+		t.Run(tc.access+"/"+tc.refresh, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv("JWT_ACCESS_TTL", tc.access)
+			t.Setenv("JWT_REFRESH_TTL", tc.refresh)
+			_, err := Load()
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Fatalf("Load: %v", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Fatalf("err = %v; esperado erro com %q", err, tc.wantErr)
+			}
+		})
+	}
+}
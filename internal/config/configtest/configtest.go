@@ -0,0 +1,32 @@
+// Package configtest configuração dos testes de handler e service: um único
+// lugar para as variáveis obrigatórias quando o config ganha campos
+package configtest
+
+import (
+	"testing"
+
+	"chat-kafka-go/internal/config"
+)
+
+// New configuração padrão com as variáveis obrigatórias preenchidas e
+// barramento em memória; ajustes do teste vão direto no Config devolvido
+func New(tb testing.TB) *config.Config {
+	tb.Helper()
+	for key, value := range map[string]string{
+		"DB_HOST":            "localhost",
+		"DB_PORT":            "5432",
+		"DB_USER":            "chat",
+		"DB_PASSWORD":        "chat",
+		"DB_NAME":            "chat",
+		"JWT_ACCESS_SECRET":  "access-secret-de-teste-com-32-bytes!",
+		"JWT_REFRESH_SECRET": "refresh-secret-de-teste-com-32-bytes",
+		"EVENT_BUS":          "memory",
+	} {
+		tb.Setenv(key, value)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		tb.Fatalf("config: %v", err)
+	}
+	return cfg
+}
@@ -0,0 +1,284 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/status"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrMessageExists lote com mensagem já gravada (ConflictError)
+var ErrMessageExists = errors.New("mensagem já existe")
+
+// ConflictMode o que fazer quando o ID da mensagem já existe no banco
+type ConflictMode int
+
+const (
+	ConflictSkip  ConflictMode = iota // Mantém a linha existente (replay idempotente)
+	ConflictMerge                     // Funde versões mais novas: status só avança
+	ConflictError                     // Falha o lote inteiro
+)
+
+// BulkOptions opções da gravação em massa
+type BulkOptions struct {
+	BatchSize  int                // Linhas por transação (default 5000)
+	OnConflict ConflictMode       // Default: ConflictSkip
+	Progress   func(r BulkResult) // Chamado após cada lote (opcional)
+}
+
+// BulkResult resultado acumulado da gravação
+type BulkResult struct {
+	Processed int64 // Linhas enviadas via COPY
+	Inserted  int64 // Mensagens novas
+	Updated   int64 // Mensagens existentes que avançaram (ConflictMerge ou status)
+}
+
+// Skipped linhas sem efeito: já gravadas, versão antiga ou de usuário removido
+func (r BulkResult) Skipped() int64 {
+	return r.Processed - r.Inserted - r.Updated
+}
+
+// MessageStatus etapa de uma mensagem já gravada (replay de message.status)
+type MessageStatus struct {
+	ID      pgtype.UUID
+	Status  status.Message
+	At      pgtype.Timestamp // Horário da etapa (delivered_at ou read_at)
+	Version int32            // Versão da mensagem após a mudança
+}
+
+const defaultBatchSize = 5000
+
+var (
+	messageColumns = []string{"id", "sender_id", "receiver_id", "content", "status",
+		"created_at", "delivered_at", "read_at", "version", "client_sent_at"}
+	statusColumns = []string{"id", "status", "delivered_at", "read_at", "version"}
+)
+
+// messageRankSQL posição do status na sequência de entrega (mesma de pkg/status)
+const messageRankSQL = `CASE %s WHEN 'accepted' THEN 1 WHEN 'delivered' THEN 2 WHEN 'read' THEN 3 ELSE 0 END`
+
+// mergeSQL aplica a versão mais nova de cada ID da tabela temporária em
+// messages. Só versões maiores entram; o status nunca volta (quarantined é
+// final) e horários já gravados não são trocados. Conteúdo, participantes e
+// created_at são imutáveis
+var mergeSQL = `
+	UPDATE messages m SET
+		status = CASE
+			WHEN m.status = 'quarantined' THEN m.status
+			WHEN t.status = 'quarantined' THEN t.status
+			WHEN ` + fmt.Sprintf(messageRankSQL, "t.status") + ` > ` + fmt.Sprintf(messageRankSQL, "m.status") + ` THEN t.status
+			ELSE m.status
+		END,
+		delivered_at = COALESCE(m.delivered_at, t.delivered_at),
+		read_at = COALESCE(m.read_at, t.read_at),
+		version = t.version
+	FROM (SELECT DISTINCT ON (id) * FROM %s ORDER BY id, version DESC) t
+	WHERE m.id = t.id AND t.version > m.version`
+
+// CopyMessages grava mensagens em massa usando COPY (importadores, seeders,
+// replay). Cada lote é copiado para uma tabela temporária e depois mesclado
+// em messages numa transação: COPY sozinho não trata conflitos e a chave
+// primária inclui created_at, então mensagens existentes são casadas só pelo
+// ID. Não rode dois imports das mesmas mensagens em paralelo
+func (db *DB) CopyMessages(ctx context.Context, messages []repository.Message, opts BulkOptions) (BulkResult, error) {
+	rows := make([][]interface{}, len(messages))
+	now := time.Now().UTC()
+	for i, m := range messages {
+		row, err := messageRow(m, now)
+		if err != nil {
+			return BulkResult{}, fmt.Errorf("mensagem %d: %w", i, err)
+		}
+		rows[i] = row
+	}
+	return db.copyBatches(ctx, rows, opts, db.copyMessagesBatch)
+}
+
+// CopyMessageStatuses avança em massa o status de mensagens já gravadas,
+// com as mesmas regras do ConflictMerge; IDs inexistentes são ignorados
+func (db *DB) CopyMessageStatuses(ctx context.Context, statuses []MessageStatus, opts BulkOptions) (BulkResult, error) {
+	rows := make([][]interface{}, len(statuses))
+	for i, s := range statuses {
+		row, err := statusRow(s)
+		if err != nil {
+			return BulkResult{}, fmt.Errorf("status %d: %w", i, err)
+		}
+		rows[i] = row
+	}
+	return db.copyBatches(ctx, rows, opts, db.copyStatusesBatch)
+}
+
+// batchFunc grava um lote numa transação e retorna inseridas e atualizadas
+type batchFunc func(ctx context.Context, tx pgx.Tx, rows [][]interface{}, mode ConflictMode) (int64, int64, error)
+
+// copyBatches divide as linhas em lotes, um por transação
+func (db *DB) copyBatches(ctx context.Context, rows [][]interface{}, opts BulkOptions, copyBatch batchFunc) (BulkResult, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+
+	var result BulkResult
+	for start := 0; start < len(rows); start += opts.BatchSize {
+		end := min(start+opts.BatchSize, len(rows))
+
+		inserted, updated, err := db.inTx(ctx, func(tx pgx.Tx) (int64, int64, error) {
+			return copyBatch(ctx, tx, rows[start:end], opts.OnConflict)
+		})
+		if err != nil {
+			return result, fmt.Errorf("erro no lote %d-%d: %w", start, end, err)
+		}
+
+		result.Processed += int64(end - start)
+		result.Inserted += inserted
+		result.Updated += updated
+		if opts.Progress != nil {
+			opts.Progress(result)
+		}
+	}
+	return result, nil
+}
+
+// inTx roda fn numa transação do primário
+func (db *DB) inTx(ctx context.Context, fn func(tx pgx.Tx) (int64, int64, error)) (int64, int64, error) {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("falha ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	inserted, updated, err := fn(tx)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, 0, fmt.Errorf("falha no commit: %w", err)
+	}
+	return inserted, updated, nil
+}
+
+// copyMessagesBatch copia um lote de mensagens e mescla conforme o modo
+func (db *DB) copyMessagesBatch(ctx context.Context, tx pgx.Tx, rows [][]interface{}, mode ConflictMode) (int64, int64, error) {
+	if _, err := tx.Exec(ctx, `
+		CREATE TEMP TABLE tmp_messages_import
+		(LIKE messages INCLUDING DEFAULTS) ON COMMIT DROP
+	`); err != nil {
+		return 0, 0, fmt.Errorf("falha ao criar tabela temporária: %w", err)
+	}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"tmp_messages_import"}, messageColumns, pgx.CopyFromRows(rows)); err != nil {
+		return 0, 0, fmt.Errorf("falha no COPY: %w", err)
+	}
+
+	if mode == ConflictError {
+		var existing int64
+		err := tx.QueryRow(ctx, `
+			SELECT COUNT(DISTINCT t.id) FROM tmp_messages_import t
+			WHERE EXISTS (SELECT 1 FROM messages m WHERE m.id = t.id)
+		`).Scan(&existing)
+		if err != nil {
+			return 0, 0, fmt.Errorf("falha ao conferir conflitos: %w", err)
+		}
+		if existing > 0 {
+			return 0, 0, fmt.Errorf("%w: %d no lote", ErrMessageExists, existing)
+		}
+	}
+
+	// Antes do INSERT: as linhas novas já têm a versão do lote
+	var updated int64
+	if mode == ConflictMerge {
+		tag, err := tx.Exec(ctx, fmt.Sprintf(mergeSQL, "tmp_messages_import"))
+		if err != nil {
+			return 0, 0, fmt.Errorf("falha ao mesclar lote: %w", err)
+		}
+		updated = tag.RowsAffected()
+	}
+
+	// ID repetido no lote: vale a maior versão. Usuário que não existe mais
+	// (conta purgada) descarta a mensagem em vez de falhar o lote na FK
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO messages (id, sender_id, receiver_id, content, status, created_at, delivered_at, read_at, version, client_sent_at)
+		SELECT DISTINCT ON (t.id) t.id, t.sender_id, t.receiver_id, t.content, t.status, t.created_at, t.delivered_at, t.read_at, t.version, t.client_sent_at
+		FROM tmp_messages_import t
+		WHERE NOT EXISTS (SELECT 1 FROM messages m WHERE m.id = t.id)
+			AND EXISTS (SELECT 1 FROM users u WHERE u.id = t.sender_id)
+			AND EXISTS (SELECT 1 FROM users u WHERE u.id = t.receiver_id)
+		ORDER BY t.id, t.version DESC
+	`)
+	if err != nil {
+		return 0, 0, fmt.Errorf("falha ao inserir lote: %w", err)
+	}
+	return tag.RowsAffected(), updated, nil
+}
+
+// copyStatusesBatch copia um lote de status e mescla nas mensagens existentes
+func (db *DB) copyStatusesBatch(ctx context.Context, tx pgx.Tx, rows [][]interface{}, _ ConflictMode) (int64, int64, error) {
+	if _, err := tx.Exec(ctx, `
+		CREATE TEMP TABLE tmp_message_status_import (
+			id UUID NOT NULL,
+			status VARCHAR(20) NOT NULL,
+			delivered_at TIMESTAMP,
+			read_at TIMESTAMP,
+			version INTEGER NOT NULL
+		) ON COMMIT DROP
+	`); err != nil {
+		return 0, 0, fmt.Errorf("falha ao criar tabela temporária: %w", err)
+	}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"tmp_message_status_import"}, statusColumns, pgx.CopyFromRows(rows)); err != nil {
+		return 0, 0, fmt.Errorf("falha no COPY: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, fmt.Sprintf(mergeSQL, "tmp_message_status_import"))
+	if err != nil {
+		return 0, 0, fmt.Errorf("falha ao mesclar lote: %w", err)
+	}
+	return 0, tag.RowsAffected(), nil
+}
+
+// messageRow converte a mensagem em linha do COPY, preenchendo ID, status,
+// data e versão ausentes (seeders)
+func messageRow(m repository.Message, now time.Time) ([]interface{}, error) {
+	if !m.ID.Valid {
+		m.ID = pgtype.UUID{Bytes: uuid.New(), Valid: true}
+	}
+	if m.Status == "" {
+		m.Status = status.MessageAccepted
+	}
+	if !m.Status.Valid() {
+		return nil, fmt.Errorf("%w: %q", status.ErrUnknown, m.Status)
+	}
+	if !m.SenderID.Valid || !m.ReceiverID.Valid {
+		return nil, errors.New("remetente e destinatário são obrigatórios")
+	}
+	if !m.CreatedAt.Valid {
+		m.CreatedAt = pgtype.Timestamp{Time: now, Valid: true}
+	}
+	if m.Version <= 0 {
+		m.Version = 1
+	}
+	return []interface{}{m.ID, m.SenderID, m.ReceiverID, m.Content, string(m.Status),
+		m.CreatedAt, m.DeliveredAt, m.ReadAt, m.Version, m.ClientSentAt}, nil
+}
+
+// statusRow converte a etapa em linha do COPY; leitura implica entrega
+func statusRow(s MessageStatus) ([]interface{}, error) {
+	if !s.ID.Valid {
+		return nil, errors.New("ID da mensagem é obrigatório")
+	}
+	if !s.Status.Valid() {
+		return nil, fmt.Errorf("%w: %q", status.ErrUnknown, s.Status)
+	}
+
+	var deliveredAt, readAt pgtype.Timestamp
+	switch s.Status {
+	case status.MessageDelivered:
+		deliveredAt = s.At
+	case status.MessageRead:
+		deliveredAt, readAt = s.At, s.At
+	}
+	return []interface{}{s.ID, string(s.Status), deliveredAt, readAt, s.Version}, nil
+}
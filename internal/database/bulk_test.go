@@ -0,0 +1,262 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"chat-kafka-go/internal/database"
+	"chat-kafka-go/internal/database/dbtest"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/status"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// bulkFixture banco de integração com um par de usuários
+type bulkFixture struct {
+	db       *database.DB
+	queries  *repository.Queries
+	sender   pgtype.UUID
+	receiver pgtype.UUID
+	base     time.Time
+}
+
+func newBulkFixture(t *testing.T) *bulkFixture {
+	t.Helper()
+	pool := dbtest.New(t)
+	return &bulkFixture{
+		db:       &database.DB{Pool: pool},
+		queries:  repository.New(pool),
+		sender:   dbtest.User(t, pool, "alice").ID,
+		receiver: dbtest.User(t, pool, "bob").ID,
+		base:     time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func ts(t time.Time) pgtype.Timestamp {
+	return pgtype.Timestamp{Time: t, Valid: true}
+}
+
+func newID() pgtype.UUID {
+	return pgtype.UUID{Bytes: uuid.New(), Valid: true}
+}
+
+// message mensagem completa (todas as colunas preenchidas conforme o status)
+func (f *bulkFixture) message(st status.Message, version int32) repository.Message {
+	m := repository.Message{
+		ID:           newID(),
+		SenderID:     f.sender,
+		ReceiverID:   f.receiver,
+		Content:      "oi",
+		Status:       st,
+		CreatedAt:    ts(f.base),
+		Version:      version,
+		ClientSentAt: ts(f.base.Add(-time.Second)),
+	}
+	if st == status.MessageDelivered || st == status.MessageRead {
+		m.DeliveredAt = ts(f.base.Add(time.Minute))
+	}
+	if st == status.MessageRead {
+		m.ReadAt = ts(f.base.Add(2 * time.Minute))
+	}
+	return m
+}
+
+func (f *bulkFixture) copy(t *testing.T, mode database.ConflictMode, messages ...repository.Message) database.BulkResult {
+	t.Helper()
+	result, err := f.db.CopyMessages(context.Background(), messages, database.BulkOptions{OnConflict: mode})
+	if err != nil {
+		t.Fatalf("CopyMessages: %v", err)
+	}
+	return result
+}
+
+func (f *bulkFixture) get(t *testing.T, id pgtype.UUID) repository.Message {
+	t.Helper()
+	m, err := f.queries.GetMessageByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetMessageByID: %v", err)
+	}
+	return m
+}
+
+func TestCopyMessagesWritesEveryColumn(t *testing.T) {
+	f := newBulkFixture(t)
+	want := f.message(status.MessageRead, 3)
+
+	result := f.copy(t, database.ConflictSkip, want)
+	if result.Processed != 1 || result.Inserted != 1 {
+		t.Fatalf("result = %+v, quer 1 processada e 1 inserida", result)
+	}
+
+	got := f.get(t, want.ID)
+	if got != want {
+		t.Errorf("linha gravada = %+v\nquer %+v", got, want)
+	}
+}
+
+func TestCopyMessagesFillsDefaults(t *testing.T) {
+	f := newBulkFixture(t)
+	m := repository.Message{SenderID: f.sender, ReceiverID: f.receiver, Content: "seed"}
+
+	f.copy(t, database.ConflictSkip, m)
+
+	page, err := f.queries.ListConversationMessages(context.Background(), repository.ListConversationMessagesParams{
+		UserA: f.sender, UserB: f.receiver, Limit: 10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 || page[0].Status != status.MessageAccepted || page[0].Version != 1 || !page[0].CreatedAt.Valid {
+		t.Errorf("página = %+v, quer uma mensagem accepted versão 1 com created_at", page)
+	}
+}
+
+func TestCopyMessagesSkipsMissingUsers(t *testing.T) {
+	f := newBulkFixture(t)
+	orphan := f.message(status.MessageAccepted, 1)
+	orphan.SenderID = newID() // Conta purgada
+	kept := f.message(status.MessageAccepted, 1)
+
+	result := f.copy(t, database.ConflictSkip, orphan, kept)
+	if result.Inserted != 1 || result.Skipped() != 1 {
+		t.Errorf("result = %+v, quer 1 inserida e 1 descartada", result)
+	}
+}
+
+func TestCopyMessagesMergeNeverMovesStatusBackwards(t *testing.T) {
+	f := newBulkFixture(t)
+	read := f.message(status.MessageRead, 3)
+	accepted := f.message(status.MessageAccepted, 1)
+	quarantined := f.message(status.MessageQuarantined, 2)
+	f.copy(t, database.ConflictSkip, read, accepted, quarantined)
+
+	// Versão antiga: ignorada mesmo no merge
+	staleRead := read
+	staleRead.Status, staleRead.Version = status.MessageDelivered, 2
+	// Versão nova com status atrás: versão sobe, status fica
+	laterRead := read
+	laterRead.Status, laterRead.Version = status.MessageAccepted, 4
+	// Avanço normal
+	delivered := accepted
+	delivered.Status, delivered.Version = status.MessageDelivered, 2
+	delivered.DeliveredAt = ts(f.base.Add(time.Minute))
+	// quarantined é final
+	unquarantined := quarantined
+	unquarantined.Status, unquarantined.Version = status.MessageRead, 5
+
+	result := f.copy(t, database.ConflictMerge, staleRead, laterRead, delivered, unquarantined)
+	if result.Inserted != 0 || result.Updated != 3 {
+		t.Errorf("result = %+v, quer 0 inseridas e 3 atualizadas", result)
+	}
+
+	if got := f.get(t, read.ID); got.Status != status.MessageRead || got.Version != 4 || got.ReadAt != read.ReadAt {
+		t.Errorf("read = %s v%d read_at %v, quer read v4 com read_at preservado", got.Status, got.Version, got.ReadAt)
+	}
+	if got := f.get(t, accepted.ID); got.Status != status.MessageDelivered || got.Version != 2 || got.DeliveredAt != delivered.DeliveredAt {
+		t.Errorf("accepted = %s v%d, quer delivered v2 com delivered_at", got.Status, got.Version)
+	}
+	if got := f.get(t, quarantined.ID); got.Status != status.MessageQuarantined {
+		t.Errorf("quarantined = %s, quer quarantined", got.Status)
+	}
+}
+
+func TestCopyMessagesSkipKeepsExisting(t *testing.T) {
+	f := newBulkFixture(t)
+	existing := f.message(status.MessageAccepted, 1)
+	f.copy(t, database.ConflictSkip, existing)
+
+	newer := existing
+	newer.Status, newer.Version = status.MessageRead, 3
+	fresh := f.message(status.MessageAccepted, 1)
+
+	result := f.copy(t, database.ConflictSkip, newer, fresh)
+	if result.Inserted != 1 || result.Updated != 0 {
+		t.Errorf("result = %+v, quer 1 inserida e 0 atualizadas", result)
+	}
+	if got := f.get(t, existing.ID); got.Status != status.MessageAccepted || got.Version != 1 {
+		t.Errorf("existente = %s v%d, quer accepted v1", got.Status, got.Version)
+	}
+}
+
+func TestCopyMessagesErrorRollsBackBatch(t *testing.T) {
+	f := newBulkFixture(t)
+	existing := f.message(status.MessageAccepted, 1)
+	f.copy(t, database.ConflictSkip, existing)
+
+	fresh := f.message(status.MessageAccepted, 1)
+	_, err := f.db.CopyMessages(context.Background(), []repository.Message{fresh, existing},
+		database.BulkOptions{OnConflict: database.ConflictError})
+	if !errors.Is(err, database.ErrMessageExists) {
+		t.Fatalf("err = %v, quer ErrMessageExists", err)
+	}
+	if _, err := f.queries.GetMessageByID(context.Background(), fresh.ID); err == nil {
+		t.Error("mensagem nova do lote com conflito foi gravada")
+	}
+}
+
+func TestCopyMessagesBatchesAndReportsProgress(t *testing.T) {
+	f := newBulkFixture(t)
+	var messages []repository.Message
+	for i := 0; i < 5; i++ {
+		messages = append(messages, f.message(status.MessageAccepted, 1))
+	}
+	// ID repetido no mesmo lote: grava uma linha, a de maior versão
+	dup := messages[0]
+	dup.Status, dup.Version = status.MessageDelivered, 2
+	messages = append(messages, dup)
+
+	var reports []database.BulkResult
+	result, err := f.db.CopyMessages(context.Background(), messages, database.BulkOptions{
+		BatchSize: 6,
+		Progress:  func(r database.BulkResult) { reports = append(reports, r) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Processed != 6 || result.Inserted != 5 || len(reports) != 1 {
+		t.Errorf("result = %+v com %d relatórios, quer 6 processadas, 5 inseridas, 1 relatório", result, len(reports))
+	}
+	if got := f.get(t, dup.ID); got.Status != status.MessageDelivered {
+		t.Errorf("duplicada = %s, quer delivered (maior versão)", got.Status)
+	}
+
+	reports = nil
+	more := []repository.Message{f.message(status.MessageAccepted, 1), f.message(status.MessageAccepted, 1), f.message(status.MessageAccepted, 1)}
+	if _, err := f.db.CopyMessages(context.Background(), more, database.BulkOptions{
+		BatchSize: 2,
+		Progress:  func(r database.BulkResult) { reports = append(reports, r) },
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 2 || reports[1].Processed != 3 {
+		t.Errorf("relatórios = %+v, quer 2 lotes somando 3", reports)
+	}
+}
+
+func TestCopyMessageStatuses(t *testing.T) {
+	f := newBulkFixture(t)
+	m := f.message(status.MessageAccepted, 1)
+	f.copy(t, database.ConflictSkip, m)
+
+	readAt := f.base.Add(5 * time.Minute)
+	result, err := f.db.CopyMessageStatuses(context.Background(), []database.MessageStatus{
+		{ID: m.ID, Status: status.MessageRead, At: ts(readAt), Version: 2},
+		{ID: m.ID, Status: status.MessageDelivered, At: ts(f.base), Version: 1}, // Atrasado
+		{ID: newID(), Status: status.MessageDelivered, At: ts(f.base), Version: 2},
+	}, database.BulkOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Updated != 1 {
+		t.Errorf("result = %+v, quer 1 atualizada", result)
+	}
+
+	got := f.get(t, m.ID)
+	if got.Status != status.MessageRead || got.Version != 2 || got.ReadAt != ts(readAt) || got.DeliveredAt != ts(readAt) {
+		t.Errorf("mensagem = %+v, quer read v2 com read_at e delivered_at da leitura", got)
+	}
+}
@@ -4,21 +4,87 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sync/atomic"
 	"time"
 
 	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/repository"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type DB struct {
-	Pool *pgxpool.Pool
+	Pool     *pgxpool.Pool   // Primário (escritas e leituras read-your-writes)
+	Replicas []*pgxpool.Pool // Réplicas de leitura (histórico, busca, export)
+
+	next atomic.Uint32
+	down []atomic.Bool // Réplica falhou no último Health: Reader a pula
+}
+
+// Health estado do banco: com o primário de pé, réplicas fora só degradam
+// (as leituras vão para as restantes ou para o primário)
+type Health struct {
+	Replicas     int `json:"replicas"`
+	ReplicasDown int `json:"replicas_down"`
+}
+
+// Degraded alguma réplica fora
+func (h Health) Degraded() bool {
+	return h.ReplicasDown > 0
 }
 
 // New cria nova conexão com PostgreSQL
 func New(ctx context.Context, cfg *config.DatabaseConfig) (*DB, error) {
+	return open(ctx, cfg, true)
+}
+
+// Open cria os pools sem testar a conexão (STARTUP_DB_MODE=degraded)
+// As conexões são abertas sob demanda; até o banco responder as consultas
+// falham e o /readyz retorna 503
+func Open(ctx context.Context, cfg *config.DatabaseConfig) (*DB, error) {
+	db, err := open(ctx, cfg, false)
+	if err != nil {
+		return nil, err
+	}
+	log.Println("WARN: database indisponível no boot, conectando sob demanda")
+	return db, nil
+}
+
+// open cria o pool do primário e das réplicas; ping testa cada conexão
+func open(ctx context.Context, cfg *config.DatabaseConfig, ping bool) (*DB, error) {
+	pool, err := connect(ctx, cfg, cfg.DSN(), ping)
+	if err != nil {
+		return nil, err
+	}
+	if ping {
+		log.Println("✓ Database conectado com sucesso")
+	}
+
+	db := &DB{Pool: pool}
+
+	// Conectar réplicas
+	for i, dsn := range cfg.ReplicaDSNs {
+		replica, err := connect(ctx, cfg, dsn, ping)
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("réplica %d: %w", i, err)
+		}
+		db.Replicas = append(db.Replicas, replica)
+	}
+	db.down = make([]atomic.Bool, len(db.Replicas))
+	if len(db.Replicas) > 0 && ping {
+		log.Printf("✓ %d réplica(s) de leitura conectada(s)", len(db.Replicas))
+	}
+
+	return db, nil
+}
+
+// connect cria pool de conexões para um DSN
+func connect(ctx context.Context, cfg *config.DatabaseConfig, dsn string, ping bool) (*pgxpool.Pool, error) {
 	// Parse config
-	poolConfig, err := pgxpool.ParseConfig(cfg.DSN())
+	poolConfig, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
 		return nil, fmt.Errorf("falha ao parsear config: %w", err)
 	}
@@ -37,22 +103,90 @@ func New(ctx context.Context, cfg *config.DatabaseConfig) (*DB, error) {
 	}
 
 	// Testar conexão
+	if !ping {
+		return pool, nil
+	}
 	if err := pool.Ping(ctx); err != nil {
 		pool.Close()
 		return nil, fmt.Errorf("falha no ping: %w", err)
 	}
 
-	log.Println("✓ Database conectado com sucesso")
-	return &DB{Pool: pool}, nil
+	return pool, nil
+}
+
+// Reader retorna conexão para leituras pesadas: cada query vai para uma réplica
+// (round-robin) ou para o primário se não houver réplicas configuradas.
+// Use com repository.New(db.Reader())
+func (db *DB) Reader() repository.DBTX {
+	if len(db.Replicas) == 0 {
+		return db.Pool
+	}
+	return replicaSet{db: db}
+}
+
+// replica escolhe a próxima réplica de pé; com todas fora, o primário
+func (db *DB) replica() *pgxpool.Pool {
+	start := int(db.next.Add(1))
+	for i := range db.Replicas {
+		j := (start + i) % len(db.Replicas)
+		if !db.down[j].Load() {
+			return db.Replicas[j]
+		}
+	}
+	return db.Pool
+}
+
+// replicaSet implementa repository.DBTX distribuindo queries entre réplicas
+type replicaSet struct {
+	db *DB
+}
+
+func (r replicaSet) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return r.db.replica().Exec(ctx, sql, args...)
+}
+
+func (r replicaSet) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return r.db.replica().Query(ctx, sql, args...)
+}
+
+func (r replicaSet) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return r.db.replica().QueryRow(ctx, sql, args...)
 }
 
 // Close fecha conexão
 func (db *DB) Close() {
+	for _, replica := range db.Replicas {
+		replica.Close()
+	}
 	db.Pool.Close()
 	log.Println("✓ Database desconectado")
 }
 
-// Health verifica saúde do banco
-func (db *DB) Health(ctx context.Context) error {
-	return db.Pool.Ping(ctx)
+// Health verifica saúde do banco: erro só com o primário fora (sem ele
+// nenhuma escrita funciona). Réplicas são conferidas a cada chamada (sonda
+// de readiness): a que falha sai do Reader até voltar a responder
+func (db *DB) Health(ctx context.Context) (Health, error) {
+	if err := db.Pool.Ping(ctx); err != nil {
+		return Health{}, err
+	}
+	return db.checkReplicas(ctx), nil
+}
+
+// checkReplicas pinga as réplicas e atualiza quais o Reader pula
+func (db *DB) checkReplicas(ctx context.Context) Health {
+	health := Health{Replicas: len(db.Replicas)}
+	for i, replica := range db.Replicas {
+		err := replica.Ping(ctx)
+		wasDown := db.down[i].Swap(err != nil)
+		switch {
+		case err != nil:
+			health.ReplicasDown++
+			if !wasDown {
+				log.Printf("WARN: réplica %d fora, leituras redirecionadas: %v", i, err)
+			}
+		case wasDown:
+			log.Printf("✓ Réplica %d de volta", i)
+		}
+	}
+	return health
 }
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// unreachablePool pool sem servidor (porta fechada): conecta sob demanda e
+// todo Ping falha na hora
+func unreachablePool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	pool, err := pgxpool.New(context.Background(), "postgres://chat@127.0.0.1:1/chat?connect_timeout=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func testDB(t *testing.T, replicas int) *DB {
+	t.Helper()
+	db := &DB{Pool: unreachablePool(t)}
+	for i := 0; i < replicas; i++ {
+		db.Replicas = append(db.Replicas, unreachablePool(t))
+	}
+	db.down = make([]atomic.Bool, replicas)
+	return db
+}
+
+func TestReplicaSkipsDown(t *testing.T) {
+	db := testDB(t, 3)
+	db.down[1].Store(true)
+
+	for i := 0; i < 6; i++ {
+		if db.replica() == db.Replicas[1] {
+			t.Fatal("leitura enviada para réplica fora")
+		}
+	}
+
+	db.down[0].Store(true)
+	db.down[2].Store(true)
+	if db.replica() != db.Pool {
+		t.Fatal("com todas as réplicas fora a leitura deveria ir para o primário")
+	}
+}
+
+func TestCheckReplicasMarksDown(t *testing.T) {
+	db := testDB(t, 2)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	health := db.checkReplicas(ctx)
+	if health.Replicas != 2 || health.ReplicasDown != 2 || !health.Degraded() {
+		t.Fatalf("health = %+v; esperado 2 réplicas fora", health)
+	}
+	if db.replica() != db.Pool {
+		t.Fatal("réplicas fora continuam recebendo leituras")
+	}
+}
+
+func TestHealthPrimaryDown(t *testing.T) {
+	db := testDB(t, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := db.Health(ctx); err == nil {
+		t.Fatal("primário fora sem erro")
+	}
+}
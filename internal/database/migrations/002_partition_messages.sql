@@ -0,0 +1,59 @@
+-- Particionamento mensal da tabela de mensagens
+-- A chave primária passa a incluir created_at (exigência do particionamento por RANGE)
+
+ALTER TABLE messages RENAME TO messages_legacy;
+
+DROP INDEX IF EXISTS idx_messages_sender_id;
+DROP INDEX IF EXISTS idx_messages_receiver_id;
+DROP INDEX IF EXISTS idx_messages_created_at;
+
+CREATE TABLE messages (
+    id UUID NOT NULL DEFAULT uuid_generate_v4(),
+    sender_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    receiver_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'sent',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id, created_at)
+) PARTITION BY RANGE (created_at);
+
+-- Índices particionados (criados automaticamente em cada partição)
+CREATE INDEX idx_messages_id ON messages(id);
+CREATE INDEX idx_messages_conversation ON messages(sender_id, receiver_id, created_at DESC);
+CREATE INDEX idx_messages_receiver_id ON messages(receiver_id, created_at DESC);
+
+-- Cria (se não existir) a partição do mês que contém a data informada
+CREATE OR REPLACE FUNCTION create_messages_partition(month DATE)
+RETURNS TEXT AS $$
+DECLARE
+    start_date DATE := date_trunc('month', month)::date;
+    end_date DATE := (date_trunc('month', month) + INTERVAL '1 month')::date;
+    partition_name TEXT := 'messages_' || to_char(start_date, 'YYYY_MM');
+BEGIN
+    EXECUTE format(
+        'CREATE TABLE IF NOT EXISTS %I PARTITION OF messages FOR VALUES FROM (%L) TO (%L)',
+        partition_name, start_date, end_date
+    );
+    RETURN partition_name;
+END;
+$$ language 'plpgsql';
+
+-- Partições para os dados existentes + mês atual e próximos
+DO $$
+DECLARE
+    month DATE := date_trunc('month', COALESCE((SELECT MIN(created_at) FROM messages_legacy), NOW()))::date;
+BEGIN
+    WHILE month <= date_trunc('month', NOW() + INTERVAL '2 months') LOOP
+        PERFORM create_messages_partition(month);
+        month := (month + INTERVAL '1 month')::date;
+    END LOOP;
+END;
+$$;
+
+-- Rede de segurança para datas fora das partições criadas
+CREATE TABLE messages_default PARTITION OF messages DEFAULT;
+
+INSERT INTO messages (id, sender_id, receiver_id, content, status, created_at)
+SELECT id, sender_id, receiver_id, content, status, created_at FROM messages_legacy;
+
+DROP TABLE messages_legacy;
@@ -0,0 +1,34 @@
+-- Resumo desnormalizado por usuário/conversa (lista de conversas em uma leitura)
+CREATE TABLE conversation_summaries (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    peer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    last_message_id UUID,
+    last_message_preview TEXT NOT NULL DEFAULT '',
+    last_message_at TIMESTAMP NOT NULL,
+    unread_count INTEGER NOT NULL DEFAULT 0,
+    last_read_message_id UUID,
+    last_read_at TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, peer_id)
+);
+
+CREATE INDEX idx_conversation_summaries_user_last ON conversation_summaries(user_id, last_message_at DESC);
+
+-- Backfill a partir das mensagens existentes
+INSERT INTO conversation_summaries (user_id, peer_id, last_message_id, last_message_preview, last_message_at, unread_count)
+SELECT DISTINCT ON (c.user_id, c.peer_id)
+    c.user_id,
+    c.peer_id,
+    c.id,
+    LEFT(c.content, 100),
+    c.created_at,
+    (
+        SELECT COUNT(*) FROM messages m
+        WHERE m.receiver_id = c.user_id AND m.sender_id = c.peer_id AND m.status <> 'read'
+    )
+FROM (
+    SELECT sender_id AS user_id, receiver_id AS peer_id, id, content, created_at FROM messages
+    UNION ALL
+    SELECT receiver_id AS user_id, sender_id AS peer_id, id, content, created_at FROM messages
+) c
+ORDER BY c.user_id, c.peer_id, c.created_at DESC;
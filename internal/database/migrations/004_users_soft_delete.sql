@@ -0,0 +1,4 @@
+-- Soft delete de usuários (restauráveis dentro do período de carência)
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX idx_users_deleted_at ON users(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,24 @@
+-- Troca de username com histórico e reserva do handle antigo
+ALTER TABLE users ADD COLUMN username_changed_at TIMESTAMP;
+
+CREATE TABLE username_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    old_username VARCHAR(50) NOT NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    reserved_until TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_username_history_old_username ON username_history(old_username, reserved_until DESC);
+CREATE INDEX idx_username_history_user_id ON username_history(user_id);
+
+-- Menções guardadas por ID (sobrevivem à troca de username)
+-- Sem FK para messages: a PK particionada inclui created_at
+CREATE TABLE message_mentions (
+    message_id UUID NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (message_id, user_id)
+);
+
+CREATE INDEX idx_message_mentions_user_id ON message_mentions(user_id, created_at DESC);
@@ -0,0 +1,10 @@
+-- Configurações de privacidade por usuário (ausência de linha = padrões)
+CREATE TABLE user_privacy_settings (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    message_policy VARCHAR(20) NOT NULL DEFAULT 'everyone',
+    presence_visibility VARCHAR(20) NOT NULL DEFAULT 'everyone',
+    share_read_receipts BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (message_policy IN ('everyone', 'friends')),
+    CHECK (presence_visibility IN ('everyone', 'friends', 'nobody'))
+);
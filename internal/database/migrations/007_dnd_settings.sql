@@ -0,0 +1,9 @@
+-- Não perturbe: janelas semanais (fuso do usuário) e soneca instantânea
+-- windows: [{"weekday": 0-6, "start": "22:00", "end": "07:00"}]
+CREATE TABLE user_dnd_settings (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    windows JSONB NOT NULL DEFAULT '[]',
+    snoozed_until TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
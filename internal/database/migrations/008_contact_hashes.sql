@@ -0,0 +1,18 @@
+-- Hashes de contatos (email/telefone) para sincronização sem expor dados em claro
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+
+CREATE TABLE contact_hashes (
+    kind VARCHAR(10) NOT NULL,
+    hash CHAR(64) NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (kind, hash),
+    CHECK (kind IN ('email', 'phone'))
+);
+
+CREATE INDEX idx_contact_hashes_user_id ON contact_hashes(user_id);
+
+-- Backfill: mesmo formato de utils.HashEmail
+INSERT INTO contact_hashes (kind, hash, user_id)
+SELECT 'email', encode(digest(lower(trim(email)), 'sha256'), 'hex'), id FROM users
+ON CONFLICT DO NOTHING;
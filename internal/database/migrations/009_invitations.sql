@@ -0,0 +1,22 @@
+-- Convites (por email = uso único; por link = reutilizável até expirar)
+CREATE TABLE invitations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    inviter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255),
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_invitations_inviter_id ON invitations(inviter_id);
+
+-- Quem entrou por convite de quem (um registro por convidado)
+CREATE TABLE referrals (
+    invitee_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    inviter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    invitation_id UUID NOT NULL REFERENCES invitations(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_referrals_inviter_id ON referrals(inviter_id);
@@ -0,0 +1,40 @@
+-- Dispositivos conhecidos de cada usuário (fingerprint = hash do device_id ou user agent)
+CREATE TABLE user_devices (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    fingerprint CHAR(64) NOT NULL,
+    user_agent TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE(user_id, fingerprint)
+);
+
+-- Histórico de logins com IP e geolocalização aproximada
+CREATE TABLE login_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device_id UUID REFERENCES user_devices(id) ON DELETE SET NULL,
+    refresh_token_id UUID REFERENCES refresh_tokens(id) ON DELETE SET NULL,
+    ip VARCHAR(45) NOT NULL,
+    country VARCHAR(2),
+    city VARCHAR(100),
+    new_device BOOLEAN NOT NULL DEFAULT FALSE,
+    new_location BOOLEAN NOT NULL DEFAULT FALSE,
+    alert_token_hash CHAR(64) UNIQUE, -- Link "não fui eu" (apenas o hash)
+    reported_at TIMESTAMP,            -- Usuário informou que não reconhece o login
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_login_events_user_id ON login_events(user_id, created_at DESC);
+
+-- Notificações in-app
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_notifications_user_id ON notifications(user_id, created_at DESC);
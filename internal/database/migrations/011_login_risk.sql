@@ -0,0 +1,27 @@
+-- Log de auditoria (eventos de segurança: logins, falhas, step-up, revogações)
+CREATE TABLE audit_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(50) NOT NULL,
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    risk_score INTEGER NOT NULL DEFAULT 0,
+    metadata JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_events_user_id ON audit_events(user_id, created_at DESC);
+CREATE INDEX idx_audit_events_ip ON audit_events(ip, action, created_at DESC);
+
+-- Confirmação por email exigida em logins de risco alto (step-up)
+CREATE TABLE login_challenges (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash CHAR(64) NOT NULL,
+    device_id TEXT NOT NULL DEFAULT '', -- device_id enviado no login original
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    verified_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_login_challenges_user_id ON login_challenges(user_id);
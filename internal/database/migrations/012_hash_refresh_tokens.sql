@@ -0,0 +1,9 @@
+-- Refresh tokens passam a ser armazenados apenas como SHA-256 (hex).
+-- Tokens em texto puro existentes são invalidados: usuários fazem login de novo.
+DELETE FROM refresh_tokens;
+
+DROP INDEX IF EXISTS idx_refresh_tokens_token;
+ALTER TABLE refresh_tokens RENAME COLUMN token TO token_hash;
+ALTER TABLE refresh_tokens ALTER COLUMN token_hash TYPE CHAR(64);
+
+CREATE INDEX idx_refresh_tokens_token_hash ON refresh_tokens(token_hash);
@@ -0,0 +1,12 @@
+-- Chaves de API para integrações internas e bots (apenas o hash é salvo)
+CREATE TABLE api_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,                        -- Início da chave, para identificação
+    key_hash CHAR(64) UNIQUE NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE, -- Usuário (bot) em nome de quem a chave age
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
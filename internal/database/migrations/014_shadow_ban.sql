@@ -0,0 +1,3 @@
+-- Shadow ban: mensagens do usuário são gravadas e parecem entregues para ele,
+-- mas não chegam aos destinatários
+ALTER TABLE users ADD COLUMN shadow_banned_at TIMESTAMP;
@@ -0,0 +1,21 @@
+-- Anexos de mensagens: upload em duas etapas (envio do conteúdo + finalize)
+-- seguido de varredura antivírus assíncrona
+CREATE TABLE attachments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    uploader_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    message_id UUID,                                     -- Sem FK: messages é particionada por created_at
+    storage_key TEXT NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',       -- pending (aguardando conteúdo) ou uploaded
+    scan_status VARCHAR(20) NOT NULL DEFAULT 'pending',  -- pending, scanning, clean, infected, skipped ou error
+    scan_result TEXT,                                    -- Assinatura encontrada pelo antivírus
+    scanned_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_attachments_message_id ON attachments(message_id) WHERE message_id IS NOT NULL;
+CREATE INDEX idx_attachments_scan_queue ON attachments(created_at)
+    WHERE status = 'uploaded' AND scan_status IN ('pending', 'scanning');
@@ -0,0 +1,6 @@
+-- Classificação de imagens (NSFW) e prévia desfocada dos anexos sinalizados
+ALTER TABLE attachments
+    ADD COLUMN nsfw_score DOUBLE PRECISION,             -- NULL = não classificado
+    ADD COLUMN nsfw_labels TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN nsfw_flagged BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN preview_key TEXT;                       -- Versão desfocada servida sem confirmação
@@ -0,0 +1,12 @@
+-- Sessões de upload retomável (protocolo tus): offset confirmado e expiração
+CREATE TABLE upload_sessions (
+    attachment_id UUID PRIMARY KEY REFERENCES attachments(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    upload_length BIGINT NOT NULL,
+    upload_offset BIGINT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,                     -- Renovada a cada bloco recebido
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_upload_sessions_expires_at ON upload_sessions(expires_at);
@@ -0,0 +1,17 @@
+-- Fila de transcodificação de vídeos (versão para web + pôster)
+CREATE TABLE transcode_jobs (
+    attachment_id UUID PRIMARY KEY REFERENCES attachments(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',     -- pending, running, done ou failed
+    attempts INT NOT NULL DEFAULT 0,
+    error TEXT,
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_transcode_jobs_queue ON transcode_jobs(created_at) WHERE status IN ('pending', 'running');
+
+ALTER TABLE attachments
+    ADD COLUMN rendition_key TEXT,                     -- Vídeo H.264/AAC com faststart
+    ADD COLUMN poster_key TEXT;                        -- Quadro representativo em JPEG
@@ -0,0 +1,8 @@
+-- Busca global: trigramas para usernames (ILIKE '%termo%') e texto completo
+-- nas mensagens. Configuração 'simple' evita stemming de um idioma só
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_users_username_trgm ON users USING GIN (username gin_trgm_ops);
+
+-- Índice particionado (criado automaticamente em cada partição)
+CREATE INDEX idx_messages_content_fts ON messages USING GIN (to_tsvector('simple', content));
@@ -0,0 +1,35 @@
+-- Barramento de eventos sem Kafka (EVENT_BUS=postgres): eventos ficam numa
+-- tabela e os consumidores são acordados por NOTIFY
+CREATE TABLE event_log (
+    id BIGSERIAL PRIMARY KEY,
+    topic VARCHAR(255) NOT NULL,
+    key TEXT NOT NULL DEFAULT '',
+    value BYTEA NOT NULL,
+    headers JSONB NOT NULL DEFAULT '{}',                -- request ID, tenant, usuário
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_event_log_topic ON event_log(topic, id);
+CREATE INDEX idx_event_log_created_at ON event_log(created_at);
+
+-- Último evento processado por grupo de consumidores
+CREATE TABLE event_consumer_offsets (
+    consumer_group VARCHAR(255) NOT NULL,
+    topic VARCHAR(255) NOT NULL,
+    last_event_id BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (consumer_group, topic)
+);
+
+-- Acorda os consumidores no commit da inserção (payload = tópico)
+CREATE OR REPLACE FUNCTION notify_event_log()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('event_log', NEW.topic);
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER event_log_notify
+AFTER INSERT ON event_log
+FOR EACH ROW EXECUTE FUNCTION notify_event_log();
@@ -0,0 +1,8 @@
+-- IDs de mensagens gerados pela aplicação (MESSAGE_ID_MODE, padrão UUIDv7
+-- ordenado pelo tempo). Linhas antigas com UUIDv4 continuam válidas: a
+-- paginação por cursor ordena por (created_at, id), não só pelo ID
+ALTER TABLE messages ALTER COLUMN id DROP DEFAULT;
+
+-- Keyset do histórico: desempate por id dentro do mesmo created_at
+DROP INDEX IF EXISTS idx_messages_conversation;
+CREATE INDEX idx_messages_conversation ON messages(sender_id, receiver_id, created_at DESC, id DESC);
@@ -0,0 +1,11 @@
+-- Contador de mensagens do dia por usuário (cotas). Uma linha por usuário:
+-- o contador recomeça quando o dia gravado fica para trás
+CREATE TABLE user_usage (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    messages INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Soma dos anexos por usuário (cota de armazenamento)
+CREATE INDEX idx_attachments_uploader_id ON attachments(uploader_id);
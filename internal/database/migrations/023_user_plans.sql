@@ -0,0 +1,13 @@
+-- Plano por usuário (sem linha = PLAN_DEFAULT). Atualizado pelos webhooks do
+-- provedor de cobrança ou pelo admin (status manual)
+CREATE TABLE user_plans (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    plan VARCHAR(20) NOT NULL,                          -- free, pro ou workspace
+    status VARCHAR(20) NOT NULL,                        -- active, trialing, past_due, canceled ou manual
+    billing_provider VARCHAR(20),
+    billing_customer_id TEXT,
+    billing_subscription_id TEXT,
+    current_period_end TIMESTAMP,                       -- NULL = sem vencimento
+    billing_event_at TIMESTAMP,                         -- Último evento aplicado (descarta fora de ordem)
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
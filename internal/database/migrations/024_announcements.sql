@@ -0,0 +1,33 @@
+-- Remetente das mensagens de sistema (anúncios). Senha inválida: não faz login
+INSERT INTO users (id, username, email, password_hash)
+VALUES ('00000000-0000-0000-0000-000000000001', 'system', 'system@localhost', '!')
+ON CONFLICT DO NOTHING;
+
+-- Anúncios do admin para todos os usuários ou um plano, enviados como
+-- mensagens do usuário system pelo pipeline normal
+CREATE TABLE announcements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    content TEXT NOT NULL,
+    segment_plan VARCHAR(20),                           -- NULL = todos os usuários
+    requires_ack BOOLEAN NOT NULL DEFAULT FALSE,        -- Usuário precisa confirmar a leitura
+    scheduled_at TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled',    -- scheduled, sending, sent ou canceled
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX idx_announcements_due ON announcements(scheduled_at) WHERE status = 'scheduled';
+
+-- Uma entrega por usuário: torna o envio em lotes idempotente e guarda a confirmação
+CREATE TABLE announcement_deliveries (
+    announcement_id UUID NOT NULL REFERENCES announcements(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    message_id UUID NOT NULL,                           -- Sem FK: messages é particionada
+    delivered_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    acknowledged_at TIMESTAMP,
+    PRIMARY KEY (announcement_id, user_id)
+);
+
+CREATE INDEX idx_announcement_deliveries_pending ON announcement_deliveries(user_id)
+    WHERE acknowledged_at IS NULL;
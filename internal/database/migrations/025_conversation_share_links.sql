@@ -0,0 +1,13 @@
+-- Links somente leitura do histórico de uma conversa (ex.: transcrição para o
+-- suporte): quem tem o link lista as mensagens como o dono, mas não envia
+CREATE TABLE conversation_share_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    peer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_conversation_share_links_owner ON conversation_share_links(owner_id, peer_id);
@@ -0,0 +1,42 @@
+-- Caixa de suporte: o cliente conversa com o usuário support e os agentes
+-- respondem em nome dele (o cliente não vê quem atende). Senha inválida: não faz login
+INSERT INTO users (id, username, email, password_hash)
+VALUES ('00000000-0000-0000-0000-000000000002', 'support', 'support@localhost', '!')
+ON CONFLICT DO NOTHING;
+
+-- Usuários com papel de agente (gerenciados pelo admin)
+CREATE TABLE support_agents (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Chamados: a conversa cliente <-> support enquanto não fecham
+CREATE TABLE support_tickets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    customer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    agent_id UUID REFERENCES users(id) ON DELETE SET NULL,  -- NULL = na fila
+    subject VARCHAR(200) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',             -- open, pending (aguarda o cliente) ou closed
+    first_response_due_at TIMESTAMP NOT NULL,               -- SLA fixado na abertura
+    resolution_due_at TIMESTAMP NOT NULL,
+    first_response_at TIMESTAMP,
+    closed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Um chamado ativo por cliente: a conversa com o suporte é uma só
+CREATE UNIQUE INDEX idx_support_tickets_active_customer ON support_tickets(customer_id)
+    WHERE status <> 'closed';
+CREATE INDEX idx_support_tickets_queue ON support_tickets(status, created_at);
+
+-- Notas internas dos agentes (nunca chegam ao cliente)
+CREATE TABLE support_ticket_notes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ticket_id UUID NOT NULL REFERENCES support_tickets(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_support_ticket_notes_ticket ON support_ticket_notes(ticket_id, created_at);
@@ -0,0 +1,18 @@
+-- Retenção legal: enquanto ativa, nenhum caminho de remoção/retenção apaga
+-- os dados do usuário (peer_id NULL) ou da conversa (par user_id < peer_id).
+-- Liberadas ficam registradas (released_at); sem CASCADE: a linha barra
+-- também a remoção física do usuário
+CREATE TABLE legal_holds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id),
+    peer_id UUID REFERENCES users(id),
+    reason TEXT NOT NULL,
+    released_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (peer_id IS NULL OR user_id < peer_id)
+);
+
+-- Uma retenção ativa por usuário/conversa
+CREATE UNIQUE INDEX idx_legal_holds_active ON legal_holds(user_id, COALESCE(peer_id, user_id))
+    WHERE released_at IS NULL;
+CREATE INDEX idx_legal_holds_active_peer ON legal_holds(peer_id) WHERE released_at IS NULL;
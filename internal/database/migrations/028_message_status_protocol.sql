@@ -0,0 +1,9 @@
+-- Protocolo de status em três etapas, com o horário do servidor de cada uma:
+-- accepted (persistida; created_at), delivered (chegou a um dispositivo do
+-- destinatário) e read (lida, só com confirmação de leitura). Só avança:
+-- delivered_at/read_at nunca são sobrescritos
+ALTER TABLE messages ADD COLUMN delivered_at TIMESTAMP;
+ALTER TABLE messages ADD COLUMN read_at TIMESTAMP;
+
+UPDATE messages SET status = 'accepted' WHERE status = 'sent';
+ALTER TABLE messages ALTER COLUMN status SET DEFAULT 'accepted';
@@ -0,0 +1,8 @@
+-- Status de mensagens e amizades restritos aos valores de pkg/status; as
+-- transições permitidas são validadas na aplicação (mesma tabela em
+-- pkg/status) e os UPDATEs de etapa só avançam (WHERE no status atual)
+ALTER TABLE messages ADD CONSTRAINT messages_status_check
+    CHECK (status IN ('accepted', 'delivered', 'read', 'quarantined'));
+
+ALTER TABLE friendships ADD CONSTRAINT friendships_status_check
+    CHECK (status IN ('pending', 'accepted'));
@@ -0,0 +1,6 @@
+-- Concorrência otimista: toda mudança de status incrementa version e os
+-- UPDATEs que partem de uma leitura exigem a versão lida (compare-and-set),
+-- então dois dispositivos (ou o consumer e um ack) não sobrescrevem em
+-- silêncio um estado mais novo
+ALTER TABLE messages ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE friendships ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
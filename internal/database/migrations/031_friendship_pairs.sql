@@ -0,0 +1,18 @@
+-- Uma única linha por par de usuários, em qualquer direção: UNIQUE(user_id,
+-- friend_id) deixava A→B e B→A coexistirem quando os dois pediam ao mesmo
+-- tempo. user_id continua sendo quem pediu (quem aceita é friend_id)
+
+-- Pedidos recíprocos já gravados: os dois quiseram a amizade, então a linha
+-- mais antiga vira accepted e a outra sai
+UPDATE friendships f SET status = 'accepted', version = f.version + 1
+FROM friendships r
+WHERE r.user_id = f.friend_id AND r.friend_id = f.user_id
+  AND (f.created_at, f.id) < (r.created_at, r.id);
+
+DELETE FROM friendships f
+USING friendships r
+WHERE r.user_id = f.friend_id AND r.friend_id = f.user_id
+  AND (f.created_at, f.id) > (r.created_at, r.id);
+
+CREATE UNIQUE INDEX idx_friendships_pair
+    ON friendships (LEAST(user_id, friend_id), GREATEST(user_id, friend_id));
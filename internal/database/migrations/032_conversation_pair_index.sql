@@ -0,0 +1,6 @@
+-- Histórico pelo par simétrico da conversa: LEAST/GREATEST(sender_id,
+-- receiver_id) casa os dois sentidos numa única faixa do índice, já na
+-- ordem da paginação (created_at, id). idx_messages_conversation continua
+-- servindo as consultas por sentido (não lidas, resumos)
+CREATE INDEX idx_messages_pair
+    ON messages (LEAST(sender_id, receiver_id), GREATEST(sender_id, receiver_id), created_at DESC, id DESC);
@@ -0,0 +1,4 @@
+-- Horário informado pelo dispositivo no envio (opcional), guardado ao lado
+-- do horário do servidor (created_at). Ordenação e paginação continuam só
+-- por created_at: relógio errado no cliente não embaralha a conversa
+ALTER TABLE messages ADD COLUMN client_sent_at TIMESTAMP;
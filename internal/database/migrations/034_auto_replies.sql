@@ -0,0 +1,21 @@
+-- Resposta automática (férias/ausência): texto enviado de volta a quem
+-- manda DM dentro da janela ativa. starts_at NULL = desde já; ends_at NULL =
+-- até o usuário desligar
+CREATE TABLE auto_replies (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    message TEXT NOT NULL,
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    friends_only BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (ends_at IS NULL OR starts_at IS NULL OR ends_at > starts_at)
+);
+
+-- Última resposta automática por conversa (dia UTC): no máximo uma por
+-- remetente por dia; a linha é reaproveitada, não cresce com o tempo
+CREATE TABLE auto_reply_sends (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    sender_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    PRIMARY KEY (user_id, sender_id)
+);
@@ -0,0 +1,9 @@
+-- Preferências de interface sincronizadas entre dispositivos (tema,
+-- densidade, enter para enviar...). prefs guarda só o que o usuário mudou; os
+-- padrões e o schema das chaves ficam no service. version: compare-and-set
+CREATE TABLE user_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    prefs JSONB NOT NULL DEFAULT '{}',
+    version INTEGER NOT NULL DEFAULT 1,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,9 @@
+-- Usernames únicos sem diferenciar maiúsculas ("Ana" e "ana" são o mesmo
+-- usuário nas buscas, menções e reservas). Duplicatas existentes impedem o
+-- índice; liste antes de aplicar e renomeie uma das contas:
+--   SELECT LOWER(username), array_agg(username) FROM users GROUP BY 1 HAVING COUNT(*) > 1;
+CREATE UNIQUE INDEX idx_users_username_lower ON users(LOWER(username));
+DROP INDEX idx_users_username;
+
+CREATE INDEX idx_username_history_old_username_lower ON username_history(LOWER(old_username), reserved_until DESC);
+DROP INDEX idx_username_history_old_username;
@@ -0,0 +1,19 @@
+-- Confirmação de email: email_verified_at NULL = email não confirmado. Contas
+-- existentes (inclusive as de sistema) entram como confirmadas para não
+-- bloquear ninguém ao ligar EMAIL_VERIFICATION_REQUIRED
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP;
+UPDATE users SET email_verified_at = created_at;
+
+-- Tokens do link de confirmação (só o hash SHA-256 é guardado); email = endereço
+-- confirmado pelo token, que deixa de valer se o usuário trocar de email
+CREATE TABLE email_verification_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    email VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_email_verification_tokens_user_id ON email_verification_tokens(user_id, created_at DESC);
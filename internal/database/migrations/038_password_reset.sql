@@ -0,0 +1,14 @@
+-- Links de redefinição de senha (uso único; só o hash SHA-256 é guardado).
+-- email = endereço que recebeu o link: deixa de valer se o usuário trocar de email
+CREATE TABLE password_reset_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    email VARCHAR(255) NOT NULL,
+    ip VARCHAR(45) NOT NULL DEFAULT '', -- IP de quem pediu
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_password_reset_tokens_user_id ON password_reset_tokens(user_id, created_at DESC);
@@ -0,0 +1,21 @@
+-- Autorização do usuário para o suporte ver suas conversas (uma por usuário;
+-- revogar apaga a linha)
+CREATE TABLE support_access_grants (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Sessões de impersonação somente leitura emitidas pelo admin. O token (JWT
+-- com claim imp = id) só vale enquanto a sessão não expirou nem foi revogada
+CREATE TABLE impersonation_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    operator VARCHAR(100) NOT NULL, -- Quem do suporte pediu (o token de admin é compartilhado)
+    reason TEXT NOT NULL,           -- Chamado ou motivo (obrigatório)
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_impersonation_sessions_user_id ON impersonation_sessions(user_id, created_at DESC);
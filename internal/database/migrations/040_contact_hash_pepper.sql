@@ -0,0 +1,16 @@
+-- Sincronização de contatos: o servidor guarda HMAC-SHA256 (CONTACT_HASH_PEPPER)
+-- do SHA-256 enviado pelo cliente, então a tabela vazada não confirma emails
+-- por força bruta. Os hashes antigos (SHA-256 puro) são descartados e o
+-- servidor recria os de email no boot. Telefone sai: nenhum era gravado
+DELETE FROM contact_hashes;
+ALTER TABLE contact_hashes DROP CONSTRAINT contact_hashes_kind_check;
+ALTER TABLE contact_hashes ADD CONSTRAINT contact_hashes_kind_check CHECK (kind = 'email');
+
+-- Hashes consultados por usuário no dia (limite contra enumeração de contas).
+-- Uma linha por usuário: o contador recomeça quando o dia gravado fica para trás
+CREATE TABLE contact_lookups (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    hashes INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,11 @@
+-- Email e username únicos só entre contas ativas: os UNIQUE da tabela valiam
+-- também para contas removidas (soft delete), e o cadastro com o email de
+-- uma conta removida passava pela verificação (que ignora removidas) e
+-- falhava no INSERT. Restaurar uma conta cujo email ou username já foi
+-- reaproveitado falha com conflito
+ALTER TABLE users DROP CONSTRAINT users_email_key;
+ALTER TABLE users DROP CONSTRAINT users_username_key;
+DROP INDEX idx_users_username_lower;
+
+CREATE UNIQUE INDEX idx_users_email_active ON users(email) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_users_username_lower_active ON users(LOWER(username)) WHERE deleted_at IS NULL;
@@ -0,0 +1,47 @@
+-- Purga de contas removidas após a carência (USER_DELETION_GRACE_PERIOD).
+-- Bloqueiam a purga: qualquer retenção legal do usuário, mesmo liberada (a
+-- FK de legal_holds não tem CASCADE), e mensagens trocadas com usuário sob
+-- retenção ativa (sairiam em cascata junto com a conta)
+CREATE OR REPLACE FUNCTION user_purge_blocked(target UUID)
+RETURNS BOOLEAN AS $$
+    SELECT EXISTS (
+        SELECT 1 FROM legal_holds h WHERE h.user_id = target OR h.peer_id = target
+    ) OR EXISTS (
+        SELECT 1 FROM legal_holds h
+        JOIN messages m ON (m.sender_id = target AND m.receiver_id = h.user_id)
+                        OR (m.sender_id = h.user_id AND m.receiver_id = target)
+        WHERE h.released_at IS NULL AND h.peer_id IS NULL
+    );
+$$ LANGUAGE sql STABLE;
+
+-- Com DELETED_USER_MESSAGES=anonymize as mensagens continuam no histórico do
+-- outro lado ("usuário removido"), então a conta não é apagada: identidade e
+-- credencial saem (senha vazia marca a conta já anonimizada) junto com
+-- usernames antigos, hashes de contato, dispositivos e logins
+CREATE OR REPLACE FUNCTION anonymize_deleted_users(cutoff TIMESTAMP, batch_size INT)
+RETURNS INT AS $$
+DECLARE
+    ids UUID[];
+BEGIN
+    SELECT array_agg(id) INTO ids FROM (
+        SELECT u.id FROM users u
+        WHERE u.deleted_at < cutoff AND u.password_hash <> '' AND NOT user_purge_blocked(u.id)
+        ORDER BY u.deleted_at
+        LIMIT batch_size
+    ) batch;
+    IF ids IS NULL THEN
+        RETURN 0;
+    END IF;
+
+    DELETE FROM username_history WHERE user_id = ANY(ids);
+    DELETE FROM contact_hashes WHERE user_id = ANY(ids);
+    DELETE FROM user_devices WHERE user_id = ANY(ids);
+    DELETE FROM login_events WHERE user_id = ANY(ids);
+    UPDATE users SET
+        username = 'removido_' || replace(id::text, '-', ''),
+        email = id::text || '@removido.invalid',
+        password_hash = ''
+    WHERE id = ANY(ids);
+    RETURN cardinality(ids);
+END;
+$$ LANGUAGE plpgsql;
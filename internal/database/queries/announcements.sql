@@ -0,0 +1,75 @@
+-- name: CreateAnnouncement :one
+INSERT INTO announcements (content, segment_plan, requires_ack, scheduled_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetAnnouncement :one
+SELECT * FROM announcements WHERE id = $1;
+
+-- name: ListAnnouncements :many
+SELECT * FROM announcements
+ORDER BY created_at DESC
+LIMIT $1 OFFSET $2;
+
+-- name: CountAnnouncementDeliveries :one
+SELECT
+    COUNT(*)::int AS delivered,
+    COUNT(acknowledged_at)::int AS acknowledged
+FROM announcement_deliveries
+WHERE announcement_id = $1;
+
+-- name: CancelAnnouncement :execrows
+UPDATE announcements SET status = 'canceled', finished_at = NOW()
+WHERE id = $1 AND status IN ('scheduled', 'sending');
+
+-- name: ClaimDueAnnouncements :many
+-- Reserva os anúncios vencidos; SKIP LOCKED evita envio duplicado entre instâncias
+UPDATE announcements SET status = 'sending', started_at = NOW()
+WHERE id IN (
+    SELECT id FROM announcements
+    WHERE status = 'scheduled' AND scheduled_at <= sqlc.arg(now)::timestamp
+    ORDER BY scheduled_at
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: ReleaseAnnouncement :exec
+UPDATE announcements SET status = 'scheduled', started_at = NULL
+WHERE id = $1 AND status = 'sending';
+
+-- name: FinishAnnouncement :exec
+UPDATE announcements SET status = 'sent', finished_at = NOW()
+WHERE id = $1 AND status = 'sending';
+
+-- name: ListAnnouncementRecipients :many
+-- Próximo lote de destinatários (ordem por id) com o plano gravado
+SELECT u.id, p.plan, p.status, p.current_period_end
+FROM users u
+LEFT JOIN user_plans p ON p.user_id = u.id
+WHERE u.id > sqlc.arg(after_id) AND u.id <> sqlc.arg(system_id) AND u.deleted_at IS NULL
+ORDER BY u.id
+LIMIT sqlc.arg(batch_size);
+
+-- name: CreateAnnouncementMessage :execrows
+-- Entrega e mensagem no mesmo comando; 0 linhas = usuário já recebeu
+WITH delivery AS (
+    INSERT INTO announcement_deliveries (announcement_id, user_id, message_id)
+    VALUES (sqlc.arg(announcement_id), sqlc.arg(user_id), sqlc.arg(message_id))
+    ON CONFLICT DO NOTHING
+    RETURNING message_id, user_id
+)
+INSERT INTO messages (id, sender_id, receiver_id, content, status)
+SELECT message_id, sqlc.arg(sender_id), user_id, sqlc.arg(content), 'accepted' FROM delivery;
+
+-- name: ListPendingAnnouncements :many
+SELECT a.id, a.content, d.message_id, d.delivered_at
+FROM announcement_deliveries d
+JOIN announcements a ON a.id = d.announcement_id
+WHERE d.user_id = $1 AND a.requires_ack AND d.acknowledged_at IS NULL
+ORDER BY d.delivered_at;
+
+-- name: AcknowledgeAnnouncement :execrows
+-- Idempotente: mantém a primeira confirmação; 0 linhas = não entregue ao usuário
+UPDATE announcement_deliveries SET acknowledged_at = COALESCE(acknowledged_at, NOW())
+WHERE announcement_id = $1 AND user_id = $2;
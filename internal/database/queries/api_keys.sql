@@ -0,0 +1,30 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (name, prefix, key_hash, scopes, user_id)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetActiveAPIKeyByHash :one
+-- Chave de bot com conta removida para de valer mesmo sem revogação
+SELECT * FROM api_keys
+WHERE key_hash = $1 AND revoked_at IS NULL
+  AND (user_id IS NULL OR EXISTS (
+    SELECT 1 FROM users WHERE users.id = api_keys.user_id AND users.deleted_at IS NULL
+  ));
+
+-- name: ListAPIKeys :many
+SELECT * FROM api_keys
+ORDER BY created_at DESC;
+
+-- name: RevokeAPIKey :execrows
+UPDATE api_keys SET revoked_at = NOW()
+WHERE id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeUserAPIKeys :execrows
+-- Exclusão da conta: revoga as chaves que agem em nome do usuário
+UPDATE api_keys SET revoked_at = NOW()
+WHERE user_id = $1 AND revoked_at IS NULL;
+
+-- name: TouchAPIKey :exec
+-- Atualiza no máximo uma vez por minuto (evita escrita a cada requisição)
+UPDATE api_keys SET last_used_at = NOW()
+WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute');
@@ -0,0 +1,108 @@
+-- name: CreateAttachment :one
+INSERT INTO attachments (id, uploader_id, storage_key, file_name, content_type, size_bytes)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetAttachmentByID :one
+SELECT * FROM attachments WHERE id = $1;
+
+-- name: SetAttachmentSize :execrows
+UPDATE attachments SET size_bytes = $3, updated_at = NOW()
+WHERE id = $1 AND uploader_id = $2 AND status = 'pending';
+
+-- name: MarkAttachmentUploaded :execrows
+UPDATE attachments SET status = 'uploaded', updated_at = NOW()
+WHERE id = $1 AND uploader_id = $2 AND status = 'pending' AND size_bytes > 0;
+
+-- Reserva um lote para varredura; itens presos em 'scanning' (worker caiu)
+-- voltam para a fila depois de stale_before
+-- name: ClaimAttachmentsForScan :many
+UPDATE attachments SET scan_status = 'scanning', updated_at = NOW()
+WHERE id IN (
+    SELECT a.id FROM attachments a
+    WHERE a.status = 'uploaded'
+      AND (a.scan_status = 'pending' OR (a.scan_status = 'scanning' AND a.updated_at < sqlc.arg(stale_before)))
+    ORDER BY a.created_at
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: UpdateAttachmentScan :exec
+UPDATE attachments
+SET scan_status = $2, scan_result = $3, storage_key = $4, scanned_at = NOW(), updated_at = NOW()
+WHERE id = $1;
+
+-- name: ReleaseAttachmentScan :exec
+UPDATE attachments SET scan_status = 'pending', updated_at = NOW()
+WHERE id = $1 AND scan_status = 'scanning';
+
+-- name: AttachToMessage :execrows
+UPDATE attachments SET message_id = $3, updated_at = NOW()
+WHERE id = $1 AND uploader_id = $2 AND message_id IS NULL
+  AND status = 'uploaded' AND scan_status <> 'infected';
+
+-- name: ListAttachmentsByMessageIDs :many
+SELECT * FROM attachments
+WHERE message_id = ANY(sqlc.arg(message_ids)::uuid[])
+ORDER BY created_at;
+
+-- name: UpdateAttachmentClassification :exec
+UPDATE attachments
+SET nsfw_score = $2, nsfw_labels = $3, nsfw_flagged = $4, preview_key = $5, updated_at = NOW()
+WHERE id = $1;
+
+-- Upload não finalizado ou finalizado e nunca enviado em mensagem
+-- name: ListStaleAttachmentUploads :many
+SELECT * FROM attachments
+WHERE message_id IS NULL AND created_at < sqlc.arg(cutoff)
+  AND id NOT IN (SELECT attachment_id FROM upload_sessions) -- Sessões tus têm expiração própria
+ORDER BY created_at
+LIMIT sqlc.arg(batch_size);
+
+-- name: ListAttachmentsWithDeletedMessage :many
+SELECT * FROM attachments a
+WHERE a.message_id IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM messages m WHERE m.id = a.message_id)
+  AND NOT EXISTS ( -- Conversa desconhecida: qualquer retenção com o autor do upload
+    SELECT 1 FROM legal_holds h
+    WHERE h.released_at IS NULL AND (h.user_id = a.uploader_id OR h.peer_id = a.uploader_id)
+  )
+ORDER BY a.created_at
+LIMIT sqlc.arg(batch_size);
+
+-- name: ListPurgeableUserAttachments :many
+-- Uploads de contas a purgar: o objeto sai antes da conta, cuja remoção
+-- apagaria as linhas em cascata e deixaria os arquivos órfãos
+SELECT * FROM attachments a
+WHERE a.uploader_id IN (
+  SELECT u.id FROM users u
+  WHERE u.deleted_at < sqlc.arg(cutoff) AND NOT user_purge_blocked(u.id)
+)
+ORDER BY a.created_at
+LIMIT sqlc.arg(batch_size);
+
+-- name: ListExpiredAttachments :many
+SELECT * FROM attachments a
+WHERE a.created_at < sqlc.arg(cutoff)
+  AND NOT EXISTS ( -- Retenção legal do remetente, destinatário ou da conversa
+    SELECT 1 FROM legal_holds h
+    LEFT JOIN messages m ON m.id = a.message_id
+    WHERE h.released_at IS NULL
+      AND ((h.peer_id IS NULL AND h.user_id IN (a.uploader_id, m.sender_id, m.receiver_id))
+        OR (h.user_id = LEAST(m.sender_id, m.receiver_id) AND h.peer_id = GREATEST(m.sender_id, m.receiver_id)))
+  )
+ORDER BY a.created_at
+LIMIT sqlc.arg(batch_size);
+
+-- name: DeleteAttachment :exec
+DELETE FROM attachments WHERE id = $1;
+
+-- name: SetAttachmentRenditions :exec
+UPDATE attachments SET rendition_key = $2, poster_key = $3, updated_at = NOW()
+WHERE id = $1;
+
+-- name: SumAttachmentBytesByUploader :one
+SELECT COALESCE(SUM(size_bytes), 0)::bigint AS total_bytes
+FROM attachments
+WHERE uploader_id = $1;
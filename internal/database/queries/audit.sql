@@ -0,0 +1,23 @@
+-- name: CreateAuditEvent :exec
+INSERT INTO audit_events (user_id, action, ip, risk_score, metadata)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListUserAuditEvents :many
+SELECT * FROM audit_events
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
+
+-- name: ListAuditEventsByAction :many
+SELECT * FROM audit_events
+WHERE action = $1
+ORDER BY created_at DESC
+LIMIT $2;
+
+-- name: CountUserAuditEventsSince :one
+SELECT COUNT(*)::int FROM audit_events
+WHERE user_id = $1 AND action = $2 AND created_at > $3;
+
+-- name: CountIPAuditEventsSince :one
+SELECT COUNT(*)::int FROM audit_events
+WHERE ip = $1 AND action = $2 AND created_at > $3;
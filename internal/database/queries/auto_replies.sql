@@ -0,0 +1,27 @@
+-- name: GetAutoReply :one
+SELECT * FROM auto_replies WHERE user_id = $1;
+
+-- name: UpsertAutoReply :one
+INSERT INTO auto_replies (user_id, message, starts_at, ends_at, friends_only)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id) DO UPDATE SET
+    message = EXCLUDED.message,
+    starts_at = EXCLUDED.starts_at,
+    ends_at = EXCLUDED.ends_at,
+    friends_only = EXCLUDED.friends_only,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteAutoReply :execrows
+DELETE FROM auto_replies WHERE user_id = $1;
+
+-- name: ClaimAutoReply :execrows
+-- Reserva a resposta do dia para a conversa; 0 linhas = já respondida hoje
+INSERT INTO auto_reply_sends (user_id, sender_id, day)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, sender_id) DO UPDATE SET day = EXCLUDED.day
+WHERE auto_reply_sends.day < EXCLUDED.day;
+
+-- name: ReleaseAutoReply :exec
+-- Desfaz a reserva quando o envio falhou (a retentativa do evento responde)
+DELETE FROM auto_reply_sends WHERE user_id = $1 AND sender_id = $2 AND day = $3;
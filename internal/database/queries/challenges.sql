@@ -0,0 +1,15 @@
+-- name: CreateLoginChallenge :one
+INSERT INTO login_challenges (user_id, code_hash, device_id, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetPendingLoginChallenge :one
+SELECT * FROM login_challenges
+WHERE id = $1 AND verified_at IS NULL AND expires_at > NOW();
+
+-- name: IncrementLoginChallengeAttempts :exec
+UPDATE login_challenges SET attempts = attempts + 1 WHERE id = $1;
+
+-- name: MarkLoginChallengeVerified :execrows
+UPDATE login_challenges SET verified_at = NOW()
+WHERE id = $1 AND verified_at IS NULL;
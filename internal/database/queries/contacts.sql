@@ -0,0 +1,28 @@
+-- name: UpsertContactHash :exec
+INSERT INTO contact_hashes (kind, hash, user_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (kind, hash) DO UPDATE SET user_id = EXCLUDED.user_id;
+
+-- name: ListUsersByContactHashes :many
+SELECT ch.hash, u.id, u.username FROM contact_hashes ch
+INNER JOIN users u ON u.id = ch.user_id
+WHERE ch.kind = $1 AND ch.hash = ANY(sqlc.arg(hashes)::text[]) AND u.deleted_at IS NULL;
+
+-- name: ListUsersWithoutContactHash :many
+-- Usuários ativos sem hash de email (após a migração 040 ou troca do pepper)
+SELECT u.id, u.email FROM users u
+WHERE u.deleted_at IS NULL
+  AND NOT EXISTS (SELECT 1 FROM contact_hashes ch WHERE ch.user_id = u.id AND ch.kind = 'email')
+ORDER BY u.id
+LIMIT $1;
+
+-- name: ConsumeContactLookups :one
+-- Soma os hashes consultados se o dia ainda comporta; sem linha = limite esgotado
+INSERT INTO contact_lookups (user_id, day, hashes)
+VALUES ($1, $2, sqlc.arg(hashes)::int)
+ON CONFLICT (user_id) DO UPDATE
+SET hashes = CASE WHEN contact_lookups.day = EXCLUDED.day THEN contact_lookups.hashes + EXCLUDED.hashes ELSE EXCLUDED.hashes END,
+    day = EXCLUDED.day,
+    updated_at = NOW()
+WHERE contact_lookups.day <> EXCLUDED.day OR contact_lookups.hashes + EXCLUDED.hashes <= sqlc.arg(max_hashes)::int
+RETURNING hashes;
@@ -0,0 +1,52 @@
+-- name: UpsertConversationSummary :exec
+-- Ignora reentregas (mesma mensagem) e mensagens mais antigas que a atual
+INSERT INTO conversation_summaries (user_id, peer_id, last_message_id, last_message_preview, last_message_at, unread_count)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (user_id, peer_id) DO UPDATE SET
+    last_message_id = EXCLUDED.last_message_id,
+    last_message_preview = EXCLUDED.last_message_preview,
+    last_message_at = EXCLUDED.last_message_at,
+    unread_count = conversation_summaries.unread_count + EXCLUDED.unread_count,
+    updated_at = NOW()
+WHERE conversation_summaries.last_message_id IS DISTINCT FROM EXCLUDED.last_message_id
+  AND conversation_summaries.last_message_at <= EXCLUDED.last_message_at;
+
+-- name: MarkConversationRead :exec
+UPDATE conversation_summaries
+SET unread_count = 0, last_read_message_id = $3, last_read_at = NOW(), updated_at = NOW()
+WHERE user_id = $1 AND peer_id = $2;
+
+-- name: ListConversationSummaries :many
+SELECT * FROM conversation_summaries
+WHERE user_id = $1
+ORDER BY last_message_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: GetConversationListVersion :one
+-- Versão da lista de conversas (ETag): muda a cada resumo gravado pelo consumidor ou removido
+SELECT COUNT(*) AS conversations,
+       COALESCE(MAX(updated_at), '-infinity'::timestamp)::timestamp AS last_updated_at
+FROM conversation_summaries
+WHERE user_id = $1;
+
+-- name: ResetConversationSummaries :execrows
+-- Esvazia a última mensagem para a reconstrução preencher de novo, mantendo as marcações de leitura
+UPDATE conversation_summaries
+SET last_message_id = NULL, last_message_preview = '', last_message_at = '-infinity', unread_count = 0, updated_at = NOW();
+
+-- name: DeleteEmptyConversationSummaries :execrows
+-- Resumos que a reconstrução não preencheu (nenhum evento da conversa no tópico)
+DELETE FROM conversation_summaries WHERE last_message_id IS NULL;
+
+-- name: RecomputeUnreadCounts :execrows
+-- Não lidas = mensagens do par posteriores à última lida; remetente em shadow ban não conta
+UPDATE conversation_summaries cs
+SET unread_count = (
+    SELECT COUNT(*) FROM messages m
+    INNER JOIN users s ON s.id = m.sender_id
+    WHERE m.receiver_id = cs.user_id AND m.sender_id = cs.peer_id
+      AND s.shadow_banned_at IS NULL
+      AND m.created_at > COALESCE(
+          (SELECT r.created_at FROM messages r WHERE r.id = cs.last_read_message_id LIMIT 1),
+          '-infinity'::timestamp)
+), updated_at = NOW();
@@ -0,0 +1,19 @@
+-- name: GetDNDSettings :one
+SELECT * FROM user_dnd_settings WHERE user_id = $1;
+
+-- name: UpsertDNDSchedule :one
+INSERT INTO user_dnd_settings (user_id, timezone, windows)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE SET
+    timezone = EXCLUDED.timezone,
+    windows = EXCLUDED.windows,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: UpsertDNDSnooze :one
+INSERT INTO user_dnd_settings (user_id, snoozed_until)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET
+    snoozed_until = EXCLUDED.snoozed_until,
+    updated_at = NOW()
+RETURNING *;
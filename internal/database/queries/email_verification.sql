@@ -0,0 +1,24 @@
+-- name: CreateEmailVerificationToken :one
+INSERT INTO email_verification_tokens (user_id, token_hash, email, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetPendingEmailVerificationToken :one
+SELECT * FROM email_verification_tokens
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW();
+
+-- name: GetLatestEmailVerificationToken :one
+-- Último link enviado ao usuário (intervalo mínimo entre reenvios)
+SELECT * FROM email_verification_tokens
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: MarkEmailVerificationTokenUsed :execrows
+UPDATE email_verification_tokens SET used_at = NOW()
+WHERE id = $1 AND used_at IS NULL;
+
+-- name: InvalidateEmailVerificationTokens :exec
+-- Reenvio: só o link mais recente vale
+UPDATE email_verification_tokens SET used_at = NOW()
+WHERE user_id = $1 AND used_at IS NULL;
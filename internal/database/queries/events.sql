@@ -0,0 +1,26 @@
+-- Trava transacional serializa as inserções: ids são confirmados em ordem e o
+-- consumidor, que avança por id, nunca pula um evento ainda não confirmado
+-- name: AppendEvent :exec
+WITH serialized AS (SELECT pg_advisory_xact_lock(hashtext('event_log')))
+INSERT INTO event_log (topic, key, value, headers)
+SELECT sqlc.arg(topic)::varchar, sqlc.arg(key)::text, sqlc.arg(value)::bytea, sqlc.arg(headers)::jsonb FROM serialized;
+
+-- name: ListEventsAfter :many
+SELECT * FROM event_log
+WHERE topic = sqlc.arg(topic) AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(batch_size);
+
+-- name: GetConsumerOffset :one
+SELECT last_event_id FROM event_consumer_offsets
+WHERE consumer_group = $1 AND topic = $2;
+
+-- name: SaveConsumerOffset :exec
+INSERT INTO event_consumer_offsets (consumer_group, topic, last_event_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (consumer_group, topic) DO UPDATE SET
+    last_event_id = EXCLUDED.last_event_id,
+    updated_at = NOW();
+
+-- name: PruneEvents :execrows
+DELETE FROM event_log WHERE created_at < sqlc.arg(created_before);
@@ -8,14 +8,27 @@ SELECT * FROM friendships
 WHERE (user_id = $1 AND friend_id = $2)
    OR (user_id = $2 AND friend_id = $1);
 
--- name: UpdateFriendshipStatus :exec
-UPDATE friendships SET status = $2 WHERE id = $1;
+-- name: RequestFriendship :one
+-- Upsert no par canônico: sem linha cria o pedido (pending); pedido pendente
+-- no sentido inverso significa que os dois pediram, então vira accepted.
+-- Sem linha retornada = pedido repetido ou já são amigos
+INSERT INTO friendships (user_id, friend_id, status)
+VALUES ($1, $2, 'pending')
+ON CONFLICT ((LEAST(user_id, friend_id)), (GREATEST(user_id, friend_id))) DO UPDATE
+SET status = 'accepted', version = friendships.version + 1
+WHERE friendships.status = 'pending' AND friendships.user_id = EXCLUDED.friend_id
+RETURNING *;
+
+-- name: UpdateFriendshipStatus :execrows
+-- Compare-and-set: 0 linhas = outra escrita avançou a versão desde a leitura
+UPDATE friendships SET status = $2, version = version + 1
+WHERE id = $1 AND version = $3;
 
 -- name: ListUserFriends :many
 SELECT u.* FROM users u
 INNER JOIN friendships f ON u.id = f.friend_id
-WHERE f.user_id = $1 AND f.status = 'accepted'
+WHERE f.user_id = $1 AND f.status = 'accepted' AND u.deleted_at IS NULL
 UNION
 SELECT u.* FROM users u
 INNER JOIN friendships f ON u.id = f.user_id
-WHERE f.friend_id = $1 AND f.status = 'accepted';
\ No newline at end of file
+WHERE f.friend_id = $1 AND f.status = 'accepted' AND u.deleted_at IS NULL;
\ No newline at end of file
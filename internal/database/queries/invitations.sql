@@ -0,0 +1,21 @@
+-- name: CreateInvitation :one
+INSERT INTO invitations (inviter_id, email, token_hash, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetValidInvitationByTokenHash :one
+SELECT * FROM invitations
+WHERE token_hash = $1 AND expires_at > NOW() AND accepted_at IS NULL;
+
+-- name: MarkInvitationAccepted :exec
+UPDATE invitations SET accepted_at = NOW() WHERE id = $1;
+
+-- name: CreateReferral :exec
+INSERT INTO referrals (invitee_id, inviter_id, invitation_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (invitee_id) DO NOTHING;
+
+-- name: GetReferralStats :one
+SELECT
+    (SELECT COUNT(*) FROM invitations i WHERE i.inviter_id = $1)::int AS invitations_sent,
+    (SELECT COUNT(*) FROM referrals r WHERE r.inviter_id = $1)::int AS referrals_accepted;
@@ -0,0 +1,26 @@
+-- name: CreateLegalHold :one
+-- Sem linha = já existe retenção ativa para o alvo
+INSERT INTO legal_holds (user_id, peer_id, reason)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, COALESCE(peer_id, user_id)) WHERE released_at IS NULL DO NOTHING
+RETURNING *;
+
+-- name: ListLegalHolds :many
+SELECT * FROM legal_holds
+WHERE NOT sqlc.arg(active_only)::bool OR released_at IS NULL
+ORDER BY created_at DESC;
+
+-- name: ReleaseLegalHold :one
+UPDATE legal_holds SET released_at = NOW()
+WHERE id = $1 AND released_at IS NULL
+RETURNING *;
+
+-- name: UserHasActiveLegalHold :one
+-- Qualquer retenção ativa com o usuário (dele ou de uma conversa dele)
+SELECT EXISTS (
+    SELECT 1 FROM legal_holds
+    WHERE released_at IS NULL AND (user_id = $1 OR peer_id = $1)
+)::bool AS held;
+
+-- name: CountActiveLegalHolds :one
+SELECT COUNT(*) FROM legal_holds WHERE released_at IS NULL;
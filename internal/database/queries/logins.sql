@@ -0,0 +1,42 @@
+-- name: GetUserDevice :one
+SELECT * FROM user_devices
+WHERE user_id = $1 AND fingerprint = $2;
+
+-- name: CreateUserDevice :one
+INSERT INTO user_devices (user_id, fingerprint, user_agent)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, fingerprint) DO UPDATE SET last_seen_at = NOW()
+RETURNING *;
+
+-- name: TouchUserDevice :exec
+UPDATE user_devices SET last_seen_at = NOW(), user_agent = $2
+WHERE id = $1;
+
+-- name: CountUserLogins :one
+SELECT COUNT(*)::int FROM login_events WHERE user_id = $1;
+
+-- name: HasLoginFromCountry :one
+SELECT EXISTS(
+    SELECT 1 FROM login_events WHERE user_id = $1 AND country = $2
+) AS known;
+
+-- name: CreateLoginEvent :one
+INSERT INTO login_events (
+    user_id, device_id, refresh_token_id, ip, country, city,
+    new_device, new_location, alert_token_hash
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: GetLoginEventByAlertTokenHash :one
+SELECT * FROM login_events
+WHERE alert_token_hash = $1 AND reported_at IS NULL;
+
+-- name: MarkLoginEventReported :exec
+UPDATE login_events SET reported_at = NOW() WHERE id = $1;
+
+-- name: GetLastLoginEvent :one
+SELECT * FROM login_events
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT 1;
@@ -0,0 +1,2 @@
+-- name: CreateMessagesPartition :one
+SELECT create_messages_partition(sqlc.arg(month)::date)::text AS partition_name;
@@ -1,17 +1,59 @@
 -- name: CreateMessage :one
-INSERT INTO messages (sender_id, receiver_id, content, status)
-VALUES ($1, $2, $3, $4)
+INSERT INTO messages (id, sender_id, receiver_id, content, status, client_sent_at)
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING *;
 
 -- name: GetMessageByID :one
 SELECT * FROM messages WHERE id = $1;
 
--- name: ListMessagesBetweenUsers :many
+-- name: GetConversationMessageCreatedAt :one
+-- Conversa identificada pelo par simétrico: user_a/user_b em qualquer ordem
+SELECT created_at FROM messages
+WHERE id = sqlc.arg(id)
+  AND LEAST(sender_id, receiver_id) = LEAST(sqlc.arg(user_a)::uuid, sqlc.arg(user_b)::uuid)
+  AND GREATEST(sender_id, receiver_id) = GREATEST(sqlc.arg(user_a)::uuid, sqlc.arg(user_b)::uuid)
+  AND created_at BETWEEN sqlc.arg(from_time)::timestamp AND sqlc.arg(to_time)::timestamp;
+
+-- name: ListConversationMessages :many
+-- Os dois sentidos da conversa, mais recentes primeiro; user_a/user_b em
+-- qualquer ordem (mesmo resultado dos dois lados). since = corte da
+-- retenção do plano (-infinity = histórico completo)
+SELECT * FROM messages
+WHERE LEAST(sender_id, receiver_id) = LEAST(sqlc.arg(user_a)::uuid, sqlc.arg(user_b)::uuid)
+  AND GREATEST(sender_id, receiver_id) = GREATEST(sqlc.arg(user_a)::uuid, sqlc.arg(user_b)::uuid)
+  AND created_at >= sqlc.arg(since)::timestamp
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListConversationMessagesBefore :many
+-- Página anterior ao cursor (created_at, id), mesma ordem de ListConversationMessages
 SELECT * FROM messages
-WHERE (sender_id = $1 AND receiver_id = $2)
-   OR (sender_id = $2 AND receiver_id = $1)
-ORDER BY created_at DESC
-LIMIT $3 OFFSET $4;
+WHERE LEAST(sender_id, receiver_id) = LEAST(sqlc.arg(user_a)::uuid, sqlc.arg(user_b)::uuid)
+  AND GREATEST(sender_id, receiver_id) = GREATEST(sqlc.arg(user_a)::uuid, sqlc.arg(user_b)::uuid)
+  AND (created_at, id) < (sqlc.arg(before_created_at)::timestamp, sqlc.arg(before_id)::uuid)
+  AND created_at >= sqlc.arg(since)::timestamp
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: UpdateMessageStatus :execrows
+-- Compare-and-set: 0 linhas = outra escrita avançou a versão desde a leitura
+UPDATE messages SET status = $2, version = version + 1
+WHERE id = $1 AND version = $3;
+
+-- name: MarkMessageDelivered :one
+-- Só avança de accepted; sem linha = já entregue/lida, de outro destinatário ou inexistente
+UPDATE messages SET status = 'delivered', delivered_at = NOW(), version = version + 1
+WHERE id = $1 AND receiver_id = $2 AND status = 'accepted'
+RETURNING *;
+
+-- name: MarkMessageRead :one
+-- Leitura implica entrega: delivered_at é preenchido se ainda faltava.
+-- Compare-and-set na versão lida ao validar a transição
+UPDATE messages SET status = 'read', read_at = NOW(), delivered_at = COALESCE(delivered_at, NOW()), version = version + 1
+WHERE id = $1 AND receiver_id = $2 AND status IN ('accepted', 'delivered') AND version = $3
+RETURNING *;
 
--- name: UpdateMessageStatus :exec
-UPDATE messages SET status = $2 WHERE id = $1;
\ No newline at end of file
+-- name: CreateMessageMention :exec
+INSERT INTO message_mentions (message_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
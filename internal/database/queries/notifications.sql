@@ -0,0 +1,14 @@
+-- name: CreateNotification :one
+INSERT INTO notifications (user_id, kind, payload)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: ListUserNotifications :many
+SELECT * FROM notifications
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
+
+-- name: MarkNotificationRead :execrows
+UPDATE notifications SET read_at = NOW()
+WHERE id = $1 AND user_id = $2 AND read_at IS NULL;
@@ -0,0 +1,24 @@
+-- name: CreatePasswordResetToken :one
+INSERT INTO password_reset_tokens (user_id, token_hash, email, ip, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetPendingPasswordResetToken :one
+SELECT * FROM password_reset_tokens
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW();
+
+-- name: GetLatestPasswordResetToken :one
+-- Último pedido do usuário (intervalo mínimo entre pedidos)
+SELECT * FROM password_reset_tokens
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: MarkPasswordResetTokenUsed :execrows
+UPDATE password_reset_tokens SET used_at = NOW()
+WHERE id = $1 AND used_at IS NULL;
+
+-- name: InvalidatePasswordResetTokens :exec
+-- Novo pedido ou senha redefinida: links pendentes deixam de valer
+UPDATE password_reset_tokens SET used_at = NOW()
+WHERE user_id = $1 AND used_at IS NULL;
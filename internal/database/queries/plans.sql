@@ -0,0 +1,30 @@
+-- name: GetUserPlan :one
+SELECT * FROM user_plans WHERE user_id = $1;
+
+-- name: ApplyBillingPlan :execrows
+-- Evento mais antigo que o último aplicado não sobrescreve (webhooks fora de ordem)
+INSERT INTO user_plans (
+    user_id, plan, status, billing_provider, billing_customer_id,
+    billing_subscription_id, current_period_end, billing_event_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (user_id) DO UPDATE
+SET plan = EXCLUDED.plan,
+    status = EXCLUDED.status,
+    billing_provider = EXCLUDED.billing_provider,
+    billing_customer_id = EXCLUDED.billing_customer_id,
+    billing_subscription_id = EXCLUDED.billing_subscription_id,
+    current_period_end = EXCLUDED.current_period_end,
+    billing_event_at = EXCLUDED.billing_event_at,
+    updated_at = NOW()
+WHERE user_plans.billing_event_at IS NULL OR user_plans.billing_event_at <= EXCLUDED.billing_event_at;
+
+-- name: SetManualPlan :one
+INSERT INTO user_plans (user_id, plan, status, current_period_end)
+VALUES ($1, $2, 'manual', $3)
+ON CONFLICT (user_id) DO UPDATE
+SET plan = EXCLUDED.plan,
+    status = 'manual',
+    current_period_end = EXCLUDED.current_period_end,
+    updated_at = NOW()
+RETURNING *;
@@ -0,0 +1,14 @@
+-- name: GetUserPreferences :one
+SELECT * FROM user_preferences WHERE user_id = $1;
+
+-- name: SaveUserPreferences :one
+-- Primeira gravação cria a linha (versão 1); depois só grava se a versão lida
+-- ainda for a atual (sem linha = outra escrita venceu)
+INSERT INTO user_preferences (user_id, prefs)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET
+    prefs = EXCLUDED.prefs,
+    version = user_preferences.version + 1,
+    updated_at = NOW()
+WHERE user_preferences.version = $3
+RETURNING *;
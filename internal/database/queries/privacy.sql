@@ -0,0 +1,12 @@
+-- name: GetPrivacySettings :one
+SELECT * FROM user_privacy_settings WHERE user_id = $1;
+
+-- name: UpsertPrivacySettings :one
+INSERT INTO user_privacy_settings (user_id, message_policy, presence_visibility, share_read_receipts)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id) DO UPDATE SET
+    message_policy = EXCLUDED.message_policy,
+    presence_visibility = EXCLUDED.presence_visibility,
+    share_read_receipts = EXCLUDED.share_read_receipts,
+    updated_at = NOW()
+RETURNING *;
@@ -0,0 +1,53 @@
+-- Removidos, contas em shadow ban e o próprio usuário não aparecem;
+-- username exato primeiro, depois por similaridade
+-- name: SearchUsers :many
+SELECT u.id, u.username, u.created_at,
+    EXISTS (
+        SELECT 1 FROM friendships f
+        WHERE f.status = 'accepted'
+          AND ((f.user_id = sqlc.arg(viewer_id) AND f.friend_id = u.id)
+            OR (f.friend_id = sqlc.arg(viewer_id) AND f.user_id = u.id))
+    )::bool AS is_friend
+FROM users u
+WHERE u.deleted_at IS NULL
+  AND u.shadow_banned_at IS NULL
+  AND u.id <> sqlc.arg(viewer_id)
+  AND u.username ILIKE sqlc.arg(pattern)
+ORDER BY (LOWER(u.username) = LOWER(sqlc.arg(query)::text)) DESC,
+    similarity(u.username, sqlc.arg(query)::text) DESC, u.username
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- Conversas do usuário cujo par (nome da conversa) casa com o padrão
+-- name: SearchConversations :many
+SELECT cs.peer_id, u.username AS peer_username, cs.last_message_preview, cs.last_message_at, cs.unread_count
+FROM conversation_summaries cs
+INNER JOIN users u ON u.id = cs.peer_id
+WHERE cs.user_id = sqlc.arg(user_id)
+  AND u.username ILIKE sqlc.arg(pattern)
+ORDER BY cs.last_message_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- Só mensagens de que o usuário participa; as de remetentes em shadow ban
+-- ficam ocultas para os demais (como no histórico)
+-- name: SearchMessages :many
+SELECT m.id, m.sender_id, m.receiver_id, m.content, m.status, m.created_at,
+    (s.deleted_at IS NOT NULL)::bool AS sender_deleted
+FROM messages m
+INNER JOIN users s ON s.id = m.sender_id
+WHERE (m.sender_id = sqlc.arg(user_id) OR m.receiver_id = sqlc.arg(user_id))
+  AND (s.shadow_banned_at IS NULL OR m.sender_id = sqlc.arg(user_id))
+  AND to_tsvector('simple', m.content) @@ websearch_to_tsquery('simple', sqlc.arg(query))
+ORDER BY ts_rank(to_tsvector('simple', m.content), websearch_to_tsquery('simple', sqlc.arg(query))) DESC,
+    m.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- Carrega os resultados do índice externo com as mesmas regras de visibilidade
+-- de SearchMessages (o índice pode estar defasado)
+-- name: ListSearchMessagesByIDs :many
+SELECT m.id, m.sender_id, m.receiver_id, m.content, m.status, m.created_at,
+    (s.deleted_at IS NOT NULL)::bool AS sender_deleted
+FROM messages m
+INNER JOIN users s ON s.id = m.sender_id
+WHERE m.id = ANY(sqlc.arg(ids)::uuid[])
+  AND (m.sender_id = sqlc.arg(user_id) OR m.receiver_id = sqlc.arg(user_id))
+  AND (s.shadow_banned_at IS NULL OR m.sender_id = sqlc.arg(user_id));
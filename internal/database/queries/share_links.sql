@@ -0,0 +1,18 @@
+-- name: CreateShareLink :one
+INSERT INTO conversation_share_links (owner_id, peer_id, token_hash, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetActiveShareLinkByTokenHash :one
+SELECT * FROM conversation_share_links
+WHERE token_hash = $1 AND expires_at > NOW() AND revoked_at IS NULL;
+
+-- name: ListShareLinks :many
+-- Links ainda válidos do dono para a conversa
+SELECT * FROM conversation_share_links
+WHERE owner_id = $1 AND peer_id = $2 AND expires_at > NOW() AND revoked_at IS NULL
+ORDER BY created_at DESC;
+
+-- name: RevokeShareLink :execrows
+UPDATE conversation_share_links SET revoked_at = NOW()
+WHERE id = $1 AND owner_id = $2 AND revoked_at IS NULL;
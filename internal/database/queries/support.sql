@@ -0,0 +1,71 @@
+-- name: AddSupportAgent :exec
+INSERT INTO support_agents (user_id) VALUES ($1)
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveSupportAgent :execrows
+DELETE FROM support_agents WHERE user_id = $1;
+
+-- name: IsSupportAgent :one
+SELECT EXISTS (SELECT 1 FROM support_agents WHERE user_id = $1)::bool AS agent;
+
+-- name: ListSupportAgents :many
+SELECT u.id, u.username, sa.created_at
+FROM support_agents sa
+INNER JOIN users u ON u.id = sa.user_id
+ORDER BY u.username;
+
+-- name: CreateSupportTicket :one
+INSERT INTO support_tickets (customer_id, subject, first_response_due_at, resolution_due_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetSupportTicket :one
+SELECT * FROM support_tickets WHERE id = $1;
+
+-- name: GetActiveSupportTicket :one
+SELECT * FROM support_tickets WHERE customer_id = $1 AND status <> 'closed';
+
+-- name: ListSupportTickets :many
+-- Fila dos agentes: filtros opcionais de status e responsável (unassigned = sem agente)
+SELECT * FROM support_tickets
+WHERE (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status))
+  AND (sqlc.narg(agent_id)::uuid IS NULL OR agent_id = sqlc.narg(agent_id))
+  AND (NOT sqlc.arg(unassigned)::bool OR agent_id IS NULL)
+ORDER BY created_at
+LIMIT $1 OFFSET $2;
+
+-- name: ListCustomerSupportTickets :many
+SELECT * FROM support_tickets
+WHERE customer_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
+
+-- name: ClaimSupportTicket :one
+-- Só chamados na fila: dois agentes não assumem o mesmo
+UPDATE support_tickets SET agent_id = $2, updated_at = NOW()
+WHERE id = $1 AND agent_id IS NULL AND status <> 'closed'
+RETURNING *;
+
+-- name: UpdateSupportTicketStatus :one
+UPDATE support_tickets
+SET status = sqlc.arg(status)::text,
+    closed_at = CASE WHEN sqlc.arg(status)::text = 'closed' THEN NOW() END,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: MarkSupportTicketResponded :exec
+-- Primeira resposta de agente (SLA); respostas seguintes só atualizam updated_at
+UPDATE support_tickets
+SET first_response_at = COALESCE(first_response_at, NOW()), updated_at = NOW()
+WHERE id = $1;
+
+-- name: CreateSupportTicketNote :one
+INSERT INTO support_ticket_notes (ticket_id, author_id, content)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: ListSupportTicketNotes :many
+SELECT * FROM support_ticket_notes
+WHERE ticket_id = $1
+ORDER BY created_at;
@@ -0,0 +1,37 @@
+-- name: UpsertSupportAccessGrant :one
+INSERT INTO support_access_grants (user_id, expires_at)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET expires_at = EXCLUDED.expires_at, created_at = NOW()
+RETURNING *;
+
+-- name: GetActiveSupportAccessGrant :one
+SELECT * FROM support_access_grants
+WHERE user_id = $1 AND expires_at > NOW();
+
+-- name: DeleteSupportAccessGrant :execrows
+DELETE FROM support_access_grants WHERE user_id = $1;
+
+-- name: CreateImpersonationSession :one
+INSERT INTO impersonation_sessions (user_id, operator, reason, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetActiveImpersonationSession :one
+SELECT * FROM impersonation_sessions
+WHERE id = $1 AND revoked_at IS NULL AND expires_at > NOW();
+
+-- name: ListUserImpersonationSessions :many
+SELECT * FROM impersonation_sessions
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
+
+-- name: RevokeImpersonationSession :one
+UPDATE impersonation_sessions SET revoked_at = NOW()
+WHERE id = $1 AND revoked_at IS NULL
+RETURNING *;
+
+-- name: RevokeUserImpersonationSessions :execrows
+-- Usuário retirou a autorização: sessões em andamento terminam junto
+UPDATE impersonation_sessions SET revoked_at = NOW()
+WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW();
@@ -1,14 +1,17 @@
 -- name: CreateRefreshToken :one
-INSERT INTO refresh_tokens (user_id, token, expires_at)
+INSERT INTO refresh_tokens (user_id, token_hash, expires_at)
 VALUES ($1, $2, $3)
 RETURNING *;
 
 -- name: GetRefreshToken :one
 SELECT * FROM refresh_tokens
-WHERE token = $1 AND expires_at > NOW();
+WHERE token_hash = $1 AND expires_at > NOW();
 
 -- name: DeleteRefreshToken :exec
-DELETE FROM refresh_tokens WHERE token = $1;
+DELETE FROM refresh_tokens WHERE token_hash = $1;
 
 -- name: DeleteUserRefreshTokens :exec
-DELETE FROM refresh_tokens WHERE user_id = $1;
\ No newline at end of file
+DELETE FROM refresh_tokens WHERE user_id = $1;
+
+-- name: DeleteRefreshTokenByID :exec
+DELETE FROM refresh_tokens WHERE id = $1;
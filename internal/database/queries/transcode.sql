@@ -0,0 +1,29 @@
+-- name: CreateTranscodeJob :exec
+INSERT INTO transcode_jobs (attachment_id)
+VALUES ($1)
+ON CONFLICT (attachment_id) DO NOTHING;
+
+-- Reserva um lote; jobs presos em 'running' (worker caiu) voltam depois de stale_before
+-- name: ClaimTranscodeJobs :many
+UPDATE transcode_jobs
+SET status = 'running', attempts = attempts + 1, started_at = NOW(), updated_at = NOW()
+WHERE attachment_id IN (
+    SELECT j.attachment_id FROM transcode_jobs j
+    WHERE (j.status = 'pending' OR (j.status = 'running' AND j.updated_at < sqlc.arg(stale_before)))
+    ORDER BY j.created_at
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteTranscodeJob :exec
+UPDATE transcode_jobs
+SET status = 'done', error = NULL, finished_at = NOW(), updated_at = NOW()
+WHERE attachment_id = $1;
+
+-- Volta para a fila ou falha de vez ao atingir max_attempts
+-- name: FailTranscodeJob :exec
+UPDATE transcode_jobs
+SET status = CASE WHEN attempts >= sqlc.arg(max_attempts)::int THEN 'failed' ELSE 'pending' END,
+    error = sqlc.arg(error), updated_at = NOW()
+WHERE attachment_id = sqlc.arg(attachment_id);
@@ -0,0 +1,22 @@
+-- name: CreateUploadSession :one
+INSERT INTO upload_sessions (attachment_id, user_id, upload_length, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetUploadSession :one
+SELECT * FROM upload_sessions WHERE attachment_id = $1;
+
+-- Avança o offset apenas se ninguém escreveu antes (PATCH concorrente)
+-- name: AdvanceUploadSession :execrows
+UPDATE upload_sessions
+SET upload_offset = sqlc.arg(new_offset), expires_at = sqlc.arg(expires_at), updated_at = NOW()
+WHERE attachment_id = sqlc.arg(attachment_id) AND upload_offset = sqlc.arg(current_offset);
+
+-- name: DeleteUploadSession :exec
+DELETE FROM upload_sessions WHERE attachment_id = $1;
+
+-- name: ListExpiredUploadAttachments :many
+SELECT * FROM attachments
+WHERE id IN (SELECT attachment_id FROM upload_sessions WHERE expires_at < sqlc.arg(cutoff))
+ORDER BY created_at
+LIMIT sqlc.arg(batch_size);
@@ -0,0 +1,13 @@
+-- name: ConsumeDailyMessage :one
+-- Conta uma mensagem se o dia ainda não chegou ao limite; sem linha = cota esgotada
+INSERT INTO user_usage (user_id, day, messages)
+VALUES ($1, $2, 1)
+ON CONFLICT (user_id) DO UPDATE
+SET messages = CASE WHEN user_usage.day = EXCLUDED.day THEN user_usage.messages + 1 ELSE 1 END,
+    day = EXCLUDED.day,
+    updated_at = NOW()
+WHERE user_usage.day <> EXCLUDED.day OR user_usage.messages < sqlc.arg(max_messages)::int
+RETURNING messages;
+
+-- name: GetDailyMessages :one
+SELECT COALESCE((SELECT messages FROM user_usage WHERE user_id = $1 AND day = $2), 0)::int AS messages;
@@ -4,15 +4,86 @@ VALUES ($1, $2, $3)
 RETURNING *;
 
 -- name: GetUserByID :one
-SELECT * FROM users WHERE id = $1;
+SELECT * FROM users WHERE id = $1 AND deleted_at IS NULL;
 
 -- name: GetUserByEmail :one
-SELECT * FROM users WHERE email = $1;
+SELECT * FROM users WHERE email = $1 AND deleted_at IS NULL;
 
 -- name: GetUserByUsername :one
-SELECT * FROM users WHERE username = $1;
+-- Sem diferenciar maiúsculas (índice único em LOWER(username))
+SELECT * FROM users WHERE LOWER(username) = LOWER(sqlc.arg(username)) AND deleted_at IS NULL;
+
+-- name: GetUsersByIDs :many
+-- Busca em lote (lista de membros); IDs inexistentes ou excluídos ficam de fora
+SELECT * FROM users
+WHERE id = ANY(sqlc.arg(ids)::uuid[]) AND deleted_at IS NULL;
 
 -- name: ListUsers :many
 SELECT * FROM users
+WHERE deleted_at IS NULL
 ORDER BY created_at DESC
-LIMIT $1 OFFSET $2;
\ No newline at end of file
+LIMIT $1 OFFSET $2;
+
+-- name: GetUserByIDIncludingDeleted :one
+SELECT * FROM users WHERE id = $1;
+
+-- name: SoftDeleteUser :execrows
+UPDATE users SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: PurgeDeletedUsers :execrows
+-- DELETED_USER_MESSAGES=hide: apaga de vez contas removidas antes do cutoff
+-- (fim da carência); mensagens, sessões e demais dados saem em cascata.
+-- Conta com upload ainda no armazenamento espera a coleta de anexos
+DELETE FROM users d
+WHERE d.deleted_at < sqlc.arg(cutoff) AND d.id IN (
+  SELECT u.id FROM users u
+  WHERE u.deleted_at < sqlc.arg(cutoff)
+    AND NOT EXISTS (SELECT 1 FROM attachments a WHERE a.uploader_id = u.id)
+    AND NOT user_purge_blocked(u.id)
+  ORDER BY u.deleted_at
+  LIMIT sqlc.arg(batch_size)
+);
+
+-- name: AnonymizeDeletedUsers :one
+-- DELETED_USER_MESSAGES=anonymize: a conta fica, sem identidade (ver migração 042)
+SELECT anonymize_deleted_users(sqlc.arg(cutoff)::timestamp, sqlc.arg(batch_size)::int)::int AS anonymized;
+
+-- name: RestoreUser :execrows
+-- Só restaura dentro do período de carência (deleted_at posterior ao cutoff)
+UPDATE users SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL AND deleted_at > sqlc.arg(cutoff);
+
+-- name: UpdateUsername :exec
+UPDATE users SET username = $2, username_changed_at = NOW() WHERE id = $1;
+
+-- name: CreateUsernameHistory :exec
+INSERT INTO username_history (user_id, old_username, reserved_until)
+VALUES ($1, $2, $3);
+
+-- name: GetActiveUsernameReservation :one
+SELECT * FROM username_history
+WHERE LOWER(old_username) = LOWER(sqlc.arg(old_username)) AND reserved_until > NOW()
+ORDER BY changed_at DESC
+LIMIT 1;
+
+-- name: ShadowBanUser :execrows
+UPDATE users SET shadow_banned_at = NOW() WHERE id = $1 AND shadow_banned_at IS NULL;
+
+-- name: LiftShadowBan :execrows
+UPDATE users SET shadow_banned_at = NULL WHERE id = $1 AND shadow_banned_at IS NOT NULL;
+
+-- name: IsUserShadowBanned :one
+SELECT (shadow_banned_at IS NOT NULL)::bool AS banned FROM users WHERE id = $1;
+
+-- name: MarkEmailVerified :execrows
+-- 0 linhas = o email do usuário mudou depois do envio do link
+UPDATE users SET email_verified_at = COALESCE(email_verified_at, NOW())
+WHERE id = $1 AND email = sqlc.arg(email) AND deleted_at IS NULL;
+
+-- name: IsEmailVerified :one
+SELECT (email_verified_at IS NOT NULL)::bool AS verified FROM users WHERE id = $1;
+
+-- name: UpdatePassword :execrows
+-- 0 linhas = o email do usuário mudou depois do envio do link (ou conta removida)
+UPDATE users SET password_hash = $2
+WHERE id = $1 AND email = $3 AND deleted_at IS NULL;
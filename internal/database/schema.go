@@ -0,0 +1,177 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// As migrações são aplicadas por fora (sem tabela de controle); a versão do
+// schema é deduzida dos objetos que cada uma cria
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+var (
+	sqlComment   = regexp.MustCompile(`--[^\n]*`)
+	createTable  = regexp.MustCompile(`(?is)^CREATE TABLE (?:IF NOT EXISTS )?(\w+)`)
+	dropTable    = regexp.MustCompile(`(?is)^DROP TABLE (?:IF EXISTS )?(\w+)`)
+	alterTable   = regexp.MustCompile(`(?is)^ALTER TABLE (?:IF EXISTS )?(?:ONLY )?(\w+)`)
+	addColumn    = regexp.MustCompile(`(?i)ADD COLUMN (?:IF NOT EXISTS )?(\w+)`)
+	renameColumn = regexp.MustCompile(`(?i)RENAME COLUMN (\w+) TO (\w+)`)
+	renameTable  = regexp.MustCompile(`(?i)RENAME TO (\w+)`)
+)
+
+// Migrations migrações embutidas, em ordem pelo nome (testes de integração
+// aplicam todas num schema vazio)
+func Migrations() fs.FS {
+	sub, err := fs.Sub(migrationFiles, "migrations")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
+
+// SchemaStatus versão do schema no banco comparada às migrações embutidas
+type SchemaStatus struct {
+	Expected int      `json:"expected"` // Última migração do binário
+	Current  int      `json:"current"`  // Última migração com todos os objetos presentes
+	Missing  []string `json:"missing,omitempty"`
+}
+
+// schemaObject tabela ("users") ou coluna ("users.deleted_at") criada por uma migração
+type schemaObject struct {
+	name    string
+	version int
+	file    string
+}
+
+// SchemaStatus confere tabelas e colunas criadas pelas migrações contra o
+// information_schema. Migrações que só mudam índices ou constraints não são
+// detectadas (contam como aplicadas se as seguintes estiverem)
+func (db *DB) SchemaStatus(ctx context.Context) (*SchemaStatus, error) {
+	expected, objects, err := expectedSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Pool.Query(ctx, `
+		SELECT table_name, column_name FROM information_schema.columns
+		WHERE table_schema = current_schema()`)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler information_schema: %w", err)
+	}
+	defer rows.Close()
+
+	present := make(map[string]bool)
+	for rows.Next() {
+		var table, column string
+		if err := rows.Scan(&table, &column); err != nil {
+			return nil, err
+		}
+		present[table] = true
+		present[table+"."+column] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	status := &SchemaStatus{Expected: expected, Current: expected}
+	for _, obj := range objects {
+		if present[obj.name] {
+			continue
+		}
+		status.Missing = append(status.Missing, obj.name+" ("+obj.file+")")
+		status.Current = min(status.Current, obj.version-1)
+	}
+	return status, nil
+}
+
+// expectedSchema lê as migrações em ordem e retorna a última versão e os
+// objetos que devem existir ao final (descontando os removidos/renomeados)
+func expectedSchema() (int, []schemaObject, error) {
+	files, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return 0, nil, err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name() < files[j].Name() })
+
+	latest := 0
+	objects := make(map[string]schemaObject)
+	for _, f := range files {
+		prefix, _, _ := strings.Cut(f.Name(), "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return 0, nil, fmt.Errorf("migração sem número: %s", f.Name())
+		}
+		latest = max(latest, version)
+
+		content, err := migrationFiles.ReadFile(path.Join("migrations", f.Name()))
+		if err != nil {
+			return 0, nil, err
+		}
+		add := func(name string) {
+			objects[name] = schemaObject{name: name, version: version, file: f.Name()}
+		}
+
+		// Corpos de função ($$ ... $$) viram pedaços sem CREATE/ALTER no início
+		for _, stmt := range strings.Split(sqlComment.ReplaceAllString(string(content), ""), ";") {
+			stmt = strings.TrimSpace(stmt)
+			if m := createTable.FindStringSubmatch(stmt); m != nil {
+				add(m[1])
+				continue
+			}
+			if m := dropTable.FindStringSubmatch(stmt); m != nil {
+				dropObjects(objects, m[1])
+				continue
+			}
+			m := alterTable.FindStringSubmatch(stmt)
+			if m == nil {
+				continue
+			}
+			table := m[1]
+			for _, col := range addColumn.FindAllStringSubmatch(stmt, -1) {
+				add(table + "." + col[1])
+			}
+			if rc := renameColumn.FindStringSubmatch(stmt); rc != nil {
+				delete(objects, table+"."+rc[1])
+				add(table + "." + rc[2])
+			} else if rt := renameTable.FindStringSubmatch(stmt); rt != nil {
+				for name, obj := range dropObjects(objects, table) {
+					obj.name = rt[1] + strings.TrimPrefix(name, table)
+					objects[obj.name] = obj
+				}
+			}
+		}
+	}
+
+	list := make([]schemaObject, 0, len(objects))
+	for _, obj := range objects {
+		list = append(list, obj)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].version != list[j].version {
+			return list[i].version < list[j].version
+		}
+		return list[i].name < list[j].name
+	})
+	return latest, list, nil
+}
+
+// dropObjects remove a tabela e suas colunas, retornando o que foi removido
+func dropObjects(objects map[string]schemaObject, table string) map[string]schemaObject {
+	removed := make(map[string]schemaObject)
+	for name, obj := range objects {
+		if name == table || strings.HasPrefix(name, table+".") {
+			removed[name] = obj
+			delete(objects, name)
+		}
+	}
+	return removed
+}
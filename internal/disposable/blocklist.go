@@ -0,0 +1,238 @@
+package disposable
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"chat-kafka-go/internal/recovery"
+	"chat-kafka-go/internal/reporter"
+)
+
+//go:embed domains.txt
+var embeddedDomains string
+
+// maxListBytes limite do download da lista remota
+const maxListBytes = 10 << 20
+
+// Blocklist lista de domínios de email descartável verificada no registro.
+// Combina a lista embutida/remota com ajustes feitos em runtime pelo admin
+// (adicionados/removidos), que sobrevivem às atualizações remotas.
+type Blocklist struct {
+	mu      sync.RWMutex
+	base    map[string]struct{} // Lista embutida ou última lista remota
+	added   map[string]struct{} // Adicionados via admin
+	removed map[string]struct{} // Liberados via admin
+
+	url      string
+	interval time.Duration
+	client   *http.Client
+}
+
+// NewBlocklist cria blocklist carregada com a lista embutida
+// url vazia desabilita a atualização remota
+func NewBlocklist(url string, interval time.Duration) *Blocklist {
+	base, _ := parseDomains(strings.NewReader(embeddedDomains))
+	return &Blocklist{
+		base:     base,
+		added:    map[string]struct{}{},
+		removed:  map[string]struct{}{},
+		url:      url,
+		interval: interval,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// IsDisposable verifica se o domínio do email está bloqueado
+// Subdomínios também são bloqueados (ex: x.mailinator.com)
+func (b *Blocklist) IsDisposable(email string) bool {
+	at := strings.LastIndexByte(email, '@')
+	if at < 0 {
+		return false
+	}
+	domain := normalize(email[at+1:])
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for domain != "" {
+		if b.blockedLocked(domain) {
+			return true
+		}
+		dot := strings.IndexByte(domain, '.')
+		if dot < 0 {
+			break
+		}
+		domain = domain[dot+1:]
+	}
+	return false
+}
+
+func (b *Blocklist) blockedLocked(domain string) bool {
+	if _, ok := b.removed[domain]; ok {
+		return false
+	}
+	if _, ok := b.added[domain]; ok {
+		return true
+	}
+	_, ok := b.base[domain]
+	return ok
+}
+
+// Add bloqueia domínio em runtime
+func (b *Blocklist) Add(domain string) error {
+	domain = normalize(domain)
+	if !validDomain(domain) {
+		return fmt.Errorf("domínio inválido")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.removed, domain)
+	b.added[domain] = struct{}{}
+	return nil
+}
+
+// Remove libera domínio em runtime (mesmo que esteja na lista base)
+func (b *Blocklist) Remove(domain string) error {
+	domain = normalize(domain)
+	if !validDomain(domain) {
+		return fmt.Errorf("domínio inválido")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.added, domain)
+	b.removed[domain] = struct{}{}
+	return nil
+}
+
+// Domains retorna os domínios bloqueados, ordenados
+func (b *Blocklist) Domains() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	domains := make([]string, 0, len(b.base)+len(b.added))
+	for d := range b.base {
+		if b.blockedLocked(d) {
+			domains = append(domains, d)
+		}
+	}
+	for d := range b.added {
+		if _, inBase := b.base[d]; !inBase {
+			domains = append(domains, d)
+		}
+	}
+	sort.Strings(domains)
+	return domains
+}
+
+// Stats resumo para o admin/dump
+func (b *Blocklist) Stats() map[string]int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return map[string]int{
+		"base":    len(b.base),
+		"added":   len(b.added),
+		"removed": len(b.removed),
+	}
+}
+
+// Refresh baixa a lista remota e substitui a lista base
+func (b *Blocklist) Refresh(ctx context.Context) error {
+	if b.url == "" {
+		return fmt.Errorf("DISPOSABLE_DOMAINS_URL não configurada")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.url, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição: %w", err)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao baixar lista de domínios: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("lista de domínios retornou status %d", resp.StatusCode)
+	}
+
+	base, err := parseDomains(io.LimitReader(resp.Body, maxListBytes))
+	if err != nil {
+		return fmt.Errorf("erro ao ler lista de domínios: %w", err)
+	}
+	if len(base) == 0 {
+		return fmt.Errorf("lista de domínios remota vazia")
+	}
+
+	b.mu.Lock()
+	b.base = base
+	b.mu.Unlock()
+
+	log.Printf("✓ Lista de domínios descartáveis atualizada (%d domínios)", len(base))
+	return nil
+}
+
+// Run atualiza a lista remota imediatamente e depois a cada intervalo
+// Não faz nada se a URL remota não estiver configurada
+func (b *Blocklist) Run(ctx context.Context) {
+	if b.url == "" {
+		return
+	}
+
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		err := recovery.Guard(ctx, "disposable_refresh", func() error {
+			return b.Refresh(ctx)
+		})
+		if err != nil {
+			// Mantém a lista anterior
+			log.Printf("ERRO: atualização de domínios descartáveis: %v", err)
+			reporter.CaptureError(ctx, err, map[string]string{"component": "disposable_refresh"})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// parseDomains lê um domínio por linha, ignorando linhas vazias e comentários
+func parseDomains(r io.Reader) (map[string]struct{}, error) {
+	domains := map[string]struct{}{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		domain := normalize(line)
+		if validDomain(domain) {
+			domains[domain] = struct{}{}
+		}
+	}
+	return domains, scanner.Err()
+}
+
+func normalize(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
+
+func validDomain(domain string) bool {
+	return domain != "" && len(domain) <= 253 && strings.Contains(domain, ".") &&
+		!strings.ContainsAny(domain, " @/")
+}
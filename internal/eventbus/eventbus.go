@@ -0,0 +1,114 @@
+package eventbus
+
+import (
+	"context"
+
+	"chat-kafka-go/internal/reqctx"
+)
+
+// Backends aceitos em EVENT_BUS
+const (
+	BackendKafka    = "kafka"
+	BackendNATS     = "nats"     // JetStream (streams e consumers duráveis)
+	BackendPostgres = "postgres" // LISTEN/NOTIFY + tabela de eventos (sem Kafka)
+	BackendMemory   = "memory"   // Canais em memória (um processo, sem durabilidade)
+)
+
+// Semânticas de entrega aceitas em EVENT_BUS_DELIVERY
+//
+// at-least-once: o publicador espera a confirmação do transporte (com
+// retries) e o consumidor confirma o evento DEPOIS do handler. Um processo
+// que cai no meio reprocessa o evento: handlers precisam ser idempotentes.
+//
+// at-most-once: o publicador não espera confirmação nem tenta de novo e o
+// consumidor confirma ANTES do handler. Nada é processado duas vezes, mas
+// eventos podem se perder (falha de rede, queda durante o handler).
+// Mais vazão e menos latência.
+//
+// exactly-once (só Kafka): publicação e commit de offsets em transações
+// Kafka; consumidores leem só transações confirmadas. Cada evento custa um
+// commit de transação (bem menos vazão). Efeitos fora do Kafka (Postgres)
+// continuam sujeitos a reprocessamento e dependem de handlers idempotentes
+const (
+	DeliveryAtLeastOnce = "at-least-once"
+	DeliveryAtMostOnce  = "at-most-once"
+	DeliveryExactlyOnce = "exactly-once"
+)
+
+// Message evento entregue ao consumidor, independente do transporte
+type Message struct {
+	Topic     string
+	Partition int32 // Só Kafka; demais transportes usam 0
+	Offset    int64 // Posição no transporte (offset Kafka, sequência JetStream, id do evento no Postgres...)
+	Key       string
+	Value     []byte
+}
+
+// Handler processa um evento consumido
+// O contexto já contém request ID, tenant e usuário propagados pelo publicador
+type Handler func(ctx context.Context, msg *Message) error
+
+// Subscriber consome eventos de um tópico em nome de um grupo
+// Cada grupo recebe todos os eventos; dentro do grupo, cada evento é
+// processado uma vez
+type Subscriber interface {
+	Run(ctx context.Context) error
+	Close() error
+}
+
+// Bus transporte de eventos (publicação + assinaturas)
+// Implementado por kafka.Bus, NATSBus, PostgresBus e MemoryBus
+type Bus interface {
+	// SendMessage publica evento (implementa service.KafkaProducer)
+	SendMessage(ctx context.Context, topic, key string, value []byte) error
+	Subscribe(topic, group string, handler Handler, pool PoolOptions) (Subscriber, error)
+	Close() error
+}
+
+// Degradable barramento que informa quando publica sem o transporte (ex.:
+// Kafka fora e eventos no buffer em disco); consultado pelo /readyz e pelo
+// envio de mensagens, que passa a entregar direto às conexões WebSocket
+type Degradable interface {
+	Degraded() bool
+}
+
+// IsDegraded indica se o barramento está em modo degradado (false se não informa)
+func IsDegraded(bus interface{}) bool {
+	d, ok := bus.(Degradable)
+	return ok && d.Degraded()
+}
+
+// Headers propagados do publicador para o consumidor (transportes sem headers nativos)
+const (
+	headerRequestID = "request_id"
+	headerTenantID  = "tenant_id"
+	headerUserID    = "user_id"
+)
+
+// headersFromContext extrai request ID, tenant e usuário do contexto
+func headersFromContext(ctx context.Context) map[string]string {
+	headers := map[string]string{}
+	add := func(key, value string) {
+		if value != "" {
+			headers[key] = value
+		}
+	}
+	add(headerRequestID, reqctx.RequestID(ctx))
+	add(headerTenantID, reqctx.TenantID(ctx))
+	add(headerUserID, reqctx.UserID(ctx))
+	return headers
+}
+
+// contextFromHeaders restaura request ID, tenant e usuário no contexto do consumidor
+func contextFromHeaders(ctx context.Context, headers map[string]string) context.Context {
+	if v := headers[headerRequestID]; v != "" {
+		ctx = reqctx.WithRequestID(ctx, v)
+	}
+	if v := headers[headerTenantID]; v != "" {
+		ctx = reqctx.WithTenantID(ctx, v)
+	}
+	if v := headers[headerUserID]; v != "" {
+		ctx = reqctx.WithUserID(ctx, v)
+	}
+	return ctx
+}
@@ -0,0 +1,128 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+
+	"chat-kafka-go/internal/recovery"
+	"chat-kafka-go/internal/reporter"
+	"chat-kafka-go/internal/reqctx"
+)
+
+// MemoryBus barramento em processo para desenvolvimento local
+// Eventos publicados antes de existir assinante, ou pendentes quando o
+// processo cai, são perdidos (EVENT_BUS_DELIVERY não se aplica)
+type MemoryBus struct {
+	bufferSize int
+
+	mu     sync.RWMutex
+	groups map[string]map[string]*memorySubscriber // tópico -> grupo -> assinante
+	offset atomic.Int64
+}
+
+// NewMemoryBus cria barramento vazio; bufferSize eventos pendentes por grupo
+// antes de SendMessage bloquear
+func NewMemoryBus(bufferSize int) *MemoryBus {
+	log.Println("✓ Barramento de eventos em memória")
+	return &MemoryBus{
+		bufferSize: bufferSize,
+		groups:     map[string]map[string]*memorySubscriber{},
+	}
+}
+
+// memoryEvent evento na fila de um grupo
+type memoryEvent struct {
+	msg     Message
+	headers map[string]string
+}
+
+// SendMessage implementa Bus
+func (b *MemoryBus) SendMessage(ctx context.Context, topic, key string, value []byte) error {
+	event := memoryEvent{
+		msg: Message{
+			Topic:  topic,
+			Offset: b.offset.Add(1),
+			Key:    key,
+			Value:  value,
+		},
+		headers: headersFromContext(ctx),
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, sub := range b.groups[topic] {
+		select {
+		case sub.events <- event:
+		case <-ctx.Done():
+			return fmt.Errorf("falha ao enfileirar evento: %w", ctx.Err())
+		}
+	}
+	return nil
+}
+
+// Subscribe implementa Bus; o mesmo grupo assinado duas vezes é rejeitado
+func (b *MemoryBus) Subscribe(topic, group string, handler Handler, pool PoolOptions) (Subscriber, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.groups[topic] == nil {
+		b.groups[topic] = map[string]*memorySubscriber{}
+	}
+	if _, ok := b.groups[topic][group]; ok {
+		return nil, fmt.Errorf("grupo %s já assina o tópico %s", group, topic)
+	}
+
+	sub := &memorySubscriber{
+		events:  make(chan memoryEvent, b.bufferSize),
+		handler: handler,
+		pool:    pool,
+	}
+	b.groups[topic][group] = sub
+	return sub, nil
+}
+
+// Close implementa Bus
+func (b *MemoryBus) Close() error { return nil }
+
+// memorySubscriber consome a fila de um grupo
+type memorySubscriber struct {
+	events  chan memoryEvent
+	handler Handler
+	pool    PoolOptions
+}
+
+// Run implementa Subscriber
+func (s *memorySubscriber) Run(ctx context.Context) error {
+	workers := NewPool(s.pool, "memory_bus", s.handler)
+	defer workers.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-s.events:
+			workers.Submit(contextFromHeaders(ctx, event.headers), &event.msg, nil)
+		}
+	}
+}
+
+// Close implementa Subscriber
+func (s *memorySubscriber) Close() error { return nil }
+
+// dispatch chama o handler; panic ou erro em um evento não derruba o consumidor
+func dispatch(ctx context.Context, component string, handler Handler, msg *Message) {
+	err := recovery.Guard(ctx, component, func() error {
+		return handler(ctx, msg)
+	})
+	if err != nil {
+		log.Printf("ERRO: evento %s/%d request_id=%s: %v", msg.Topic, msg.Offset, reqctx.RequestID(ctx), err)
+		reporter.CaptureError(ctx, err, map[string]string{
+			"component":  component,
+			"topic":      msg.Topic,
+			"request_id": reqctx.RequestID(ctx),
+		})
+	}
+}
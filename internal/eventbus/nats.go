@@ -0,0 +1,247 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/reporter"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// natsRequestTimeout prazo das chamadas de controle (stream, consumer, publish)
+const natsRequestTimeout = 10 * time.Second
+
+// NATSBus barramento sobre NATS JetStream (EVENT_BUS=nats)
+// Cada tópico vira um stream com subjects "<tópico>.<chave>"; cada grupo, um
+// consumer durável compartilhado entre as instâncias. Mesma semântica do
+// consumer Kafka: ack depois do handler (com ou sem erro), reentrega só se o
+// processo cair antes do ack (ou ack antes do handler em at-most-once). A ordem por chave só é garantida com uma
+// instância por grupo (não há partições fixas como no Kafka)
+type NATSBus struct {
+	conn     *nats.Conn
+	js       jetstream.JetStream
+	cfg      *config.NATSConfig
+	delivery string
+
+	mu      sync.Mutex
+	streams map[string]bool // Tópicos com stream já garantido
+}
+
+// NewNATSBus conecta ao servidor NATS com JetStream habilitado
+func NewNATSBus(cfg *config.NATSConfig, delivery string) (*NATSBus, error) {
+	conn, err := nats.Connect(cfg.URL,
+		nats.Name("chat-kafka-go"),
+		nats.MaxReconnects(-1),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao conectar no NATS: %w", err)
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("falha ao abrir JetStream: %w", err)
+	}
+
+	log.Println("✓ Barramento de eventos NATS JetStream")
+	return &NATSBus{
+		conn:     conn,
+		js:       js,
+		cfg:      cfg,
+		delivery: delivery,
+		streams:  map[string]bool{},
+	}, nil
+}
+
+// SendMessage implementa Bus; em at-least-once retorna após o JetStream
+// persistir o evento, em at-most-once publica sem esperar confirmação
+func (b *NATSBus) SendMessage(ctx context.Context, topic, key string, value []byte) error {
+	if err := b.ensureStream(ctx, topic); err != nil {
+		return err
+	}
+
+	msg := nats.NewMsg(natsSubject(topic, key))
+	msg.Data = value
+	for k, v := range headersFromContext(ctx) {
+		msg.Header.Set(k, v)
+	}
+
+	if b.delivery == DeliveryAtMostOnce {
+		if err := b.conn.PublishMsg(msg); err != nil {
+			return fmt.Errorf("falha ao publicar evento: %w", err)
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, natsRequestTimeout)
+	defer cancel()
+	if _, err := b.js.PublishMsg(ctx, msg); err != nil {
+		return fmt.Errorf("falha ao publicar evento: %w", err)
+	}
+	return nil
+}
+
+// Subscribe implementa Bus (um consumer durável por grupo)
+func (b *NATSBus) Subscribe(topic, group string, handler Handler, pool PoolOptions) (Subscriber, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), natsRequestTimeout)
+	defer cancel()
+
+	if err := b.ensureStream(ctx, topic); err != nil {
+		return nil, err
+	}
+
+	consumer, err := b.js.CreateOrUpdateConsumer(ctx, natsName(topic), jetstream.ConsumerConfig{
+		Durable:       natsName(group),
+		FilterSubject: topic + ".>",
+		DeliverPolicy: jetstream.DeliverAllPolicy,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       b.cfg.AckWait,
+		MaxDeliver:    b.cfg.MaxDeliver,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("falha ao criar consumer %s: %w", group, err)
+	}
+
+	return &natsSubscriber{
+		consumer: consumer,
+		topic:    topic,
+		group:    group,
+		delivery: b.delivery,
+		ackWait:  b.cfg.AckWait,
+		handler:  handler,
+		pool:     pool,
+	}, nil
+}
+
+// Close implementa Bus; entrega o que estiver pendente antes de desconectar
+func (b *NATSBus) Close() error {
+	return b.conn.Drain()
+}
+
+// ensureStream cria (ou atualiza) o stream do tópico na primeira utilização
+func (b *NATSBus) ensureStream(ctx context.Context, topic string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.streams[topic] {
+		return nil
+	}
+
+	_, err := b.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     natsName(topic),
+		Subjects: []string{topic + ".>"},
+		Storage:  jetstream.FileStorage,
+		MaxAge:   b.cfg.MaxAge,
+	})
+	if err != nil {
+		return fmt.Errorf("falha ao criar stream %s: %w", topic, err)
+	}
+	b.streams[topic] = true
+	return nil
+}
+
+// natsSubscriber consome o consumer durável de um grupo
+type natsSubscriber struct {
+	consumer jetstream.Consumer
+	topic    string
+	group    string
+	delivery string
+	ackWait  time.Duration
+	handler  Handler
+	pool     PoolOptions
+}
+
+// Run implementa Subscriber; cada evento é confirmado individualmente, então
+// o processamento em paralelo (por chave) não precisa de confirmação em ordem
+func (s *natsSubscriber) Run(ctx context.Context) error {
+	workers := NewPool(s.pool, "nats_bus", s.handler)
+	defer workers.Close()
+
+	consumeCtx, err := s.consumer.Consume(func(msg jetstream.Msg) {
+		headers := map[string]string{}
+		for k := range msg.Headers() {
+			headers[k] = msg.Headers().Get(k)
+		}
+
+		event := &Message{
+			Topic: s.topic,
+			Key:   strings.TrimPrefix(msg.Subject(), s.topic+"."),
+			Value: msg.Data(),
+		}
+		if meta, err := msg.Metadata(); err == nil {
+			event.Offset = int64(meta.Sequence.Stream)
+		}
+
+		// at-most-once: ack confirmado pelo servidor antes de processar
+		if s.delivery == DeliveryAtMostOnce {
+			if err := msg.DoubleAck(ctx); err != nil {
+				log.Printf("ERRO: ack do evento %s/%d: %v", event.Topic, event.Offset, err)
+				return
+			}
+			workers.Submit(contextFromHeaders(ctx, headers), event, nil)
+			return
+		}
+
+		// Handler longo (retry aguardando, job bulk) renova o prazo de ack
+		finished := make(chan struct{})
+		go s.keepAlive(msg, finished)
+
+		workers.Submit(contextFromHeaders(ctx, headers), event, func() {
+			close(finished)
+			if err := msg.Ack(); err != nil {
+				log.Printf("ERRO: ack do evento %s/%d: %v", event.Topic, event.Offset, err)
+			}
+		})
+	}, jetstream.ConsumeErrHandler(func(_ jetstream.ConsumeContext, err error) {
+		log.Printf("ERRO: consumidor NATS %s: %v", s.group, err)
+		reporter.CaptureError(ctx, err, map[string]string{"component": "nats_bus", "group": s.group})
+	}))
+	if err != nil {
+		return fmt.Errorf("erro no consumer: %w", err)
+	}
+
+	<-ctx.Done()
+	consumeCtx.Stop()
+	<-consumeCtx.Closed() // Nenhum callback em andamento antes de fechar os workers
+	return nil
+}
+
+// Close implementa Subscriber (Run para com o contexto)
+func (s *natsSubscriber) Close() error { return nil }
+
+// keepAlive avisa o servidor que o evento segue em processamento (a cada
+// metade do AckWait) para não ser reentregue antes do ack
+func (s *natsSubscriber) keepAlive(msg jetstream.Msg, finished <-chan struct{}) {
+	if s.ackWait <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.ackWait / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-finished:
+			return
+		case <-ticker.C:
+			_ = msg.InProgress()
+		}
+	}
+}
+
+// natsSubject subject do evento; chave vazia vira "_" (subject não aceita token vazio)
+func natsSubject(topic, key string) string {
+	if key == "" {
+		key = "_"
+	}
+	return topic + "." + natsName(key)
+}
+
+// natsName troca caracteres reservados em nomes de stream, consumer e tokens de subject
+func natsName(s string) string {
+	return strings.NewReplacer(".", "_", "*", "_", ">", "_", " ", "_").Replace(s)
+}
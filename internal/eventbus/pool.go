@@ -0,0 +1,124 @@
+package eventbus
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// PoolOptions paralelismo de uma assinatura
+// Eventos com a mesma chave (conversa) caem sempre no mesmo worker e são
+// processados na ordem de chegada; chaves diferentes andam em paralelo
+type PoolOptions struct {
+	Workers int           // Workers do tópico (<= 1 = sequencial)
+	Buffer  int           // Eventos enfileirados por worker antes de bloquear o consumo
+	Timeout time.Duration // Prazo de cada handler (0 = sem prazo)
+}
+
+// Pool distribui eventos entre workers pela chave
+type Pool struct {
+	component string
+	handler   Handler
+	timeout   time.Duration
+	lanes     []chan poolJob
+	wg        sync.WaitGroup
+}
+
+// poolJob evento na fila de um worker; done é chamado depois do handler
+type poolJob struct {
+	ctx  context.Context
+	msg  *Message
+	done func()
+}
+
+// NewPool inicia os workers; Close espera a fila esvaziar
+func NewPool(opts PoolOptions, component string, handler Handler) *Pool {
+	p := &Pool{
+		component: component,
+		handler:   handler,
+		timeout:   opts.Timeout,
+		lanes:     make([]chan poolJob, max(opts.Workers, 1)),
+	}
+	for i := range p.lanes {
+		p.lanes[i] = make(chan poolJob, opts.Buffer)
+		p.wg.Add(1)
+		go p.work(p.lanes[i])
+	}
+	return p
+}
+
+// Submit enfileira o evento no worker da chave (bloqueia com a fila cheia)
+// done (opcional) é chamado quando o handler termina, com ou sem erro
+func (p *Pool) Submit(ctx context.Context, msg *Message, done func()) {
+	h := fnv.New32a()
+	h.Write([]byte(msg.Key))
+	p.lanes[h.Sum32()%uint32(len(p.lanes))] <- poolJob{ctx: ctx, msg: msg, done: done}
+}
+
+// Close encerra as filas e espera os eventos pendentes
+func (p *Pool) Close() {
+	for _, lane := range p.lanes {
+		close(lane)
+	}
+	p.wg.Wait()
+}
+
+// work processa a fila de um worker em ordem
+func (p *Pool) work(lane chan poolJob) {
+	defer p.wg.Done()
+	for job := range lane {
+		ctx, cancel := job.ctx, context.CancelFunc(func() {})
+		if p.timeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		}
+		dispatch(ctx, p.component, p.handler, job.msg)
+		cancel()
+
+		if job.done != nil {
+			job.done()
+		}
+	}
+}
+
+// OffsetTracker confirma offsets em ordem quando o processamento é paralelo:
+// um offset só é confirmado depois que todos os anteriores terminaram, então
+// uma queda reprocessa no máximo os eventos ainda em voo
+type OffsetTracker struct {
+	mu      sync.Mutex
+	pending []int64 // Offsets em voo, na ordem de chegada
+	done    map[int64]bool
+	commit  func(offset int64)
+}
+
+// NewOffsetTracker cria tracker; commit recebe o último offset concluído da sequência
+func NewOffsetTracker(commit func(offset int64)) *OffsetTracker {
+	return &OffsetTracker{
+		done:   map[int64]bool{},
+		commit: commit,
+	}
+}
+
+// Add registra offset recebido (chamar na ordem de consumo)
+func (t *OffsetTracker) Add(offset int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending = append(t.pending, offset)
+}
+
+// Done marca offset como processado e confirma o maior prefixo concluído
+func (t *OffsetTracker) Done(offset int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.done[offset] = true
+	last, advanced := int64(0), false
+	for len(t.pending) > 0 && t.done[t.pending[0]] {
+		last, advanced = t.pending[0], true
+		delete(t.done, last)
+		t.pending = t.pending[1:]
+	}
+	if advanced {
+		t.commit(last)
+	}
+}
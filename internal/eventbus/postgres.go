@@ -0,0 +1,229 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/reporter"
+	"chat-kafka-go/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// notifyChannel canal do NOTIFY disparado pelo trigger de event_log
+const notifyChannel = "event_log"
+
+// postgresBatchSize eventos lidos por consulta
+const postgresBatchSize = 100
+
+// PostgresBus barramento sobre o próprio Postgres: eventos em event_log,
+// consumidores acordados por LISTEN/NOTIFY (com polling de segurança) e
+// offsets por grupo em event_consumer_offsets. O offset é gravado depois do
+// handler (at-least-once) ou antes (at-most-once), conforme EVENT_BUS_DELIVERY.
+// Cada grupo processa em sequência (PoolOptions é ignorado)
+type PostgresBus struct {
+	pool    *pgxpool.Pool
+	queries *repository.Queries
+	cfg     *config.EventBusConfig
+}
+
+// NewPostgresBus cria barramento no pool primário
+func NewPostgresBus(pool *pgxpool.Pool, cfg *config.EventBusConfig) *PostgresBus {
+	log.Println("✓ Barramento de eventos Postgres (LISTEN/NOTIFY)")
+	return &PostgresBus{
+		pool:    pool,
+		queries: repository.New(pool),
+		cfg:     cfg,
+	}
+}
+
+// SendMessage implementa Bus
+func (b *PostgresBus) SendMessage(ctx context.Context, topic, key string, value []byte) error {
+	headers, err := json.Marshal(headersFromContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	err = b.queries.AppendEvent(ctx, repository.AppendEventParams{
+		Topic:   topic,
+		Key:     key,
+		Value:   value,
+		Headers: headers,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao gravar evento: %w", err)
+	}
+	return nil
+}
+
+// Subscribe implementa Bus
+func (b *PostgresBus) Subscribe(topic, group string, handler Handler, _ PoolOptions) (Subscriber, error) {
+	return &postgresSubscriber{
+		bus:     b,
+		topic:   topic,
+		group:   group,
+		handler: handler,
+	}, nil
+}
+
+// Close implementa Bus (o pool pertence ao database.DB)
+func (b *PostgresBus) Close() error { return nil }
+
+// postgresSubscriber consome um tópico em nome de um grupo
+type postgresSubscriber struct {
+	bus     *PostgresBus
+	topic   string
+	group   string
+	handler Handler
+
+	lastPrune time.Time
+}
+
+// Run implementa Subscriber; reconecta após falhas até o contexto ser cancelado
+func (s *postgresSubscriber) Run(ctx context.Context) error {
+	for {
+		err := s.consume(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		log.Printf("ERRO: consumidor %s/%s: %v", s.group, s.topic, err)
+		reporter.CaptureError(ctx, err, map[string]string{"component": "postgres_bus", "group": s.group})
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(s.bus.cfg.PollInterval):
+		}
+	}
+}
+
+// Close implementa Subscriber
+func (s *postgresSubscriber) Close() error { return nil }
+
+// consume segura uma conexão dedicada (lock do grupo + LISTEN) e processa
+// eventos até erro ou cancelamento
+func (s *postgresSubscriber) consume(ctx context.Context) error {
+	conn, err := s.bus.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	// Um consumidor por grupo entre as instâncias; as demais ficam de reserva
+	lockKey := s.group + "/" + s.topic
+	for {
+		var locked bool
+		if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", lockKey).Scan(&locked); err != nil {
+			return fmt.Errorf("erro ao obter lock do grupo: %w", err)
+		}
+		if locked {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.bus.cfg.PollInterval):
+		}
+	}
+	// Conexão volta ao pool: o lock de sessão precisa ser liberado antes
+	defer conn.Exec(context.Background(), "SELECT pg_advisory_unlock(hashtext($1))", lockKey)
+
+	if _, err := conn.Exec(ctx, "LISTEN "+notifyChannel); err != nil {
+		return fmt.Errorf("erro ao escutar eventos: %w", err)
+	}
+	defer conn.Exec(context.Background(), "UNLISTEN "+notifyChannel)
+
+	last, err := s.bus.queries.GetConsumerOffset(ctx, repository.GetConsumerOffsetParams{
+		ConsumerGroup: s.group,
+		Topic:         s.topic,
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("erro ao buscar offset do grupo: %w", err)
+	}
+
+	for {
+		// Drena tudo que já existe antes de esperar notificação
+		for {
+			n, err := s.processBatch(ctx, &last)
+			if err != nil {
+				return err
+			}
+			if n < postgresBatchSize {
+				break
+			}
+		}
+		s.prune(ctx)
+
+		waitCtx, cancel := context.WithTimeout(ctx, s.bus.cfg.PollInterval)
+		_, err := conn.Conn().WaitForNotification(waitCtx)
+		timedOut := errors.Is(waitCtx.Err(), context.DeadlineExceeded)
+		cancel()
+		if err != nil && !timedOut {
+			return fmt.Errorf("erro aguardando notificação: %w", err)
+		}
+	}
+}
+
+// processBatch processa um lote a partir de last, gravando o offset a cada evento
+func (s *postgresSubscriber) processBatch(ctx context.Context, last *int64) (int, error) {
+	events, err := s.bus.queries.ListEventsAfter(ctx, repository.ListEventsAfterParams{
+		Topic:     s.topic,
+		AfterID:   *last,
+		BatchSize: postgresBatchSize,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("erro ao listar eventos: %w", err)
+	}
+
+	for _, event := range events {
+		var headers map[string]string
+		_ = json.Unmarshal(event.Headers, &headers)
+
+		msg := &Message{
+			Topic:  event.Topic,
+			Offset: event.ID,
+			Key:    event.Key,
+			Value:  event.Value,
+		}
+		atMostOnce := s.bus.cfg.Delivery == DeliveryAtMostOnce
+
+		if !atMostOnce {
+			dispatch(contextFromHeaders(ctx, headers), "postgres_bus", s.handler, msg)
+		}
+
+		*last = event.ID
+		err := s.bus.queries.SaveConsumerOffset(ctx, repository.SaveConsumerOffsetParams{
+			ConsumerGroup: s.group,
+			Topic:         s.topic,
+			LastEventID:   event.ID,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("erro ao salvar offset do grupo: %w", err)
+		}
+
+		if atMostOnce {
+			dispatch(contextFromHeaders(ctx, headers), "postgres_bus", s.handler, msg)
+		}
+	}
+	return len(events), nil
+}
+
+// prune apaga eventos além da retenção (no máximo uma vez por hora)
+func (s *postgresSubscriber) prune(ctx context.Context) {
+	if s.bus.cfg.Retention <= 0 || time.Since(s.lastPrune) < time.Hour {
+		return
+	}
+	s.lastPrune = time.Now()
+
+	cutoff := pgtype.Timestamp{Time: time.Now().Add(-s.bus.cfg.Retention), Valid: true}
+	if _, err := s.bus.queries.PruneEvents(ctx, cutoff); err != nil {
+		log.Printf("ERRO: limpeza de eventos: %v", err)
+	}
+}
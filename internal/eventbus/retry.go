@@ -0,0 +1,278 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"chat-kafka-go/internal/metrics"
+	"chat-kafka-go/internal/recovery"
+	"chat-kafka-go/internal/reporter"
+)
+
+// RetryOptions estágios de retry e quarentena de uma assinatura
+type RetryOptions struct {
+	// Atrasos dos estágios, em ordem; depois do último o evento vai para a DLQ
+	// (vazio = sem retry, erro só é registrado)
+	Delays []time.Duration
+	// Panics/timeouts do mesmo evento antes da quarentena (0 = nunca)
+	PoisonThreshold int
+}
+
+// Motivos de falha que contam para a quarentena
+const (
+	crashPanic   = "panic"
+	crashTimeout = "timeout"
+)
+
+// retryEnvelope evento original embrulhado nos tópicos de retry e na DLQ
+type retryEnvelope struct {
+	Topic     string `json:"topic"`
+	Key       string `json:"key"`
+	Value     []byte `json:"value"`
+	Attempt   int    `json:"attempt"`    // Tentativas que já falharam
+	NotBefore int64  `json:"not_before"` // Unix (ms): não reprocessar antes disso
+	Error     string `json:"error"`      // Último erro do handler
+
+	// Panics/timeouts acumulados; no limite o evento vai para a quarentena
+	Crashes     int    `json:"crashes,omitempty"`
+	CrashReason string `json:"crash_reason,omitempty"` // panic ou timeout
+	Stack       string `json:"stack,omitempty"`        // Stack do último panic
+}
+
+// SubscribeWithRetry assina o tópico e os tópicos de retry do grupo
+// Um evento que falha não trava a partição: vai para "<tópico>-<grupo>-retry-<atraso>"
+// do primeiro estágio, é reprocessado depois do atraso e, falhando de novo,
+// escala para o próximo estágio até a DLQ "<tópico>-<grupo>-dlq".
+// Evento venenoso (panic ou timeout repetidos) pula os estágios restantes e vai
+// para "<tópico>-<grupo>-quarantine" com o stack do panic.
+// Sem atrasos configurados, equivale a bus.Subscribe (erro só é registrado)
+func SubscribeWithRetry(bus Bus, opts RetryOptions, topic, group string, handler Handler, pool PoolOptions) (Subscriber, error) {
+	if len(opts.Delays) == 0 {
+		return bus.Subscribe(topic, group, handler, pool)
+	}
+	delays := opts.Delays
+
+	r := &retrier{
+		bus:             bus,
+		delays:          delays,
+		poisonThreshold: opts.PoisonThreshold,
+		topic:           topic,
+		group:           group,
+		handler:         handler,
+		timeout:         pool.Timeout,
+	}
+
+	main, err := bus.Subscribe(topic, group, r.handleMain, pool)
+	if err != nil {
+		return nil, err
+	}
+	subs := multiSubscriber{main}
+
+	// Um consumidor sequencial por estágio: eventos do estágio vencem na ordem de chegada
+	for stage := range delays {
+		stagePool := PoolOptions{Workers: 1, Buffer: pool.Buffer}
+		sub, err := bus.Subscribe(r.stageTopic(stage), group, r.stageHandler(stage), stagePool)
+		if err != nil {
+			subs.Close()
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, nil
+}
+
+// retrier encaminha falhas entre os estágios de retry
+type retrier struct {
+	bus             Bus
+	delays          []time.Duration
+	poisonThreshold int
+	topic           string
+	group           string
+	handler         Handler
+	timeout         time.Duration
+}
+
+// handleMain processa o tópico principal; falha vai para o primeiro estágio
+func (r *retrier) handleMain(ctx context.Context, msg *Message) error {
+	err := r.call(ctx, msg)
+	if err == nil {
+		return nil
+	}
+	env := retryEnvelope{Topic: msg.Topic, Key: msg.Key, Value: msg.Value}
+	return r.escalate(ctx, r.recordCrash(ctx, env, err), 0, err)
+}
+
+// call executa o handler convertendo panic em erro (com stack)
+func (r *retrier) call(ctx context.Context, msg *Message) error {
+	return recovery.Guard(ctx, "event_handler", func() error {
+		return r.handler(ctx, msg)
+	})
+}
+
+// recordCrash contabiliza panic ou timeout do handler no envelope
+func (r *retrier) recordCrash(ctx context.Context, env retryEnvelope, err error) retryEnvelope {
+	var panicErr *recovery.PanicError
+	switch {
+	case errors.As(err, &panicErr):
+		env.Crashes++
+		env.CrashReason = crashPanic
+		env.Stack = string(panicErr.Stack)
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded):
+		env.Crashes++
+		env.CrashReason = crashTimeout
+	}
+	return env
+}
+
+// stageHandler espera o evento vencer e reprocessa; falha escala para o próximo estágio
+func (r *retrier) stageHandler(stage int) Handler {
+	return func(ctx context.Context, msg *Message) error {
+		var env retryEnvelope
+		if err := json.Unmarshal(msg.Value, &env); err != nil {
+			return fmt.Errorf("envelope de retry inválido: %w", err)
+		}
+
+		if wait := time.Until(time.UnixMilli(env.NotBefore)); wait > 0 {
+			timer := time.NewTimer(wait)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				// Desligando: devolve ao estágio para a tentativa não se perder
+				return r.publish(context.WithoutCancel(ctx), r.stageTopic(stage), env)
+			}
+		}
+
+		handlerCtx, cancel := ctx, context.CancelFunc(func() {})
+		if r.timeout > 0 {
+			handlerCtx, cancel = context.WithTimeout(ctx, r.timeout)
+		}
+		defer cancel()
+
+		err := r.call(handlerCtx, &Message{
+			Topic:     env.Topic,
+			Partition: msg.Partition,
+			Offset:    msg.Offset,
+			Key:       env.Key,
+			Value:     env.Value,
+		})
+		if err == nil {
+			return nil
+		}
+		return r.escalate(ctx, r.recordCrash(handlerCtx, env, err), stage+1, err)
+	}
+}
+
+// escalate publica o evento no estágio informado ou, depois do último, na DLQ
+// (ou na quarentena, se atingiu o limite de panics/timeouts)
+func (r *retrier) escalate(ctx context.Context, env retryEnvelope, stage int, cause error) error {
+	env.Attempt++
+	env.Error = cause.Error()
+
+	destination := r.dlqTopic()
+	if r.poisonThreshold > 0 && env.Crashes >= r.poisonThreshold {
+		destination = r.quarantineTopic()
+		log.Printf("ERRO: evento %s key=%s (grupo %s) em quarentena após %d falhas (%s): %v",
+			env.Topic, env.Key, r.group, env.Crashes, env.CrashReason, cause)
+		metrics.EventsQuarantinedTotal.WithLabelValues(r.topic, r.group, env.CrashReason).Inc()
+		reporter.CaptureError(ctx, cause, map[string]string{
+			"component": "event_quarantine",
+			"topic":     env.Topic,
+			"group":     r.group,
+			"key":       env.Key,
+			"reason":    env.CrashReason,
+		})
+	} else if stage < len(r.delays) {
+		destination = r.stageTopic(stage)
+		env.NotBefore = time.Now().Add(r.delays[stage]).UnixMilli()
+		log.Printf("Evento %s (grupo %s) falhou na tentativa %d, nova tentativa em %s: %v",
+			env.Topic, r.group, env.Attempt, r.delays[stage], cause)
+	} else {
+		log.Printf("ERRO: evento %s (grupo %s) enviado à DLQ após %d tentativas: %v",
+			env.Topic, r.group, env.Attempt, cause)
+	}
+
+	// Handler pode ter estourado o prazo: o envio não depende dele
+	if err := r.publish(context.WithoutCancel(ctx), destination, env); err != nil {
+		return fmt.Errorf("%w (falha ao enviar para %s: %v)", cause, destination, err)
+	}
+	metrics.EventRetriesTotal.WithLabelValues(r.topic, destination).Inc()
+	return nil
+}
+
+// publish grava o envelope no tópico de destino
+func (r *retrier) publish(ctx context.Context, topic string, env retryEnvelope) error {
+	value, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+	return r.bus.SendMessage(ctx, topic, env.Key, value)
+}
+
+// stageTopic tópico de retry do estágio (por grupo: outros grupos não reprocessam)
+func (r *retrier) stageTopic(stage int) string {
+	return fmt.Sprintf("%s-%s-retry-%s", r.topic, r.group, formatDelay(r.delays[stage]))
+}
+
+// dlqTopic tópico de eventos que esgotaram as tentativas
+func (r *retrier) dlqTopic() string {
+	return fmt.Sprintf("%s-%s-dlq", r.topic, r.group)
+}
+
+// quarantineTopic tópico de eventos venenosos (panic/timeout repetidos)
+func (r *retrier) quarantineTopic() string {
+	return fmt.Sprintf("%s-%s-quarantine", r.topic, r.group)
+}
+
+// formatDelay atraso curto para nome de tópico (5s, 1m, 2h)
+func formatDelay(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+}
+
+// multiSubscriber roda várias assinaturas como uma só
+type multiSubscriber []Subscriber
+
+// Run implementa Subscriber; espera todas terminarem
+func (m multiSubscriber) Run(ctx context.Context) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, sub := range m {
+		wg.Add(1)
+		go func(sub Subscriber) {
+			defer wg.Done()
+			if err := sub.Run(ctx); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(sub)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Close implementa Subscriber
+func (m multiSubscriber) Close() error {
+	var errs []error
+	for _, sub := range m {
+		if err := sub.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,85 @@
+package geoip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Location geolocalização aproximada (país/cidade) de um IP
+type Location struct {
+	Country string `json:"country,omitempty"` // ISO 3166-1 alpha-2
+	City    string `json:"city,omitempty"`
+}
+
+// Locator resolve a localização de um IP
+type Locator interface {
+	Lookup(ctx context.Context, ip string) (Location, error)
+}
+
+// New cria locator HTTP se a URL estiver configurada, senão um locator vazio
+// A URL deve conter {ip}, ex: https://ipapi.co/{ip}/json/
+func New(url string, timeout time.Duration) Locator {
+	if url == "" {
+		return NoopLocator{}
+	}
+	return &HTTPLocator{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// NoopLocator não resolve localização (geolocalização desabilitada)
+type NoopLocator struct{}
+
+// Lookup implementa Locator
+func (NoopLocator) Lookup(ctx context.Context, ip string) (Location, error) {
+	return Location{}, nil
+}
+
+// HTTPLocator consulta API HTTP que retorna JSON com country_code e city
+type HTTPLocator struct {
+	url    string
+	client *http.Client
+}
+
+// Lookup implementa Locator; IPs privados/loopback não são consultados
+func (l *HTTPLocator) Lookup(ctx context.Context, ip string) (Location, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil || parsed.IsPrivate() || parsed.IsLoopback() || parsed.IsUnspecified() {
+		return Location{}, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.ReplaceAll(l.url, "{ip}", ip), nil)
+	if err != nil {
+		return Location{}, fmt.Errorf("erro ao criar requisição de geolocalização: %w", err)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return Location{}, fmt.Errorf("erro ao consultar geolocalização: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Location{}, fmt.Errorf("geolocalização retornou status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		CountryCode string `json:"country_code"`
+		City        string `json:"city"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&body); err != nil {
+		return Location{}, fmt.Errorf("resposta de geolocalização inválida: %w", err)
+	}
+
+	return Location{
+		Country: strings.ToUpper(body.CountryCode),
+		City:    body.City,
+	}, nil
+}
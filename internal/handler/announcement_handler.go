@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/utils"
+)
+
+// AnnouncementHandler anúncios do sistema recebidos pelo usuário
+type AnnouncementHandler struct {
+	announcements *service.AnnouncementService
+}
+
+// NewAnnouncementHandler cria nova instância do handler
+func NewAnnouncementHandler(announcements *service.AnnouncementService) *AnnouncementHandler {
+	return &AnnouncementHandler{announcements: announcements}
+}
+
+// Pending GET /announcements/pending (exigem confirmação ainda não dada)
+func (h *AnnouncementHandler) Pending(w http.ResponseWriter, r *http.Request) {
+	pending, err := h.announcements.Pending(r.Context(), reqctx.UserID(r.Context()))
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "ANNOUNCEMENTS_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, pending, "")
+}
+
+// Acknowledge POST /announcements/{id}/ack
+func (h *AnnouncementHandler) Acknowledge(w http.ResponseWriter, r *http.Request) {
+	err := h.announcements.Acknowledge(r.Context(), reqctx.UserID(r.Context()), r.PathValue("id"))
+	if errors.Is(err, service.ErrAnnouncementNotFound) {
+		utils.Error(w, http.StatusNotFound, err.Error(), "ANNOUNCEMENT_NOT_FOUND")
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "ANNOUNCEMENT_ACK_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, nil, "")
+}
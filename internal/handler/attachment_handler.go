@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// AttachmentHandler rotas de anexos
+type AttachmentHandler struct {
+	attachments *service.AttachmentService
+}
+
+// NewAttachmentHandler cria nova instância do handler
+func NewAttachmentHandler(attachments *service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{attachments: attachments}
+}
+
+// Create POST /attachments
+func (h *AttachmentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input types.CreateAttachmentInput
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+
+	resp, err := h.attachments.Create(r.Context(), reqctx.UserID(r.Context()), input)
+	if err != nil {
+		attachmentError(w, err, "ATTACHMENT_CREATE_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusCreated, resp, "")
+}
+
+// Upload PUT /attachments/{id}/content (corpo = conteúdo do arquivo)
+func (h *AttachmentHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	err := h.attachments.Upload(r.Context(), reqctx.UserID(r.Context()), r.PathValue("id"), r.Body)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "ATTACHMENT_UPLOAD_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, nil, "conteúdo recebido")
+}
+
+// Finalize POST /attachments/{id}/finalize
+func (h *AttachmentHandler) Finalize(w http.ResponseWriter, r *http.Request) {
+	attachment, err := h.attachments.Finalize(r.Context(), reqctx.UserID(r.Context()), r.PathValue("id"))
+	if err != nil {
+		attachmentError(w, err, "ATTACHMENT_FINALIZE_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, attachment, "")
+}
+
+// Get GET /attachments/{id} (metadados e estado da varredura)
+func (h *AttachmentHandler) Get(w http.ResponseWriter, r *http.Request) {
+	attachment, err := h.attachments.Get(r.Context(), reqctx.UserID(r.Context()), r.PathValue("id"))
+	if err != nil {
+		utils.Error(w, http.StatusNotFound, err.Error(), "ATTACHMENT_NOT_FOUND")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, attachment, "")
+}
+
+// Download GET /attachments/{id}/content?variant=web&confirm_nsfw=true
+// Sem confirmação, imagens sinalizadas podem vir como prévia desfocada
+// (header X-Attachment-Preview: blurred)
+func (h *AttachmentHandler) Download(w http.ResponseWriter, r *http.Request) {
+	confirm := r.URL.Query().Get("confirm_nsfw") == "true"
+	variant := r.URL.Query().Get("variant")
+	content, attachment, err := h.attachments.Open(r.Context(), reqctx.UserID(r.Context()), r.PathValue("id"), variant, confirm)
+	if err != nil {
+		downloadError(w, err)
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Cache-Control", "private, no-store")
+	serveAttachment(w, content, attachment)
+}
+
+// DownloadURL POST /attachments/{id}/download-url?variant=web&confirm_nsfw=true
+func (h *AttachmentHandler) DownloadURL(w http.ResponseWriter, r *http.Request) {
+	confirm := r.URL.Query().Get("confirm_nsfw") == "true"
+	variant := r.URL.Query().Get("variant")
+	resp, err := h.attachments.DownloadURL(r.Context(), reqctx.UserID(r.Context()), r.PathValue("id"), variant, confirm)
+	if err != nil {
+		downloadError(w, err)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, resp, "")
+}
+
+// Signed GET /files/{id}/{variant}?expires=...&signature=... (sem autenticação)
+// Cacheável até a expiração da URL, para servir via CDN
+func (h *AttachmentHandler) Signed(w http.ResponseWriter, r *http.Request) {
+	id, variant := r.PathValue("id"), r.PathValue("variant")
+	query := r.URL.Query()
+	if err := h.attachments.VerifySignedPath(id, variant, query.Get("expires"), query.Get("signature")); err != nil {
+		utils.Error(w, http.StatusForbidden, err.Error(), "INVALID_SIGNATURE")
+		return
+	}
+
+	content, attachment, err := h.attachments.OpenSigned(r.Context(), id, variant)
+	if err != nil {
+		downloadError(w, err)
+		return
+	}
+	defer content.Close()
+
+	expires, _ := strconv.ParseInt(query.Get("expires"), 10, 64)
+	maxAge := max(0, expires-time.Now().Unix())
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.FormatInt(maxAge, 10))
+	serveAttachment(w, content, attachment)
+}
+
+// serveAttachment escreve headers e conteúdo do anexo
+func serveAttachment(w http.ResponseWriter, content io.Reader, attachment *types.AttachmentResponse) {
+	w.Header().Set("Content-Type", attachment.ContentType)
+	if attachment.Blurred {
+		w.Header().Set("X-Attachment-Preview", "blurred")
+	}
+	if attachment.Size > 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	}
+	w.Header().Set("Content-Disposition", "attachment; filename="+strconv.Quote(attachment.FileName))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	_, _ = io.Copy(w, content)
+}
+
+// downloadError responde erros de acesso ao conteúdo do anexo
+func downloadError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrAttachmentQuarantined):
+		utils.Error(w, http.StatusGone, err.Error(), "ATTACHMENT_QUARANTINED")
+	case errors.Is(err, service.ErrNSFWConfirmationRequired):
+		utils.Error(w, http.StatusConflict, err.Error(), "NSFW_CONFIRMATION_REQUIRED")
+	default:
+		utils.Error(w, http.StatusNotFound, err.Error(), "ATTACHMENT_NOT_FOUND")
+	}
+}
+
+// attachmentError responde erros da política de upload com códigos específicos
+func attachmentError(w http.ResponseWriter, err error, fallbackCode string) {
+	switch {
+	case errors.Is(err, service.ErrAttachmentTooLarge):
+		utils.Error(w, http.StatusRequestEntityTooLarge, err.Error(), "ATTACHMENT_TOO_LARGE")
+	case errors.Is(err, service.ErrAttachmentTypeNotAllowed):
+		utils.Error(w, http.StatusUnsupportedMediaType, err.Error(), "ATTACHMENT_TYPE_NOT_ALLOWED")
+	case errors.Is(err, service.ErrAttachmentTypeMismatch):
+		utils.Error(w, http.StatusUnsupportedMediaType, err.Error(), "ATTACHMENT_TYPE_MISMATCH")
+	case errors.Is(err, service.ErrQuotaExceeded):
+		utils.Error(w, http.StatusTooManyRequests, err.Error(), "QUOTA_EXCEEDED")
+	default:
+		utils.Error(w, http.StatusBadRequest, err.Error(), fallbackCode)
+	}
+}
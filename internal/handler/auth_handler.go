@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// AuthHandler rotas de autenticação
+type AuthHandler struct {
+	auth   *service.AuthService
+	logins *service.LoginAlertService
+}
+
+// NewAuthHandler cria nova instância do handler
+func NewAuthHandler(auth *service.AuthService, logins *service.LoginAlertService) *AuthHandler {
+	return &AuthHandler{auth: auth, logins: logins}
+}
+
+// Register POST /auth/register
+func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
+	var input types.RegisterInput
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+
+	resp, err := h.auth.Register(r.Context(), input, clientInfo(r))
+	if accountConflict(w, err) {
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "REGISTER_FAILED")
+		return
+	}
+
+	if resp.VerificationRequired {
+		utils.Success(w, http.StatusCreated, resp, "usuário criado: confirme o email para entrar")
+		return
+	}
+
+	utils.Success(w, http.StatusCreated, resp, "usuário criado")
+}
+
+// Login POST /auth/login
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var input types.LoginInput
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+
+	resp, err := h.auth.Login(r.Context(), input, clientInfo(r))
+	if errors.Is(err, service.ErrEmailNotVerified) {
+		utils.Error(w, http.StatusForbidden, err.Error(), "EMAIL_NOT_VERIFIED")
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, err.Error(), "LOGIN_FAILED")
+		return
+	}
+
+	if resp.Challenge != nil {
+		utils.Success(w, http.StatusAccepted, resp, "confirme o login com o código enviado por email")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, resp, "")
+}
+
+// VerifyLogin POST /auth/login/verify (código do step-up)
+func (h *AuthHandler) VerifyLogin(w http.ResponseWriter, r *http.Request) {
+	var input types.VerifyLoginInput
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+
+	resp, err := h.auth.VerifyLogin(r.Context(), input, clientInfo(r))
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, err.Error(), "LOGIN_VERIFY_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, resp, "")
+}
+
+// Refresh POST /auth/refresh
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var input types.RefreshTokenInput
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+
+	tokens, err := h.auth.RefreshToken(r.Context(), input)
+	if errors.Is(err, utils.ErrTokenExpired) {
+		// Sessão acabou: novo login
+		utils.Error(w, http.StatusUnauthorized, "refresh token expirado", "REFRESH_EXPIRED")
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, err.Error(), "REFRESH_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, tokens, "")
+}
+
+// Logout POST /auth/logout
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	var input types.RefreshTokenInput
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+
+	if err := h.auth.Logout(r.Context(), input.RefreshToken); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "LOGOUT_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, nil, "logout realizado")
+}
+
+// NotMe POST /auth/not-me (link do alerta de novo login; revoga a sessão)
+func (h *AuthHandler) NotMe(w http.ResponseWriter, r *http.Request) {
+	var input types.NotMeInput
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+
+	if err := h.logins.ReportNotMe(r.Context(), input.Token); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "NOT_ME_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, nil, "sessão encerrada")
+}
+
+// VerifyEmail POST /auth/verify-email (token do link enviado no cadastro)
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var input types.VerifyEmailInput
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+
+	if err := h.auth.VerifyEmail(r.Context(), input, clientInfo(r)); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "VERIFY_EMAIL_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, nil, "email confirmado")
+}
+
+// ResendVerification POST /auth/verify-email/resend (resposta igual para
+// qualquer email: não revela contas)
+func (h *AuthHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	var input types.ResendVerificationInput
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+
+	if err := h.auth.ResendVerification(r.Context(), input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "RESEND_VERIFICATION_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, nil, "se o email estiver cadastrado e pendente, um novo link foi enviado")
+}
+
+// ForgotPassword POST /auth/forgot-password (resposta igual para qualquer
+// email: não revela contas)
+func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var input types.ForgotPasswordInput
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+
+	if err := h.auth.ForgotPassword(r.Context(), input, clientInfo(r)); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "FORGOT_PASSWORD_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, nil, "se o email estiver cadastrado, um link de redefinição foi enviado")
+}
+
+// ResetPassword POST /auth/reset-password (token do link + nova senha)
+func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var input types.ResetPasswordInput
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+
+	if err := h.auth.ResetPassword(r.Context(), input, clientInfo(r)); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "RESET_PASSWORD_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, nil, "senha redefinida: entre novamente em todos os dispositivos")
+}
+
+// Introspect POST /auth/introspect (RFC 7662; chave de API com escopo
+// tokens:introspect). Aceita form (token, token_type_hint) ou JSON; a
+// resposta segue a RFC, sem o envelope success/data
+func (h *AuthHandler) Introspect(w http.ResponseWriter, r *http.Request) {
+	var input types.IntrospectInput
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		if err := r.ParseForm(); err != nil {
+			utils.Error(w, http.StatusBadRequest, "formulário inválido", "INVALID_REQUEST")
+			return
+		}
+		input.Token = r.PostForm.Get("token")
+		input.TokenTypeHint = r.PostForm.Get("token_type_hint")
+	} else if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+
+	if input.Token == "" {
+		utils.Error(w, http.StatusBadRequest, "token é obrigatório", "INVALID_REQUEST")
+		return
+	}
+
+	resp, err := h.auth.Introspect(r.Context(), input)
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, err.Error(), "INTROSPECTION_FAILED")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	utils.JSON(w, http.StatusOK, resp)
+}
+
+// clientInfo extrai IP e user agent da requisição
+func clientInfo(r *http.Request) types.ClientInfo {
+	return types.ClientInfo{
+		IP:        utils.ClientIP(r),
+		UserAgent: r.UserAgent(),
+	}
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"chat-kafka-go/internal/config/configtest"
+	"chat-kafka-go/internal/disposable"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/internal/repository/repotest"
+	"chat-kafka-go/internal/service"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func newAuthHandler(t *testing.T) (*AuthHandler, *repotest.DB) {
+	db := repotest.New()
+	auth := service.NewAuthService(repository.New(db), nil, disposable.NewBlocklist("", 0), nil, nil, nil, nil, nil, configtest.New(t))
+	return NewAuthHandler(auth, nil), db
+}
+
+func noRows([]interface{}) repotest.Result { return repotest.Rows() }
+
+func TestRegisterRejectsReservedUsername(t *testing.T) {
+	h, db := newAuthHandler(t)
+	db.On("GetUserByEmail", noRows)
+	db.On("GetUserByUsername", noRows)
+	db.On("GetActiveUsernameReservation", func([]interface{}) repotest.Result {
+		return repotest.Rows(repotest.Row(repository.UsernameHistory{OldUsername: "maria"}))
+	})
+
+	body := `{"username":"maria","email":"nova@example.com","password":"segredo123"}`
+	code, resp := serve(t, h.Register, http.MethodPost, body, "")
+	if code != http.StatusConflict || resp.Code != "USERNAME_RESERVED" {
+		t.Fatalf("resposta = %d %s; esperado 409 USERNAME_RESERVED", code, resp.Code)
+	}
+	if calls := db.Calls("CreateUser"); len(calls) != 0 {
+		t.Fatal("usuário criado com username reservado")
+	}
+}
+
+func TestRegisterRejectsTakenEmail(t *testing.T) {
+	h, db := newAuthHandler(t)
+	db.On("GetUserByEmail", func([]interface{}) repotest.Result {
+		return repotest.Rows(repotest.Row(repository.User{Email: "maria@example.com"}))
+	})
+
+	body := `{"username":"maria2","email":"maria@example.com","password":"segredo123"}`
+	code, resp := serve(t, h.Register, http.MethodPost, body, "")
+	if code != http.StatusConflict || resp.Code != "EMAIL_TAKEN" {
+		t.Fatalf("resposta = %d %s; esperado 409 EMAIL_TAKEN", code, resp.Code)
+	}
+}
+
+// TestRegisterUniqueViolation cadastro simultâneo: o índice único de contas
+// ativas recusa o INSERT depois da verificação; 409, não 500
+func TestRegisterUniqueViolation(t *testing.T) {
+	h, db := newAuthHandler(t)
+	db.On("GetUserByEmail", noRows)
+	db.On("GetUserByUsername", noRows)
+	db.On("GetActiveUsernameReservation", noRows)
+	db.On("CreateUser", func([]interface{}) repotest.Result {
+		return repotest.Result{Err: &pgconn.PgError{Code: "23505", ConstraintName: "idx_users_email_active"}}
+	})
+
+	body := `{"username":"maria","email":"maria@example.com","password":"segredo123"}`
+	code, resp := serve(t, h.Register, http.MethodPost, body, "")
+	if code != http.StatusConflict || resp.Code != "EMAIL_TAKEN" {
+		t.Fatalf("resposta = %d %s; esperado 409 EMAIL_TAKEN", code, resp.Code)
+	}
+}
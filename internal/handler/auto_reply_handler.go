@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// AutoReplyHandler resposta automática (férias/ausência) do usuário
+type AutoReplyHandler struct {
+	replies *service.AutoReplyService
+}
+
+// NewAutoReplyHandler cria nova instância do handler
+func NewAutoReplyHandler(replies *service.AutoReplyService) *AutoReplyHandler {
+	return &AutoReplyHandler{replies: replies}
+}
+
+// Get GET /users/me/auto-reply
+func (h *AutoReplyHandler) Get(w http.ResponseWriter, r *http.Request) {
+	reply, err := h.replies.Get(r.Context(), reqctx.UserID(r.Context()))
+	if errors.Is(err, service.ErrAutoReplyNotFound) {
+		utils.Error(w, http.StatusNotFound, err.Error(), "AUTO_REPLY_NOT_FOUND")
+		return
+	}
+	if forbidden(w, err) {
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "AUTO_REPLY_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, reply, "")
+}
+
+// Update PUT /users/me/auto-reply (liga ou substitui)
+func (h *AutoReplyHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var input types.UpdateAutoReplyInput
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+	input.UserID = reqctx.UserID(r.Context())
+
+	reply, err := h.replies.Update(r.Context(), input)
+	if forbidden(w, err) {
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "AUTO_REPLY_UPDATE_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, reply, "resposta automática ativada")
+}
+
+// Disable DELETE /users/me/auto-reply
+func (h *AutoReplyHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	err := h.replies.Disable(r.Context(), reqctx.UserID(r.Context()))
+	if forbidden(w, err) {
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "AUTO_REPLY_DISABLE_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, nil, "resposta automática desativada")
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// batchHeaders headers da sub-resposta devolvidos no resultado
+var batchHeaders = []string{"ETag", "Cache-Control", "Retry-After"}
+
+// BatchHandler executa lotes de leituras no próprio roteador (uma ida e
+// volta para, por exemplo, os perfis dos membros de um grupo)
+type BatchHandler struct {
+	router      http.Handler
+	maxRequests int
+	concurrency int
+}
+
+// NewBatchHandler cria o handler; router é o mux das rotas públicas
+func NewBatchHandler(router http.Handler, maxRequests, concurrency int) *BatchHandler {
+	return &BatchHandler{router: router, maxRequests: maxRequests, concurrency: concurrency}
+}
+
+// Batch POST /batch {"requests":[{"id":"a","path":"/users/{id}"}]}
+// Só GET; cada item passa pela autenticação da própria rota (JWT ou chave de
+// API com escopo) com os headers do lote. Responde 200 com um resultado por
+// item, na ordem recebida, cada um com status e corpo próprios
+func (h *BatchHandler) Batch(w http.ResponseWriter, r *http.Request) {
+	var input types.BatchInput
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+	if len(input.Requests) == 0 {
+		utils.Error(w, http.StatusBadRequest, "requests não pode ser vazio", "BATCH_INVALID")
+		return
+	}
+	if len(input.Requests) > h.maxRequests {
+		utils.Error(w, http.StatusBadRequest, fmt.Sprintf("máximo de %d requisições por lote", h.maxRequests), "BATCH_TOO_LARGE")
+		return
+	}
+
+	results := make([]types.BatchResult, len(input.Requests))
+	sem := make(chan struct{}, h.concurrency)
+	var wg sync.WaitGroup
+	for i, item := range input.Requests {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, item types.BatchRequest) {
+			defer func() {
+				if rec := recover(); rec != nil {
+					log.Printf("ERRO: panic no item %d do lote (%s): %v", i, item.Path, rec)
+					results[i] = batchError(item.ID, http.StatusInternalServerError, "erro interno", "INTERNAL_ERROR")
+				}
+				<-sem
+				wg.Done()
+			}()
+			results[i] = h.execute(r, item)
+		}(i, item)
+	}
+	wg.Wait()
+
+	utils.Success(w, http.StatusOK, results, "")
+}
+
+// execute roda um item no roteador e captura a resposta
+func (h *BatchHandler) execute(r *http.Request, item types.BatchRequest) types.BatchResult {
+	if item.Method != "" && !strings.EqualFold(item.Method, http.MethodGet) {
+		return batchError(item.ID, http.StatusMethodNotAllowed, "lote aceita apenas GET", "BATCH_METHOD_NOT_ALLOWED")
+	}
+	target, err := url.ParseRequestURI(item.Path)
+	if err != nil || !strings.HasPrefix(item.Path, "/") || strings.HasPrefix(item.Path, "//") {
+		return batchError(item.ID, http.StatusBadRequest, "path inválido", "BATCH_INVALID_PATH")
+	}
+	if target.Path == "/ws" {
+		return batchError(item.ID, http.StatusBadRequest, "WebSocket não pode ser usado em lote", "BATCH_INVALID_PATH")
+	}
+
+	sub, err := http.NewRequestWithContext(r.Context(), http.MethodGet, target.String(), http.NoBody)
+	if err != nil {
+		return batchError(item.ID, http.StatusBadRequest, "path inválido", "BATCH_INVALID_PATH")
+	}
+	sub.Header = r.Header.Clone()
+	for _, name := range []string{"Content-Type", "Content-Length", "If-None-Match", "If-Modified-Since"} {
+		sub.Header.Del(name)
+	}
+	for name, value := range item.Headers {
+		sub.Header.Set(name, value)
+	}
+	sub.Host = r.Host
+	sub.RemoteAddr = r.RemoteAddr
+	sub.RequestURI = target.RequestURI()
+
+	rec := &batchRecorder{header: make(http.Header)}
+	h.router.ServeHTTP(rec, sub)
+
+	result := types.BatchResult{ID: item.ID, Status: rec.statusCode()}
+	for _, name := range batchHeaders {
+		if value := rec.header.Get(name); value != "" {
+			if result.Headers == nil {
+				result.Headers = make(map[string]string)
+			}
+			result.Headers[name] = value
+		}
+	}
+
+	body := bytes.TrimSpace(rec.body.Bytes())
+	switch {
+	case len(body) == 0:
+	case json.Valid(body):
+		result.Body = body
+	case result.Status >= http.StatusBadRequest:
+		// Erros em texto puro do mux (404/405 de rota inexistente)
+		result.Body = batchErrorBody(string(body), "BATCH_ITEM_FAILED")
+	default:
+		// Conteúdo binário (anexos) fica fora do envelope JSON do lote
+		result.Body = batchErrorBody("resposta não é JSON; use a rota diretamente", "BATCH_NOT_JSON")
+	}
+	return result
+}
+
+// batchError resultado de item recusado antes de chegar à rota
+func batchError(id string, status int, message, code string) types.BatchResult {
+	return types.BatchResult{ID: id, Status: status, Body: batchErrorBody(message, code)}
+}
+
+// batchErrorBody corpo no formato de utils.Error
+func batchErrorBody(message, code string) json.RawMessage {
+	body, _ := json.Marshal(types.ErrorResponse{Success: false, Error: message, Code: code})
+	return body
+}
+
+// batchRecorder ResponseWriter em memória de um item do lote
+type batchRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *batchRecorder) Header() http.Header {
+	return b.header
+}
+
+func (b *batchRecorder) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *batchRecorder) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
+
+// statusCode status escrito pela rota (200 quando nada foi escrito)
+func (b *batchRecorder) statusCode() int {
+	if b.status == 0 {
+		return http.StatusOK
+	}
+	return b.status
+}
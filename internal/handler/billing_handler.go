@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"chat-kafka-go/internal/billing"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/utils"
+)
+
+// maxWebhookBytes limite do corpo do webhook de cobrança
+const maxWebhookBytes = 1 << 20
+
+// BillingHandler webhooks do provedor de cobrança
+type BillingHandler struct {
+	provider billing.Provider // nil = cobrança desligada
+	plans    *service.PlanService
+}
+
+// NewBillingHandler cria nova instância do handler
+func NewBillingHandler(provider billing.Provider, plans *service.PlanService) *BillingHandler {
+	return &BillingHandler{provider: provider, plans: plans}
+}
+
+// Webhook POST /billing/webhook (sem JWT: autenticado pela assinatura do provedor)
+func (h *BillingHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	if h.provider == nil {
+		utils.Error(w, http.StatusNotFound, "cobrança desligada", "BILLING_DISABLED")
+		return
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBytes))
+	if err != nil {
+		utils.Error(w, http.StatusRequestEntityTooLarge, err.Error(), "INVALID_BODY")
+		return
+	}
+
+	event, err := h.provider.ParseWebhook(payload, r.Header)
+	switch {
+	case errors.Is(err, billing.ErrIgnored):
+		utils.Success(w, http.StatusOK, nil, "evento ignorado")
+		return
+	case errors.Is(err, billing.ErrInvalidSignature):
+		utils.Error(w, http.StatusUnauthorized, err.Error(), "INVALID_SIGNATURE")
+		return
+	case err != nil:
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_EVENT")
+		return
+	}
+
+	// Erro responde 5xx/4xx: o provedor reenvia o evento
+	if err := h.plans.ApplyBillingEvent(r.Context(), h.provider.Name(), event); err != nil {
+		log.Printf("ERRO: webhook de cobrança %s: %v", event.ID, err)
+		utils.Error(w, http.StatusUnprocessableEntity, err.Error(), "BILLING_WEBHOOK_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, nil, "")
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// ContactHandler rotas de contatos
+type ContactHandler struct {
+	contacts *service.ContactService
+}
+
+// NewContactHandler cria nova instância do handler
+func NewContactHandler(contacts *service.ContactService) *ContactHandler {
+	return &ContactHandler{contacts: contacts}
+}
+
+// Sync POST /contacts/sync (429 ao passar do limite diário de hashes)
+func (h *ContactHandler) Sync(w http.ResponseWriter, r *http.Request) {
+	var input types.ContactSyncInput
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+	input.UserID = reqctx.UserID(r.Context())
+
+	matches, err := h.contacts.Sync(r.Context(), input)
+	if forbidden(w, err) {
+		return
+	}
+	if errors.Is(err, service.ErrQuotaExceeded) {
+		utils.Error(w, http.StatusTooManyRequests, err.Error(), "QUOTA_EXCEEDED")
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "CONTACT_SYNC_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, matches, "")
+}
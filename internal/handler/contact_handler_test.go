@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/config/configtest"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/internal/repository/repotest"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func newContactHandler(t *testing.T) (*ContactHandler, *repotest.DB, *config.SecurityConfig) {
+	db := repotest.New()
+	cfg := configtest.New(t).Security
+	cfg.ContactHashPepper = "pepper-de-teste"
+	return NewContactHandler(service.NewContactService(repository.New(db), &cfg)), db, &cfg
+}
+
+func syncBody(hashes ...string) string {
+	return fmt.Sprintf(`{"email_hashes":["%s"]}`, strings.Join(hashes, `","`))
+}
+
+func TestContactSyncMatchesKeyedHashes(t *testing.T) {
+	h, db, cfg := newContactHandler(t)
+	friend, err := utils.StringToUUID(otherUserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientHash := utils.HashEmail("Amiga@Example.com")
+	stored := utils.KeyContactHash(cfg.ContactHashPepper, clientHash)
+
+	db.On("ConsumeContactLookups", func(args []interface{}) repotest.Result {
+		return repotest.Rows([]interface{}{args[2]})
+	})
+	db.On("ListUsersByContactHashes", func(args []interface{}) repotest.Result {
+		var rows [][]interface{}
+		for _, key := range args[1].([]string) {
+			if key == stored {
+				rows = append(rows, repotest.Row(repository.ListUsersByContactHashesRow{Hash: stored, ID: friend, Username: "amiga"}))
+			}
+		}
+		return repotest.Rows(rows...)
+	})
+
+	// Mesmo hash em maiúsculas e repetido conta uma vez
+	code, resp := serve(t, h.Sync, http.MethodPost, syncBody(strings.ToUpper(clientHash), clientHash), testUserID)
+	if code != http.StatusOK {
+		t.Fatalf("status = %d (%s); esperado 200", code, resp.Error)
+	}
+	var matches []types.ContactMatch
+	decodeData(t, resp, &matches)
+	if len(matches) != 1 || matches[0].Hash != clientHash || matches[0].UserID != otherUserID {
+		t.Fatalf("matches = %+v; esperado amiga pelo hash do cliente", matches)
+	}
+
+	lookup := db.Calls("ListUsersByContactHashes")[0][1].([]string)
+	if len(lookup) != 1 || lookup[0] != stored {
+		t.Fatalf("consulta com %v; esperado só o hash com pepper", lookup)
+	}
+	if counted := db.Calls("ConsumeContactLookups")[0][2]; counted != int32(1) {
+		t.Fatalf("limite diário contou %v hashes; esperado 1", counted)
+	}
+}
+
+func TestContactSyncDailyLimit(t *testing.T) {
+	h, db, _ := newContactHandler(t)
+	db.On("ConsumeContactLookups", func([]interface{}) repotest.Result { return repotest.Rows() })
+
+	code, resp := serve(t, h.Sync, http.MethodPost, syncBody(utils.HashEmail("a@example.com")), testUserID)
+	if code != http.StatusTooManyRequests || resp.Code != "QUOTA_EXCEEDED" {
+		t.Fatalf("resposta = %d %s; esperado 429 QUOTA_EXCEEDED", code, resp.Code)
+	}
+	if calls := db.Calls("ListUsersByContactHashes"); len(calls) != 0 {
+		t.Fatal("contatos consultados com o limite diário esgotado")
+	}
+}
+
+func TestContactSyncPerCallLimit(t *testing.T) {
+	h, db, cfg := newContactHandler(t)
+
+	hashes := make([]string, cfg.ContactSyncMaxHashes+1)
+	for i := range hashes {
+		hashes[i] = utils.HashEmail(fmt.Sprintf("%d@example.com", i))
+	}
+	code, resp := serve(t, h.Sync, http.MethodPost, syncBody(hashes...), testUserID)
+	if code != http.StatusBadRequest || resp.Code != "CONTACT_SYNC_FAILED" {
+		t.Fatalf("resposta = %d %s; esperado 400 CONTACT_SYNC_FAILED", code, resp.Code)
+	}
+	if calls := db.Calls("ConsumeContactLookups"); len(calls) != 0 {
+		t.Fatal("lote acima do limite consumiu a cota")
+	}
+}
+
+func TestContactSyncRejectsPhoneHashes(t *testing.T) {
+	h, _, _ := newContactHandler(t)
+
+	code, resp := serve(t, h.Sync, http.MethodPost, `{"phone_hashes":["`+utils.HashEmail("x")+`"]}`, testUserID)
+	if code != http.StatusBadRequest || resp.Code != "INVALID_JSON" {
+		t.Fatalf("resposta = %d %s; esperado 400 INVALID_JSON", code, resp.Code)
+	}
+}
+
+func TestContactBackfillUsesPepper(t *testing.T) {
+	db := repotest.New()
+	cfg := config.SecurityConfig{ContactHashPepper: "pepper-de-teste"}
+	contacts := service.NewContactService(repository.New(db), &cfg)
+	id, err := utils.StringToUUID(testUserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db.On("ListUsersWithoutContactHash", func([]interface{}) repotest.Result {
+		if len(db.Calls("UpsertContactHash")) > 0 {
+			return repotest.Rows()
+		}
+		return repotest.Rows(repotest.Row(repository.ListUsersWithoutContactHashRow{ID: id, Email: "Maria@Example.com"}))
+	})
+	db.On("UpsertContactHash", func([]interface{}) repotest.Result { return repotest.Result{RowsAffected: 1} })
+
+	n, err := contacts.Backfill(context.Background())
+	if err != nil || n != 1 {
+		t.Fatalf("Backfill = %d, %v; esperado 1, nil", n, err)
+	}
+	args := db.Calls("UpsertContactHash")[0]
+	want := utils.KeyContactHash(cfg.ContactHashPepper, utils.HashEmail("maria@example.com"))
+	if args[1] != want || args[2].(pgtype.UUID) != id {
+		t.Fatalf("hash gravado = %v; esperado %s", args[1], want)
+	}
+}
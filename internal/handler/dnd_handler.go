@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"net/http"
+
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// DNDHandler não perturbe do usuário autenticado (afeta só push/email)
+type DNDHandler struct {
+	dnd *service.DNDService
+}
+
+// NewDNDHandler cria nova instância do handler
+func NewDNDHandler(dnd *service.DNDService) *DNDHandler {
+	return &DNDHandler{dnd: dnd}
+}
+
+// Get GET /users/me/dnd
+func (h *DNDHandler) Get(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.dnd.GetSettings(r.Context(), reqctx.UserID(r.Context()))
+	if forbidden(w, err) {
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "DND_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, settings, "")
+}
+
+// Update PUT /users/me/dnd (substitui fuso e janelas semanais)
+func (h *DNDHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var input types.UpdateDNDScheduleInput
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+	input.UserID = reqctx.UserID(r.Context())
+
+	settings, err := h.dnd.UpdateSchedule(r.Context(), input)
+	if forbidden(w, err) {
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "DND_UPDATE_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, settings, "")
+}
+
+// Snooze POST /users/me/dnd/snooze (hours = 0 cancela a soneca)
+func (h *DNDHandler) Snooze(w http.ResponseWriter, r *http.Request) {
+	var input types.SnoozeInput
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+	input.UserID = reqctx.UserID(r.Context())
+
+	settings, err := h.dnd.Snooze(r.Context(), input)
+	if forbidden(w, err) {
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "DND_SNOOZE_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, settings, "")
+}
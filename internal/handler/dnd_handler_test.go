@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/internal/repository/repotest"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/types"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func newDNDHandler(now time.Time) (*DNDHandler, *repotest.DB) {
+	db := repotest.New()
+	dnd := service.NewDNDService(repository.New(db))
+	dnd.SetClock(clock.NewManual(now))
+	return NewDNDHandler(dnd), db
+}
+
+func TestDNDGetDefaults(t *testing.T) {
+	h, db := newDNDHandler(time.Now())
+	db.On("GetDNDSettings", func([]interface{}) repotest.Result { return repotest.Rows() })
+
+	code, resp := serve(t, h.Get, http.MethodGet, "", testUserID)
+	if code != http.StatusOK {
+		t.Fatalf("status = %d (%s); esperado 200", code, resp.Error)
+	}
+	var settings types.DNDSettingsResponse
+	decodeData(t, resp, &settings)
+	if settings.Timezone != "UTC" || len(settings.Windows) != 0 || settings.SnoozedUntil != "" {
+		t.Fatalf("padrões inesperados: %+v", settings)
+	}
+}
+
+func TestDNDUpdateSchedule(t *testing.T) {
+	h, db := newDNDHandler(time.Now())
+	db.On("UpsertDNDSchedule", func(args []interface{}) repotest.Result {
+		return repotest.Rows(repotest.Row(repository.UserDndSetting{
+			UserID:   args[0].(pgtype.UUID),
+			Timezone: args[1].(string),
+			Windows:  args[2].([]byte),
+		}))
+	})
+
+	body := `{"timezone":"America/Sao_Paulo","windows":[{"weekday":1,"start":"22:00","end":"07:00"}]}`
+	code, resp := serve(t, h.Update, http.MethodPut, body, testUserID)
+	if code != http.StatusOK {
+		t.Fatalf("status = %d (%s); esperado 200", code, resp.Error)
+	}
+
+	var saved []types.DNDWindow
+	if err := json.Unmarshal(db.Calls("UpsertDNDSchedule")[0][2].([]byte), &saved); err != nil {
+		t.Fatalf("janelas gravadas inválidas: %v", err)
+	}
+	want := types.DNDWindow{Weekday: 1, Start: "22:00", End: "07:00"}
+	if len(saved) != 1 || saved[0] != want {
+		t.Fatalf("janelas gravadas = %+v; esperado [%+v]", saved, want)
+	}
+}
+
+func TestDNDUpdateRejectsInvalidWindow(t *testing.T) {
+	h, db := newDNDHandler(time.Now())
+
+	for _, body := range []string{
+		`{"timezone":"UTC","windows":[{"weekday":7,"start":"22:00","end":"07:00"}]}`,
+		`{"timezone":"UTC","windows":[{"weekday":1,"start":"25:00","end":"07:00"}]}`,
+		`{"timezone":"Marte/Olympus","windows":[]}`,
+	} {
+		code, resp := serve(t, h.Update, http.MethodPut, body, testUserID)
+		if code != http.StatusBadRequest || resp.Code != "DND_UPDATE_FAILED" {
+			t.Fatalf("%s: resposta = %d %s; esperado 400 DND_UPDATE_FAILED", body, code, resp.Code)
+		}
+	}
+	if calls := db.Calls("UpsertDNDSchedule"); len(calls) != 0 {
+		t.Fatalf("janelas inválidas gravadas: %v", calls)
+	}
+}
+
+func TestDNDSnooze(t *testing.T) {
+	now := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+	h, db := newDNDHandler(now)
+	db.On("UpsertDNDSnooze", func(args []interface{}) repotest.Result {
+		return repotest.Rows(repotest.Row(repository.UserDndSetting{
+			UserID:       args[0].(pgtype.UUID),
+			Timezone:     "UTC",
+			Windows:      []byte("[]"),
+			SnoozedUntil: args[1].(pgtype.Timestamp),
+		}))
+	})
+
+	code, resp := serve(t, h.Snooze, http.MethodPost, `{"hours":2}`, testUserID)
+	if code != http.StatusOK {
+		t.Fatalf("status = %d (%s); esperado 200", code, resp.Error)
+	}
+	var settings types.DNDSettingsResponse
+	decodeData(t, resp, &settings)
+	if want := now.Add(2 * time.Hour).Format(time.RFC3339); settings.SnoozedUntil != want {
+		t.Fatalf("snoozed_until = %q; esperado %q", settings.SnoozedUntil, want)
+	}
+}
+
+func TestDNDSnoozeRejectsOutOfRange(t *testing.T) {
+	h, db := newDNDHandler(time.Now())
+
+	for _, body := range []string{`{"hours":-1}`, `{"hours":169}`} {
+		code, resp := serve(t, h.Snooze, http.MethodPost, body, testUserID)
+		if code != http.StatusBadRequest || resp.Code != "DND_SNOOZE_FAILED" {
+			t.Fatalf("%s: resposta = %d %s; esperado 400 DND_SNOOZE_FAILED", body, code, resp.Code)
+		}
+	}
+	if calls := db.Calls("UpsertDNDSnooze"); len(calls) != 0 {
+		t.Fatalf("soneca inválida gravada: %v", calls)
+	}
+}
+
+func TestDNDRequiresUser(t *testing.T) {
+	h, _ := newDNDHandler(time.Now())
+
+	code, resp := serve(t, h.Snooze, http.MethodPost, `{"hours":1}`, "")
+	if code != http.StatusForbidden || resp.Code != "FORBIDDEN" {
+		t.Fatalf("resposta = %d %s; esperado 403 FORBIDDEN", code, resp.Code)
+	}
+}
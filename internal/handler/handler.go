@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/utils"
+)
+
+// maxBodyBytes limite padrão do corpo JSON das requisições
+const maxBodyBytes = 1 << 20 // 1 MB
+
+// decodeJSON decodifica o corpo da requisição rejeitando campos desconhecidos
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("JSON inválido: %w", err)
+	}
+	return nil
+}
+
+// forbidden responde 403 quando o service recusou o ator autenticado
+func forbidden(w http.ResponseWriter, err error) bool {
+	if !errors.Is(err, service.ErrForbidden) {
+		return false
+	}
+	utils.Error(w, http.StatusForbidden, err.Error(), "FORBIDDEN")
+	return true
+}
+
+// accountConflict responde 409 quando email ou username já pertencem (ou
+// estão reservados) a outra conta
+func accountConflict(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrEmailTaken):
+		utils.Error(w, http.StatusConflict, err.Error(), "EMAIL_TAKEN")
+	case errors.Is(err, service.ErrUsernameTaken):
+		utils.Error(w, http.StatusConflict, err.Error(), "USERNAME_TAKEN")
+	case errors.Is(err, service.ErrUsernameReserved):
+		utils.Error(w, http.StatusConflict, err.Error(), "USERNAME_RESERVED")
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"chat-kafka-go/internal/reqctx"
+)
+
+const testUserID = "8f14e45f-ceea-4e67-a5c9-7b1a2d3e4f50"
+
+// testResponse corpo padrão das respostas (utils.Success/utils.Error)
+type testResponse struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Error   string          `json:"error"`
+	Code    string          `json:"code"`
+}
+
+// serve executa o handler como o usuário informado ("" = sem autenticação)
+func serve(t *testing.T, fn http.HandlerFunc, method, body, userID string) (int, testResponse) {
+	t.Helper()
+	req := httptest.NewRequest(method, "/", strings.NewReader(body))
+	if userID != "" {
+		req = req.WithContext(reqctx.WithUserID(req.Context(), userID))
+	}
+	rec := httptest.NewRecorder()
+	fn(rec, req)
+
+	var resp testResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("resposta inválida %q: %v", rec.Body.String(), err)
+	}
+	return rec.Code, resp
+}
+
+// decodeData decodifica o campo data da resposta
+func decodeData(t *testing.T, resp testResponse, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(resp.Data, v); err != nil {
+		t.Fatalf("data inválido %q: %v", resp.Data, err)
+	}
+}
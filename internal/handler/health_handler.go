@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"chat-kafka-go/internal/database"
+	"chat-kafka-go/internal/eventbus"
+	"chat-kafka-go/internal/ws"
+	"chat-kafka-go/pkg/utils"
+)
+
+// readyTimeout prazo das verificações de prontidão
+const readyTimeout = 2 * time.Second
+
+// DatabaseHealth banco com primário e réplicas (database.DB)
+type DatabaseHealth interface {
+	Health(ctx context.Context) (database.Health, error)
+}
+
+// HealthHandler sondas de liveness e readiness
+type HealthHandler struct {
+	db  DatabaseHealth
+	bus eventbus.Bus
+	hub *ws.Hub
+}
+
+// NewHealthHandler cria nova instância do handler
+func NewHealthHandler(db DatabaseHealth, bus eventbus.Bus, hub *ws.Hub) *HealthHandler {
+	return &HealthHandler{db: db, bus: bus, hub: hub}
+}
+
+// Live GET /healthz (processo de pé)
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	utils.Success(w, http.StatusOK, map[string]string{"status": "ok"}, "")
+}
+
+// Ready GET /readyz
+// Sem o primário do banco ou drenando para encerrar, a instância sai do balanceamento (503).
+// Réplica fora (leituras vão para o primário) e barramento degradado (Kafka
+// fora, eventos no buffer em disco) continuam prontos: status "degraded"
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	if h.hub.Draining() {
+		utils.Error(w, http.StatusServiceUnavailable, "instância encerrando", "DRAINING")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+	defer cancel()
+
+	dbHealth, err := h.db.Health(ctx)
+	if err != nil {
+		utils.Error(w, http.StatusServiceUnavailable, "banco de dados indisponível", "NOT_READY")
+		return
+	}
+
+	status, dbStatus, busStatus := "ok", "ok", "ok"
+	if dbHealth.Degraded() {
+		status, dbStatus = "degraded", "degraded"
+	}
+	if eventbus.IsDegraded(h.bus) {
+		status, busStatus = "degraded", "degraded"
+	}
+	utils.Success(w, http.StatusOK, map[string]string{
+		"status":    status,
+		"database":  dbStatus,
+		"event_bus": busStatus,
+	}, "")
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"chat-kafka-go/internal/database"
+	"chat-kafka-go/internal/eventbus"
+	"chat-kafka-go/internal/ws"
+)
+
+// fakeDatabase saúde fixa do banco
+type fakeDatabase struct {
+	health database.Health
+	err    error
+}
+
+func (f fakeDatabase) Health(context.Context) (database.Health, error) {
+	return f.health, f.err
+}
+
+func TestReady(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		db         fakeDatabase
+		wantCode   int
+		wantStatus string
+	}{
+		{"ok", fakeDatabase{health: database.Health{Replicas: 2}}, http.StatusOK, "ok"},
+		{"réplica fora", fakeDatabase{health: database.Health{Replicas: 2, ReplicasDown: 1}}, http.StatusOK, "degraded"},
+		{"primário fora", fakeDatabase{err: errors.New("connection refused")}, http.StatusServiceUnavailable, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHealthHandler(tc.db, eventbus.NewMemoryBus(1), ws.NewHub(ws.DrainOptions{}))
+
+			code, resp := serve(t, h.Ready, http.MethodGet, "", "")
+			if code != tc.wantCode {
+				t.Fatalf("status = %d; esperado %d", code, tc.wantCode)
+			}
+			if tc.wantCode != http.StatusOK {
+				if resp.Code != "NOT_READY" {
+					t.Fatalf("code = %s; esperado NOT_READY", resp.Code)
+				}
+				return
+			}
+			var data map[string]string
+			decodeData(t, resp, &data)
+			if data["status"] != tc.wantStatus || data["database"] != tc.wantStatus {
+				t.Fatalf("data = %v; esperado status e database %s", data, tc.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// InvitationHandler rotas de convites
+type InvitationHandler struct {
+	invitations *service.InvitationService
+}
+
+// NewInvitationHandler cria nova instância do handler
+func NewInvitationHandler(invitations *service.InvitationService) *InvitationHandler {
+	return &InvitationHandler{invitations: invitations}
+}
+
+// Create POST /invitations
+func (h *InvitationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input types.CreateInvitationInput
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+	input.InviterID = reqctx.UserID(r.Context())
+
+	invitation, err := h.invitations.CreateInvitation(r.Context(), input)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVITATION_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusCreated, invitation, "convite criado")
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/status"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// MessageHandler rotas de mensagens e conversas
+type MessageHandler struct {
+	messages *service.MessageService
+}
+
+// NewMessageHandler cria nova instância do handler
+func NewMessageHandler(messages *service.MessageService) *MessageHandler {
+	return &MessageHandler{messages: messages}
+}
+
+// Send POST /messages
+func (h *MessageHandler) Send(w http.ResponseWriter, r *http.Request) {
+	var input types.SendMessageInput
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+	// Remetente é sempre o autenticado (usuário ou bot da chave de API)
+	input.SenderID = reqctx.UserID(r.Context())
+	_, input.ViaAPIKey = reqctx.Scopes(r.Context())
+
+	message, err := h.messages.SendMessage(r.Context(), input)
+	switch {
+	case errors.Is(err, service.ErrQuotaExceeded):
+		utils.Error(w, http.StatusTooManyRequests, err.Error(), "QUOTA_EXCEEDED")
+		return
+	case errors.Is(err, service.ErrFriendshipRequired):
+		utils.Error(w, http.StatusForbidden, err.Error(), "FRIENDSHIP_REQUIRED")
+		return
+	case errors.Is(err, service.ErrEmailNotVerified):
+		utils.Error(w, http.StatusForbidden, err.Error(), "EMAIL_NOT_VERIFIED")
+		return
+	case errors.Is(err, service.ErrRecipientNotFound):
+		utils.Error(w, http.StatusNotFound, err.Error(), "RECIPIENT_NOT_FOUND")
+		return
+	case forbidden(w, err):
+		return
+	case err != nil:
+		utils.Error(w, http.StatusBadRequest, err.Error(), "SEND_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusCreated, message, "")
+}
+
+// Delivered POST /messages/{id}/delivered: ack do dispositivo do
+// destinatário (ex.: push recebido com o app fora do WebSocket)
+func (h *MessageHandler) Delivered(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.messages.MarkAsDelivered(r.Context(), r.PathValue("id"), reqctx.UserID(r.Context()))
+	if err != nil {
+		messageStatusError(w, err)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, resp, "")
+}
+
+// Read POST /messages/{id}/read: destinatário leu (zera não lidas da conversa)
+func (h *MessageHandler) Read(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.messages.MarkAsRead(r.Context(), r.PathValue("id"), reqctx.UserID(r.Context()))
+	if err != nil {
+		messageStatusError(w, err)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, resp, "")
+}
+
+// History GET /messages/{peerID}?page=1&per_page=50 ou ?before=<message_id>
+// (cursor: meta.next_cursor da página anterior)
+func (h *MessageHandler) History(w http.ResponseWriter, r *http.Request) {
+	page, perPage := pagination(r)
+
+	resp, err := h.messages.GetMessagesBetween(r.Context(), types.ListMessagesInput{
+		UserID:   reqctx.UserID(r.Context()),
+		FriendID: r.PathValue("peerID"),
+		Page:     page,
+		PerPage:  perPage,
+		Before:   r.URL.Query().Get("before"),
+	})
+	if forbidden(w, err) {
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "HISTORY_FAILED")
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, resp)
+}
+
+// Conversations GET /conversations?page=1&per_page=20 (ETag; If-None-Match → 304)
+func (h *MessageHandler) Conversations(w http.ResponseWriter, r *http.Request) {
+	page, perPage := pagination(r)
+	input := types.ListConversationsInput{
+		UserID:  reqctx.UserID(r.Context()),
+		Page:    page,
+		PerPage: perPage,
+	}
+
+	// Polling barato: a versão sai de uma agregação, sem montar a página
+	etag, err := h.messages.ConversationsETag(r.Context(), input)
+	if forbidden(w, err) {
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "CONVERSATIONS_FAILED")
+		return
+	}
+	if utils.NotModified(w, r, etag) {
+		return
+	}
+
+	resp, err := h.messages.ListConversations(r.Context(), input)
+	if forbidden(w, err) {
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "CONVERSATIONS_FAILED")
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, resp)
+}
+
+// messageStatusError status HTTP dos erros de confirmação de entrega/leitura
+func messageStatusError(w http.ResponseWriter, err error) {
+	if forbidden(w, err) {
+		return
+	}
+	if errors.Is(err, service.ErrMessageNotFound) {
+		utils.Error(w, http.StatusNotFound, err.Error(), "MESSAGE_NOT_FOUND")
+		return
+	}
+	if errors.Is(err, status.ErrConflict) {
+		utils.Error(w, http.StatusConflict, err.Error(), "VERSION_CONFLICT")
+		return
+	}
+	if errors.Is(err, status.ErrInvalidTransition) {
+		utils.Error(w, http.StatusConflict, err.Error(), "INVALID_STATUS_TRANSITION")
+		return
+	}
+	utils.Error(w, http.StatusBadRequest, err.Error(), "MESSAGE_STATUS_FAILED")
+}
+
+// pagination lê page/per_page da query (services aplicam os defaults)
+func pagination(r *http.Request) (int, int) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	return page, perPage
+}
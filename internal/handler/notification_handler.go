@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/utils"
+)
+
+// NotificationHandler rotas de notificações in-app
+type NotificationHandler struct {
+	notifications *service.NotificationService
+}
+
+// NewNotificationHandler cria nova instância do handler
+func NewNotificationHandler(notifications *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notifications: notifications}
+}
+
+// List GET /notifications?limit=50
+func (h *NotificationHandler) List(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	notifications, err := h.notifications.List(r.Context(), reqctx.UserID(r.Context()), limit)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "NOTIFICATIONS_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, notifications, "")
+}
+
+// MarkRead POST /notifications/{id}/read
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	err := h.notifications.MarkRead(r.Context(), reqctx.UserID(r.Context()), r.PathValue("id"))
+	if err != nil {
+		utils.Error(w, http.StatusNotFound, err.Error(), "NOTIFICATION_NOT_FOUND")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, nil, "")
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/status"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// PreferencesHandler preferências de interface sincronizadas entre dispositivos
+type PreferencesHandler struct {
+	preferences *service.PreferencesService
+}
+
+// NewPreferencesHandler cria nova instância do handler
+func NewPreferencesHandler(preferences *service.PreferencesService) *PreferencesHandler {
+	return &PreferencesHandler{preferences: preferences}
+}
+
+// Get GET /users/me/preferences
+func (h *PreferencesHandler) Get(w http.ResponseWriter, r *http.Request) {
+	prefs, err := h.preferences.Get(r.Context(), reqctx.UserID(r.Context()))
+	if forbidden(w, err) {
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "PREFERENCES_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, prefs, "")
+}
+
+// Update PATCH /users/me/preferences (mescla; null volta ao padrão)
+func (h *PreferencesHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var input types.UpdatePreferencesInput
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+	input.UserID = reqctx.UserID(r.Context())
+
+	prefs, err := h.preferences.Update(r.Context(), input)
+	if forbidden(w, err) {
+		return
+	}
+	if errors.Is(err, status.ErrConflict) {
+		utils.Error(w, http.StatusConflict, err.Error(), "VERSION_CONFLICT")
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "PREFERENCES_UPDATE_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, prefs, "")
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// PrivacyHandler configurações de privacidade do usuário autenticado
+type PrivacyHandler struct {
+	privacy *service.PrivacyService
+}
+
+// NewPrivacyHandler cria nova instância do handler
+func NewPrivacyHandler(privacy *service.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{privacy: privacy}
+}
+
+// Get GET /users/me/privacy (padrões se nunca alteradas)
+func (h *PrivacyHandler) Get(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.privacy.GetSettings(r.Context(), reqctx.UserID(r.Context()))
+	if forbidden(w, err) {
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "PRIVACY_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, settings, "")
+}
+
+// Update PUT /users/me/privacy (substitui todas as configurações)
+func (h *PrivacyHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var input types.UpdatePrivacySettingsInput
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+	input.UserID = reqctx.UserID(r.Context())
+
+	settings, err := h.privacy.UpdateSettings(r.Context(), input)
+	if forbidden(w, err) {
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "PRIVACY_UPDATE_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, settings, "")
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/internal/repository/repotest"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/types"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func newPrivacyHandler() (*PrivacyHandler, *repotest.DB) {
+	db := repotest.New()
+	return NewPrivacyHandler(service.NewPrivacyService(repository.New(db))), db
+}
+
+func TestPrivacyGetDefaults(t *testing.T) {
+	h, db := newPrivacyHandler()
+	db.On("GetPrivacySettings", func([]interface{}) repotest.Result { return repotest.Rows() })
+
+	code, resp := serve(t, h.Get, http.MethodGet, "", testUserID)
+	if code != http.StatusOK {
+		t.Fatalf("status = %d (%s); esperado 200", code, resp.Error)
+	}
+	var settings types.PrivacySettingsResponse
+	decodeData(t, resp, &settings)
+	if settings.MessagePolicy != service.PolicyEveryone || settings.PresenceVisibility != service.PolicyEveryone || !settings.ShareReadReceipts {
+		t.Fatalf("padrões inesperados: %+v", settings)
+	}
+}
+
+func TestPrivacyUpdate(t *testing.T) {
+	h, db := newPrivacyHandler()
+	db.On("UpsertPrivacySettings", func(args []interface{}) repotest.Result {
+		return repotest.Rows(repotest.Row(repository.UserPrivacySetting{
+			UserID:             args[0].(pgtype.UUID),
+			MessagePolicy:      args[1].(string),
+			PresenceVisibility: args[2].(string),
+			ShareReadReceipts:  args[3].(bool),
+		}))
+	})
+
+	body := `{"message_policy":"friends","presence_visibility":"nobody","share_read_receipts":false}`
+	code, resp := serve(t, h.Update, http.MethodPut, body, testUserID)
+	if code != http.StatusOK {
+		t.Fatalf("status = %d (%s); esperado 200", code, resp.Error)
+	}
+	var settings types.PrivacySettingsResponse
+	decodeData(t, resp, &settings)
+	if settings.MessagePolicy != "friends" || settings.PresenceVisibility != "nobody" || settings.ShareReadReceipts {
+		t.Fatalf("configurações não aplicadas: %+v", settings)
+	}
+}
+
+func TestPrivacyUpdateRejectsInvalidPolicy(t *testing.T) {
+	h, db := newPrivacyHandler()
+
+	body := `{"message_policy":"nobody","presence_visibility":"everyone","share_read_receipts":true}`
+	code, resp := serve(t, h.Update, http.MethodPut, body, testUserID)
+	if code != http.StatusBadRequest || resp.Code != "PRIVACY_UPDATE_FAILED" {
+		t.Fatalf("resposta = %d %s; esperado 400 PRIVACY_UPDATE_FAILED", code, resp.Code)
+	}
+	if calls := db.Calls("UpsertPrivacySettings"); len(calls) != 0 {
+		t.Fatalf("configurações gravadas com política inválida: %v", calls)
+	}
+}
+
+func TestPrivacyRequiresUser(t *testing.T) {
+	h, _ := newPrivacyHandler()
+
+	code, resp := serve(t, h.Get, http.MethodGet, "", "")
+	if code != http.StatusForbidden || resp.Code != "FORBIDDEN" {
+		t.Fatalf("resposta = %d %s; esperado 403 FORBIDDEN", code, resp.Code)
+	}
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// SearchHandler rota de busca global
+type SearchHandler struct {
+	search *service.SearchService
+}
+
+// NewSearchHandler cria nova instância do handler
+func NewSearchHandler(search *service.SearchService) *SearchHandler {
+	return &SearchHandler{search: search}
+}
+
+// Search GET /search?q=termo&type=users,messages&per_page=10&messages_page=2
+// page vale para todos os tipos; <tipo>_page sobrescreve só aquele tipo
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	page, perPage := pagination(r)
+
+	var kinds []string
+	for _, kind := range strings.Split(query.Get("type"), ",") {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			kinds = append(kinds, kind)
+		}
+	}
+
+	pages := map[string]int{}
+	for _, kind := range []string{service.SearchTypeUsers, service.SearchTypeConversations, service.SearchTypeMessages} {
+		pages[kind] = page
+		if p, err := strconv.Atoi(query.Get(kind + "_page")); err == nil {
+			pages[kind] = p
+		}
+	}
+
+	resp, err := h.search.Search(r.Context(), types.SearchInput{
+		UserID:  reqctx.UserID(r.Context()),
+		Query:   query.Get("q"),
+		Types:   kinds,
+		Pages:   pages,
+		PerPage: perPage,
+	})
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "SEARCH_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, resp, "")
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// ShareLinkHandler links somente leitura de conversas
+type ShareLinkHandler struct {
+	links *service.ShareLinkService
+}
+
+// NewShareLinkHandler cria nova instância do handler
+func NewShareLinkHandler(links *service.ShareLinkService) *ShareLinkHandler {
+	return &ShareLinkHandler{links: links}
+}
+
+// Create POST /conversations/{id}/share-links {"expires_in":"24h"}
+func (h *ShareLinkHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input types.CreateShareLinkInput
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+	input.OwnerID = reqctx.UserID(r.Context())
+	input.PeerID = r.PathValue("id")
+
+	link, err := h.links.Create(r.Context(), input)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "SHARE_LINK_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusCreated, link, "link criado")
+}
+
+// List GET /conversations/{id}/share-links (válidos, sem token)
+func (h *ShareLinkHandler) List(w http.ResponseWriter, r *http.Request) {
+	links, err := h.links.List(r.Context(), reqctx.UserID(r.Context()), r.PathValue("id"))
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "SHARE_LINK_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, links, "")
+}
+
+// Revoke DELETE /conversations/{id}/share-links/{linkID}
+func (h *ShareLinkHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	err := h.links.Revoke(r.Context(), reqctx.UserID(r.Context()), r.PathValue("linkID"))
+	switch {
+	case errors.Is(err, service.ErrShareLinkInvalid):
+		utils.Error(w, http.StatusNotFound, err.Error(), "SHARE_LINK_NOT_FOUND")
+		return
+	case err != nil:
+		utils.Error(w, http.StatusBadRequest, err.Error(), "SHARE_LINK_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, nil, "link revogado")
+}
+
+// History GET /shared/{token}/messages?per_page=50&before=<message_id>
+// (sem login: o token do link é a credencial, válida só aqui)
+func (h *ShareLinkHandler) History(w http.ResponseWriter, r *http.Request) {
+	page, perPage := pagination(r)
+
+	resp, err := h.links.History(r.Context(), r.PathValue("token"), types.ListMessagesInput{
+		Page:    page,
+		PerPage: perPage,
+		Before:  r.URL.Query().Get("before"),
+	})
+	switch {
+	case errors.Is(err, service.ErrShareLinkInvalid):
+		utils.Error(w, http.StatusNotFound, err.Error(), "SHARE_LINK_NOT_FOUND")
+		return
+	case err != nil:
+		utils.Error(w, http.StatusBadRequest, err.Error(), "HISTORY_FAILED")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	utils.JSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"net/http"
+
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// SupportAccessHandler autorização do usuário para o suporte ver suas
+// conversas (somente leitura) e histórico desses acessos
+type SupportAccessHandler struct {
+	impersonations *service.ImpersonationService
+}
+
+// NewSupportAccessHandler cria nova instância do handler
+func NewSupportAccessHandler(impersonations *service.ImpersonationService) *SupportAccessHandler {
+	return &SupportAccessHandler{impersonations: impersonations}
+}
+
+// Get GET /users/me/support-access
+func (h *SupportAccessHandler) Get(w http.ResponseWriter, r *http.Request) {
+	access, err := h.impersonations.GetAccess(r.Context(), reqctx.UserID(r.Context()))
+	if forbidden(w, err) {
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, err.Error(), "SUPPORT_ACCESS_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, access, "")
+}
+
+// Grant PUT /users/me/support-access (autoriza ou renova o prazo)
+func (h *SupportAccessHandler) Grant(w http.ResponseWriter, r *http.Request) {
+	var input types.GrantSupportAccessInput
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+
+	access, err := h.impersonations.GrantAccess(r.Context(), reqctx.UserID(r.Context()), input, utils.ClientIP(r))
+	if forbidden(w, err) {
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "SUPPORT_ACCESS_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, access, "acesso do suporte autorizado")
+}
+
+// Revoke DELETE /users/me/support-access (encerra também os tokens ativos)
+func (h *SupportAccessHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	err := h.impersonations.RevokeAccess(r.Context(), reqctx.UserID(r.Context()), utils.ClientIP(r))
+	if forbidden(w, err) {
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "SUPPORT_ACCESS_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, nil, "acesso do suporte revogado")
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// SupportHandler caixa de suporte (clientes e agentes)
+type SupportHandler struct {
+	support *service.SupportService
+}
+
+// NewSupportHandler cria nova instância do handler
+func NewSupportHandler(support *service.SupportService) *SupportHandler {
+	return &SupportHandler{support: support}
+}
+
+// Open POST /support/tickets {"subject":"...","content":"primeira mensagem"}
+func (h *SupportHandler) Open(w http.ResponseWriter, r *http.Request) {
+	var input types.OpenSupportTicketInput
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+	input.CustomerID = reqctx.UserID(r.Context())
+
+	ticket, err := h.support.Open(r.Context(), input)
+	if err != nil {
+		supportError(w, err)
+		return
+	}
+
+	utils.Success(w, http.StatusCreated, ticket, "chamado aberto")
+}
+
+// Mine GET /support/tickets/mine (chamados do cliente)
+func (h *SupportHandler) Mine(w http.ResponseWriter, r *http.Request) {
+	tickets, err := h.support.Mine(r.Context(), reqctx.UserID(r.Context()))
+	if err != nil {
+		supportError(w, err)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, tickets, "")
+}
+
+// Queue GET /support/tickets?status=open&assigned=me|none&page=1 (agentes)
+func (h *SupportHandler) Queue(w http.ResponseWriter, r *http.Request) {
+	page, perPage := pagination(r)
+
+	tickets, err := h.support.Queue(r.Context(), reqctx.UserID(r.Context()), types.SupportTicketFilter{
+		Status:   r.URL.Query().Get("status"),
+		Assigned: r.URL.Query().Get("assigned"),
+		Page:     page,
+		PerPage:  perPage,
+	})
+	if err != nil {
+		supportError(w, err)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, tickets, "")
+}
+
+// Get GET /support/tickets/{id} (agentes; com notas internas)
+func (h *SupportHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ticket, err := h.support.Get(r.Context(), reqctx.UserID(r.Context()), r.PathValue("id"))
+	if err != nil {
+		supportError(w, err)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, ticket, "")
+}
+
+// Claim POST /support/tickets/{id}/claim (agente assume da fila)
+func (h *SupportHandler) Claim(w http.ResponseWriter, r *http.Request) {
+	ticket, err := h.support.Claim(r.Context(), reqctx.UserID(r.Context()), r.PathValue("id"))
+	if err != nil {
+		supportError(w, err)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, ticket, "chamado assumido")
+}
+
+// SetStatus PUT /support/tickets/{id}/status {"status":"pending"}
+func (h *SupportHandler) SetStatus(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Status string `json:"status"`
+	}
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+
+	ticket, err := h.support.SetStatus(r.Context(), reqctx.UserID(r.Context()), r.PathValue("id"), input.Status)
+	if err != nil {
+		supportError(w, err)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, ticket, "")
+}
+
+// AddNote POST /support/tickets/{id}/notes {"content":"..."} (interna)
+func (h *SupportHandler) AddNote(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Content string `json:"content"`
+	}
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+
+	note, err := h.support.AddNote(r.Context(), reqctx.UserID(r.Context()), r.PathValue("id"), input.Content)
+	if err != nil {
+		supportError(w, err)
+		return
+	}
+
+	utils.Success(w, http.StatusCreated, note, "nota registrada")
+}
+
+// Messages GET /support/tickets/{id}/messages?before=<message_id> (agentes)
+func (h *SupportHandler) Messages(w http.ResponseWriter, r *http.Request) {
+	page, perPage := pagination(r)
+
+	resp, err := h.support.Messages(r.Context(), reqctx.UserID(r.Context()), r.PathValue("id"), types.ListMessagesInput{
+		Page:    page,
+		PerPage: perPage,
+		Before:  r.URL.Query().Get("before"),
+	})
+	if err != nil {
+		supportError(w, err)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, resp)
+}
+
+// Reply POST /support/tickets/{id}/reply {"content":"..."} (como support)
+func (h *SupportHandler) Reply(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Content string `json:"content"`
+	}
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+
+	message, err := h.support.Reply(r.Context(), reqctx.UserID(r.Context()), r.PathValue("id"), input.Content)
+	if err != nil {
+		supportError(w, err)
+		return
+	}
+
+	utils.Success(w, http.StatusCreated, message, "resposta enviada")
+}
+
+// supportError status HTTP dos erros da caixa de suporte
+func supportError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrNotSupportAgent):
+		utils.Error(w, http.StatusForbidden, err.Error(), "NOT_SUPPORT_AGENT")
+	case errors.Is(err, service.ErrSupportTicketNotFound):
+		utils.Error(w, http.StatusNotFound, err.Error(), "TICKET_NOT_FOUND")
+	case errors.Is(err, service.ErrSupportTicketActive), errors.Is(err, service.ErrSupportTicketTaken):
+		utils.Error(w, http.StatusConflict, err.Error(), "TICKET_CONFLICT")
+	case errors.Is(err, service.ErrSupportTicketNotAssigned):
+		utils.Error(w, http.StatusForbidden, err.Error(), "TICKET_NOT_ASSIGNED")
+	default:
+		utils.Error(w, http.StatusBadRequest, err.Error(), "SUPPORT_FAILED")
+	}
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// Protocolo tus 1.0.0 (core + creation, expiration, termination)
+const (
+	tusVersion     = "1.0.0"
+	tusExtensions  = "creation,expiration,termination"
+	tusContentType = "application/offset+octet-stream"
+)
+
+// TusHandler uploads retomáveis via protocolo tus (https://tus.io)
+type TusHandler struct {
+	attachments *service.AttachmentService
+	maxSize     int64
+}
+
+// NewTusHandler cria nova instância do handler
+func NewTusHandler(attachments *service.AttachmentService, maxSize int64) *TusHandler {
+	return &TusHandler{
+		attachments: attachments,
+		maxSize:     maxSize,
+	}
+}
+
+// Options OPTIONS /uploads (descoberta de versão e extensões)
+func (h *TusHandler) Options(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Tus-Resumable", tusVersion)
+	w.Header().Set("Tus-Version", tusVersion)
+	w.Header().Set("Tus-Extension", tusExtensions)
+	w.Header().Set("Tus-Max-Size", strconv.FormatInt(h.maxSize, 10))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Create POST /uploads (Upload-Length + Upload-Metadata com filename e filetype)
+func (h *TusHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if !h.checkVersion(w, r) {
+		return
+	}
+
+	length, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || length <= 0 {
+		utils.Error(w, http.StatusBadRequest, "Upload-Length inválido", "INVALID_UPLOAD_LENGTH")
+		return
+	}
+	metadata := parseUploadMetadata(r.Header.Get("Upload-Metadata"))
+
+	session, err := h.attachments.CreateUpload(r.Context(), reqctx.UserID(r.Context()), types.CreateAttachmentInput{
+		FileName:    metadata["filename"],
+		ContentType: metadata["filetype"],
+		Size:        length,
+	})
+	if err != nil {
+		attachmentError(w, err, "UPLOAD_CREATE_FAILED")
+		return
+	}
+
+	w.Header().Set("Location", "/uploads/"+session.AttachmentID)
+	w.Header().Set("Upload-Expires", session.ExpiresAt.UTC().Format(http.TimeFormat))
+	w.WriteHeader(http.StatusCreated)
+}
+
+// Head HEAD /uploads/{id} (offset confirmado para retomar)
+func (h *TusHandler) Head(w http.ResponseWriter, r *http.Request) {
+	if !h.checkVersion(w, r) {
+		return
+	}
+
+	session, err := h.attachments.UploadStatus(r.Context(), reqctx.UserID(r.Context()), r.PathValue("id"))
+	if err != nil {
+		// HEAD não tem corpo
+		w.WriteHeader(uploadErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+	w.Header().Set("Upload-Length", strconv.FormatInt(session.Length, 10))
+	w.Header().Set("Upload-Expires", session.ExpiresAt.UTC().Format(http.TimeFormat))
+	w.WriteHeader(http.StatusOK)
+}
+
+// Patch PATCH /uploads/{id} (bloco a partir de Upload-Offset)
+func (h *TusHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	if !h.checkVersion(w, r) {
+		return
+	}
+	if r.Header.Get("Content-Type") != tusContentType {
+		utils.Error(w, http.StatusUnsupportedMediaType, "Content-Type deve ser "+tusContentType, "INVALID_CONTENT_TYPE")
+		return
+	}
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		utils.Error(w, http.StatusBadRequest, "Upload-Offset inválido", "INVALID_UPLOAD_OFFSET")
+		return
+	}
+
+	session, err := h.attachments.AppendUpload(r.Context(), reqctx.UserID(r.Context()), r.PathValue("id"), offset, r.Body)
+	if session != nil {
+		w.Header().Set("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+		w.Header().Set("Upload-Expires", session.ExpiresAt.UTC().Format(http.TimeFormat))
+	}
+	if err != nil {
+		if status := uploadErrorStatus(err); status != http.StatusBadRequest {
+			utils.Error(w, status, err.Error(), "UPLOAD_FAILED")
+			return
+		}
+		attachmentError(w, err, "UPLOAD_FAILED")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Delete DELETE /uploads/{id} (cancela o upload)
+func (h *TusHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if !h.checkVersion(w, r) {
+		return
+	}
+
+	if err := h.attachments.TerminateUpload(r.Context(), reqctx.UserID(r.Context()), r.PathValue("id")); err != nil {
+		utils.Error(w, uploadErrorStatus(err), err.Error(), "UPLOAD_TERMINATE_FAILED")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkVersion exige Tus-Resumable compatível e o devolve na resposta
+func (h *TusHandler) checkVersion(w http.ResponseWriter, r *http.Request) bool {
+	w.Header().Set("Tus-Resumable", tusVersion)
+	if r.Header.Get("Tus-Resumable") != tusVersion {
+		w.Header().Set("Tus-Version", tusVersion)
+		utils.Error(w, http.StatusPreconditionFailed, "versão do protocolo tus não suportada", "TUS_VERSION_UNSUPPORTED")
+		return false
+	}
+	return true
+}
+
+// uploadErrorStatus status HTTP dos erros de upload retomável
+func uploadErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrUploadNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrUploadOffsetMismatch):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// parseUploadMetadata decodifica "chave base64,chave base64" do header Upload-Metadata
+func parseUploadMetadata(header string) map[string]string {
+	metadata := make(map[string]string)
+	for _, pair := range strings.Split(header, ",") {
+		key, encoded, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			continue
+		}
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			continue
+		}
+		metadata[key] = string(value)
+	}
+	return metadata
+}
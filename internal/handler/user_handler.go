@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// UserHandler rotas de usuários
+type UserHandler struct {
+	users    *service.UserService
+	presence *service.PresenceService
+	quotas   *service.QuotaService
+	plans    *service.PlanService
+}
+
+// NewUserHandler cria nova instância do handler
+func NewUserHandler(users *service.UserService, presence *service.PresenceService, quotas *service.QuotaService, plans *service.PlanService) *UserHandler {
+	return &UserHandler{users: users, presence: presence, quotas: quotas, plans: plans}
+}
+
+// Me GET /users/me (perfil com estatísticas de indicação; ETag)
+func (h *UserHandler) Me(w http.ResponseWriter, r *http.Request) {
+	profile, err := h.users.GetProfile(r.Context(), reqctx.UserID(r.Context()))
+	if forbidden(w, err) {
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusNotFound, err.Error(), "USER_NOT_FOUND")
+		return
+	}
+
+	utils.SuccessWithETag(w, r, http.StatusOK, profile, "")
+}
+
+// ChangeUsername PATCH /users/me/username (o antigo fica reservado por um período)
+func (h *UserHandler) ChangeUsername(w http.ResponseWriter, r *http.Request) {
+	var input types.ChangeUsernameInput
+	if err := decodeJSON(w, r, &input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "INVALID_JSON")
+		return
+	}
+	input.UserID = reqctx.UserID(r.Context())
+
+	user, err := h.users.ChangeUsername(r.Context(), input)
+	if forbidden(w, err) || accountConflict(w, err) {
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "USERNAME_CHANGE_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, user, "")
+}
+
+// Delete DELETE /users/me (soft delete: sessões revogadas; o suporte restaura
+// dentro do período de carência)
+func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	err := h.users.DeleteUser(r.Context(), reqctx.UserID(r.Context()))
+	if forbidden(w, err) {
+		return
+	}
+	if errors.Is(err, service.ErrLegalHold) {
+		utils.Error(w, http.StatusConflict, err.Error(), "LEGAL_HOLD")
+		return
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "DELETE_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, nil, "conta removida")
+}
+
+// Usage GET /users/me/usage (mensagens do dia e armazenamento contra as cotas;
+// com chave de API mostra a cota de bot)
+func (h *UserHandler) Usage(w http.ResponseWriter, r *http.Request) {
+	_, isAPIKey := reqctx.Scopes(r.Context())
+
+	usage, err := h.quotas.Usage(r.Context(), reqctx.UserID(r.Context()), isAPIKey)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "USAGE_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, usage, "")
+}
+
+// Plan GET /users/me/plan (plano em vigor e seus limites)
+func (h *UserHandler) Plan(w http.ResponseWriter, r *http.Request) {
+	plan, err := h.plans.Get(r.Context(), reqctx.UserID(r.Context()))
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "PLAN_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, plan, "")
+}
+
+// Get GET /users/{id} (email só para o próprio usuário ou integrações; ETag)
+func (h *UserHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	user, err := h.users.GetUserByID(r.Context(), id)
+	if err != nil {
+		utils.Error(w, http.StatusNotFound, err.Error(), "USER_NOT_FOUND")
+		return
+	}
+
+	if _, isAPIKey := reqctx.Scopes(r.Context()); !isAPIKey && reqctx.UserID(r.Context()) != user.ID {
+		user.Email = ""
+	}
+
+	utils.SuccessWithETag(w, r, http.StatusOK, user, "")
+}
+
+// Lookup GET /users?ids=a,b,c (lista de membros numa requisição; email só
+// para o próprio usuário ou integrações)
+func (h *UserHandler) Lookup(w http.ResponseWriter, r *http.Request) {
+	var ids []string
+	for _, value := range r.URL.Query()["ids"] {
+		for _, id := range strings.Split(value, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	resp, err := h.users.GetUsersByIDs(r.Context(), ids)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "LOOKUP_FAILED")
+		return
+	}
+
+	if _, isAPIKey := reqctx.Scopes(r.Context()); !isAPIKey {
+		viewerID := reqctx.UserID(r.Context())
+		for i := range resp.Users {
+			if resp.Users[i].ID != viewerID {
+				resp.Users[i].Email = ""
+			}
+		}
+	}
+
+	utils.SuccessWithETag(w, r, http.StatusOK, resp, "")
+}
+
+// ConversationMembers GET /conversations/{id}/members (conversa direta, ID =
+// ID do par): perfis dos dois participantes com presença, numa consulta
+func (h *UserHandler) ConversationMembers(w http.ResponseWriter, r *http.Request) {
+	viewerID := reqctx.UserID(r.Context())
+	peerID := r.PathValue("id")
+	if viewerID == "" {
+		utils.Error(w, http.StatusBadRequest, "conversa exige usuário autenticado", "MEMBERS_FAILED")
+		return
+	}
+
+	lookup, err := h.users.GetUsersByIDs(r.Context(), []string{viewerID, peerID})
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "MEMBERS_FAILED")
+		return
+	}
+	if len(lookup.Missing) > 0 || len(lookup.Users) != 2 {
+		utils.Error(w, http.StatusNotFound, "conversa não encontrada", "CONVERSATION_NOT_FOUND")
+		return
+	}
+
+	presenceViewer := viewerID
+	if _, isAPIKey := reqctx.Scopes(r.Context()); isAPIKey {
+		presenceViewer = ""
+	}
+
+	resp := types.ConversationMembersResponse{ConversationID: lookup.Users[1].ID}
+	for _, user := range lookup.Users {
+		if user.ID != viewerID {
+			user.Email = ""
+		}
+		member := types.ConversationMember{UserResponse: user, Role: "member"}
+
+		presence, err := h.presence.Get(r.Context(), presenceViewer, user.ID)
+		switch {
+		case errors.Is(err, service.ErrPresenceHidden):
+		case err != nil:
+			utils.Error(w, http.StatusBadRequest, err.Error(), "MEMBERS_FAILED")
+			return
+		default:
+			member.Online = &presence.Online
+		}
+		resp.Members = append(resp.Members, member)
+	}
+
+	utils.Success(w, http.StatusOK, resp, "")
+}
+
+// Presence GET /users/{id}/presence (respeita presence_visibility; integrações veem sempre)
+func (h *UserHandler) Presence(w http.ResponseWriter, r *http.Request) {
+	viewerID := reqctx.UserID(r.Context())
+	if _, isAPIKey := reqctx.Scopes(r.Context()); isAPIKey {
+		viewerID = ""
+	}
+
+	presence, err := h.presence.Get(r.Context(), viewerID, r.PathValue("id"))
+	switch {
+	case errors.Is(err, service.ErrPresenceHidden):
+		utils.Error(w, http.StatusForbidden, err.Error(), "PRESENCE_HIDDEN")
+		return
+	case err != nil:
+		utils.Error(w, http.StatusBadRequest, err.Error(), "PRESENCE_FAILED")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, presence, "")
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"chat-kafka-go/internal/config/configtest"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/internal/repository/repotest"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/utils"
+)
+
+const otherUserID = "c9f0f895-fb98-4b91-9d2e-6f1a3c5b7d80"
+
+func newUserHandler(t *testing.T) (*UserHandler, *repotest.DB) {
+	db := repotest.New()
+	users := service.NewUserService(repository.New(db), nil, nil, configtest.New(t))
+	return NewUserHandler(users, nil, nil, nil), db
+}
+
+// onUsernameChange usuário atual "antigo" e reserva de "maria" por reservedBy
+func onUsernameChange(t *testing.T, db *repotest.DB, reservedBy string) {
+	id, err := utils.StringToUUID(testUserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner, err := utils.StringToUUID(reservedBy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.On("GetUserByID", func([]interface{}) repotest.Result {
+		return repotest.Rows(repotest.Row(repository.User{ID: id, Username: "antigo"}))
+	})
+	db.On("GetUserByUsername", noRows)
+	db.On("GetActiveUsernameReservation", func([]interface{}) repotest.Result {
+		return repotest.Rows(repotest.Row(repository.UsernameHistory{UserID: owner, OldUsername: "maria"}))
+	})
+	db.On("UpdateUsername", func([]interface{}) repotest.Result { return repotest.Result{RowsAffected: 1} })
+	db.On("CreateUsernameHistory", func([]interface{}) repotest.Result { return repotest.Result{RowsAffected: 1} })
+}
+
+func TestChangeUsernameReservedByOther(t *testing.T) {
+	h, db := newUserHandler(t)
+	onUsernameChange(t, db, otherUserID)
+
+	code, resp := serve(t, h.ChangeUsername, http.MethodPatch, `{"username":"maria"}`, testUserID)
+	if code != http.StatusConflict || resp.Code != "USERNAME_RESERVED" {
+		t.Fatalf("resposta = %d %s; esperado 409 USERNAME_RESERVED", code, resp.Code)
+	}
+	if calls := db.Calls("UpdateUsername"); len(calls) != 0 {
+		t.Fatal("username reservado de outra conta foi aplicado")
+	}
+}
+
+func TestChangeUsernameBackToOwnReservation(t *testing.T) {
+	h, db := newUserHandler(t)
+	onUsernameChange(t, db, testUserID)
+
+	code, resp := serve(t, h.ChangeUsername, http.MethodPatch, `{"username":"maria"}`, testUserID)
+	if code != http.StatusOK {
+		t.Fatalf("status = %d (%s); esperado 200", code, resp.Error)
+	}
+	calls := db.Calls("CreateUsernameHistory")
+	if len(calls) != 1 || calls[0][1] != "antigo" {
+		t.Fatalf("histórico = %v; esperado reserva de antigo", calls)
+	}
+}
+
+func TestChangeUsernameRequiresUser(t *testing.T) {
+	h, _ := newUserHandler(t)
+
+	code, resp := serve(t, h.ChangeUsername, http.MethodPatch, `{"username":"maria"}`, "")
+	if code != http.StatusForbidden || resp.Code != "FORBIDDEN" {
+		t.Fatalf("resposta = %d %s; esperado 403 FORBIDDEN", code, resp.Code)
+	}
+}
+
+func TestDeleteMe(t *testing.T) {
+	h, db := newUserHandler(t)
+	db.On("UserHasActiveLegalHold", func([]interface{}) repotest.Result { return repotest.Rows([]interface{}{false}) })
+	db.On("SoftDeleteUser", func([]interface{}) repotest.Result { return repotest.Result{RowsAffected: 1} })
+	db.On("DeleteUserRefreshTokens", func([]interface{}) repotest.Result { return repotest.Result{RowsAffected: 2} })
+	db.On("RevokeUserAPIKeys", func([]interface{}) repotest.Result { return repotest.Result{RowsAffected: 1} })
+
+	code, resp := serve(t, h.Delete, http.MethodDelete, "", testUserID)
+	if code != http.StatusOK || !resp.Success {
+		t.Fatalf("resposta = %d %s; esperado 200", code, resp.Code)
+	}
+	// Conta, sessões e chaves na mesma transação confirmada
+	for _, name := range []string{"SoftDeleteUser", "DeleteUserRefreshTokens", "RevokeUserAPIKeys"} {
+		if txs := db.Tx(name); len(txs) != 1 || txs[0] != 1 || !db.Committed(1) {
+			t.Errorf("%s nas transações %v; esperado uma vez na transação 1 confirmada", name, txs)
+		}
+	}
+}
+
+func TestDeleteMeRollsBackOnFailure(t *testing.T) {
+	h, db := newUserHandler(t)
+	db.On("UserHasActiveLegalHold", func([]interface{}) repotest.Result { return repotest.Rows([]interface{}{false}) })
+	db.On("SoftDeleteUser", func([]interface{}) repotest.Result { return repotest.Result{RowsAffected: 1} })
+	db.On("DeleteUserRefreshTokens", func([]interface{}) repotest.Result { return repotest.Result{RowsAffected: 2} })
+	db.On("RevokeUserAPIKeys", func([]interface{}) repotest.Result { return repotest.Result{Err: errors.New("conexão perdida")} })
+
+	code, resp := serve(t, h.Delete, http.MethodDelete, "", testUserID)
+	if code != http.StatusBadRequest || resp.Code != "DELETE_FAILED" {
+		t.Fatalf("resposta = %d %s; esperado 400 DELETE_FAILED", code, resp.Code)
+	}
+	if txs := db.Tx("SoftDeleteUser"); len(txs) != 1 || !db.RolledBack(txs[0]) {
+		t.Fatalf("remoção nas transações %v; esperado rollback", txs)
+	}
+}
+
+func TestDeleteMeUnderLegalHold(t *testing.T) {
+	h, db := newUserHandler(t)
+	db.On("UserHasActiveLegalHold", func([]interface{}) repotest.Result { return repotest.Rows([]interface{}{true}) })
+
+	code, resp := serve(t, h.Delete, http.MethodDelete, "", testUserID)
+	if code != http.StatusConflict || resp.Code != "LEGAL_HOLD" {
+		t.Fatalf("resposta = %d %s; esperado 409 LEGAL_HOLD", code, resp.Code)
+	}
+	if calls := db.Calls("SoftDeleteUser"); len(calls) != 0 {
+		t.Fatal("conta removida sob retenção legal")
+	}
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"chat-kafka-go/internal/cluster"
+	"chat-kafka-go/internal/maintenance"
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/internal/ws"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/gorilla/websocket"
+)
+
+// WSHandler handshake WebSocket autenticado por ticket
+type WSHandler struct {
+	hub         *ws.Hub
+	tickets     ws.TicketStore
+	router      *cluster.Router // nil sem roteamento entre instâncias
+	maintenance *maintenance.Switch
+	upgrader    websocket.Upgrader
+}
+
+// NewWSHandler cria nova instância do handler
+// allowedOrigins vazio aceita qualquer origem; router (opcional) indica a
+// instância dona de cada usuário; clientes conectados durante a manutenção
+// recebem o evento "maintenance" logo após o handshake
+func NewWSHandler(hub *ws.Hub, tickets ws.TicketStore, router *cluster.Router, maint *maintenance.Switch, allowedOrigins []string) *WSHandler {
+	return &WSHandler{
+		hub:         hub,
+		tickets:     tickets,
+		router:      router,
+		maintenance: maint,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin:     checkOrigin(allowedOrigins),
+		},
+	}
+}
+
+// Ticket POST /ws/ticket (troca o access token por ticket de uso único, 30s)
+// Com roteamento entre instâncias, ws_url indica onde conectar
+func (h *WSHandler) Ticket(w http.ResponseWriter, r *http.Request) {
+	userID := reqctx.UserID(r.Context())
+	ticket, expiresAt, err := h.tickets.Issue(r.Context(), userID)
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, err.Error(), "TICKET_FAILED")
+		return
+	}
+
+	response := map[string]string{
+		"ticket":     ticket,
+		"expires_at": expiresAt.Format(time.RFC3339),
+	}
+	if h.router != nil {
+		if owner := h.router.Owner(userID); owner.URL != "" {
+			response["ws_url"] = owner.URL
+		}
+	}
+	utils.Success(w, http.StatusCreated, response, "")
+}
+
+// Connect GET /ws?ticket=...
+func (h *WSHandler) Connect(w http.ResponseWriter, r *http.Request) {
+	// Instância encerrando: cliente tenta de novo (o balanceador já a tirou);
+	// verificado antes de consumir o ticket
+	if h.hub.Draining() {
+		w.Header().Set("Retry-After", "1")
+		utils.Error(w, http.StatusServiceUnavailable, "instância encerrando, reconecte", "DRAINING")
+		return
+	}
+
+	userID, ok := h.tickets.Redeem(r.Context(), r.URL.Query().Get("ticket"))
+	if !ok {
+		utils.Error(w, http.StatusUnauthorized, "ticket inválido ou expirado", "UNAUTHORIZED")
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade já respondeu ao cliente
+		log.Printf("ERRO: upgrade websocket: %v", err)
+		return
+	}
+
+	// Conectou fora da instância dona: atende e pede para migrar
+	if h.router != nil && !h.router.IsLocal(userID) {
+		if owner := h.router.Owner(userID); owner.URL != "" {
+			_ = ws.WriteMigrate(conn, owner.URL)
+		}
+	}
+	if state := h.maintenance.State(); state.Active() {
+		_ = ws.WriteFrame(conn, "maintenance", state)
+	}
+
+	ws.Serve(h.hub, conn, userID)
+}
+
+// checkOrigin aceita requisições sem Origin (apps nativos) ou de origens permitidas
+func checkOrigin(allowed []string) func(r *http.Request) bool {
+	if len(allowed) == 0 {
+		return func(r *http.Request) bool { return true }
+	}
+	set := make(map[string]struct{}, len(allowed))
+	for _, o := range allowed {
+		set[o] = struct{}{}
+	}
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		u, err := url.Parse(origin)
+		if err != nil {
+			return false
+		}
+		_, ok := set[u.Scheme+"://"+u.Host]
+		return ok
+	}
+}
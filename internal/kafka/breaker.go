@@ -0,0 +1,71 @@
+package kafka
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"chat-kafka-go/internal/metrics"
+)
+
+// breaker circuito do producer: abre após falhas de entrega consecutivas e,
+// enquanto aberto, os eventos vão direto para o buffer em disco. Passado o
+// cooldown o tráfego volta ao Kafka (meio aberto): um sucesso fecha o
+// circuito, novas falhas o reabrem
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	opened    bool // Aberto ou meio aberto (até o primeiro sucesso)
+}
+
+// newBreaker cria o circuito; threshold <= 0 nunca abre
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	return &breaker{threshold: threshold, cooldown: cooldown}
+}
+
+// open indica se os envios devem ir para o buffer em disco
+func (b *breaker) open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return time.Now().Before(b.openUntil)
+}
+
+// tripped indica circuito aberto ou meio aberto (sem sucesso desde a abertura)
+func (b *breaker) tripped() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.opened
+}
+
+// failure registra falha de entrega
+func (b *breaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.threshold <= 0 || time.Now().Before(b.openUntil) {
+		return
+	}
+	b.failures++
+	if b.failures < b.threshold {
+		return
+	}
+	b.failures = 0
+	b.openUntil = time.Now().Add(b.cooldown)
+	b.opened = true
+	metrics.KafkaBreakerOpen.Set(1)
+	log.Printf("ERRO: circuito do producer Kafka aberto por %s após %d falhas de entrega", b.cooldown, b.threshold)
+}
+
+// success registra entrega confirmada
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	if b.opened && !time.Now().Before(b.openUntil) {
+		b.opened = false
+		metrics.KafkaBreakerOpen.Set(0)
+		log.Println("✓ Circuito do producer Kafka fechado")
+	}
+}
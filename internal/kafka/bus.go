@@ -0,0 +1,37 @@
+package kafka
+
+import (
+	"context"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/eventbus"
+)
+
+// Bus barramento de eventos sobre Kafka (EVENT_BUS=kafka)
+type Bus struct {
+	*Producer
+	cfg      *config.KafkaConfig
+	delivery string
+}
+
+// NewBus conecta o producer; consumers são criados por Subscribe
+func NewBus(cfg *config.KafkaConfig, delivery string) (*Bus, error) {
+	producer, err := NewProducer(cfg, delivery)
+	if err != nil {
+		return nil, err
+	}
+	return &Bus{Producer: producer, cfg: cfg, delivery: delivery}, nil
+}
+
+// SendMessage implementa eventbus.Bus
+func (b *Bus) SendMessage(ctx context.Context, topic, key string, value []byte) error {
+	return b.Producer.SendMessage(ctx, topic, key, value)
+}
+
+// Subscribe implementa eventbus.Bus (um consumer group por grupo)
+func (b *Bus) Subscribe(topic, group string, handler eventbus.Handler, pool eventbus.PoolOptions) (eventbus.Subscriber, error) {
+	cfg := *b.cfg
+	cfg.Topic = topic
+	cfg.ConsumerGroup = group
+	return NewConsumer(&cfg, b.delivery, handler, pool)
+}
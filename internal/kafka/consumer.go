@@ -0,0 +1,288 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/eventbus"
+	"chat-kafka-go/internal/recovery"
+	"chat-kafka-go/internal/reporter"
+	"chat-kafka-go/internal/reqctx"
+
+	"github.com/IBM/sarama"
+)
+
+// Consumer consome tópico em um consumer group
+type Consumer struct {
+	group    sarama.ConsumerGroup
+	groupID  string
+	topics   []string
+	delivery string
+	handler  eventbus.Handler
+	txn      *Producer // exactly-once: offsets confirmados em transação
+
+	pool    eventbus.PoolOptions
+	workers *eventbus.Pool // Workers da sessão atual (nil = sequencial)
+
+	// Pausa pela API admin: a sessão atual é encerrada e o consumer só volta
+	// ao grupo no Resume
+	mu            sync.Mutex
+	paused        bool
+	resumed       chan struct{}
+	cancelSession context.CancelFunc
+}
+
+// NewConsumer cria consumer group para o tópico configurado
+// delivery define se o offset é marcado antes (at-most-once) ou depois
+// (at-least-once) do handler, ou confirmado numa transação (exactly-once).
+// Com pool.Workers > 1 as mensagens são processadas em paralelo por chave
+// (exceto em exactly-once, que é sequencial)
+func NewConsumer(cfg *config.KafkaConfig, delivery string, handler eventbus.Handler, pool eventbus.PoolOptions) (*Consumer, error) {
+	saramaCfg := sarama.NewConfig()
+	saramaCfg.Consumer.Offsets.Initial = sarama.OffsetOldest
+	saramaCfg.Consumer.Return.Errors = true
+	saramaCfg.Version = sarama.V2_1_0_0
+	// Ignora mensagens de transações abortadas (e pendentes)
+	saramaCfg.Consumer.IsolationLevel = sarama.ReadCommitted
+
+	c := &Consumer{
+		groupID:  cfg.ConsumerGroup,
+		topics:   []string{cfg.Topic},
+		delivery: delivery,
+		handler:  handler,
+		pool:     pool,
+	}
+
+	// Um producer transacional por grupo e instância só para os offsets
+	if delivery == eventbus.DeliveryExactlyOnce {
+		txn, err := newProducer(cfg, delivery, cfg.TransactionalID+"-"+cfg.ConsumerGroup+"-"+cfg.Topic)
+		if err != nil {
+			return nil, err
+		}
+		c.txn = txn
+	}
+
+	group, err := sarama.NewConsumerGroup(cfg.Brokers, cfg.ConsumerGroup, saramaCfg)
+	if err != nil {
+		if c.txn != nil {
+			c.txn.Close()
+		}
+		return nil, fmt.Errorf("falha ao criar consumer group: %w", err)
+	}
+	c.group = group
+	register(c)
+
+	log.Println("✓ Kafka consumer conectado")
+	return c, nil
+}
+
+// Run consome até o contexto ser cancelado
+func (c *Consumer) Run(ctx context.Context) error {
+	go func() {
+		for err := range c.group.Errors() {
+			log.Printf("ERRO: consumer: %v", err)
+			reporter.CaptureError(ctx, err, map[string]string{"component": "kafka_consumer"})
+		}
+	}()
+
+	for {
+		if !c.waitResumed(ctx) {
+			return nil
+		}
+
+		sessionCtx, cancel := context.WithCancel(ctx)
+		c.mu.Lock()
+		c.cancelSession = cancel
+		if c.paused { // Pausado entre a espera e o início da sessão
+			cancel()
+		}
+		c.mu.Unlock()
+
+		// Consume retorna a cada rebalance; precisa ser chamado em loop
+		err := c.group.Consume(sessionCtx, c.topics, c)
+		cancel()
+		if err != nil {
+			if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+				return nil
+			}
+			return fmt.Errorf("erro no consumer: %w", err)
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// Close fecha o consumer group (e o producer transacional)
+func (c *Consumer) Close() error {
+	unregister(c)
+	err := c.group.Close()
+	if c.txn != nil {
+		c.txn.Close()
+	}
+	return err
+}
+
+// Pause encerra a sessão atual e não volta ao grupo até Resume; sem
+// heartbeats, o broker remove o membro após o session timeout
+func (c *Consumer) Pause() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.paused {
+		return
+	}
+	c.paused = true
+	c.resumed = make(chan struct{})
+	if c.cancelSession != nil {
+		c.cancelSession()
+	}
+}
+
+// Resume volta a consumir do último offset confirmado
+func (c *Consumer) Resume() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.paused {
+		return
+	}
+	c.paused = false
+	close(c.resumed)
+}
+
+// Paused indica se o consumer está pausado
+func (c *Consumer) Paused() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.paused
+}
+
+// waitResumed bloqueia enquanto pausado; false se o contexto acabou
+func (c *Consumer) waitResumed(ctx context.Context) bool {
+	c.mu.Lock()
+	paused, resumed := c.paused, c.resumed
+	c.mu.Unlock()
+	if !paused {
+		return ctx.Err() == nil
+	}
+
+	log.Printf("Kafka consumer %s %v pausado", c.groupID, c.topics)
+	select {
+	case <-ctx.Done():
+		return false
+	case <-resumed:
+		log.Printf("✓ Kafka consumer %s %v retomado", c.groupID, c.topics)
+		return true
+	}
+}
+
+// Setup implementa sarama.ConsumerGroupHandler; inicia os workers compartilhados
+// pelas partições da sessão
+func (c *Consumer) Setup(sarama.ConsumerGroupSession) error {
+	if c.pool.Workers > 1 && c.txn == nil {
+		c.workers = eventbus.NewPool(c.pool, "kafka_consumer", c.handler)
+	}
+	return nil
+}
+
+// Cleanup implementa sarama.ConsumerGroupHandler; espera as mensagens em voo
+// antes do commit final da sessão (rebalance)
+func (c *Consumer) Cleanup(sarama.ConsumerGroupSession) error {
+	if c.workers != nil {
+		c.workers.Close()
+		c.workers = nil
+	}
+	return nil
+}
+
+// ConsumeClaim processa mensagens de uma partição
+func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	if c.workers != nil {
+		return c.consumeParallel(session, claim)
+	}
+
+	for msg := range claim.Messages() {
+		ctx := contextFromHeaders(session.Context(), msg.Headers)
+
+		// exactly-once: offset entra na transação; falha no commit encerra a
+		// sessão e a partição é retomada do último offset confirmado
+		if c.txn != nil {
+			err := c.txn.inTxn(func() error {
+				c.handle(ctx, msg)
+				return c.txn.producer.AddMessageToTxn(msg, c.groupID, nil)
+			})
+			if err != nil {
+				return fmt.Errorf("mensagem %s/%d/%d: %w", msg.Topic, msg.Partition, msg.Offset, err)
+			}
+			continue
+		}
+
+		// at-most-once: commit síncrono antes de processar; se o processo cair
+		// durante o handler, a mensagem não volta
+		if c.delivery == eventbus.DeliveryAtMostOnce {
+			session.MarkMessage(msg, "")
+			session.Commit()
+		}
+
+		c.handle(ctx, msg)
+
+		if c.delivery != eventbus.DeliveryAtMostOnce {
+			session.MarkMessage(msg, "")
+		}
+	}
+	return nil
+}
+
+// consumeParallel distribui as mensagens da partição entre os workers; em
+// at-least-once o offset só avança até a última mensagem contígua concluída
+func (c *Consumer) consumeParallel(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	tracker := eventbus.NewOffsetTracker(func(offset int64) {
+		session.MarkOffset(claim.Topic(), claim.Partition(), offset+1, "")
+	})
+
+	for msg := range claim.Messages() {
+		ctx := contextFromHeaders(session.Context(), msg.Headers)
+
+		if c.delivery == eventbus.DeliveryAtMostOnce {
+			session.MarkMessage(msg, "")
+			session.Commit()
+			c.workers.Submit(ctx, toMessage(msg), nil)
+			continue
+		}
+
+		offset := msg.Offset
+		tracker.Add(offset)
+		c.workers.Submit(ctx, toMessage(msg), func() { tracker.Done(offset) })
+	}
+	return nil
+}
+
+// handle chama o handler; erro ou panic é registrado e a mensagem segue como processada
+func (c *Consumer) handle(ctx context.Context, msg *sarama.ConsumerMessage) {
+	err := recovery.Guard(ctx, "kafka_consumer", func() error {
+		return c.handler(ctx, toMessage(msg))
+	})
+	if err != nil {
+		log.Printf("ERRO: mensagem %s/%d/%d request_id=%s: %v",
+			msg.Topic, msg.Partition, msg.Offset, reqctx.RequestID(ctx), err)
+		reporter.CaptureError(ctx, err, map[string]string{
+			"component":  "kafka_consumer",
+			"topic":      msg.Topic,
+			"request_id": reqctx.RequestID(ctx),
+		})
+	}
+}
+
+// toMessage converte a mensagem do sarama para o formato do barramento
+func toMessage(msg *sarama.ConsumerMessage) *eventbus.Message {
+	return &eventbus.Message{
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Key:       string(msg.Key),
+		Value:     msg.Value,
+	}
+}
@@ -0,0 +1,193 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/eventbus"
+
+	"github.com/IBM/sarama"
+)
+
+// ErrNotConnected envio antes do Kafka conectar, sem buffer em disco
+var ErrNotConnected = errors.New("Kafka ainda não conectado")
+
+// DeferredBus barramento Kafka que sobe sem brokers (STARTUP_EVENT_BUS_MODE=degraded)
+// Até conectar, eventos publicados vão para o buffer em disco (sem buffer o
+// envio falha) e as assinaturas esperam; depois tudo passa ao Bus normal e
+// o buffer é reenviado
+type DeferredBus struct {
+	cfg      *config.KafkaConfig
+	delivery string
+	spill    *Spill
+
+	mu    sync.RWMutex
+	bus   *Bus          // nil até conectar
+	ready chan struct{} // Fechado ao conectar
+	stop  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewDeferredBus tenta conectar na hora; falhando, segue tentando em background
+func NewDeferredBus(cfg *config.KafkaConfig, delivery string, retryInterval time.Duration) (*DeferredBus, error) {
+	spill, err := openSpill(cfg, delivery)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &DeferredBus{
+		cfg:      cfg,
+		delivery: delivery,
+		spill:    spill,
+		ready:    make(chan struct{}),
+		stop:     make(chan struct{}),
+	}
+	if err := b.connect(); err != nil {
+		log.Printf("WARN: Kafka indisponível no boot, conectando em background: %v", err)
+		b.wg.Add(1)
+		go b.connectLoop(retryInterval)
+	}
+	return b, nil
+}
+
+// SendMessage implementa eventbus.Bus; sem conexão o evento vai para o buffer
+func (b *DeferredBus) SendMessage(ctx context.Context, topic, key string, value []byte) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.bus != nil {
+		return b.bus.SendMessage(ctx, topic, key, value)
+	}
+	if b.spill == nil {
+		return ErrNotConnected
+	}
+	return b.spill.Store(&sarama.ProducerMessage{
+		Topic:   topic,
+		Key:     sarama.StringEncoder(key),
+		Value:   sarama.ByteEncoder(value),
+		Headers: headersFromContext(ctx),
+	})
+}
+
+// Subscribe implementa eventbus.Bus; o consumer é criado quando o Kafka conectar
+func (b *DeferredBus) Subscribe(topic, group string, handler eventbus.Handler, pool eventbus.PoolOptions) (eventbus.Subscriber, error) {
+	return &deferredSubscriber{
+		bus:     b,
+		topic:   topic,
+		group:   group,
+		handler: handler,
+		pool:    pool,
+	}, nil
+}
+
+// Degraded implementa eventbus.Degradable
+func (b *DeferredBus) Degraded() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.bus == nil || b.bus.Degraded()
+}
+
+// Close implementa eventbus.Bus (eventos no buffer ficam para o próximo boot)
+func (b *DeferredBus) Close() error {
+	close(b.stop)
+	b.wg.Wait()
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.bus != nil {
+		return b.bus.Close()
+	}
+	if b.spill != nil {
+		return b.spill.Close()
+	}
+	return nil
+}
+
+// connect cria o producer usando o buffer já aberto
+func (b *DeferredBus) connect() error {
+	producer, err := newSpillingProducer(b.cfg, b.delivery, b.spill)
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	b.bus = &Bus{Producer: producer, cfg: b.cfg, delivery: b.delivery}
+	b.mu.Unlock()
+	close(b.ready)
+	return nil
+}
+
+// connectLoop tenta conectar a cada intervalo até conseguir ou fechar
+func (b *DeferredBus) connectLoop(interval time.Duration) {
+	defer b.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+		}
+		if err := b.connect(); err != nil {
+			log.Printf("WARN: Kafka ainda indisponível: %v", err)
+			continue
+		}
+		log.Println("✓ Kafka conectado, saindo do modo degradado")
+		return
+	}
+}
+
+// deferredSubscriber assinatura que começa a consumir quando o Kafka conectar
+type deferredSubscriber struct {
+	bus     *DeferredBus
+	topic   string
+	group   string
+	handler eventbus.Handler
+	pool    eventbus.PoolOptions
+
+	mu     sync.Mutex
+	sub    eventbus.Subscriber
+	closed bool
+}
+
+// Run implementa eventbus.Subscriber
+func (s *deferredSubscriber) Run(ctx context.Context) error {
+	select {
+	case <-s.bus.ready:
+	case <-ctx.Done():
+		return nil
+	}
+
+	s.bus.mu.RLock()
+	bus := s.bus.bus
+	s.bus.mu.RUnlock()
+
+	sub, err := bus.Subscribe(s.topic, s.group, s.handler, s.pool)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return sub.Close()
+	}
+	s.sub = sub
+	s.mu.Unlock()
+
+	return sub.Run(ctx)
+}
+
+// Close implementa eventbus.Subscriber
+func (s *deferredSubscriber) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	if s.sub != nil {
+		return s.sub.Close()
+	}
+	return nil
+}
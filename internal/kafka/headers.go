@@ -0,0 +1,51 @@
+package kafka
+
+import (
+	"context"
+
+	"chat-kafka-go/internal/reqctx"
+
+	"github.com/IBM/sarama"
+)
+
+// Headers propagados do produtor para o consumidor
+const (
+	HeaderRequestID = "x-request-id"
+	HeaderTenantID  = "x-tenant-id"
+	HeaderUserID    = "x-user-id"
+)
+
+// headersFromContext monta headers Kafka a partir do contexto da requisição
+func headersFromContext(ctx context.Context) []sarama.RecordHeader {
+	var headers []sarama.RecordHeader
+
+	add := func(key, value string) {
+		if value != "" {
+			headers = append(headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+		}
+	}
+
+	add(HeaderRequestID, reqctx.RequestID(ctx))
+	add(HeaderTenantID, reqctx.TenantID(ctx))
+	add(HeaderUserID, reqctx.UserID(ctx))
+
+	return headers
+}
+
+// contextFromHeaders restaura request ID, tenant e usuário no contexto do consumidor
+func contextFromHeaders(ctx context.Context, headers []*sarama.RecordHeader) context.Context {
+	for _, h := range headers {
+		if h == nil {
+			continue
+		}
+		switch string(h.Key) {
+		case HeaderRequestID:
+			ctx = reqctx.WithRequestID(ctx, string(h.Value))
+		case HeaderTenantID:
+			ctx = reqctx.WithTenantID(ctx, string(h.Value))
+		case HeaderUserID:
+			ctx = reqctx.WithUserID(ctx, string(h.Value))
+		}
+	}
+	return ctx
+}
@@ -0,0 +1,267 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"chat-kafka-go/internal/config"
+
+	"github.com/IBM/sarama"
+)
+
+// ErrGroupActive reset de offsets com membros conectados ao grupo
+var ErrGroupActive = errors.New("grupo com consumidores ativos: pause o consumo em todas as instâncias e aguarde o session timeout")
+
+// PartitionOffset posição do grupo numa partição
+type PartitionOffset struct {
+	Partition int32 `json:"partition"`
+	Committed int64 `json:"committed"` // -1 = grupo sem commit na partição
+	Oldest    int64 `json:"oldest"`
+	HighWater int64 `json:"high_water"`
+	Lag       int64 `json:"lag"`
+}
+
+// GroupStatus offsets e estado do grupo num tópico
+type GroupStatus struct {
+	Group      string            `json:"group"`
+	Topic      string            `json:"topic"`
+	State      string            `json:"state"`  // Estado no coordenador (Stable, Empty...)
+	Paused     bool              `json:"paused"` // Consumers desta instância pausados
+	Partitions []PartitionOffset `json:"partitions"`
+}
+
+// OffsetAdmin consulta e move offsets de consumer groups (API admin)
+type OffsetAdmin struct {
+	client sarama.Client
+	admin  sarama.ClusterAdmin
+}
+
+// NewOffsetAdmin conecta ao cluster com um client próprio
+func NewOffsetAdmin(cfg *config.KafkaConfig) (*OffsetAdmin, error) {
+	saramaCfg := sarama.NewConfig()
+	saramaCfg.Version = sarama.V2_1_0_0
+
+	client, err := sarama.NewClient(cfg.Brokers, saramaCfg)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao conectar no Kafka: %w", err)
+	}
+	admin, err := sarama.NewClusterAdminFromClient(client)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("falha ao criar admin do Kafka: %w", err)
+	}
+	return &OffsetAdmin{client: client, admin: admin}, nil
+}
+
+// Close fecha o admin (e o client)
+func (a *OffsetAdmin) Close() error {
+	return a.admin.Close()
+}
+
+// Status retorna offset confirmado, limites e lag de cada partição
+func (a *OffsetAdmin) Status(group, topic string) (*GroupStatus, error) {
+	state, err := a.groupState(group)
+	if err != nil {
+		return nil, err
+	}
+	partitions, err := a.client.Partitions(topic)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar partições: %w", err)
+	}
+	fetched, err := a.admin.ListConsumerGroupOffsets(group, map[string][]int32{topic: partitions})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar offsets do grupo: %w", err)
+	}
+
+	status := &GroupStatus{
+		Group:      group,
+		Topic:      topic,
+		State:      state,
+		Paused:     GroupPaused(group),
+		Partitions: []PartitionOffset{},
+	}
+	for _, p := range partitions {
+		po := PartitionOffset{Partition: p, Committed: -1}
+		if block := fetched.GetBlock(topic, p); block != nil && block.Err == sarama.ErrNoError {
+			po.Committed = block.Offset
+		}
+		if po.Oldest, err = a.client.GetOffset(topic, p, sarama.OffsetOldest); err != nil {
+			return nil, fmt.Errorf("erro ao buscar offset inicial da partição %d: %w", p, err)
+		}
+		if po.HighWater, err = a.client.GetOffset(topic, p, sarama.OffsetNewest); err != nil {
+			return nil, fmt.Errorf("erro ao buscar high water mark da partição %d: %w", p, err)
+		}
+		// Sem commit, o grupo começa do início (Offsets.Initial = OffsetOldest)
+		po.Lag = po.HighWater - max(po.Committed, po.Oldest)
+		status.Partitions = append(status.Partitions, po)
+	}
+	sort.Slice(status.Partitions, func(i, j int) bool {
+		return status.Partitions[i].Partition < status.Partitions[j].Partition
+	})
+	return status, nil
+}
+
+// ResetToOffset move o grupo para o offset em todas as partições (limitado ao
+// intervalo disponível); aceita sarama.OffsetOldest e sarama.OffsetNewest
+func (a *OffsetAdmin) ResetToOffset(group, topic string, offset int64) (*GroupStatus, error) {
+	return a.reset(group, topic, func(p int32) (int64, error) {
+		switch offset {
+		case sarama.OffsetOldest, sarama.OffsetNewest:
+			return a.client.GetOffset(topic, p, offset)
+		}
+		oldest, err := a.client.GetOffset(topic, p, sarama.OffsetOldest)
+		if err != nil {
+			return 0, err
+		}
+		newest, err := a.client.GetOffset(topic, p, sarama.OffsetNewest)
+		if err != nil {
+			return 0, err
+		}
+		return min(max(offset, oldest), newest), nil
+	})
+}
+
+// ResetToTime move o grupo para a primeira mensagem em ou após t (fim da
+// partição se não houver)
+func (a *OffsetAdmin) ResetToTime(group, topic string, t time.Time) (*GroupStatus, error) {
+	return a.reset(group, topic, func(p int32) (int64, error) {
+		offset, err := a.client.GetOffset(topic, p, t.UnixMilli())
+		if err != nil {
+			return 0, err
+		}
+		if offset == -1 { // Nenhuma mensagem depois de t
+			return a.client.GetOffset(topic, p, sarama.OffsetNewest)
+		}
+		return offset, nil
+	})
+}
+
+// reset confirma o offset calculado para cada partição; exige grupo sem membros
+func (a *OffsetAdmin) reset(group, topic string, target func(partition int32) (int64, error)) (*GroupStatus, error) {
+	state, err := a.groupState(group)
+	if err != nil {
+		return nil, err
+	}
+	if state != "Empty" && state != "Dead" {
+		return nil, ErrGroupActive
+	}
+
+	partitions, err := a.client.Partitions(topic)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar partições: %w", err)
+	}
+
+	req := &sarama.OffsetCommitRequest{
+		Version:                 2,
+		ConsumerGroup:           group,
+		ConsumerGroupGeneration: -1, // Commit fora de sessão (grupo vazio)
+		RetentionTime:           -1,
+	}
+	for _, p := range partitions {
+		offset, err := target(p)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao calcular offset da partição %d: %w", p, err)
+		}
+		req.AddBlock(topic, p, offset, 0, "reset via admin")
+	}
+
+	coordinator, err := a.client.Coordinator(group)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao localizar coordenador do grupo: %w", err)
+	}
+	resp, err := coordinator.CommitOffset(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao confirmar offsets: %w", err)
+	}
+	for _, partitionErrs := range resp.Errors {
+		for p, kerr := range partitionErrs {
+			if kerr != sarama.ErrNoError {
+				return nil, fmt.Errorf("erro ao confirmar offset da partição %d: %w", p, kerr)
+			}
+		}
+	}
+
+	log.Printf("✓ Offsets do grupo %s no tópico %s redefinidos", group, topic)
+	return a.Status(group, topic)
+}
+
+// groupState estado do grupo no coordenador
+func (a *OffsetAdmin) groupState(group string) (string, error) {
+	groups, err := a.admin.DescribeConsumerGroups([]string{group})
+	if err != nil {
+		return "", fmt.Errorf("erro ao descrever grupo: %w", err)
+	}
+	if len(groups) == 0 {
+		return "Dead", nil
+	}
+	if groups[0].Err != sarama.ErrNoError {
+		return "", fmt.Errorf("erro ao descrever grupo: %w", groups[0].Err)
+	}
+	return groups[0].State, nil
+}
+
+// Consumers desta instância por grupo (pausa/retomada pela API admin)
+var (
+	registryMu sync.Mutex
+	registry   = map[string][]*Consumer{}
+)
+
+// register inclui o consumer no registro do grupo
+func register(c *Consumer) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[c.groupID] = append(registry[c.groupID], c)
+}
+
+// unregister remove o consumer do registro
+func unregister(c *Consumer) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	consumers := registry[c.groupID]
+	for i, other := range consumers {
+		if other == c {
+			registry[c.groupID] = append(consumers[:i], consumers[i+1:]...)
+			break
+		}
+	}
+	if len(registry[c.groupID]) == 0 {
+		delete(registry, c.groupID)
+	}
+}
+
+// PauseGroup pausa os consumers do grupo nesta instância (inclusive estágios
+// de retry); retorna quantos foram pausados
+func PauseGroup(group string) int {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, c := range registry[group] {
+		c.Pause()
+	}
+	return len(registry[group])
+}
+
+// ResumeGroup retoma os consumers do grupo nesta instância
+func ResumeGroup(group string) int {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, c := range registry[group] {
+		c.Resume()
+	}
+	return len(registry[group])
+}
+
+// GroupPaused indica se algum consumer do grupo está pausado nesta instância
+func GroupPaused(group string) bool {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, c := range registry[group] {
+		if c.Paused() {
+			return true
+		}
+	}
+	return false
+}
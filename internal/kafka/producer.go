@@ -0,0 +1,306 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/eventbus"
+	"chat-kafka-go/internal/metrics"
+	"chat-kafka-go/internal/reporter"
+
+	"github.com/IBM/sarama"
+)
+
+// Producer envia mensagens para o Kafka (implementa service.KafkaProducer)
+// Usa o producer assíncrono do sarama: mensagens são agrupadas em lotes
+// e o resultado chega pelos canais de delivery report
+// Com KAFKA_SPILL_DIR, eventos que o Kafka não aceita (falha de entrega ou
+// circuito aberto) vão para um buffer em disco e são reenviados quando os
+// brokers voltam; reenviados chegam depois de eventos mais novos da mesma chave
+type Producer struct {
+	producer sarama.AsyncProducer
+	wg       sync.WaitGroup
+	txMu     sync.Mutex // exactly-once: uma transação aberta por vez
+
+	breaker       *breaker
+	spill         *Spill // nil = sem buffer (eventos não entregues são descartados)
+	stop          chan struct{}
+	replayWG      sync.WaitGroup
+	replayPending atomic.Int64 // Eventos do reenvio atual sem confirmação
+	replayDone    func()
+}
+
+// spillReplay marca (Metadata) eventos reenviados do buffer em disco
+type spillReplay struct{}
+
+// NewProducer cria producer assíncrono com batching configurável
+// at-least-once: acks de todas as réplicas + producer idempotente (retries
+// não duplicam nem reordenam; o sarama exige uma requisição em voo por broker)
+// at-most-once: sem ack do broker e sem retries
+// exactly-once: idempotente + transacional, uma transação por mensagem
+func NewProducer(cfg *config.KafkaConfig, delivery string) (*Producer, error) {
+	spill, err := openSpill(cfg, delivery)
+	if err != nil {
+		return nil, err
+	}
+	p, err := newSpillingProducer(cfg, delivery, spill)
+	if err != nil && spill != nil {
+		spill.Close()
+	}
+	return p, err
+}
+
+// openSpill abre o buffer em disco configurado (nil se desativado)
+// Transação precisa da confirmação do Kafka: sem buffer em exactly-once
+func openSpill(cfg *config.KafkaConfig, delivery string) (*Spill, error) {
+	if cfg.SpillDir == "" || delivery == eventbus.DeliveryExactlyOnce {
+		return nil, nil
+	}
+	return OpenSpill(cfg.SpillDir, cfg.SpillMaxBytes)
+}
+
+// newSpillingProducer cria o producer do barramento com o buffer já aberto
+// (spill nil = sem buffer); o buffer passa a ser fechado pelo producer
+func newSpillingProducer(cfg *config.KafkaConfig, delivery string, spill *Spill) (*Producer, error) {
+	p, err := newProducer(cfg, delivery, cfg.TransactionalID)
+	if err != nil {
+		return nil, err
+	}
+
+	if spill != nil {
+		p.spill = spill
+		p.replayWG.Add(1)
+		go p.replayLoop(cfg.BreakerCooldown)
+		log.Printf("✓ Buffer de contingência do producer em %s", cfg.SpillDir)
+	}
+
+	log.Println("✓ Kafka producer conectado")
+	return p, nil
+}
+
+// newProducer cria o producer; transactionalID só é usado em exactly-once
+func newProducer(cfg *config.KafkaConfig, delivery, transactionalID string) (*Producer, error) {
+	saramaCfg := sarama.NewConfig()
+	saramaCfg.Producer.Return.Successes = true
+	saramaCfg.Producer.Return.Errors = true
+	saramaCfg.Producer.Flush.Frequency = cfg.Linger
+	saramaCfg.Producer.Flush.Bytes = cfg.BatchBytes
+	saramaCfg.Producer.Flush.MaxMessages = cfg.BatchMaxMsgs
+	saramaCfg.Net.MaxOpenRequests = cfg.MaxInFlight
+	saramaCfg.Version = sarama.V2_1_0_0 // Necessário para headers
+
+	switch delivery {
+	case eventbus.DeliveryAtMostOnce:
+		saramaCfg.Producer.RequiredAcks = sarama.NoResponse
+		saramaCfg.Producer.Retry.Max = 0
+	default:
+		saramaCfg.Producer.RequiredAcks = sarama.WaitForAll
+		saramaCfg.Producer.Retry.Max = max(cfg.RetryMax, 1)
+		saramaCfg.Producer.Idempotent = true
+		saramaCfg.Net.MaxOpenRequests = 1
+		if delivery == eventbus.DeliveryExactlyOnce {
+			saramaCfg.Producer.Transaction.ID = transactionalID
+		}
+	}
+
+	producer, err := sarama.NewAsyncProducer(cfg.Brokers, saramaCfg)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao criar producer: %w", err)
+	}
+
+	p := &Producer{
+		producer: producer,
+		breaker:  newBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown),
+		stop:     make(chan struct{}),
+	}
+	p.wg.Add(2)
+	go p.handleSuccesses()
+	go p.handleErrors()
+	return p, nil
+}
+
+// SendMessage enfileira mensagem propagando request ID, tenant e usuário como headers
+// Retorna assim que a mensagem entra no buffer; falhas de entrega chegam em handleErrors
+// Producer transacional retorna só depois do commit da transação
+// Com o circuito aberto a mensagem vai direto para o buffer em disco
+func (p *Producer) SendMessage(ctx context.Context, topic string, key string, value []byte) error {
+	msg := &sarama.ProducerMessage{
+		Topic:   topic,
+		Key:     sarama.StringEncoder(key),
+		Value:   sarama.ByteEncoder(value),
+		Headers: headersFromContext(ctx),
+	}
+
+	if p.producer.IsTransactional() {
+		return p.sendInTxn(ctx, msg)
+	}
+	if p.spill != nil && p.breaker.open() {
+		return p.spillMessage(msg)
+	}
+
+	select {
+	case p.producer.Input() <- msg:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("falha ao enfileirar mensagem: %w", ctx.Err())
+	}
+}
+
+// sendInTxn publica a mensagem numa transação própria
+func (p *Producer) sendInTxn(ctx context.Context, msg *sarama.ProducerMessage) error {
+	return p.inTxn(func() error {
+		select {
+		case p.producer.Input() <- msg:
+			return nil
+		case <-ctx.Done():
+			return fmt.Errorf("falha ao enfileirar mensagem: %w", ctx.Err())
+		}
+	})
+}
+
+// inTxn executa fn numa transação: commit se fn e o flush derem certo, abort
+// caso contrário. Erro fatal deixa o producer inutilizável (reiniciar o processo)
+func (p *Producer) inTxn(fn func() error) error {
+	p.txMu.Lock()
+	defer p.txMu.Unlock()
+
+	if err := p.producer.BeginTxn(); err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+
+	err := fn()
+	if err == nil {
+		if err = p.producer.CommitTxn(); err == nil {
+			metrics.KafkaTransactionsTotal.WithLabelValues("committed").Inc()
+			return nil
+		}
+		err = fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	if p.producer.TxnStatus()&sarama.ProducerTxnFlagFatalError != 0 {
+		return fmt.Errorf("producer transacional em erro fatal: %w", err)
+	}
+	if abortErr := p.producer.AbortTxn(); abortErr != nil {
+		return fmt.Errorf("erro ao abortar transação: %w (causa: %v)", abortErr, err)
+	}
+	metrics.KafkaTransactionsTotal.WithLabelValues("aborted").Inc()
+	return err
+}
+
+// handleSuccesses consome delivery reports de sucesso
+func (p *Producer) handleSuccesses() {
+	defer p.wg.Done()
+	for msg := range p.producer.Successes() {
+		metrics.KafkaProducedTotal.WithLabelValues(msg.Topic, "success").Inc()
+		p.breaker.success()
+		p.ackReplay(msg)
+	}
+}
+
+// handleErrors consome delivery reports de erro (após esgotar retries)
+func (p *Producer) handleErrors() {
+	defer p.wg.Done()
+	for perr := range p.producer.Errors() {
+		metrics.KafkaProducedTotal.WithLabelValues(perr.Msg.Topic, "error").Inc()
+		p.breaker.failure()
+
+		if p.spill != nil {
+			// Evento vai para o buffer; falha de reenvio volta para o buffer novo
+			_ = p.spillMessage(perr.Msg)
+			p.ackReplay(perr.Msg)
+			continue
+		}
+
+		log.Printf("ERRO: falha na entrega ao Kafka (topic=%s): %v", perr.Msg.Topic, perr.Err)
+		reporter.CaptureError(context.Background(), perr.Err, map[string]string{
+			"component": "kafka_producer",
+			"topic":     perr.Msg.Topic,
+		})
+	}
+}
+
+// Degraded implementa eventbus.Degradable: circuito aberto ou eventos no
+// buffer em disco aguardando reenvio
+func (p *Producer) Degraded() bool {
+	return p.breaker.tripped() || (p.spill != nil && p.spill.Pending())
+}
+
+// spillMessage grava a mensagem no buffer em disco; buffer cheio perde o evento
+func (p *Producer) spillMessage(msg *sarama.ProducerMessage) error {
+	return p.spill.Store(msg)
+}
+
+// replayLoop reenvia o buffer em disco quando o circuito está fechado
+// (um reenvio por vez; o próximo só depois de todos confirmados)
+func (p *Producer) replayLoop(interval time.Duration) {
+	defer p.replayWG.Done()
+	ticker := time.NewTicker(max(interval, time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+		if p.breaker.open() || p.replayPending.Load() > 0 || !p.spill.Pending() {
+			continue
+		}
+		p.replay()
+	}
+}
+
+// replay enfileira os eventos guardados no producer
+func (p *Producer) replay() {
+	msgs, done, err := p.spill.Drain()
+	if err != nil {
+		log.Printf("ERRO: reenvio do buffer de contingência: %v", err)
+		return
+	}
+	if len(msgs) == 0 {
+		done()
+		return
+	}
+
+	log.Printf("Reenviando %d eventos do buffer de contingência", len(msgs))
+	p.replayDone = done
+	p.replayPending.Store(int64(len(msgs)))
+	for _, msg := range msgs {
+		msg.Metadata = spillReplay{}
+		select {
+		case p.producer.Input() <- msg:
+		case <-p.stop:
+			return // Arquivo de reenvio fica em disco para a próxima inicialização
+		}
+	}
+}
+
+// ackReplay conta o delivery report de um evento reenviado; com todos
+// confirmados (ou de volta ao buffer) o arquivo de reenvio é removido
+func (p *Producer) ackReplay(msg *sarama.ProducerMessage) {
+	if _, ok := msg.Metadata.(spillReplay); !ok {
+		return
+	}
+	if p.replayPending.Add(-1) == 0 {
+		p.replayDone()
+		log.Println("✓ Buffer de contingência reenviado ao Kafka")
+	}
+}
+
+// Close envia mensagens pendentes e aguarda os delivery reports
+// Eventos ainda no buffer em disco são reenviados na próxima inicialização
+func (p *Producer) Close() error {
+	close(p.stop)
+	p.replayWG.Wait()
+	p.producer.AsyncClose()
+	p.wg.Wait()
+	if p.spill != nil {
+		return p.spill.Close()
+	}
+	return nil
+}
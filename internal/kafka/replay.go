@@ -0,0 +1,160 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/eventbus"
+	"chat-kafka-go/internal/recovery"
+
+	"github.com/IBM/sarama"
+)
+
+// replayIdleTimeout sem mensagens nesse prazo, a partição é dada como lida:
+// o restante até o high water mark são marcadores de transação (commit/abort),
+// que ocupam offsets mas nunca chegam ao consumidor
+const replayIdleTimeout = 10 * time.Second
+
+// Offsets próximo offset a ler (ou limite exclusivo) por partição
+type Offsets map[int32]int64
+
+// Replayer relê o tópico do início até um limite fixo, sem consumer group
+// (não mexe nos offsets dos workers). Usado para reconstruir modelos de leitura
+type Replayer struct {
+	client   sarama.Client
+	consumer sarama.Consumer
+	topic    string
+}
+
+// NewReplayer conecta ao cluster para reler o tópico configurado
+func NewReplayer(cfg *config.KafkaConfig) (*Replayer, error) {
+	saramaCfg := sarama.NewConfig()
+	saramaCfg.Consumer.Return.Errors = true
+	saramaCfg.Version = sarama.V2_1_0_0
+	saramaCfg.Consumer.IsolationLevel = sarama.ReadCommitted
+
+	client, err := sarama.NewClient(cfg.Brokers, saramaCfg)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao conectar no Kafka: %w", err)
+	}
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("falha ao criar consumer: %w", err)
+	}
+
+	log.Println("✓ Kafka replayer conectado")
+	return &Replayer{client: client, consumer: consumer, topic: cfg.Topic}, nil
+}
+
+// Close fecha consumer e conexão
+func (r *Replayer) Close() error {
+	if err := r.consumer.Close(); err != nil {
+		return err
+	}
+	return r.client.Close()
+}
+
+// Bounds retorna o primeiro offset disponível e o high water mark (limite
+// exclusivo) de cada partição no momento da chamada
+func (r *Replayer) Bounds() (oldest, newest Offsets, err error) {
+	partitions, err := r.client.Partitions(r.topic)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao listar partições: %w", err)
+	}
+
+	oldest, newest = Offsets{}, Offsets{}
+	for _, p := range partitions {
+		if oldest[p], err = r.client.GetOffset(r.topic, p, sarama.OffsetOldest); err != nil {
+			return nil, nil, fmt.Errorf("erro ao buscar offset inicial da partição %d: %w", p, err)
+		}
+		if newest[p], err = r.client.GetOffset(r.topic, p, sarama.OffsetNewest); err != nil {
+			return nil, nil, fmt.Errorf("erro ao buscar high water mark da partição %d: %w", p, err)
+		}
+	}
+	return oldest, newest, nil
+}
+
+// Replay lê cada partição de from[p] até until[p] (exclusivo), em paralelo
+// entre partições e em ordem dentro de cada uma. done é chamado após cada
+// mensagem processada com o próximo offset da partição (checkpoint).
+// O primeiro erro do handler interrompe a releitura: o checkpoint aponta
+// para a mensagem que falhou
+func (r *Replayer) Replay(ctx context.Context, from, until Offsets, handler eventbus.Handler, done func(partition int32, next int64)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	for partition, end := range until {
+		start := from[partition]
+		if start >= end {
+			continue
+		}
+
+		pc, err := r.consumer.ConsumePartition(r.topic, partition, start)
+		if err != nil {
+			return fmt.Errorf("erro ao ler partição %d: %w", partition, err)
+		}
+
+		wg.Add(1)
+		go func(partition int32, end int64, pc sarama.PartitionConsumer) {
+			defer wg.Done()
+			defer pc.Close()
+
+			idle := time.NewTimer(replayIdleTimeout)
+			defer idle.Stop()
+
+			for {
+				select {
+				case <-idle.C:
+					log.Printf("Partição %d: sem mensagens até o offset %d (marcadores de transação)", partition, end)
+					done(partition, end)
+					return
+				case <-ctx.Done():
+					fail(ctx.Err())
+					return
+				case err := <-pc.Errors():
+					fail(fmt.Errorf("erro na partição %d: %w", partition, err))
+					return
+				case msg := <-pc.Messages():
+					msgCtx := contextFromHeaders(ctx, msg.Headers)
+					err := recovery.Guard(msgCtx, "kafka_replay", func() error {
+						return handler(msgCtx, toMessage(msg))
+					})
+					if err != nil {
+						fail(fmt.Errorf("mensagem %s/%d/%d: %w", msg.Topic, msg.Partition, msg.Offset, err))
+						return
+					}
+					done(partition, msg.Offset+1)
+					if msg.Offset+1 >= end {
+						return
+					}
+					if !idle.Stop() {
+						<-idle.C
+					}
+					idle.Reset(replayIdleTimeout)
+				}
+			}
+		}(partition, end, pc)
+	}
+
+	wg.Wait()
+	return firstErr
+}
@@ -0,0 +1,243 @@
+package kafka
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"chat-kafka-go/internal/metrics"
+	"chat-kafka-go/internal/reporter"
+
+	"github.com/IBM/sarama"
+)
+
+// ErrSpillFull buffer em disco no limite: o evento é perdido
+var ErrSpillFull = errors.New("buffer de contingência do producer cheio")
+
+// Arquivos do buffer: spill.wal recebe novos eventos; no reenvio ele vira
+// spill.replay, removido só depois que o Kafka confirma todos os eventos
+const (
+	spillFile  = "spill.wal"
+	replayFile = "spill.replay"
+)
+
+// spillRecord evento guardado em disco (uma linha JSON por evento)
+type spillRecord struct {
+	Topic   string            `json:"topic"`
+	Key     string            `json:"key"`
+	Value   []byte            `json:"value"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// Spill buffer local limitado para eventos que o Kafka não aceitou
+// Uma queda durante o reenvio reenvia de novo na próxima inicialização
+// (duplicatas possíveis, perda não)
+type Spill struct {
+	mu       sync.Mutex
+	dir      string
+	maxBytes int64
+	file     *os.File
+	size     int64 // Bytes em spill.wal + spill.replay
+}
+
+// OpenSpill abre (ou cria) o buffer no diretório; eventos de execuções
+// anteriores ficam pendentes para reenvio
+func OpenSpill(dir string, maxBytes int64) (*Spill, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("erro ao criar diretório do buffer: %w", err)
+	}
+	s := &Spill{dir: dir, maxBytes: maxBytes}
+	if err := s.openFile(); err != nil {
+		return nil, err
+	}
+	for _, name := range []string{spillFile, replayFile} {
+		if info, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			s.size += info.Size()
+		}
+	}
+	metrics.KafkaSpillBytes.Set(float64(s.size))
+	return s, nil
+}
+
+// Append grava o evento; ErrSpillFull se ultrapassar o limite
+func (s *Spill) Append(msg *sarama.ProducerMessage) error {
+	line, err := encodeSpillRecord(msg)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.maxBytes > 0 && s.size+int64(len(line)) > s.maxBytes {
+		return ErrSpillFull
+	}
+	if _, err := s.file.Write(line); err != nil {
+		return fmt.Errorf("erro ao gravar no buffer: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("erro ao sincronizar buffer: %w", err)
+	}
+	s.size += int64(len(line))
+	metrics.KafkaSpillBytes.Set(float64(s.size))
+	return nil
+}
+
+// Store grava o evento contabilizando métricas; falha (buffer cheio ou erro
+// de disco) perde o evento e é reportada como alerta
+func (s *Spill) Store(msg *sarama.ProducerMessage) error {
+	err := s.Append(msg)
+	if err == nil {
+		metrics.KafkaSpilledTotal.WithLabelValues(msg.Topic).Inc()
+		return nil
+	}
+
+	if errors.Is(err, ErrSpillFull) {
+		metrics.KafkaSpillDroppedTotal.WithLabelValues(msg.Topic).Inc()
+	}
+	log.Printf("ERRO: evento perdido, buffer de contingência indisponível (topic=%s): %v", msg.Topic, err)
+	reporter.CaptureError(context.Background(), err, map[string]string{
+		"component": "kafka_spill",
+		"topic":     msg.Topic,
+	})
+	return fmt.Errorf("falha ao guardar mensagem: %w", err)
+}
+
+// Pending indica se há eventos aguardando reenvio
+func (s *Spill) Pending() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size > 0
+}
+
+// Drain separa os eventos guardados para reenvio; done remove o arquivo de
+// reenvio e deve ser chamado só depois de todos confirmados pelo Kafka
+// Um reenvio interrompido (queda) é retomado junto com os eventos novos
+func (s *Spill) Drain() ([]*sarama.ProducerMessage, func(), error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	replayPath := filepath.Join(s.dir, replayFile)
+	if _, err := os.Stat(replayPath); errors.Is(err, os.ErrNotExist) {
+		// Sem reenvio pendente: o wal atual vira o arquivo de reenvio
+		if err := s.file.Close(); err != nil {
+			return nil, nil, fmt.Errorf("erro ao fechar buffer: %w", err)
+		}
+		if err := os.Rename(filepath.Join(s.dir, spillFile), replayPath); err != nil {
+			return nil, nil, fmt.Errorf("erro ao rotacionar buffer: %w", err)
+		}
+		if err := s.openFile(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	msgs, replayed, err := readSpill(replayPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	done := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if err := os.Remove(replayPath); err == nil {
+			s.size -= replayed
+			metrics.KafkaSpillBytes.Set(float64(s.size))
+		}
+	}
+	return msgs, done, nil
+}
+
+// Close fecha o arquivo (eventos pendentes continuam em disco)
+func (s *Spill) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// openFile abre spill.wal para acrescentar eventos
+func (s *Spill) openFile() error {
+	f, err := os.OpenFile(filepath.Join(s.dir, spillFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir buffer: %w", err)
+	}
+	s.file = f
+	return nil
+}
+
+// readSpill lê os eventos do arquivo; linha incompleta (queda durante a
+// gravação) é descartada. Retorna também o tamanho lido
+func readSpill(path string) ([]*sarama.ProducerMessage, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao abrir buffer para reenvio: %w", err)
+	}
+	defer f.Close()
+
+	var (
+		msgs []*sarama.ProducerMessage
+		size int64
+	)
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		size += int64(len(line))
+		if err != nil {
+			break // EOF (ou linha final incompleta)
+		}
+		var rec spillRecord
+		if json.Unmarshal(line, &rec) != nil {
+			continue
+		}
+		msgs = append(msgs, rec.message())
+	}
+	return msgs, size, nil
+}
+
+// encodeSpillRecord serializa a mensagem como linha JSON
+func encodeSpillRecord(msg *sarama.ProducerMessage) ([]byte, error) {
+	rec := spillRecord{Topic: msg.Topic}
+	if msg.Key != nil {
+		key, err := msg.Key.Encode()
+		if err != nil {
+			return nil, err
+		}
+		rec.Key = string(key)
+	}
+	if msg.Value != nil {
+		value, err := msg.Value.Encode()
+		if err != nil {
+			return nil, err
+		}
+		rec.Value = value
+	}
+	if len(msg.Headers) > 0 {
+		rec.Headers = map[string]string{}
+		for _, h := range msg.Headers {
+			rec.Headers[string(h.Key)] = string(h.Value)
+		}
+	}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return nil, err
+	}
+	return append(line, '\n'), nil
+}
+
+// message reconstrói a mensagem do producer
+func (r spillRecord) message() *sarama.ProducerMessage {
+	msg := &sarama.ProducerMessage{
+		Topic: r.Topic,
+		Key:   sarama.StringEncoder(r.Key),
+		Value: sarama.ByteEncoder(r.Value),
+	}
+	for k, v := range r.Headers {
+		msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte(k), Value: []byte(v)})
+	}
+	return msg
+}
@@ -0,0 +1,71 @@
+package kafka
+
+import (
+	"fmt"
+	"strconv"
+
+	"chat-kafka-go/internal/config"
+
+	"github.com/IBM/sarama"
+)
+
+// TopicReport tópicos obrigatórios ausentes no cluster
+type TopicReport struct {
+	Missing    []string
+	AutoCreate bool // auto.create.topics.enable no broker: criados no primeiro envio com partições padrão
+}
+
+// CheckTopics confere se os tópicos existem (self-check do boot)
+func CheckTopics(cfg *config.KafkaConfig, topics []string) (*TopicReport, error) {
+	saramaCfg := sarama.NewConfig()
+	saramaCfg.Version = sarama.V2_1_0_0
+
+	client, err := sarama.NewClient(cfg.Brokers, saramaCfg)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao conectar no Kafka: %w", err)
+	}
+	defer client.Close()
+
+	existing, err := client.Topics()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar tópicos: %w", err)
+	}
+	found := make(map[string]bool, len(existing))
+	for _, t := range existing {
+		found[t] = true
+	}
+
+	report := &TopicReport{}
+	for _, t := range topics {
+		if !found[t] {
+			report.Missing = append(report.Missing, t)
+		}
+	}
+	if len(report.Missing) == 0 {
+		return report, nil
+	}
+
+	// Com tópico ausente, vale saber se o broker cria sozinho
+	admin, err := sarama.NewClusterAdminFromClient(client)
+	if err != nil {
+		return report, nil
+	}
+	controller, err := client.Controller()
+	if err != nil {
+		return report, nil
+	}
+	entries, err := admin.DescribeConfig(sarama.ConfigResource{
+		Type:        sarama.BrokerResource,
+		Name:        strconv.Itoa(int(controller.ID())),
+		ConfigNames: []string{"auto.create.topics.enable"},
+	})
+	if err != nil {
+		return report, nil
+	}
+	for _, entry := range entries {
+		if entry.Name == "auto.create.topics.enable" {
+			report.AutoCreate = entry.Value == "true"
+		}
+	}
+	return report, nil
+}
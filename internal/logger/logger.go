@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"chat-kafka-go/internal/config"
+
+	"github.com/joho/godotenv"
+)
+
+// level nível global, alterável em runtime (admin endpoint / SIGHUP)
+var level = new(slog.LevelVar)
+
+// Setup configura o logger padrão (slog + pacote log) a partir da config
+func Setup(cfg *config.LogConfig) error {
+	if err := SetLevel(cfg.Level); err != nil {
+		return err
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch cfg.Format {
+	case "json":
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	case "", "text":
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	default:
+		return fmt.Errorf("LOG_FORMAT inválido: %s (use json ou text)", cfg.Format)
+	}
+
+	if cfg.SampleInitial > 0 {
+		handler = newSamplingHandler(handler, cfg.SampleInitial, cfg.SampleThereafter, cfg.SampleTick)
+	}
+
+	// slog.SetDefault também redireciona log.Printf para o handler
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// Level retorna o nível atual
+func Level() slog.Level {
+	return level.Level()
+}
+
+// SetLevel altera o nível (debug, info, warn, error)
+func SetLevel(name string) error {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(strings.ToUpper(name))); err != nil {
+		return fmt.Errorf("LOG_LEVEL inválido: %s", name)
+	}
+	level.Set(l)
+	return nil
+}
+
+// WatchSIGHUP recarrega LOG_LEVEL do ambiente/.env ao receber SIGHUP
+func WatchSIGHUP(ctx context.Context) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ch:
+				_ = godotenv.Overload()
+				name := os.Getenv("LOG_LEVEL")
+				if name == "" {
+					name = "info"
+				}
+				if err := SetLevel(name); err != nil {
+					slog.Error("Erro ao recarregar nível de log", "error", err)
+					continue
+				}
+				slog.Info("Nível de log recarregado", "level", Level().String())
+			}
+		}
+	}()
+}
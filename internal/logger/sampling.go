@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// samplingHandler limita logs de debug repetidos (mesma mensagem)
+// Em cada janela (tick): loga os primeiros `initial`, depois 1 a cada `thereafter`
+type samplingHandler struct {
+	next       slog.Handler
+	initial    uint64
+	thereafter uint64
+	counters   *counters
+}
+
+type counters struct {
+	mu      sync.Mutex
+	tick    time.Duration
+	resetAt time.Time
+	byMsg   map[string]*atomic.Uint64
+}
+
+func newSamplingHandler(next slog.Handler, initial, thereafter int, tick time.Duration) *samplingHandler {
+	if tick <= 0 {
+		tick = time.Second
+	}
+	return &samplingHandler{
+		next:       next,
+		initial:    uint64(initial),
+		thereafter: uint64(thereafter),
+		counters: &counters{
+			tick:  tick,
+			byMsg: make(map[string]*atomic.Uint64),
+		},
+	}
+}
+
+func (h *samplingHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	// Apenas debug é amostrado; info/warn/error sempre passam
+	if r.Level > slog.LevelDebug {
+		return h.next.Handle(ctx, r)
+	}
+
+	n := h.counters.inc(r.Message, r.Time)
+	if n <= h.initial {
+		return h.next.Handle(ctx, r)
+	}
+	if h.thereafter > 0 && (n-h.initial)%h.thereafter == 0 {
+		return h.next.Handle(ctx, r)
+	}
+	return nil
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{next: h.next.WithAttrs(attrs), initial: h.initial, thereafter: h.thereafter, counters: h.counters}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{next: h.next.WithGroup(name), initial: h.initial, thereafter: h.thereafter, counters: h.counters}
+}
+
+// inc incrementa contador da mensagem, zerando todos a cada tick
+func (c *counters) inc(msg string, now time.Time) uint64 {
+	c.mu.Lock()
+	if now.After(c.resetAt) {
+		c.byMsg = make(map[string]*atomic.Uint64)
+		c.resetAt = now.Add(c.tick)
+	}
+	counter, ok := c.byMsg[msg]
+	if !ok {
+		counter = new(atomic.Uint64)
+		c.byMsg[msg] = counter
+	}
+	c.mu.Unlock()
+
+	return counter.Add(1)
+}
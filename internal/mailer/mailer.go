@@ -0,0 +1,55 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/smtp"
+
+	"chat-kafka-go/internal/config"
+)
+
+// Mailer envia emails transacionais (convites, verificação, reset de senha)
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// New cria mailer SMTP se SMTP_HOST estiver configurado, senão loga os emails
+func New(cfg *config.MailConfig) Mailer {
+	if cfg.SMTPHost == "" {
+		return LogMailer{}
+	}
+	return &SMTPMailer{cfg: cfg}
+}
+
+// LogMailer apenas loga (desenvolvimento)
+type LogMailer struct{}
+
+// Send implementa Mailer
+func (LogMailer) Send(ctx context.Context, to, subject, body string) error {
+	log.Printf("email: para=%s assunto=%q\n%s", to, subject, body)
+	return nil
+}
+
+// SMTPMailer envia via servidor SMTP
+type SMTPMailer struct {
+	cfg *config.MailConfig
+}
+
+// Send implementa Mailer
+func (m *SMTPMailer) Send(ctx context.Context, to, subject, body string) error {
+	addr := fmt.Sprintf("%s:%s", m.cfg.SMTPHost, m.cfg.SMTPPort)
+
+	var auth smtp.Auth
+	if m.cfg.SMTPUser != "" {
+		auth = smtp.PlainAuth("", m.cfg.SMTPUser, m.cfg.SMTPPassword, m.cfg.SMTPHost)
+	}
+
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s",
+		m.cfg.From, to, subject, body)
+
+	if err := smtp.SendMail(addr, auth, m.cfg.From, []string{to}, []byte(msg)); err != nil {
+		return fmt.Errorf("falha ao enviar email: %w", err)
+	}
+	return nil
+}
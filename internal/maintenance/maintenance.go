@@ -0,0 +1,86 @@
+package maintenance
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"chat-kafka-go/internal/config"
+)
+
+// Modos de manutenção
+const (
+	ModeOff      = "off"
+	ModeReadOnly = "read_only" // Leituras seguem; escritas respondem 503
+	ModeFull     = "full"      // Toda a API responde 503 (exceto sondas)
+)
+
+// State estado atual; também é o corpo do evento WebSocket "maintenance"
+type State struct {
+	Mode       string    `json:"mode"`
+	Message    string    `json:"message,omitempty"`
+	RetryAfter int       `json:"retry_after"` // Segundos (mesmo valor do header Retry-After)
+	Since      time.Time `json:"since"`
+}
+
+// Active indica se a API está em manutenção (read_only ou full)
+func (s State) Active() bool {
+	return s.Mode != ModeOff
+}
+
+// Switch modo de manutenção da instância, alterável em runtime (admin)
+type Switch struct {
+	mu       sync.RWMutex
+	state    State
+	onChange []func(State)
+}
+
+// New cria o switch no modo da configuração
+func New(cfg *config.MaintenanceConfig) *Switch {
+	return &Switch{state: State{
+		Mode:       cfg.Mode,
+		Message:    cfg.Message,
+		RetryAfter: int(cfg.RetryAfter / time.Second),
+		Since:      time.Now().UTC(),
+	}}
+}
+
+// OnChange registra função chamada a cada troca de estado (ex.: avisar os
+// clientes WebSocket); registrar antes de servir requisições
+func (s *Switch) OnChange(fn func(State)) {
+	s.onChange = append(s.onChange, fn)
+}
+
+// State estado atual
+func (s *Switch) State() State {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state
+}
+
+// Set troca o modo; retryAfter <= 0 mantém o valor atual
+func (s *Switch) Set(mode string, retryAfter time.Duration, message string) (State, error) {
+	switch mode {
+	case ModeOff, ModeReadOnly, ModeFull:
+	default:
+		return State{}, fmt.Errorf("modo deve ser %s, %s ou %s", ModeOff, ModeReadOnly, ModeFull)
+	}
+
+	s.mu.Lock()
+	state := State{
+		Mode:       mode,
+		Message:    message,
+		RetryAfter: s.state.RetryAfter,
+		Since:      time.Now().UTC(),
+	}
+	if retryAfter > 0 {
+		state.RetryAfter = max(int(retryAfter/time.Second), 1)
+	}
+	s.state = state
+	s.mu.Unlock()
+
+	for _, fn := range s.onChange {
+		fn(state)
+	}
+	return state, nil
+}
@@ -0,0 +1,109 @@
+package media
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // Registra decoder GIF
+	"image/jpeg"
+	_ "image/png" // Registra decoder PNG
+	"io"
+)
+
+// Tamanhos da prévia desfocada: reduz para blurSamples e amplia para
+// blurWidth com interpolação bilinear (perde todos os detalhes)
+const (
+	blurSamples = 16
+	blurWidth   = 320
+)
+
+// BlurPreview gera uma versão JPEG fortemente desfocada da imagem
+func BlurPreview(r io.Reader) ([]byte, error) {
+	src, _, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao decodificar imagem: %w", err)
+	}
+
+	bounds := src.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return nil, fmt.Errorf("imagem vazia")
+	}
+
+	small := downscale(src, blurSamples, max(1, blurSamples*bounds.Dy()/bounds.Dx()))
+	height := max(1, blurWidth*bounds.Dy()/bounds.Dx())
+	blurred := upscaleBilinear(small, blurWidth, height)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, blurred, &jpeg.Options{Quality: 70}); err != nil {
+		return nil, fmt.Errorf("erro ao gerar prévia: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// downscale reduz a imagem pela média de cada bloco de pixels
+func downscale(src image.Image, w, h int) *image.RGBA {
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+
+	for y := 0; y < h; y++ {
+		y0, y1 := b.Min.Y+y*b.Dy()/h, b.Min.Y+(y+1)*b.Dy()/h
+		for x := 0; x < w; x++ {
+			x0, x1 := b.Min.X+x*b.Dx()/w, b.Min.X+(x+1)*b.Dx()/w
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < max(y1, y0+1); sy++ {
+				for sx := x0; sx < max(x1, x0+1); sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{uint16(r / n), uint16(g / n), uint16(bl / n), uint16(a / n)})
+		}
+	}
+	return dst
+}
+
+// upscaleBilinear amplia a imagem interpolando entre os pixels vizinhos
+func upscaleBilinear(src *image.RGBA, w, h int) *image.RGBA {
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+
+	for y := 0; y < h; y++ {
+		fy := (float64(y)+0.5)*float64(sh)/float64(h) - 0.5
+		y0, ty := clampFloor(fy, sh)
+		y1 := min(y0+1, sh-1)
+		for x := 0; x < w; x++ {
+			fx := (float64(x)+0.5)*float64(sw)/float64(w) - 0.5
+			x0, tx := clampFloor(fx, sw)
+			x1 := min(x0+1, sw-1)
+
+			c00, c10 := src.RGBAAt(x0, y0), src.RGBAAt(x1, y0)
+			c01, c11 := src.RGBAAt(x0, y1), src.RGBAAt(x1, y1)
+			lerp := func(a, b, c, d uint8) uint8 {
+				top := float64(a)*(1-tx) + float64(b)*tx
+				bottom := float64(c)*(1-tx) + float64(d)*tx
+				return uint8(top*(1-ty) + bottom*ty + 0.5)
+			}
+			dst.SetRGBA(x, y, color.RGBA{
+				R: lerp(c00.R, c10.R, c01.R, c11.R),
+				G: lerp(c00.G, c10.G, c01.G, c11.G),
+				B: lerp(c00.B, c10.B, c01.B, c11.B),
+				A: lerp(c00.A, c10.A, c01.A, c11.A),
+			})
+		}
+	}
+	return dst
+}
+
+// clampFloor retorna o índice inteiro (limitado a [0, n-1]) e a fração de f
+func clampFloor(f float64, n int) (int, float64) {
+	if f <= 0 {
+		return 0, 0
+	}
+	i := int(f)
+	if i >= n-1 {
+		return n - 1, 0
+	}
+	return i, f - float64(i)
+}
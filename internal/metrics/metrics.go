@@ -0,0 +1,219 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// PanicsTotal panics recuperados por componente (http, websocket, kafka, worker)
+var PanicsTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "chat_panics_recovered_total",
+		Help: "Total de panics recuperados por componente",
+	},
+	[]string{"component"},
+)
+
+// KafkaProducedTotal relatórios de entrega do producer (success/error)
+var KafkaProducedTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "chat_kafka_produced_total",
+		Help: "Total de mensagens confirmadas ou rejeitadas pelo Kafka",
+	},
+	[]string{"topic", "result"},
+)
+
+// KafkaTransactionsTotal transações do producer (committed/aborted)
+var KafkaTransactionsTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "chat_kafka_transactions_total",
+		Help: "Total de transações Kafka confirmadas ou abortadas",
+	},
+	[]string{"result"},
+)
+
+// AttachmentScansTotal varreduras antivírus por resultado (clean/infected/skipped/error)
+var AttachmentScansTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "chat_attachment_scans_total",
+		Help: "Total de anexos verificados pelo antivírus por resultado",
+	},
+	[]string{"result"},
+)
+
+// AttachmentGCTotal anexos órfãos removidos (ou candidatos, em dry-run) por motivo
+var AttachmentGCTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "chat_attachment_gc_total",
+		Help: "Total de anexos órfãos removidos pela coleta (mode=dry_run apenas conta)",
+	},
+	[]string{"reason", "mode"},
+)
+
+// AttachmentGCBytesTotal bytes liberados pela coleta de anexos
+var AttachmentGCBytesTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "chat_attachment_gc_bytes_total",
+		Help: "Total de bytes de anexos removidos pela coleta",
+	},
+	[]string{"reason"},
+)
+
+// TranscodeJobsTotal jobs de transcodificação de vídeo por resultado (done/retry/failed)
+var TranscodeJobsTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "chat_transcode_jobs_total",
+		Help: "Total de jobs de transcodificação de vídeo processados por resultado",
+	},
+	[]string{"result"},
+)
+
+// BulkJobsTotal jobs do tópico bulk por tipo e resultado (success/error/unknown)
+var BulkJobsTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "chat_bulk_jobs_total",
+		Help: "Total de jobs de baixa prioridade processados por tipo e resultado",
+	},
+	[]string{"type", "result"},
+)
+
+// EventRetriesTotal eventos que falharam, por tópico e destino (estágio de retry ou DLQ)
+var EventRetriesTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "chat_event_retries_total",
+		Help: "Total de eventos enviados a tópicos de retry ou à DLQ",
+	},
+	[]string{"topic", "destination"},
+)
+
+// EventsQuarantinedTotal eventos venenosos em quarentena por tópico, grupo e motivo (panic/timeout)
+var EventsQuarantinedTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "chat_events_quarantined_total",
+		Help: "Total de eventos enviados à quarentena por panics ou timeouts repetidos",
+	},
+	[]string{"topic", "group", "reason"},
+)
+
+// KafkaBreakerOpen circuito do producer aberto (1) ou fechado (0)
+var KafkaBreakerOpen = promauto.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "chat_kafka_breaker_open",
+		Help: "Circuito do producer Kafka aberto após falhas consecutivas de entrega",
+	},
+)
+
+// KafkaSpillBytes tamanho do buffer de contingência em disco aguardando reenvio
+var KafkaSpillBytes = promauto.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "chat_kafka_spill_bytes",
+		Help: "Bytes de eventos no buffer em disco aguardando reenvio ao Kafka",
+	},
+)
+
+// KafkaSpilledTotal eventos gravados no buffer em disco por tópico
+var KafkaSpilledTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "chat_kafka_spilled_total",
+		Help: "Total de eventos desviados para o buffer em disco",
+	},
+	[]string{"topic"},
+)
+
+// KafkaSpillDroppedTotal eventos perdidos com o buffer em disco cheio (alertar em > 0)
+var KafkaSpillDroppedTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "chat_kafka_spill_dropped_total",
+		Help: "Total de eventos perdidos porque o buffer em disco estava cheio",
+	},
+	[]string{"topic"},
+)
+
+// DirectDeliveriesTotal mensagens entregues via WebSocket sem o barramento (modo degradado)
+var DirectDeliveriesTotal = promauto.NewCounter(
+	prometheus.CounterOpts{
+		Name: "chat_direct_deliveries_total",
+		Help: "Total de mensagens entregues direto às conexões com o barramento degradado",
+	},
+)
+
+// ClusterForwardedTotal frames encaminhados à instância dona do usuário (success/error)
+var ClusterForwardedTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "chat_cluster_forwarded_total",
+		Help: "Total de frames WebSocket encaminhados a outra instância do cluster",
+	},
+	[]string{"result"},
+)
+
+// HistoryCacheTotal consultas de histórico pelo cache em memória (hit/miss/bypass)
+var HistoryCacheTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "chat_history_cache_total",
+		Help: "Total de consultas de histórico atendidas ou não pelo cache de mensagens recentes",
+	},
+	[]string{"result"},
+)
+
+// QuotaExceededTotal envios recusados por cota esgotada (messages/bot_messages/storage/contact_lookups)
+var QuotaExceededTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "chat_quota_exceeded_total",
+		Help: "Total de envios recusados por cota do usuário esgotada",
+	},
+	[]string{"quota"},
+)
+
+// AnnouncementMessagesTotal mensagens de anúncio gravadas por resultado (delivered/published_error)
+var AnnouncementMessagesTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "chat_announcement_messages_total",
+		Help: "Total de mensagens de anúncio entregues aos usuários",
+	},
+	[]string{"result"},
+)
+
+// SupportTicketsTotal eventos dos chamados de suporte (opened/claimed/replied/closed)
+var SupportTicketsTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "chat_support_tickets_total",
+		Help: "Total de eventos dos chamados de suporte",
+	},
+	[]string{"event"},
+)
+
+// SLOEventsTotal eventos dos SLIs por objetivo e resultado (good/bad);
+// burn rate = taxa de bad / (1 - objetivo)
+var SLOEventsTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "chat_slo_events_total",
+		Help: "Total de eventos dos SLIs por objetivo e resultado",
+	},
+	[]string{"slo", "result"},
+)
+
+// ArchivedMessagesTotal mensagens enviadas ao arquivo de compliance
+var ArchivedMessagesTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "chat_archived_messages_total",
+		Help: "Total de mensagens espelhadas no arquivo de compliance",
+	},
+	[]string{"result"},
+)
+
+// ClientClockSkewTotal envios com horário do cliente além do limite de diferença
+var ClientClockSkewTotal = promauto.NewCounter(
+	prometheus.CounterOpts{
+		Name: "chat_client_clock_skew_total",
+		Help: "Total de mensagens cujo horário do cliente diverge do servidor além de CLIENT_CLOCK_SKEW_THRESHOLD",
+	},
+)
+
+// AutoRepliesTotal respostas automáticas avaliadas pelo consumidor
+var AutoRepliesTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "chat_auto_replies_total",
+		Help: "Total de respostas automáticas por resultado (sent, throttled, refused)",
+	},
+	[]string{"result"},
+)
@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// APIKeyHeader header alternativo para chaves de API
+const APIKeyHeader = "X-API-Key"
+
+// ImpersonationHeader sessão de suporte que fez a requisição (resposta)
+const ImpersonationHeader = "X-Impersonation-Session"
+
+// APIKeyValidator valida credenciais de máquina
+// userID vazio = chave sem usuário associado
+type APIKeyValidator interface {
+	ValidateAPIKey(ctx context.Context, key string) (userID string, scopes []string, err error)
+}
+
+// ImpersonationValidator confere tokens do suporte (claim imp)
+type ImpersonationValidator interface {
+	// ValidateImpersonation sessão ativa e escopo permitido; registra o acesso
+	ValidateImpersonation(ctx context.Context, sessionID, scope, path string) error
+}
+
+// Auth exige "Authorization: Bearer <access token>" e coloca o usuário no
+// contexto; tokens do suporte (impersonação) não são aceitos
+func Auth(cfg *config.JWTConfig) func(http.Handler) http.Handler {
+	return authenticate(cfg, nil, "")
+}
+
+// authenticate valida o access token; com impersonations, aceita token do
+// suporte em leituras do escopo
+func authenticate(cfg *config.JWTConfig, impersonations ImpersonationValidator, scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if !strings.HasPrefix(header, "Bearer ") {
+				utils.Error(w, http.StatusUnauthorized, "token de acesso ausente", "UNAUTHORIZED")
+				return
+			}
+
+			claims, err := utils.ValidateAccessToken(strings.TrimPrefix(header, "Bearer "), cfg.AccessSecret, cfg.Issuer, cfg.Audience, cfg.Leeway, cfg.Now())
+			if errors.Is(err, utils.ErrTokenExpired) {
+				// Cliente renova com o refresh token e repete
+				utils.Error(w, http.StatusUnauthorized, "token de acesso expirado", "TOKEN_EXPIRED")
+				return
+			}
+			if err != nil {
+				utils.Error(w, http.StatusUnauthorized, "token de acesso inválido", "INVALID_TOKEN")
+				return
+			}
+
+			if claims.Impersonation != nil && !allowImpersonation(w, r, claims.Impersonation, impersonations, scope) {
+				return
+			}
+
+			ctx := reqctx.WithUserID(r.Context(), claims.UserID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// allowImpersonation token do suporte só lê (GET), só em rotas com escopo de
+// leitura e só com a sessão ativa; responde o erro quando recusa
+func allowImpersonation(w http.ResponseWriter, r *http.Request, imp *types.ImpersonationClaim, impersonations ImpersonationValidator, scope string) bool {
+	if impersonations == nil {
+		utils.Error(w, http.StatusForbidden, "token de suporte não vale nesta rota", "IMPERSONATION_FORBIDDEN")
+		return false
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		utils.Error(w, http.StatusForbidden, "token de suporte é somente leitura", "IMPERSONATION_READ_ONLY")
+		return false
+	}
+	if err := impersonations.ValidateImpersonation(r.Context(), imp.SessionID, scope, r.URL.Path); err != nil {
+		utils.Error(w, http.StatusForbidden, err.Error(), "IMPERSONATION_DENIED")
+		return false
+	}
+	// Respostas vistas pelo suporte ficam identificadas
+	w.Header().Set(ImpersonationHeader, imp.SessionID)
+	return true
+}
+
+// Scoped aceita access token (usuário ou suporte, só leitura) ou chave de API
+// com o escopo exigido
+// Chave: "X-API-Key: ck_..." ou "Authorization: Bearer ck_..."
+func Scoped(cfg *config.JWTConfig, keys APIKeyValidator, impersonations ImpersonationValidator, scope string) func(http.Handler) http.Handler {
+	jwtAuth := authenticate(cfg, impersonations, scope)
+	return func(next http.Handler) http.Handler {
+		userHandler := jwtAuth(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := apiKeyFromRequest(r)
+			if key == "" {
+				userHandler.ServeHTTP(w, r)
+				return
+			}
+			serveAPIKey(w, r, next, keys, key, scope)
+		})
+	}
+}
+
+// APIKey exige chave de API com o escopo (rotas de serviço: access token de
+// usuário não é aceito)
+func APIKey(keys APIKeyValidator, scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := apiKeyFromRequest(r)
+			if key == "" {
+				utils.Error(w, http.StatusUnauthorized, "chave de API ausente", "UNAUTHORIZED")
+				return
+			}
+			serveAPIKey(w, r, next, keys, key, scope)
+		})
+	}
+}
+
+// serveAPIKey valida a chave e o escopo e coloca escopos e usuário no contexto
+func serveAPIKey(w http.ResponseWriter, r *http.Request, next http.Handler, keys APIKeyValidator, key, scope string) {
+	userID, scopes, err := keys.ValidateAPIKey(r.Context(), key)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "chave de API inválida", "UNAUTHORIZED")
+		return
+	}
+	if !slices.Contains(scopes, scope) {
+		utils.Error(w, http.StatusForbidden, "chave de API sem o escopo "+scope, "FORBIDDEN")
+		return
+	}
+
+	ctx := reqctx.WithScopes(r.Context(), scopes)
+	if userID != "" {
+		ctx = reqctx.WithUserID(ctx, userID)
+	}
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+func apiKeyFromRequest(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return key
+	}
+	if token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "); strings.HasPrefix(token, utils.APIKeyPrefix) {
+		return token
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/utils"
+)
+
+const testUserID = "8f14e45f-ceea-4e67-a5c9-7b1a2d3e4f50"
+
+// authRequest passa o token pelo Auth com a hora do relógio manual;
+// devolve status e código de erro
+func authRequest(t *testing.T, cfg *config.JWTConfig, token string) (int, string) {
+	t.Helper()
+	handler := Auth(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := reqctx.UserID(r.Context()); got != testUserID {
+			t.Errorf("usuário no contexto = %q; esperado %q", got, testUserID)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/users/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	var body struct {
+		Code string `json:"code"`
+	}
+	if rec.Code != http.StatusNoContent {
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("resposta %d sem JSON: %v", rec.Code, err)
+		}
+	}
+	return rec.Code, body.Code
+}
+
+func TestAuthExpiryAndLeeway(t *testing.T) {
+	issued := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	manual := clock.NewManual(issued)
+	cfg := &config.JWTConfig{
+		AccessSecret:     "access-secret-de-teste-com-32-bytes!",
+		AccessExpiration: 15 * time.Minute,
+		Issuer:           "chat-kafka-go",
+		Audience:         "chat-kafka-go-api",
+		Leeway:           30 * time.Second,
+		Clock:            manual,
+	}
+	token, err := utils.GenerateAccessToken(testUserID, "maria", "maria@example.com",
+		cfg.AccessSecret, cfg.Issuer, cfg.Audience, cfg.AccessExpiration, issued)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiresAt := issued.Add(cfg.AccessExpiration)
+
+	for _, tc := range []struct {
+		name     string
+		now      time.Time
+		wantCode int
+		wantErr  string
+	}{
+		{"válido", issued.Add(time.Minute), http.StatusNoContent, ""},
+		// Instância atrasada em relação à que emitiu: nbf dentro da tolerância
+		{"relógio atrasado", issued.Add(-10 * time.Second), http.StatusNoContent, ""},
+		{"relógio muito atrasado", issued.Add(-time.Minute), http.StatusUnauthorized, "INVALID_TOKEN"},
+		{"expirado dentro da tolerância", expiresAt.Add(20 * time.Second), http.StatusNoContent, ""},
+		{"expirado", expiresAt.Add(cfg.Leeway + time.Second), http.StatusUnauthorized, "TOKEN_EXPIRED"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			manual.Set(tc.now)
+			code, errCode := authRequest(t, cfg, token)
+			if code != tc.wantCode || errCode != tc.wantErr {
+				t.Fatalf("resposta = %d %s; esperado %d %s", code, errCode, tc.wantCode, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthMissingToken(t *testing.T) {
+	handler := Auth(&config.JWTConfig{AccessSecret: "x"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler chamado sem token")
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/me", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d; esperado 401", rec.Code)
+	}
+}
@@ -0,0 +1,187 @@
+package middleware
+
+import (
+	"bufio"
+	"compress/gzip"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CompressOptions quando comprimir a resposta
+type CompressOptions struct {
+	MinBytes int                        // Respostas menores saem sem compressão
+	Types    []string                   // Content-Types comprimidos (sem parâmetros)
+	Skip     func(r *http.Request) bool // Rotas sem compressão (opcional)
+}
+
+var gzipPool = sync.Pool{
+	New: func() any {
+		gz, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression)
+		return gz
+	},
+}
+
+// Compress comprime com gzip respostas do tipo permitido a partir de MinBytes
+// quando o cliente aceita. A decisão espera os primeiros MinBytes: respostas
+// pequenas e streams (Flush antes do limite) saem como estão
+func Compress(opts CompressOptions) func(http.Handler) http.Handler {
+	types := make(map[string]bool, len(opts.Types))
+	for _, t := range opts.Types {
+		types[strings.ToLower(strings.TrimSpace(t))] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodHead || (opts.Skip != nil && opts.Skip(r)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Add("Vary", "Accept-Encoding")
+			if !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, minBytes: opts.MinBytes, types: types}
+			defer cw.close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// acceptsGzip Accept-Encoding inclui gzip (ou *) sem q=0
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// compressWriter acumula até minBytes para decidir se comprime
+type compressWriter struct {
+	http.ResponseWriter
+	minBytes int
+	types    map[string]bool
+
+	status  int
+	buf     []byte
+	decided bool
+	gz      *gzip.Writer
+}
+
+func (c *compressWriter) WriteHeader(status int) {
+	if c.decided {
+		c.ResponseWriter.WriteHeader(status)
+		return
+	}
+	c.status = status
+}
+
+func (c *compressWriter) Write(p []byte) (int, error) {
+	if !c.decided {
+		c.buf = append(c.buf, p...)
+		if len(c.buf) < c.minBytes {
+			return len(p), nil
+		}
+		if err := c.decide(true); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if c.gz != nil {
+		return c.gz.Write(p)
+	}
+	return c.ResponseWriter.Write(p)
+}
+
+// decide escreve o header (com ou sem Content-Encoding) e o que foi acumulado
+func (c *compressWriter) decide(large bool) error {
+	c.decided = true
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+	if large && c.compressible() {
+		h := c.Header()
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", "gzip")
+		c.gz = gzipPool.Get().(*gzip.Writer)
+		c.gz.Reset(c.ResponseWriter)
+	}
+	c.ResponseWriter.WriteHeader(c.status)
+
+	buf := c.buf
+	c.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if c.gz != nil {
+		_, err = c.gz.Write(buf)
+	} else {
+		_, err = c.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// compressible status com corpo, sem codificação própria e tipo permitido
+func (c *compressWriter) compressible() bool {
+	if c.status < http.StatusOK || c.status == http.StatusNoContent || c.status == http.StatusNotModified {
+		return false
+	}
+	h := c.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(h.Get("Content-Type"))
+	return err == nil && c.types[mediaType]
+}
+
+// close conclui a resposta (pequena: sem compressão) e devolve o gzip ao pool
+func (c *compressWriter) close() {
+	if !c.decided {
+		if c.status == 0 && len(c.buf) == 0 {
+			return // Nada escrito (handler abortou ou conexão sequestrada)
+		}
+		_ = c.decide(false)
+	}
+	if c.gz != nil {
+		_ = c.gz.Close()
+		gzipPool.Put(c.gz)
+		c.gz = nil
+	}
+}
+
+// Flush stream antes de atingir minBytes segue sem compressão
+func (c *compressWriter) Flush() {
+	if !c.decided {
+		_ = c.decide(false)
+	}
+	if c.gz != nil {
+		_ = c.gz.Flush()
+	}
+	_ = http.NewResponseController(c.ResponseWriter).Flush()
+}
+
+func (c *compressWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+// Hijack conexão assumida pelo handler: nada a comprimir
+func (c *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	c.decided = true
+	return http.NewResponseController(c.ResponseWriter).Hijack()
+}
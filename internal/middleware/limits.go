@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"chat-kafka-go/pkg/utils"
+)
+
+// deadlineGrace folga das deadlines da conexão sobre o prazo da rota: o
+// handler ainda consegue responder depois que o contexto expira
+const deadlineGrace = 5 * time.Second
+
+// Limits prazo e corpo máximo de uma classe de rotas (0 = sem limite)
+type Limits struct {
+	Timeout time.Duration
+	MaxBody int64
+}
+
+// RouteLimits aplica os limites da classe da rota: prazo no contexto,
+// deadlines de leitura/escrita da conexão (substituem SERVER_READ_TIMEOUT e
+// SERVER_WRITE_TIMEOUT, permitindo uploads longos) e corpo máximo
+func RouteLimits(classify func(r *http.Request) Limits) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limits := classify(r)
+
+			if limits.MaxBody > 0 && r.Body != nil {
+				// Content-Length declarado acima do limite: recusa sem ler o corpo
+				if r.ContentLength > limits.MaxBody {
+					utils.Error(w, http.StatusRequestEntityTooLarge, "corpo da requisição excede o limite", "BODY_TOO_LARGE")
+					return
+				}
+				r.Body = http.MaxBytesReader(w, r.Body, limits.MaxBody)
+			}
+
+			if limits.Timeout > 0 {
+				ctx, cancel := context.WithTimeout(r.Context(), limits.Timeout)
+				defer cancel()
+				r = r.WithContext(ctx)
+
+				rc := http.NewResponseController(w)
+				deadline := time.Now().Add(limits.Timeout + deadlineGrace)
+				_ = rc.SetReadDeadline(deadline)
+				_ = rc.SetWriteDeadline(deadline)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"chat-kafka-go/internal/maintenance"
+	"chat-kafka-go/pkg/utils"
+)
+
+// maintenanceExempt rotas atendidas mesmo em manutenção total (sondas: a
+// instância continua no balanceador e responde 503 com o corpo padrão)
+var maintenanceExempt = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+}
+
+// Maintenance responde 503 com código MAINTENANCE e Retry-After conforme o
+// modo: full bloqueia tudo; read_only bloqueia escritas, exceto as de sessão
+// (login, refresh, ticket WebSocket) para os clientes continuarem lendo.
+// POST /batch também passa: o lote só executa GETs
+func Maintenance(sw *maintenance.Switch) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			state := sw.State()
+			if !blockedByMaintenance(state.Mode, r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			message := state.Message
+			if message == "" {
+				message = "serviço em manutenção, tente novamente mais tarde"
+			}
+			w.Header().Set("Retry-After", strconv.Itoa(state.RetryAfter))
+			utils.Error(w, http.StatusServiceUnavailable, message, "MAINTENANCE")
+		})
+	}
+}
+
+// blockedByMaintenance requisição recusada no modo informado
+func blockedByMaintenance(mode string, r *http.Request) bool {
+	if maintenanceExempt[r.URL.Path] {
+		return false
+	}
+	switch mode {
+	case maintenance.ModeFull:
+		return true
+	case maintenance.ModeReadOnly:
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return false
+		}
+		return !strings.HasPrefix(r.URL.Path, "/auth/") && r.URL.Path != "/ws/ticket" && r.URL.Path != "/batch"
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"chat-kafka-go/internal/config"
+)
+
+// RealIP troca r.RemoteAddr pelo IP do cliente informado pelo proxy quando a
+// conexão vem de um proxy confiável (utils.ClientIP passa a ver o cliente, não
+// o balanceador). Em X-Forwarded-For, percorre da direita para a esquerda e
+// usa o primeiro endereço não confiável: entradas à esquerda vêm do cliente e
+// podem ser forjadas
+func RealIP(cfg *config.ServerConfig) func(http.Handler) http.Handler {
+	trusted := make([]netip.Prefix, 0, len(cfg.TrustedProxies))
+	for _, proxy := range cfg.TrustedProxies {
+		if prefix, err := config.ParsePrefix(proxy); err == nil {
+			trusted = append(trusted, prefix)
+		}
+	}
+	header := http.CanonicalHeaderKey(cfg.RealIPHeader)
+
+	return func(next http.Handler) http.Handler {
+		if len(trusted) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if peer, ok := parseIP(r.RemoteAddr); ok && isTrusted(trusted, peer) {
+				if ip, ok := forwardedIP(r.Header.Values(header), header, trusted); ok {
+					r.RemoteAddr = net.JoinHostPort(ip.String(), "0")
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// forwardedIP cliente segundo o header do proxy
+func forwardedIP(values []string, header string, trusted []netip.Prefix) (netip.Addr, bool) {
+	if header != "X-Forwarded-For" {
+		if len(values) == 0 {
+			return netip.Addr{}, false
+		}
+		return parseIP(strings.TrimSpace(values[len(values)-1]))
+	}
+
+	// Vários headers equivalem a uma lista separada por vírgulas
+	hops := strings.Split(strings.Join(values, ","), ",")
+	var last netip.Addr
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip, ok := parseIP(strings.TrimSpace(hops[i]))
+		if !ok {
+			break // Entrada inválida: não dá para confiar no que vem antes
+		}
+		last = ip
+		if !isTrusted(trusted, ip) {
+			return ip, true
+		}
+	}
+	// Todos confiáveis (tráfego interno): o mais à esquerda válido
+	return last, last.IsValid()
+}
+
+// parseIP aceita IP puro ou host:porta
+func parseIP(s string) (netip.Addr, bool) {
+	if addrPort, err := netip.ParseAddrPort(s); err == nil {
+		return addrPort.Addr().Unmap(), true
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+// isTrusted IP pertence a algum proxy confiável
+func isTrusted(trusted []netip.Prefix, ip netip.Addr) bool {
+	for _, prefix := range trusted {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
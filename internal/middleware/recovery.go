@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+
+	"chat-kafka-go/internal/recovery"
+	"chat-kafka-go/pkg/utils"
+)
+
+// Recovery captura panics dos handlers HTTP e responde 500
+func Recovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				// http.ErrAbortHandler é usado intencionalmente para abortar a resposta
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+				recovery.Handle(r.Context(), "http", rec)
+				utils.Error(w, http.StatusInternalServerError, "erro interno do servidor", "INTERNAL_ERROR")
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
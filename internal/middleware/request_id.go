@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+
+	"chat-kafka-go/internal/reqctx"
+
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader header usado para propagar o ID da requisição
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID reaproveita X-Request-ID do cliente/proxy ou gera um novo
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" || len(id) > 128 {
+			id = uuid.New().String()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(reqctx.WithRequestID(r.Context(), id)))
+	})
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"bufio"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"chat-kafka-go/internal/reqctx"
+)
+
+// SlowRequests loga requisições que levaram threshold ou mais (0 = desligado)
+// Conexões assumidas pelo handler (WebSocket) não são medidas
+func SlowRequests(threshold time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if threshold <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			elapsed := time.Since(start)
+			if rec.hijacked || elapsed < threshold {
+				return
+			}
+			log.Printf("WARN: requisição lenta: %s %s -> %d em %s (request_id=%s)",
+				r.Method, r.URL.Path, rec.status, elapsed.Round(time.Millisecond), reqctx.RequestID(r.Context()))
+		})
+	}
+}
+
+// statusRecorder guarda o status da resposta; Unwrap mantém
+// http.ResponseController funcionando (deadlines, flush)
+type statusRecorder struct {
+	http.ResponseWriter
+	status   int
+	hijacked bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Hijack necessário para o upgrade WebSocket
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.hijacked = true
+	return http.NewResponseController(r.ResponseWriter).Hijack()
+}
+
+// Flush repassa ao writer original quando suportado
+func (r *statusRecorder) Flush() {
+	_ = http.NewResponseController(r.ResponseWriter).Flush()
+}
@@ -0,0 +1,55 @@
+package recovery
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+
+	"chat-kafka-go/internal/metrics"
+	"chat-kafka-go/internal/reporter"
+)
+
+// PanicError erro gerado a partir de um panic recuperado
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Handle registra panic recuperado (log + métrica + reporter)
+// Deve ser chamado com o valor retornado por recover()
+func Handle(ctx context.Context, component string, recovered interface{}) *PanicError {
+	stack := debug.Stack()
+
+	log.Printf("ERRO: panic recuperado em %s: %v\n%s", component, recovered, stack)
+	metrics.PanicsTotal.WithLabelValues(component).Inc()
+	reporter.CapturePanic(ctx, recovered, map[string]string{"component": component})
+
+	return &PanicError{Value: recovered, Stack: stack}
+}
+
+// Guard executa fn convertendo panic em erro (handlers Kafka, jobs de worker)
+func Guard(ctx context.Context, component string, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = Handle(ctx, component, r)
+		}
+	}()
+	return fn()
+}
+
+// Go inicia goroutine protegida (loops de leitura/escrita do WebSocket, etc)
+func Go(ctx context.Context, component string, fn func()) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				Handle(ctx, component, r)
+			}
+		}()
+		fn()
+	}()
+}
@@ -0,0 +1,18 @@
+package reporter
+
+import (
+	"context"
+	"log"
+)
+
+// LogTransport escreve eventos no log padrão (útil em desenvolvimento)
+type LogTransport struct{}
+
+// Send implementa Transport
+func (LogTransport) Send(ctx context.Context, event *Event) error {
+	log.Printf("[%s] %s env=%s release=%s tags=%v", event.Level, event.Message, event.Environment, event.Release, event.Tags)
+	if len(event.Stack) > 0 {
+		log.Printf("%s", event.Stack)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package reporter
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/reqctx"
+)
+
+// Level severidade do evento reportado
+type Level string
+
+const (
+	LevelError   Level = "error"
+	LevelFatal   Level = "fatal"
+	LevelWarning Level = "warning"
+)
+
+// Event evento de erro enviado ao backend de reporte
+type Event struct {
+	Err         error
+	Message     string
+	Level       Level
+	Stack       []byte
+	Tags        map[string]string
+	Environment string
+	Release     string
+	Timestamp   time.Time
+}
+
+// Transport backend plugável que entrega eventos (Sentry, log, etc)
+type Transport interface {
+	Send(ctx context.Context, event *Event) error
+}
+
+// Reporter aplica amostragem e tags padrão antes de entregar ao transport
+type Reporter struct {
+	transport   Transport
+	environment string
+	release     string
+	sampleRate  float64
+
+	wg sync.WaitGroup
+}
+
+// New cria reporter a partir da configuração
+func New(cfg *config.ReporterConfig) (*Reporter, error) {
+	var transport Transport
+
+	switch cfg.Backend {
+	case "", "log":
+		transport = LogTransport{}
+	case "sentry":
+		t, err := NewSentryTransport(cfg.DSN)
+		if err != nil {
+			return nil, err
+		}
+		transport = t
+	case "none":
+		transport = nil
+	default:
+		return nil, fmt.Errorf("backend de reporte desconhecido: %s", cfg.Backend)
+	}
+
+	return &Reporter{
+		transport:   transport,
+		environment: cfg.Environment,
+		release:     cfg.Release,
+		sampleRate:  cfg.SampleRate,
+	}, nil
+}
+
+// Capture envia evento de forma assíncrona respeitando a taxa de amostragem
+func (r *Reporter) Capture(ctx context.Context, event *Event) {
+	if r == nil || r.transport == nil {
+		return
+	}
+	// Eventos fatais (panics) nunca são descartados pela amostragem
+	if event.Level != LevelFatal && r.sampleRate < 1 && rand.Float64() >= r.sampleRate {
+		return
+	}
+
+	event.Environment = r.environment
+	event.Release = r.release
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	if event.Level == "" {
+		event.Level = LevelError
+	}
+	if requestID := reqctx.RequestID(ctx); requestID != "" {
+		if event.Tags == nil {
+			event.Tags = map[string]string{}
+		}
+		event.Tags["request_id"] = requestID
+	}
+
+	// Contexto da requisição pode ser cancelado antes do envio terminar
+	ctx = context.WithoutCancel(ctx)
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		if err := r.transport.Send(ctx, event); err != nil {
+			log.Printf("WARN: Erro ao reportar evento: %v", err)
+		}
+	}()
+}
+
+// Flush aguarda envio dos eventos pendentes (usar no shutdown)
+func (r *Reporter) Flush(timeout time.Duration) bool {
+	if r == nil {
+		return true
+	}
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+var (
+	defaultMu       sync.RWMutex
+	defaultReporter *Reporter
+)
+
+// SetDefault define o reporter global usado pelos helpers do pacote
+func SetDefault(r *Reporter) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultReporter = r
+}
+
+// Default retorna o reporter global (nil = reporte desabilitado)
+func Default() *Reporter {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultReporter
+}
+
+// CaptureError reporta um erro com tags opcionais
+func CaptureError(ctx context.Context, err error, tags map[string]string) {
+	if err == nil {
+		return
+	}
+	Default().Capture(ctx, &Event{
+		Err:     err,
+		Message: err.Error(),
+		Level:   LevelError,
+		Tags:    tags,
+	})
+}
+
+// CapturePanic reporta valor recuperado de um panic com stack trace
+func CapturePanic(ctx context.Context, recovered interface{}, tags map[string]string) {
+	Default().Capture(ctx, &Event{
+		Err:     fmt.Errorf("panic: %v", recovered),
+		Message: fmt.Sprintf("panic: %v", recovered),
+		Level:   LevelFatal,
+		Stack:   debug.Stack(),
+		Tags:    tags,
+	})
+}
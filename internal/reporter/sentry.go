@@ -0,0 +1,102 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SentryTransport envia eventos para a API de store do Sentry
+type SentryTransport struct {
+	endpoint  string
+	publicKey string
+	client    *http.Client
+}
+
+// NewSentryTransport cria transport a partir do DSN (https://key@host/project)
+func NewSentryTransport(dsn string) (*SentryTransport, error) {
+	if dsn == "" {
+		return nil, fmt.Errorf("SENTRY_DSN é obrigatório para o backend sentry")
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("SENTRY_DSN inválido: %w", err)
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return nil, fmt.Errorf("SENTRY_DSN sem chave pública")
+	}
+
+	projectID := strings.Trim(u.Path, "/")
+	if projectID == "" {
+		return nil, fmt.Errorf("SENTRY_DSN sem project id")
+	}
+
+	return &SentryTransport{
+		endpoint:  fmt.Sprintf("%s://%s/api/%s/store/", u.Scheme, u.Host, projectID),
+		publicKey: u.User.Username(),
+		client:    &http.Client{Timeout: 5 * time.Second},
+	}, nil
+}
+
+// sentryEvent payload mínimo aceito pelo Sentry
+type sentryEvent struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Level       Level             `json:"level"`
+	Platform    string            `json:"platform"`
+	Message     string            `json:"message"`
+	Environment string            `json:"environment,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Extra       map[string]string `json:"extra,omitempty"`
+}
+
+// Send implementa Transport
+func (t *SentryTransport) Send(ctx context.Context, event *Event) error {
+	payload := sentryEvent{
+		EventID:     strings.ReplaceAll(uuid.New().String(), "-", ""),
+		Timestamp:   event.Timestamp.UTC().Format(time.RFC3339),
+		Level:       event.Level,
+		Platform:    "go",
+		Message:     event.Message,
+		Environment: event.Environment,
+		Release:     event.Release,
+		Tags:        event.Tags,
+	}
+	if len(event.Stack) > 0 {
+		payload.Extra = map[string]string{"stacktrace": string(event.Stack)}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar evento: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf(
+		"Sentry sentry_version=7, sentry_client=chat-kafka-go/1.0, sentry_key=%s", t.publicKey,
+	))
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao enviar evento: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sentry respondeu com status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -8,19 +8,20 @@ package repository
 import (
 	"context"
 
+	"chat-kafka-go/pkg/status"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createFriendship = `-- name: CreateFriendship :one
 INSERT INTO friendships (user_id, friend_id, status)
 VALUES ($1, $2, $3)
-RETURNING id, user_id, friend_id, status, created_at
+RETURNING id, user_id, friend_id, status, created_at, version
 `
 
 type CreateFriendshipParams struct {
-	UserID   pgtype.UUID `json:"user_id"`
-	FriendID pgtype.UUID `json:"friend_id"`
-	Status   string      `json:"status"`
+	UserID   pgtype.UUID       `json:"user_id"`
+	FriendID pgtype.UUID       `json:"friend_id"`
+	Status   status.Friendship `json:"status"`
 }
 
 func (q *Queries) CreateFriendship(ctx context.Context, arg CreateFriendshipParams) (Friendship, error) {
@@ -32,12 +33,13 @@ func (q *Queries) CreateFriendship(ctx context.Context, arg CreateFriendshipPara
 		&i.FriendID,
 		&i.Status,
 		&i.CreatedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
 const getFriendship = `-- name: GetFriendship :one
-SELECT id, user_id, friend_id, status, created_at FROM friendships
+SELECT id, user_id, friend_id, status, created_at, version FROM friendships
 WHERE (user_id = $1 AND friend_id = $2)
    OR (user_id = $2 AND friend_id = $1)
 `
@@ -56,18 +58,19 @@ func (q *Queries) GetFriendship(ctx context.Context, arg GetFriendshipParams) (F
 		&i.FriendID,
 		&i.Status,
 		&i.CreatedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
 const listUserFriends = `-- name: ListUserFriends :many
-SELECT u.id, u.username, u.email, u.password_hash, u.created_at, u.updated_at FROM users u
+SELECT u.id, u.username, u.email, u.password_hash, u.created_at, u.updated_at, u.deleted_at, u.username_changed_at, u.shadow_banned_at, u.email_verified_at FROM users u
 INNER JOIN friendships f ON u.id = f.friend_id
-WHERE f.user_id = $1 AND f.status = 'accepted'
+WHERE f.user_id = $1 AND f.status = 'accepted' AND u.deleted_at IS NULL
 UNION
-SELECT u.id, u.username, u.email, u.password_hash, u.created_at, u.updated_at FROM users u
+SELECT u.id, u.username, u.email, u.password_hash, u.created_at, u.updated_at, u.deleted_at, u.username_changed_at, u.shadow_banned_at, u.email_verified_at FROM users u
 INNER JOIN friendships f ON u.id = f.user_id
-WHERE f.friend_id = $1 AND f.status = 'accepted'
+WHERE f.friend_id = $1 AND f.status = 'accepted' AND u.deleted_at IS NULL
 `
 
 func (q *Queries) ListUserFriends(ctx context.Context, userID pgtype.UUID) ([]User, error) {
@@ -86,6 +89,10 @@ func (q *Queries) ListUserFriends(ctx context.Context, userID pgtype.UUID) ([]Us
 			&i.PasswordHash,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.UsernameChangedAt,
+			&i.ShadowBannedAt,
+			&i.EmailVerifiedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -97,16 +104,53 @@ func (q *Queries) ListUserFriends(ctx context.Context, userID pgtype.UUID) ([]Us
 	return items, nil
 }
 
-const updateFriendshipStatus = `-- name: UpdateFriendshipStatus :exec
-UPDATE friendships SET status = $2 WHERE id = $1
+const requestFriendship = `-- name: RequestFriendship :one
+INSERT INTO friendships (user_id, friend_id, status)
+VALUES ($1, $2, 'pending')
+ON CONFLICT ((LEAST(user_id, friend_id)), (GREATEST(user_id, friend_id))) DO UPDATE
+SET status = 'accepted', version = friendships.version + 1
+WHERE friendships.status = 'pending' AND friendships.user_id = EXCLUDED.friend_id
+RETURNING id, user_id, friend_id, status, created_at, version
+`
+
+type RequestFriendshipParams struct {
+	UserID   pgtype.UUID `json:"user_id"`
+	FriendID pgtype.UUID `json:"friend_id"`
+}
+
+// Upsert no par canônico: sem linha cria o pedido (pending); pedido pendente
+// no sentido inverso significa que os dois pediram, então vira accepted.
+// Sem linha retornada = pedido repetido ou já são amigos
+func (q *Queries) RequestFriendship(ctx context.Context, arg RequestFriendshipParams) (Friendship, error) {
+	row := q.db.QueryRow(ctx, requestFriendship, arg.UserID, arg.FriendID)
+	var i Friendship
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FriendID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const updateFriendshipStatus = `-- name: UpdateFriendshipStatus :execrows
+UPDATE friendships SET status = $2, version = version + 1
+WHERE id = $1 AND version = $3
 `
 
 type UpdateFriendshipStatusParams struct {
-	ID     pgtype.UUID `json:"id"`
-	Status string      `json:"status"`
+	ID      pgtype.UUID       `json:"id"`
+	Status  status.Friendship `json:"status"`
+	Version int32             `json:"version"`
 }
 
-func (q *Queries) UpdateFriendshipStatus(ctx context.Context, arg UpdateFriendshipStatusParams) error {
-	_, err := q.db.Exec(ctx, updateFriendshipStatus, arg.ID, arg.Status)
-	return err
+// Compare-and-set: 0 linhas = outra escrita avançou a versão desde a leitura
+func (q *Queries) UpdateFriendshipStatus(ctx context.Context, arg UpdateFriendshipStatusParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateFriendshipStatus, arg.ID, arg.Status, arg.Version)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
@@ -8,28 +8,33 @@ package repository
 import (
 	"context"
 
+	"chat-kafka-go/pkg/status"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createMessage = `-- name: CreateMessage :one
-INSERT INTO messages (sender_id, receiver_id, content, status)
-VALUES ($1, $2, $3, $4)
-RETURNING id, sender_id, receiver_id, content, status, created_at
+INSERT INTO messages (id, sender_id, receiver_id, content, status, client_sent_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, sender_id, receiver_id, content, status, created_at, delivered_at, read_at, version, client_sent_at
 `
 
 type CreateMessageParams struct {
-	SenderID   pgtype.UUID `json:"sender_id"`
-	ReceiverID pgtype.UUID `json:"receiver_id"`
-	Content    string      `json:"content"`
-	Status     string      `json:"status"`
+	ID           pgtype.UUID      `json:"id"`
+	SenderID     pgtype.UUID      `json:"sender_id"`
+	ReceiverID   pgtype.UUID      `json:"receiver_id"`
+	Content      string           `json:"content"`
+	Status       status.Message   `json:"status"`
+	ClientSentAt pgtype.Timestamp `json:"client_sent_at"`
 }
 
 func (q *Queries) CreateMessage(ctx context.Context, arg CreateMessageParams) (Message, error) {
 	row := q.db.QueryRow(ctx, createMessage,
+		arg.ID,
 		arg.SenderID,
 		arg.ReceiverID,
 		arg.Content,
 		arg.Status,
+		arg.ClientSentAt,
 	)
 	var i Message
 	err := row.Scan(
@@ -39,12 +44,62 @@ func (q *Queries) CreateMessage(ctx context.Context, arg CreateMessageParams) (M
 		&i.Content,
 		&i.Status,
 		&i.CreatedAt,
+		&i.DeliveredAt,
+		&i.ReadAt,
+		&i.Version,
+		&i.ClientSentAt,
 	)
 	return i, err
 }
 
+const createMessageMention = `-- name: CreateMessageMention :exec
+INSERT INTO message_mentions (message_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type CreateMessageMentionParams struct {
+	MessageID pgtype.UUID `json:"message_id"`
+	UserID    pgtype.UUID `json:"user_id"`
+}
+
+func (q *Queries) CreateMessageMention(ctx context.Context, arg CreateMessageMentionParams) error {
+	_, err := q.db.Exec(ctx, createMessageMention, arg.MessageID, arg.UserID)
+	return err
+}
+
+const getConversationMessageCreatedAt = `-- name: GetConversationMessageCreatedAt :one
+SELECT created_at FROM messages
+WHERE id = $1
+  AND LEAST(sender_id, receiver_id) = LEAST($2::uuid, $3::uuid)
+  AND GREATEST(sender_id, receiver_id) = GREATEST($2::uuid, $3::uuid)
+  AND created_at BETWEEN $4::timestamp AND $5::timestamp
+`
+
+type GetConversationMessageCreatedAtParams struct {
+	ID       pgtype.UUID      `json:"id"`
+	UserA    pgtype.UUID      `json:"user_a"`
+	UserB    pgtype.UUID      `json:"user_b"`
+	FromTime pgtype.Timestamp `json:"from_time"`
+	ToTime   pgtype.Timestamp `json:"to_time"`
+}
+
+// Conversa identificada pelo par simétrico: user_a/user_b em qualquer ordem
+func (q *Queries) GetConversationMessageCreatedAt(ctx context.Context, arg GetConversationMessageCreatedAtParams) (pgtype.Timestamp, error) {
+	row := q.db.QueryRow(ctx, getConversationMessageCreatedAt,
+		arg.ID,
+		arg.UserA,
+		arg.UserB,
+		arg.FromTime,
+		arg.ToTime,
+	)
+	var created_at pgtype.Timestamp
+	err := row.Scan(&created_at)
+	return created_at, err
+}
+
 const getMessageByID = `-- name: GetMessageByID :one
-SELECT id, sender_id, receiver_id, content, status, created_at FROM messages WHERE id = $1
+SELECT id, sender_id, receiver_id, content, status, created_at, delivered_at, read_at, version, client_sent_at FROM messages WHERE id = $1
 `
 
 func (q *Queries) GetMessageByID(ctx context.Context, id pgtype.UUID) (Message, error) {
@@ -57,31 +112,99 @@ func (q *Queries) GetMessageByID(ctx context.Context, id pgtype.UUID) (Message,
 		&i.Content,
 		&i.Status,
 		&i.CreatedAt,
+		&i.DeliveredAt,
+		&i.ReadAt,
+		&i.Version,
+		&i.ClientSentAt,
 	)
 	return i, err
 }
 
-const listMessagesBetweenUsers = `-- name: ListMessagesBetweenUsers :many
-SELECT id, sender_id, receiver_id, content, status, created_at FROM messages
-WHERE (sender_id = $1 AND receiver_id = $2)
-   OR (sender_id = $2 AND receiver_id = $1)
-ORDER BY created_at DESC
-LIMIT $3 OFFSET $4
+const listConversationMessages = `-- name: ListConversationMessages :many
+SELECT id, sender_id, receiver_id, content, status, created_at, delivered_at, read_at, version, client_sent_at FROM messages
+WHERE LEAST(sender_id, receiver_id) = LEAST($1::uuid, $2::uuid)
+  AND GREATEST(sender_id, receiver_id) = GREATEST($1::uuid, $2::uuid)
+  AND created_at >= $3::timestamp
+ORDER BY created_at DESC, id DESC
+LIMIT $5 OFFSET $4
 `
 
-type ListMessagesBetweenUsersParams struct {
-	SenderID   pgtype.UUID `json:"sender_id"`
-	ReceiverID pgtype.UUID `json:"receiver_id"`
-	Limit      int32       `json:"limit"`
-	Offset     int32       `json:"offset"`
+type ListConversationMessagesParams struct {
+	UserA  pgtype.UUID      `json:"user_a"`
+	UserB  pgtype.UUID      `json:"user_b"`
+	Since  pgtype.Timestamp `json:"since"`
+	Offset int32            `json:"offset"`
+	Limit  int32            `json:"limit"`
 }
 
-func (q *Queries) ListMessagesBetweenUsers(ctx context.Context, arg ListMessagesBetweenUsersParams) ([]Message, error) {
-	rows, err := q.db.Query(ctx, listMessagesBetweenUsers,
-		arg.SenderID,
-		arg.ReceiverID,
-		arg.Limit,
+// Os dois sentidos da conversa, mais recentes primeiro; user_a/user_b em
+// qualquer ordem (mesmo resultado dos dois lados). since = corte da
+// retenção do plano (-infinity = histórico completo)
+func (q *Queries) ListConversationMessages(ctx context.Context, arg ListConversationMessagesParams) ([]Message, error) {
+	rows, err := q.db.Query(ctx, listConversationMessages,
+		arg.UserA,
+		arg.UserB,
+		arg.Since,
 		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Message{}
+	for rows.Next() {
+		var i Message
+		if err := rows.Scan(
+			&i.ID,
+			&i.SenderID,
+			&i.ReceiverID,
+			&i.Content,
+			&i.Status,
+			&i.CreatedAt,
+			&i.DeliveredAt,
+			&i.ReadAt,
+			&i.Version,
+			&i.ClientSentAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listConversationMessagesBefore = `-- name: ListConversationMessagesBefore :many
+SELECT id, sender_id, receiver_id, content, status, created_at, delivered_at, read_at, version, client_sent_at FROM messages
+WHERE LEAST(sender_id, receiver_id) = LEAST($1::uuid, $2::uuid)
+  AND GREATEST(sender_id, receiver_id) = GREATEST($1::uuid, $2::uuid)
+  AND (created_at, id) < ($3::timestamp, $4::uuid)
+  AND created_at >= $5::timestamp
+ORDER BY created_at DESC, id DESC
+LIMIT $6
+`
+
+type ListConversationMessagesBeforeParams struct {
+	UserA           pgtype.UUID      `json:"user_a"`
+	UserB           pgtype.UUID      `json:"user_b"`
+	BeforeCreatedAt pgtype.Timestamp `json:"before_created_at"`
+	BeforeID        pgtype.UUID      `json:"before_id"`
+	Since           pgtype.Timestamp `json:"since"`
+	Limit           int32            `json:"limit"`
+}
+
+// Página anterior ao cursor (created_at, id), mesma ordem de ListConversationMessages
+func (q *Queries) ListConversationMessagesBefore(ctx context.Context, arg ListConversationMessagesBeforeParams) ([]Message, error) {
+	rows, err := q.db.Query(ctx, listConversationMessagesBefore,
+		arg.UserA,
+		arg.UserB,
+		arg.BeforeCreatedAt,
+		arg.BeforeID,
+		arg.Since,
+		arg.Limit,
 	)
 	if err != nil {
 		return nil, err
@@ -97,6 +220,10 @@ func (q *Queries) ListMessagesBetweenUsers(ctx context.Context, arg ListMessages
 			&i.Content,
 			&i.Status,
 			&i.CreatedAt,
+			&i.DeliveredAt,
+			&i.ReadAt,
+			&i.Version,
+			&i.ClientSentAt,
 		); err != nil {
 			return nil, err
 		}
@@ -108,16 +235,84 @@ func (q *Queries) ListMessagesBetweenUsers(ctx context.Context, arg ListMessages
 	return items, nil
 }
 
-const updateMessageStatus = `-- name: UpdateMessageStatus :exec
-UPDATE messages SET status = $2 WHERE id = $1
+const markMessageDelivered = `-- name: MarkMessageDelivered :one
+UPDATE messages SET status = 'delivered', delivered_at = NOW(), version = version + 1
+WHERE id = $1 AND receiver_id = $2 AND status = 'accepted'
+RETURNING id, sender_id, receiver_id, content, status, created_at, delivered_at, read_at, version, client_sent_at
+`
+
+type MarkMessageDeliveredParams struct {
+	ID         pgtype.UUID `json:"id"`
+	ReceiverID pgtype.UUID `json:"receiver_id"`
+}
+
+// Só avança de accepted; sem linha = já entregue/lida, de outro destinatário ou inexistente
+func (q *Queries) MarkMessageDelivered(ctx context.Context, arg MarkMessageDeliveredParams) (Message, error) {
+	row := q.db.QueryRow(ctx, markMessageDelivered, arg.ID, arg.ReceiverID)
+	var i Message
+	err := row.Scan(
+		&i.ID,
+		&i.SenderID,
+		&i.ReceiverID,
+		&i.Content,
+		&i.Status,
+		&i.CreatedAt,
+		&i.DeliveredAt,
+		&i.ReadAt,
+		&i.Version,
+		&i.ClientSentAt,
+	)
+	return i, err
+}
+
+const markMessageRead = `-- name: MarkMessageRead :one
+UPDATE messages SET status = 'read', read_at = NOW(), delivered_at = COALESCE(delivered_at, NOW()), version = version + 1
+WHERE id = $1 AND receiver_id = $2 AND status IN ('accepted', 'delivered') AND version = $3
+RETURNING id, sender_id, receiver_id, content, status, created_at, delivered_at, read_at, version, client_sent_at
+`
+
+type MarkMessageReadParams struct {
+	ID         pgtype.UUID `json:"id"`
+	ReceiverID pgtype.UUID `json:"receiver_id"`
+	Version    int32       `json:"version"`
+}
+
+// Leitura implica entrega: delivered_at é preenchido se ainda faltava.
+// Compare-and-set na versão lida ao validar a transição
+func (q *Queries) MarkMessageRead(ctx context.Context, arg MarkMessageReadParams) (Message, error) {
+	row := q.db.QueryRow(ctx, markMessageRead, arg.ID, arg.ReceiverID, arg.Version)
+	var i Message
+	err := row.Scan(
+		&i.ID,
+		&i.SenderID,
+		&i.ReceiverID,
+		&i.Content,
+		&i.Status,
+		&i.CreatedAt,
+		&i.DeliveredAt,
+		&i.ReadAt,
+		&i.Version,
+		&i.ClientSentAt,
+	)
+	return i, err
+}
+
+const updateMessageStatus = `-- name: UpdateMessageStatus :execrows
+UPDATE messages SET status = $2, version = version + 1
+WHERE id = $1 AND version = $3
 `
 
 type UpdateMessageStatusParams struct {
-	ID     pgtype.UUID `json:"id"`
-	Status string      `json:"status"`
+	ID      pgtype.UUID    `json:"id"`
+	Status  status.Message `json:"status"`
+	Version int32          `json:"version"`
 }
 
-func (q *Queries) UpdateMessageStatus(ctx context.Context, arg UpdateMessageStatusParams) error {
-	_, err := q.db.Exec(ctx, updateMessageStatus, arg.ID, arg.Status)
-	return err
+// Compare-and-set: 0 linhas = outra escrita avançou a versão desde a leitura
+func (q *Queries) UpdateMessageStatus(ctx context.Context, arg UpdateMessageStatusParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateMessageStatus, arg.ID, arg.Status, arg.Version)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
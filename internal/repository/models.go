@@ -5,39 +5,391 @@
 package repository
 
 import (
+	"chat-kafka-go/pkg/status"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type Announcement struct {
+	ID          pgtype.UUID      `json:"id"`
+	Content     string           `json:"content"`
+	SegmentPlan *string          `json:"segment_plan"`
+	RequiresAck bool             `json:"requires_ack"`
+	ScheduledAt pgtype.Timestamp `json:"scheduled_at"`
+	Status      string           `json:"status"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	StartedAt   pgtype.Timestamp `json:"started_at"`
+	FinishedAt  pgtype.Timestamp `json:"finished_at"`
+}
+
+type AnnouncementDelivery struct {
+	AnnouncementID pgtype.UUID      `json:"announcement_id"`
+	UserID         pgtype.UUID      `json:"user_id"`
+	MessageID      pgtype.UUID      `json:"message_id"`
+	DeliveredAt    pgtype.Timestamp `json:"delivered_at"`
+	AcknowledgedAt pgtype.Timestamp `json:"acknowledged_at"`
+}
+
+type ApiKey struct {
+	ID         pgtype.UUID      `json:"id"`
+	Name       string           `json:"name"`
+	Prefix     string           `json:"prefix"`
+	KeyHash    string           `json:"key_hash"`
+	Scopes     []string         `json:"scopes"`
+	UserID     pgtype.UUID      `json:"user_id"`
+	LastUsedAt pgtype.Timestamp `json:"last_used_at"`
+	RevokedAt  pgtype.Timestamp `json:"revoked_at"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+}
+
+type Attachment struct {
+	ID           pgtype.UUID      `json:"id"`
+	UploaderID   pgtype.UUID      `json:"uploader_id"`
+	MessageID    pgtype.UUID      `json:"message_id"`
+	StorageKey   string           `json:"storage_key"`
+	FileName     string           `json:"file_name"`
+	ContentType  string           `json:"content_type"`
+	SizeBytes    int64            `json:"size_bytes"`
+	Status       string           `json:"status"`
+	ScanStatus   string           `json:"scan_status"`
+	ScanResult   *string          `json:"scan_result"`
+	ScannedAt    pgtype.Timestamp `json:"scanned_at"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	NsfwScore    *float64         `json:"nsfw_score"`
+	NsfwLabels   []string         `json:"nsfw_labels"`
+	NsfwFlagged  bool             `json:"nsfw_flagged"`
+	PreviewKey   *string          `json:"preview_key"`
+	RenditionKey *string          `json:"rendition_key"`
+	PosterKey    *string          `json:"poster_key"`
+}
+
+type AuditEvent struct {
+	ID        pgtype.UUID      `json:"id"`
+	UserID    pgtype.UUID      `json:"user_id"`
+	Action    string           `json:"action"`
+	Ip        string           `json:"ip"`
+	RiskScore int32            `json:"risk_score"`
+	Metadata  []byte           `json:"metadata"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type AutoReply struct {
+	UserID      pgtype.UUID      `json:"user_id"`
+	Message     string           `json:"message"`
+	StartsAt    pgtype.Timestamp `json:"starts_at"`
+	EndsAt      pgtype.Timestamp `json:"ends_at"`
+	FriendsOnly bool             `json:"friends_only"`
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+}
+
+type AutoReplySend struct {
+	UserID   pgtype.UUID `json:"user_id"`
+	SenderID pgtype.UUID `json:"sender_id"`
+	Day      pgtype.Date `json:"day"`
+}
+
+type ContactHash struct {
+	Kind      string           `json:"kind"`
+	Hash      string           `json:"hash"`
+	UserID    pgtype.UUID      `json:"user_id"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type ContactLookup struct {
+	UserID    pgtype.UUID      `json:"user_id"`
+	Day       pgtype.Date      `json:"day"`
+	Hashes    int32            `json:"hashes"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+type ConversationSummary struct {
+	UserID             pgtype.UUID      `json:"user_id"`
+	PeerID             pgtype.UUID      `json:"peer_id"`
+	LastMessageID      pgtype.UUID      `json:"last_message_id"`
+	LastMessagePreview string           `json:"last_message_preview"`
+	LastMessageAt      pgtype.Timestamp `json:"last_message_at"`
+	UnreadCount        int32            `json:"unread_count"`
+	LastReadMessageID  pgtype.UUID      `json:"last_read_message_id"`
+	LastReadAt         pgtype.Timestamp `json:"last_read_at"`
+	UpdatedAt          pgtype.Timestamp `json:"updated_at"`
+}
+
+type ConversationShareLink struct {
+	ID        pgtype.UUID      `json:"id"`
+	OwnerID   pgtype.UUID      `json:"owner_id"`
+	PeerID    pgtype.UUID      `json:"peer_id"`
+	TokenHash string           `json:"token_hash"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	RevokedAt pgtype.Timestamp `json:"revoked_at"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type EmailVerificationToken struct {
+	ID        pgtype.UUID      `json:"id"`
+	UserID    pgtype.UUID      `json:"user_id"`
+	TokenHash string           `json:"token_hash"`
+	Email     string           `json:"email"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	UsedAt    pgtype.Timestamp `json:"used_at"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type EventConsumerOffset struct {
+	ConsumerGroup string           `json:"consumer_group"`
+	Topic         string           `json:"topic"`
+	LastEventID   int64            `json:"last_event_id"`
+	UpdatedAt     pgtype.Timestamp `json:"updated_at"`
+}
+
+type EventLog struct {
+	ID        int64            `json:"id"`
+	Topic     string           `json:"topic"`
+	Key       string           `json:"key"`
+	Value     []byte           `json:"value"`
+	Headers   []byte           `json:"headers"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
 type Friendship struct {
+	ID        pgtype.UUID       `json:"id"`
+	UserID    pgtype.UUID       `json:"user_id"`
+	FriendID  pgtype.UUID       `json:"friend_id"`
+	Status    status.Friendship `json:"status"`
+	CreatedAt pgtype.Timestamp  `json:"created_at"`
+	Version   int32             `json:"version"`
+}
+
+type ImpersonationSession struct {
 	ID        pgtype.UUID      `json:"id"`
 	UserID    pgtype.UUID      `json:"user_id"`
-	FriendID  pgtype.UUID      `json:"friend_id"`
-	Status    string           `json:"status"`
+	Operator  string           `json:"operator"`
+	Reason    string           `json:"reason"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	RevokedAt pgtype.Timestamp `json:"revoked_at"`
 	CreatedAt pgtype.Timestamp `json:"created_at"`
 }
 
-type Message struct {
+type Invitation struct {
+	ID         pgtype.UUID      `json:"id"`
+	InviterID  pgtype.UUID      `json:"inviter_id"`
+	Email      *string          `json:"email"`
+	TokenHash  string           `json:"token_hash"`
+	ExpiresAt  pgtype.Timestamp `json:"expires_at"`
+	AcceptedAt pgtype.Timestamp `json:"accepted_at"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+}
+
+type LegalHold struct {
 	ID         pgtype.UUID      `json:"id"`
-	SenderID   pgtype.UUID      `json:"sender_id"`
-	ReceiverID pgtype.UUID      `json:"receiver_id"`
-	Content    string           `json:"content"`
-	Status     string           `json:"status"`
+	UserID     pgtype.UUID      `json:"user_id"`
+	PeerID     pgtype.UUID      `json:"peer_id"`
+	Reason     string           `json:"reason"`
+	ReleasedAt pgtype.Timestamp `json:"released_at"`
 	CreatedAt  pgtype.Timestamp `json:"created_at"`
 }
 
+type LoginChallenge struct {
+	ID         pgtype.UUID      `json:"id"`
+	UserID     pgtype.UUID      `json:"user_id"`
+	CodeHash   string           `json:"code_hash"`
+	DeviceID   string           `json:"device_id"`
+	Attempts   int32            `json:"attempts"`
+	ExpiresAt  pgtype.Timestamp `json:"expires_at"`
+	VerifiedAt pgtype.Timestamp `json:"verified_at"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+}
+
+type LoginEvent struct {
+	ID             pgtype.UUID      `json:"id"`
+	UserID         pgtype.UUID      `json:"user_id"`
+	DeviceID       pgtype.UUID      `json:"device_id"`
+	RefreshTokenID pgtype.UUID      `json:"refresh_token_id"`
+	Ip             string           `json:"ip"`
+	Country        *string          `json:"country"`
+	City           *string          `json:"city"`
+	NewDevice      bool             `json:"new_device"`
+	NewLocation    bool             `json:"new_location"`
+	AlertTokenHash *string          `json:"alert_token_hash"`
+	ReportedAt     pgtype.Timestamp `json:"reported_at"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+}
+
+type Message struct {
+	ID           pgtype.UUID      `json:"id"`
+	SenderID     pgtype.UUID      `json:"sender_id"`
+	ReceiverID   pgtype.UUID      `json:"receiver_id"`
+	Content      string           `json:"content"`
+	Status       status.Message   `json:"status"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	DeliveredAt  pgtype.Timestamp `json:"delivered_at"`
+	ReadAt       pgtype.Timestamp `json:"read_at"`
+	Version      int32            `json:"version"`
+	ClientSentAt pgtype.Timestamp `json:"client_sent_at"`
+}
+
+type MessageMention struct {
+	MessageID pgtype.UUID      `json:"message_id"`
+	UserID    pgtype.UUID      `json:"user_id"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type Notification struct {
+	ID        pgtype.UUID      `json:"id"`
+	UserID    pgtype.UUID      `json:"user_id"`
+	Kind      string           `json:"kind"`
+	Payload   []byte           `json:"payload"`
+	ReadAt    pgtype.Timestamp `json:"read_at"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type PasswordResetToken struct {
+	ID        pgtype.UUID      `json:"id"`
+	UserID    pgtype.UUID      `json:"user_id"`
+	TokenHash string           `json:"token_hash"`
+	Email     string           `json:"email"`
+	Ip        string           `json:"ip"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	UsedAt    pgtype.Timestamp `json:"used_at"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type Referral struct {
+	InviteeID    pgtype.UUID      `json:"invitee_id"`
+	InviterID    pgtype.UUID      `json:"inviter_id"`
+	InvitationID pgtype.UUID      `json:"invitation_id"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+}
+
 type RefreshToken struct {
 	ID        pgtype.UUID      `json:"id"`
 	UserID    pgtype.UUID      `json:"user_id"`
-	Token     string           `json:"token"`
+	TokenHash string           `json:"token_hash"`
 	ExpiresAt pgtype.Timestamp `json:"expires_at"`
 	CreatedAt pgtype.Timestamp `json:"created_at"`
 }
 
-type User struct {
-	ID           pgtype.UUID      `json:"id"`
-	Username     string           `json:"username"`
-	Email        string           `json:"email"`
-	PasswordHash string           `json:"password_hash"`
+type SupportAccessGrant struct {
+	UserID    pgtype.UUID      `json:"user_id"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type SupportAgent struct {
+	UserID    pgtype.UUID      `json:"user_id"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type SupportTicket struct {
+	ID                 pgtype.UUID      `json:"id"`
+	CustomerID         pgtype.UUID      `json:"customer_id"`
+	AgentID            pgtype.UUID      `json:"agent_id"`
+	Subject            string           `json:"subject"`
+	Status             string           `json:"status"`
+	FirstResponseDueAt pgtype.Timestamp `json:"first_response_due_at"`
+	ResolutionDueAt    pgtype.Timestamp `json:"resolution_due_at"`
+	FirstResponseAt    pgtype.Timestamp `json:"first_response_at"`
+	ClosedAt           pgtype.Timestamp `json:"closed_at"`
+	CreatedAt          pgtype.Timestamp `json:"created_at"`
+	UpdatedAt          pgtype.Timestamp `json:"updated_at"`
+}
+
+type SupportTicketNote struct {
+	ID        pgtype.UUID      `json:"id"`
+	TicketID  pgtype.UUID      `json:"ticket_id"`
+	AuthorID  pgtype.UUID      `json:"author_id"`
+	Content   string           `json:"content"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type TranscodeJob struct {
+	AttachmentID pgtype.UUID      `json:"attachment_id"`
+	Status       string           `json:"status"`
+	Attempts     int32            `json:"attempts"`
+	Error        *string          `json:"error"`
+	StartedAt    pgtype.Timestamp `json:"started_at"`
+	FinishedAt   pgtype.Timestamp `json:"finished_at"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+}
+
+type UploadSession struct {
+	AttachmentID pgtype.UUID      `json:"attachment_id"`
+	UserID       pgtype.UUID      `json:"user_id"`
+	UploadLength int64            `json:"upload_length"`
+	UploadOffset int64            `json:"upload_offset"`
+	ExpiresAt    pgtype.Timestamp `json:"expires_at"`
 	CreatedAt    pgtype.Timestamp `json:"created_at"`
 	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
 }
+
+type User struct {
+	ID                pgtype.UUID      `json:"id"`
+	Username          string           `json:"username"`
+	Email             string           `json:"email"`
+	PasswordHash      string           `json:"password_hash"`
+	CreatedAt         pgtype.Timestamp `json:"created_at"`
+	UpdatedAt         pgtype.Timestamp `json:"updated_at"`
+	DeletedAt         pgtype.Timestamp `json:"deleted_at"`
+	UsernameChangedAt pgtype.Timestamp `json:"username_changed_at"`
+	ShadowBannedAt    pgtype.Timestamp `json:"shadow_banned_at"`
+	EmailVerifiedAt   pgtype.Timestamp `json:"email_verified_at"`
+}
+
+type UserDevice struct {
+	ID          pgtype.UUID      `json:"id"`
+	UserID      pgtype.UUID      `json:"user_id"`
+	Fingerprint string           `json:"fingerprint"`
+	UserAgent   string           `json:"user_agent"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	LastSeenAt  pgtype.Timestamp `json:"last_seen_at"`
+}
+
+type UserDndSetting struct {
+	UserID       pgtype.UUID      `json:"user_id"`
+	Timezone     string           `json:"timezone"`
+	Windows      []byte           `json:"windows"`
+	SnoozedUntil pgtype.Timestamp `json:"snoozed_until"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+}
+
+type UserPlan struct {
+	UserID                pgtype.UUID      `json:"user_id"`
+	Plan                  string           `json:"plan"`
+	Status                string           `json:"status"`
+	BillingProvider       *string          `json:"billing_provider"`
+	BillingCustomerID     *string          `json:"billing_customer_id"`
+	BillingSubscriptionID *string          `json:"billing_subscription_id"`
+	CurrentPeriodEnd      pgtype.Timestamp `json:"current_period_end"`
+	BillingEventAt        pgtype.Timestamp `json:"billing_event_at"`
+	UpdatedAt             pgtype.Timestamp `json:"updated_at"`
+}
+
+type UserPreference struct {
+	UserID    pgtype.UUID      `json:"user_id"`
+	Prefs     []byte           `json:"prefs"`
+	Version   int32            `json:"version"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+type UserPrivacySetting struct {
+	UserID             pgtype.UUID      `json:"user_id"`
+	MessagePolicy      string           `json:"message_policy"`
+	PresenceVisibility string           `json:"presence_visibility"`
+	ShareReadReceipts  bool             `json:"share_read_receipts"`
+	UpdatedAt          pgtype.Timestamp `json:"updated_at"`
+}
+
+type UserUsage struct {
+	UserID    pgtype.UUID      `json:"user_id"`
+	Day       pgtype.Date      `json:"day"`
+	Messages  int32            `json:"messages"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+type UsernameHistory struct {
+	ID            pgtype.UUID      `json:"id"`
+	UserID        pgtype.UUID      `json:"user_id"`
+	OldUsername   string           `json:"old_username"`
+	ChangedAt     pgtype.Timestamp `json:"changed_at"`
+	ReservedUntil pgtype.Timestamp `json:"reserved_until"`
+}
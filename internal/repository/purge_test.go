@@ -0,0 +1,191 @@
+package repository_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"chat-kafka-go/internal/database/dbtest"
+	"chat-kafka-go/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// purgeFixture contas removidas em volta do fim da carência
+type purgeFixture struct {
+	pool    *pgxpool.Pool
+	q       *repository.Queries
+	cutoff  pgtype.Timestamp
+	expired repository.User // Carência vencida, nada segura
+	recent  repository.User // Ainda na carência
+	held    repository.User // Carência vencida, retenção legal (mesmo liberada)
+	peer    repository.User // Carência vencida, conversou com usuário sob retenção
+	upload  repository.User // Carência vencida, anexo no armazenamento
+	keeper  repository.User // Ativo, sob retenção legal
+}
+
+func newPurgeFixture(t *testing.T) *purgeFixture {
+	t.Helper()
+	pool, q := newQueries(t)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	f := &purgeFixture{
+		pool:    pool,
+		q:       q,
+		cutoff:  pgtype.Timestamp{Time: now.Add(-30 * 24 * time.Hour), Valid: true},
+		expired: dbtest.User(t, pool, "expired"),
+		recent:  dbtest.User(t, pool, "recent"),
+		held:    dbtest.User(t, pool, "held"),
+		peer:    dbtest.User(t, pool, "peer"),
+		upload:  dbtest.User(t, pool, "upload"),
+		keeper:  dbtest.User(t, pool, "keeper"),
+	}
+
+	exec := func(sql string, args ...interface{}) {
+		t.Helper()
+		if _, err := pool.Exec(ctx, sql, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := f.cutoff.Time.Add(-time.Hour)
+	for _, u := range []repository.User{f.expired, f.held, f.peer, f.upload} {
+		exec("UPDATE users SET deleted_at = $2 WHERE id = $1", u.ID, old)
+	}
+	exec("UPDATE users SET deleted_at = $2 WHERE id = $1", f.recent.ID, f.cutoff.Time.Add(time.Hour))
+
+	hold, err := q.CreateLegalHold(ctx, repository.CreateLegalHoldParams{UserID: f.held.ID, Reason: "processo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.ReleaseLegalHold(ctx, hold.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.CreateLegalHold(ctx, repository.CreateLegalHoldParams{UserID: f.keeper.ID, Reason: "processo"}); err != nil {
+		t.Fatal(err)
+	}
+	exec("INSERT INTO messages (sender_id, receiver_id, content, status) VALUES ($1, $2, 'oi', 'accepted')", f.peer.ID, f.keeper.ID)
+	exec("INSERT INTO messages (sender_id, receiver_id, content, status) VALUES ($1, $2, 'oi', 'accepted')", f.expired.ID, f.recent.ID)
+	exec("INSERT INTO attachments (uploader_id, storage_key, file_name, content_type) VALUES ($1, 'k', 'a.png', 'image/png')", f.upload.ID)
+
+	// Dados pessoais fora de users
+	exec("INSERT INTO contact_hashes (kind, hash, user_id) VALUES ('email', $2, $1)", f.expired.ID, string(bytes.Repeat([]byte("a"), 64)))
+	exec("INSERT INTO username_history (user_id, old_username, reserved_until) VALUES ($1, 'antigo', NOW())", f.expired.ID)
+	return f
+}
+
+// exists usuário ainda tem linha em users
+func (f *purgeFixture) exists(t *testing.T, u repository.User) bool {
+	t.Helper()
+	_, err := f.q.GetUserByIDIncludingDeleted(context.Background(), u.ID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatal(err)
+	}
+	return err == nil
+}
+
+func (f *purgeFixture) count(t *testing.T, sql string, args ...interface{}) int {
+	t.Helper()
+	var n int
+	if err := f.pool.QueryRow(context.Background(), sql, args...).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestPurgeDeletedUsers(t *testing.T) {
+	f := newPurgeFixture(t)
+	ctx := context.Background()
+
+	uploads, err := f.q.ListPurgeableUserAttachments(ctx, repository.ListPurgeableUserAttachmentsParams{Cutoff: f.cutoff, BatchSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uploads) != 1 || uploads[0].UploaderID != f.upload.ID {
+		t.Errorf("anexos a coletar = %+v, quer só o de upload", uploads)
+	}
+
+	purged, err := f.q.PurgeDeletedUsers(ctx, repository.PurgeDeletedUsersParams{Cutoff: f.cutoff, BatchSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if purged != 1 {
+		t.Errorf("contas apagadas = %d, quer 1", purged)
+	}
+	for _, tt := range []struct {
+		user repository.User
+		kept bool
+	}{
+		{f.expired, false}, {f.recent, true}, {f.held, true}, {f.peer, true}, {f.upload, true}, {f.keeper, true},
+	} {
+		if got := f.exists(t, tt.user); got != tt.kept {
+			t.Errorf("%s mantido = %v, quer %v", tt.user.Username, got, tt.kept)
+		}
+	}
+	if n := f.count(t, "SELECT COUNT(*) FROM messages WHERE sender_id = $1", f.expired.ID); n != 0 {
+		t.Errorf("mensagens da conta apagada = %d, quer 0 (cascata)", n)
+	}
+}
+
+func TestAnonymizeDeletedUsers(t *testing.T) {
+	f := newPurgeFixture(t)
+	ctx := context.Background()
+	params := repository.AnonymizeDeletedUsersParams{Cutoff: f.cutoff, BatchSize: 10}
+
+	n, err := f.q.AnonymizeDeletedUsers(ctx, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Anexo não segura: com anonymize ele continua na conversa
+	if n != 2 {
+		t.Errorf("contas anonimizadas = %d, quer 2 (expired e upload)", n)
+	}
+
+	user, err := f.q.GetUserByIDIncludingDeleted(ctx, f.expired.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Username == f.expired.Username || user.Email == f.expired.Email || user.PasswordHash != "" {
+		t.Errorf("conta anonimizada mantém identidade: %+v", user)
+	}
+	if n := f.count(t, "SELECT COUNT(*) FROM messages WHERE sender_id = $1", f.expired.ID); n != 1 {
+		t.Errorf("mensagens da conta anonimizada = %d, quer 1 (histórico do outro lado)", n)
+	}
+	if n := f.count(t, "SELECT (SELECT COUNT(*) FROM contact_hashes WHERE user_id = $1) + (SELECT COUNT(*) FROM username_history WHERE user_id = $1)", f.expired.ID); n != 0 {
+		t.Errorf("dados pessoais restantes = %d, quer 0", n)
+	}
+	if held, err := f.q.GetUserByIDIncludingDeleted(ctx, f.held.ID); err != nil || held.Username != f.held.Username {
+		t.Errorf("conta sob retenção foi anonimizada: %+v, %v", held, err)
+	}
+
+	// Segunda rodada não repete as já anonimizadas
+	if n, err := f.q.AnonymizeDeletedUsers(ctx, params); err != nil || n != 0 {
+		t.Errorf("segunda rodada = %d, %v; quer 0", n, err)
+	}
+}
+
+func TestGetActiveAPIKeyByHashIgnoresDeletedOwner(t *testing.T) {
+	pool, q := newQueries(t)
+	ctx := context.Background()
+	bot := dbtest.User(t, pool, "bot")
+	hash := string(bytes.Repeat([]byte("b"), 64))
+
+	if _, err := q.CreateAPIKey(ctx, repository.CreateAPIKeyParams{
+		Name: "bot", Prefix: "ck_test", KeyHash: hash, Scopes: []string{}, UserID: bot.ID,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.GetActiveAPIKeyByHash(ctx, hash); err != nil {
+		t.Fatalf("chave de conta ativa: %v", err)
+	}
+
+	if _, err := q.SoftDeleteUser(ctx, bot.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.GetActiveAPIKeyByHash(ctx, hash); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("chave de conta removida: err = %v, quer pgx.ErrNoRows", err)
+	}
+}
@@ -11,23 +11,261 @@ import (
 )
 
 type Querier interface {
+	// Idempotente: mantém a primeira confirmação; 0 linhas = não entregue ao usuário
+	AcknowledgeAnnouncement(ctx context.Context, arg AcknowledgeAnnouncementParams) (int64, error)
+	AddSupportAgent(ctx context.Context, userID pgtype.UUID) error
+	// Avança o offset apenas se ninguém escreveu antes (PATCH concorrente)
+	AdvanceUploadSession(ctx context.Context, arg AdvanceUploadSessionParams) (int64, error)
+	// Trava transacional serializa as inserções: ids são confirmados em ordem e o
+	// consumidor, que avança por id, nunca pula um evento ainda não confirmado
+	AppendEvent(ctx context.Context, arg AppendEventParams) error
+	// Evento mais antigo que o último aplicado não sobrescreve (webhooks fora de ordem)
+	ApplyBillingPlan(ctx context.Context, arg ApplyBillingPlanParams) (int64, error)
+	AttachToMessage(ctx context.Context, arg AttachToMessageParams) (int64, error)
+	CancelAnnouncement(ctx context.Context, id pgtype.UUID) (int64, error)
+	// Reserva um lote para varredura; itens presos em 'scanning' (worker caiu)
+	// voltam para a fila depois de stale_before
+	ClaimAttachmentsForScan(ctx context.Context, arg ClaimAttachmentsForScanParams) ([]Attachment, error)
+	// Reserva a resposta do dia para a conversa; 0 linhas = já respondida hoje
+	ClaimAutoReply(ctx context.Context, arg ClaimAutoReplyParams) (int64, error)
+	// Reserva os anúncios vencidos; SKIP LOCKED evita envio duplicado entre instâncias
+	ClaimDueAnnouncements(ctx context.Context, arg ClaimDueAnnouncementsParams) ([]Announcement, error)
+	// Só chamados na fila: dois agentes não assumem o mesmo
+	ClaimSupportTicket(ctx context.Context, arg ClaimSupportTicketParams) (SupportTicket, error)
+	// Reserva um lote; jobs presos em 'running' (worker caiu) voltam depois de stale_before
+	ClaimTranscodeJobs(ctx context.Context, arg ClaimTranscodeJobsParams) ([]TranscodeJob, error)
+	CompleteTranscodeJob(ctx context.Context, attachmentID pgtype.UUID) error
+	// Conta uma mensagem se o dia ainda não chegou ao limite; sem linha = cota esgotada
+	// Soma os hashes consultados se o dia ainda comporta; sem linha = limite esgotado
+	ConsumeContactLookups(ctx context.Context, arg ConsumeContactLookupsParams) (int32, error)
+	ConsumeDailyMessage(ctx context.Context, arg ConsumeDailyMessageParams) (int32, error)
+	CountActiveLegalHolds(ctx context.Context) (int64, error)
+	CountAnnouncementDeliveries(ctx context.Context, announcementID pgtype.UUID) (CountAnnouncementDeliveriesRow, error)
+	CountIPAuditEventsSince(ctx context.Context, arg CountIPAuditEventsSinceParams) (int32, error)
+	CountUserAuditEventsSince(ctx context.Context, arg CountUserAuditEventsSinceParams) (int32, error)
+	CountUserLogins(ctx context.Context, userID pgtype.UUID) (int32, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
+	CreateAnnouncement(ctx context.Context, arg CreateAnnouncementParams) (Announcement, error)
+	// Entrega e mensagem no mesmo comando; 0 linhas = usuário já recebeu
+	CreateAnnouncementMessage(ctx context.Context, arg CreateAnnouncementMessageParams) (int64, error)
+	CreateAttachment(ctx context.Context, arg CreateAttachmentParams) (Attachment, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) error
+	CreateEmailVerificationToken(ctx context.Context, arg CreateEmailVerificationTokenParams) (EmailVerificationToken, error)
 	CreateFriendship(ctx context.Context, arg CreateFriendshipParams) (Friendship, error)
+	CreateImpersonationSession(ctx context.Context, arg CreateImpersonationSessionParams) (ImpersonationSession, error)
+	CreateInvitation(ctx context.Context, arg CreateInvitationParams) (Invitation, error)
+	// Sem linha = já existe retenção ativa para o alvo
+	CreateLegalHold(ctx context.Context, arg CreateLegalHoldParams) (LegalHold, error)
+	CreateLoginChallenge(ctx context.Context, arg CreateLoginChallengeParams) (LoginChallenge, error)
+	CreateLoginEvent(ctx context.Context, arg CreateLoginEventParams) (LoginEvent, error)
 	CreateMessage(ctx context.Context, arg CreateMessageParams) (Message, error)
+	CreateMessageMention(ctx context.Context, arg CreateMessageMentionParams) error
+	CreateMessagesPartition(ctx context.Context, month pgtype.Date) (string, error)
+	CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error)
+	CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) (PasswordResetToken, error)
+	CreateReferral(ctx context.Context, arg CreateReferralParams) error
 	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error)
+	CreateShareLink(ctx context.Context, arg CreateShareLinkParams) (ConversationShareLink, error)
+	CreateSupportTicket(ctx context.Context, arg CreateSupportTicketParams) (SupportTicket, error)
+	CreateSupportTicketNote(ctx context.Context, arg CreateSupportTicketNoteParams) (SupportTicketNote, error)
+	CreateTranscodeJob(ctx context.Context, attachmentID pgtype.UUID) error
+	CreateUploadSession(ctx context.Context, arg CreateUploadSessionParams) (UploadSession, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
-	DeleteRefreshToken(ctx context.Context, token string) error
+	CreateUserDevice(ctx context.Context, arg CreateUserDeviceParams) (UserDevice, error)
+	CreateUsernameHistory(ctx context.Context, arg CreateUsernameHistoryParams) error
+	DeleteAttachment(ctx context.Context, id pgtype.UUID) error
+	DeleteAutoReply(ctx context.Context, userID pgtype.UUID) (int64, error)
+	// Resumos que a reconstrução não preencheu (nenhum evento da conversa no tópico)
+	DeleteEmptyConversationSummaries(ctx context.Context) (int64, error)
+	DeleteRefreshToken(ctx context.Context, tokenHash string) error
+	DeleteRefreshTokenByID(ctx context.Context, id pgtype.UUID) error
+	DeleteSupportAccessGrant(ctx context.Context, userID pgtype.UUID) (int64, error)
+	DeleteUploadSession(ctx context.Context, attachmentID pgtype.UUID) error
 	DeleteUserRefreshTokens(ctx context.Context, userID pgtype.UUID) error
+	// Volta para a fila ou falha de vez ao atingir max_attempts
+	FailTranscodeJob(ctx context.Context, arg FailTranscodeJobParams) error
+	FinishAnnouncement(ctx context.Context, id pgtype.UUID) error
+	// Chave de bot com conta removida para de valer mesmo sem revogação
+	GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (ApiKey, error)
+	GetActiveImpersonationSession(ctx context.Context, id pgtype.UUID) (ImpersonationSession, error)
+	GetActiveShareLinkByTokenHash(ctx context.Context, tokenHash string) (ConversationShareLink, error)
+	GetActiveSupportAccessGrant(ctx context.Context, userID pgtype.UUID) (SupportAccessGrant, error)
+	GetActiveSupportTicket(ctx context.Context, customerID pgtype.UUID) (SupportTicket, error)
+	GetActiveUsernameReservation(ctx context.Context, oldUsername string) (UsernameHistory, error)
+	GetAnnouncement(ctx context.Context, id pgtype.UUID) (Announcement, error)
+	GetAttachmentByID(ctx context.Context, id pgtype.UUID) (Attachment, error)
+	GetAutoReply(ctx context.Context, userID pgtype.UUID) (AutoReply, error)
+	GetConsumerOffset(ctx context.Context, arg GetConsumerOffsetParams) (int64, error)
+	// Versão da lista de conversas (ETag): muda a cada resumo gravado pelo consumidor ou removido
+	GetConversationListVersion(ctx context.Context, userID pgtype.UUID) (GetConversationListVersionRow, error)
+	// Conversa identificada pelo par simétrico: user_a/user_b em qualquer ordem
+	GetConversationMessageCreatedAt(ctx context.Context, arg GetConversationMessageCreatedAtParams) (pgtype.Timestamp, error)
+	GetDNDSettings(ctx context.Context, userID pgtype.UUID) (UserDndSetting, error)
+	GetDailyMessages(ctx context.Context, arg GetDailyMessagesParams) (int32, error)
 	GetFriendship(ctx context.Context, arg GetFriendshipParams) (Friendship, error)
+	GetLastLoginEvent(ctx context.Context, userID pgtype.UUID) (LoginEvent, error)
+	// Último link enviado ao usuário (intervalo mínimo entre reenvios)
+	GetLatestEmailVerificationToken(ctx context.Context, userID pgtype.UUID) (EmailVerificationToken, error)
+	// Último pedido do usuário (intervalo mínimo entre pedidos)
+	GetLatestPasswordResetToken(ctx context.Context, userID pgtype.UUID) (PasswordResetToken, error)
+	GetLoginEventByAlertTokenHash(ctx context.Context, alertTokenHash *string) (LoginEvent, error)
 	GetMessageByID(ctx context.Context, id pgtype.UUID) (Message, error)
-	GetRefreshToken(ctx context.Context, token string) (RefreshToken, error)
+	GetPendingEmailVerificationToken(ctx context.Context, tokenHash string) (EmailVerificationToken, error)
+	GetPendingLoginChallenge(ctx context.Context, id pgtype.UUID) (LoginChallenge, error)
+	GetPendingPasswordResetToken(ctx context.Context, tokenHash string) (PasswordResetToken, error)
+	GetPrivacySettings(ctx context.Context, userID pgtype.UUID) (UserPrivacySetting, error)
+	GetReferralStats(ctx context.Context, inviterID pgtype.UUID) (GetReferralStatsRow, error)
+	GetRefreshToken(ctx context.Context, tokenHash string) (RefreshToken, error)
+	GetSupportTicket(ctx context.Context, id pgtype.UUID) (SupportTicket, error)
+	GetUploadSession(ctx context.Context, attachmentID pgtype.UUID) (UploadSession, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id pgtype.UUID) (User, error)
+	GetUserByIDIncludingDeleted(ctx context.Context, id pgtype.UUID) (User, error)
+	// Sem diferenciar maiúsculas (índice único em LOWER(username))
 	GetUserByUsername(ctx context.Context, username string) (User, error)
-	ListMessagesBetweenUsers(ctx context.Context, arg ListMessagesBetweenUsersParams) ([]Message, error)
+	GetUserDevice(ctx context.Context, arg GetUserDeviceParams) (UserDevice, error)
+	GetUserPlan(ctx context.Context, userID pgtype.UUID) (UserPlan, error)
+	GetUserPreferences(ctx context.Context, userID pgtype.UUID) (UserPreference, error)
+	// Busca em lote (lista de membros); IDs inexistentes ou excluídos ficam de fora
+	GetUsersByIDs(ctx context.Context, ids []pgtype.UUID) ([]User, error)
+	GetValidInvitationByTokenHash(ctx context.Context, tokenHash string) (Invitation, error)
+	HasLoginFromCountry(ctx context.Context, arg HasLoginFromCountryParams) (bool, error)
+	IncrementLoginChallengeAttempts(ctx context.Context, id pgtype.UUID) error
+	// Reenvio: só o link mais recente vale
+	InvalidateEmailVerificationTokens(ctx context.Context, userID pgtype.UUID) error
+	// Novo pedido ou senha redefinida: links pendentes deixam de valer
+	InvalidatePasswordResetTokens(ctx context.Context, userID pgtype.UUID) error
+	IsEmailVerified(ctx context.Context, id pgtype.UUID) (bool, error)
+	IsSupportAgent(ctx context.Context, userID pgtype.UUID) (bool, error)
+	IsUserShadowBanned(ctx context.Context, id pgtype.UUID) (bool, error)
+	LiftShadowBan(ctx context.Context, id pgtype.UUID) (int64, error)
+	ListAPIKeys(ctx context.Context) ([]ApiKey, error)
+	// Próximo lote de destinatários (ordem por id) com o plano gravado
+	ListAnnouncementRecipients(ctx context.Context, arg ListAnnouncementRecipientsParams) ([]ListAnnouncementRecipientsRow, error)
+	ListAnnouncements(ctx context.Context, arg ListAnnouncementsParams) ([]Announcement, error)
+	ListAttachmentsByMessageIDs(ctx context.Context, messageIds []pgtype.UUID) ([]Attachment, error)
+	ListAttachmentsWithDeletedMessage(ctx context.Context, batchSize int32) ([]Attachment, error)
+	ListAuditEventsByAction(ctx context.Context, arg ListAuditEventsByActionParams) ([]AuditEvent, error)
+	// Os dois sentidos da conversa, mais recentes primeiro; user_a/user_b em
+	// qualquer ordem (mesmo resultado dos dois lados)
+	ListConversationMessages(ctx context.Context, arg ListConversationMessagesParams) ([]Message, error)
+	// Página anterior ao cursor (created_at, id), mesma ordem de ListConversationMessages
+	ListConversationMessagesBefore(ctx context.Context, arg ListConversationMessagesBeforeParams) ([]Message, error)
+	ListConversationSummaries(ctx context.Context, arg ListConversationSummariesParams) ([]ConversationSummary, error)
+	ListCustomerSupportTickets(ctx context.Context, arg ListCustomerSupportTicketsParams) ([]SupportTicket, error)
+	ListEventsAfter(ctx context.Context, arg ListEventsAfterParams) ([]EventLog, error)
+	ListExpiredAttachments(ctx context.Context, arg ListExpiredAttachmentsParams) ([]Attachment, error)
+	ListExpiredUploadAttachments(ctx context.Context, arg ListExpiredUploadAttachmentsParams) ([]Attachment, error)
+	ListLegalHolds(ctx context.Context, activeOnly bool) ([]LegalHold, error)
+	ListPendingAnnouncements(ctx context.Context, userID pgtype.UUID) ([]ListPendingAnnouncementsRow, error)
+	// Uploads de contas a purgar: o objeto sai antes da conta, cuja remoção
+	// apagaria as linhas em cascata e deixaria os arquivos órfãos
+	ListPurgeableUserAttachments(ctx context.Context, arg ListPurgeableUserAttachmentsParams) ([]Attachment, error)
+	// Carrega os resultados do índice externo com as mesmas regras de visibilidade
+	// de SearchMessages (o índice pode estar defasado)
+	ListSearchMessagesByIDs(ctx context.Context, arg ListSearchMessagesByIDsParams) ([]ListSearchMessagesByIDsRow, error)
+	// Links ainda válidos do dono para a conversa
+	ListShareLinks(ctx context.Context, arg ListShareLinksParams) ([]ConversationShareLink, error)
+	// Upload não finalizado ou finalizado e nunca enviado em mensagem
+	ListStaleAttachmentUploads(ctx context.Context, arg ListStaleAttachmentUploadsParams) ([]Attachment, error)
+	ListSupportAgents(ctx context.Context) ([]ListSupportAgentsRow, error)
+	ListSupportTicketNotes(ctx context.Context, ticketID pgtype.UUID) ([]SupportTicketNote, error)
+	// Fila dos agentes: filtros opcionais de status e responsável (unassigned = sem agente)
+	ListSupportTickets(ctx context.Context, arg ListSupportTicketsParams) ([]SupportTicket, error)
+	ListUserAuditEvents(ctx context.Context, arg ListUserAuditEventsParams) ([]AuditEvent, error)
 	ListUserFriends(ctx context.Context, userID pgtype.UUID) ([]User, error)
+	ListUserImpersonationSessions(ctx context.Context, arg ListUserImpersonationSessionsParams) ([]ImpersonationSession, error)
+	ListUserNotifications(ctx context.Context, arg ListUserNotificationsParams) ([]Notification, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
-	UpdateFriendshipStatus(ctx context.Context, arg UpdateFriendshipStatusParams) error
-	UpdateMessageStatus(ctx context.Context, arg UpdateMessageStatusParams) error
+	ListUsersByContactHashes(ctx context.Context, arg ListUsersByContactHashesParams) ([]ListUsersByContactHashesRow, error)
+	// Usuários ativos sem hash de email (após a migração 040 ou troca do pepper)
+	ListUsersWithoutContactHash(ctx context.Context, limit int32) ([]ListUsersWithoutContactHashRow, error)
+	MarkAttachmentUploaded(ctx context.Context, arg MarkAttachmentUploadedParams) (int64, error)
+	MarkConversationRead(ctx context.Context, arg MarkConversationReadParams) error
+	MarkEmailVerificationTokenUsed(ctx context.Context, id pgtype.UUID) (int64, error)
+	// 0 linhas = o email do usuário mudou depois do envio do link
+	MarkEmailVerified(ctx context.Context, arg MarkEmailVerifiedParams) (int64, error)
+	MarkInvitationAccepted(ctx context.Context, id pgtype.UUID) error
+	MarkLoginChallengeVerified(ctx context.Context, id pgtype.UUID) (int64, error)
+	MarkLoginEventReported(ctx context.Context, id pgtype.UUID) error
+	// Só avança de accepted; sem linha = já entregue/lida, de outro destinatário ou inexistente
+	MarkMessageDelivered(ctx context.Context, arg MarkMessageDeliveredParams) (Message, error)
+	// Leitura implica entrega: delivered_at é preenchido se ainda faltava.
+	// Compare-and-set na versão lida ao validar a transição
+	MarkMessageRead(ctx context.Context, arg MarkMessageReadParams) (Message, error)
+	MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (int64, error)
+	MarkPasswordResetTokenUsed(ctx context.Context, id pgtype.UUID) (int64, error)
+	// Primeira resposta de agente (SLA); respostas seguintes só atualizam updated_at
+	MarkSupportTicketResponded(ctx context.Context, id pgtype.UUID) error
+	PruneEvents(ctx context.Context, createdBefore pgtype.Timestamp) (int64, error)
+	// Apaga de vez contas removidas antes do cutoff (fim da carência); mensagens,
+	// sessões e demais dados saem em cascata. Conta com upload ainda no
+	// armazenamento espera a coleta de anexos (próxima rodada)
+	PurgeDeletedUsers(ctx context.Context, arg PurgeDeletedUsersParams) (int64, error)
+	// Não lidas = mensagens do par posteriores à última lida; remetente em shadow ban não conta
+	RecomputeUnreadCounts(ctx context.Context) (int64, error)
+	ReleaseAnnouncement(ctx context.Context, id pgtype.UUID) error
+	ReleaseAttachmentScan(ctx context.Context, id pgtype.UUID) error
+	// Desfaz a reserva quando o envio falhou (a retentativa do evento responde)
+	ReleaseAutoReply(ctx context.Context, arg ReleaseAutoReplyParams) error
+	ReleaseLegalHold(ctx context.Context, id pgtype.UUID) (LegalHold, error)
+	RemoveSupportAgent(ctx context.Context, userID pgtype.UUID) (int64, error)
+	// Upsert no par canônico: sem linha cria o pedido (pending); pedido pendente
+	// no sentido inverso significa que os dois pediram, então vira accepted.
+	// Sem linha retornada = pedido repetido ou já são amigos
+	RequestFriendship(ctx context.Context, arg RequestFriendshipParams) (Friendship, error)
+	// Esvazia a última mensagem para a reconstrução preencher de novo, mantendo as marcações de leitura
+	ResetConversationSummaries(ctx context.Context) (int64, error)
+	// Só restaura dentro do período de carência (deleted_at posterior ao cutoff)
+	RestoreUser(ctx context.Context, arg RestoreUserParams) (int64, error)
+	RevokeAPIKey(ctx context.Context, id pgtype.UUID) (int64, error)
+	RevokeImpersonationSession(ctx context.Context, id pgtype.UUID) (ImpersonationSession, error)
+	RevokeShareLink(ctx context.Context, arg RevokeShareLinkParams) (int64, error)
+	// Exclusão da conta: revoga as chaves que agem em nome do usuário
+	RevokeUserAPIKeys(ctx context.Context, userID pgtype.UUID) (int64, error)
+	// Usuário retirou a autorização: sessões em andamento terminam junto
+	RevokeUserImpersonationSessions(ctx context.Context, userID pgtype.UUID) (int64, error)
+	SaveConsumerOffset(ctx context.Context, arg SaveConsumerOffsetParams) error
+	// Primeira gravação cria a linha (versão 1); depois só grava se a versão lida
+	// ainda for a atual (sem linha = outra escrita venceu)
+	SaveUserPreferences(ctx context.Context, arg SaveUserPreferencesParams) (UserPreference, error)
+	// Conversas do usuário cujo par (nome da conversa) casa com o padrão
+	SearchConversations(ctx context.Context, arg SearchConversationsParams) ([]SearchConversationsRow, error)
+	// Só mensagens de que o usuário participa; as de remetentes em shadow ban
+	// ficam ocultas para os demais (como no histórico)
+	SearchMessages(ctx context.Context, arg SearchMessagesParams) ([]SearchMessagesRow, error)
+	// Removidos, contas em shadow ban e o próprio usuário não aparecem;
+	// username exato primeiro, depois por similaridade
+	SearchUsers(ctx context.Context, arg SearchUsersParams) ([]SearchUsersRow, error)
+	SetAttachmentRenditions(ctx context.Context, arg SetAttachmentRenditionsParams) error
+	SetAttachmentSize(ctx context.Context, arg SetAttachmentSizeParams) (int64, error)
+	SetManualPlan(ctx context.Context, arg SetManualPlanParams) (UserPlan, error)
+	ShadowBanUser(ctx context.Context, id pgtype.UUID) (int64, error)
+	SoftDeleteUser(ctx context.Context, id pgtype.UUID) (int64, error)
+	SumAttachmentBytesByUploader(ctx context.Context, uploaderID pgtype.UUID) (int64, error)
+	// Atualiza no máximo uma vez por minuto (evita escrita a cada requisição)
+	TouchAPIKey(ctx context.Context, id pgtype.UUID) error
+	TouchUserDevice(ctx context.Context, arg TouchUserDeviceParams) error
+	UpdateAttachmentClassification(ctx context.Context, arg UpdateAttachmentClassificationParams) error
+	UpdateAttachmentScan(ctx context.Context, arg UpdateAttachmentScanParams) error
+	// Compare-and-set: 0 linhas = outra escrita avançou a versão desde a leitura
+	UpdateFriendshipStatus(ctx context.Context, arg UpdateFriendshipStatusParams) (int64, error)
+	// Compare-and-set: 0 linhas = outra escrita avançou a versão desde a leitura
+	UpdateMessageStatus(ctx context.Context, arg UpdateMessageStatusParams) (int64, error)
+	UpdateSupportTicketStatus(ctx context.Context, arg UpdateSupportTicketStatusParams) (SupportTicket, error)
+	// 0 linhas = o email do usuário mudou depois do envio do link (ou conta removida)
+	UpdatePassword(ctx context.Context, arg UpdatePasswordParams) (int64, error)
+	UpdateUsername(ctx context.Context, arg UpdateUsernameParams) error
+	UpsertAutoReply(ctx context.Context, arg UpsertAutoReplyParams) (AutoReply, error)
+	UpsertContactHash(ctx context.Context, arg UpsertContactHashParams) error
+	// Ignora reentregas (mesma mensagem) e mensagens mais antigas que a atual
+	UpsertConversationSummary(ctx context.Context, arg UpsertConversationSummaryParams) error
+	UpsertDNDSchedule(ctx context.Context, arg UpsertDNDScheduleParams) (UserDndSetting, error)
+	UpsertDNDSnooze(ctx context.Context, arg UpsertDNDSnoozeParams) (UserDndSetting, error)
+	UpsertPrivacySettings(ctx context.Context, arg UpsertPrivacySettingsParams) (UserPrivacySetting, error)
+	UpsertSupportAccessGrant(ctx context.Context, arg UpsertSupportAccessGrantParams) (SupportAccessGrant, error)
+	// Qualquer retenção ativa com o usuário (dele ou de uma conversa dele)
+	UserHasActiveLegalHold(ctx context.Context, userID pgtype.UUID) (bool, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,258 @@
+package repository_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"chat-kafka-go/internal/database/dbtest"
+	"chat-kafka-go/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Testes de integração das consultas sensíveis a concorrência: rodam o SQL
+// de verdade (TEST_DATABASE_URL), não a cópia em Go do repotest
+
+// concurrently roda fn em n goroutines liberadas juntas
+func concurrently(n int, fn func(i int)) {
+	var ready, done sync.WaitGroup
+	start := make(chan struct{})
+	ready.Add(n)
+	done.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer done.Done()
+			ready.Done()
+			<-start
+			fn(i)
+		}(i)
+	}
+	ready.Wait()
+	close(start)
+	done.Wait()
+}
+
+func day(t time.Time) pgtype.Date {
+	return pgtype.Date{Time: t, Valid: true}
+}
+
+func newQueries(t *testing.T) (*pgxpool.Pool, *repository.Queries) {
+	t.Helper()
+	pool := dbtest.New(t)
+	return pool, repository.New(pool)
+}
+
+func TestConsumeDailyMessageConcurrentLimit(t *testing.T) {
+	pool, q := newQueries(t)
+	user := dbtest.User(t, pool, "alice")
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	const limit, senders = 5, 20
+
+	var mu sync.Mutex
+	var accepted, refused int
+	concurrently(senders, func(int) {
+		_, err := q.ConsumeDailyMessage(context.Background(), repository.ConsumeDailyMessageParams{
+			UserID: user.ID, Day: day(today), MaxMessages: limit,
+		})
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case err == nil:
+			accepted++
+		case errors.Is(err, pgx.ErrNoRows):
+			refused++
+		default:
+			t.Errorf("ConsumeDailyMessage: %v", err)
+		}
+	})
+	if accepted != limit || refused != senders-limit {
+		t.Errorf("aceitas %d e recusadas %d, quer %d e %d", accepted, refused, limit, senders-limit)
+	}
+
+	// Dia novo zera a contagem mesmo com o dia anterior esgotado
+	count, err := q.ConsumeDailyMessage(context.Background(), repository.ConsumeDailyMessageParams{
+		UserID: user.ID, Day: day(today.AddDate(0, 0, 1)), MaxMessages: limit,
+	})
+	if err != nil || count != 1 {
+		t.Errorf("dia seguinte = %d, %v; quer 1", count, err)
+	}
+}
+
+func TestConsumeContactLookupsConcurrentLimit(t *testing.T) {
+	pool, q := newQueries(t)
+	user := dbtest.User(t, pool, "alice")
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	// 10 lotes de 3 hashes contra limite de 10: cabem 3 lotes
+	var mu sync.Mutex
+	var accepted int
+	concurrently(10, func(int) {
+		_, err := q.ConsumeContactLookups(context.Background(), repository.ConsumeContactLookupsParams{
+			UserID: user.ID, Day: day(today), Hashes: 3, MaxHashes: 10,
+		})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			t.Errorf("ConsumeContactLookups: %v", err)
+			return
+		}
+		if err == nil {
+			mu.Lock()
+			accepted++
+			mu.Unlock()
+		}
+	})
+	if accepted != 3 {
+		t.Errorf("lotes aceitos = %d, quer 3", accepted)
+	}
+
+	// Lote que passaria do limite é recusado inteiro, não em parte
+	_, err := q.ConsumeContactLookups(context.Background(), repository.ConsumeContactLookupsParams{
+		UserID: user.ID, Day: day(today), Hashes: 2, MaxHashes: 10,
+	})
+	if !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("lote acima do limite: err = %v, quer pgx.ErrNoRows", err)
+	}
+	hashes, err := q.ConsumeContactLookups(context.Background(), repository.ConsumeContactLookupsParams{
+		UserID: user.ID, Day: day(today), Hashes: 1, MaxHashes: 10,
+	})
+	if err != nil || hashes != 10 {
+		t.Errorf("lote que completa o limite = %d, %v; quer 10", hashes, err)
+	}
+}
+
+func TestClaimAutoReplyOncePerDay(t *testing.T) {
+	pool, q := newQueries(t)
+	owner := dbtest.User(t, pool, "alice")
+	sender := dbtest.User(t, pool, "bob")
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	claim := func(d time.Time) (int64, error) {
+		return q.ClaimAutoReply(context.Background(), repository.ClaimAutoReplyParams{
+			UserID: owner.ID, SenderID: sender.ID, Day: day(d),
+		})
+	}
+
+	var mu sync.Mutex
+	var claimed int64
+	concurrently(10, func(int) {
+		rows, err := claim(today)
+		if err != nil {
+			t.Errorf("ClaimAutoReply: %v", err)
+			return
+		}
+		mu.Lock()
+		claimed += rows
+		mu.Unlock()
+	})
+	if claimed != 1 {
+		t.Errorf("reservas no mesmo dia = %d, quer 1", claimed)
+	}
+
+	if rows, err := claim(today.AddDate(0, 0, -1)); err != nil || rows != 0 {
+		t.Errorf("dia anterior = %d, %v; quer 0 (não volta a reserva)", rows, err)
+	}
+	if rows, err := claim(today.AddDate(0, 0, 1)); err != nil || rows != 1 {
+		t.Errorf("dia seguinte = %d, %v; quer 1", rows, err)
+	}
+}
+
+func TestCreateLegalHoldOneActivePerTarget(t *testing.T) {
+	pool, q := newQueries(t)
+	alice := dbtest.User(t, pool, "alice")
+	bob := dbtest.User(t, pool, "bob")
+	ctx := context.Background()
+
+	// CHECK (user_id < peer_id): conversa guardada com o par ordenado
+	low, high := alice.ID, bob.ID
+	if bytes.Compare(low.Bytes[:], high.Bytes[:]) > 0 {
+		low, high = high, low
+	}
+	noPeer := pgtype.UUID{}
+
+	var mu sync.Mutex
+	var created []repository.LegalHold
+	concurrently(5, func(int) {
+		hold, err := q.CreateLegalHold(ctx, repository.CreateLegalHoldParams{UserID: low, PeerID: noPeer, Reason: "processo"})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return
+		}
+		if err != nil {
+			t.Errorf("CreateLegalHold: %v", err)
+			return
+		}
+		mu.Lock()
+		created = append(created, hold)
+		mu.Unlock()
+	})
+	if len(created) != 1 {
+		t.Fatalf("retenções ativas do usuário = %d, quer 1", len(created))
+	}
+
+	// Conversa do mesmo usuário é outro alvo
+	if _, err := q.CreateLegalHold(ctx, repository.CreateLegalHoldParams{UserID: low, PeerID: high, Reason: "conversa"}); err != nil {
+		t.Errorf("retenção da conversa: %v", err)
+	}
+	if _, err := q.CreateLegalHold(ctx, repository.CreateLegalHoldParams{UserID: low, PeerID: high, Reason: "conversa"}); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("segunda retenção da conversa: err = %v, quer pgx.ErrNoRows", err)
+	}
+
+	// Liberada não conta: nova retenção do mesmo alvo entra
+	if _, err := q.ReleaseLegalHold(ctx, created[0].ID); err != nil {
+		t.Fatalf("ReleaseLegalHold: %v", err)
+	}
+	if _, err := q.CreateLegalHold(ctx, repository.CreateLegalHoldParams{UserID: low, PeerID: noPeer, Reason: "novo processo"}); err != nil {
+		t.Errorf("retenção após liberar: %v", err)
+	}
+}
+
+func TestRestoreUserCutoff(t *testing.T) {
+	pool, q := newQueries(t)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	cutoff := pgtype.Timestamp{Time: now.Add(-30 * 24 * time.Hour), Valid: true}
+
+	deletedAt := func(user repository.User, at time.Time) {
+		t.Helper()
+		if _, err := pool.Exec(ctx, "UPDATE users SET deleted_at = $2 WHERE id = $1", user.ID, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+	restore := func(user repository.User) int64 {
+		t.Helper()
+		rows, err := q.RestoreUser(ctx, repository.RestoreUserParams{ID: user.ID, Cutoff: cutoff})
+		if err != nil {
+			t.Fatalf("RestoreUser: %v", err)
+		}
+		return rows
+	}
+
+	recent := dbtest.User(t, pool, "recent")
+	deletedAt(recent, cutoff.Time.Add(time.Hour))
+	expired := dbtest.User(t, pool, "expired")
+	deletedAt(expired, cutoff.Time.Add(-time.Hour))
+	boundary := dbtest.User(t, pool, "boundary")
+	deletedAt(boundary, cutoff.Time)
+	active := dbtest.User(t, pool, "active")
+
+	for _, tt := range []struct {
+		user repository.User
+		want int64
+	}{
+		{recent, 1},   // Dentro da carência
+		{expired, 0},  // Carência vencida
+		{boundary, 0}, // deleted_at = cutoff já venceu
+		{active, 0},   // Nada a restaurar
+	} {
+		if got := restore(tt.user); got != tt.want {
+			t.Errorf("RestoreUser(%s) = %d, quer %d", tt.user.Username, got, tt.want)
+		}
+	}
+
+	if user, err := q.GetUserByID(ctx, recent.ID); err != nil || user.DeletedAt.Valid {
+		t.Errorf("usuário restaurado: %+v, %v; quer ativo", user, err)
+	}
+}
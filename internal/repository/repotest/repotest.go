@@ -0,0 +1,299 @@
+// Package repotest banco falso para testes de services e handlers: implementa
+// repository.DBTX respondendo cada query pelo nome gerado (-- name: X), sem
+// Postgres. Queries sem resposta registrada falham, então o teste declara
+// exatamente o que o código deve consultar. Transações (Begin) só marcam as
+// chamadas e o desfecho: o estado simulado pelos handlers não é desfeito
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Result resposta de uma query: linhas (valores na ordem do SELECT/RETURNING),
+// linhas afetadas (Exec) ou erro
+type Result struct {
+	Rows         [][]interface{}
+	RowsAffected int64
+	Err          error
+}
+
+// Handler responde uma query a partir dos argumentos
+type Handler func(args []interface{}) Result
+
+// Call chamada registrada
+type Call struct {
+	Name string
+	Args []interface{}
+	Tx   int // Transação em que rodou (1, 2...; 0 = fora de transação)
+}
+
+// Desfecho de uma transação
+const (
+	txOpen = iota
+	txCommitted
+	txRolledBack
+)
+
+// DB implementa repository.DBTX e repository.TxBeginner
+type DB struct {
+	mu       sync.Mutex
+	handlers map[string]Handler
+	calls    []Call
+	txs      []int // Desfecho de cada transação (índice = número - 1)
+}
+
+// New cria banco sem respostas
+func New() *DB {
+	return &DB{handlers: map[string]Handler{}}
+}
+
+// On registra a resposta da query name
+func (db *DB) On(name string, h Handler) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.handlers[name] = h
+}
+
+// Calls argumentos de cada chamada da query name, em ordem
+func (db *DB) Calls(name string) [][]interface{} {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	var out [][]interface{}
+	for _, c := range db.calls {
+		if c.Name == name {
+			out = append(out, c.Args)
+		}
+	}
+	return out
+}
+
+// Tx transação de cada chamada da query name, em ordem (0 = fora de transação)
+func (db *DB) Tx(name string) []int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	var out []int
+	for _, c := range db.calls {
+		if c.Name == name {
+			out = append(out, c.Tx)
+		}
+	}
+	return out
+}
+
+// Committed indica se a transação n terminou em commit
+func (db *DB) Committed(n int) bool {
+	return db.txState(n) == txCommitted
+}
+
+// RolledBack indica se a transação n terminou em rollback
+func (db *DB) RolledBack(n int) bool {
+	return db.txState(n) == txRolledBack
+}
+
+func (db *DB) txState(n int) int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if n < 1 || n > len(db.txs) {
+		return -1
+	}
+	return db.txs[n-1]
+}
+
+// Rows resposta com as linhas informadas
+func Rows(rows ...[]interface{}) Result {
+	return Result{Rows: rows}
+}
+
+// Row valores de uma linha a partir de um model: campos exportados na ordem
+// da struct, que é a ordem das colunas lidas pelo código gerado
+func Row(model interface{}) []interface{} {
+	v := reflect.ValueOf(model)
+	values := make([]interface{}, 0, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).IsExported() {
+			values = append(values, v.Field(i).Interface())
+		}
+	}
+	return values
+}
+
+// Exec implementa repository.DBTX
+func (db *DB) Exec(_ context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return db.exec(0, sql, args)
+}
+
+// Query implementa repository.DBTX
+func (db *DB) Query(_ context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return db.query(0, sql, args)
+}
+
+// QueryRow implementa repository.DBTX (sem linhas: pgx.ErrNoRows)
+func (db *DB) QueryRow(_ context.Context, sql string, args ...interface{}) pgx.Row {
+	return db.queryRow(0, sql, args)
+}
+
+// Begin implementa repository.TxBeginner
+func (db *DB) Begin(context.Context) (pgx.Tx, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.txs = append(db.txs, txOpen)
+	return &tx{db: db, n: len(db.txs)}, nil
+}
+
+func (db *DB) exec(tx int, sql string, args []interface{}) (pgconn.CommandTag, error) {
+	res := db.run(tx, sql, args)
+	if res.Err != nil {
+		return pgconn.CommandTag{}, res.Err
+	}
+	return pgconn.NewCommandTag(fmt.Sprintf("UPDATE %d", res.RowsAffected)), nil
+}
+
+func (db *DB) query(tx int, sql string, args []interface{}) (pgx.Rows, error) {
+	res := db.run(tx, sql, args)
+	if res.Err != nil {
+		return nil, res.Err
+	}
+	return &rows{rows: res.Rows, pos: -1}, nil
+}
+
+func (db *DB) queryRow(tx int, sql string, args []interface{}) pgx.Row {
+	res := db.run(tx, sql, args)
+	switch {
+	case res.Err != nil:
+		return row{err: res.Err}
+	case len(res.Rows) == 0:
+		return row{err: pgx.ErrNoRows}
+	default:
+		return row{values: res.Rows[0]}
+	}
+}
+
+func (db *DB) run(tx int, sql string, args []interface{}) Result {
+	name := queryName(sql)
+
+	db.mu.Lock()
+	db.calls = append(db.calls, Call{Name: name, Args: args, Tx: tx})
+	h, ok := db.handlers[name]
+	db.mu.Unlock()
+
+	if !ok {
+		return Result{Err: fmt.Errorf("repotest: query sem resposta: %s", name)}
+	}
+	return h(args)
+}
+
+// finish encerra a transação n (depois do commit, o Rollback do defer não muda nada)
+func (db *DB) finish(n, state int) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.txs[n-1] != txOpen {
+		return pgx.ErrTxClosed
+	}
+	db.txs[n-1] = state
+	return nil
+}
+
+// tx transação falsa: as queries passam pelas respostas do DB marcadas com o
+// número dela. Os demais métodos de pgx.Tx (CopyFrom, SendBatch...) não são
+// usados pelos services e ficam no pgx.Tx nil
+type tx struct {
+	pgx.Tx
+	db *DB
+	n  int
+}
+
+func (t *tx) Begin(context.Context) (pgx.Tx, error) {
+	return nil, errors.New("repotest: transação aninhada não suportada")
+}
+
+func (t *tx) Commit(context.Context) error   { return t.db.finish(t.n, txCommitted) }
+func (t *tx) Rollback(context.Context) error { return t.db.finish(t.n, txRolledBack) }
+
+func (t *tx) Exec(_ context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return t.db.exec(t.n, sql, args)
+}
+
+func (t *tx) Query(_ context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return t.db.query(t.n, sql, args)
+}
+
+func (t *tx) QueryRow(_ context.Context, sql string, args ...interface{}) pgx.Row {
+	return t.db.queryRow(t.n, sql, args)
+}
+
+// queryName nome da query no comentário gerado (-- name: X :kind)
+func queryName(sql string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(sql), "\n")
+	if fields := strings.Fields(line); len(fields) >= 3 && fields[0] == "--" && fields[1] == "name:" {
+		return fields[2]
+	}
+	return line
+}
+
+type row struct {
+	values []interface{}
+	err    error
+}
+
+func (r row) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	return scan(r.values, dest)
+}
+
+type rows struct {
+	rows [][]interface{}
+	pos  int
+}
+
+func (r *rows) Close()                                       {}
+func (r *rows) Err() error                                   { return nil }
+func (r *rows) CommandTag() pgconn.CommandTag                { return pgconn.NewCommandTag("SELECT") }
+func (r *rows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *rows) RawValues() [][]byte                          { return nil }
+func (r *rows) Conn() *pgx.Conn                              { return nil }
+
+func (r *rows) Next() bool {
+	r.pos++
+	return r.pos < len(r.rows)
+}
+
+func (r *rows) Scan(dest ...interface{}) error {
+	return scan(r.rows[r.pos], dest)
+}
+
+func (r *rows) Values() ([]interface{}, error) {
+	return r.rows[r.pos], nil
+}
+
+// scan copia os valores para os destinos (nil zera o destino)
+func scan(values, dest []interface{}) error {
+	if len(values) != len(dest) {
+		return fmt.Errorf("repotest: %d valores para %d destinos", len(values), len(dest))
+	}
+	for i, d := range dest {
+		target := reflect.ValueOf(d).Elem()
+		if values[i] == nil {
+			target.Set(reflect.Zero(target.Type()))
+			continue
+		}
+		v := reflect.ValueOf(values[i])
+		if !v.Type().AssignableTo(target.Type()) {
+			if !v.Type().ConvertibleTo(target.Type()) {
+				return fmt.Errorf("repotest: coluna %d: %s em %s", i, v.Type(), target.Type())
+			}
+			v = v.Convert(target.Type())
+		}
+		target.Set(v)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrTxUnsupported conexão sem transações (ex: conjunto de réplicas do Reader)
+var ErrTxUnsupported = errors.New("conexão não suporta transações")
+
+// TxBeginner conexão que abre transações (pgxpool.Pool, pgx.Tx, repotest.DB)
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// InTx roda fn com queries presas a uma transação: commit se fn retornar
+// nil, rollback em erro. Dentro de outra transação vira savepoint
+func (q *Queries) InTx(ctx context.Context, fn func(q *Queries) error) error {
+	db, ok := q.db.(TxBeginner)
+	if !ok {
+		return ErrTxUnsupported
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(q.WithTx(tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro no commit: %w", err)
+	}
+	return nil
+}
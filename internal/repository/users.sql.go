@@ -11,10 +11,27 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const anonymizeDeletedUsers = `-- name: AnonymizeDeletedUsers :one
+SELECT anonymize_deleted_users($1::timestamp, $2::int)::int AS anonymized
+`
+
+type AnonymizeDeletedUsersParams struct {
+	Cutoff    pgtype.Timestamp `json:"cutoff"`
+	BatchSize int32            `json:"batch_size"`
+}
+
+// DELETED_USER_MESSAGES=anonymize: a conta fica, sem identidade (ver migração 042)
+func (q *Queries) AnonymizeDeletedUsers(ctx context.Context, arg AnonymizeDeletedUsersParams) (int32, error) {
+	row := q.db.QueryRow(ctx, anonymizeDeletedUsers, arg.Cutoff, arg.BatchSize)
+	var anonymized int32
+	err := row.Scan(&anonymized)
+	return anonymized, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (username, email, password_hash)
 VALUES ($1, $2, $3)
-RETURNING id, username, email, password_hash, created_at, updated_at
+RETURNING id, username, email, password_hash, created_at, updated_at, deleted_at, username_changed_at, shadow_banned_at, email_verified_at
 `
 
 type CreateUserParams struct {
@@ -33,12 +50,52 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.UsernameChangedAt,
+		&i.ShadowBannedAt,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
+const createUsernameHistory = `-- name: CreateUsernameHistory :exec
+INSERT INTO username_history (user_id, old_username, reserved_until)
+VALUES ($1, $2, $3)
+`
+
+type CreateUsernameHistoryParams struct {
+	UserID        pgtype.UUID      `json:"user_id"`
+	OldUsername   string           `json:"old_username"`
+	ReservedUntil pgtype.Timestamp `json:"reserved_until"`
+}
+
+func (q *Queries) CreateUsernameHistory(ctx context.Context, arg CreateUsernameHistoryParams) error {
+	_, err := q.db.Exec(ctx, createUsernameHistory, arg.UserID, arg.OldUsername, arg.ReservedUntil)
+	return err
+}
+
+const getActiveUsernameReservation = `-- name: GetActiveUsernameReservation :one
+SELECT id, user_id, old_username, changed_at, reserved_until FROM username_history
+WHERE LOWER(old_username) = LOWER($1) AND reserved_until > NOW()
+ORDER BY changed_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetActiveUsernameReservation(ctx context.Context, oldUsername string) (UsernameHistory, error) {
+	row := q.db.QueryRow(ctx, getActiveUsernameReservation, oldUsername)
+	var i UsernameHistory
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.OldUsername,
+		&i.ChangedAt,
+		&i.ReservedUntil,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, username, email, password_hash, created_at, updated_at FROM users WHERE email = $1
+SELECT id, username, email, password_hash, created_at, updated_at, deleted_at, username_changed_at, shadow_banned_at, email_verified_at FROM users WHERE email = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
@@ -51,12 +108,16 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.UsernameChangedAt,
+		&i.ShadowBannedAt,
+		&i.EmailVerifiedAt,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, username, email, password_hash, created_at, updated_at FROM users WHERE id = $1
+SELECT id, username, email, password_hash, created_at, updated_at, deleted_at, username_changed_at, shadow_banned_at, email_verified_at FROM users WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetUserByID(ctx context.Context, id pgtype.UUID) (User, error) {
@@ -69,14 +130,41 @@ func (q *Queries) GetUserByID(ctx context.Context, id pgtype.UUID) (User, error)
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.UsernameChangedAt,
+		&i.ShadowBannedAt,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
+const getUserByIDIncludingDeleted = `-- name: GetUserByIDIncludingDeleted :one
+SELECT id, username, email, password_hash, created_at, updated_at, deleted_at, username_changed_at, shadow_banned_at, email_verified_at FROM users WHERE id = $1
+`
+
+func (q *Queries) GetUserByIDIncludingDeleted(ctx context.Context, id pgtype.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByIDIncludingDeleted, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.UsernameChangedAt,
+		&i.ShadowBannedAt,
+		&i.EmailVerifiedAt,
 	)
 	return i, err
 }
 
 const getUserByUsername = `-- name: GetUserByUsername :one
-SELECT id, username, email, password_hash, created_at, updated_at FROM users WHERE username = $1
+SELECT id, username, email, password_hash, created_at, updated_at, deleted_at, username_changed_at, shadow_banned_at, email_verified_at FROM users WHERE LOWER(username) = LOWER($1) AND deleted_at IS NULL
 `
 
+// Sem diferenciar maiúsculas (índice único em LOWER(username))
 func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User, error) {
 	row := q.db.QueryRow(ctx, getUserByUsername, username)
 	var i User
@@ -87,12 +175,88 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User,
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.UsernameChangedAt,
+		&i.ShadowBannedAt,
+		&i.EmailVerifiedAt,
 	)
 	return i, err
 }
 
+const getUsersByIDs = `-- name: GetUsersByIDs :many
+SELECT id, username, email, password_hash, created_at, updated_at, deleted_at, username_changed_at, shadow_banned_at, email_verified_at FROM users
+WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+`
+
+// Busca em lote (lista de membros); IDs inexistentes ou excluídos ficam de fora
+func (q *Queries) GetUsersByIDs(ctx context.Context, ids []pgtype.UUID) ([]User, error) {
+	rows, err := q.db.Query(ctx, getUsersByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.Email,
+			&i.PasswordHash,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.UsernameChangedAt,
+			&i.ShadowBannedAt,
+			&i.EmailVerifiedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const isEmailVerified = `-- name: IsEmailVerified :one
+SELECT (email_verified_at IS NOT NULL)::bool AS verified FROM users WHERE id = $1
+`
+
+func (q *Queries) IsEmailVerified(ctx context.Context, id pgtype.UUID) (bool, error) {
+	row := q.db.QueryRow(ctx, isEmailVerified, id)
+	var verified bool
+	err := row.Scan(&verified)
+	return verified, err
+}
+
+const isUserShadowBanned = `-- name: IsUserShadowBanned :one
+SELECT (shadow_banned_at IS NOT NULL)::bool AS banned FROM users WHERE id = $1
+`
+
+func (q *Queries) IsUserShadowBanned(ctx context.Context, id pgtype.UUID) (bool, error) {
+	row := q.db.QueryRow(ctx, isUserShadowBanned, id)
+	var banned bool
+	err := row.Scan(&banned)
+	return banned, err
+}
+
+const liftShadowBan = `-- name: LiftShadowBan :execrows
+UPDATE users SET shadow_banned_at = NULL WHERE id = $1 AND shadow_banned_at IS NOT NULL
+`
+
+func (q *Queries) LiftShadowBan(ctx context.Context, id pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, liftShadowBan, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const listUsers = `-- name: ListUsers :many
-SELECT id, username, email, password_hash, created_at, updated_at FROM users
+SELECT id, username, email, password_hash, created_at, updated_at, deleted_at, username_changed_at, shadow_banned_at, email_verified_at FROM users
+WHERE deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2
 `
@@ -118,6 +282,10 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 			&i.PasswordHash,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.UsernameChangedAt,
+			&i.ShadowBannedAt,
+			&i.EmailVerifiedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -128,3 +296,127 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 	}
 	return items, nil
 }
+
+const markEmailVerified = `-- name: MarkEmailVerified :execrows
+UPDATE users SET email_verified_at = COALESCE(email_verified_at, NOW())
+WHERE id = $1 AND email = $2 AND deleted_at IS NULL
+`
+
+type MarkEmailVerifiedParams struct {
+	ID    pgtype.UUID `json:"id"`
+	Email string      `json:"email"`
+}
+
+// 0 linhas = o email do usuário mudou depois do envio do link
+func (q *Queries) MarkEmailVerified(ctx context.Context, arg MarkEmailVerifiedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markEmailVerified, arg.ID, arg.Email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgeDeletedUsers = `-- name: PurgeDeletedUsers :execrows
+DELETE FROM users d
+WHERE d.deleted_at < $1 AND d.id IN (
+  SELECT u.id FROM users u
+  WHERE u.deleted_at < $1
+    AND NOT EXISTS (SELECT 1 FROM attachments a WHERE a.uploader_id = u.id)
+    AND NOT user_purge_blocked(u.id)
+  ORDER BY u.deleted_at
+  LIMIT $2
+)
+`
+
+type PurgeDeletedUsersParams struct {
+	Cutoff    pgtype.Timestamp `json:"cutoff"`
+	BatchSize int32            `json:"batch_size"`
+}
+
+// DELETED_USER_MESSAGES=hide: apaga de vez contas removidas antes do cutoff
+// (fim da carência); mensagens, sessões e demais dados saem em cascata.
+// Conta com upload ainda no armazenamento espera a coleta de anexos
+func (q *Queries) PurgeDeletedUsers(ctx context.Context, arg PurgeDeletedUsersParams) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedUsers, arg.Cutoff, arg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreUser = `-- name: RestoreUser :execrows
+UPDATE users SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL AND deleted_at > $2
+`
+
+type RestoreUserParams struct {
+	ID     pgtype.UUID      `json:"id"`
+	Cutoff pgtype.Timestamp `json:"cutoff"`
+}
+
+// Só restaura dentro do período de carência (deleted_at posterior ao cutoff)
+func (q *Queries) RestoreUser(ctx context.Context, arg RestoreUserParams) (int64, error) {
+	result, err := q.db.Exec(ctx, restoreUser, arg.ID, arg.Cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const shadowBanUser = `-- name: ShadowBanUser :execrows
+UPDATE users SET shadow_banned_at = NOW() WHERE id = $1 AND shadow_banned_at IS NULL
+`
+
+func (q *Queries) ShadowBanUser(ctx context.Context, id pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, shadowBanUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const softDeleteUser = `-- name: SoftDeleteUser :execrows
+UPDATE users SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) SoftDeleteUser(ctx context.Context, id pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, softDeleteUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updatePassword = `-- name: UpdatePassword :execrows
+UPDATE users SET password_hash = $2
+WHERE id = $1 AND email = $3 AND deleted_at IS NULL
+`
+
+type UpdatePasswordParams struct {
+	ID           pgtype.UUID `json:"id"`
+	PasswordHash string      `json:"password_hash"`
+	Email        string      `json:"email"`
+}
+
+// 0 linhas = o email do usuário mudou depois do envio do link (ou conta removida)
+func (q *Queries) UpdatePassword(ctx context.Context, arg UpdatePasswordParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updatePassword, arg.ID, arg.PasswordHash, arg.Email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateUsername = `-- name: UpdateUsername :exec
+UPDATE users SET username = $2, username_changed_at = NOW() WHERE id = $1
+`
+
+type UpdateUsernameParams struct {
+	ID       pgtype.UUID `json:"id"`
+	Username string      `json:"username"`
+}
+
+func (q *Queries) UpdateUsername(ctx context.Context, arg UpdateUsernameParams) error {
+	_, err := q.db.Exec(ctx, updateUsername, arg.ID, arg.Username)
+	return err
+}
@@ -0,0 +1,71 @@
+package reqctx
+
+import "context"
+
+// Chaves privadas para evitar colisão com outros pacotes
+type ctxKey int
+
+const (
+	requestIDKey ctxKey = iota
+	tenantIDKey
+	userIDKey
+	scopesKey
+	systemKey
+)
+
+// WithRequestID adiciona o ID da requisição ao contexto
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestID retorna o ID da requisição ("" se ausente)
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// WithTenantID adiciona o tenant ao contexto
+func WithTenantID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantIDKey, id)
+}
+
+// TenantID retorna o tenant ("" se ausente)
+func TenantID(ctx context.Context) string {
+	id, _ := ctx.Value(tenantIDKey).(string)
+	return id
+}
+
+// WithUserID adiciona o usuário autenticado (ator) ao contexto
+func WithUserID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, userIDKey, id)
+}
+
+// UserID retorna o usuário autenticado ("" se ausente)
+func UserID(ctx context.Context) string {
+	id, _ := ctx.Value(userIDKey).(string)
+	return id
+}
+
+// WithScopes marca a requisição como autenticada por chave de API com os escopos dados
+func WithScopes(ctx context.Context, scopes []string) context.Context {
+	return context.WithValue(ctx, scopesKey, scopes)
+}
+
+// Scopes retorna os escopos da chave de API; ok=false para usuários (JWT),
+// que não são limitados por escopo
+func Scopes(ctx context.Context) (scopes []string, ok bool) {
+	scopes, ok = ctx.Value(scopesKey).([]string)
+	return scopes, ok
+}
+
+// WithSystem marca chamada interna (worker, usuário support/system, link
+// compartilhado): age em nome de outro usuário sem ser o ator autenticado
+func WithSystem(ctx context.Context) context.Context {
+	return context.WithValue(ctx, systemKey, true)
+}
+
+// IsSystem indica chamada interna marcada com WithSystem
+func IsSystem(ctx context.Context) bool {
+	system, _ := ctx.Value(systemKey).(bool)
+	return system
+}
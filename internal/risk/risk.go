@@ -0,0 +1,122 @@
+package risk
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Pesos de cada sinal no score (0-100)
+const (
+	weightBadIP          = 50 // IP em faixa de má reputação
+	weightNewDevice      = 25 // Dispositivo nunca visto
+	weightNewCountry     = 25 // País nunca visto
+	weightImpossibleTrip = 40 // País diferente do último login dentro da janela
+	weightUserFailure    = 10 // Por falha recente de senha do usuário
+	maxUserFailures      = 30
+	weightIPVelocity     = 20 // Muitas falhas recentes vindas do mesmo IP
+	ipVelocityThreshold  = 10
+)
+
+// Motivos retornados na avaliação (gravados no log de auditoria)
+const (
+	ReasonBadIP          = "bad_ip"
+	ReasonNewDevice      = "new_device"
+	ReasonNewCountry     = "new_country"
+	ReasonImpossibleTrip = "impossible_travel"
+	ReasonUserFailures   = "user_failures"
+	ReasonIPVelocity     = "ip_velocity"
+)
+
+// Signals sinais coletados para um login
+type Signals struct {
+	IP             string
+	NewDevice      bool
+	NewCountry     bool
+	CountryChanged bool // Último login recente veio de outro país
+	UserFailures   int  // Falhas de senha do usuário na janela
+	IPFailures     int  // Falhas de senha do IP (qualquer usuário) na janela
+}
+
+// Assessment resultado da avaliação de risco
+type Assessment struct {
+	Score   int      `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+// Engine avalia o risco de logins
+type Engine struct {
+	badNets []*net.IPNet
+}
+
+// NewEngine cria engine com as faixas de IP de má reputação (CIDR ou IP)
+func NewEngine(badIPs []string) (*Engine, error) {
+	e := &Engine{}
+	for _, entry := range badIPs {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			if strings.Contains(entry, ":") {
+				entry += "/128"
+			} else {
+				entry += "/32"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("faixa de IP inválida %q: %w", entry, err)
+		}
+		e.badNets = append(e.badNets, ipNet)
+	}
+	return e, nil
+}
+
+// BadIP verifica se o IP está em faixa de má reputação
+func (e *Engine) BadIP(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, n := range e.badNets {
+		if n.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// Assess calcula o score (limitado a 100) e os motivos
+func (e *Engine) Assess(s Signals) Assessment {
+	a := Assessment{Reasons: []string{}}
+
+	if e.BadIP(s.IP) {
+		a.add(weightBadIP, ReasonBadIP)
+	}
+	if s.NewDevice {
+		a.add(weightNewDevice, ReasonNewDevice)
+	}
+	if s.NewCountry {
+		a.add(weightNewCountry, ReasonNewCountry)
+	}
+	if s.CountryChanged {
+		a.add(weightImpossibleTrip, ReasonImpossibleTrip)
+	}
+	if s.UserFailures > 0 {
+		a.add(min(s.UserFailures*weightUserFailure, maxUserFailures), ReasonUserFailures)
+	}
+	if s.IPFailures >= ipVelocityThreshold {
+		a.add(weightIPVelocity, ReasonIPVelocity)
+	}
+
+	if a.Score > 100 {
+		a.Score = 100
+	}
+	return a
+}
+
+func (a *Assessment) add(weight int, reason string) {
+	a.Score += weight
+	a.Reasons = append(a.Reasons, reason)
+}
@@ -0,0 +1,232 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// monthLayout sufixo dos índices mensais (prefixo-2006.01)
+const monthLayout = "2006.01"
+
+// Document mensagem espelhada no índice
+type Document struct {
+	ID         string
+	SenderID   string
+	ReceiverID string
+	Content    string
+	CreatedAt  time.Time
+}
+
+// Index backend externo de busca de mensagens
+// As mensagens ficam em índices mensais para que a retenção apague índices
+// inteiros em vez de documentos
+type Index interface {
+	Enabled() bool
+	// EnsureTemplate cria/atualiza o template aplicado aos índices mensais
+	EnsureTemplate(ctx context.Context) error
+	// IndexMessage grava o documento (idempotente: reentregas sobrescrevem)
+	IndexMessage(ctx context.Context, doc Document) error
+	// SearchMessages retorna IDs das mensagens do usuário por relevância
+	SearchMessages(ctx context.Context, userID, query string, limit, offset int) ([]string, error)
+	// DeleteIndicesBefore apaga índices de meses inteiramente anteriores ao cutoff
+	DeleteIndicesBefore(ctx context.Context, cutoff time.Time) ([]string, error)
+}
+
+// New cria cliente Elasticsearch/OpenSearch se a URL estiver configurada,
+// senão um índice vazio (busca fica no Postgres)
+func New(baseURL, prefix, username, password string, timeout time.Duration) Index {
+	if baseURL == "" {
+		return NoopIndex{}
+	}
+	return &Elastic{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		prefix:   prefix,
+		username: username,
+		password: password,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// NoopIndex não indexa (busca externa desabilitada)
+type NoopIndex struct{}
+
+// Enabled implementa Index
+func (NoopIndex) Enabled() bool { return false }
+
+// EnsureTemplate implementa Index
+func (NoopIndex) EnsureTemplate(ctx context.Context) error { return nil }
+
+// IndexMessage implementa Index
+func (NoopIndex) IndexMessage(ctx context.Context, doc Document) error { return nil }
+
+// SearchMessages implementa Index
+func (NoopIndex) SearchMessages(ctx context.Context, userID, query string, limit, offset int) ([]string, error) {
+	return nil, nil
+}
+
+// DeleteIndicesBefore implementa Index
+func (NoopIndex) DeleteIndicesBefore(ctx context.Context, cutoff time.Time) ([]string, error) {
+	return nil, nil
+}
+
+// Elastic cliente HTTP compatível com Elasticsearch 7.8+ e OpenSearch
+type Elastic struct {
+	baseURL  string
+	prefix   string
+	username string
+	password string
+	client   *http.Client
+}
+
+// Enabled implementa Index
+func (e *Elastic) Enabled() bool { return true }
+
+// EnsureTemplate implementa Index
+func (e *Elastic) EnsureTemplate(ctx context.Context) error {
+	template := map[string]interface{}{
+		"index_patterns": []string{e.prefix + "-*"},
+		"template": map[string]interface{}{
+			"mappings": map[string]interface{}{
+				"dynamic": "strict",
+				"properties": map[string]interface{}{
+					"sender_id":    map[string]string{"type": "keyword"},
+					"receiver_id":  map[string]string{"type": "keyword"},
+					"participants": map[string]string{"type": "keyword"},
+					"content":      map[string]string{"type": "text"},
+					"created_at":   map[string]string{"type": "date"},
+				},
+			},
+		},
+	}
+	return e.do(ctx, http.MethodPut, "/_index_template/"+e.prefix, template, nil)
+}
+
+// IndexMessage implementa Index
+func (e *Elastic) IndexMessage(ctx context.Context, doc Document) error {
+	index := e.prefix + "-" + doc.CreatedAt.UTC().Format(monthLayout)
+	body := map[string]interface{}{
+		"sender_id":    doc.SenderID,
+		"receiver_id":  doc.ReceiverID,
+		"participants": []string{doc.SenderID, doc.ReceiverID},
+		"content":      doc.Content,
+		"created_at":   doc.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	return e.do(ctx, http.MethodPut, "/"+index+"/_doc/"+url.PathEscape(doc.ID), body, nil)
+}
+
+// SearchMessages implementa Index
+func (e *Elastic) SearchMessages(ctx context.Context, userID, query string, limit, offset int) ([]string, error) {
+	body := map[string]interface{}{
+		"from":    offset,
+		"size":    limit,
+		"_source": false,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []interface{}{
+					map[string]interface{}{"term": map[string]string{"participants": userID}},
+				},
+				"must": []interface{}{
+					map[string]interface{}{"simple_query_string": map[string]interface{}{
+						"query":            query,
+						"fields":           []string{"content"},
+						"default_operator": "and",
+					}},
+				},
+			},
+		},
+		"sort": []interface{}{"_score", map[string]string{"created_at": "desc"}},
+	}
+
+	var result struct {
+		Hits struct {
+			Hits []struct {
+				ID string `json:"_id"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := e.do(ctx, http.MethodPost, "/"+e.prefix+"-*/_search?ignore_unavailable=true", body, &result); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(result.Hits.Hits))
+	for i, hit := range result.Hits.Hits {
+		ids[i] = hit.ID
+	}
+	return ids, nil
+}
+
+// DeleteIndicesBefore implementa Index
+func (e *Elastic) DeleteIndicesBefore(ctx context.Context, cutoff time.Time) ([]string, error) {
+	var indices []struct {
+		Index string `json:"index"`
+	}
+	if err := e.do(ctx, http.MethodGet, "/_cat/indices/"+e.prefix+"-*?format=json&h=index", nil, &indices); err != nil {
+		return nil, err
+	}
+
+	var deleted []string
+	for _, idx := range indices {
+		month, err := time.Parse(monthLayout, strings.TrimPrefix(idx.Index, e.prefix+"-"))
+		if err != nil {
+			continue // Não é um índice mensal deste prefixo
+		}
+		// Só apaga quando o mês inteiro já passou do cutoff
+		if !month.AddDate(0, 1, 0).Before(cutoff) {
+			continue
+		}
+		if err := e.do(ctx, http.MethodDelete, "/"+idx.Index, nil, nil); err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, idx.Index)
+	}
+	return deleted, nil
+}
+
+// do envia requisição JSON e decodifica a resposta em out (se não for nil)
+func (e *Elastic) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, e.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição de busca: %w", err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if e.username != "" {
+		req.SetBasicAuth(e.username, e.password)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao consultar índice de busca: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return fmt.Errorf("índice de busca retornou status %d: %s", resp.StatusCode, detail)
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("resposta do índice de busca inválida: %w", err)
+	}
+	return nil
+}
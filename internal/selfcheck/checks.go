@@ -0,0 +1,158 @@
+package selfcheck
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/database"
+	"chat-kafka-go/internal/kafka"
+)
+
+const (
+	minSecretBytes    = 32 // HS256: chave com pelo menos o tamanho do hash
+	minSecretDistinct = 10 // Caracteres distintos; menos que isso sugere "aaaa..." ou "1234..."
+
+	clockWarnSkew     = 2 * time.Second
+	clockCriticalSkew = 30 * time.Second // Tokens emitidos aqui valem errado nas outras instâncias
+)
+
+// minSaneTime relógio antes disso não foi sincronizado (NTP)
+var minSaneTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// exampleSecrets valores do example.env, que não podem ir para produção
+var exampleSecrets = []string{
+	"meu-super-secret-access-12345678",
+	"meu-super-secret-refresh-87654321",
+}
+
+// Schema compara a versão do schema no banco com as migrações do binário
+func Schema(db *database.DB) Check {
+	return func(ctx context.Context) Result {
+		status, err := db.SchemaStatus(ctx)
+		if err != nil {
+			// Banco fora (STARTUP_DB_MODE=degraded): /readyz já informa
+			return Result{Status: StatusWarn, Detail: fmt.Sprintf("não foi possível verificar: %v", err)}
+		}
+		if len(status.Missing) > 0 {
+			return Result{
+				Status: StatusCritical,
+				Detail: fmt.Sprintf("schema na versão %03d, esperado %03d: aplique as migrações", status.Current, status.Expected),
+				Issues: status.Missing,
+			}
+		}
+		return Result{Status: StatusOK, Detail: fmt.Sprintf("versão %03d", status.Expected)}
+	}
+}
+
+// Topics confere se os tópicos obrigatórios existem no Kafka
+func Topics(cfg *config.KafkaConfig, topics ...string) Check {
+	return func(ctx context.Context) Result {
+		report, err := kafka.CheckTopics(cfg, topics)
+		if err != nil {
+			return Result{Status: StatusWarn, Detail: fmt.Sprintf("não foi possível verificar: %v", err)}
+		}
+		if len(report.Missing) == 0 {
+			return Result{Status: StatusOK, Detail: strings.Join(topics, ", ")}
+		}
+		if report.AutoCreate {
+			return Result{
+				Status: StatusWarn,
+				Detail: "tópicos ausentes serão criados automaticamente com as partições padrão do broker",
+				Issues: report.Missing,
+			}
+		}
+		return Result{Status: StatusCritical, Detail: "tópicos ausentes", Issues: report.Missing}
+	}
+}
+
+// JWTSecrets verifica tamanho e variedade dos segredos dos tokens
+func JWTSecrets(cfg *config.JWTConfig) Check {
+	return func(ctx context.Context) Result {
+		var critical, warn []string
+		for _, secret := range []struct{ env, value string }{
+			{"JWT_ACCESS_SECRET", cfg.AccessSecret},
+			{"JWT_REFRESH_SECRET", cfg.RefreshSecret},
+		} {
+			switch {
+			case isExampleSecret(secret.value):
+				critical = append(critical, secret.env+" é o valor do example.env")
+			case len(secret.value) < minSecretBytes:
+				critical = append(critical, fmt.Sprintf("%s com %d bytes (mínimo %d)", secret.env, len(secret.value), minSecretBytes))
+			case distinctChars(secret.value) < minSecretDistinct:
+				warn = append(warn, secret.env+" com pouca variedade de caracteres")
+			}
+		}
+		if cfg.AccessSecret == cfg.RefreshSecret {
+			critical = append(critical, "JWT_ACCESS_SECRET e JWT_REFRESH_SECRET iguais: refresh token aceito como access token")
+		}
+
+		switch {
+		case len(critical) > 0:
+			return Result{Status: StatusCritical, Detail: "segredos fracos", Issues: append(critical, warn...)}
+		case len(warn) > 0:
+			return Result{Status: StatusWarn, Detail: "segredos fracos", Issues: warn}
+		}
+		return Result{Status: StatusOK, Detail: "segredos fortes e distintos"}
+	}
+}
+
+// Clock compara o relógio local com o do Postgres
+func Clock(db *database.DB) Check {
+	return func(ctx context.Context) Result {
+		local := time.Now()
+		if local.Before(minSaneTime) {
+			return Result{Status: StatusCritical, Detail: fmt.Sprintf("relógio local em %s: não sincronizado", local.UTC().Format(time.RFC3339))}
+		}
+
+		var remote time.Time
+		if err := db.Pool.QueryRow(ctx, "SELECT NOW()").Scan(&remote); err != nil {
+			return Result{Status: StatusWarn, Detail: fmt.Sprintf("não foi possível comparar com o banco: %v", err)}
+		}
+		// NOW() é lido no meio da ida e volta
+		rtt := time.Since(local)
+		skew := local.Add(rtt / 2).Sub(remote).Round(time.Millisecond)
+		if skew < 0 {
+			skew = -skew
+		}
+
+		detail := fmt.Sprintf("diferença de %s para o banco", skew)
+		switch {
+		case skew >= clockCriticalSkew:
+			return Result{Status: StatusCritical, Detail: detail}
+		case skew >= clockWarnSkew:
+			return Result{Status: StatusWarn, Detail: detail}
+		}
+		return Result{Status: StatusOK, Detail: detail}
+	}
+}
+
+// ConfigWarnings avisos de configuração (nunca críticos: Validate já barrou os erros)
+func ConfigWarnings(cfg *config.Config) Check {
+	return func(ctx context.Context) Result {
+		warnings := cfg.Warnings()
+		if len(warnings) == 0 {
+			return Result{Status: StatusOK, Detail: "sem avisos"}
+		}
+		return Result{Status: StatusWarn, Detail: fmt.Sprintf("%d aviso(s)", len(warnings)), Issues: warnings}
+	}
+}
+
+func isExampleSecret(secret string) bool {
+	for _, example := range exampleSecrets {
+		if secret == example {
+			return true
+		}
+	}
+	return false
+}
+
+func distinctChars(s string) int {
+	seen := make(map[rune]bool)
+	for _, r := range s {
+		seen[r] = true
+	}
+	return len(seen)
+}
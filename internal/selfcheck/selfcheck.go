@@ -0,0 +1,112 @@
+// Package selfcheck verificações do boot (schema, tópicos, segredos, relógio,
+// avisos de config): relatório logado na subida e em GET /admin/selfcheck
+package selfcheck
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Status resultado de uma verificação (o pior define o do relatório)
+type Status string
+
+const (
+	StatusOK       Status = "ok"
+	StatusWarn     Status = "warn"     // Funciona, mas merece atenção
+	StatusCritical Status = "critical" // Encerra o boot com STARTUP_SELFCHECK_STRICT
+)
+
+// checkTimeout prazo de cada verificação (banco e Kafka podem não responder)
+const checkTimeout = 5 * time.Second
+
+// Result resultado de uma verificação
+type Result struct {
+	Name   string   `json:"name"`
+	Status Status   `json:"status"`
+	Detail string   `json:"detail"`
+	Issues []string `json:"issues,omitempty"` // Um item por problema (tabelas ausentes, avisos...)
+}
+
+// Report relatório completo do self-check
+type Report struct {
+	Status Status    `json:"status"`
+	RanAt  time.Time `json:"ran_at"`
+	Checks []Result  `json:"checks"`
+}
+
+// Check verificação; o nome é preenchido pelo Runner
+type Check func(ctx context.Context) Result
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Runner executa as verificações em ordem (boot e GET /admin/selfcheck)
+type Runner struct {
+	checks []namedCheck
+}
+
+// New cria runner sem verificações
+func New() *Runner {
+	return &Runner{}
+}
+
+// Add registra uma verificação
+func (r *Runner) Add(name string, check Check) {
+	r.checks = append(r.checks, namedCheck{name: name, check: check})
+}
+
+// Run executa todas as verificações, cada uma com prazo próprio
+func (r *Runner) Run(ctx context.Context) *Report {
+	report := &Report{Status: StatusOK, RanAt: time.Now().UTC(), Checks: make([]Result, 0, len(r.checks))}
+	for _, c := range r.checks {
+		checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+		result := c.check(checkCtx)
+		cancel()
+
+		result.Name = c.name
+		report.Checks = append(report.Checks, result)
+		if severity(result.Status) > severity(report.Status) {
+			report.Status = result.Status
+		}
+	}
+	return report
+}
+
+// Critical indica problema que impede o boot
+func (r *Report) Critical() bool {
+	return r.Status == StatusCritical
+}
+
+// Log registra uma linha estruturada por verificação e por problema
+func (r *Report) Log() {
+	for _, result := range r.Checks {
+		level := slog.LevelInfo
+		switch result.Status {
+		case StatusWarn:
+			level = slog.LevelWarn
+		case StatusCritical:
+			level = slog.LevelError
+		}
+		slog.Log(context.Background(), level, "Self-check",
+			"check", result.Name, "status", result.Status, "detail", result.Detail)
+		for _, issue := range result.Issues {
+			slog.Log(context.Background(), level, "Self-check",
+				"check", result.Name, "status", result.Status, "issue", issue)
+		}
+	}
+	slog.Info("Self-check concluído", "status", r.Status, "checks", len(r.Checks))
+}
+
+func severity(s Status) int {
+	switch s {
+	case StatusCritical:
+		return 2
+	case StatusWarn:
+		return 1
+	default:
+		return 0
+	}
+}
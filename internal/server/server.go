@@ -0,0 +1,240 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/handler"
+	"chat-kafka-go/internal/maintenance"
+	"chat-kafka-go/internal/middleware"
+	"chat-kafka-go/internal/service"
+)
+
+// Handlers handlers HTTP da API pública
+type Handlers struct {
+	Auth          *handler.AuthHandler
+	Users         *handler.UserHandler
+	Contacts      *handler.ContactHandler
+	Invitations   *handler.InvitationHandler
+	Notifications *handler.NotificationHandler
+	Announcements *handler.AnnouncementHandler
+	WS            *handler.WSHandler
+	Messages      *handler.MessageHandler
+	ShareLinks    *handler.ShareLinkHandler
+	Support       *handler.SupportHandler
+	SupportAccess *handler.SupportAccessHandler
+	Attachments   *handler.AttachmentHandler
+	Uploads       *handler.TusHandler
+	Search        *handler.SearchHandler
+	Billing       *handler.BillingHandler
+	AutoReplies   *handler.AutoReplyHandler
+	Preferences   *handler.PreferencesHandler
+	Privacy       *handler.PrivacyHandler
+	DND           *handler.DNDHandler
+	Health        *handler.HealthHandler
+
+	// APIKeys valida chaves de API aceitas nas rotas com escopo
+	APIKeys middleware.APIKeyValidator
+
+	// Impersonations valida tokens do suporte (somente leitura) nas rotas com escopo
+	Impersonations middleware.ImpersonationValidator
+
+	// Maintenance modo de manutenção (503 em escritas ou em toda a API)
+	Maintenance *maintenance.Switch
+}
+
+// New cria o servidor HTTP da API
+func New(cfg *config.Config, h Handlers) *http.Server {
+	return &http.Server{
+		Addr:         ":" + cfg.Server.Port,
+		Handler:      NewRouter(cfg, h),
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+	}
+}
+
+// NewRouter registra rotas e middlewares globais
+func NewRouter(cfg *config.Config, h Handlers) http.Handler {
+	mux := http.NewServeMux()
+	auth := middleware.Auth(&cfg.JWT)
+	scoped := func(scope string, fn http.HandlerFunc) http.Handler {
+		return middleware.Scoped(&cfg.JWT, h.APIKeys, h.Impersonations, scope)(fn)
+	}
+
+	// Sondas (liveness e readiness, com estado degradado do barramento)
+	mux.HandleFunc("GET /healthz", h.Health.Live)
+	mux.HandleFunc("GET /readyz", h.Health.Ready)
+
+	// Autenticação
+	mux.HandleFunc("POST /auth/register", h.Auth.Register)
+	mux.HandleFunc("POST /auth/login", h.Auth.Login)
+	mux.HandleFunc("POST /auth/login/verify", h.Auth.VerifyLogin)
+	mux.HandleFunc("POST /auth/refresh", h.Auth.Refresh)
+	mux.HandleFunc("POST /auth/logout", h.Auth.Logout)
+	mux.HandleFunc("POST /auth/not-me", h.Auth.NotMe)
+	mux.HandleFunc("POST /auth/verify-email", h.Auth.VerifyEmail)
+	mux.HandleFunc("POST /auth/verify-email/resend", h.Auth.ResendVerification)
+	mux.HandleFunc("POST /auth/forgot-password", h.Auth.ForgotPassword)
+	mux.HandleFunc("POST /auth/reset-password", h.Auth.ResetPassword)
+	mux.Handle("POST /auth/introspect", middleware.APIKey(h.APIKeys, service.ScopeTokensIntrospect)(http.HandlerFunc(h.Auth.Introspect)))
+
+	// Usuários
+	mux.Handle("GET /users", scoped(service.ScopeUsersRead, h.Users.Lookup))
+	mux.Handle("GET /users/me", scoped(service.ScopeUsersRead, h.Users.Me))
+	mux.Handle("DELETE /users/me", auth(http.HandlerFunc(h.Users.Delete)))
+	mux.Handle("PATCH /users/me/username", auth(http.HandlerFunc(h.Users.ChangeUsername)))
+	mux.Handle("GET /users/me/usage", scoped(service.ScopeUsersRead, h.Users.Usage))
+	mux.Handle("GET /users/me/plan", scoped(service.ScopeUsersRead, h.Users.Plan))
+	mux.Handle("GET /users/me/auto-reply", auth(http.HandlerFunc(h.AutoReplies.Get)))
+	mux.Handle("PUT /users/me/auto-reply", auth(http.HandlerFunc(h.AutoReplies.Update)))
+	mux.Handle("DELETE /users/me/auto-reply", auth(http.HandlerFunc(h.AutoReplies.Disable)))
+	mux.Handle("GET /users/me/preferences", auth(http.HandlerFunc(h.Preferences.Get)))
+	mux.Handle("PATCH /users/me/preferences", auth(http.HandlerFunc(h.Preferences.Update)))
+	mux.Handle("GET /users/me/privacy", auth(http.HandlerFunc(h.Privacy.Get)))
+	mux.Handle("PUT /users/me/privacy", auth(http.HandlerFunc(h.Privacy.Update)))
+	mux.Handle("GET /users/me/dnd", auth(http.HandlerFunc(h.DND.Get)))
+	mux.Handle("PUT /users/me/dnd", auth(http.HandlerFunc(h.DND.Update)))
+	mux.Handle("POST /users/me/dnd/snooze", auth(http.HandlerFunc(h.DND.Snooze)))
+	mux.Handle("GET /users/me/support-access", auth(http.HandlerFunc(h.SupportAccess.Get)))
+	mux.Handle("PUT /users/me/support-access", auth(http.HandlerFunc(h.SupportAccess.Grant)))
+	mux.Handle("DELETE /users/me/support-access", auth(http.HandlerFunc(h.SupportAccess.Revoke)))
+	mux.Handle("GET /users/{id}", scoped(service.ScopeUsersRead, h.Users.Get))
+	mux.Handle("GET /users/{id}/presence", scoped(service.ScopeUsersRead, h.Users.Presence))
+
+	// Mensagens
+	mux.Handle("POST /messages", scoped(service.ScopeMessagesSend, h.Messages.Send))
+	mux.Handle("GET /messages/{peerID}", scoped(service.ScopeMessagesRead, h.Messages.History))
+	mux.Handle("POST /messages/{id}/delivered", scoped(service.ScopeMessagesRead, h.Messages.Delivered))
+	mux.Handle("POST /messages/{id}/read", scoped(service.ScopeMessagesRead, h.Messages.Read))
+	mux.Handle("GET /conversations", scoped(service.ScopeMessagesRead, h.Messages.Conversations))
+	mux.Handle("GET /conversations/{id}/members", scoped(service.ScopeMessagesRead, h.Users.ConversationMembers))
+
+	// Links somente leitura de conversa (o token só vale em /shared/{token}/messages)
+	mux.Handle("POST /conversations/{id}/share-links", auth(http.HandlerFunc(h.ShareLinks.Create)))
+	mux.Handle("GET /conversations/{id}/share-links", auth(http.HandlerFunc(h.ShareLinks.List)))
+	mux.Handle("DELETE /conversations/{id}/share-links/{linkID}", auth(http.HandlerFunc(h.ShareLinks.Revoke)))
+	mux.HandleFunc("GET /shared/{token}/messages", h.ShareLinks.History)
+
+	// Busca global
+	mux.Handle("GET /search", auth(http.HandlerFunc(h.Search.Search)))
+
+	// Anexos (cria, envia conteúdo, finaliza; varredura antivírus em background)
+	mux.Handle("POST /attachments", auth(http.HandlerFunc(h.Attachments.Create)))
+	mux.Handle("PUT /attachments/{id}/content", auth(http.HandlerFunc(h.Attachments.Upload)))
+	mux.Handle("POST /attachments/{id}/finalize", auth(http.HandlerFunc(h.Attachments.Finalize)))
+	mux.Handle("GET /attachments/{id}", auth(http.HandlerFunc(h.Attachments.Get)))
+	mux.Handle("GET /attachments/{id}/content", auth(http.HandlerFunc(h.Attachments.Download)))
+	mux.Handle("POST /attachments/{id}/download-url", auth(http.HandlerFunc(h.Attachments.DownloadURL)))
+	mux.HandleFunc("GET /files/{id}/{variant}", h.Attachments.Signed) // URL assinada (CDN)
+
+	// Upload retomável (protocolo tus); o anexo criado segue o mesmo pipeline
+	mux.HandleFunc("OPTIONS /uploads", h.Uploads.Options)
+	mux.Handle("POST /uploads", auth(http.HandlerFunc(h.Uploads.Create)))
+	mux.Handle("HEAD /uploads/{id}", auth(http.HandlerFunc(h.Uploads.Head)))
+	mux.Handle("PATCH /uploads/{id}", auth(http.HandlerFunc(h.Uploads.Patch)))
+	mux.Handle("DELETE /uploads/{id}", auth(http.HandlerFunc(h.Uploads.Delete)))
+
+	// Convites
+	mux.Handle("POST /invitations", auth(http.HandlerFunc(h.Invitations.Create)))
+
+	// Notificações
+	mux.Handle("GET /notifications", auth(http.HandlerFunc(h.Notifications.List)))
+	mux.Handle("POST /notifications/{id}/read", auth(http.HandlerFunc(h.Notifications.MarkRead)))
+
+	// Anúncios do sistema (confirmação de leitura dos que exigem)
+	mux.Handle("GET /announcements/pending", auth(http.HandlerFunc(h.Announcements.Pending)))
+	mux.Handle("POST /announcements/{id}/ack", auth(http.HandlerFunc(h.Announcements.Acknowledge)))
+
+	// WebSocket (ticket de uso único no lugar do JWT na URL)
+	mux.Handle("POST /ws/ticket", auth(http.HandlerFunc(h.WS.Ticket)))
+	mux.HandleFunc("GET /ws", h.WS.Connect)
+
+	// Caixa de suporte: cliente abre chamado; agentes assumem, anotam e respondem
+	mux.Handle("POST /support/tickets", auth(http.HandlerFunc(h.Support.Open)))
+	mux.Handle("GET /support/tickets/mine", auth(http.HandlerFunc(h.Support.Mine)))
+	mux.Handle("GET /support/tickets", auth(http.HandlerFunc(h.Support.Queue)))
+	mux.Handle("GET /support/tickets/{id}", auth(http.HandlerFunc(h.Support.Get)))
+	mux.Handle("POST /support/tickets/{id}/claim", auth(http.HandlerFunc(h.Support.Claim)))
+	mux.Handle("PUT /support/tickets/{id}/status", auth(http.HandlerFunc(h.Support.SetStatus)))
+	mux.Handle("POST /support/tickets/{id}/notes", auth(http.HandlerFunc(h.Support.AddNote)))
+	mux.Handle("GET /support/tickets/{id}/messages", auth(http.HandlerFunc(h.Support.Messages)))
+	mux.Handle("POST /support/tickets/{id}/reply", auth(http.HandlerFunc(h.Support.Reply)))
+
+	// Contatos
+	mux.Handle("POST /contacts/sync", auth(http.HandlerFunc(h.Contacts.Sync)))
+
+	// Cobrança (assinatura do provedor no lugar do JWT)
+	mux.HandleFunc("POST /billing/webhook", h.Billing.Webhook)
+
+	// Lote de leituras (cada item autentica na própria rota)
+	batch := handler.NewBatchHandler(mux, cfg.Server.BatchMaxRequests, cfg.Server.BatchConcurrency)
+	mux.HandleFunc("POST /batch", batch.Batch)
+
+	// Middlewares globais (o primeiro da lista é o mais externo)
+	return chain(mux,
+		middleware.RealIP(&cfg.Server),
+		middleware.RequestID,
+		middleware.SlowRequests(cfg.Server.SlowRequest),
+		middleware.Recovery,
+		middleware.Maintenance(h.Maintenance),
+		middleware.RouteLimits(routeLimits(cfg)),
+		compression(cfg),
+	)
+}
+
+// compression gzip nas rotas JSON; WebSocket e prefixos de
+// SERVER_COMPRESSION_EXCLUDE ficam de fora
+func compression(cfg *config.Config) func(http.Handler) http.Handler {
+	if !cfg.Server.Compression {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	exclude := cfg.Server.CompressionExclude
+	return middleware.Compress(middleware.CompressOptions{
+		MinBytes: cfg.Server.CompressionMinBytes,
+		Types:    cfg.Server.CompressionTypes,
+		Skip: func(r *http.Request) bool {
+			if r.URL.Path == "/ws" {
+				return true
+			}
+			for _, prefix := range exclude {
+				if strings.HasPrefix(r.URL.Path, prefix) {
+					return true
+				}
+			}
+			return false
+		},
+	})
+}
+
+// routeLimits classe de limites de cada rota: /auth/* (corpo pequeno),
+// conteúdo de anexos (prazo longo, corpo até ATTACHMENT_MAX_BYTES) e demais.
+// O WebSocket fica sem prazo (conexão longa)
+func routeLimits(cfg *config.Config) func(r *http.Request) middleware.Limits {
+	auth := middleware.Limits{Timeout: cfg.Server.RequestTimeout, MaxBody: cfg.Server.AuthBodyBytes}
+	transfer := middleware.Limits{Timeout: cfg.Server.TransferTimeout, MaxBody: cfg.Storage.MaxAttachmentBytes}
+	api := middleware.Limits{Timeout: cfg.Server.RequestTimeout, MaxBody: cfg.Server.DefaultBodyBytes}
+
+	return func(r *http.Request) middleware.Limits {
+		path := r.URL.Path
+		switch {
+		case path == "/ws":
+			return middleware.Limits{}
+		case strings.HasPrefix(path, "/auth/"):
+			return auth
+		case strings.HasPrefix(path, "/attachments/") && strings.HasSuffix(path, "/content"),
+			strings.HasPrefix(path, "/uploads/"),
+			strings.HasPrefix(path, "/files/"):
+			return transfer
+		}
+		return api
+	}
+}
+
+// chain aplica middlewares na ordem em que aparecem
+func chain(h http.Handler, middlewares ...func(http.Handler) http.Handler) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+
+	"chat-kafka-go/internal/config"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// cipherSuites TLS 1.2 só com ECDHE e AEAD (TLS 1.3 não é configurável)
+var cipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+}
+
+// ConfigureTLS ativa HTTPS no servidor da API (certificado em arquivo ou
+// ACME). Com TLS_HTTP_PORT, retorna também o servidor HTTP que atende o
+// desafio ACME e redireciona o resto para HTTPS; nil sem TLS
+func ConfigureTLS(srv *http.Server, cfg *config.ServerConfig) (*http.Server, error) {
+	if !cfg.TLSEnabled() {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:       tls.VersionTLS12,
+		CipherSuites:     cipherSuites,
+		CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256},
+		NextProtos:       []string{"http/1.1"},
+	}
+	if cfg.TLSMinVersion == "1.3" {
+		tlsConfig.MinVersion = tls.VersionTLS13
+	}
+	if cfg.HTTP2 {
+		tlsConfig.NextProtos = []string{"h2", "http/1.1"}
+	} else {
+		// Mapa vazio desliga o HTTP/2 automático do net/http
+		srv.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+	}
+
+	redirect := redirectHTTPS(cfg.Port)
+	if len(cfg.TLSAutocert) > 0 {
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.TLSAutocert...),
+			Cache:      autocert.DirCache(cfg.TLSAutocertDir),
+			Email:      cfg.TLSAutocertMail,
+		}
+		tlsConfig.GetCertificate = manager.GetCertificate
+		// Desafio tls-alpn-01 na própria porta HTTPS; http-01 em TLS_HTTP_PORT
+		tlsConfig.NextProtos = append(tlsConfig.NextProtos, "acme-tls/1")
+		redirect = manager.HTTPHandler(redirect)
+	} else {
+		cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao carregar certificado TLS: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	srv.TLSConfig = tlsConfig
+
+	if cfg.TLSHTTPPort == "" {
+		return nil, nil
+	}
+	return &http.Server{
+		Addr:              ":" + cfg.TLSHTTPPort,
+		Handler:           redirect,
+		ReadHeaderTimeout: cfg.ReadTimeout,
+	}, nil
+}
+
+// ListenAndServe serve HTTPS se ConfigureTLS ativou TLS, senão HTTP
+func ListenAndServe(srv *http.Server) error {
+	if srv.TLSConfig != nil {
+		return srv.ListenAndServeTLS("", "")
+	}
+	return srv.ListenAndServe()
+}
+
+// redirectHTTPS redireciona GET/HEAD para o mesmo caminho na porta HTTPS
+func redirectHTTPS(port string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "use HTTPS", http.StatusBadRequest)
+			return
+		}
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusFound)
+	})
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"chat-kafka-go/internal/reqctx"
+)
+
+// ErrForbidden o usuário autenticado não é o dono da operação (remetente,
+// leitor ou quem pede/aceita a amizade)
+var ErrForbidden = errors.New("operação não permitida para o usuário autenticado")
+
+// authorize exige que o ator autenticado (reqctx.UserID, vindo das Claims do
+// JWT ou da chave de API) seja userID. Sem ator no contexto também nega:
+// chamadas internas que agem por outro usuário usam reqctx.WithSystem
+func authorize(ctx context.Context, userID string) error {
+	if reqctx.IsSystem(ctx) {
+		return nil
+	}
+	if actor := reqctx.UserID(ctx); actor == "" || actor != userID {
+		return ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+	"chat-kafka-go/pkg/validate"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// SystemUserID remetente dos anúncios (criado na migração 024)
+const SystemUserID = "00000000-0000-0000-0000-000000000001"
+
+// Estados de um anúncio
+const (
+	AnnouncementScheduled = "scheduled"
+	AnnouncementSending   = "sending"
+	AnnouncementSent      = "sent"
+	AnnouncementCanceled  = "canceled"
+)
+
+// ErrAnnouncementNotFound anúncio inexistente (ou não entregue ao usuário)
+var ErrAnnouncementNotFound = errors.New("anúncio não encontrado")
+
+// AnnouncementService anúncios do admin e confirmação de leitura pelos
+// usuários; o envio em lotes é do worker.AnnouncementDispatcher
+type AnnouncementService struct {
+	queries *repository.Queries
+	clock   clock.Clock // Agendamento "agora"
+}
+
+// NewAnnouncementService cria nova instância do service
+func NewAnnouncementService(queries *repository.Queries) *AnnouncementService {
+	return &AnnouncementService{
+		queries: queries,
+		clock:   clock.System,
+	}
+}
+
+// SetClock troca o relógio (testes)
+func (s *AnnouncementService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Create agenda anúncio para todos os usuários ou os de um plano
+func (s *AnnouncementService) Create(ctx context.Context, input types.CreateAnnouncementInput) (*types.AnnouncementResponse, error) {
+	input.Content = validate.Text(input.Content)
+	if input.Content == "" {
+		return nil, fmt.Errorf("conteúdo do anúncio é obrigatório")
+	}
+	if !validate.Content(input.Content) {
+		return nil, fmt.Errorf("anúncio muito longo (máximo %d caracteres)", validate.MaxContentLength)
+	}
+	var plan *string
+	if input.Plan != "" {
+		if !validPlan(input.Plan) {
+			return nil, fmt.Errorf("plano deve ser free, pro ou workspace")
+		}
+		plan = &input.Plan
+	}
+	scheduledAt := s.clock.Now().UTC()
+	if input.ScheduledAt != "" {
+		t, err := time.Parse(time.RFC3339, input.ScheduledAt)
+		if err != nil {
+			return nil, fmt.Errorf("scheduled_at inválido (RFC3339): %w", err)
+		}
+		scheduledAt = t.UTC()
+	}
+
+	announcement, err := s.queries.CreateAnnouncement(ctx, repository.CreateAnnouncementParams{
+		Content:     input.Content,
+		SegmentPlan: plan,
+		RequiresAck: input.RequiresAck,
+		ScheduledAt: pgtype.Timestamp{Time: scheduledAt, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar anúncio: %w", err)
+	}
+	return toAnnouncementResponse(announcement, repository.CountAnnouncementDeliveriesRow{}), nil
+}
+
+// List anúncios mais recentes primeiro, com o andamento da entrega
+func (s *AnnouncementService) List(ctx context.Context, page, perPage int) ([]types.AnnouncementResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	announcements, err := s.queries.ListAnnouncements(ctx, repository.ListAnnouncementsParams{
+		Limit:  int32(perPage),
+		Offset: int32((page - 1) * perPage),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar anúncios: %w", err)
+	}
+
+	resp := make([]types.AnnouncementResponse, 0, len(announcements))
+	for _, a := range announcements {
+		counts, err := s.queries.CountAnnouncementDeliveries(ctx, a.ID)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao contar entregas: %w", err)
+		}
+		resp = append(resp, *toAnnouncementResponse(a, counts))
+	}
+	return resp, nil
+}
+
+// Get anúncio com o andamento da entrega
+func (s *AnnouncementService) Get(ctx context.Context, id string) (*types.AnnouncementResponse, error) {
+	announcementUUID, err := utils.StringToUUID(id)
+	if err != nil {
+		return nil, fmt.Errorf("ID de anúncio inválido: %w", err)
+	}
+
+	announcement, err := s.queries.GetAnnouncement(ctx, announcementUUID)
+	if err == pgx.ErrNoRows {
+		return nil, ErrAnnouncementNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar anúncio: %w", err)
+	}
+	counts, err := s.queries.CountAnnouncementDeliveries(ctx, announcementUUID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao contar entregas: %w", err)
+	}
+	return toAnnouncementResponse(announcement, counts), nil
+}
+
+// Cancel cancela anúncio agendado ou interrompe o envio (lotes restantes)
+func (s *AnnouncementService) Cancel(ctx context.Context, id string) error {
+	announcementUUID, err := utils.StringToUUID(id)
+	if err != nil {
+		return fmt.Errorf("ID de anúncio inválido: %w", err)
+	}
+
+	rows, err := s.queries.CancelAnnouncement(ctx, announcementUUID)
+	if err != nil {
+		return fmt.Errorf("erro ao cancelar anúncio: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("%w ou já enviado", ErrAnnouncementNotFound)
+	}
+	return nil
+}
+
+// Pending anúncios entregues ao usuário que exigem confirmação ainda não dada
+func (s *AnnouncementService) Pending(ctx context.Context, userID string) ([]types.PendingAnnouncementResponse, error) {
+	userUUID, err := utils.StringToUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	rows, err := s.queries.ListPendingAnnouncements(ctx, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar anúncios: %w", err)
+	}
+
+	resp := make([]types.PendingAnnouncementResponse, 0, len(rows))
+	for _, row := range rows {
+		resp = append(resp, types.PendingAnnouncementResponse{
+			ID:          utils.UUIDToString(row.ID),
+			MessageID:   utils.UUIDToString(row.MessageID),
+			Content:     row.Content,
+			DeliveredAt: row.DeliveredAt.Time.Format(time.RFC3339),
+		})
+	}
+	return resp, nil
+}
+
+// Acknowledge confirma a leitura do anúncio (idempotente)
+func (s *AnnouncementService) Acknowledge(ctx context.Context, userID, announcementID string) error {
+	userUUID, err := utils.StringToUUID(userID)
+	if err != nil {
+		return fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+	announcementUUID, err := utils.StringToUUID(announcementID)
+	if err != nil {
+		return fmt.Errorf("ID de anúncio inválido: %w", err)
+	}
+
+	rows, err := s.queries.AcknowledgeAnnouncement(ctx, repository.AcknowledgeAnnouncementParams{
+		AnnouncementID: announcementUUID,
+		UserID:         userUUID,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao confirmar anúncio: %w", err)
+	}
+	if rows == 0 {
+		return ErrAnnouncementNotFound
+	}
+	return nil
+}
+
+// toAnnouncementResponse converte anúncio com as contagens de entrega
+func toAnnouncementResponse(a repository.Announcement, counts repository.CountAnnouncementDeliveriesRow) *types.AnnouncementResponse {
+	resp := &types.AnnouncementResponse{
+		ID:           utils.UUIDToString(a.ID),
+		Content:      a.Content,
+		RequiresAck:  a.RequiresAck,
+		ScheduledAt:  a.ScheduledAt.Time.Format(time.RFC3339),
+		Status:       a.Status,
+		Delivered:    int(counts.Delivered),
+		Acknowledged: int(counts.Acknowledged),
+		CreatedAt:    a.CreatedAt.Time.Format(time.RFC3339),
+	}
+	if a.SegmentPlan != nil {
+		resp.Plan = *a.SegmentPlan
+	}
+	if a.StartedAt.Valid {
+		resp.StartedAt = a.StartedAt.Time.Format(time.RFC3339)
+	}
+	if a.FinishedAt.Valid {
+		resp.FinishedAt = a.FinishedAt.Time.Format(time.RFC3339)
+	}
+	return resp
+}
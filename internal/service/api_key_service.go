@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+	"chat-kafka-go/pkg/validate"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Escopos das chaves de API
+const (
+	ScopeMessagesSend = "messages:send"
+	ScopeMessagesRead = "messages:read"
+	ScopeUsersRead    = "users:read"
+
+	// ScopeTokensIntrospect serviços internos validam tokens de usuários
+	// em POST /auth/introspect
+	ScopeTokensIntrospect = "tokens:introspect"
+)
+
+// validScopes escopos aceitos na criação
+var validScopes = []string{ScopeMessagesSend, ScopeMessagesRead, ScopeUsersRead, ScopeTokensIntrospect}
+
+// userScopes escopos que agem em nome de um usuário (exigem user_id)
+var userScopes = []string{ScopeMessagesSend, ScopeMessagesRead}
+
+// APIKeyService gerencia credenciais de máquina (integrações e bots)
+type APIKeyService struct {
+	queries *repository.Queries
+}
+
+// NewAPIKeyService cria nova instância do service
+func NewAPIKeyService(queries *repository.Queries) *APIKeyService {
+	return &APIKeyService{queries: queries}
+}
+
+// Create gera nova chave; a chave completa só é retornada aqui
+func (s *APIKeyService) Create(ctx context.Context, input types.CreateAPIKeyInput) (*types.APIKeyResponse, error) {
+	if !validate.Length(input.Name, 1, 100) {
+		return nil, fmt.Errorf("name é obrigatório (até 100 caracteres)")
+	}
+	if len(input.Scopes) == 0 {
+		return nil, fmt.Errorf("informe ao menos um escopo")
+	}
+
+	needsUser := false
+	for _, scope := range input.Scopes {
+		if !slices.Contains(validScopes, scope) {
+			return nil, fmt.Errorf("escopo desconhecido: %s", scope)
+		}
+		if slices.Contains(userScopes, scope) {
+			needsUser = true
+		}
+	}
+
+	var userUUID pgtype.UUID
+	if input.UserID != "" {
+		var err error
+		userUUID, err = utils.StringToUUID(input.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("user_id inválido: %w", err)
+		}
+		if _, err := s.queries.GetUserByID(ctx, userUUID); err != nil {
+			return nil, fmt.Errorf("usuário não encontrado")
+		}
+	} else if needsUser {
+		return nil, fmt.Errorf("escopos de mensagens exigem user_id")
+	}
+
+	secret, err := utils.GenerateSecureToken()
+	if err != nil {
+		return nil, err
+	}
+	key := utils.APIKeyPrefix + secret
+
+	apiKey, err := s.queries.CreateAPIKey(ctx, repository.CreateAPIKeyParams{
+		Name:    input.Name,
+		Prefix:  key[:len(utils.APIKeyPrefix)+8],
+		KeyHash: utils.HashToken(key),
+		Scopes:  input.Scopes,
+		UserID:  userUUID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar chave de API: %w", err)
+	}
+
+	resp := toAPIKeyResponse(apiKey)
+	resp.Key = key
+	return resp, nil
+}
+
+// List lista as chaves (sem o segredo)
+func (s *APIKeyService) List(ctx context.Context) ([]*types.APIKeyResponse, error) {
+	keys, err := s.queries.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar chaves de API: %w", err)
+	}
+
+	resp := make([]*types.APIKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		resp = append(resp, toAPIKeyResponse(k))
+	}
+	return resp, nil
+}
+
+// Revoke revoga a chave imediatamente
+func (s *APIKeyService) Revoke(ctx context.Context, id string) error {
+	keyUUID, err := utils.StringToUUID(id)
+	if err != nil {
+		return fmt.Errorf("ID inválido: %w", err)
+	}
+
+	rows, err := s.queries.RevokeAPIKey(ctx, keyUUID)
+	if err != nil {
+		return fmt.Errorf("erro ao revogar chave de API: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("chave não encontrada ou já revogada")
+	}
+	return nil
+}
+
+// ValidateAPIKey valida a chave e retorna usuário associado e escopos
+func (s *APIKeyService) ValidateAPIKey(ctx context.Context, key string) (string, []string, error) {
+	apiKey, err := s.queries.GetActiveAPIKeyByHash(ctx, utils.HashToken(key))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", nil, fmt.Errorf("chave de API inválida")
+		}
+		return "", nil, fmt.Errorf("erro ao buscar chave de API: %w", err)
+	}
+
+	if err := s.queries.TouchAPIKey(ctx, apiKey.ID); err != nil {
+		return "", nil, fmt.Errorf("erro ao atualizar chave de API: %w", err)
+	}
+
+	return utils.UUIDToString(apiKey.UserID), apiKey.Scopes, nil
+}
+
+func toAPIKeyResponse(k repository.ApiKey) *types.APIKeyResponse {
+	resp := &types.APIKeyResponse{
+		ID:        utils.UUIDToString(k.ID),
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Scopes:    k.Scopes,
+		UserID:    utils.UUIDToString(k.UserID),
+		CreatedAt: k.CreatedAt.Time.Format(time.RFC3339),
+	}
+	if k.LastUsedAt.Valid {
+		resp.LastUsedAt = k.LastUsedAt.Time.Format(time.RFC3339)
+	}
+	if k.RevokedAt.Valid {
+		resp.RevokedAt = k.RevokedAt.Time.Format(time.RFC3339)
+	}
+	return resp
+}
@@ -0,0 +1,712 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/internal/storage"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/idgen"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Estados da varredura antivírus de um anexo
+const (
+	ScanPending  = "pending"
+	ScanScanning = "scanning"
+	ScanClean    = "clean"
+	ScanInfected = "infected"
+	ScanSkipped  = "skipped" // Nenhum antivírus configurado
+	ScanError    = "error"
+)
+
+// Políticas para imagens sinalizadas como NSFW
+const (
+	NSFWPolicyOff  = "off"
+	NSFWPolicyFlag = "flag"
+	NSFWPolicyBlur = "blur"
+)
+
+// ErrAttachmentQuarantined anexo infectado (conteúdo indisponível)
+var ErrAttachmentQuarantined = errors.New("anexo em quarentena")
+
+// Erros da política de upload (tamanho e tipo)
+var (
+	ErrAttachmentTooLarge       = errors.New("anexo excede o tamanho máximo")
+	ErrAttachmentTypeNotAllowed = errors.New("tipo de arquivo não permitido")
+	ErrAttachmentTypeMismatch   = errors.New("conteúdo não corresponde ao tipo declarado")
+)
+
+// Erros de upload retomável
+var (
+	ErrUploadNotFound       = errors.New("upload não encontrado ou expirado")
+	ErrUploadOffsetMismatch = errors.New("offset do upload não confere")
+)
+
+// ErrNSFWConfirmationRequired imagem sinalizada sem prévia: original só com confirmação
+var ErrNSFWConfirmationRequired = errors.New("conteúdo sensível: confirme para ver o original")
+
+// Versões de um anexo servidas no download
+const (
+	VariantOriginal = "original"
+	VariantPreview  = "preview" // Prévia desfocada de imagem NSFW
+	VariantWeb      = "web"     // Vídeo transcodificado para web
+	VariantPoster   = "poster"  // Quadro representativo do vídeo
+)
+
+// URLSigner assina URLs de download (HMAC próprio, CloudFront, S3 presign...)
+// Implementada por signedurl.Signer
+type URLSigner interface {
+	Sign(path string, now time.Time) (string, time.Time)
+	Verify(path, expires, signature string, now time.Time) error
+}
+
+// AttachmentService gerencia upload e download de anexos
+type AttachmentService struct {
+	queries *repository.Queries
+	store   storage.Store
+	signer  URLSigner
+	quotas  *QuotaService
+	cfg     *config.Config
+	clock   clock.Clock // Sessões de upload e URLs assinadas
+	ids     idgen.Generator
+}
+
+// NewAttachmentService cria nova instância do service
+func NewAttachmentService(queries *repository.Queries, store storage.Store, signer URLSigner, quotas *QuotaService, cfg *config.Config) *AttachmentService {
+	return &AttachmentService{
+		queries: queries,
+		store:   store,
+		signer:  signer,
+		quotas:  quotas,
+		cfg:     cfg,
+		clock:   clock.System,
+		ids:     idgen.Random,
+	}
+}
+
+// SetClock troca o relógio (testes)
+func (s *AttachmentService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// SetIDs troca o gerador de IDs (testes)
+func (s *AttachmentService) SetIDs(ids idgen.Generator) {
+	s.ids = ids
+}
+
+// Create registra o anexo e retorna a URL para envio do conteúdo
+func (s *AttachmentService) Create(ctx context.Context, userID string, input types.CreateAttachmentInput) (*types.AttachmentUploadResponse, error) {
+	userUUID, err := utils.StringToUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	input.FileName = strings.TrimSpace(input.FileName)
+	if input.FileName == "" || len(input.FileName) > 255 {
+		return nil, fmt.Errorf("nome do arquivo deve ter entre 1 e 255 caracteres")
+	}
+	if input.ContentType == "" {
+		return nil, fmt.Errorf("content_type é obrigatório")
+	}
+	if input.Size <= 0 {
+		return nil, fmt.Errorf("tamanho do anexo deve ser maior que zero")
+	}
+	contentType, err := s.checkPolicy(input.ContentType, input.Size)
+	if err != nil {
+		return nil, err
+	}
+	input.ContentType = contentType
+	if err := s.quotas.CheckAttachment(ctx, userUUID, input.Size); err != nil {
+		return nil, err
+	}
+
+	id := s.ids.New()
+	attachment, err := s.queries.CreateAttachment(ctx, repository.CreateAttachmentParams{
+		ID:          pgtype.UUID{Bytes: id, Valid: true},
+		UploaderID:  userUUID,
+		StorageKey:  fmt.Sprintf("attachments/%s/%s", userID, id),
+		FileName:    input.FileName,
+		ContentType: input.ContentType,
+		SizeBytes:   input.Size,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar anexo: %w", err)
+	}
+
+	return &types.AttachmentUploadResponse{
+		Attachment: s.toResponse(attachment),
+		UploadURL:  fmt.Sprintf("%s/attachments/%s/content", s.cfg.Mail.BaseURL, id),
+	}, nil
+}
+
+// Upload grava o conteúdo de um anexo ainda não finalizado
+func (s *AttachmentService) Upload(ctx context.Context, userID, attachmentID string, body io.Reader) error {
+	userUUID, attachment, err := s.ownAttachment(ctx, userID, attachmentID)
+	if err != nil {
+		return err
+	}
+	if attachment.Status != "pending" {
+		return fmt.Errorf("upload do anexo já foi finalizado")
+	}
+	if err := s.checkNoUploadSession(ctx, attachment.ID); err != nil {
+		return err
+	}
+
+	// Lê um byte a mais que o declarado para detectar conteúdo maior
+	written, err := s.store.Put(ctx, attachment.StorageKey, io.LimitReader(body, attachment.SizeBytes+1))
+	if err != nil {
+		return fmt.Errorf("erro ao gravar anexo: %w", err)
+	}
+	if written > attachment.SizeBytes {
+		_ = s.store.Delete(ctx, attachment.StorageKey)
+		return fmt.Errorf("conteúdo maior que o tamanho declarado (%d bytes)", attachment.SizeBytes)
+	}
+
+	rows, err := s.queries.SetAttachmentSize(ctx, repository.SetAttachmentSizeParams{
+		ID:         attachment.ID,
+		UploaderID: userUUID,
+		SizeBytes:  written,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar anexo: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("upload do anexo já foi finalizado")
+	}
+	return nil
+}
+
+// Finalize conclui o upload e coloca o anexo na fila do antivírus
+func (s *AttachmentService) Finalize(ctx context.Context, userID, attachmentID string) (*types.AttachmentResponse, error) {
+	userUUID, attachment, err := s.ownAttachment(ctx, userID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkNoUploadSession(ctx, attachment.ID); err != nil {
+		return nil, err
+	}
+	return s.finalize(ctx, userUUID, attachment)
+}
+
+// finalize valida o conteúdo e libera o anexo para o antivírus
+func (s *AttachmentService) finalize(ctx context.Context, userUUID pgtype.UUID, attachment repository.Attachment) (*types.AttachmentResponse, error) {
+	// Revalida com o conteúdo recebido (tamanho real e tipo detectado)
+	if err := s.checkContent(ctx, attachment); err != nil {
+		_ = s.store.Delete(ctx, attachment.StorageKey)
+		return nil, err
+	}
+
+	rows, err := s.queries.MarkAttachmentUploaded(ctx, repository.MarkAttachmentUploadedParams{
+		ID:         attachment.ID,
+		UploaderID: userUUID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao finalizar anexo: %w", err)
+	}
+	if rows == 0 {
+		return nil, fmt.Errorf("anexo sem conteúdo ou já finalizado")
+	}
+
+	attachment, err = s.queries.GetAttachmentByID(ctx, attachment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar anexo: %w", err)
+	}
+	resp := s.toResponse(attachment)
+	return &resp, nil
+}
+
+// CreateUpload inicia upload retomável (tus): cria o anexo e a sessão que
+// acompanha o offset recebido
+func (s *AttachmentService) CreateUpload(ctx context.Context, userID string, input types.CreateAttachmentInput) (*types.UploadSessionResponse, error) {
+	created, err := s.Create(ctx, userID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	userUUID, _ := utils.StringToUUID(userID)
+	attachmentUUID, _ := utils.StringToUUID(created.Attachment.ID)
+	session, err := s.queries.CreateUploadSession(ctx, repository.CreateUploadSessionParams{
+		AttachmentID: attachmentUUID,
+		UserID:       userUUID,
+		UploadLength: input.Size,
+		ExpiresAt:    pgtype.Timestamp{Time: s.clock.Now().Add(s.cfg.Storage.UploadSessionTTL), Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar sessão de upload: %w", err)
+	}
+	return toUploadSessionResponse(session, nil), nil
+}
+
+// UploadStatus retorna o offset confirmado de um upload retomável
+func (s *AttachmentService) UploadStatus(ctx context.Context, userID, attachmentID string) (*types.UploadSessionResponse, error) {
+	session, _, err := s.uploadSession(ctx, userID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	return toUploadSessionResponse(session, nil), nil
+}
+
+// AppendUpload grava um bloco a partir de offset (deve ser o offset confirmado)
+// Bytes recebidos antes de uma interrupção são mantidos; ao completar, o anexo
+// é finalizado e a sessão removida
+func (s *AttachmentService) AppendUpload(ctx context.Context, userID, attachmentID string, offset int64, body io.Reader) (*types.UploadSessionResponse, error) {
+	session, attachment, err := s.uploadSession(ctx, userID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if offset != session.UploadOffset {
+		return nil, fmt.Errorf("%w: esperado %d", ErrUploadOffsetMismatch, session.UploadOffset)
+	}
+
+	// Lê um byte a mais que o restante para detectar conteúdo maior que o declarado
+	remaining := session.UploadLength - offset
+	written, writeErr := s.store.PutAt(ctx, attachment.StorageKey, offset, io.LimitReader(body, remaining+1))
+	if written > remaining {
+		return nil, fmt.Errorf("%w (%d bytes declarados)", ErrAttachmentTooLarge, session.UploadLength)
+	}
+
+	if written > 0 {
+		rows, err := s.queries.AdvanceUploadSession(ctx, repository.AdvanceUploadSessionParams{
+			NewOffset:     offset + written,
+			ExpiresAt:     pgtype.Timestamp{Time: s.clock.Now().Add(s.cfg.Storage.UploadSessionTTL), Valid: true},
+			AttachmentID:  attachment.ID,
+			CurrentOffset: offset,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("erro ao atualizar sessão de upload: %w", err)
+		}
+		if rows == 0 {
+			return nil, fmt.Errorf("%w: upload concorrente", ErrUploadOffsetMismatch)
+		}
+		session.UploadOffset = offset + written
+		session.ExpiresAt.Time = s.clock.Now().Add(s.cfg.Storage.UploadSessionTTL)
+	}
+	if writeErr != nil {
+		return toUploadSessionResponse(session, nil), fmt.Errorf("erro ao gravar bloco: %w", writeErr)
+	}
+	if session.UploadOffset < session.UploadLength {
+		return toUploadSessionResponse(session, nil), nil
+	}
+
+	// Upload completo
+	finalized, err := s.finalize(ctx, session.UserID, attachment)
+	if err != nil {
+		_ = s.queries.DeleteAttachment(ctx, attachment.ID)
+		return nil, err
+	}
+	if err := s.queries.DeleteUploadSession(ctx, attachment.ID); err != nil {
+		return nil, fmt.Errorf("erro ao encerrar sessão de upload: %w", err)
+	}
+	return toUploadSessionResponse(session, finalized), nil
+}
+
+// TerminateUpload cancela upload retomável, removendo conteúdo e anexo
+func (s *AttachmentService) TerminateUpload(ctx context.Context, userID, attachmentID string) error {
+	_, attachment, err := s.uploadSession(ctx, userID, attachmentID)
+	if err != nil {
+		return err
+	}
+	if err := s.store.Delete(ctx, attachment.StorageKey); err != nil {
+		return fmt.Errorf("erro ao remover conteúdo: %w", err)
+	}
+	if err := s.queries.DeleteAttachment(ctx, attachment.ID); err != nil {
+		return fmt.Errorf("erro ao remover anexo: %w", err)
+	}
+	return nil
+}
+
+// uploadSession busca sessão de upload ativa do próprio usuário
+func (s *AttachmentService) uploadSession(ctx context.Context, userID, attachmentID string) (repository.UploadSession, repository.Attachment, error) {
+	_, attachment, err := s.ownAttachment(ctx, userID, attachmentID)
+	if err != nil {
+		return repository.UploadSession{}, repository.Attachment{}, ErrUploadNotFound
+	}
+
+	session, err := s.queries.GetUploadSession(ctx, attachment.ID)
+	if err == pgx.ErrNoRows {
+		return repository.UploadSession{}, repository.Attachment{}, ErrUploadNotFound
+	}
+	if err != nil {
+		return repository.UploadSession{}, repository.Attachment{}, fmt.Errorf("erro ao buscar sessão de upload: %w", err)
+	}
+	if session.ExpiresAt.Time.Before(s.clock.Now()) {
+		return repository.UploadSession{}, repository.Attachment{}, ErrUploadNotFound
+	}
+	return session, attachment, nil
+}
+
+// checkNoUploadSession impede o fluxo simples (PUT/finalize) em anexo com
+// upload retomável em andamento
+func (s *AttachmentService) checkNoUploadSession(ctx context.Context, attachmentID pgtype.UUID) error {
+	_, err := s.queries.GetUploadSession(ctx, attachmentID)
+	if err == nil {
+		return fmt.Errorf("anexo com upload retomável em andamento")
+	}
+	if err != pgx.ErrNoRows {
+		return fmt.Errorf("erro ao buscar sessão de upload: %w", err)
+	}
+	return nil
+}
+
+// toUploadSessionResponse converte sessão de upload para resposta
+func toUploadSessionResponse(session repository.UploadSession, attachment *types.AttachmentResponse) *types.UploadSessionResponse {
+	return &types.UploadSessionResponse{
+		AttachmentID: utils.UUIDToString(session.AttachmentID),
+		Offset:       session.UploadOffset,
+		Length:       session.UploadLength,
+		ExpiresAt:    session.ExpiresAt.Time,
+		Attachment:   attachment,
+	}
+}
+
+// Get retorna metadados do anexo (remetente ou destinatário da mensagem)
+func (s *AttachmentService) Get(ctx context.Context, userID, attachmentID string) (*types.AttachmentResponse, error) {
+	attachment, _, err := s.visibleAttachment(ctx, userID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	resp := s.toResponse(attachment)
+	return &resp, nil
+}
+
+// Open abre o conteúdo do anexo para download
+// O destinatário só recebe anexos já verificados; infectados nunca são servidos.
+// Imagens NSFW com política blur entregam a prévia desfocada até o destinatário
+// confirmar (confirmNSFW); Blurred na resposta indica qual versão foi aberta.
+// variant escolhe outra versão (web, poster); vazio = original
+func (s *AttachmentService) Open(ctx context.Context, userID, attachmentID, variant string, confirmNSFW bool) (io.ReadCloser, *types.AttachmentResponse, error) {
+	attachment, variant, err := s.resolveDownload(ctx, userID, attachmentID, variant, confirmNSFW)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.openVariant(ctx, attachment, variant)
+}
+
+// DownloadURL gera URL assinada e temporária para o anexo
+// O acesso (conversa, varredura, confirmação NSFW) é verificado aqui; a URL
+// carrega apenas a versão liberada e pode ser servida por CDN sem autenticação
+func (s *AttachmentService) DownloadURL(ctx context.Context, userID, attachmentID, variant string, confirmNSFW bool) (*types.DownloadURLResponse, error) {
+	attachment, variant, err := s.resolveDownload(ctx, userID, attachmentID, variant, confirmNSFW)
+	if err != nil {
+		return nil, err
+	}
+
+	url, expiresAt := s.signer.Sign(signedFilePath(utils.UUIDToString(attachment.ID), variant), s.clock.Now())
+	return &types.DownloadURLResponse{
+		URL:       url,
+		ExpiresAt: expiresAt.Format(time.RFC3339),
+		Blurred:   variant == VariantPreview,
+	}, nil
+}
+
+// OpenSigned abre anexo de uma URL assinada (assinatura já validada)
+// Quarentena aplicada depois da assinatura continua bloqueando o download
+func (s *AttachmentService) OpenSigned(ctx context.Context, attachmentID, variant string) (io.ReadCloser, *types.AttachmentResponse, error) {
+	attachment, err := s.getAttachment(ctx, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attachment.ScanStatus == ScanInfected {
+		return nil, nil, ErrAttachmentQuarantined
+	}
+	return s.openVariant(ctx, attachment, variant)
+}
+
+// VerifySignedPath valida assinatura e expiração de uma URL de download
+func (s *AttachmentService) VerifySignedPath(attachmentID, variant, expires, signature string) error {
+	return s.signer.Verify(signedFilePath(attachmentID, variant), expires, signature, s.clock.Now())
+}
+
+// resolveDownload aplica as regras de acesso e escolhe a versão a servir
+func (s *AttachmentService) resolveDownload(ctx context.Context, userID, attachmentID, variant string, confirmNSFW bool) (repository.Attachment, string, error) {
+	attachment, isUploader, err := s.visibleAttachment(ctx, userID, attachmentID)
+	if err != nil {
+		return repository.Attachment{}, "", err
+	}
+
+	switch attachment.ScanStatus {
+	case ScanInfected:
+		return repository.Attachment{}, "", ErrAttachmentQuarantined
+	case ScanClean, ScanSkipped:
+	default:
+		if !isUploader {
+			return repository.Attachment{}, "", fmt.Errorf("anexo ainda em verificação")
+		}
+	}
+
+	// Versões derivadas (vídeo para web, pôster) não passam pela regra NSFW de imagens
+	if variant != "" && variant != VariantOriginal {
+		if !slices.Contains(AttachmentVariants(attachment), variant) {
+			return repository.Attachment{}, "", fmt.Errorf("versão %q indisponível", variant)
+		}
+		return attachment, variant, nil
+	}
+
+	resp := s.toResponse(attachment)
+	if resp.NSFW && s.cfg.Storage.NSFWPolicy == NSFWPolicyBlur && !isUploader && !confirmNSFW {
+		if !resp.Blurred {
+			return repository.Attachment{}, "", ErrNSFWConfirmationRequired
+		}
+		return attachment, VariantPreview, nil
+	}
+	return attachment, VariantOriginal, nil
+}
+
+// openVariant abre o original ou a prévia desfocada
+func (s *AttachmentService) openVariant(ctx context.Context, attachment repository.Attachment, variant string) (io.ReadCloser, *types.AttachmentResponse, error) {
+	resp := s.toResponse(attachment)
+	resp.Blurred = variant == VariantPreview
+	key := attachment.StorageKey
+	switch variant {
+	case VariantOriginal:
+	case VariantPreview:
+		if attachment.PreviewKey == nil {
+			return nil, nil, fmt.Errorf("anexo sem prévia")
+		}
+		key, resp.ContentType = *attachment.PreviewKey, "image/jpeg"
+	case VariantWeb:
+		if attachment.RenditionKey == nil {
+			return nil, nil, fmt.Errorf("anexo sem versão para web")
+		}
+		key, resp.ContentType = *attachment.RenditionKey, "video/mp4"
+	case VariantPoster:
+		if attachment.PosterKey == nil {
+			return nil, nil, fmt.Errorf("anexo sem pôster")
+		}
+		key, resp.ContentType = *attachment.PosterKey, "image/jpeg"
+	default:
+		return nil, nil, fmt.Errorf("versão de anexo inválida")
+	}
+	if variant != VariantOriginal {
+		resp.Size = 0 // Tamanho registrado é o do original
+	}
+
+	content, err := s.store.Open(ctx, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao abrir anexo: %w", err)
+	}
+	return content, &resp, nil
+}
+
+// signedFilePath caminho coberto pela assinatura das URLs de download
+func signedFilePath(attachmentID, variant string) string {
+	return "/files/" + attachmentID + "/" + variant
+}
+
+// checkPolicy valida tipo e tamanho contra a política da instalação e
+// retorna o tipo normalizado (sem parâmetros, minúsculo)
+func (s *AttachmentService) checkPolicy(contentType string, size int64) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrAttachmentTypeNotAllowed, contentType)
+	}
+	if !s.allowedType(mediaType) {
+		return "", fmt.Errorf("%w: %s", ErrAttachmentTypeNotAllowed, mediaType)
+	}
+
+	if limit := s.maxBytes(mediaType); size > limit {
+		return "", fmt.Errorf("%w (%d bytes para %s)", ErrAttachmentTooLarge, limit, mediaCategory(mediaType))
+	}
+	return mediaType, nil
+}
+
+// checkContent revalida o anexo enviado: tamanho gravado e categoria do
+// conteúdo detectada pelos primeiros bytes (impede exe declarado como imagem)
+func (s *AttachmentService) checkContent(ctx context.Context, attachment repository.Attachment) error {
+	if _, err := s.checkPolicy(attachment.ContentType, attachment.SizeBytes); err != nil {
+		return err
+	}
+
+	content, err := s.store.Open(ctx, attachment.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("anexo sem conteúdo")
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao abrir anexo: %w", err)
+	}
+	defer content.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(content, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return fmt.Errorf("erro ao ler anexo: %w", err)
+	}
+	// Imagens são sempre reconhecidas; vídeos e documentos podem vir como
+	// octet-stream (formato que o detector não conhece)
+	detected := http.DetectContentType(head[:n])
+	declared := mediaCategory(attachment.ContentType)
+	unknown := detected == "application/octet-stream" && declared != "image"
+	if mediaCategory(detected) != declared && !unknown {
+		return fmt.Errorf("%w: declarado %s, detectado %s", ErrAttachmentTypeMismatch, attachment.ContentType, detected)
+	}
+	return nil
+}
+
+// allowedType verifica o tipo contra a lista (aceita curinga como image/*)
+func (s *AttachmentService) allowedType(mediaType string) bool {
+	for _, allowed := range s.cfg.Storage.AllowedMIMETypes {
+		allowed = strings.ToLower(allowed)
+		if allowed == mediaType || allowed == "*/*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// maxBytes limite de tamanho da categoria do tipo (nunca acima do limite geral)
+func (s *AttachmentService) maxBytes(mediaType string) int64 {
+	cfg := s.cfg.Storage
+	var limit int64
+	switch mediaCategory(mediaType) {
+	case "image":
+		limit = cfg.MaxImageBytes
+	case "video":
+		limit = cfg.MaxVideoBytes
+	default:
+		limit = cfg.MaxDocumentBytes
+	}
+	if limit <= 0 || limit > cfg.MaxAttachmentBytes {
+		return cfg.MaxAttachmentBytes
+	}
+	return limit
+}
+
+// mediaCategory agrupa tipos em image, video ou document
+func mediaCategory(contentType string) string {
+	switch {
+	case strings.HasPrefix(contentType, "image/"):
+		return "image"
+	case strings.HasPrefix(contentType, "video/"):
+		return "video"
+	default:
+		return "document"
+	}
+}
+
+// ownAttachment busca anexo do próprio usuário
+func (s *AttachmentService) ownAttachment(ctx context.Context, userID, attachmentID string) (pgtype.UUID, repository.Attachment, error) {
+	userUUID, err := utils.StringToUUID(userID)
+	if err != nil {
+		return pgtype.UUID{}, repository.Attachment{}, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	attachment, err := s.getAttachment(ctx, attachmentID)
+	if err != nil {
+		return pgtype.UUID{}, repository.Attachment{}, err
+	}
+	if attachment.UploaderID != userUUID {
+		return pgtype.UUID{}, repository.Attachment{}, fmt.Errorf("anexo não encontrado")
+	}
+	return userUUID, attachment, nil
+}
+
+// visibleAttachment busca anexo visível ao usuário (quem enviou ou destinatário
+// da mensagem em que foi anexado); também informa se é quem enviou
+func (s *AttachmentService) visibleAttachment(ctx context.Context, userID, attachmentID string) (repository.Attachment, bool, error) {
+	userUUID, err := utils.StringToUUID(userID)
+	if err != nil {
+		return repository.Attachment{}, false, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	attachment, err := s.getAttachment(ctx, attachmentID)
+	if err != nil {
+		return repository.Attachment{}, false, err
+	}
+	if attachment.UploaderID == userUUID {
+		return attachment, true, nil
+	}
+
+	if attachment.MessageID.Valid {
+		message, err := s.queries.GetMessageByID(ctx, attachment.MessageID)
+		if err != nil && err != pgx.ErrNoRows {
+			return repository.Attachment{}, false, fmt.Errorf("erro ao buscar mensagem: %w", err)
+		}
+		if err == nil && message.ReceiverID == userUUID {
+			return attachment, false, nil
+		}
+	}
+	return repository.Attachment{}, false, fmt.Errorf("anexo não encontrado")
+}
+
+// getAttachment busca anexo pelo ID
+func (s *AttachmentService) getAttachment(ctx context.Context, attachmentID string) (repository.Attachment, error) {
+	id, err := utils.StringToUUID(attachmentID)
+	if err != nil {
+		return repository.Attachment{}, fmt.Errorf("ID de anexo inválido: %w", err)
+	}
+
+	attachment, err := s.queries.GetAttachmentByID(ctx, id)
+	if err == pgx.ErrNoRows {
+		return repository.Attachment{}, fmt.Errorf("anexo não encontrado")
+	}
+	if err != nil {
+		return repository.Attachment{}, fmt.Errorf("erro ao buscar anexo: %w", err)
+	}
+	return attachment, nil
+}
+
+// toResponse converte anexo para resposta da API
+func (s *AttachmentService) toResponse(a repository.Attachment) types.AttachmentResponse {
+	return toAttachmentResponse(a, s.cfg.Storage.NSFWPolicy)
+}
+
+// toAttachmentResponse converte anexo do banco para resposta da API
+// aplicando a política NSFW da instalação
+func toAttachmentResponse(a repository.Attachment, nsfwPolicy string) types.AttachmentResponse {
+	resp := types.AttachmentResponse{
+		ID:          utils.UUIDToString(a.ID),
+		MessageID:   utils.UUIDToString(a.MessageID),
+		FileName:    a.FileName,
+		ContentType: a.ContentType,
+		Size:        a.SizeBytes,
+		Status:      a.Status,
+		ScanStatus:  a.ScanStatus,
+		CreatedAt:   a.CreatedAt.Time.Format(time.RFC3339),
+		NSFW:        a.NsfwFlagged,
+		NSFWLabels:  a.NsfwLabels,
+	}
+	if a.ScanResult != nil {
+		resp.ScanResult = *a.ScanResult
+	}
+	if a.ScannedAt.Valid {
+		resp.ScannedAt = a.ScannedAt.Time.Format(time.RFC3339)
+	}
+	if nsfwPolicy == NSFWPolicyOff {
+		resp.NSFW, resp.NSFWLabels = false, nil
+	}
+	resp.Blurred = resp.NSFW && nsfwPolicy == NSFWPolicyBlur && a.PreviewKey != nil
+	resp.Variants = AttachmentVariants(a)
+	return resp
+}
+
+// AttachmentVariants versões do anexo disponíveis para download
+func AttachmentVariants(a repository.Attachment) []string {
+	variants := []string{VariantOriginal}
+	if a.PreviewKey != nil {
+		variants = append(variants, VariantPreview)
+	}
+	if a.RenditionKey != nil {
+		variants = append(variants, VariantWeb)
+	}
+	if a.PosterKey != nil {
+		variants = append(variants, VariantPoster)
+	}
+	return variants
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"chat-kafka-go/internal/reporter"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Ações registradas no log de auditoria
+const (
+	AuditLoginSuccess   = "login.success"
+	AuditLoginFailed    = "login.failed"
+	AuditLoginStepUp    = "login.step_up"
+	AuditStepUpVerified = "login.step_up_verified"
+	AuditStepUpFailed   = "login.step_up_failed"
+	AuditSessionNotMe   = "session.revoked_not_me"
+	AuditShadowBan      = "moderation.shadow_ban"
+	AuditShadowBanLift  = "moderation.shadow_ban_lifted"
+	AuditSupportAgent   = "support.agent_added"
+	AuditSupportRevoke  = "support.agent_removed"
+	AuditLegalHold      = "compliance.legal_hold"
+	AuditLegalHoldLift  = "compliance.legal_hold_released"
+	AuditEmailVerified  = "account.email_verified"
+	AuditPasswordForgot = "account.password_reset_requested"
+	AuditPasswordReset  = "account.password_reset"
+
+	AuditSupportAccessGrant  = "support.access_granted"
+	AuditSupportAccessRevoke = "support.access_revoked"
+	AuditImpersonationStart  = "support.impersonation_started"
+	AuditImpersonationEnd    = "support.impersonation_ended"
+	AuditImpersonationAccess = "support.impersonation_access"
+)
+
+// AuditService grava e consulta o log de auditoria de segurança
+type AuditService struct {
+	queries *repository.Queries
+}
+
+// NewAuditService cria nova instância do service
+func NewAuditService(queries *repository.Queries) *AuditService {
+	return &AuditService{queries: queries}
+}
+
+// Record grava evento; falhas são logadas e reportadas (nunca bloqueiam o fluxo)
+func (s *AuditService) Record(ctx context.Context, userID pgtype.UUID, action, ip string, riskScore int, metadata interface{}) {
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		log.Printf("ERRO: auditoria %s: %v", action, err)
+		return
+	}
+
+	err = s.queries.CreateAuditEvent(ctx, repository.CreateAuditEventParams{
+		UserID:    userID,
+		Action:    action,
+		Ip:        ip,
+		RiskScore: int32(riskScore),
+		Metadata:  data,
+	})
+	if err != nil {
+		log.Printf("ERRO: auditoria %s: %v", action, err)
+		reporter.CaptureError(ctx, err, map[string]string{"component": "audit"})
+	}
+}
+
+// CountUserSince conta eventos do usuário desde o instante informado
+func (s *AuditService) CountUserSince(ctx context.Context, userID pgtype.UUID, action string, since time.Time) (int, error) {
+	n, err := s.queries.CountUserAuditEventsSince(ctx, repository.CountUserAuditEventsSinceParams{
+		UserID:    userID,
+		Action:    action,
+		CreatedAt: pgtype.Timestamp{Time: since, Valid: true},
+	})
+	return int(n), err
+}
+
+// CountIPSince conta eventos do IP desde o instante informado
+func (s *AuditService) CountIPSince(ctx context.Context, ip, action string, since time.Time) (int, error) {
+	n, err := s.queries.CountIPAuditEventsSince(ctx, repository.CountIPAuditEventsSinceParams{
+		Ip:        ip,
+		Action:    action,
+		CreatedAt: pgtype.Timestamp{Time: since, Valid: true},
+	})
+	return int(n), err
+}
+
+// List retorna eventos recentes de um usuário ou de uma ação
+func (s *AuditService) List(ctx context.Context, userID, action string, limit int) ([]types.AuditEventResponse, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	var events []repository.AuditEvent
+	switch {
+	case userID != "":
+		userUUID, err := utils.StringToUUID(userID)
+		if err != nil {
+			return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+		}
+		events, err = s.queries.ListUserAuditEvents(ctx, repository.ListUserAuditEventsParams{
+			UserID: userUUID,
+			Limit:  int32(limit),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar auditoria: %w", err)
+		}
+	case action != "":
+		var err error
+		events, err = s.queries.ListAuditEventsByAction(ctx, repository.ListAuditEventsByActionParams{
+			Action: action,
+			Limit:  int32(limit),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar auditoria: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("informe user_id ou action")
+	}
+
+	resp := make([]types.AuditEventResponse, 0, len(events))
+	for _, e := range events {
+		resp = append(resp, types.AuditEventResponse{
+			ID:        utils.UUIDToString(e.ID),
+			UserID:    utils.UUIDToString(e.UserID),
+			Action:    e.Action,
+			IP:        e.Ip,
+			RiskScore: int(e.RiskScore),
+			Metadata:  e.Metadata,
+			CreatedAt: e.CreatedAt.Time.Format(time.RFC3339),
+		})
+	}
+	return resp, nil
+}
@@ -2,11 +2,16 @@ package service
 
 import (
 	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/disposable"
+	"chat-kafka-go/internal/reporter"
 	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/clock"
 	"chat-kafka-go/pkg/types"
 	"chat-kafka-go/pkg/utils"
+	"chat-kafka-go/pkg/validate"
 	"context"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -15,45 +20,97 @@ import (
 
 // AuthService gerencia autenticação e autorização
 type AuthService struct {
-	queries *repository.Queries // Repository gerado pelo SQLC
-	cfg     *config.Config      // Configurações (JWT secrets, etc)
+	queries     *repository.Queries       // Repository gerado pelo SQLC
+	invitations *InvitationService        // Convites aceitos no registro
+	blocklist   *disposable.Blocklist     // Domínios de email descartável
+	logins      *LoginAlertService        // Histórico de logins e alertas de novo dispositivo
+	risk        *LoginRiskService         // Score de risco e step-up
+	emails      *EmailVerificationService // Confirmação de email (cadastro e exigência no login)
+	resets      *PasswordResetService     // Redefinição de senha por link no email
+	audit       *AuditService             // Log de auditoria
+	cfg         *config.Config            // Configurações (JWT secrets, etc)
+	clock       clock.Clock               // Emissão e expiração dos tokens
+	welcome     *WelcomeService           // nil = sem conversa de boas-vindas
+	usernames   *validate.UsernamePolicy
 }
 
 // NewAuthService cria nova instância do service
-func NewAuthService(queries *repository.Queries, cfg *config.Config) *AuthService {
+func NewAuthService(queries *repository.Queries, invitations *InvitationService, blocklist *disposable.Blocklist, logins *LoginAlertService, risk *LoginRiskService, emails *EmailVerificationService, resets *PasswordResetService, audit *AuditService, cfg *config.Config) *AuthService {
 	return &AuthService{
-		queries: queries,
-		cfg:     cfg,
+		queries:     queries,
+		invitations: invitations,
+		blocklist:   blocklist,
+		logins:      logins,
+		risk:        risk,
+		emails:      emails,
+		resets:      resets,
+		audit:       audit,
+		usernames:   newUsernamePolicy(&cfg.User),
+		cfg:         cfg,
+		clock:       clock.System,
 	}
 }
 
+// SetClock troca o relógio (testes de expiração)
+func (s *AuthService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// SetWelcome envia as mensagens de boas-vindas a cada novo usuário
+func (s *AuthService) SetWelcome(welcome *WelcomeService) {
+	s.welcome = welcome
+}
+
 // Register cria um novo usuário e retorna tokens
-func (s *AuthService) Register(ctx context.Context, input types.RegisterInput) (*types.AuthResponse, error) {
+func (s *AuthService) Register(ctx context.Context, input types.RegisterInput, client types.ClientInfo) (*types.AuthResponse, error) {
 	// 1. Validar input
 	if err := s.validateRegisterInput(input); err != nil {
 		return nil, err
 	}
+	if s.blocklist.IsDisposable(input.Email) {
+		return nil, fmt.Errorf("emails descartáveis não são permitidos")
+	}
 
 	// 2. Verificar se email já existe
 	_, err := s.queries.GetUserByEmail(ctx, input.Email)
 	if err == nil {
 		// Email encontrado = já existe
-		return nil, fmt.Errorf("email já cadastrado")
+		return nil, ErrEmailTaken
 	}
 	if err != pgx.ErrNoRows {
 		// Erro diferente de "não encontrado"
 		return nil, fmt.Errorf("erro ao verificar email: %w", err)
 	}
 
-	// 3. Verificar se username já existe
+	// 3. Verificar se username já existe (sem diferenciar maiúsculas)
 	_, err = s.queries.GetUserByUsername(ctx, input.Username)
 	if err == nil {
-		return nil, fmt.Errorf("username já cadastrado")
+		return nil, ErrUsernameTaken
 	}
 	if err != pgx.ErrNoRows {
 		return nil, fmt.Errorf("erro ao verificar username: %w", err)
 	}
 
+	// Username antigo de outra conta ainda reservado após a troca
+	_, err = s.queries.GetActiveUsernameReservation(ctx, input.Username)
+	if err == nil {
+		return nil, ErrUsernameReserved
+	}
+	if err != pgx.ErrNoRows {
+		return nil, fmt.Errorf("erro ao verificar reserva: %w", err)
+	}
+
+	// Convite conferido antes de criar a conta: depois do INSERT o cadastro
+	// não falha mais por causa dele
+	var invitation *repository.Invitation
+	if input.InviteToken != "" {
+		inv, err := s.invitations.ValidateInvitation(ctx, input.InviteToken, input.Email)
+		if err != nil {
+			return nil, err
+		}
+		invitation = &inv
+	}
+
 	// 4. Hash da senha
 	passwordHash, err := utils.HashPassword(input.Password)
 	if err != nil {
@@ -66,31 +123,76 @@ func (s *AuthService) Register(ctx context.Context, input types.RegisterInput) (
 		Email:        input.Email,
 		PasswordHash: passwordHash,
 	})
+	if conflict := uniqueViolation(err); conflict != nil {
+		return nil, conflict // Outro cadastro venceu entre a verificação e o INSERT
+	}
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar usuário: %w", err)
 	}
 
-	// 6. Gerar tokens JWT
-	tokens, err := s.generateTokens(user.ID, user.Username, user.Email)
+	// Daqui em diante a conta já existe: falhas abaixo não desfazem o
+	// cadastro (erro faria o cliente repetir e esbarrar no próprio email)
+
+	// Hash do email para sincronização de contatos (o backfill do boot
+	// indexa quem ficar sem)
+	err = s.queries.UpsertContactHash(ctx, repository.UpsertContactHashParams{
+		Kind:   utils.ContactKindEmail,
+		Hash:   emailContactHash(s.cfg.Security.ContactHashPepper, user.Email),
+		UserID: user.ID,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("erro ao gerar tokens: %w", err)
+		log.Printf("WARN: hash de contato de %s: %v", user.Username, err)
 	}
 
-	// 7. Salvar refresh token no banco
-	if err := s.saveRefreshToken(ctx, user.ID, tokens.RefreshToken); err != nil {
-		return nil, fmt.Errorf("erro ao salvar refresh token: %w", err)
+	// Convite: registra indicação e amizade com quem convidou
+	if invitation != nil {
+		if err := s.invitations.AcceptInvitation(ctx, *invitation, user); err != nil {
+			log.Printf("WARN: convite aceito por %s: %v", user.Username, err)
+		}
 	}
 
-	// 8. Montar resposta
-	return &types.AuthResponse{
+	// Boas-vindas: falha no envio não desfaz o cadastro
+	if s.welcome != nil {
+		if err := s.welcome.Greet(ctx, user); err != nil {
+			log.Printf("WARN: boas-vindas para %s: %v", user.Username, err)
+		}
+	}
+
+	// Link de confirmação: falha no envio não desfaz o cadastro (reenvio disponível)
+	if err := s.emails.Send(ctx, user); err != nil {
+		log.Printf("WARN: confirmação de email para %s: %v", user.Username, err)
+	}
+
+	resp := &types.AuthResponse{
 		User: &types.UserResponse{
 			ID:        utils.UUIDToString(user.ID), // Converte UUID para string
 			Username:  user.Username,
 			Email:     user.Email,
 			CreatedAt: user.CreatedAt.Time.Format(time.RFC3339),
 		},
-		Tokens: tokens,
-	}, nil
+	}
+	if s.emails.RequiredForLogin() {
+		// Sessão só depois de confirmar o email
+		resp.VerificationRequired = true
+		return resp, nil
+	}
+
+	// 6. Gerar tokens JWT
+	tokens, err := s.generateTokens(user.ID, user.Username, user.Email)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar tokens: %w", err)
+	}
+
+	// 7. Salvar refresh token no banco
+	session, err := s.saveRefreshToken(ctx, user.ID, tokens.RefreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao salvar refresh token: %w", err)
+	}
+	s.recordLogin(ctx, user, session, input.DeviceID, s.logins.Locate(ctx, client))
+
+	// 8. Montar resposta
+	resp.Tokens = tokens
+	return resp, nil
 }
 
 // validateRegisterInput valida dados de entrada
@@ -98,48 +200,33 @@ func (s *AuthService) validateRegisterInput(input types.RegisterInput) error {
 	if input.Username == "" {
 		return fmt.Errorf("username é obrigatório")
 	}
-	if len(input.Username) < 3 || len(input.Username) > 50 {
-		return fmt.Errorf("username deve ter entre 3 e 50 caracteres")
+	if err := s.usernames.Check(input.Username); err != nil {
+		return err
 	}
 
 	if input.Email == "" {
 		return fmt.Errorf("email é obrigatório")
 	}
-	// Validação básica de email (pode usar regex mais complexo)
-	if !contains(input.Email, "@") || !contains(input.Email, ".") {
+	if !validate.Email(input.Email) {
 		return fmt.Errorf("email inválido")
 	}
 
-	if input.Password == "" {
+	return validatePassword(input.Password)
+}
+
+// validatePassword regras de senha do cadastro e da redefinição
+func validatePassword(password string) error {
+	if password == "" {
 		return fmt.Errorf("senha é obrigatória")
 	}
-	if len(input.Password) < 6 {
+	if len(password) < 6 {
 		return fmt.Errorf("senha deve ter no mínimo 6 caracteres")
 	}
-
 	return nil
 }
 
-// contains verifica se string contém substring
-func contains(s, substr string) bool {
-	return len(s) > 0 && len(substr) > 0 &&
-		len(s) >= len(substr) && s != substr &&
-		(s[0:len(substr)] == substr ||
-			s[len(s)-len(substr):] == substr ||
-			len(s) > len(substr) && containsMiddle(s, substr))
-}
-
-func containsMiddle(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
-		}
-	}
-	return false
-}
-
 // Login autentica usuário e retorna tokens
-func (s *AuthService) Login(ctx context.Context, input types.LoginInput) (*types.AuthResponse, error) {
+func (s *AuthService) Login(ctx context.Context, input types.LoginInput, client types.ClientInfo) (*types.AuthResponse, error) {
 	// 1. Validar input
 	if input.Email == "" || input.Password == "" {
 		return nil, fmt.Errorf("email e senha são obrigatórios")
@@ -149,6 +236,8 @@ func (s *AuthService) Login(ctx context.Context, input types.LoginInput) (*types
 	user, err := s.queries.GetUserByEmail(ctx, input.Email)
 	if err != nil {
 		if err == pgx.ErrNoRows {
+			// Conta para a velocidade de falhas do IP
+			s.audit.Record(ctx, pgtype.UUID{}, AuditLoginFailed, client.IP, 0, nil)
 			return nil, fmt.Errorf("credenciais inválidas")
 		}
 		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
@@ -156,21 +245,87 @@ func (s *AuthService) Login(ctx context.Context, input types.LoginInput) (*types
 
 	// 3. Verificar senha
 	if !utils.CheckPassword(input.Password, user.PasswordHash) {
+		s.audit.Record(ctx, user.ID, AuditLoginFailed, client.IP, 0, nil)
 		return nil, fmt.Errorf("credenciais inválidas")
 	}
 
-	// 4. Gerar novos tokens
+	// Email não confirmado: senha certa, mas sem sessão (EMAIL_VERIFICATION_REQUIRED=login)
+	if s.emails.RequiredForLogin() && !user.EmailVerifiedAt.Valid {
+		return nil, ErrEmailNotVerified
+	}
+
+	// 4. Avaliar risco; acima do limite exige confirmação por email (step-up)
+	client = s.logins.Locate(ctx, client)
+	assessment, err := s.risk.Assess(ctx, user, input.DeviceID, client)
+	if err != nil {
+		// Falha na avaliação não bloqueia o login
+		log.Printf("ERRO: avaliação de risco: %v", err)
+		reporter.CaptureError(ctx, err, map[string]string{"component": "login_risk"})
+	}
+	if s.risk.RequiresStepUp(assessment) {
+		challenge, err := s.risk.StartChallenge(ctx, user, input.DeviceID, client, assessment)
+		if err != nil {
+			return nil, err
+		}
+		return &types.AuthResponse{Challenge: challenge}, nil
+	}
+
+	// 5. Gerar tokens e registrar sessão
+	return s.startSession(ctx, user, input.DeviceID, client, assessment.Score, assessment.Reasons)
+}
+
+// VerifyLogin conclui login de risco com o código enviado por email
+func (s *AuthService) VerifyLogin(ctx context.Context, input types.VerifyLoginInput, client types.ClientInfo) (*types.AuthResponse, error) {
+	if input.ChallengeID == "" || input.Code == "" {
+		return nil, fmt.Errorf("challenge_id e code são obrigatórios")
+	}
+
+	user, deviceID, err := s.risk.VerifyChallenge(ctx, input, client)
+	if err != nil {
+		return nil, err
+	}
+
+	client = s.logins.Locate(ctx, client)
+	return s.startSession(ctx, user, deviceID, client, 0, []string{"step_up_verified"})
+}
+
+// VerifyEmail confirma o email com o token do link enviado no cadastro
+func (s *AuthService) VerifyEmail(ctx context.Context, input types.VerifyEmailInput, client types.ClientInfo) error {
+	return s.emails.Verify(ctx, input.Token, client.IP)
+}
+
+// ResendVerification envia novo link de confirmação (sem revelar se o email existe)
+func (s *AuthService) ResendVerification(ctx context.Context, input types.ResendVerificationInput) error {
+	return s.emails.Resend(ctx, input.Email)
+}
+
+// ForgotPassword envia link de redefinição de senha (sem revelar se o email existe)
+func (s *AuthService) ForgotPassword(ctx context.Context, input types.ForgotPasswordInput, client types.ClientInfo) error {
+	return s.resets.Request(ctx, input.Email, client.IP)
+}
+
+// ResetPassword troca a senha com o token do link e encerra todas as sessões
+func (s *AuthService) ResetPassword(ctx context.Context, input types.ResetPasswordInput, client types.ClientInfo) error {
+	return s.resets.Reset(ctx, input.Token, input.Password, client.IP)
+}
+
+// startSession gera tokens, salva o refresh token e registra o login
+func (s *AuthService) startSession(ctx context.Context, user repository.User, deviceID string, client types.ClientInfo, riskScore int, reasons []string) (*types.AuthResponse, error) {
 	tokens, err := s.generateTokens(user.ID, user.Username, user.Email)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao gerar tokens: %w", err)
 	}
 
-	// 5. Salvar refresh token no banco
-	if err := s.saveRefreshToken(ctx, user.ID, tokens.RefreshToken); err != nil {
+	session, err := s.saveRefreshToken(ctx, user.ID, tokens.RefreshToken)
+	if err != nil {
 		return nil, fmt.Errorf("erro ao salvar refresh token: %w", err)
 	}
+	s.recordLogin(ctx, user, session, deviceID, client)
+	s.audit.Record(ctx, user.ID, AuditLoginSuccess, client.IP, riskScore, map[string]interface{}{
+		"reasons": reasons,
+		"country": client.Country,
+	})
 
-	// 6. Retornar resposta
 	return &types.AuthResponse{
 		User: &types.UserResponse{
 			ID:        utils.UUIDToString(user.ID),
@@ -190,13 +345,13 @@ func (s *AuthService) RefreshToken(ctx context.Context, input types.RefreshToken
 	}
 
 	// 2. Validar JWT do refresh token
-	userID, err := utils.ValidateRefreshToken(input.RefreshToken, s.cfg.JWT.RefreshSecret)
+	userID, err := utils.ValidateRefreshToken(input.RefreshToken, s.cfg.JWT.RefreshSecret, s.cfg.JWT.Leeway, s.clock.Now())
 	if err != nil {
-		return nil, fmt.Errorf("refresh token inválido: %w", err)
+		return nil, fmt.Errorf("refresh token: %w", err)
 	}
 
 	// 3. Verificar se refresh token existe no banco (não foi revogado)
-	tokenRecord, err := s.queries.GetRefreshToken(ctx, input.RefreshToken)
+	_, err = s.queries.GetRefreshToken(ctx, utils.HashToken(input.RefreshToken))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, fmt.Errorf("refresh token inválido ou expirado")
@@ -221,7 +376,10 @@ func (s *AuthService) RefreshToken(ctx context.Context, input types.RefreshToken
 		user.Username,
 		user.Email,
 		s.cfg.JWT.AccessSecret,
+		s.cfg.JWT.Issuer,
+		s.cfg.JWT.Audience,
 		s.cfg.JWT.AccessExpiration,
+		s.clock.Now(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao gerar access token: %w", err)
@@ -230,7 +388,8 @@ func (s *AuthService) RefreshToken(ctx context.Context, input types.RefreshToken
 	// 6. Retornar novos tokens
 	return &types.TokenPair{
 		AccessToken:  accessToken,
-		RefreshToken: tokenRecord.Token, // Mesmo refresh token
+		RefreshToken: input.RefreshToken, // Mesmo refresh token
+		ExpiresIn:    int64(s.cfg.JWT.AccessExpiration.Seconds()),
 	}, nil
 }
 
@@ -242,32 +401,155 @@ func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
 	}
 
 	// 2. Deletar refresh token do banco (revoga)
-	if err := s.queries.DeleteRefreshToken(ctx, refreshToken); err != nil {
+	if err := s.queries.DeleteRefreshToken(ctx, utils.HashToken(refreshToken)); err != nil {
 		return fmt.Errorf("erro ao revogar token: %w", err)
 	}
 
 	return nil
 }
 
+// Introspect estado de um token para outros serviços (RFC 7662), sem que
+// eles conheçam os segredos de assinatura. Token inválido, expirado,
+// revogado ou de usuário removido = inativo, sem dizer o motivo; erro só
+// em falha interna
+func (s *AuthService) Introspect(ctx context.Context, input types.IntrospectInput) (*types.IntrospectionResponse, error) {
+	if input.Token == "" {
+		return nil, fmt.Errorf("token é obrigatório")
+	}
+
+	checks := []func(context.Context, string) (*types.IntrospectionResponse, error){s.introspectAccess, s.introspectRefresh}
+	if input.TokenTypeHint == "refresh_token" {
+		checks[0], checks[1] = checks[1], checks[0]
+	}
+	for _, check := range checks {
+		resp, err := check(ctx, input.Token)
+		if err != nil {
+			return nil, err
+		}
+		if resp != nil {
+			return resp, nil
+		}
+	}
+	return &types.IntrospectionResponse{Active: false}, nil
+}
+
+// introspectAccess nil = não é um access token ativo
+func (s *AuthService) introspectAccess(ctx context.Context, token string) (*types.IntrospectionResponse, error) {
+	claims, err := utils.ValidateAccessToken(token, s.cfg.JWT.AccessSecret, s.cfg.JWT.Issuer, s.cfg.JWT.Audience, s.cfg.JWT.Leeway, s.clock.Now())
+	if err != nil {
+		return nil, nil
+	}
+	// Assinatura válida não basta: conta removida depois da emissão
+	user, err := s.activeUser(ctx, claims.UserID)
+	if user == nil || err != nil {
+		return nil, err
+	}
+	// Token de suporte vale enquanto a sessão não for revogada
+	if claims.Impersonation != nil {
+		sessionID, err := utils.StringToUUID(claims.Impersonation.SessionID)
+		if err != nil {
+			return nil, nil
+		}
+		if _, err := s.queries.GetActiveImpersonationSession(ctx, sessionID); err != nil {
+			if err == pgx.ErrNoRows {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("erro ao buscar sessão de suporte: %w", err)
+		}
+	}
+
+	resp := &types.IntrospectionResponse{
+		Active:    true,
+		TokenType: "access_token",
+		Subject:   claims.UserID,
+		Username:  user.Username,
+		Email:     user.Email,
+		Issuer:    claims.Issuer,
+		Audience:  claims.Audience,
+		JTI:       claims.ID,
+
+		Impersonation: claims.Impersonation,
+	}
+	if claims.ExpiresAt != nil {
+		resp.ExpiresAt = claims.ExpiresAt.Unix()
+	}
+	if claims.IssuedAt != nil {
+		resp.IssuedAt = claims.IssuedAt.Unix()
+	}
+	if claims.NotBefore != nil {
+		resp.NotBefore = claims.NotBefore.Unix()
+	}
+	return resp, nil
+}
+
+// introspectRefresh nil = não é um refresh token ativo (inclusive revogado por logout)
+func (s *AuthService) introspectRefresh(ctx context.Context, token string) (*types.IntrospectionResponse, error) {
+	userID, err := utils.ValidateRefreshToken(token, s.cfg.JWT.RefreshSecret, s.cfg.JWT.Leeway, s.clock.Now())
+	if err != nil {
+		return nil, nil
+	}
+	session, err := s.queries.GetRefreshToken(ctx, utils.HashToken(token))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar refresh token: %w", err)
+	}
+	user, err := s.activeUser(ctx, userID)
+	if user == nil || err != nil {
+		return nil, err
+	}
+
+	return &types.IntrospectionResponse{
+		Active:    true,
+		TokenType: "refresh_token",
+		Subject:   userID,
+		Username:  user.Username,
+		Email:     user.Email,
+		ExpiresAt: session.ExpiresAt.Time.Unix(),
+		IssuedAt:  session.CreatedAt.Time.Unix(),
+	}, nil
+}
+
+// activeUser nil = usuário inexistente ou removido
+func (s *AuthService) activeUser(ctx context.Context, userID string) (*repository.User, error) {
+	userUUID, err := utils.StringToUUID(userID)
+	if err != nil {
+		return nil, nil
+	}
+	user, err := s.queries.GetUserByID(ctx, userUUID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+	return &user, nil
+}
+
 // generateTokens gera access token e refresh token
 func (s *AuthService) generateTokens(userID pgtype.UUID, username, email string) (*types.TokenPair, error) {
-	// Access Token (1 hora)
+	// Access Token (JWT_ACCESS_TTL)
 	accessToken, err := utils.GenerateAccessToken(
 		utils.UUIDToString(userID),
 		username,
 		email,
 		s.cfg.JWT.AccessSecret,
+		s.cfg.JWT.Issuer,
+		s.cfg.JWT.Audience,
 		s.cfg.JWT.AccessExpiration,
+		s.clock.Now(),
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	// Refresh Token (7 dias)
+	// Refresh Token (JWT_REFRESH_TTL)
 	refreshToken, err := utils.GenerateRefreshToken(
 		utils.UUIDToString(userID),
 		s.cfg.JWT.RefreshSecret,
 		s.cfg.JWT.RefreshExpiration,
+		s.clock.Now(),
 	)
 	if err != nil {
 		return nil, err
@@ -276,23 +558,30 @@ func (s *AuthService) generateTokens(userID pgtype.UUID, username, email string)
 	return &types.TokenPair{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.cfg.JWT.AccessExpiration.Seconds()),
 	}, nil
 }
 
-// saveRefreshToken salva refresh token no banco
-func (s *AuthService) saveRefreshToken(ctx context.Context, userID pgtype.UUID, token string) error {
+// saveRefreshToken salva o hash do refresh token no banco (nunca o token em claro)
+func (s *AuthService) saveRefreshToken(ctx context.Context, userID pgtype.UUID, token string) (repository.RefreshToken, error) {
 	// Calcular expiração
 	expiresAt := pgtype.Timestamp{
-		Time:  time.Now().Add(s.cfg.JWT.RefreshExpiration),
+		Time:  s.clock.Now().Add(s.cfg.JWT.RefreshExpiration),
 		Valid: true,
 	}
 
 	// Salvar no banco
-	_, err := s.queries.CreateRefreshToken(ctx, repository.CreateRefreshTokenParams{
+	return s.queries.CreateRefreshToken(ctx, repository.CreateRefreshTokenParams{
 		UserID:    userID,
-		Token:     token,
+		TokenHash: utils.HashToken(token),
 		ExpiresAt: expiresAt,
 	})
+}
 
-	return err
+// recordLogin registra IP/dispositivo da sessão; falhas não bloqueiam o login
+func (s *AuthService) recordLogin(ctx context.Context, user repository.User, session repository.RefreshToken, deviceID string, client types.ClientInfo) {
+	if err := s.logins.RecordLogin(ctx, user, session.ID, deviceID, client); err != nil {
+		log.Printf("ERRO: registro de login: %v", err)
+		reporter.CaptureError(ctx, err, map[string]string{"component": "login_alert"})
+	}
 }
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"chat-kafka-go/internal/config/configtest"
+	"chat-kafka-go/internal/disposable"
+	"chat-kafka-go/internal/mailer"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/internal/repository/repotest"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// TestRefreshTokenTTL refresh token vale por JWT_REFRESH_TTL e o access
+// token renovado por JWT_ACCESS_TTL, no relógio do serviço
+func TestRefreshTokenTTL(t *testing.T) {
+	cfg := configtest.New(t)
+	cfg.JWT.AccessExpiration = 15 * time.Minute
+	cfg.JWT.RefreshExpiration = 24 * time.Hour
+
+	db := repotest.New()
+	db.On("GetRefreshToken", func([]interface{}) repotest.Result {
+		return repotest.Rows(repotest.Row(repository.RefreshToken{}))
+	})
+	db.On("GetUserByID", func(args []interface{}) repotest.Result {
+		return repotest.Rows(repotest.Row(repository.User{Username: "maria", Email: "maria@example.com"}))
+	})
+	auth := NewAuthService(repository.New(db), nil, disposable.NewBlocklist("", 0), nil, nil, nil, nil, nil, cfg)
+	manual := clock.NewManual(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	auth.SetClock(manual)
+
+	tokens, err := auth.generateTokens(mustUUID(t, testUserID), "maria", "maria@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokens.ExpiresIn != int64((15 * time.Minute).Seconds()) {
+		t.Fatalf("ExpiresIn = %d; esperado 900", tokens.ExpiresIn)
+	}
+
+	// Perto do fim do refresh: o access token novo vale JWT_ACCESS_TTL a partir de agora
+	manual.Advance(cfg.JWT.RefreshExpiration - time.Minute)
+	renewed, err := auth.RefreshToken(context.Background(), types.RefreshTokenInput{RefreshToken: tokens.RefreshToken})
+	if err != nil {
+		t.Fatalf("RefreshToken antes do TTL: %v", err)
+	}
+	claims, err := utils.ValidateAccessToken(renewed.AccessToken, cfg.JWT.AccessSecret, cfg.JWT.Issuer, cfg.JWT.Audience, 0, manual.Now())
+	if err != nil {
+		t.Fatalf("access token renovado: %v", err)
+	}
+	if want := manual.Now().Add(cfg.JWT.AccessExpiration); !claims.ExpiresAt.Time.Equal(want) {
+		t.Fatalf("exp = %v; esperado %v", claims.ExpiresAt.Time, want)
+	}
+
+	manual.Advance(time.Minute + cfg.JWT.Leeway + time.Second)
+	if _, err := auth.RefreshToken(context.Background(), types.RefreshTokenInput{RefreshToken: tokens.RefreshToken}); !errors.Is(err, utils.ErrTokenExpired) {
+		t.Fatalf("RefreshToken depois do TTL: err = %v; esperado %v", err, utils.ErrTokenExpired)
+	}
+}
+
+// registerDB banco falso do cadastro: email e username livres e o convite
+// por email de inviteEmail
+func registerDB(t *testing.T, inviteEmail string) *repotest.DB {
+	userID := mustUUID(t, testUserID)
+	noRows := func([]interface{}) repotest.Result { return repotest.Rows() }
+	db := repotest.New()
+	db.On("GetUserByEmail", noRows)
+	db.On("GetUserByUsername", noRows)
+	db.On("GetActiveUsernameReservation", noRows)
+	db.On("GetValidInvitationByTokenHash", func([]interface{}) repotest.Result {
+		return repotest.Rows(repotest.Row(repository.Invitation{Email: &inviteEmail}))
+	})
+	db.On("CreateUser", func(args []interface{}) repotest.Result {
+		return repotest.Rows(repotest.Row(repository.User{
+			ID:       userID,
+			Username: args[0].(string),
+			Email:    args[1].(string),
+		}))
+	})
+	db.On("UpsertContactHash", func([]interface{}) repotest.Result { return repotest.Result{RowsAffected: 1} })
+	return db
+}
+
+// newRegisterService cadastro com confirmação de email obrigatória (para
+// antes da sessão) e convites
+func newRegisterService(t *testing.T, db *repotest.DB) *AuthService {
+	cfg := configtest.New(t)
+	cfg.User.EmailVerificationRequired = "login"
+	queries := repository.New(db)
+	return NewAuthService(queries, NewInvitationService(queries, mailer.LogMailer{}, cfg), disposable.NewBlocklist("", 0),
+		nil, nil, NewEmailVerificationService(queries, mailer.LogMailer{}, nil, cfg), nil, nil, cfg)
+}
+
+func TestRegisterRejectsInvalidInvitationBeforeCreatingUser(t *testing.T) {
+	db := registerDB(t, "outra@example.com")
+	auth := newRegisterService(t, db)
+
+	_, err := auth.Register(context.Background(), types.RegisterInput{
+		Username:    "maria",
+		Email:       "maria@example.com",
+		Password:    "segredo123",
+		InviteToken: "convite",
+	}, types.ClientInfo{})
+	if err == nil {
+		t.Fatal("cadastro aceito com convite de outro email")
+	}
+	if calls := db.Calls("CreateUser"); len(calls) != 0 {
+		t.Fatal("usuário criado antes de validar o convite")
+	}
+}
+
+func TestRegisterSucceedsWhenAcceptInvitationFails(t *testing.T) {
+	db := registerDB(t, "maria@example.com")
+	db.On("CreateReferral", func([]interface{}) repotest.Result {
+		return repotest.Result{Err: errors.New("conexão perdida")}
+	})
+	auth := newRegisterService(t, db)
+
+	resp, err := auth.Register(context.Background(), types.RegisterInput{
+		Username:    "maria",
+		Email:       "maria@example.com",
+		Password:    "segredo123",
+		InviteToken: "convite",
+	}, types.ClientInfo{})
+	if err != nil {
+		t.Fatalf("Register: %v (usuário já criado)", err)
+	}
+	if resp.User == nil || resp.User.ID != testUserID {
+		t.Fatalf("User = %+v; esperado %s", resp.User, testUserID)
+	}
+	if calls := db.Calls("CreateReferral"); len(calls) != 1 {
+		t.Fatalf("CreateReferral chamado %d vezes; esperado 1", len(calls))
+	}
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"chat-kafka-go/internal/metrics"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/events"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+	"chat-kafka-go/pkg/validate"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxAutoReplyLength limite do texto da resposta automática
+const maxAutoReplyLength = 1000
+
+// ErrAutoReplyNotFound usuário sem resposta automática configurada
+var ErrAutoReplyNotFound = errors.New("resposta automática não configurada")
+
+// AutoReplyService resposta automática (férias/ausência): configurada pelo
+// usuário e enviada pelo consumidor quando chega uma DM dentro da janela,
+// no máximo uma vez por conversa por dia (UTC)
+type AutoReplyService struct {
+	queries  *repository.Queries
+	messages *MessageService
+	privacy  *PrivacyService
+	clock    clock.Clock // Janela ativa e dia do limite
+}
+
+// NewAutoReplyService cria nova instância do service
+func NewAutoReplyService(queries *repository.Queries, messages *MessageService, privacy *PrivacyService) *AutoReplyService {
+	return &AutoReplyService{
+		queries:  queries,
+		messages: messages,
+		privacy:  privacy,
+		clock:    clock.System,
+	}
+}
+
+// SetClock troca o relógio (testes)
+func (s *AutoReplyService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Get retorna a resposta automática do usuário
+func (s *AutoReplyService) Get(ctx context.Context, userID string) (*types.AutoReplySettingsResponse, error) {
+	if err := authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	uuid, err := utils.StringToUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	reply, err := s.queries.GetAutoReply(ctx, uuid)
+	if err == pgx.ErrNoRows {
+		return nil, ErrAutoReplyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar resposta automática: %w", err)
+	}
+	return toAutoReplySettingsResponse(reply, s.clock.Now()), nil
+}
+
+// Update liga ou substitui a resposta automática
+func (s *AutoReplyService) Update(ctx context.Context, input types.UpdateAutoReplyInput) (*types.AutoReplySettingsResponse, error) {
+	if err := authorize(ctx, input.UserID); err != nil {
+		return nil, err
+	}
+	input.Message = validate.Text(input.Message)
+	if input.Message == "" {
+		return nil, fmt.Errorf("message é obrigatório")
+	}
+	if !validate.Length(input.Message, 0, maxAutoReplyLength) {
+		return nil, fmt.Errorf("resposta automática muito longa (máximo %d caracteres)", maxAutoReplyLength)
+	}
+
+	startsAt, err := parseWindowTime("starts_at", input.StartsAt)
+	if err != nil {
+		return nil, err
+	}
+	endsAt, err := parseWindowTime("ends_at", input.EndsAt)
+	if err != nil {
+		return nil, err
+	}
+	if endsAt.Valid && startsAt.Valid && !endsAt.Time.After(startsAt.Time) {
+		return nil, fmt.Errorf("ends_at deve ser posterior a starts_at")
+	}
+	if endsAt.Valid && !endsAt.Time.After(s.clock.Now()) {
+		return nil, fmt.Errorf("ends_at já passou")
+	}
+
+	uuid, err := utils.StringToUUID(input.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	reply, err := s.queries.UpsertAutoReply(ctx, repository.UpsertAutoReplyParams{
+		UserID:      uuid,
+		Message:     input.Message,
+		StartsAt:    startsAt,
+		EndsAt:      endsAt,
+		FriendsOnly: input.FriendsOnly,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao salvar resposta automática: %w", err)
+	}
+	return toAutoReplySettingsResponse(reply, s.clock.Now()), nil
+}
+
+// Disable desliga a resposta automática (idempotente)
+func (s *AutoReplyService) Disable(ctx context.Context, userID string) error {
+	if err := authorize(ctx, userID); err != nil {
+		return err
+	}
+	uuid, err := utils.StringToUUID(userID)
+	if err != nil {
+		return fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	if _, err := s.queries.DeleteAutoReply(ctx, uuid); err != nil {
+		return fmt.Errorf("erro ao remover resposta automática: %w", err)
+	}
+	return nil
+}
+
+// Respond envia a resposta automática do destinatário de uma DM recebida,
+// se houver uma ativa; chamado pelo consumidor de mensagens. Recusas
+// definitivas (privacidade, amizade) não são erro: o evento não é repetido
+func (s *AutoReplyService) Respond(ctx context.Context, event events.MessageSent) error {
+	// Respostas automáticas, anúncios e contas do serviço nunca são respondidos
+	// (nem respondem): duas contas ausentes não entram em laço
+	if event.AutoReply || event.AnnouncementID != "" || event.SenderID == event.ReceiverID {
+		return nil
+	}
+	for _, id := range []string{event.SenderID, event.ReceiverID} {
+		if id == SupportUserID || id == SystemUserID {
+			return nil
+		}
+	}
+
+	owner, err := utils.StringToUUID(event.ReceiverID)
+	if err != nil {
+		return fmt.Errorf("receiver_id inválido: %w", err)
+	}
+	sender, err := utils.StringToUUID(event.SenderID)
+	if err != nil {
+		return fmt.Errorf("sender_id inválido: %w", err)
+	}
+
+	reply, err := s.queries.GetAutoReply(ctx, owner)
+	if err == pgx.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao buscar resposta automática: %w", err)
+	}
+
+	// Janela avaliada no horário da mensagem, não no do processamento
+	// (evento atrasado no retry não responde fora da ausência)
+	if !autoReplyActive(reply, time.Unix(event.Timestamp, 0)) {
+		return nil
+	}
+	if reply.FriendsOnly {
+		friends, err := s.privacy.areFriends(ctx, owner, sender)
+		if err != nil {
+			return err
+		}
+		if !friends {
+			return nil
+		}
+	}
+
+	claim := repository.ClaimAutoReplyParams{
+		UserID:   owner,
+		SenderID: sender,
+		Day:      pgtype.Date{Time: s.clock.Now().UTC().Truncate(24 * time.Hour), Valid: true},
+	}
+	claimed, err := s.queries.ClaimAutoReply(ctx, claim)
+	if err != nil {
+		return fmt.Errorf("erro ao reservar resposta automática: %w", err)
+	}
+	if claimed == 0 {
+		metrics.AutoRepliesTotal.WithLabelValues("throttled").Inc()
+		return nil
+	}
+
+	// Enviada em nome do dono da resposta, que não está na requisição
+	_, err = s.messages.SendMessage(reqctx.WithSystem(ctx), types.SendMessageInput{
+		SenderID:   event.ReceiverID,
+		ReceiverID: event.SenderID,
+		Content:    reply.Message,
+		AutoReply:  true,
+	})
+	if errors.Is(err, ErrFriendshipRequired) {
+		metrics.AutoRepliesTotal.WithLabelValues("refused").Inc()
+		return nil
+	}
+	if err != nil {
+		release := repository.ReleaseAutoReplyParams(claim)
+		if releaseErr := s.queries.ReleaseAutoReply(ctx, release); releaseErr != nil {
+			return fmt.Errorf("erro ao enviar resposta automática: %w (reserva mantida: %v)", err, releaseErr)
+		}
+		return fmt.Errorf("erro ao enviar resposta automática: %w", err)
+	}
+	metrics.AutoRepliesTotal.WithLabelValues("sent").Inc()
+	return nil
+}
+
+// autoReplyActive indica se at está dentro da janela configurada
+func autoReplyActive(reply repository.AutoReply, at time.Time) bool {
+	if reply.StartsAt.Valid && at.Before(reply.StartsAt.Time) {
+		return false
+	}
+	if reply.EndsAt.Valid && !at.Before(reply.EndsAt.Time) {
+		return false
+	}
+	return true
+}
+
+// parseWindowTime converte limite da janela (RFC 3339, vazio = sem limite)
+func parseWindowTime(field, value string) (pgtype.Timestamp, error) {
+	if value == "" {
+		return pgtype.Timestamp{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return pgtype.Timestamp{}, fmt.Errorf("%s inválido (RFC 3339): %w", field, err)
+	}
+	return pgtype.Timestamp{Time: t.UTC(), Valid: true}, nil
+}
+
+func toAutoReplySettingsResponse(reply repository.AutoReply, now time.Time) *types.AutoReplySettingsResponse {
+	resp := &types.AutoReplySettingsResponse{
+		Message:     reply.Message,
+		FriendsOnly: reply.FriendsOnly,
+		Active:      autoReplyActive(reply, now),
+		UpdatedAt:   reply.UpdatedAt.Time.Format(time.RFC3339),
+	}
+	if reply.StartsAt.Valid {
+		resp.StartsAt = reply.StartsAt.Time.Format(time.RFC3339)
+	}
+	if reply.EndsAt.Valid {
+		resp.EndsAt = reply.EndsAt.Time.Format(time.RFC3339)
+	}
+	return resp
+}
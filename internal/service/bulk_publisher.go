@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/pkg/events"
+)
+
+// BulkPublisher publica jobs de baixa prioridade no tópico bulk, consumido
+// por um pool de workers próprio (worker.BulkDispatcher)
+type BulkPublisher struct {
+	producer KafkaProducer
+	topic    string
+}
+
+// NewBulkPublisher cria nova instância do publicador
+func NewBulkPublisher(producer KafkaProducer, cfg *config.Config) *BulkPublisher {
+	return &BulkPublisher{
+		producer: producer,
+		topic:    cfg.Kafka.BulkTopic,
+	}
+}
+
+// Enqueue publica job do tipo informado; jobs com a mesma chave são
+// processados em ordem
+func (p *BulkPublisher) Enqueue(ctx context.Context, jobType, key string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar job %s: %w", jobType, err)
+	}
+
+	event, err := events.Marshal(events.BulkJob{
+		Type:      jobType,
+		Payload:   data,
+		CreatedAt: time.Now().Unix(),
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao serializar job %s: %w", jobType, err)
+	}
+
+	if err := p.producer.SendMessage(ctx, p.topic, key, event); err != nil {
+		return fmt.Errorf("erro ao publicar job %s: %w", jobType, err)
+	}
+	return nil
+}
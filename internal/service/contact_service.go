@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/metrics"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// contactBackfillBatch usuários indexados por consulta no boot
+const contactBackfillBatch = 500
+
+// ContactService sincroniza agenda do cliente com usuários cadastrados
+// O servidor só recebe hashes de email e guarda HMAC deles com o pepper
+// (CONTACT_HASH_PEPPER); consultas têm limite por chamada e por dia
+type ContactService struct {
+	queries *repository.Queries
+	cfg     *config.SecurityConfig
+	clock   clock.Clock // Dia corrente (UTC) do limite diário
+}
+
+// NewContactService cria nova instância do service
+func NewContactService(queries *repository.Queries, cfg *config.SecurityConfig) *ContactService {
+	return &ContactService{
+		queries: queries,
+		cfg:     cfg,
+		clock:   clock.System,
+	}
+}
+
+// SetClock troca o relógio (testes)
+func (s *ContactService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Index grava o hash do email do usuário (cadastro e backfill)
+func (s *ContactService) Index(ctx context.Context, userID pgtype.UUID, email string) error {
+	err := s.queries.UpsertContactHash(ctx, repository.UpsertContactHashParams{
+		Kind:   utils.ContactKindEmail,
+		Hash:   emailContactHash(s.cfg.ContactHashPepper, email),
+		UserID: userID,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao salvar hash de contato: %w", err)
+	}
+	return nil
+}
+
+// Backfill indexa usuários ainda sem hash (após a migração 040 ou troca do
+// pepper com contact_hashes esvaziada)
+func (s *ContactService) Backfill(ctx context.Context) (int, error) {
+	indexed := 0
+	for {
+		users, err := s.queries.ListUsersWithoutContactHash(ctx, contactBackfillBatch)
+		if err != nil {
+			return indexed, fmt.Errorf("erro ao listar usuários sem hash de contato: %w", err)
+		}
+		for _, user := range users {
+			if err := s.Index(ctx, user.ID, user.Email); err != nil {
+				return indexed, err
+			}
+			indexed++
+		}
+		if len(users) < contactBackfillBatch {
+			return indexed, nil
+		}
+	}
+}
+
+// Sync retorna quais contatos (por hash) já estão cadastrados. Hashes
+// repetidos (inclusive em maiúsculas) contam uma vez no limite diário
+func (s *ContactService) Sync(ctx context.Context, input types.ContactSyncInput) ([]types.ContactMatch, error) {
+	if err := authorize(ctx, input.UserID); err != nil {
+		return nil, err
+	}
+	if len(input.EmailHashes) > s.cfg.ContactSyncMaxHashes {
+		return nil, fmt.Errorf("máximo de %d contatos por sincronização", s.cfg.ContactSyncMaxHashes)
+	}
+
+	// Hash guardado → hash do cliente, para responder no formato enviado
+	byKey := make(map[string]string, len(input.EmailHashes))
+	keys := make([]string, 0, len(input.EmailHashes))
+	for _, h := range input.EmailHashes {
+		h = strings.ToLower(h)
+		if !isSHA256Hex(h) {
+			return nil, fmt.Errorf("hash inválido (esperado SHA-256 hex): %s", h)
+		}
+		key := utils.KeyContactHash(s.cfg.ContactHashPepper, h)
+		if _, ok := byKey[key]; ok {
+			continue
+		}
+		byKey[key] = h
+		keys = append(keys, key)
+	}
+
+	matches := []types.ContactMatch{}
+	if len(keys) == 0 {
+		return matches, nil
+	}
+
+	userUUID, err := utils.StringToUUID(input.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+	if err := s.consumeLookups(ctx, userUUID, len(keys)); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.queries.ListUsersByContactHashes(ctx, repository.ListUsersByContactHashesParams{
+		Kind:   utils.ContactKindEmail,
+		Hashes: keys,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar contatos: %w", err)
+	}
+
+	for _, row := range rows {
+		userID := utils.UUIDToString(row.ID)
+		if userID == input.UserID {
+			continue // O próprio usuário na agenda
+		}
+		matches = append(matches, types.ContactMatch{
+			Kind:     utils.ContactKindEmail,
+			Hash:     byKey[row.Hash],
+			UserID:   userID,
+			Username: row.Username,
+		})
+	}
+	return matches, nil
+}
+
+// consumeLookups soma n hashes ao limite diário do usuário (um único UPSERT)
+func (s *ContactService) consumeLookups(ctx context.Context, userID pgtype.UUID, n int) error {
+	now := s.clock.Now().UTC()
+	_, err := s.queries.ConsumeContactLookups(ctx, repository.ConsumeContactLookupsParams{
+		UserID:    userID,
+		Day:       pgtype.Date{Time: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), Valid: true},
+		Hashes:    int32(n),
+		MaxHashes: int32(s.cfg.ContactSyncDailyHashes),
+	})
+	if err == pgx.ErrNoRows {
+		metrics.QuotaExceededTotal.WithLabelValues("contact_lookups").Inc()
+		return fmt.Errorf("%w: limite de %d contatos consultados por dia", ErrQuotaExceeded, s.cfg.ContactSyncDailyHashes)
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao contar consulta de contatos: %w", err)
+	}
+	return nil
+}
+
+// emailContactHash hash guardado para o email (HMAC do hash enviado pelos clientes)
+func emailContactHash(pepper, email string) string {
+	return utils.KeyContactHash(pepper, utils.HashEmail(email))
+}
+
+// isSHA256Hex valida hash SHA-256 em hexadecimal minúsculo
+func isSHA256Hex(s string) bool {
+	if len(s) != 64 || strings.ToLower(s) != s {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
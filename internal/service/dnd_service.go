@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxSnoozeHours limite da soneca instantânea
+const maxSnoozeHours = 24 * 7
+
+// DNDService gerencia horários de não perturbe
+// Afeta apenas push/email; entrega via WebSocket continua normal
+type DNDService struct {
+	queries *repository.Queries
+	clock   clock.Clock // Soneca e janelas de silêncio
+}
+
+// NewDNDService cria nova instância do service
+func NewDNDService(queries *repository.Queries) *DNDService {
+	return &DNDService{
+		queries: queries,
+		clock:   clock.System,
+	}
+}
+
+// SetClock troca o relógio (testes)
+func (s *DNDService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// GetSettings retorna configurações de não perturbe do usuário
+func (s *DNDService) GetSettings(ctx context.Context, userID string) (*types.DNDSettingsResponse, error) {
+	if err := authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	uuid, err := utils.StringToUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	settings, err := s.queries.GetDNDSettings(ctx, uuid)
+	if err == pgx.ErrNoRows {
+		return &types.DNDSettingsResponse{Timezone: "UTC", Windows: []types.DNDWindow{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar não perturbe: %w", err)
+	}
+	return toDNDSettingsResponse(settings, s.clock.Now())
+}
+
+// UpdateSchedule substitui as janelas semanais
+func (s *DNDService) UpdateSchedule(ctx context.Context, input types.UpdateDNDScheduleInput) (*types.DNDSettingsResponse, error) {
+	if err := authorize(ctx, input.UserID); err != nil {
+		return nil, err
+	}
+	if input.Timezone == "" {
+		input.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(input.Timezone); err != nil {
+		return nil, fmt.Errorf("timezone inválido: %s", input.Timezone)
+	}
+	for _, w := range input.Windows {
+		if err := validateDNDWindow(w); err != nil {
+			return nil, err
+		}
+	}
+
+	uuid, err := utils.StringToUUID(input.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	if input.Windows == nil {
+		input.Windows = []types.DNDWindow{}
+	}
+	windows, err := json.Marshal(input.Windows)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar janelas: %w", err)
+	}
+
+	settings, err := s.queries.UpsertDNDSchedule(ctx, repository.UpsertDNDScheduleParams{
+		UserID:   uuid,
+		Timezone: input.Timezone,
+		Windows:  windows,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao salvar não perturbe: %w", err)
+	}
+	return toDNDSettingsResponse(settings, s.clock.Now())
+}
+
+// Snooze silencia notificações pelas próximas N horas (0 cancela a soneca)
+func (s *DNDService) Snooze(ctx context.Context, input types.SnoozeInput) (*types.DNDSettingsResponse, error) {
+	if err := authorize(ctx, input.UserID); err != nil {
+		return nil, err
+	}
+	if input.Hours < 0 || input.Hours > maxSnoozeHours {
+		return nil, fmt.Errorf("hours deve estar entre 0 e %d", maxSnoozeHours)
+	}
+
+	uuid, err := utils.StringToUUID(input.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	until := pgtype.Timestamp{}
+	if input.Hours > 0 {
+		until = pgtype.Timestamp{Time: s.clock.Now().Add(time.Duration(input.Hours) * time.Hour), Valid: true}
+	}
+
+	settings, err := s.queries.UpsertDNDSnooze(ctx, repository.UpsertDNDSnoozeParams{
+		UserID:       uuid,
+		SnoozedUntil: until,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao salvar soneca: %w", err)
+	}
+	return toDNDSettingsResponse(settings, s.clock.Now())
+}
+
+// IsQuiet indica se notificações push/email do usuário devem ser suprimidas agora
+func (s *DNDService) IsQuiet(ctx context.Context, userID pgtype.UUID, now time.Time) (bool, error) {
+	settings, err := s.queries.GetDNDSettings(ctx, userID)
+	if err == pgx.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("erro ao buscar não perturbe: %w", err)
+	}
+
+	if settings.SnoozedUntil.Valid && now.Before(settings.SnoozedUntil.Time) {
+		return true, nil
+	}
+
+	var windows []types.DNDWindow
+	if err := json.Unmarshal(settings.Windows, &windows); err != nil {
+		return false, fmt.Errorf("janelas inválidas: %w", err)
+	}
+
+	loc, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	weekday := int(local.Weekday())
+	yesterday := (weekday + 6) % 7
+
+	for _, w := range windows {
+		start, end := parseClock(w.Start), parseClock(w.End)
+		if start < end {
+			if w.Weekday == weekday && minute >= start && minute < end {
+				return true, nil
+			}
+			continue
+		}
+		// Atravessa a meia-noite: parte do dia da janela + manhã do dia seguinte
+		if w.Weekday == weekday && minute >= start {
+			return true, nil
+		}
+		if w.Weekday == yesterday && minute < end {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// validateDNDWindow valida dia da semana e horários HH:MM
+func validateDNDWindow(w types.DNDWindow) error {
+	if w.Weekday < 0 || w.Weekday > 6 {
+		return fmt.Errorf("weekday deve estar entre 0 e 6")
+	}
+	if parseClock(w.Start) < 0 || parseClock(w.End) < 0 {
+		return fmt.Errorf("horários devem estar no formato HH:MM")
+	}
+	if w.Start == w.End {
+		return fmt.Errorf("início e fim da janela não podem ser iguais")
+	}
+	return nil
+}
+
+// parseClock converte HH:MM em minutos desde a meia-noite (-1 se inválido)
+func parseClock(s string) int {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return -1
+	}
+	return t.Hour()*60 + t.Minute()
+}
+
+func toDNDSettingsResponse(settings repository.UserDndSetting, now time.Time) (*types.DNDSettingsResponse, error) {
+	windows := []types.DNDWindow{}
+	if err := json.Unmarshal(settings.Windows, &windows); err != nil {
+		return nil, fmt.Errorf("janelas inválidas: %w", err)
+	}
+
+	resp := &types.DNDSettingsResponse{
+		Timezone: settings.Timezone,
+		Windows:  windows,
+	}
+	if settings.SnoozedUntil.Valid && settings.SnoozedUntil.Time.After(now) {
+		resp.SnoozedUntil = settings.SnoozedUntil.Time.Format(time.RFC3339)
+	}
+	return resp, nil
+}
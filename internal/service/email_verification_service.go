@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/mailer"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrEmailNotVerified ação exige email confirmado (EMAIL_VERIFICATION_REQUIRED)
+var ErrEmailNotVerified = errors.New("confirme seu email para continuar")
+
+// ErrVerificationTokenInvalid link de confirmação inexistente, usado ou expirado
+var ErrVerificationTokenInvalid = errors.New("link de confirmação inválido ou expirado")
+
+// EmailVerificationService envia e confirma os links de verificação de email
+type EmailVerificationService struct {
+	queries *repository.Queries
+	mailer  mailer.Mailer
+	audit   *AuditService
+	cfg     *config.Config
+	clock   clock.Clock // Expiração do link e intervalo entre reenvios
+}
+
+// NewEmailVerificationService cria nova instância do service
+func NewEmailVerificationService(queries *repository.Queries, mailer mailer.Mailer, audit *AuditService, cfg *config.Config) *EmailVerificationService {
+	return &EmailVerificationService{
+		queries: queries,
+		mailer:  mailer,
+		audit:   audit,
+		cfg:     cfg,
+		clock:   clock.System,
+	}
+}
+
+// SetClock troca o relógio (testes)
+func (s *EmailVerificationService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// RequiredForLogin login exige email confirmado
+func (s *EmailVerificationService) RequiredForLogin() bool {
+	return s.cfg.User.EmailVerificationRequired == "login"
+}
+
+// RequiredForMessaging envio de mensagens exige email confirmado (também
+// no modo login: contas antigas ou sessões anteriores à mudança)
+func (s *EmailVerificationService) RequiredForMessaging() bool {
+	mode := s.cfg.User.EmailVerificationRequired
+	return mode == "messaging" || mode == "login"
+}
+
+// RequireVerified retorna ErrEmailNotVerified se o usuário não confirmou o email
+func (s *EmailVerificationService) RequireVerified(ctx context.Context, userID pgtype.UUID) error {
+	verified, err := s.queries.IsEmailVerified(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("erro ao verificar confirmação de email: %w", err)
+	}
+	if !verified {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
+
+// Send cria token e envia o link de confirmação; links anteriores deixam de valer
+func (s *EmailVerificationService) Send(ctx context.Context, user repository.User) error {
+	token, err := utils.GenerateSecureToken()
+	if err != nil {
+		return err
+	}
+
+	if err := s.queries.InvalidateEmailVerificationTokens(ctx, user.ID); err != nil {
+		return fmt.Errorf("erro ao invalidar links anteriores: %w", err)
+	}
+	_, err = s.queries.CreateEmailVerificationToken(ctx, repository.CreateEmailVerificationTokenParams{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(token),
+		Email:     user.Email,
+		ExpiresAt: pgtype.Timestamp{Time: s.clock.Now().Add(s.cfg.User.EmailVerificationTTL), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao criar link de confirmação: %w", err)
+	}
+
+	link := fmt.Sprintf("%s/verify-email?token=%s", s.cfg.Mail.BaseURL, url.QueryEscape(token))
+	body := fmt.Sprintf("Olá %s,\n\nConfirme seu email: %s\n\nO link vale por %s. "+
+		"Se você não criou esta conta, ignore esta mensagem.\n", user.Username, link, s.cfg.User.EmailVerificationTTL)
+	if err := s.mailer.Send(ctx, user.Email, "Confirme seu email", body); err != nil {
+		return fmt.Errorf("erro ao enviar link de confirmação: %w", err)
+	}
+	return nil
+}
+
+// Verify confirma o email do token (uso único)
+func (s *EmailVerificationService) Verify(ctx context.Context, token, ip string) error {
+	if token == "" {
+		return fmt.Errorf("token é obrigatório")
+	}
+
+	record, err := s.queries.GetPendingEmailVerificationToken(ctx, utils.HashToken(token))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return ErrVerificationTokenInvalid
+		}
+		return fmt.Errorf("erro ao buscar link de confirmação: %w", err)
+	}
+
+	// Dois cliques simultâneos: só um consome o token
+	rows, err := s.queries.MarkEmailVerificationTokenUsed(ctx, record.ID)
+	if err != nil {
+		return fmt.Errorf("erro ao consumir link de confirmação: %w", err)
+	}
+	if rows == 0 {
+		return ErrVerificationTokenInvalid
+	}
+
+	rows, err = s.queries.MarkEmailVerified(ctx, repository.MarkEmailVerifiedParams{
+		ID:    record.UserID,
+		Email: record.Email,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao confirmar email: %w", err)
+	}
+	if rows == 0 {
+		// Email trocado (ou conta removida) depois do envio
+		return ErrVerificationTokenInvalid
+	}
+
+	s.audit.Record(ctx, record.UserID, AuditEmailVerified, ip, 0, nil)
+	return nil
+}
+
+// Resend reenvia o link para o email, respeitando o intervalo mínimo. Email
+// desconhecido ou já confirmado não gera erro (a resposta não revela contas)
+func (s *EmailVerificationService) Resend(ctx context.Context, email string) error {
+	if email == "" {
+		return fmt.Errorf("email é obrigatório")
+	}
+
+	user, err := s.queries.GetUserByEmail(ctx, email)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+		return fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+	if user.EmailVerifiedAt.Valid {
+		return nil
+	}
+
+	last, err := s.queries.GetLatestEmailVerificationToken(ctx, user.ID)
+	if err != nil && err != pgx.ErrNoRows {
+		return fmt.Errorf("erro ao buscar último link: %w", err)
+	}
+	if err == nil && s.clock.Now().Sub(last.CreatedAt.Time) < s.cfg.User.EmailVerificationResendInterval {
+		log.Printf("Reenvio de confirmação para %s ignorado: intervalo mínimo", user.Username)
+		return nil
+	}
+
+	return s.Send(ctx, user)
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/eventbus"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/events"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// HistoryCache LRU em memória das mensagens mais recentes de cada conversa
+// ativa: "abrir conversa, carregar as últimas 50" não vai ao banco
+// Com o roteamento do cluster o usuário volta sempre à mesma instância, então
+// o cache local acerta. Nova mensagem (evento do barramento, consumer group
+// próprio por instância) invalida a conversa; status e anexos mudados em
+// outra instância envelhecem no máximo até o TTL
+type HistoryCache struct {
+	mu        sync.Mutex
+	capacity  int           // Conversas
+	size      int           // Mensagens por conversa
+	ttl       time.Duration // 0 = sem expiração
+	order     *list.List    // Frente = usada mais recentemente
+	entries   map[string]*list.Element
+	byMessage map[pgtype.UUID]string // Mensagem → conversa (invalidação por status)
+	loading   map[string]*historyLoad
+}
+
+// historyEntry mensagens recentes de uma conversa
+type historyEntry struct {
+	key         string
+	messages    []repository.Message // Mais recentes primeiro (até size)
+	attachments map[pgtype.UUID][]types.AttachmentResponse
+	loadedAt    time.Time
+}
+
+// historyLoad consultas ao banco em andamento para a conversa; invalidação
+// durante a consulta descarta o resultado (seria gravado já desatualizado)
+type historyLoad struct {
+	pending int
+	stale   bool
+}
+
+// NewHistoryCache cria o cache; nil com HISTORY_CACHE_CONVERSATIONS=0
+// (os métodos aceitam receptor nil e não fazem nada)
+func NewHistoryCache(cfg *config.HistoryConfig) *HistoryCache {
+	if cfg.CacheConversations <= 0 {
+		return nil
+	}
+	return &HistoryCache{
+		capacity:  cfg.CacheConversations,
+		size:      cfg.CacheMessages,
+		ttl:       cfg.CacheTTL,
+		order:     list.New(),
+		entries:   map[string]*list.Element{},
+		byMessage: map[pgtype.UUID]string{},
+		loading:   map[string]*historyLoad{},
+	}
+}
+
+// conversationKey chave da conversa, igual nos dois sentidos
+func conversationKey(a, b pgtype.UUID) string {
+	return utils.ConversationKey(utils.UUIDToString(a), utils.UUIDToString(b))
+}
+
+// conversation par simétrico de usuários: (a, b) e (b, a) são a mesma
+// conversa nas consultas (LEAST/GREATEST) e na chave do cache
+type conversation struct {
+	a, b pgtype.UUID
+}
+
+// key chave do cache, igual à chave de partição do tópico
+func (c conversation) key() string {
+	return conversationKey(c.a, c.b)
+}
+
+// covers indica se a página cabe nas mensagens guardadas por conversa
+func (c *HistoryCache) covers(offset, limit int) bool {
+	return c != nil && offset+limit <= c.size
+}
+
+// get página da conversa a partir de cutoff (mensagens e anexos) se estiver
+// no cache
+func (c *HistoryCache) get(key string, cutoff time.Time, offset, limit int) ([]repository.Message, map[pgtype.UUID][]types.AttachmentResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, nil, false
+	}
+	entry := el.Value.(*historyEntry)
+	if c.ttl > 0 && time.Since(entry.loadedAt) > c.ttl {
+		c.remove(el)
+		return nil, nil, false
+	}
+	c.order.MoveToFront(el)
+
+	messages := retainedSince(entry.messages, cutoff)
+	if offset >= len(messages) {
+		return []repository.Message{}, entry.attachments, true
+	}
+	end := min(offset+limit, len(messages))
+	return append([]repository.Message(nil), messages[offset:end]...), entry.attachments, true
+}
+
+// retainedSince mensagens a partir de cutoff; a lista vem das mais recentes
+// para as mais antigas, então é um prefixo (zero = todas)
+func retainedSince(messages []repository.Message, cutoff time.Time) []repository.Message {
+	n := sort.Search(len(messages), func(i int) bool {
+		return messages[i].CreatedAt.Time.Before(cutoff)
+	})
+	return messages[:n]
+}
+
+// begin marca consulta ao banco em andamento; toda chamada termina em put
+func (c *HistoryCache) begin(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	load, ok := c.loading[key]
+	if !ok {
+		load = &historyLoad{}
+		c.loading[key] = load
+	}
+	load.pending++
+}
+
+// put grava o resultado da consulta iniciada em begin; messages nil (erro na
+// consulta) ou invalidação no meio do caminho só encerram a consulta
+func (c *HistoryCache) put(key string, messages []repository.Message, attachments map[pgtype.UUID][]types.AttachmentResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	load := c.loading[key]
+	stale := load == nil || load.stale
+	if load != nil {
+		if load.pending--; load.pending == 0 {
+			delete(c.loading, key)
+		}
+	}
+	if stale || messages == nil {
+		return
+	}
+
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+	entry := &historyEntry{key: key, messages: messages, attachments: attachments, loadedAt: time.Now()}
+	c.entries[key] = c.order.PushFront(entry)
+	for _, msg := range messages {
+		c.byMessage[msg.ID] = key
+	}
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+}
+
+// Invalidate descarta a conversa entre os dois usuários
+func (c *HistoryCache) Invalidate(a, b pgtype.UUID) {
+	if c == nil {
+		return
+	}
+	c.invalidate(conversationKey(a, b))
+}
+
+// InvalidateMessage descarta a conversa que contém a mensagem (mudança de status)
+func (c *HistoryCache) InvalidateMessage(messageID pgtype.UUID) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	key, ok := c.byMessage[messageID]
+	c.mu.Unlock()
+	if ok {
+		c.invalidate(key)
+	}
+}
+
+// Handle implementa eventbus.Handler: nova mensagem invalida a conversa
+func (c *HistoryCache) Handle(_ context.Context, msg *eventbus.Message) error {
+	// Mensagem nova ou mudança de status: a conversa sai do cache nas duas
+	var event events.MessageSent
+	if events.TypeOf(msg.Value) == events.TypeMessageStatus {
+		var status events.MessageStatusChanged
+		if err := events.Decode(msg.Value, &status); err != nil {
+			return err
+		}
+		event.SenderID, event.ReceiverID = status.SenderID, status.ReceiverID
+	} else if err := events.Decode(msg.Value, &event); err != nil {
+		return err
+	}
+	senderID, err := utils.StringToUUID(event.SenderID)
+	if err != nil {
+		return fmt.Errorf("sender_id inválido: %w", err)
+	}
+	receiverID, err := utils.StringToUUID(event.ReceiverID)
+	if err != nil {
+		return fmt.Errorf("receiver_id inválido: %w", err)
+	}
+
+	c.Invalidate(senderID, receiverID)
+	return nil
+}
+
+// Stats resumo para /debug/dump
+func (c *HistoryCache) Stats() map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return map[string]int{
+		"conversations": c.order.Len(),
+		"capacity":      c.capacity,
+		"messages":      len(c.byMessage),
+		"loading":       len(c.loading),
+	}
+}
+
+// invalidate remove a entrada e invalida consultas em andamento
+func (c *HistoryCache) invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+	if load, ok := c.loading[key]; ok {
+		load.stale = true
+	}
+}
+
+// remove tira a entrada do LRU e do índice de mensagens (com o lock)
+func (c *HistoryCache) remove(el *list.Element) {
+	entry := c.order.Remove(el).(*historyEntry)
+	delete(c.entries, entry.key)
+	for _, msg := range entry.messages {
+		if c.byMessage[msg.ID] == entry.key {
+			delete(c.byMessage, msg.ID)
+		}
+	}
+}
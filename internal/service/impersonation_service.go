@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+	"chat-kafka-go/pkg/validate"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	// ErrSupportAccessRequired usuário não autorizou o acesso do suporte (ou expirou)
+	ErrSupportAccessRequired = errors.New("usuário não autorizou o acesso do suporte")
+	// ErrImpersonationNotFound sessão de suporte inexistente
+	ErrImpersonationNotFound = errors.New("sessão de suporte não encontrada")
+	// ErrImpersonationEnded sessão revogada ou expirada
+	ErrImpersonationEnded = errors.New("sessão de suporte encerrada")
+	// ErrImpersonationScope rota fora do que o token de suporte pode ler
+	ErrImpersonationScope = errors.New("token de suporte não permite este acesso")
+)
+
+// impersonationScopes rotas com escopo liberadas ao token de suporte
+// (somente leitura: o middleware recusa métodos que alteram estado)
+var impersonationScopes = []string{ScopeMessagesRead, ScopeUsersRead}
+
+// impersonationHistory sessões exibidas ao usuário e ao admin
+const impersonationHistory = 20
+
+// ImpersonationService acesso de suporte às conversas de um usuário: o
+// usuário autoriza por tempo limitado, o admin emite token somente leitura
+// (marcado com imp) e cada acesso fica na auditoria
+type ImpersonationService struct {
+	queries *repository.Queries
+	audit   *AuditService
+	cfg     *config.Config
+	clock   clock.Clock // Prazos da autorização e das sessões
+}
+
+// NewImpersonationService cria nova instância do service
+func NewImpersonationService(queries *repository.Queries, audit *AuditService, cfg *config.Config) *ImpersonationService {
+	return &ImpersonationService{
+		queries: queries,
+		audit:   audit,
+		cfg:     cfg,
+		clock:   clock.System,
+	}
+}
+
+// SetClock troca o relógio (testes)
+func (s *ImpersonationService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// GrantAccess autoriza o suporte por ExpiresIn (máximo SUPPORT_ACCESS_MAX_TTL);
+// repetir renova o prazo
+func (s *ImpersonationService) GrantAccess(ctx context.Context, userID string, input types.GrantSupportAccessInput, ip string) (*types.SupportAccessResponse, error) {
+	if err := authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	userUUID, err := utils.StringToUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	ttl := s.cfg.Support.AccessMaxTTL
+	if input.ExpiresIn != "" {
+		ttl, err = time.ParseDuration(input.ExpiresIn)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("expires_in inválido (use uma duração, ex.: 24h)")
+		}
+		if ttl > s.cfg.Support.AccessMaxTTL {
+			return nil, fmt.Errorf("expires_in máximo é %s", s.cfg.Support.AccessMaxTTL)
+		}
+	}
+
+	grant, err := s.queries.UpsertSupportAccessGrant(ctx, repository.UpsertSupportAccessGrantParams{
+		UserID:    userUUID,
+		ExpiresAt: pgtype.Timestamp{Time: s.clock.Now().Add(ttl), Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao autorizar acesso do suporte: %w", err)
+	}
+
+	s.audit.Record(ctx, userUUID, AuditSupportAccessGrant, ip, 0, map[string]string{
+		"expires_at": grant.ExpiresAt.Time.Format(time.RFC3339),
+	})
+	return s.accessResponse(ctx, userUUID, &grant)
+}
+
+// RevokeAccess retira a autorização e encerra os tokens de suporte ativos
+func (s *ImpersonationService) RevokeAccess(ctx context.Context, userID, ip string) error {
+	if err := authorize(ctx, userID); err != nil {
+		return err
+	}
+	userUUID, err := utils.StringToUUID(userID)
+	if err != nil {
+		return fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	if _, err := s.queries.DeleteSupportAccessGrant(ctx, userUUID); err != nil {
+		return fmt.Errorf("erro ao revogar acesso do suporte: %w", err)
+	}
+	ended, err := s.queries.RevokeUserImpersonationSessions(ctx, userUUID)
+	if err != nil {
+		return fmt.Errorf("erro ao encerrar sessões de suporte: %w", err)
+	}
+
+	s.audit.Record(ctx, userUUID, AuditSupportAccessRevoke, ip, 0, map[string]int64{
+		"sessions_ended": ended,
+	})
+	return nil
+}
+
+// GetAccess autorização atual e as últimas sessões do suporte na conta
+func (s *ImpersonationService) GetAccess(ctx context.Context, userID string) (*types.SupportAccessResponse, error) {
+	if err := authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	userUUID, err := utils.StringToUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	grant, err := s.queries.GetActiveSupportAccessGrant(ctx, userUUID)
+	if err != nil {
+		if err != pgx.ErrNoRows {
+			return nil, fmt.Errorf("erro ao buscar acesso do suporte: %w", err)
+		}
+		return s.accessResponse(ctx, userUUID, nil)
+	}
+	return s.accessResponse(ctx, userUUID, &grant)
+}
+
+// Start emite token de suporte somente leitura; exige autorização ativa do
+// usuário e vale até o menor entre ExpiresIn, SUPPORT_IMPERSONATION_MAX_TTL
+// e o fim da autorização
+func (s *ImpersonationService) Start(ctx context.Context, userID string, input types.StartImpersonationInput, ip string) (*types.ImpersonationResponse, error) {
+	userUUID, err := utils.StringToUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+	input.Operator = strings.TrimSpace(validate.Text(input.Operator))
+	if !validate.Length(input.Operator, 1, 100) {
+		return nil, fmt.Errorf("operator é obrigatório (máximo 100 caracteres)")
+	}
+	input.Reason = strings.TrimSpace(validate.Text(input.Reason))
+	if input.Reason == "" || !validate.Content(input.Reason) {
+		return nil, fmt.Errorf("reason é obrigatório (máximo %d caracteres)", validate.MaxContentLength)
+	}
+
+	ttl := s.cfg.Support.ImpersonationMaxTTL
+	if input.ExpiresIn != "" {
+		ttl, err = time.ParseDuration(input.ExpiresIn)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("expires_in inválido (use uma duração, ex.: 15m)")
+		}
+		if ttl > s.cfg.Support.ImpersonationMaxTTL {
+			return nil, fmt.Errorf("expires_in máximo é %s", s.cfg.Support.ImpersonationMaxTTL)
+		}
+	}
+
+	user, err := s.queries.GetUserByID(ctx, userUUID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("usuário não encontrado")
+		}
+		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+
+	grant, err := s.queries.GetActiveSupportAccessGrant(ctx, userUUID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrSupportAccessRequired
+		}
+		return nil, fmt.Errorf("erro ao buscar acesso do suporte: %w", err)
+	}
+
+	now := s.clock.Now()
+	if left := grant.ExpiresAt.Time.Sub(now); left < ttl {
+		ttl = left
+	}
+	if ttl <= 0 {
+		return nil, ErrSupportAccessRequired
+	}
+
+	session, err := s.queries.CreateImpersonationSession(ctx, repository.CreateImpersonationSessionParams{
+		UserID:    userUUID,
+		Operator:  input.Operator,
+		Reason:    input.Reason,
+		ExpiresAt: pgtype.Timestamp{Time: now.Add(ttl), Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar sessão de suporte: %w", err)
+	}
+
+	token, err := utils.GenerateImpersonationToken(
+		utils.UUIDToString(user.ID),
+		user.Username,
+		user.Email,
+		types.ImpersonationClaim{
+			SessionID: utils.UUIDToString(session.ID),
+			Operator:  session.Operator,
+			ReadOnly:  true,
+		},
+		s.cfg.JWT.AccessSecret,
+		s.cfg.JWT.Issuer,
+		s.cfg.JWT.Audience,
+		ttl,
+		now,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, userUUID, AuditImpersonationStart, ip, 0, map[string]string{
+		"session_id": utils.UUIDToString(session.ID),
+		"operator":   session.Operator,
+		"reason":     session.Reason,
+		"expires_at": session.ExpiresAt.Time.Format(time.RFC3339),
+	})
+	log.Printf("✓ Sessão de suporte %s: %s vendo %s até %s", utils.UUIDToString(session.ID),
+		session.Operator, user.Username, session.ExpiresAt.Time.Format(time.RFC3339))
+
+	return &types.ImpersonationResponse{
+		Session:     s.sessionResponse(session),
+		AccessToken: token,
+		ExpiresIn:   int64(ttl.Seconds()),
+	}, nil
+}
+
+// Revoke encerra a sessão antes do prazo (o token deixa de valer na hora)
+func (s *ImpersonationService) Revoke(ctx context.Context, sessionID, ip string) (*types.ImpersonationSessionResponse, error) {
+	sessionUUID, err := utils.StringToUUID(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de sessão inválido: %w", err)
+	}
+
+	session, err := s.queries.RevokeImpersonationSession(ctx, sessionUUID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrImpersonationNotFound
+		}
+		return nil, fmt.Errorf("erro ao revogar sessão de suporte: %w", err)
+	}
+
+	s.audit.Record(ctx, session.UserID, AuditImpersonationEnd, ip, 0, map[string]string{
+		"session_id": sessionID,
+		"operator":   session.Operator,
+	})
+	resp := s.sessionResponse(session)
+	return &resp, nil
+}
+
+// ListForUser últimas sessões de suporte do usuário (visão do admin)
+func (s *ImpersonationService) ListForUser(ctx context.Context, userID string) ([]types.ImpersonationSessionResponse, error) {
+	userUUID, err := utils.StringToUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+	return s.listSessions(ctx, userUUID)
+}
+
+// ValidateImpersonation confere escopo e sessão de um token de suporte e
+// registra o acesso (middleware.ImpersonationValidator)
+func (s *ImpersonationService) ValidateImpersonation(ctx context.Context, sessionID, scope, path string) error {
+	if !slices.Contains(impersonationScopes, scope) {
+		return ErrImpersonationScope
+	}
+	sessionUUID, err := utils.StringToUUID(sessionID)
+	if err != nil {
+		return ErrImpersonationEnded
+	}
+
+	session, err := s.queries.GetActiveImpersonationSession(ctx, sessionUUID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return ErrImpersonationEnded
+		}
+		return fmt.Errorf("erro ao buscar sessão de suporte: %w", err)
+	}
+
+	s.audit.Record(ctx, session.UserID, AuditImpersonationAccess, "", 0, map[string]string{
+		"session_id": sessionID,
+		"operator":   session.Operator,
+		"path":       path,
+	})
+	return nil
+}
+
+func (s *ImpersonationService) accessResponse(ctx context.Context, userID pgtype.UUID, grant *repository.SupportAccessGrant) (*types.SupportAccessResponse, error) {
+	sessions, err := s.listSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &types.SupportAccessResponse{Sessions: sessions}
+	if grant != nil {
+		resp.Active = true
+		resp.ExpiresAt = grant.ExpiresAt.Time.Format(time.RFC3339)
+	}
+	return resp, nil
+}
+
+func (s *ImpersonationService) listSessions(ctx context.Context, userID pgtype.UUID) ([]types.ImpersonationSessionResponse, error) {
+	sessions, err := s.queries.ListUserImpersonationSessions(ctx, repository.ListUserImpersonationSessionsParams{
+		UserID: userID,
+		Limit:  impersonationHistory,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar sessões de suporte: %w", err)
+	}
+
+	resp := make([]types.ImpersonationSessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		resp = append(resp, s.sessionResponse(session))
+	}
+	return resp, nil
+}
+
+func (s *ImpersonationService) sessionResponse(session repository.ImpersonationSession) types.ImpersonationSessionResponse {
+	resp := types.ImpersonationSessionResponse{
+		ID:        utils.UUIDToString(session.ID),
+		UserID:    utils.UUIDToString(session.UserID),
+		Operator:  session.Operator,
+		Reason:    session.Reason,
+		Active:    !session.RevokedAt.Valid && session.ExpiresAt.Time.After(s.clock.Now()),
+		ExpiresAt: session.ExpiresAt.Time.Format(time.RFC3339),
+		CreatedAt: session.CreatedAt.Time.Format(time.RFC3339),
+	}
+	if session.RevokedAt.Valid {
+		resp.RevokedAt = session.RevokedAt.Time.Format(time.RFC3339)
+	}
+	return resp
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/mailer"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/status"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+	"chat-kafka-go/pkg/validate"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// InvitationService gerencia convites e indicações
+type InvitationService struct {
+	queries *repository.Queries
+	mailer  mailer.Mailer
+	cfg     *config.Config
+	clock   clock.Clock // Expiração dos convites
+}
+
+// NewInvitationService cria nova instância do service
+func NewInvitationService(queries *repository.Queries, mailer mailer.Mailer, cfg *config.Config) *InvitationService {
+	return &InvitationService{
+		queries: queries,
+		mailer:  mailer,
+		cfg:     cfg,
+		clock:   clock.System,
+	}
+}
+
+// SetClock troca o relógio (testes)
+func (s *InvitationService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// CreateInvitation cria convite por email (uso único) ou por link (reutilizável)
+func (s *InvitationService) CreateInvitation(ctx context.Context, input types.CreateInvitationInput) (*types.InvitationResponse, error) {
+	inviterUUID, err := utils.StringToUUID(input.InviterID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	var email *string
+	if input.Email != "" {
+		normalized := strings.ToLower(strings.TrimSpace(input.Email))
+		if !validate.Email(normalized) {
+			return nil, fmt.Errorf("email inválido")
+		}
+		email = &normalized
+	}
+
+	inviter, err := s.queries.GetUserByID(ctx, inviterUUID)
+	if err != nil {
+		return nil, fmt.Errorf("usuário não encontrado: %w", err)
+	}
+
+	token, err := utils.GenerateSecureToken()
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, err := s.queries.CreateInvitation(ctx, repository.CreateInvitationParams{
+		InviterID: inviterUUID,
+		Email:     email,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: pgtype.Timestamp{
+			Time:  s.clock.Now().Add(s.cfg.User.InvitationExpiration),
+			Valid: true,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar convite: %w", err)
+	}
+
+	link := fmt.Sprintf("%s/register?invite=%s", s.cfg.Mail.BaseURL, url.QueryEscape(token))
+
+	if email != nil {
+		body := fmt.Sprintf("%s convidou você para o chat.\n\nCrie sua conta: %s\n", inviter.Username, link)
+		if err := s.mailer.Send(ctx, *email, "Você foi convidado", body); err != nil {
+			return nil, fmt.Errorf("erro ao enviar convite: %w", err)
+		}
+	}
+
+	resp := &types.InvitationResponse{
+		ID:        utils.UUIDToString(invitation.ID),
+		Link:      link,
+		ExpiresAt: invitation.ExpiresAt.Time.Format(time.RFC3339),
+	}
+	if email != nil {
+		resp.Email = *email
+	}
+	return resp, nil
+}
+
+// ValidateInvitation confere o convite antes do cadastro: token válido e,
+// em convite por email, destinado ao email informado
+func (s *InvitationService) ValidateInvitation(ctx context.Context, token, email string) (repository.Invitation, error) {
+	invitation, err := s.queries.GetValidInvitationByTokenHash(ctx, utils.HashToken(token))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return repository.Invitation{}, fmt.Errorf("convite inválido ou expirado")
+		}
+		return repository.Invitation{}, fmt.Errorf("erro ao buscar convite: %w", err)
+	}
+
+	// Convite por email só vale para o email convidado
+	if invitation.Email != nil && !strings.EqualFold(*invitation.Email, email) {
+		return repository.Invitation{}, fmt.Errorf("convite destinado a outro email")
+	}
+	return invitation, nil
+}
+
+// AcceptInvitation vincula o usuário recém-criado ao convite já validado:
+// registra a indicação e cria amizade já aceita com quem convidou
+func (s *InvitationService) AcceptInvitation(ctx context.Context, invitation repository.Invitation, invitee repository.User) error {
+	err := s.queries.CreateReferral(ctx, repository.CreateReferralParams{
+		InviteeID:    invitee.ID,
+		InviterID:    invitation.InviterID,
+		InvitationID: invitation.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao registrar indicação: %w", err)
+	}
+
+	_, err = s.queries.CreateFriendship(ctx, repository.CreateFriendshipParams{
+		UserID:   invitation.InviterID,
+		FriendID: invitee.ID,
+		Status:   status.FriendshipAccepted,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao criar amizade: %w", err)
+	}
+
+	if invitation.Email != nil {
+		if err := s.queries.MarkInvitationAccepted(ctx, invitation.ID); err != nil {
+			return fmt.Errorf("erro ao marcar convite: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetReferralStats retorna convites enviados e aceitos pelo usuário
+func (s *InvitationService) GetReferralStats(ctx context.Context, userID pgtype.UUID) (types.ReferralStats, error) {
+	stats, err := s.queries.GetReferralStats(ctx, userID)
+	if err != nil {
+		return types.ReferralStats{}, fmt.Errorf("erro ao buscar indicações: %w", err)
+	}
+	return types.ReferralStats{
+		InvitationsSent:   int(stats.InvitationsSent),
+		ReferralsAccepted: int(stats.ReferralsAccepted),
+	}, nil
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrLegalHold dados sob retenção legal não podem ser removidos
+	ErrLegalHold = errors.New("dados sob retenção legal")
+	// ErrLegalHoldExists já há retenção ativa para o usuário/conversa
+	ErrLegalHoldExists = errors.New("retenção legal já ativa")
+	// ErrLegalHoldNotFound retenção inexistente ou já liberada
+	ErrLegalHoldNotFound = errors.New("retenção legal não encontrada")
+)
+
+// LegalHoldService retenções legais (admin). Enquanto ativa, a remoção de
+// conta, a coleta de anexos e a retenção do índice de busca não apagam os
+// dados do usuário ou da conversa
+type LegalHoldService struct {
+	queries *repository.Queries
+}
+
+// NewLegalHoldService cria nova instância do service
+func NewLegalHoldService(queries *repository.Queries) *LegalHoldService {
+	return &LegalHoldService{queries: queries}
+}
+
+// Place coloca usuário (ou a conversa dele com PeerID) sob retenção
+func (s *LegalHoldService) Place(ctx context.Context, input types.PlaceLegalHoldInput) (*types.LegalHoldResponse, error) {
+	userUUID, err := utils.StringToUUID(input.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" {
+		return nil, fmt.Errorf("reason é obrigatório")
+	}
+	if _, err := s.queries.GetUserByIDIncludingDeleted(ctx, userUUID); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("usuário não encontrado")
+		}
+		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+
+	params := repository.CreateLegalHoldParams{UserID: userUUID, Reason: reason}
+	if input.PeerID != "" {
+		peerUUID, err := utils.StringToUUID(input.PeerID)
+		if err != nil || peerUUID == userUUID {
+			return nil, fmt.Errorf("peer_id inválido")
+		}
+		if _, err := s.queries.GetUserByIDIncludingDeleted(ctx, peerUUID); err != nil {
+			if err == pgx.ErrNoRows {
+				return nil, fmt.Errorf("usuário não encontrado")
+			}
+			return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
+		}
+		// Conversa guardada pelo par ordenado (user_id < peer_id)
+		params.PeerID = peerUUID
+		if bytes.Compare(peerUUID.Bytes[:], userUUID.Bytes[:]) < 0 {
+			params.UserID, params.PeerID = peerUUID, userUUID
+		}
+	}
+
+	hold, err := s.queries.CreateLegalHold(ctx, params)
+	if err == pgx.ErrNoRows {
+		return nil, ErrLegalHoldExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar retenção legal: %w", err)
+	}
+
+	resp := toLegalHoldResponse(hold)
+	return &resp, nil
+}
+
+// List retenções (activeOnly = só as ativas), mais recentes primeiro
+func (s *LegalHoldService) List(ctx context.Context, activeOnly bool) ([]types.LegalHoldResponse, error) {
+	holds, err := s.queries.ListLegalHolds(ctx, activeOnly)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar retenções legais: %w", err)
+	}
+
+	resp := make([]types.LegalHoldResponse, len(holds))
+	for i, hold := range holds {
+		resp[i] = toLegalHoldResponse(hold)
+	}
+	return resp, nil
+}
+
+// Release libera a retenção; o registro permanece com released_at
+func (s *LegalHoldService) Release(ctx context.Context, holdID string) (*types.LegalHoldResponse, error) {
+	holdUUID, err := utils.StringToUUID(holdID)
+	if err != nil {
+		return nil, ErrLegalHoldNotFound
+	}
+
+	hold, err := s.queries.ReleaseLegalHold(ctx, holdUUID)
+	if err == pgx.ErrNoRows {
+		return nil, ErrLegalHoldNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao liberar retenção legal: %w", err)
+	}
+
+	resp := toLegalHoldResponse(hold)
+	return &resp, nil
+}
+
+// AnyActive há alguma retenção ativa (caminhos que não conseguem excluir
+// dados seletivamente ficam suspensos)
+func (s *LegalHoldService) AnyActive(ctx context.Context) (bool, error) {
+	n, err := s.queries.CountActiveLegalHolds(ctx)
+	if err != nil {
+		return false, fmt.Errorf("erro ao consultar retenções legais: %w", err)
+	}
+	return n > 0, nil
+}
+
+func toLegalHoldResponse(hold repository.LegalHold) types.LegalHoldResponse {
+	resp := types.LegalHoldResponse{
+		ID:        utils.UUIDToString(hold.ID),
+		UserID:    utils.UUIDToString(hold.UserID),
+		PeerID:    utils.UUIDToString(hold.PeerID),
+		Reason:    hold.Reason,
+		Active:    !hold.ReleasedAt.Valid,
+		CreatedAt: hold.CreatedAt.Time.Format(time.RFC3339),
+	}
+	if hold.ReleasedAt.Valid {
+		resp.ReleasedAt = hold.ReleasedAt.Time.Format(time.RFC3339)
+	}
+	return resp
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/geoip"
+	"chat-kafka-go/internal/mailer"
+	"chat-kafka-go/internal/reporter"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// LoginAlertService registra logins (IP, dispositivo, localização) e avisa o
+// usuário quando o login vem de um dispositivo ou país novo
+type LoginAlertService struct {
+	queries       *repository.Queries
+	locator       geoip.Locator
+	mailer        mailer.Mailer
+	notifications *NotificationService
+	audit         *AuditService
+	cfg           *config.Config
+}
+
+// NewLoginAlertService cria nova instância do service
+func NewLoginAlertService(queries *repository.Queries, locator geoip.Locator, mailer mailer.Mailer, notifications *NotificationService, audit *AuditService, cfg *config.Config) *LoginAlertService {
+	return &LoginAlertService{
+		queries:       queries,
+		locator:       locator,
+		mailer:        mailer,
+		notifications: notifications,
+		audit:         audit,
+		cfg:           cfg,
+	}
+}
+
+// Locate preenche país/cidade do cliente; falha na geolocalização não impede o login
+func (s *LoginAlertService) Locate(ctx context.Context, client types.ClientInfo) types.ClientInfo {
+	location, err := s.locator.Lookup(ctx, client.IP)
+	if err != nil {
+		log.Printf("AVISO: geolocalização de %s: %v", client.IP, err)
+		return client
+	}
+	client.Country = location.Country
+	client.City = location.City
+	return client
+}
+
+// RecordLogin registra o login da sessão (refresh token) e dispara alerta
+// se for um dispositivo ou país ainda não visto. O primeiro login
+// (registro) apenas registra o dispositivo, sem alerta.
+func (s *LoginAlertService) RecordLogin(ctx context.Context, user repository.User, refreshTokenID pgtype.UUID, deviceID string, client types.ClientInfo) error {
+	previousLogins, err := s.queries.CountUserLogins(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("erro ao contar logins: %w", err)
+	}
+
+	// Dispositivo
+	fingerprint := utils.DeviceFingerprint(deviceID, client.UserAgent)
+	newDevice := false
+	device, err := s.queries.GetUserDevice(ctx, repository.GetUserDeviceParams{
+		UserID:      user.ID,
+		Fingerprint: fingerprint,
+	})
+	switch {
+	case err == pgx.ErrNoRows:
+		newDevice = true
+		device, err = s.queries.CreateUserDevice(ctx, repository.CreateUserDeviceParams{
+			UserID:      user.ID,
+			Fingerprint: fingerprint,
+			UserAgent:   client.UserAgent,
+		})
+		if err != nil {
+			return fmt.Errorf("erro ao registrar dispositivo: %w", err)
+		}
+	case err != nil:
+		return fmt.Errorf("erro ao buscar dispositivo: %w", err)
+	default:
+		err = s.queries.TouchUserDevice(ctx, repository.TouchUserDeviceParams{
+			ID:        device.ID,
+			UserAgent: client.UserAgent,
+		})
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar dispositivo: %w", err)
+		}
+	}
+
+	// Localização (preenchida por Locate)
+	var country, city *string
+	newLocation := false
+	if client.Country != "" {
+		country = &client.Country
+		known, err := s.queries.HasLoginFromCountry(ctx, repository.HasLoginFromCountryParams{
+			UserID:  user.ID,
+			Country: country,
+		})
+		if err != nil {
+			return fmt.Errorf("erro ao verificar localização: %w", err)
+		}
+		newLocation = !known
+	}
+	if client.City != "" {
+		city = &client.City
+	}
+
+	alert := previousLogins > 0 && (newDevice || newLocation)
+
+	var alertToken string
+	var alertTokenHash *string
+	if alert {
+		alertToken, err = utils.GenerateSecureToken()
+		if err != nil {
+			return err
+		}
+		hash := utils.HashToken(alertToken)
+		alertTokenHash = &hash
+	}
+
+	event, err := s.queries.CreateLoginEvent(ctx, repository.CreateLoginEventParams{
+		UserID:         user.ID,
+		DeviceID:       device.ID,
+		RefreshTokenID: refreshTokenID,
+		Ip:             client.IP,
+		Country:        country,
+		City:           city,
+		NewDevice:      newDevice && previousLogins > 0,
+		NewLocation:    newLocation && previousLogins > 0,
+		AlertTokenHash: alertTokenHash,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao registrar login: %w", err)
+	}
+
+	if alert {
+		s.sendAlert(ctx, user, event, client, alertToken)
+	}
+	return nil
+}
+
+// sendAlert avisa por email e notificação in-app; falhas são apenas reportadas
+func (s *LoginAlertService) sendAlert(ctx context.Context, user repository.User, event repository.LoginEvent, client types.ClientInfo, token string) {
+	notMeURL := fmt.Sprintf("%s/auth/not-me?token=%s", s.cfg.Mail.BaseURL, url.QueryEscape(token))
+
+	payload := types.NewLoginAlert{
+		IP:          client.IP,
+		Country:     client.Country,
+		City:        client.City,
+		UserAgent:   client.UserAgent,
+		NewDevice:   event.NewDevice,
+		NewLocation: event.NewLocation,
+		NotMeURL:    notMeURL,
+		LoggedInAt:  event.CreatedAt.Time.Format(time.RFC3339),
+	}
+
+	if err := s.notifications.Notify(ctx, user.ID, NotificationNewLogin, payload); err != nil {
+		reporter.CaptureError(ctx, err, map[string]string{"component": "login_alert"})
+	}
+
+	where := client.IP
+	if client.City != "" {
+		where = fmt.Sprintf("%s (%s, %s)", client.IP, client.City, client.Country)
+	} else if client.Country != "" {
+		where = fmt.Sprintf("%s (%s)", client.IP, client.Country)
+	}
+
+	body := fmt.Sprintf("Olá %s,\n\nDetectamos um novo login na sua conta.\n\nOrigem: %s\nDispositivo: %s\nData: %s\n\n"+
+		"Se não foi você, encerre a sessão imediatamente: %s\n",
+		user.Username, where, client.UserAgent, payload.LoggedInAt, notMeURL)
+	if err := s.mailer.Send(ctx, user.Email, "Novo login na sua conta", body); err != nil {
+		reporter.CaptureError(ctx, err, map[string]string{"component": "login_alert"})
+	}
+}
+
+// ReportNotMe trata o "não fui eu": revoga a sessão do login alertado
+func (s *LoginAlertService) ReportNotMe(ctx context.Context, token string) error {
+	if token == "" {
+		return fmt.Errorf("token é obrigatório")
+	}
+
+	hash := utils.HashToken(token)
+	event, err := s.queries.GetLoginEventByAlertTokenHash(ctx, &hash)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("alerta inválido ou já utilizado")
+		}
+		return fmt.Errorf("erro ao buscar alerta: %w", err)
+	}
+
+	if event.RefreshTokenID.Valid {
+		if err := s.queries.DeleteRefreshTokenByID(ctx, event.RefreshTokenID); err != nil {
+			return fmt.Errorf("erro ao revogar sessão: %w", err)
+		}
+	}
+
+	if err := s.queries.MarkLoginEventReported(ctx, event.ID); err != nil {
+		return fmt.Errorf("erro ao marcar alerta: %w", err)
+	}
+
+	s.audit.Record(ctx, event.UserID, AuditSessionNotMe, event.Ip, 0, map[string]string{
+		"login_event_id": utils.UUIDToString(event.ID),
+	})
+
+	log.Printf("Sessão revogada por \"não fui eu\" (usuário %s, IP %s)", utils.UUIDToString(event.UserID), event.Ip)
+	return nil
+}
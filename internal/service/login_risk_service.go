@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/mailer"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/internal/risk"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// LoginRiskService pontua logins (reputação do IP, velocidade, troca de
+// dispositivo/país) e exige confirmação por email acima do limite
+type LoginRiskService struct {
+	queries *repository.Queries
+	engine  *risk.Engine
+	audit   *AuditService
+	mailer  mailer.Mailer
+	cfg     *config.Config
+	clock   clock.Clock // Janela de risco e expiração do desafio
+}
+
+// NewLoginRiskService cria nova instância do service
+func NewLoginRiskService(queries *repository.Queries, engine *risk.Engine, audit *AuditService, mailer mailer.Mailer, cfg *config.Config) *LoginRiskService {
+	return &LoginRiskService{
+		queries: queries,
+		engine:  engine,
+		audit:   audit,
+		mailer:  mailer,
+		cfg:     cfg,
+		clock:   clock.System,
+	}
+}
+
+// SetClock troca o relógio (testes)
+func (s *LoginRiskService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Assess coleta os sinais do login e calcula o risco
+func (s *LoginRiskService) Assess(ctx context.Context, user repository.User, deviceID string, client types.ClientInfo) (risk.Assessment, error) {
+	since := s.clock.Now().Add(-s.cfg.Security.RiskWindow)
+	signals := risk.Signals{IP: client.IP}
+
+	previousLogins, err := s.queries.CountUserLogins(ctx, user.ID)
+	if err != nil {
+		return risk.Assessment{}, fmt.Errorf("erro ao contar logins: %w", err)
+	}
+
+	// Troca de dispositivo/país só conta se o usuário já tem histórico
+	if previousLogins > 0 {
+		_, err := s.queries.GetUserDevice(ctx, repository.GetUserDeviceParams{
+			UserID:      user.ID,
+			Fingerprint: utils.DeviceFingerprint(deviceID, client.UserAgent),
+		})
+		switch {
+		case err == pgx.ErrNoRows:
+			signals.NewDevice = true
+		case err != nil:
+			return risk.Assessment{}, fmt.Errorf("erro ao buscar dispositivo: %w", err)
+		}
+
+		if client.Country != "" {
+			country := client.Country
+			known, err := s.queries.HasLoginFromCountry(ctx, repository.HasLoginFromCountryParams{
+				UserID:  user.ID,
+				Country: &country,
+			})
+			if err != nil {
+				return risk.Assessment{}, fmt.Errorf("erro ao verificar localização: %w", err)
+			}
+			signals.NewCountry = !known
+
+			last, err := s.queries.GetLastLoginEvent(ctx, user.ID)
+			if err != nil && err != pgx.ErrNoRows {
+				return risk.Assessment{}, fmt.Errorf("erro ao buscar último login: %w", err)
+			}
+			if err == nil && last.Country != nil && *last.Country != client.Country && last.CreatedAt.Time.After(since) {
+				signals.CountryChanged = true
+			}
+		}
+	}
+
+	// Velocidade: falhas recentes do usuário e do IP
+	if signals.UserFailures, err = s.audit.CountUserSince(ctx, user.ID, AuditLoginFailed, since); err != nil {
+		return risk.Assessment{}, fmt.Errorf("erro ao contar falhas: %w", err)
+	}
+	if signals.IPFailures, err = s.audit.CountIPSince(ctx, client.IP, AuditLoginFailed, since); err != nil {
+		return risk.Assessment{}, fmt.Errorf("erro ao contar falhas: %w", err)
+	}
+
+	return s.engine.Assess(signals), nil
+}
+
+// RequiresStepUp indica se o score exige confirmação adicional
+func (s *LoginRiskService) RequiresStepUp(assessment risk.Assessment) bool {
+	return s.cfg.Security.RiskStepUpThreshold > 0 && assessment.Score >= s.cfg.Security.RiskStepUpThreshold
+}
+
+// StartChallenge cria desafio e envia o código de confirmação por email
+func (s *LoginRiskService) StartChallenge(ctx context.Context, user repository.User, deviceID string, client types.ClientInfo, assessment risk.Assessment) (*types.LoginChallengeResponse, error) {
+	code, err := utils.GenerateNumericCode(6)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := s.queries.CreateLoginChallenge(ctx, repository.CreateLoginChallengeParams{
+		UserID:    user.ID,
+		CodeHash:  utils.HashToken(code),
+		DeviceID:  deviceID,
+		ExpiresAt: pgtype.Timestamp{Time: s.clock.Now().Add(s.cfg.Security.LoginChallengeTTL), Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar desafio de login: %w", err)
+	}
+
+	body := fmt.Sprintf("Olá %s,\n\nRecebemos uma tentativa de login incomum (IP %s).\n\n"+
+		"Código de confirmação: %s\n\nSe não foi você, troque sua senha.\n", user.Username, client.IP, code)
+	if err := s.mailer.Send(ctx, user.Email, "Confirme seu login", body); err != nil {
+		return nil, fmt.Errorf("erro ao enviar código: %w", err)
+	}
+
+	s.audit.Record(ctx, user.ID, AuditLoginStepUp, client.IP, assessment.Score, map[string]interface{}{
+		"reasons":      assessment.Reasons,
+		"challenge_id": utils.UUIDToString(challenge.ID),
+		"country":      client.Country,
+	})
+
+	return &types.LoginChallengeResponse{
+		ID:        utils.UUIDToString(challenge.ID),
+		Method:    "email",
+		ExpiresAt: challenge.ExpiresAt.Time.Format(time.RFC3339),
+	}, nil
+}
+
+// VerifyChallenge confere o código; retorna o usuário e o device_id do login original
+func (s *LoginRiskService) VerifyChallenge(ctx context.Context, input types.VerifyLoginInput, client types.ClientInfo) (repository.User, string, error) {
+	challengeUUID, err := utils.StringToUUID(input.ChallengeID)
+	if err != nil {
+		return repository.User{}, "", fmt.Errorf("desafio inválido")
+	}
+
+	challenge, err := s.queries.GetPendingLoginChallenge(ctx, challengeUUID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return repository.User{}, "", fmt.Errorf("desafio inválido ou expirado")
+		}
+		return repository.User{}, "", fmt.Errorf("erro ao buscar desafio: %w", err)
+	}
+
+	if int(challenge.Attempts) >= s.cfg.Security.LoginChallengeMaxAttempts {
+		return repository.User{}, "", fmt.Errorf("número máximo de tentativas excedido")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(utils.HashToken(input.Code)), []byte(challenge.CodeHash)) != 1 {
+		if err := s.queries.IncrementLoginChallengeAttempts(ctx, challenge.ID); err != nil {
+			return repository.User{}, "", fmt.Errorf("erro ao registrar tentativa: %w", err)
+		}
+		s.audit.Record(ctx, challenge.UserID, AuditStepUpFailed, client.IP, 0, nil)
+		return repository.User{}, "", fmt.Errorf("código inválido")
+	}
+
+	// Marca como usado (protege contra uso concorrente do mesmo código)
+	rows, err := s.queries.MarkLoginChallengeVerified(ctx, challenge.ID)
+	if err != nil {
+		return repository.User{}, "", fmt.Errorf("erro ao confirmar desafio: %w", err)
+	}
+	if rows == 0 {
+		return repository.User{}, "", fmt.Errorf("desafio já utilizado")
+	}
+
+	user, err := s.queries.GetUserByID(ctx, challenge.UserID)
+	if err != nil {
+		return repository.User{}, "", fmt.Errorf("usuário não encontrado: %w", err)
+	}
+
+	s.audit.Record(ctx, user.ID, AuditStepUpVerified, client.IP, 0, nil)
+	return user, challenge.DeviceID, nil
+}
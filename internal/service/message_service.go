@@ -2,41 +2,120 @@ package service
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/eventbus"
+	"chat-kafka-go/internal/metrics"
+	"chat-kafka-go/internal/reporter"
 	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/internal/slo"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/events"
+	"chat-kafka-go/pkg/idgen"
+	"chat-kafka-go/pkg/status"
 	"chat-kafka-go/pkg/types"
 	"chat-kafka-go/pkg/utils"
+	"chat-kafka-go/pkg/validate"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 // MessageService gerencia mensagens
 type MessageService struct {
-	queries  *repository.Queries
-	producer KafkaProducer // Interface para Kafka Producer
+	queries     *repository.Queries
+	readQueries *repository.Queries // Réplica para histórico (pode ser o primário)
+	producer    KafkaProducer       // Barramento de eventos (Kafka ou lite)
+	hub         RealtimeDeliverer   // Entrega direta em modo degradado (opcional)
+	privacy     *PrivacyService
+	history     *HistoryCache             // Mensagens recentes por conversa (nil = desligado)
+	quotas      *QuotaService             // Cota diária de mensagens
+	plans       *PlanService              // Retenção do histórico do plano
+	ids         idgen.Generator           // IDs das mensagens (MESSAGE_ID_MODE)
+	delivery    *slo.Tracker              // SLO de latência da entrega direta (opcional)
+	emails      *EmailVerificationService // Exigência de email confirmado (nil = sem exigência)
+	cfg         *config.Config
 }
 
-// KafkaProducer interface para enviar mensagens ao Kafka
-// Vamos implementar depois, por enquanto é uma interface
+// KafkaProducer interface para publicar eventos de mensagem
+// Implementada por kafka.Producer e pelos barramentos de eventbus
 type KafkaProducer interface {
-	SendMessage(topic string, key string, value []byte) error
+	// ctx carrega request ID, tenant e usuário propagados como headers
+	SendMessage(ctx context.Context, topic string, key string, value []byte) error
+}
+
+// RealtimeDeliverer entrega frames às conexões WebSocket desta instância
+// Implementada por ws.Hub
+type RealtimeDeliverer interface {
+	SendToUser(userID, frameType string, data interface{}) (int, error)
 }
 
 // NewMessageService cria nova instância do service
-func NewMessageService(queries *repository.Queries, producer KafkaProducer) *MessageService {
+// readQueries é usado no histórico; se nil, usa o primário
+// hub (opcional) entrega mensagens direto quando o barramento está degradado
+// history (opcional) atende o histórico recente sem ir ao banco
+func NewMessageService(queries, readQueries *repository.Queries, producer KafkaProducer, hub RealtimeDeliverer, privacy *PrivacyService, history *HistoryCache, quotas *QuotaService, plans *PlanService, cfg *config.Config) *MessageService {
+	if readQueries == nil {
+		readQueries = queries
+	}
 	return &MessageService{
-		queries:  queries,
-		producer: producer,
+		queries:     queries,
+		readQueries: readQueries,
+		producer:    producer,
+		hub:         hub,
+		privacy:     privacy,
+		history:     history,
+		quotas:      quotas,
+		plans:       plans,
+		ids:         messageIDs(cfg),
+		cfg:         cfg,
+	}
+}
+
+// messageIDs gerador do MESSAGE_ID_MODE (validado no config)
+func messageIDs(cfg *config.Config) idgen.Generator {
+	ids, err := idgen.ForMode(cfg.Database.MessageIDMode, clock.System)
+	if err != nil {
+		return idgen.Random
 	}
+	return ids
+}
+
+// SetIDs troca o gerador de IDs (testes)
+func (s *MessageService) SetIDs(ids idgen.Generator) {
+	s.ids = ids
+}
+
+// SetDeliverySLO registra a latência das entregas diretas (modo degradado)
+// no mesmo SLO do consumidor de mensagens
+func (s *MessageService) SetDeliverySLO(t *slo.Tracker) {
+	s.delivery = t
+}
+
+// SetEmailVerification exige email confirmado do remetente conforme
+// EMAIL_VERIFICATION_REQUIRED
+func (s *MessageService) SetEmailVerification(emails *EmailVerificationService) {
+	s.emails = emails
 }
 
 // SendMessage envia mensagem (salva no DB + envia para Kafka)
 func (s *MessageService) SendMessage(ctx context.Context, input types.SendMessageInput) (*types.MessageResponse, error) {
-	// 1. Validar input
+	// 1. Validar input; remetente é o ator autenticado (exceto envio do serviço)
+	input.Content = validate.Text(input.Content)
 	if err := s.validateSendMessageInput(input); err != nil {
 		return nil, err
 	}
+	if !input.System {
+		if err := authorize(ctx, input.SenderID); err != nil {
+			return nil, err
+		}
+	}
 
 	// 2. Converter UUIDs
 	senderUUID, err := utils.StringToUUID(input.SenderID)
@@ -49,41 +128,139 @@ func (s *MessageService) SendMessage(ctx context.Context, input types.SendMessag
 		return nil, fmt.Errorf("receiver_id inválido: %w", err)
 	}
 
-	// 3. Salvar mensagem no banco com status 'sent'
-	message, err := s.queries.CreateMessage(ctx, repository.CreateMessageParams{
-		SenderID:   senderUUID,
-		ReceiverID: receiverUUID,
-		Content:    input.Content,
-		Status:     "sent",
+	// Conta removida (ainda na carência) não envia nem recebe: o access token
+	// continua válido até expirar e a privacidade não olha o destinatário
+	if err := s.checkActiveUsers(ctx, senderUUID, receiverUUID); err != nil {
+		return nil, err
+	}
+
+	// Email não confirmado não envia (respostas automáticas já foram ativadas pelo dono)
+	if !input.System && !input.AutoReply && s.emails != nil && s.emails.RequiredForMessaging() {
+		if err := s.emails.RequireVerified(ctx, senderUUID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Horário do dispositivo: só registrado, a ordem é sempre a do servidor
+	clientSentAt, err := parseClientTime(input.ClientSentAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Política do servidor e privacidade do destinatário (todos ou só amigos)
+	if !input.System {
+		if err := s.checkFriendship(ctx, input, senderUUID, receiverUUID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Anexo precisa ser do remetente, finalizado e ainda não usado
+	var attachment *repository.Attachment
+	if input.AttachmentID != "" {
+		found, err := s.sendableAttachment(ctx, senderUUID, input.AttachmentID)
+		if err != nil {
+			return nil, err
+		}
+		attachment = &found
+	}
+
+	// 3. Cota, mensagem, anexo e menções numa transação: falha no meio não
+	// deixa mensagem órfã nem cota consumida
+	var (
+		message     repository.Message
+		attachments []types.AttachmentResponse
+		mentions    []string
+	)
+	err = s.queries.InTx(ctx, func(q *repository.Queries) error {
+		// Cota diária conta depois das validações (envio recusado não consome)
+		if !input.System && !input.AutoReply {
+			if err := s.quotas.WithTx(q).ConsumeMessage(ctx, senderUUID, input.ViaAPIKey); err != nil {
+				return err
+			}
+		}
+
+		// Mensagem entra com status accepted
+		var err error
+		message, err = q.CreateMessage(ctx, repository.CreateMessageParams{
+			ID:           pgtype.UUID{Bytes: s.ids.New(), Valid: true},
+			SenderID:     senderUUID,
+			ReceiverID:   receiverUUID,
+			Content:      input.Content,
+			Status:       status.MessageAccepted,
+			ClientSentAt: clientSentAt,
+		})
+		if err != nil {
+			return fmt.Errorf("erro ao salvar mensagem: %w", err)
+		}
+
+		if attachment != nil {
+			rows, err := q.AttachToMessage(ctx, repository.AttachToMessageParams{
+				ID:         attachment.ID,
+				UploaderID: senderUUID,
+				MessageID:  message.ID,
+			})
+			if err != nil {
+				return fmt.Errorf("erro ao vincular anexo: %w", err)
+			}
+			if rows == 0 { // Usado por outro envio depois da checagem
+				return fmt.Errorf("anexo indisponível")
+			}
+			attachment.MessageID = message.ID
+			attachments = append(attachments, toAttachmentResponse(*attachment, s.cfg.Storage.NSFWPolicy))
+		}
+
+		// 4. Salvar menções por ID de usuário
+		mentions, err = s.saveMentions(ctx, q, message)
+		return err
 	})
 	if err != nil {
-		return nil, fmt.Errorf("erro ao salvar mensagem: %w", err)
+		return nil, err
 	}
+	// Remetente costuma recarregar o histórico antes do evento chegar
+	defer s.history.Invalidate(senderUUID, receiverUUID)
 
-	// 4. Preparar mensagem para Kafka
-	kafkaMessage := map[string]interface{}{
-		"id":          utils.UUIDToString(message.ID),
-		"sender_id":   input.SenderID,
-		"receiver_id": input.ReceiverID,
-		"content":     input.Content,
-		"timestamp":   message.CreatedAt.Time.Unix(),
+	// 5. Preparar mensagem para Kafka
+	kafkaMessage := events.MessageSent{
+		ID:         utils.UUIDToString(message.ID),
+		SenderID:   input.SenderID,
+		ReceiverID: input.ReceiverID,
+		Content:    input.Content,
+		Timestamp:  message.CreatedAt.Time.Unix(),
+		Mentions:   mentions,
+		AutoReply:  input.AutoReply,
 	}
 
-	messageBytes, err := json.Marshal(kafkaMessage)
+	messageBytes, err := events.Marshal(kafkaMessage)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao serializar mensagem: %w", err)
 	}
 
-	// 5. Enviar para Kafka (assíncrono)
+	// 6. Enviar para Kafka (assíncrono)
+	// Chave = conversa: eventos da mesma conversa ficam na mesma partição e no mesmo worker
 	// Se producer for nil (testes), pula esta etapa
 	if s.producer != nil {
-		if err := s.producer.SendMessage("chat-messages", input.ReceiverID, messageBytes); err != nil {
+		err := s.producer.SendMessage(ctx, s.cfg.Kafka.Topic, utils.ConversationKey(input.SenderID, input.ReceiverID), messageBytes)
+		if err != nil {
 			// Log erro mas não falha (mensagem já está no DB)
 			fmt.Printf("WARN: Erro ao enviar para Kafka: %v\n", err)
+			reporter.CaptureError(ctx, err, map[string]string{
+				"component":  "kafka_producer",
+				"message_id": utils.UUIDToString(message.ID),
+			})
+		}
+
+		// Modo degradado: destinatário online recebe agora; resumos e
+		// notificações acompanham quando o barramento reenviar o evento
+		if err != nil || eventbus.IsDegraded(s.producer) {
+			s.deliverDirect(ctx, kafkaMessage)
 		}
 	}
 
-	// 6. Retornar resposta
+	// 7. Retornar resposta
+	skew := s.clockSkew(message)
+	if skew != 0 {
+		metrics.ClientClockSkewTotal.Inc()
+	}
 	return &types.MessageResponse{
 		ID:         utils.UUIDToString(message.ID),
 		SenderID:   utils.UUIDToString(message.SenderID),
@@ -91,9 +268,189 @@ func (s *MessageService) SendMessage(ctx context.Context, input types.SendMessag
 		Content:    message.Content,
 		Status:     message.Status,
 		CreatedAt:  message.CreatedAt.Time.Format(time.RFC3339),
+		AcceptedAt: message.CreatedAt.Time.Format(time.RFC3339),
+
+		ClientSentAt:     optionalTime(message.ClientSentAt),
+		ClockSkewSeconds: skew,
+
+		Attachments: attachments,
 	}, nil
 }
 
+// parseClientTime horário opcional do cliente (RFC 3339, com ou sem fração)
+func parseClientTime(value string) (pgtype.Timestamp, error) {
+	if value == "" {
+		return pgtype.Timestamp{}, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return pgtype.Timestamp{}, fmt.Errorf("client_sent_at inválido (RFC 3339): %w", err)
+	}
+	return pgtype.Timestamp{Time: t.UTC(), Valid: true}, nil
+}
+
+// clockSkew diferença cliente - servidor em segundos quando passa de
+// CLIENT_CLOCK_SKEW_THRESHOLD (0 = sem horário do cliente ou dentro do limite)
+func (s *MessageService) clockSkew(message repository.Message) int64 {
+	if !message.ClientSentAt.Valid {
+		return 0
+	}
+	skew := message.ClientSentAt.Time.Sub(message.CreatedAt.Time)
+	if skew.Abs() < s.cfg.History.ClientClockSkew {
+		return 0
+	}
+	return int64(skew / time.Second)
+}
+
+// deliverDirect entrega a mensagem às conexões do destinatário nesta
+// instância sem passar pelo barramento. O worker entrega de novo quando o
+// evento for processado: clientes descartam frames repetidos pelo ID
+func (s *MessageService) deliverDirect(ctx context.Context, event events.MessageSent) {
+	if s.hub == nil {
+		return
+	}
+
+	// Shadow ban: mesma regra do worker, destinatário não recebe nada
+	senderID, err := utils.StringToUUID(event.SenderID)
+	if err != nil {
+		return
+	}
+	shadowBanned, err := s.queries.IsUserShadowBanned(ctx, senderID)
+	if err != nil && err != pgx.ErrNoRows {
+		fmt.Printf("WARN: Erro ao verificar shadow ban na entrega direta: %v\n", err)
+		return
+	}
+	if shadowBanned {
+		return
+	}
+
+	delivered, err := s.hub.SendToUser(event.ReceiverID, "message", event)
+	if err != nil {
+		fmt.Printf("WARN: Erro na entrega direta via websocket: %v\n", err)
+		return
+	}
+	if delivered > 0 {
+		metrics.DirectDeliveriesTotal.Inc()
+		s.delivery.ObserveMessage(event.ID)
+		if _, err := s.MarkAsDelivered(reqctx.WithSystem(ctx), event.ID, event.ReceiverID); err != nil {
+			fmt.Printf("WARN: Erro ao marcar entrega direta: %v\n", err)
+		}
+	}
+}
+
+// ErrFriendshipRequired envio exige amizade aceita (política do servidor ou
+// privacidade do destinatário); o cliente oferece enviar pedido de amizade
+var ErrFriendshipRequired = errors.New("envio permitido apenas entre amigos")
+
+// checkFriendship aplica MESSAGING_POLICY=friends e a privacidade do
+// destinatário; conversas com support/system nunca exigem amizade
+func (s *MessageService) checkFriendship(ctx context.Context, input types.SendMessageInput, sender, receiver pgtype.UUID) error {
+	if input.ReceiverID == SupportUserID || input.ReceiverID == SystemUserID {
+		return nil
+	}
+
+	if s.cfg.User.MessagingPolicy == PolicyFriends {
+		friends, err := s.privacy.areFriends(ctx, sender, receiver)
+		if err != nil {
+			return err
+		}
+		if !friends {
+			return ErrFriendshipRequired
+		}
+		return nil
+	}
+
+	allowed, err := s.privacy.CanMessage(ctx, sender, receiver)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return fmt.Errorf("%w: destinatário aceita mensagens apenas de amigos", ErrFriendshipRequired)
+	}
+	return nil
+}
+
+// ErrRecipientNotFound destinatário inexistente ou com a conta removida
+var ErrRecipientNotFound = errors.New("destinatário não encontrado")
+
+// checkActiveUsers recusa envio de ou para conta removida
+func (s *MessageService) checkActiveUsers(ctx context.Context, sender, receiver pgtype.UUID) error {
+	users, err := s.queries.GetUsersByIDs(ctx, []pgtype.UUID{sender, receiver})
+	if err != nil {
+		return fmt.Errorf("erro ao buscar usuários: %w", err)
+	}
+
+	var senderActive, receiverActive bool
+	for _, u := range users {
+		senderActive = senderActive || u.ID == sender
+		receiverActive = receiverActive || u.ID == receiver
+	}
+	if !senderActive {
+		return fmt.Errorf("%w: conta removida", ErrForbidden)
+	}
+	if !receiverActive {
+		return ErrRecipientNotFound
+	}
+	return nil
+}
+
+// sendableAttachment busca anexo que o remetente pode enviar em uma mensagem
+func (s *MessageService) sendableAttachment(ctx context.Context, senderID pgtype.UUID, attachmentID string) (repository.Attachment, error) {
+	id, err := utils.StringToUUID(attachmentID)
+	if err != nil {
+		return repository.Attachment{}, fmt.Errorf("attachment_id inválido: %w", err)
+	}
+
+	attachment, err := s.queries.GetAttachmentByID(ctx, id)
+	if err != nil && err != pgx.ErrNoRows {
+		return repository.Attachment{}, fmt.Errorf("erro ao buscar anexo: %w", err)
+	}
+	if err == pgx.ErrNoRows || attachment.UploaderID != senderID {
+		return repository.Attachment{}, fmt.Errorf("anexo não encontrado")
+	}
+	if attachment.Status != "uploaded" {
+		return repository.Attachment{}, fmt.Errorf("upload do anexo não foi finalizado")
+	}
+	if attachment.MessageID.Valid {
+		return repository.Attachment{}, fmt.Errorf("anexo já enviado em outra mensagem")
+	}
+	if attachment.ScanStatus == ScanInfected {
+		return repository.Attachment{}, ErrAttachmentQuarantined
+	}
+	return attachment, nil
+}
+
+// saveMentions resolve @username para IDs e grava em message_mentions
+// Usernames desconhecidos são ignorados
+func (s *MessageService) saveMentions(ctx context.Context, q *repository.Queries, message repository.Message) ([]string, error) {
+	usernames := utils.ExtractMentions(message.Content)
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+
+	mentions := make([]string, 0, len(usernames))
+	for _, username := range usernames {
+		user, err := resolveUsername(ctx, s.queries, username)
+		if err == pgx.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("erro ao resolver menção: %w", err)
+		}
+
+		err = q.CreateMessageMention(ctx, repository.CreateMessageMentionParams{
+			MessageID: message.ID,
+			UserID:    user.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("erro ao salvar menção: %w", err)
+		}
+		mentions = append(mentions, utils.UUIDToString(user.ID))
+	}
+
+	return mentions, nil
+}
+
 // validateSendMessageInput valida dados de entrada
 func (s *MessageService) validateSendMessageInput(input types.SendMessageInput) error {
 	if input.SenderID == "" {
@@ -102,20 +459,28 @@ func (s *MessageService) validateSendMessageInput(input types.SendMessageInput)
 	if input.ReceiverID == "" {
 		return fmt.Errorf("receiver_id é obrigatório")
 	}
+	if !validate.UUID(input.ReceiverID) {
+		return fmt.Errorf("receiver_id inválido")
+	}
 	if input.SenderID == input.ReceiverID {
 		return fmt.Errorf("não é possível enviar mensagem para si mesmo")
 	}
-	if input.Content == "" {
+	if input.Content == "" && input.AttachmentID == "" {
 		return fmt.Errorf("conteúdo da mensagem é obrigatório")
 	}
-	if len(input.Content) > 5000 {
-		return fmt.Errorf("mensagem muito longa (máximo 5000 caracteres)")
+	if !validate.Content(input.Content) {
+		return fmt.Errorf("mensagem muito longa (máximo %d caracteres)", validate.MaxContentLength)
 	}
 	return nil
 }
 
 // GetMessagesBetween lista mensagens entre dois usuários
 func (s *MessageService) GetMessagesBetween(ctx context.Context, input types.ListMessagesInput) (*types.PaginatedResponse, error) {
+	// Só um dos lados da conversa lê o histórico
+	if err := authorize(ctx, input.UserID); err != nil {
+		return nil, err
+	}
+
 	// Validar paginação
 	if input.Page < 1 {
 		input.Page = 1
@@ -135,31 +500,68 @@ func (s *MessageService) GetMessagesBetween(ctx context.Context, input types.Lis
 		return nil, fmt.Errorf("friend_id inválido: %w", err)
 	}
 
-	// Calcular offset
-	offset := (input.Page - 1) * input.PerPage
+	// Os dois sentidos da conversa; quem lê só decide os filtros abaixo
+	conv := conversation{a: userUUID, b: friendUUID}
 
-	// Buscar mensagens
-	messages, err := s.queries.ListMessagesBetweenUsers(ctx, repository.ListMessagesBetweenUsersParams{
-		SenderID:   userUUID,
-		ReceiverID: friendUUID,
-		Limit:      int32(input.PerPage),
-		Offset:     int32(offset),
-	})
+	// Retenção do plano de quem consulta: mensagens mais antigas não aparecem
+	// (o corte vai na consulta, então páginas, total e cursor já o respeitam)
+	cutoff, err := s.plans.HistoryCutoff(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []repository.Message
+	var attachments map[pgtype.UUID][]types.AttachmentResponse
+	if input.Before != "" {
+		messages, attachments, err = s.listMessagesBefore(ctx, conv, cutoff, input.Before, input.PerPage)
+	} else {
+		// Calcular offset
+		offset := (input.Page - 1) * input.PerPage
+		messages, attachments, err = s.listMessages(ctx, conv, cutoff, offset, input.PerPage)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("erro ao listar mensagens: %w", err)
+		return nil, err
+	}
+
+	// Página cheia: a última mensagem (antes dos filtros) é o cursor da próxima
+	var nextCursor string
+	if len(messages) == input.PerPage {
+		nextCursor = utils.UUIDToString(messages[len(messages)-1].ID)
+	}
+
+	// Amigo removido: oculta mensagens dele ou marca como "usuário removido"
+	friend, err := s.readQueries.GetUserByIDIncludingDeleted(ctx, friendUUID)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
 	}
+	friendDeleted := err == nil && friend.DeletedAt.Valid
+	// Amigo em shadow ban: as mensagens dele não aparecem para o destinatário
+	friendShadowBanned := err == nil && friend.ShadowBannedAt.Valid
 
 	// Converter para MessageResponse
-	messageResponses := make([]types.MessageResponse, len(messages))
-	for i, msg := range messages {
-		messageResponses[i] = types.MessageResponse{
-			ID:         utils.UUIDToString(msg.ID),
-			SenderID:   utils.UUIDToString(msg.SenderID),
-			ReceiverID: utils.UUIDToString(msg.ReceiverID),
-			Content:    msg.Content,
-			Status:     msg.Status,
-			CreatedAt:  msg.CreatedAt.Time.Format(time.RFC3339),
+	messageResponses := make([]types.MessageResponse, 0, len(messages))
+	for _, msg := range messages {
+		fromFriend := msg.SenderID == friendUUID
+		if friendDeleted && fromFriend && s.cfg.User.DeletedMessagesMode == "hide" {
+			continue
+		}
+		if friendShadowBanned && fromFriend {
+			continue
 		}
+		messageResponses = append(messageResponses, types.MessageResponse{
+			ID:            utils.UUIDToString(msg.ID),
+			SenderID:      utils.UUIDToString(msg.SenderID),
+			ReceiverID:    utils.UUIDToString(msg.ReceiverID),
+			Content:       msg.Content,
+			Status:        msg.Status,
+			CreatedAt:     msg.CreatedAt.Time.Format(time.RFC3339),
+			AcceptedAt:    msg.CreatedAt.Time.Format(time.RFC3339),
+			DeliveredAt:   optionalTime(msg.DeliveredAt),
+			ReadAt:        optionalTime(msg.ReadAt),
+			ClientSentAt:  optionalTime(msg.ClientSentAt),
+			SenderDeleted: friendDeleted && fromFriend,
+			Attachments:   attachments[msg.ID],
+		})
 	}
 
 	return &types.PaginatedResponse{
@@ -168,44 +570,457 @@ func (s *MessageService) GetMessagesBetween(ctx context.Context, input types.Lis
 		Meta: types.PaginationMeta{
 			Page:       input.Page,
 			PerPage:    input.PerPage,
-			Total:      len(messages),
+			Total:      len(messageResponses),
 			TotalPages: 0, // Calcular depois
+			NextCursor: nextCursor,
 		},
 	}, nil
 }
 
-// MarkAsDelivered marca mensagem como entregue
-func (s *MessageService) MarkAsDelivered(ctx context.Context, messageID string) error {
-	uuid, err := utils.StringToUUID(messageID)
+// sinceParam corte da retenção como argumento das consultas (zero = sem corte)
+func sinceParam(cutoff time.Time) pgtype.Timestamp {
+	if cutoff.IsZero() {
+		return pgtype.Timestamp{InfinityModifier: pgtype.NegativeInfinity, Valid: true}
+	}
+	return pgtype.Timestamp{Time: cutoff, Valid: true}
+}
+
+// listMessages página do histórico a partir de cutoff com os anexos: do
+// cache quando a página cabe nas mensagens recentes guardadas, senão da
+// réplica de leitura
+func (s *MessageService) listMessages(ctx context.Context, conv conversation, cutoff time.Time, offset, limit int) ([]repository.Message, map[pgtype.UUID][]types.AttachmentResponse, error) {
+	if !s.history.covers(offset, limit) {
+		if s.history != nil {
+			metrics.HistoryCacheTotal.WithLabelValues("bypass").Inc()
+		}
+		messages, err := s.readQueries.ListConversationMessages(ctx, repository.ListConversationMessagesParams{
+			UserA:  conv.a,
+			UserB:  conv.b,
+			Since:  sinceParam(cutoff),
+			Limit:  int32(limit),
+			Offset: int32(offset),
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("erro ao listar mensagens: %w", err)
+		}
+		attachments, err := s.attachmentsByMessage(ctx, s.readQueries, messages)
+		return messages, attachments, err
+	}
+
+	// O cache guarda a ponta da conversa sem corte (vale para qualquer plano);
+	// o corte sai antes do offset, como na consulta
+	key := conv.key()
+	if messages, attachments, ok := s.history.get(key, cutoff, offset, limit); ok {
+		metrics.HistoryCacheTotal.WithLabelValues("hit").Inc()
+		return messages, attachments, nil
+	}
+	metrics.HistoryCacheTotal.WithLabelValues("miss").Inc()
+
+	// Carrega as mensagens recentes do primário: a réplica atrasada gravaria
+	// no cache uma conversa sem a mensagem que acabou de invalidá-lo
+	s.history.begin(key)
+	messages, err := s.queries.ListConversationMessages(ctx, repository.ListConversationMessagesParams{
+		UserA:  conv.a,
+		UserB:  conv.b,
+		Since:  sinceParam(time.Time{}),
+		Limit:  int32(s.history.size),
+		Offset: 0,
+	})
+	if err != nil {
+		s.history.put(key, nil, nil)
+		return nil, nil, fmt.Errorf("erro ao listar mensagens: %w", err)
+	}
+	attachments, err := s.attachmentsByMessage(ctx, s.queries, messages)
 	if err != nil {
-		return fmt.Errorf("message_id inválido: %w", err)
+		s.history.put(key, nil, nil)
+		return nil, nil, err
 	}
+	s.history.put(key, messages, attachments)
+
+	messages = retainedSince(messages, cutoff)
+	if offset >= len(messages) {
+		return []repository.Message{}, attachments, nil
+	}
+	return messages[offset:min(offset+limit, len(messages))], attachments, nil
+}
+
+// cursorWindow margem em volta do instante do UUIDv7 ao buscar o created_at
+// do cursor: cobre relógio da instância adiantado/atrasado e o fuso da sessão
+// do banco (created_at é TIMESTAMP sem fuso)
+const cursorWindow = 24 * time.Hour
 
-	err = s.queries.UpdateMessageStatus(ctx, repository.UpdateMessageStatusParams{
-		ID:     uuid,
-		Status: "delivered",
+// listMessagesBefore página anterior à mensagem before, na ordem (created_at,
+// id) — vale para IDs antigos (UUIDv4) e novos. Com UUIDv7 o instante vem do
+// próprio ID e a busca do cursor só lê as partições vizinhas; sem ele varre
+// o índice de id de todas. Não usa o cache (páginas antigas)
+func (s *MessageService) listMessagesBefore(ctx context.Context, conv conversation, cutoff time.Time, before string, limit int) ([]repository.Message, map[pgtype.UUID][]types.AttachmentResponse, error) {
+	beforeID, err := uuid.Parse(before)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cursor inválido: %w", err)
+	}
+	cursorUUID := pgtype.UUID{Bytes: beforeID, Valid: true}
+
+	from := pgtype.Timestamp{InfinityModifier: pgtype.NegativeInfinity, Valid: true}
+	to := pgtype.Timestamp{InfinityModifier: pgtype.Infinity, Valid: true}
+	if t, ok := idgen.Time(beforeID); ok {
+		from = pgtype.Timestamp{Time: t.Add(-cursorWindow), Valid: true}
+		to = pgtype.Timestamp{Time: t.Add(cursorWindow), Valid: true}
+	}
+
+	createdAt, err := s.readQueries.GetConversationMessageCreatedAt(ctx, repository.GetConversationMessageCreatedAtParams{
+		ID:       cursorUUID,
+		UserA:    conv.a,
+		UserB:    conv.b,
+		FromTime: from,
+		ToTime:   to,
 	})
+	if err == pgx.ErrNoRows {
+		return nil, nil, fmt.Errorf("cursor inválido: mensagem não pertence à conversa")
+	}
 	if err != nil {
-		return fmt.Errorf("erro ao atualizar status: %w", err)
+		return nil, nil, fmt.Errorf("erro ao buscar cursor: %w", err)
 	}
 
-	return nil
+	if s.history != nil {
+		metrics.HistoryCacheTotal.WithLabelValues("bypass").Inc()
+	}
+	messages, err := s.readQueries.ListConversationMessagesBefore(ctx, repository.ListConversationMessagesBeforeParams{
+		UserA:           conv.a,
+		UserB:           conv.b,
+		BeforeCreatedAt: createdAt,
+		BeforeID:        cursorUUID,
+		Since:           sinceParam(cutoff),
+		Limit:           int32(limit),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao listar mensagens: %w", err)
+	}
+	attachments, err := s.attachmentsByMessage(ctx, s.readQueries, messages)
+	return messages, attachments, err
+}
+
+// attachmentsByMessage carrega anexos das mensagens da página (uma consulta)
+func (s *MessageService) attachmentsByMessage(ctx context.Context, queries *repository.Queries, messages []repository.Message) (map[pgtype.UUID][]types.AttachmentResponse, error) {
+	ids := make([]pgtype.UUID, len(messages))
+	for i, msg := range messages {
+		ids[i] = msg.ID
+	}
+
+	rows, err := queries.ListAttachmentsByMessageIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar anexos: %w", err)
+	}
+
+	byMessage := make(map[pgtype.UUID][]types.AttachmentResponse, len(rows))
+	for _, a := range rows {
+		byMessage[a.MessageID] = append(byMessage[a.MessageID], toAttachmentResponse(a, s.cfg.Storage.NSFWPolicy))
+	}
+	return byMessage, nil
 }
 
-// MarkAsRead marca mensagem como lida
-func (s *MessageService) MarkAsRead(ctx context.Context, messageID string) error {
-	uuid, err := utils.StringToUUID(messageID)
+// ErrMessageNotFound mensagem inexistente ou de outro destinatário
+var ErrMessageNotFound = errors.New("mensagem não encontrada")
+
+// MarkAsDelivered a mensagem chegou a um dispositivo do destinatário (frame
+// WebSocket entregue ou ack do cliente): accepted → delivered. Idempotente:
+// etapa já alcançada devolve o status atual sem novo evento; mensagem em
+// quarentena devolve status.ErrInvalidTransition
+func (s *MessageService) MarkAsDelivered(ctx context.Context, messageID, receiverID string) (*types.MessageStatusResponse, error) {
+	if err := authorize(ctx, receiverID); err != nil {
+		return nil, err
+	}
+	id, receiver, err := parseStatusTarget(messageID, receiverID)
 	if err != nil {
-		return fmt.Errorf("message_id inválido: %w", err)
+		return nil, err
 	}
 
-	err = s.queries.UpdateMessageStatus(ctx, repository.UpdateMessageStatusParams{
-		ID:     uuid,
-		Status: "read",
+	message, err := s.queries.MarkMessageDelivered(ctx, repository.MarkMessageDeliveredParams{
+		ID:         id,
+		ReceiverID: receiver,
 	})
+	if err == pgx.ErrNoRows {
+		return s.messageStatus(ctx, id, receiver, status.MessageDelivered)
+	}
 	if err != nil {
-		return fmt.Errorf("erro ao atualizar status: %w", err)
+		return nil, fmt.Errorf("erro ao atualizar status: %w", err)
 	}
 
-	return nil
+	s.statusChanged(ctx, message, status.MessageDelivered, message.DeliveredAt.Time)
+	return toMessageStatusResponse(message), nil
+}
+
+// statusAttempts releituras quando outra escrita (outro dispositivo, o
+// consumer) avança a versão entre a leitura e o compare-and-set
+const statusAttempts = 3
+
+// MarkAsRead o destinatário leu a mensagem: zera não lidas da conversa e,
+// com confirmação de leitura, avança para read; sem ela o remetente continua
+// vendo delivered. Perder o compare-and-set repetidamente devolve
+// *status.ConflictError
+func (s *MessageService) MarkAsRead(ctx context.Context, messageID, readerID string) (*types.MessageStatusResponse, error) {
+	if err := authorize(ctx, readerID); err != nil {
+		return nil, err
+	}
+	id, reader, err := parseStatusTarget(messageID, readerID)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := s.queries.GetMessageByID(ctx, id)
+	if err == pgx.ErrNoRows || (err == nil && message.ReceiverID != reader) {
+		return nil, ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar mensagem: %w", err)
+	}
+
+	shares, err := s.privacy.SharesReadReceipts(ctx, message.ReceiverID)
+	if err != nil {
+		return nil, err
+	}
+	target := status.MessageDelivered
+	if shares {
+		target = status.MessageRead
+	}
+	if err := advance(message.Status, target); err != nil {
+		return nil, err
+	}
+
+	var resp *types.MessageStatusResponse
+	if shares {
+		resp, err = s.markRead(ctx, message)
+	} else {
+		resp, err = s.MarkAsDelivered(ctx, messageID, readerID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Quem lê é o destinatário; o par da conversa é o remetente
+	err = s.queries.MarkConversationRead(ctx, repository.MarkConversationReadParams{
+		UserID:            message.ReceiverID,
+		PeerID:            message.SenderID,
+		LastReadMessageID: message.ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao atualizar resumo da conversa: %w", err)
+	}
+
+	return resp, nil
+}
+
+// markRead avança para read com compare-and-set na versão lida; se outra
+// escrita venceu, relê e revalida (ack repetido vira no-op)
+func (s *MessageService) markRead(ctx context.Context, message repository.Message) (*types.MessageStatusResponse, error) {
+	for attempt := 0; attempt < statusAttempts; attempt++ {
+		if message.Status.Reached(status.MessageRead) {
+			return toMessageStatusResponse(message), nil
+		}
+		if err := message.Status.Transition(status.MessageRead); err != nil {
+			return nil, err
+		}
+
+		read, err := s.queries.MarkMessageRead(ctx, repository.MarkMessageReadParams{
+			ID:         message.ID,
+			ReceiverID: message.ReceiverID,
+			Version:    message.Version,
+		})
+		if err == nil {
+			s.statusChanged(ctx, read, status.MessageRead, read.ReadAt.Time)
+			return toMessageStatusResponse(read), nil
+		}
+		if err != pgx.ErrNoRows {
+			return nil, fmt.Errorf("erro ao atualizar status: %w", err)
+		}
+
+		if message, err = s.queries.GetMessageByID(ctx, message.ID); err != nil {
+			return nil, fmt.Errorf("erro ao buscar mensagem: %w", err)
+		}
+	}
+	return nil, &status.ConflictError{
+		Entity:  "mensagem",
+		ID:      utils.UUIDToString(message.ID),
+		Version: message.Version,
+		Current: string(message.Status),
+	}
+}
+
+// messageStatus status atual da mensagem do destinatário, quando o UPDATE
+// condicional não a avançou para target
+func (s *MessageService) messageStatus(ctx context.Context, id, receiver pgtype.UUID, target status.Message) (*types.MessageStatusResponse, error) {
+	message, err := s.queries.GetMessageByID(ctx, id)
+	if err == pgx.ErrNoRows || (err == nil && message.ReceiverID != receiver) {
+		return nil, ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar mensagem: %w", err)
+	}
+	if err := advance(message.Status, target); err != nil {
+		return nil, err
+	}
+	return toMessageStatusResponse(message), nil
+}
+
+// advance valida o avanço até target; etapa já alcançada não é erro (ack
+// repetido ou fora de ordem)
+func advance(current, target status.Message) error {
+	if current.Reached(target) {
+		return nil
+	}
+	return current.Transition(target)
+}
+
+// statusChanged avisa a mudança de etapa: frame "message.status" ao remetente
+// (em qualquer instância) e evento no tópico de mensagens, na chave da
+// conversa (outras instâncias invalidam o cache de histórico). Falhas não
+// desfazem o status já gravado
+func (s *MessageService) statusChanged(ctx context.Context, message repository.Message, to status.Message, at time.Time) {
+	s.history.Invalidate(message.SenderID, message.ReceiverID)
+
+	event := events.MessageStatusChanged{
+		MessageID:  utils.UUIDToString(message.ID),
+		SenderID:   utils.UUIDToString(message.SenderID),
+		ReceiverID: utils.UUIDToString(message.ReceiverID),
+		Status:     to,
+		At:         at.Unix(),
+		Version:    message.Version,
+	}
+
+	if s.hub != nil {
+		if _, err := s.hub.SendToUser(event.SenderID, "message.status", event); err != nil {
+			fmt.Printf("WARN: Erro ao enviar status via websocket: %v\n", err)
+		}
+	}
+
+	if s.producer == nil {
+		return
+	}
+	payload, err := events.Marshal(event)
+	if err == nil {
+		err = s.producer.SendMessage(ctx, s.cfg.Kafka.Topic, utils.ConversationKey(event.SenderID, event.ReceiverID), payload)
+	}
+	if err != nil {
+		fmt.Printf("WARN: Erro ao publicar status da mensagem: %v\n", err)
+		reporter.CaptureError(ctx, err, map[string]string{
+			"component":  "kafka_producer",
+			"message_id": event.MessageID,
+		})
+	}
+}
+
+// toMessageStatusResponse etapa atual com o horário de cada uma
+func toMessageStatusResponse(message repository.Message) *types.MessageStatusResponse {
+	return &types.MessageStatusResponse{
+		MessageID:   utils.UUIDToString(message.ID),
+		Status:      message.Status,
+		AcceptedAt:  message.CreatedAt.Time.Format(time.RFC3339),
+		DeliveredAt: optionalTime(message.DeliveredAt),
+		ReadAt:      optionalTime(message.ReadAt),
+		Version:     message.Version,
+	}
+}
+
+// optionalTime RFC 3339 ou "" (etapa ainda não alcançada)
+func optionalTime(ts pgtype.Timestamp) string {
+	if !ts.Valid {
+		return ""
+	}
+	return ts.Time.Format(time.RFC3339)
+}
+
+// parseStatusTarget IDs da mensagem e do destinatário
+func parseStatusTarget(messageID, receiverID string) (pgtype.UUID, pgtype.UUID, error) {
+	id, err := utils.StringToUUID(messageID)
+	if err != nil {
+		return pgtype.UUID{}, pgtype.UUID{}, ErrMessageNotFound
+	}
+	receiver, err := utils.StringToUUID(receiverID)
+	if err != nil {
+		return pgtype.UUID{}, pgtype.UUID{}, fmt.Errorf("user_id inválido: %w", err)
+	}
+	return id, receiver, nil
+}
+
+// ConversationsETag versão da página de conversas sem listá-la: o consumidor
+// atualiza updated_at a cada mensagem ou leitura, o que invalida o ETag
+func (s *MessageService) ConversationsETag(ctx context.Context, input types.ListConversationsInput) (string, error) {
+	if err := authorize(ctx, input.UserID); err != nil {
+		return "", err
+	}
+	input = normalizeConversationsPage(input)
+
+	userUUID, err := utils.StringToUUID(input.UserID)
+	if err != nil {
+		return "", fmt.Errorf("user_id inválido: %w", err)
+	}
+
+	version, err := s.queries.GetConversationListVersion(ctx, userUUID)
+	if err != nil {
+		return "", fmt.Errorf("erro ao consultar versão das conversas: %w", err)
+	}
+
+	return utils.ETag(
+		"conversations",
+		input.UserID,
+		strconv.Itoa(input.Page),
+		strconv.Itoa(input.PerPage),
+		strconv.FormatInt(version.Conversations, 10),
+		strconv.FormatInt(version.LastUpdatedAt.Time.UnixMicro(), 10),
+	), nil
+}
+
+// normalizeConversationsPage aplica os defaults de paginação das conversas
+func normalizeConversationsPage(input types.ListConversationsInput) types.ListConversationsInput {
+	if input.Page < 1 {
+		input.Page = 1
+	}
+	if input.PerPage < 1 || input.PerPage > 100 {
+		input.PerPage = 20
+	}
+	return input
+}
+
+// ListConversations lista conversas do usuário (resumo materializado)
+func (s *MessageService) ListConversations(ctx context.Context, input types.ListConversationsInput) (*types.PaginatedResponse, error) {
+	if err := authorize(ctx, input.UserID); err != nil {
+		return nil, err
+	}
+	input = normalizeConversationsPage(input)
+
+	userUUID, err := utils.StringToUUID(input.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("user_id inválido: %w", err)
+	}
+
+	summaries, err := s.queries.ListConversationSummaries(ctx, repository.ListConversationSummariesParams{
+		UserID: userUUID,
+		Limit:  int32(input.PerPage),
+		Offset: int32((input.Page - 1) * input.PerPage),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar conversas: %w", err)
+	}
+
+	conversations := make([]types.ConversationResponse, len(summaries))
+	for i, summary := range summaries {
+		conversations[i] = types.ConversationResponse{
+			PeerID:             utils.UUIDToString(summary.PeerID),
+			LastMessageID:      utils.UUIDToString(summary.LastMessageID),
+			LastMessagePreview: summary.LastMessagePreview,
+			LastMessageAt:      summary.LastMessageAt.Time.Format(time.RFC3339),
+			UnreadCount:        int(summary.UnreadCount),
+			LastReadMessageID:  utils.UUIDToString(summary.LastReadMessageID),
+		}
+	}
+
+	return &types.PaginatedResponse{
+		Success: true,
+		Data:    conversations,
+		Meta: types.PaginationMeta{
+			Page:       input.Page,
+			PerPage:    input.PerPage,
+			Total:      len(conversations),
+			TotalPages: 0,
+		},
+	}, nil
 }
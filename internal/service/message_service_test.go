@@ -0,0 +1,372 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/config/configtest"
+	"chat-kafka-go/internal/eventbus"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/internal/repository/repotest"
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/pkg/status"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// sendDB banco falso com o caminho feliz do envio: remetente e destinatário
+// ativos, sem privacidade nem plano configurados e cota livre
+func sendDB() *repotest.DB {
+	db := repotest.New()
+	noRows := func([]interface{}) repotest.Result { return repotest.Rows() }
+	db.On("GetUsersByIDs", func(args []interface{}) repotest.Result {
+		var users [][]interface{}
+		for _, id := range args[0].([]pgtype.UUID) {
+			users = append(users, repotest.Row(repository.User{ID: id}))
+		}
+		return repotest.Rows(users...)
+	})
+	db.On("GetPrivacySettings", noRows)
+	db.On("GetUserPlan", noRows)
+	db.On("ConsumeDailyMessage", func([]interface{}) repotest.Result {
+		return repotest.Rows([]interface{}{int32(1)})
+	})
+	db.On("CreateMessage", func(args []interface{}) repotest.Result {
+		return repotest.Rows(repotest.Row(repository.Message{
+			ID:           args[0].(pgtype.UUID),
+			SenderID:     args[1].(pgtype.UUID),
+			ReceiverID:   args[2].(pgtype.UUID),
+			Content:      args[3].(string),
+			Status:       args[4].(status.Message),
+			CreatedAt:    pgtype.Timestamp{Time: time.Now(), Valid: true},
+			ClientSentAt: args[5].(pgtype.Timestamp),
+		}))
+	})
+	return db
+}
+
+// newSendService MessageService sobre o banco falso, publicando em memória
+func newSendService(tb testing.TB, db *repotest.DB) *MessageService {
+	cfg := configtest.New(tb)
+	queries := repository.New(db)
+	plans := NewPlanService(queries, cfg)
+	return NewMessageService(queries, nil, eventbus.NewMemoryBus(1), nil, NewPrivacyService(queries), nil, NewQuotaService(queries, plans), plans, cfg)
+}
+
+// BenchmarkSendMessage caminho do envio: validação, privacidade, cota,
+// INSERT (banco falso, sem rede), serialização do evento e publicação
+func BenchmarkSendMessage(b *testing.B) {
+	messages := newSendService(b, sendDB())
+
+	ctx := reqctx.WithUserID(context.Background(), testUserID)
+	input := types.SendMessageInput{
+		SenderID:   testUserID,
+		ReceiverID: otherUserID,
+		Content:    "Oi! Chego às 18h, pode ser?",
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := messages.SendMessage(ctx, input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestSendMessageRejectsDeletedUsers(t *testing.T) {
+	tests := []struct {
+		name    string
+		active  string // Único usuário ativo
+		wantErr error
+	}{
+		{"destinatário removido", testUserID, ErrRecipientNotFound},
+		{"remetente removido", otherUserID, ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := sendDB()
+			db.On("GetUsersByIDs", func([]interface{}) repotest.Result {
+				return repotest.Rows(repotest.Row(repository.User{ID: mustUUID(t, tt.active)}))
+			})
+			messages := newSendService(t, db)
+
+			_, err := messages.SendMessage(asUser(testUserID), types.SendMessageInput{
+				SenderID: testUserID, ReceiverID: otherUserID, Content: "oi",
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, quer %v", err, tt.wantErr)
+			}
+			// Recusado antes de consumir cota ou gravar
+			if len(db.Calls("ConsumeDailyMessage")) != 0 || len(db.Calls("CreateMessage")) != 0 {
+				t.Error("envio recusado consumiu cota ou gravou a mensagem")
+			}
+		})
+	}
+}
+
+func TestSendMessageAttachmentTakenRollsBack(t *testing.T) {
+	attachmentID := "44444444-4444-4444-4444-444444444444"
+	db := sendDB()
+	db.On("GetAttachmentByID", func(args []interface{}) repotest.Result {
+		return repotest.Rows(repotest.Row(repository.Attachment{
+			ID:         args[0].(pgtype.UUID),
+			UploaderID: mustUUID(t, testUserID),
+			Status:     "uploaded",
+			ScanStatus: ScanClean,
+		}))
+	})
+	// Outro envio vinculou o anexo entre a checagem e o UPDATE
+	db.On("AttachToMessage", func([]interface{}) repotest.Result {
+		return repotest.Result{RowsAffected: 0}
+	})
+	messages := newSendService(t, db)
+
+	_, err := messages.SendMessage(asUser(testUserID), types.SendMessageInput{
+		SenderID: testUserID, ReceiverID: otherUserID, Content: "oi", AttachmentID: attachmentID,
+	})
+	if err == nil {
+		t.Fatal("envio com anexo já vinculado foi aceito")
+	}
+
+	// Cota, mensagem e vínculo na mesma transação, desfeita
+	for _, name := range []string{"ConsumeDailyMessage", "CreateMessage", "AttachToMessage"} {
+		if txs := db.Tx(name); len(txs) != 1 || txs[0] != 1 {
+			t.Errorf("%s rodou nas transações %v, quer [1]", name, txs)
+		}
+	}
+	if !db.RolledBack(1) {
+		t.Error("transação do envio não foi desfeita: mensagem órfã e cota consumida")
+	}
+}
+
+// conversationDB banco falso com a semântica das consultas por par
+// (LEAST/GREATEST nos dois sentidos, ordem created_at DESC, id DESC)
+func conversationDB(messages []repository.Message) *repotest.DB {
+	samePair := func(m repository.Message, a, b pgtype.UUID) bool {
+		return (m.SenderID == a && m.ReceiverID == b) || (m.SenderID == b && m.ReceiverID == a)
+	}
+	// before (created_at, id) < (t, id) como na comparação de linhas do Postgres
+	before := func(m repository.Message, t time.Time, id pgtype.UUID) bool {
+		if !m.CreatedAt.Time.Equal(t) {
+			return m.CreatedAt.Time.Before(t)
+		}
+		return bytes.Compare(m.ID.Bytes[:], id.Bytes[:]) < 0
+	}
+	page := func(a, b pgtype.UUID, keep func(repository.Message) bool) []repository.Message {
+		var out []repository.Message
+		for _, m := range messages {
+			if samePair(m, a, b) && keep(m) {
+				out = append(out, m)
+			}
+		}
+		sort.Slice(out, func(i, j int) bool {
+			return !before(out[i], out[j].CreatedAt.Time, out[j].ID)
+		})
+		return out
+	}
+	// since created_at >= corte (-infinity = sem corte)
+	since := func(arg interface{}) func(repository.Message) bool {
+		cutoff := arg.(pgtype.Timestamp)
+		return func(m repository.Message) bool {
+			return cutoff.InfinityModifier == pgtype.NegativeInfinity || !m.CreatedAt.Time.Before(cutoff.Time)
+		}
+	}
+	rows := func(page []repository.Message) repotest.Result {
+		out := make([][]interface{}, len(page))
+		for i, m := range page {
+			out[i] = repotest.Row(m)
+		}
+		return repotest.Rows(out...)
+	}
+
+	db := repotest.New()
+	db.On("GetUserPlan", func([]interface{}) repotest.Result { return repotest.Rows() })
+	db.On("GetUserByIDIncludingDeleted", func([]interface{}) repotest.Result { return repotest.Rows() })
+	db.On("ListAttachmentsByMessageIDs", func([]interface{}) repotest.Result { return repotest.Rows() })
+	db.On("ListConversationMessages", func(args []interface{}) repotest.Result {
+		all := page(args[0].(pgtype.UUID), args[1].(pgtype.UUID), since(args[2]))
+		offset, limit := int(args[3].(int32)), int(args[4].(int32))
+		return rows(all[min(offset, len(all)):min(offset+limit, len(all))])
+	})
+	db.On("GetConversationMessageCreatedAt", func(args []interface{}) repotest.Result {
+		for _, m := range messages {
+			if m.ID == args[0].(pgtype.UUID) && samePair(m, args[1].(pgtype.UUID), args[2].(pgtype.UUID)) {
+				return repotest.Rows([]interface{}{m.CreatedAt})
+			}
+		}
+		return repotest.Rows()
+	})
+	db.On("ListConversationMessagesBefore", func(args []interface{}) repotest.Result {
+		t, id, retained := args[2].(pgtype.Timestamp).Time, args[3].(pgtype.UUID), since(args[4])
+		all := page(args[0].(pgtype.UUID), args[1].(pgtype.UUID), func(m repository.Message) bool { return before(m, t, id) && retained(m) })
+		return rows(all[:min(int(args[5].(int32)), len(all))])
+	})
+	return db
+}
+
+func testMessage(t *testing.T, id, sender, receiver string, createdAt time.Time) repository.Message {
+	t.Helper()
+	return repository.Message{
+		ID:         mustUUID(t, id),
+		SenderID:   mustUUID(t, sender),
+		ReceiverID: mustUUID(t, receiver),
+		Content:    id,
+		Status:     status.MessageAccepted,
+		CreatedAt:  pgtype.Timestamp{Time: createdAt, Valid: true},
+	}
+}
+
+func mustUUID(t *testing.T, id string) pgtype.UUID {
+	t.Helper()
+	u, err := utils.StringToUUID(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+// pageIDs IDs da página na ordem devolvida
+func pageIDs(t *testing.T, page *types.PaginatedResponse) []string {
+	t.Helper()
+	messages, ok := page.Data.([]types.MessageResponse)
+	if !ok {
+		t.Fatalf("Data = %T; esperado []types.MessageResponse", page.Data)
+	}
+	ids := make([]string, len(messages))
+	for i, m := range messages {
+		ids[i] = m.ID
+	}
+	return ids
+}
+
+func TestGetMessagesBetweenBothDirections(t *testing.T) {
+	const thirdUserID = "45c48cce-2e2d-4fbd-8f6e-0a1b2c3d4e5f"
+	base := time.Now().UTC().Add(-time.Hour).Truncate(time.Second) // Dentro da retenção do plano
+	db := conversationDB([]repository.Message{
+		testMessage(t, "00000000-0000-4000-8000-000000000001", testUserID, otherUserID, base),
+		testMessage(t, "00000000-0000-4000-8000-000000000002", otherUserID, testUserID, base.Add(time.Minute)),
+		// Mesmo created_at: desempate pelo id, decrescente
+		testMessage(t, "00000000-0000-4000-8000-000000000004", testUserID, otherUserID, base.Add(2*time.Minute)),
+		testMessage(t, "00000000-0000-4000-8000-000000000003", otherUserID, testUserID, base.Add(2*time.Minute)),
+		// Outra conversa de um dos lados
+		testMessage(t, "00000000-0000-4000-8000-000000000005", testUserID, thirdUserID, base.Add(3*time.Minute)),
+	})
+	cfg := configtest.New(t)
+	queries := repository.New(db)
+	plans := NewPlanService(queries, cfg)
+	messages := NewMessageService(queries, nil, eventbus.NewMemoryBus(1), nil, NewPrivacyService(queries), nil, NewQuotaService(queries, plans), plans, cfg)
+
+	want := []string{
+		"00000000-0000-4000-8000-000000000004",
+		"00000000-0000-4000-8000-000000000003",
+		"00000000-0000-4000-8000-000000000002",
+		"00000000-0000-4000-8000-000000000001",
+	}
+	for _, side := range []struct{ user, friend string }{
+		{testUserID, otherUserID},
+		{otherUserID, testUserID},
+	} {
+		ctx := reqctx.WithUserID(context.Background(), side.user)
+		page, err := messages.GetMessagesBetween(ctx, types.ListMessagesInput{UserID: side.user, FriendID: side.friend, PerPage: 10})
+		if err != nil {
+			t.Fatalf("GetMessagesBetween(%s): %v", side.user, err)
+		}
+		if got := pageIDs(t, page); !slices.Equal(got, want) {
+			t.Errorf("lado %s: %v; esperado %v", side.user, got, want)
+		}
+
+		// Cursor: a página seguinte continua na mesma ordem dos dois lados
+		page, err = messages.GetMessagesBetween(ctx, types.ListMessagesInput{UserID: side.user, FriendID: side.friend, PerPage: 2})
+		if err != nil {
+			t.Fatalf("GetMessagesBetween(%s): %v", side.user, err)
+		}
+		if got := pageIDs(t, page); !slices.Equal(got, want[:2]) {
+			t.Errorf("lado %s, página 1: %v; esperado %v", side.user, got, want[:2])
+		}
+		page, err = messages.GetMessagesBetween(ctx, types.ListMessagesInput{UserID: side.user, FriendID: side.friend, PerPage: 2, Before: page.Meta.NextCursor})
+		if err != nil {
+			t.Fatalf("GetMessagesBetween(%s, before): %v", side.user, err)
+		}
+		if got := pageIDs(t, page); !slices.Equal(got, want[2:]) {
+			t.Errorf("lado %s, página 2: %v; esperado %v", side.user, got, want[2:])
+		}
+	}
+}
+
+// TestGetMessagesBetweenRetentionCutoff página inteira além da retenção do
+// plano (free: 90 dias) vem vazia, sem total nem cursor, com e sem cache
+func TestGetMessagesBetweenRetentionCutoff(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	old := now.AddDate(0, 0, -100)
+	conversation := []repository.Message{
+		testMessage(t, "00000000-0000-4000-8000-000000000001", testUserID, otherUserID, old),
+		testMessage(t, "00000000-0000-4000-8000-000000000002", otherUserID, testUserID, old.Add(time.Minute)),
+		testMessage(t, "00000000-0000-4000-8000-000000000003", testUserID, otherUserID, now.Add(-time.Hour)),
+		testMessage(t, "00000000-0000-4000-8000-000000000004", otherUserID, testUserID, now.Add(-time.Minute)),
+	}
+
+	for _, tt := range []struct {
+		name  string
+		cache bool
+	}{
+		{"réplica", false},
+		{"cache", true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := configtest.New(t)
+			queries := repository.New(conversationDB(conversation))
+			plans := NewPlanService(queries, cfg)
+			var history *HistoryCache
+			if tt.cache {
+				history = NewHistoryCache(&config.HistoryConfig{CacheConversations: 10, CacheMessages: 10})
+			}
+			messages := NewMessageService(queries, queries, eventbus.NewMemoryBus(1), nil, NewPrivacyService(queries), history, NewQuotaService(queries, plans), plans, cfg)
+			ctx := reqctx.WithUserID(context.Background(), testUserID)
+
+			first, err := messages.GetMessagesBetween(ctx, types.ListMessagesInput{UserID: testUserID, FriendID: otherUserID, PerPage: 2})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := pageIDs(t, first); len(got) != 2 || first.Meta.Total != 2 || first.Meta.NextCursor == "" {
+				t.Fatalf("página 1 = %v (total %d, cursor %q); esperado as 2 recentes com cursor", got, first.Meta.Total, first.Meta.NextCursor)
+			}
+
+			for _, input := range []types.ListMessagesInput{
+				{UserID: testUserID, FriendID: otherUserID, PerPage: 2, Page: 2},
+				{UserID: testUserID, FriendID: otherUserID, PerPage: 2, Before: first.Meta.NextCursor},
+			} {
+				page, err := messages.GetMessagesBetween(ctx, input)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := pageIDs(t, page); len(got) != 0 || page.Meta.Total != 0 || page.Meta.NextCursor != "" {
+					t.Errorf("página %d/before %q = %v (total %d, cursor %q); esperado vazia, sem total nem cursor",
+						input.Page, input.Before, got, page.Meta.Total, page.Meta.NextCursor)
+				}
+			}
+		})
+	}
+}
+
+// TestConversationKeyMatchesTopicKey cache do histórico e partição do tópico
+// usam a mesma chave, igual nos dois sentidos
+func TestConversationKeyMatchesTopicKey(t *testing.T) {
+	a, b := mustUUID(t, testUserID), mustUUID(t, otherUserID)
+	want := utils.ConversationKey(testUserID, otherUserID)
+
+	if got := (conversation{a: a, b: b}).key(); got != want {
+		t.Fatalf("key(a, b) = %q; esperado %q", got, want)
+	}
+	if got := (conversation{a: b, b: a}).key(); got != want {
+		t.Fatalf("key(b, a) = %q; esperado %q", got, want)
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Tipos de notificação in-app
+const (
+	NotificationNewLogin           = "security.new_login"
+	NotificationAttachmentInfected = "attachment.infected"
+)
+
+// NotificationService gerencia notificações in-app
+type NotificationService struct {
+	queries *repository.Queries
+}
+
+// NewNotificationService cria nova instância do service
+func NewNotificationService(queries *repository.Queries) *NotificationService {
+	return &NotificationService{queries: queries}
+}
+
+// Notify cria notificação para o usuário
+func (s *NotificationService) Notify(ctx context.Context, userID pgtype.UUID, kind string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar notificação: %w", err)
+	}
+
+	_, err = s.queries.CreateNotification(ctx, repository.CreateNotificationParams{
+		UserID:  userID,
+		Kind:    kind,
+		Payload: data,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao criar notificação: %w", err)
+	}
+	return nil
+}
+
+// List retorna as notificações mais recentes do usuário
+func (s *NotificationService) List(ctx context.Context, userID string, limit int) ([]types.NotificationResponse, error) {
+	userUUID, err := utils.StringToUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	notifications, err := s.queries.ListUserNotifications(ctx, repository.ListUserNotificationsParams{
+		UserID: userUUID,
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar notificações: %w", err)
+	}
+
+	resp := make([]types.NotificationResponse, 0, len(notifications))
+	for _, n := range notifications {
+		resp = append(resp, types.NotificationResponse{
+			ID:        utils.UUIDToString(n.ID),
+			Kind:      n.Kind,
+			Payload:   n.Payload,
+			Read:      n.ReadAt.Valid,
+			CreatedAt: n.CreatedAt.Time.Format(time.RFC3339),
+		})
+	}
+	return resp, nil
+}
+
+// MarkRead marca notificação como lida
+func (s *NotificationService) MarkRead(ctx context.Context, userID, notificationID string) error {
+	userUUID, err := utils.StringToUUID(userID)
+	if err != nil {
+		return fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+	notificationUUID, err := utils.StringToUUID(notificationID)
+	if err != nil {
+		return fmt.Errorf("ID de notificação inválido: %w", err)
+	}
+
+	rows, err := s.queries.MarkNotificationRead(ctx, repository.MarkNotificationReadParams{
+		ID:     notificationUUID,
+		UserID: userUUID,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao marcar notificação: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("notificação não encontrada")
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/mailer"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrResetTokenInvalid link de redefinição inexistente, usado ou expirado
+var ErrResetTokenInvalid = errors.New("link de redefinição inválido ou expirado")
+
+// PasswordResetService envia links de redefinição de senha e troca a senha
+// com o token (uso único), encerrando as sessões abertas
+type PasswordResetService struct {
+	queries *repository.Queries
+	mailer  mailer.Mailer
+	audit   *AuditService
+	cfg     *config.Config
+	clock   clock.Clock // Expiração do link e intervalo entre pedidos
+}
+
+// NewPasswordResetService cria nova instância do service
+func NewPasswordResetService(queries *repository.Queries, mailer mailer.Mailer, audit *AuditService, cfg *config.Config) *PasswordResetService {
+	return &PasswordResetService{
+		queries: queries,
+		mailer:  mailer,
+		audit:   audit,
+		cfg:     cfg,
+		clock:   clock.System,
+	}
+}
+
+// SetClock troca o relógio (testes)
+func (s *PasswordResetService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Request envia link de redefinição; links pendentes deixam de valer. Email
+// desconhecido ou pedido dentro do intervalo mínimo não gera erro (a
+// resposta não revela contas)
+func (s *PasswordResetService) Request(ctx context.Context, email, ip string) error {
+	if email == "" {
+		return fmt.Errorf("email é obrigatório")
+	}
+
+	user, err := s.queries.GetUserByEmail(ctx, email)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+		return fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+
+	last, err := s.queries.GetLatestPasswordResetToken(ctx, user.ID)
+	if err != nil && err != pgx.ErrNoRows {
+		return fmt.Errorf("erro ao buscar último pedido: %w", err)
+	}
+	if err == nil && s.clock.Now().Sub(last.CreatedAt.Time) < s.cfg.Security.PasswordResetResendInterval {
+		log.Printf("Pedido de redefinição para %s ignorado: intervalo mínimo", user.Username)
+		return nil
+	}
+
+	token, err := utils.GenerateSecureToken()
+	if err != nil {
+		return err
+	}
+	if err := s.queries.InvalidatePasswordResetTokens(ctx, user.ID); err != nil {
+		return fmt.Errorf("erro ao invalidar links anteriores: %w", err)
+	}
+	_, err = s.queries.CreatePasswordResetToken(ctx, repository.CreatePasswordResetTokenParams{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(token),
+		Email:     user.Email,
+		Ip:        ip,
+		ExpiresAt: pgtype.Timestamp{Time: s.clock.Now().Add(s.cfg.Security.PasswordResetTTL), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao criar link de redefinição: %w", err)
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", s.cfg.Mail.BaseURL, url.QueryEscape(token))
+	body := fmt.Sprintf("Olá %s,\n\nRecebemos um pedido para redefinir sua senha (IP %s).\n\n"+
+		"Defina uma nova senha: %s\n\nO link vale por %s e pode ser usado uma vez. "+
+		"Se não foi você, ignore esta mensagem: sua senha continua a mesma.\n",
+		user.Username, ip, link, s.cfg.Security.PasswordResetTTL)
+	if err := s.mailer.Send(ctx, user.Email, "Redefinição de senha", body); err != nil {
+		return fmt.Errorf("erro ao enviar link de redefinição: %w", err)
+	}
+
+	s.audit.Record(ctx, user.ID, AuditPasswordForgot, ip, 0, nil)
+	return nil
+}
+
+// Reset troca a senha com o token do link e revoga todos os refresh tokens;
+// access tokens já emitidos valem até expirar (JWT_ACCESS_TTL)
+func (s *PasswordResetService) Reset(ctx context.Context, token, password, ip string) error {
+	if token == "" {
+		return fmt.Errorf("token é obrigatório")
+	}
+	// Senha fraca não consome o link
+	if err := validatePassword(password); err != nil {
+		return err
+	}
+
+	record, err := s.queries.GetPendingPasswordResetToken(ctx, utils.HashToken(token))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return ErrResetTokenInvalid
+		}
+		return fmt.Errorf("erro ao buscar link de redefinição: %w", err)
+	}
+
+	// Dois envios simultâneos: só um consome o token
+	rows, err := s.queries.MarkPasswordResetTokenUsed(ctx, record.ID)
+	if err != nil {
+		return fmt.Errorf("erro ao consumir link de redefinição: %w", err)
+	}
+	if rows == 0 {
+		return ErrResetTokenInvalid
+	}
+
+	passwordHash, err := utils.HashPassword(password)
+	if err != nil {
+		return fmt.Errorf("erro ao criar hash da senha: %w", err)
+	}
+	rows, err = s.queries.UpdatePassword(ctx, repository.UpdatePasswordParams{
+		ID:           record.UserID,
+		PasswordHash: passwordHash,
+		Email:        record.Email,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar senha: %w", err)
+	}
+	if rows == 0 {
+		// Email trocado (ou conta removida) depois do envio
+		return ErrResetTokenInvalid
+	}
+
+	if err := s.queries.DeleteUserRefreshTokens(ctx, record.UserID); err != nil {
+		return fmt.Errorf("erro ao revogar sessões: %w", err)
+	}
+	if err := s.queries.InvalidatePasswordResetTokens(ctx, record.UserID); err != nil {
+		log.Printf("WARN: invalidar links de redefinição: %v", err)
+	}
+	// Quem recebeu o link no email provou que o controla
+	if _, err := s.queries.MarkEmailVerified(ctx, repository.MarkEmailVerifiedParams{
+		ID:    record.UserID,
+		Email: record.Email,
+	}); err != nil {
+		log.Printf("WARN: confirmar email na redefinição de senha: %v", err)
+	}
+
+	s.audit.Record(ctx, record.UserID, AuditPasswordReset, ip, 0, nil)
+
+	body := fmt.Sprintf("Sua senha foi redefinida (IP %s) e todas as sessões foram encerradas.\n\n"+
+		"Se não foi você, peça uma nova redefinição e entre em contato com o suporte.\n", ip)
+	if err := s.mailer.Send(ctx, record.Email, "Sua senha foi alterada", body); err != nil {
+		log.Printf("WARN: aviso de senha redefinida: %v", err)
+	}
+	return nil
+}
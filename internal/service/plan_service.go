@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"chat-kafka-go/internal/billing"
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Planos
+const (
+	PlanFree      = "free"
+	PlanPro       = "pro"
+	PlanWorkspace = "workspace"
+)
+
+// planStatusManual plano atribuído pelo admin
+const planStatusManual = "manual"
+
+// planGrace tolerância depois do fim do período: a renovação chega por
+// webhook e pode atrasar
+const planGrace = 72 * time.Hour
+
+// PlanEntitlements limites de cada plano; free usa QUOTA_* e todos respeitam
+// ATTACHMENT_MAX_BYTES
+func PlanEntitlements(plan string, cfg *config.Config) types.Entitlements {
+	maxAttachment := cfg.Storage.MaxAttachmentBytes
+	switch plan {
+	case PlanPro:
+		return types.Entitlements{
+			BotDailyMessages:   10 * cfg.Quota.BotDailyMessages,
+			StorageBytes:       50 << 30,
+			MaxAttachmentBytes: maxAttachment,
+			MaxGroupMembers:    100,
+		}
+	case PlanWorkspace:
+		return types.Entitlements{
+			MaxAttachmentBytes: maxAttachment,
+			MaxGroupMembers:    1000,
+		}
+	default:
+		return types.Entitlements{
+			DailyMessages:      cfg.Quota.DailyMessages,
+			BotDailyMessages:   cfg.Quota.BotDailyMessages,
+			StorageBytes:       cfg.Quota.StorageBytes,
+			MaxAttachmentBytes: min(10<<20, maxAttachment),
+			HistoryDays:        90,
+			MaxGroupMembers:    10,
+		}
+	}
+}
+
+// validPlan plano conhecido
+func validPlan(plan string) bool {
+	return plan == PlanFree || plan == PlanPro || plan == PlanWorkspace
+}
+
+// PlanService plano efetivo por usuário (assinatura, admin ou PLAN_DEFAULT)
+type PlanService struct {
+	queries *repository.Queries
+	cfg     *config.Config
+	clock   clock.Clock // Vencimento do período e corte do histórico
+}
+
+// NewPlanService cria nova instância do service
+func NewPlanService(queries *repository.Queries, cfg *config.Config) *PlanService {
+	return &PlanService{
+		queries: queries,
+		cfg:     cfg,
+		clock:   clock.System,
+	}
+}
+
+// SetClock troca o relógio (testes)
+func (s *PlanService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Entitlements plano efetivo e limites do usuário
+func (s *PlanService) Entitlements(ctx context.Context, userUUID pgtype.UUID) (string, types.Entitlements, error) {
+	plan, _, err := s.effective(ctx, userUUID)
+	if err != nil {
+		return "", types.Entitlements{}, err
+	}
+	return plan, PlanEntitlements(plan, s.cfg), nil
+}
+
+// HistoryCutoff mensagens anteriores não aparecem no histórico do usuário
+// (zero = histórico completo)
+func (s *PlanService) HistoryCutoff(ctx context.Context, userUUID pgtype.UUID) (time.Time, error) {
+	_, ent, err := s.Entitlements(ctx, userUUID)
+	if err != nil || ent.HistoryDays == 0 {
+		return time.Time{}, err
+	}
+	return s.clock.Now().UTC().AddDate(0, 0, -ent.HistoryDays), nil
+}
+
+// Get plano efetivo do usuário (GET /users/me/plan)
+func (s *PlanService) Get(ctx context.Context, userID string) (*types.PlanResponse, error) {
+	userUUID, err := utils.StringToUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	plan, row, err := s.effective(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	return toPlanResponse(plan, row, s.cfg), nil
+}
+
+// SetPlan atribui plano sem cobrança (admin); até o próximo webhook da assinatura
+func (s *PlanService) SetPlan(ctx context.Context, userID string, input types.SetPlanInput) (*types.PlanResponse, error) {
+	userUUID, err := utils.StringToUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+	if !validPlan(input.Plan) {
+		return nil, fmt.Errorf("plano deve ser free, pro ou workspace")
+	}
+	var until pgtype.Timestamp
+	if input.Until != "" {
+		t, err := time.Parse(time.RFC3339, input.Until)
+		if err != nil {
+			return nil, fmt.Errorf("until inválido (RFC3339): %w", err)
+		}
+		until = pgtype.Timestamp{Time: t.UTC(), Valid: true}
+	}
+
+	row, err := s.queries.SetManualPlan(ctx, repository.SetManualPlanParams{
+		UserID:           userUUID,
+		Plan:             input.Plan,
+		CurrentPeriodEnd: until,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao salvar plano: %w", err)
+	}
+	return toPlanResponse(s.PlanOf(row), &row, s.cfg), nil
+}
+
+// ApplyBillingEvent grava a assinatura informada pelo provedor; evento mais
+// antigo que o último aplicado é descartado
+func (s *PlanService) ApplyBillingEvent(ctx context.Context, provider string, event *billing.Event) error {
+	userUUID, err := utils.StringToUUID(event.UserID)
+	if err != nil {
+		return fmt.Errorf("user_id da assinatura inválido: %w", err)
+	}
+	plan := event.Plan
+	if plan == "" {
+		if event.Status != billing.StatusCanceled {
+			return fmt.Errorf("assinatura %s com preço sem plano configurado", event.SubscriptionID)
+		}
+		plan = PlanFree
+	}
+
+	var periodEnd pgtype.Timestamp
+	if !event.PeriodEnd.IsZero() {
+		periodEnd = pgtype.Timestamp{Time: event.PeriodEnd.UTC(), Valid: true}
+	}
+	rows, err := s.queries.ApplyBillingPlan(ctx, repository.ApplyBillingPlanParams{
+		UserID:                userUUID,
+		Plan:                  plan,
+		Status:                event.Status,
+		BillingProvider:       &provider,
+		BillingCustomerID:     &event.CustomerID,
+		BillingSubscriptionID: &event.SubscriptionID,
+		CurrentPeriodEnd:      periodEnd,
+		BillingEventAt:        pgtype.Timestamp{Time: event.OccurredAt.UTC(), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao aplicar assinatura: %w", err)
+	}
+	if rows == 0 {
+		log.Printf("WARN: evento de cobrança %s fora de ordem descartado (usuário %s)", event.ID, event.UserID)
+		return nil
+	}
+	log.Printf("✓ Plano %s (%s) aplicado ao usuário %s", plan, event.Status, event.UserID)
+	return nil
+}
+
+// effective plano em vigor e a linha gravada (nil = sem assinatura)
+func (s *PlanService) effective(ctx context.Context, userUUID pgtype.UUID) (string, *repository.UserPlan, error) {
+	row, err := s.queries.GetUserPlan(ctx, userUUID)
+	if err == pgx.ErrNoRows {
+		return s.cfg.Plan.Default, nil, nil
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("erro ao buscar plano: %w", err)
+	}
+	return s.PlanOf(row), &row, nil
+}
+
+// PlanOf plano da linha se a assinatura está em vigor, senão PLAN_DEFAULT
+// (mesma regra para a consulta por usuário e para lotes de usuários)
+func (s *PlanService) PlanOf(row repository.UserPlan) string {
+	switch row.Status {
+	case billing.StatusActive, billing.StatusTrialing, billing.StatusPastDue, planStatusManual:
+	default:
+		return s.cfg.Plan.Default
+	}
+	if row.CurrentPeriodEnd.Valid && s.clock.Now().After(row.CurrentPeriodEnd.Time.Add(planGrace)) {
+		return s.cfg.Plan.Default
+	}
+	if !validPlan(row.Plan) {
+		return s.cfg.Plan.Default
+	}
+	return row.Plan
+}
+
+// toPlanResponse plano efetivo com os dados da assinatura
+func toPlanResponse(plan string, row *repository.UserPlan, cfg *config.Config) *types.PlanResponse {
+	resp := &types.PlanResponse{
+		Plan:         plan,
+		Entitlements: PlanEntitlements(plan, cfg),
+	}
+	if row != nil {
+		resp.Status = row.Status
+		if row.CurrentPeriodEnd.Valid {
+			resp.PeriodEnd = row.CurrentPeriodEnd.Time.Format(time.RFC3339)
+		}
+	}
+	return resp
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/events"
+	"chat-kafka-go/pkg/status"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Limites das preferências livres do cliente (chaves "client.*")
+const (
+	maxPreferenceKeys       = 100
+	maxClientPreferenceSize = 1024 // Bytes do valor JSON
+	clientPreferencePrefix  = "client."
+)
+
+// preferenceKeyPattern formato das chaves (inclusive as do cliente)
+var preferenceKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_.-]{0,63}$`)
+
+// preferenceRule schema de uma chave conhecida: padrão e validação do valor
+type preferenceRule struct {
+	def      json.RawMessage
+	validate func(json.RawMessage) error
+}
+
+// preferenceSchema chaves conhecidas; nova preferência de produto entra aqui
+var preferenceSchema = map[string]preferenceRule{
+	"theme":           {def: json.RawMessage(`"system"`), validate: enumPreference("light", "dark", "system")},
+	"message_density": {def: json.RawMessage(`"comfortable"`), validate: enumPreference("comfortable", "compact")},
+	"enter_to_send":   {def: json.RawMessage(`true`), validate: boolPreference},
+}
+
+// PreferencesService preferências de interface sincronizadas entre
+// dispositivos: chaves do schema validadas, "client.*" livres (JSON pequeno).
+// Cada mudança vira frame "preferences.updated" em todos os dispositivos
+type PreferencesService struct {
+	queries *repository.Queries
+	hub     RealtimeDeliverer // nil = sem frame de sincronização
+}
+
+// NewPreferencesService cria nova instância do service
+func NewPreferencesService(queries *repository.Queries, hub RealtimeDeliverer) *PreferencesService {
+	return &PreferencesService{queries: queries, hub: hub}
+}
+
+// Get retorna as preferências do usuário (padrões para o que não mudou)
+func (s *PreferencesService) Get(ctx context.Context, userID string) (*types.PreferencesResponse, error) {
+	if err := authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	uuid, err := utils.StringToUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	current, err := s.queries.GetUserPreferences(ctx, uuid)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, fmt.Errorf("erro ao buscar preferências: %w", err)
+	}
+	return toPreferencesResponse(current)
+}
+
+// Update mescla as chaves enviadas (null remove) e avisa os dispositivos.
+// Sem versão do cliente, conflito com outra escrita é resolvido relendo e
+// mesclando de novo; com versão, o conflito volta para o cliente
+func (s *PreferencesService) Update(ctx context.Context, input types.UpdatePreferencesInput) (*types.PreferencesResponse, error) {
+	if err := authorize(ctx, input.UserID); err != nil {
+		return nil, err
+	}
+	if len(input.Preferences) == 0 {
+		return nil, fmt.Errorf("preferences é obrigatório")
+	}
+	for key, value := range input.Preferences {
+		if err := validatePreference(key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	uuid, err := utils.StringToUUID(input.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	var read int32
+	for attempt := 0; attempt < statusAttempts; attempt++ {
+		current, err := s.queries.GetUserPreferences(ctx, uuid)
+		read = current.Version
+		if err != nil && err != pgx.ErrNoRows {
+			return nil, fmt.Errorf("erro ao buscar preferências: %w", err)
+		}
+		if input.Version != nil && *input.Version != current.Version {
+			return nil, preferencesConflict(input, current.Version)
+		}
+
+		prefs, err := mergePreferences(current.Prefs, input.Preferences)
+		if err != nil {
+			return nil, err
+		}
+		saved, err := s.queries.SaveUserPreferences(ctx, repository.SaveUserPreferencesParams{
+			UserID:  uuid,
+			Prefs:   prefs,
+			Version: current.Version,
+		})
+		if err == pgx.ErrNoRows {
+			if input.Version != nil {
+				return nil, preferencesConflict(input, current.Version)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("erro ao salvar preferências: %w", err)
+		}
+
+		resp, err := toPreferencesResponse(saved)
+		if err != nil {
+			return nil, err
+		}
+		s.broadcast(input.UserID, saved, resp)
+		return resp, nil
+	}
+	return nil, preferencesConflict(input, read)
+}
+
+// broadcast envia o frame "preferences.updated" a todos os dispositivos
+// (inclusive o que fez a mudança; a versão permite ignorar o eco)
+func (s *PreferencesService) broadcast(userID string, saved repository.UserPreference, resp *types.PreferencesResponse) {
+	if s.hub == nil {
+		return
+	}
+	event := events.PreferencesUpdated{
+		UserID:      userID,
+		Preferences: resp.Preferences,
+		Version:     saved.Version,
+		UpdatedAt:   saved.UpdatedAt.Time.Unix(),
+	}
+	if _, err := s.hub.SendToUser(userID, events.TypePreferencesUpdated, event); err != nil {
+		fmt.Printf("WARN: Erro ao enviar preferências via websocket: %v\n", err)
+	}
+}
+
+// mergePreferences aplica as mudanças sobre o JSON salvo
+func mergePreferences(stored []byte, changes map[string]json.RawMessage) ([]byte, error) {
+	prefs := map[string]json.RawMessage{}
+	if len(stored) > 0 {
+		if err := json.Unmarshal(stored, &prefs); err != nil {
+			return nil, fmt.Errorf("preferências salvas inválidas: %w", err)
+		}
+	}
+	for key, value := range changes {
+		if isJSONNull(value) {
+			delete(prefs, key)
+			continue
+		}
+		prefs[key] = value
+	}
+	if len(prefs) > maxPreferenceKeys {
+		return nil, fmt.Errorf("máximo de %d preferências", maxPreferenceKeys)
+	}
+	return json.Marshal(prefs)
+}
+
+// validatePreference confere a chave e o valor contra o schema
+func validatePreference(key string, value json.RawMessage) error {
+	if !preferenceKeyPattern.MatchString(key) {
+		return fmt.Errorf("chave de preferência inválida: %q", key)
+	}
+	if isJSONNull(value) {
+		return nil
+	}
+	if strings.HasPrefix(key, clientPreferencePrefix) {
+		if len(value) > maxClientPreferenceSize {
+			return fmt.Errorf("%s: valor muito grande (máximo %d bytes)", key, maxClientPreferenceSize)
+		}
+		return nil
+	}
+	rule, ok := preferenceSchema[key]
+	if !ok {
+		return fmt.Errorf("preferência desconhecida: %q (use o prefixo %q para chaves do cliente)", key, clientPreferencePrefix)
+	}
+	if err := rule.validate(value); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return nil
+}
+
+// enumPreference aceita uma das strings listadas
+func enumPreference(allowed ...string) func(json.RawMessage) error {
+	return func(value json.RawMessage) error {
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return errors.New("deve ser texto")
+		}
+		for _, a := range allowed {
+			if s == a {
+				return nil
+			}
+		}
+		return fmt.Errorf("deve ser um de: %s", strings.Join(allowed, ", "))
+	}
+}
+
+// boolPreference aceita true ou false
+func boolPreference(value json.RawMessage) error {
+	var b bool
+	if err := json.Unmarshal(value, &b); err != nil {
+		return errors.New("deve ser true ou false")
+	}
+	return nil
+}
+
+func isJSONNull(value json.RawMessage) bool {
+	return len(value) == 0 || strings.TrimSpace(string(value)) == "null"
+}
+
+func preferencesConflict(input types.UpdatePreferencesInput, current int32) error {
+	read := current
+	if input.Version != nil {
+		read = *input.Version
+	}
+	return &status.ConflictError{Entity: "preferências", ID: input.UserID, Version: read}
+}
+
+// toPreferencesResponse padrões do schema + preferências salvas
+func toPreferencesResponse(saved repository.UserPreference) (*types.PreferencesResponse, error) {
+	prefs := make(map[string]json.RawMessage, len(preferenceSchema))
+	for key, rule := range preferenceSchema {
+		prefs[key] = rule.def
+	}
+	if len(saved.Prefs) > 0 {
+		var stored map[string]json.RawMessage
+		if err := json.Unmarshal(saved.Prefs, &stored); err != nil {
+			return nil, fmt.Errorf("preferências salvas inválidas: %w", err)
+		}
+		for key, value := range stored {
+			prefs[key] = value
+		}
+	}
+
+	resp := &types.PreferencesResponse{Preferences: prefs, Version: saved.Version}
+	if saved.UpdatedAt.Valid {
+		resp.UpdatedAt = saved.UpdatedAt.Time.Format(time.RFC3339)
+	}
+	return resp, nil
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// ErrPresenceHidden usuário não compartilha presença com quem consulta
+var ErrPresenceHidden = errors.New("presença não visível para este usuário")
+
+// OnlineChecker indica se o usuário tem conexão WebSocket
+// (hub local ou registro de conexões do cluster)
+type OnlineChecker interface {
+	Online(ctx context.Context, userID string) (bool, error)
+}
+
+// PresenceService consulta online/offline respeitando a privacidade
+type PresenceService struct {
+	privacy *PrivacyService
+	online  OnlineChecker
+}
+
+// NewPresenceService cria nova instância do service
+func NewPresenceService(privacy *PrivacyService, online OnlineChecker) *PresenceService {
+	return &PresenceService{
+		privacy: privacy,
+		online:  online,
+	}
+}
+
+// Get presença de target vista por viewer; viewer vazio (integração) vê sempre
+func (s *PresenceService) Get(ctx context.Context, viewerID, targetID string) (*types.PresenceResponse, error) {
+	target, err := utils.StringToUUID(targetID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	if viewerID != "" {
+		viewer, err := utils.StringToUUID(viewerID)
+		if err != nil {
+			return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+		}
+		visible, err := s.privacy.CanSeePresence(ctx, viewer, target)
+		if err != nil {
+			return nil, err
+		}
+		if !visible {
+			return nil, ErrPresenceHidden
+		}
+	}
+
+	online, err := s.online.Online(ctx, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar presença: %w", err)
+	}
+	return &types.PresenceResponse{UserID: targetID, Online: online}, nil
+}
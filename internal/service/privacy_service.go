@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/status"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Valores aceitos nas configurações de privacidade
+const (
+	PolicyEveryone = "everyone"
+	PolicyFriends  = "friends"
+	PolicyNobody   = "nobody"
+)
+
+// PrivacyService gerencia e aplica configurações de privacidade
+type PrivacyService struct {
+	queries *repository.Queries
+}
+
+// NewPrivacyService cria nova instância do service
+func NewPrivacyService(queries *repository.Queries) *PrivacyService {
+	return &PrivacyService{
+		queries: queries,
+	}
+}
+
+// GetSettings retorna configurações do usuário (padrões se nunca alteradas)
+func (s *PrivacyService) GetSettings(ctx context.Context, userID string) (*types.PrivacySettingsResponse, error) {
+	if err := authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	uuid, err := utils.StringToUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	settings, err := s.getSettings(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	return toPrivacySettingsResponse(settings), nil
+}
+
+// UpdateSettings atualiza configurações de privacidade
+func (s *PrivacyService) UpdateSettings(ctx context.Context, input types.UpdatePrivacySettingsInput) (*types.PrivacySettingsResponse, error) {
+	if err := authorize(ctx, input.UserID); err != nil {
+		return nil, err
+	}
+	if input.MessagePolicy != PolicyEveryone && input.MessagePolicy != PolicyFriends {
+		return nil, fmt.Errorf("message_policy deve ser everyone ou friends")
+	}
+	if input.PresenceVisibility != PolicyEveryone && input.PresenceVisibility != PolicyFriends && input.PresenceVisibility != PolicyNobody {
+		return nil, fmt.Errorf("presence_visibility deve ser everyone, friends ou nobody")
+	}
+
+	uuid, err := utils.StringToUUID(input.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	settings, err := s.queries.UpsertPrivacySettings(ctx, repository.UpsertPrivacySettingsParams{
+		UserID:             uuid,
+		MessagePolicy:      input.MessagePolicy,
+		PresenceVisibility: input.PresenceVisibility,
+		ShareReadReceipts:  input.ShareReadReceipts,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao salvar configurações: %w", err)
+	}
+	return toPrivacySettingsResponse(settings), nil
+}
+
+// CanMessage verifica se sender pode enviar mensagem para receiver
+func (s *PrivacyService) CanMessage(ctx context.Context, senderID, receiverID pgtype.UUID) (bool, error) {
+	settings, err := s.getSettings(ctx, receiverID)
+	if err != nil {
+		return false, err
+	}
+	if settings.MessagePolicy == PolicyEveryone {
+		return true, nil
+	}
+	return s.areFriends(ctx, senderID, receiverID)
+}
+
+// CanSeePresence verifica se viewer pode ver online/offline de target
+func (s *PrivacyService) CanSeePresence(ctx context.Context, viewerID, targetID pgtype.UUID) (bool, error) {
+	if viewerID == targetID {
+		return true, nil
+	}
+
+	settings, err := s.getSettings(ctx, targetID)
+	if err != nil {
+		return false, err
+	}
+
+	switch settings.PresenceVisibility {
+	case PolicyEveryone:
+		return true, nil
+	case PolicyFriends:
+		return s.areFriends(ctx, viewerID, targetID)
+	default:
+		return false, nil
+	}
+}
+
+// SharesReadReceipts indica se o usuário compartilha confirmação de leitura
+func (s *PrivacyService) SharesReadReceipts(ctx context.Context, userID pgtype.UUID) (bool, error) {
+	settings, err := s.getSettings(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return settings.ShareReadReceipts, nil
+}
+
+// getSettings busca configurações, retornando padrões se não houver linha
+func (s *PrivacyService) getSettings(ctx context.Context, userID pgtype.UUID) (repository.UserPrivacySetting, error) {
+	settings, err := s.queries.GetPrivacySettings(ctx, userID)
+	if err == pgx.ErrNoRows {
+		return repository.UserPrivacySetting{
+			UserID:             userID,
+			MessagePolicy:      PolicyEveryone,
+			PresenceVisibility: PolicyEveryone,
+			ShareReadReceipts:  true,
+		}, nil
+	}
+	if err != nil {
+		return repository.UserPrivacySetting{}, fmt.Errorf("erro ao buscar configurações de privacidade: %w", err)
+	}
+	return settings, nil
+}
+
+// areFriends verifica amizade aceita em qualquer direção
+func (s *PrivacyService) areFriends(ctx context.Context, a, b pgtype.UUID) (bool, error) {
+	friendship, err := s.queries.GetFriendship(ctx, repository.GetFriendshipParams{
+		UserID:   a,
+		FriendID: b,
+	})
+	if err == pgx.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar amizade: %w", err)
+	}
+	return friendship.Status == status.FriendshipAccepted, nil
+}
+
+func toPrivacySettingsResponse(settings repository.UserPrivacySetting) *types.PrivacySettingsResponse {
+	resp := &types.PrivacySettingsResponse{
+		MessagePolicy:      settings.MessagePolicy,
+		PresenceVisibility: settings.PresenceVisibility,
+		ShareReadReceipts:  settings.ShareReadReceipts,
+	}
+	if settings.UpdatedAt.Valid {
+		resp.UpdatedAt = settings.UpdatedAt.Time.Format(time.RFC3339)
+	}
+	return resp
+}
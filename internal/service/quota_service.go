@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"chat-kafka-go/internal/metrics"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrQuotaExceeded cota do usuário esgotada (mensagens do dia ou armazenamento)
+var ErrQuotaExceeded = errors.New("cota excedida")
+
+// QuotaService conta o uso por usuário e aplica as cotas do plano
+type QuotaService struct {
+	queries *repository.Queries
+	plans   *PlanService
+	clock   clock.Clock // Dia corrente (UTC)
+}
+
+// NewQuotaService cria nova instância do service
+func NewQuotaService(queries *repository.Queries, plans *PlanService) *QuotaService {
+	return &QuotaService{
+		queries: queries,
+		plans:   plans,
+		clock:   clock.System,
+	}
+}
+
+// SetClock troca o relógio (testes)
+func (s *QuotaService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// WithTx cópia do service que conta o uso pelas queries da transação q
+// (a cota volta se o envio for desfeito)
+func (s *QuotaService) WithTx(q *repository.Queries) *QuotaService {
+	tx := *s
+	tx.queries = q
+	return &tx
+}
+
+// ConsumeMessage conta uma mensagem do dia; bot = enviada com chave de API.
+// A contagem e a checagem são um único UPSERT: envios simultâneos não passam
+// do limite
+func (s *QuotaService) ConsumeMessage(ctx context.Context, userUUID pgtype.UUID, bot bool) error {
+	_, ent, err := s.plans.Entitlements(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+	limit := messageLimit(ent, bot)
+	max := int32(math.MaxInt32)
+	if limit > 0 {
+		max = int32(limit)
+	}
+
+	_, err = s.queries.ConsumeDailyMessage(ctx, repository.ConsumeDailyMessageParams{
+		UserID:      userUUID,
+		Day:         s.today(),
+		MaxMessages: max,
+	})
+	if err == pgx.ErrNoRows {
+		quota := "messages"
+		if bot {
+			quota = "bot_messages"
+		}
+		metrics.QuotaExceededTotal.WithLabelValues(quota).Inc()
+		return fmt.Errorf("%w: limite de %d mensagens por dia", ErrQuotaExceeded, limit)
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao contar mensagem: %w", err)
+	}
+	return nil
+}
+
+// CheckAttachment confere o anexo de size bytes contra o tamanho máximo do
+// plano e a cota de armazenamento. Uploads simultâneos podem ultrapassar a
+// cota por um anexo
+func (s *QuotaService) CheckAttachment(ctx context.Context, userUUID pgtype.UUID, size int64) error {
+	plan, ent, err := s.plans.Entitlements(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+	if ent.MaxAttachmentBytes > 0 && size > ent.MaxAttachmentBytes {
+		return fmt.Errorf("%w: plano %s permite até %d bytes", ErrAttachmentTooLarge, plan, ent.MaxAttachmentBytes)
+	}
+	limit := ent.StorageBytes
+	if limit == 0 {
+		return nil
+	}
+
+	used, err := s.queries.SumAttachmentBytesByUploader(ctx, userUUID)
+	if err != nil {
+		return fmt.Errorf("erro ao calcular armazenamento: %w", err)
+	}
+	if used+size > limit {
+		metrics.QuotaExceededTotal.WithLabelValues("storage").Inc()
+		return fmt.Errorf("%w: armazenamento de %d bytes (%d usados)", ErrQuotaExceeded, limit, used)
+	}
+	return nil
+}
+
+// Usage uso atual e cotas do plano do usuário; bot escolhe a cota de mensagens
+func (s *QuotaService) Usage(ctx context.Context, userID string, bot bool) (*types.UsageResponse, error) {
+	userUUID, err := utils.StringToUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	plan, ent, err := s.plans.Entitlements(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	day := s.today()
+	messages, err := s.queries.GetDailyMessages(ctx, repository.GetDailyMessagesParams{
+		UserID: userUUID,
+		Day:    day,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar uso: %w", err)
+	}
+	storage, err := s.queries.SumAttachmentBytesByUploader(ctx, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao calcular armazenamento: %w", err)
+	}
+
+	return &types.UsageResponse{
+		Plan:     plan,
+		Day:      day.Time.Format(time.DateOnly),
+		ResetsAt: day.Time.AddDate(0, 0, 1).Format(time.RFC3339),
+		Messages: types.QuotaUsage{Used: int64(messages), Limit: int64(messageLimit(ent, bot))},
+		Storage:  types.QuotaUsage{Used: storage, Limit: ent.StorageBytes},
+	}, nil
+}
+
+// messageLimit cota diária de mensagens do plano (0 = sem limite)
+func messageLimit(ent types.Entitlements, bot bool) int {
+	if bot {
+		return ent.BotDailyMessages
+	}
+	return ent.DailyMessages
+}
+
+// today dia corrente em UTC
+func (s *QuotaService) today() pgtype.Date {
+	now := s.clock.Now().UTC()
+	return pgtype.Date{Time: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), Valid: true}
+}
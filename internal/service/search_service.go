@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Tipos aceitos na busca global
+const (
+	SearchTypeUsers         = "users"
+	SearchTypeConversations = "conversations"
+	SearchTypeMessages      = "messages"
+)
+
+// searchTypes ordem fixa de execução e validação
+var searchTypes = []string{SearchTypeUsers, SearchTypeConversations, SearchTypeMessages}
+
+// Limites do termo buscado (em caracteres)
+const (
+	minSearchQuery = 2
+	maxSearchQuery = 100
+)
+
+// MessageIndex backend externo de busca de mensagens
+// Implementado por search.Index (Elasticsearch/OpenSearch)
+type MessageIndex interface {
+	Enabled() bool
+	SearchMessages(ctx context.Context, userID, query string, limit, offset int) ([]string, error)
+}
+
+// SearchService busca global em usuários, conversas e mensagens
+// Os resultados respeitam o que o usuário já pode ver: só conversas e
+// mensagens de que participa, sem contas removidas ou em shadow ban
+type SearchService struct {
+	readQueries *repository.Queries
+	index       MessageIndex // Mensagens; desabilitado ou com erro = FTS do Postgres
+	cfg         *config.Config
+}
+
+// NewSearchService cria nova instância do service (consultas na réplica)
+func NewSearchService(readQueries *repository.Queries, index MessageIndex, cfg *config.Config) *SearchService {
+	return &SearchService{
+		readQueries: readQueries,
+		index:       index,
+		cfg:         cfg,
+	}
+}
+
+// Search executa a busca nos tipos pedidos, cada um com sua paginação
+func (s *SearchService) Search(ctx context.Context, input types.SearchInput) (*types.SearchResponse, error) {
+	query := strings.TrimSpace(input.Query)
+	if n := utf8.RuneCountInString(query); n < minSearchQuery || n > maxSearchQuery {
+		return nil, fmt.Errorf("termo de busca deve ter entre %d e %d caracteres", minSearchQuery, maxSearchQuery)
+	}
+
+	kinds := input.Types
+	if len(kinds) == 0 {
+		kinds = searchTypes
+	}
+	for _, kind := range kinds {
+		if !slices.Contains(searchTypes, kind) {
+			return nil, fmt.Errorf("tipo de busca inválido: %s", kind)
+		}
+	}
+
+	if input.PerPage < 1 || input.PerPage > 50 {
+		input.PerPage = 10 // Default: 10 por tipo
+	}
+
+	userUUID, err := utils.StringToUUID(input.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("user_id inválido: %w", err)
+	}
+
+	resp := &types.SearchResponse{Query: query}
+	for _, kind := range searchTypes {
+		if !slices.Contains(kinds, kind) {
+			continue
+		}
+
+		page := input.Pages[kind]
+		if page < 1 {
+			page = 1
+		}
+		// Busca um a mais para saber se há próxima página sem COUNT
+		limit := int32(input.PerPage + 1)
+		offset := int32((page - 1) * input.PerPage)
+
+		var items []interface{}
+		switch kind {
+		case SearchTypeUsers:
+			items, err = s.searchUsers(ctx, repository.SearchUsersParams{
+				ViewerID: userUUID,
+				Pattern:  likePattern(query),
+				Query:    query,
+				Limit:    limit,
+				Offset:   offset,
+			})
+		case SearchTypeConversations:
+			items, err = s.searchConversations(ctx, repository.SearchConversationsParams{
+				UserID:  userUUID,
+				Pattern: likePattern(query),
+				Limit:   limit,
+				Offset:  offset,
+			})
+		case SearchTypeMessages:
+			items, err = s.searchMessages(ctx, repository.SearchMessagesParams{
+				UserID: userUUID,
+				Query:  query,
+				Limit:  limit,
+				Offset: offset,
+			})
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		section := &types.SearchSection{
+			Items:   items,
+			Page:    page,
+			PerPage: input.PerPage,
+			HasMore: len(items) > input.PerPage,
+		}
+		if section.HasMore {
+			section.Items = items[:input.PerPage]
+		}
+
+		switch kind {
+		case SearchTypeUsers:
+			resp.Users = section
+		case SearchTypeConversations:
+			resp.Conversations = section
+		case SearchTypeMessages:
+			resp.Messages = section
+		}
+	}
+
+	return resp, nil
+}
+
+// searchUsers busca por username (sem email: a busca alcança desconhecidos)
+func (s *SearchService) searchUsers(ctx context.Context, params repository.SearchUsersParams) ([]interface{}, error) {
+	rows, err := s.readQueries.SearchUsers(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar usuários: %w", err)
+	}
+
+	items := make([]interface{}, len(rows))
+	for i, row := range rows {
+		items[i] = types.SearchUserResult{
+			ID:        utils.UUIDToString(row.ID),
+			Username:  row.Username,
+			IsFriend:  row.IsFriend,
+			CreatedAt: row.CreatedAt.Time.Format(time.RFC3339),
+		}
+	}
+	return items, nil
+}
+
+// searchConversations busca conversas pelo username do par
+func (s *SearchService) searchConversations(ctx context.Context, params repository.SearchConversationsParams) ([]interface{}, error) {
+	rows, err := s.readQueries.SearchConversations(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar conversas: %w", err)
+	}
+
+	items := make([]interface{}, len(rows))
+	for i, row := range rows {
+		items[i] = types.SearchConversationResult{
+			PeerID:             utils.UUIDToString(row.PeerID),
+			PeerUsername:       row.PeerUsername,
+			LastMessagePreview: row.LastMessagePreview,
+			LastMessageAt:      row.LastMessageAt.Time.Format(time.RFC3339),
+			UnreadCount:        int(row.UnreadCount),
+		}
+	}
+	return items, nil
+}
+
+// searchMessages busca texto completo nas mensagens do usuário, no índice
+// externo quando habilitado e no Postgres como fallback
+func (s *SearchService) searchMessages(ctx context.Context, params repository.SearchMessagesParams) ([]interface{}, error) {
+	if s.index.Enabled() {
+		items, err := s.searchMessagesIndex(ctx, params)
+		if err == nil {
+			return items, nil
+		}
+		log.Printf("ERRO: busca no índice externo, usando Postgres: %v", err)
+	}
+
+	rows, err := s.readQueries.SearchMessages(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar mensagens: %w", err)
+	}
+
+	items := make([]interface{}, 0, len(rows))
+	for _, row := range rows {
+		if msg, ok := s.searchMessageResult(repository.ListSearchMessagesByIDsRow(row)); ok {
+			items = append(items, msg)
+		}
+	}
+	return items, nil
+}
+
+// searchMessagesIndex busca IDs no índice e carrega as mensagens do Postgres,
+// que continua sendo a fonte da verdade para conteúdo e visibilidade
+func (s *SearchService) searchMessagesIndex(ctx context.Context, params repository.SearchMessagesParams) ([]interface{}, error) {
+	ids, err := s.index.SearchMessages(ctx, utils.UUIDToString(params.UserID), params.Query, int(params.Limit), int(params.Offset))
+	if err != nil {
+		return nil, err
+	}
+
+	uuids := make([]pgtype.UUID, 0, len(ids))
+	for _, id := range ids {
+		if uuid, err := utils.StringToUUID(id); err == nil {
+			uuids = append(uuids, uuid)
+		}
+	}
+
+	rows, err := s.readQueries.ListSearchMessagesByIDs(ctx, repository.ListSearchMessagesByIDsParams{
+		Ids:    uuids,
+		UserID: params.UserID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao carregar mensagens: %w", err)
+	}
+	byID := make(map[pgtype.UUID]repository.ListSearchMessagesByIDsRow, len(rows))
+	for _, row := range rows {
+		byID[row.ID] = row
+	}
+
+	// Mantém a ordem de relevância do índice
+	items := make([]interface{}, 0, len(rows))
+	for _, id := range uuids {
+		row, ok := byID[id]
+		if !ok {
+			continue
+		}
+		if msg, ok := s.searchMessageResult(row); ok {
+			items = append(items, msg)
+		}
+	}
+	return items, nil
+}
+
+// searchMessageResult converte resultado; mensagens de remetente removido
+// seguem DeletedMessagesMode, como no histórico
+func (s *SearchService) searchMessageResult(row repository.ListSearchMessagesByIDsRow) (types.MessageResponse, bool) {
+	if row.SenderDeleted && s.cfg.User.DeletedMessagesMode == "hide" {
+		return types.MessageResponse{}, false
+	}
+	return types.MessageResponse{
+		ID:            utils.UUIDToString(row.ID),
+		SenderID:      utils.UUIDToString(row.SenderID),
+		ReceiverID:    utils.UUIDToString(row.ReceiverID),
+		Content:       row.Content,
+		Status:        row.Status,
+		CreatedAt:     row.CreatedAt.Time.Format(time.RFC3339),
+		SenderDeleted: row.SenderDeleted,
+	}, true
+}
+
+// likePattern envolve o termo em % escapando curingas do ILIKE
+func likePattern(query string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(query)
+	return "%" + escaped + "%"
+}
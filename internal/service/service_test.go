@@ -0,0 +1,6 @@
+package service
+
+const (
+	testUserID  = "8f14e45f-ceea-4e67-a5c9-7b1a2d3e4f50"
+	otherUserID = "c9f0f895-fb98-4b91-9d2e-6f1a3c5b7d80"
+)
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrShareLinkInvalid link inexistente, expirado ou revogado
+var ErrShareLinkInvalid = errors.New("link inválido ou expirado")
+
+// ShareLinkService links somente leitura do histórico de uma conversa. O
+// token só é aceito em GET /shared/{token}/messages: não vale como JWT nem
+// chave de API, então não envia mensagens nem acessa outras rotas
+type ShareLinkService struct {
+	queries  *repository.Queries
+	messages *MessageService
+	cfg      *config.Config
+	clock    clock.Clock // Expiração dos links
+}
+
+// NewShareLinkService cria nova instância do service
+func NewShareLinkService(queries *repository.Queries, messages *MessageService, cfg *config.Config) *ShareLinkService {
+	return &ShareLinkService{
+		queries:  queries,
+		messages: messages,
+		cfg:      cfg,
+		clock:    clock.System,
+	}
+}
+
+// SetClock troca o relógio (testes)
+func (s *ShareLinkService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Create gera link da conversa do dono com o par; o token só aparece aqui
+func (s *ShareLinkService) Create(ctx context.Context, input types.CreateShareLinkInput) (*types.ShareLinkResponse, error) {
+	ownerUUID, err := utils.StringToUUID(input.OwnerID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+	peerUUID, err := utils.StringToUUID(input.PeerID)
+	if err != nil {
+		return nil, fmt.Errorf("ID da conversa inválido: %w", err)
+	}
+	if ownerUUID == peerUUID {
+		return nil, fmt.Errorf("conversa inválida")
+	}
+	if _, err := s.queries.GetUserByID(ctx, peerUUID); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("conversa não encontrada")
+		}
+		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+
+	ttl := s.cfg.User.ShareLinkTTL
+	if input.ExpiresIn != "" {
+		ttl, err = time.ParseDuration(input.ExpiresIn)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("expires_in inválido (use uma duração, ex.: 24h)")
+		}
+		if ttl > s.cfg.User.ShareLinkMaxTTL {
+			return nil, fmt.Errorf("expires_in máximo é %s", s.cfg.User.ShareLinkMaxTTL)
+		}
+	}
+
+	token, err := utils.GenerateSecureToken()
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := s.queries.CreateShareLink(ctx, repository.CreateShareLinkParams{
+		OwnerID:   ownerUUID,
+		PeerID:    peerUUID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: pgtype.Timestamp{Time: s.clock.Now().Add(ttl), Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar link: %w", err)
+	}
+
+	resp := toShareLinkResponse(link)
+	resp.Token = token
+	resp.Link = fmt.Sprintf("%s/shared/%s", s.cfg.Mail.BaseURL, token)
+	return &resp, nil
+}
+
+// List links válidos do dono para a conversa
+func (s *ShareLinkService) List(ctx context.Context, ownerID, peerID string) ([]types.ShareLinkResponse, error) {
+	ownerUUID, err := utils.StringToUUID(ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+	peerUUID, err := utils.StringToUUID(peerID)
+	if err != nil {
+		return nil, fmt.Errorf("ID da conversa inválido: %w", err)
+	}
+
+	links, err := s.queries.ListShareLinks(ctx, repository.ListShareLinksParams{OwnerID: ownerUUID, PeerID: peerUUID})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar links: %w", err)
+	}
+
+	resp := make([]types.ShareLinkResponse, len(links))
+	for i, link := range links {
+		resp[i] = toShareLinkResponse(link)
+	}
+	return resp, nil
+}
+
+// Revoke invalida o link na hora (só o dono)
+func (s *ShareLinkService) Revoke(ctx context.Context, ownerID, linkID string) error {
+	ownerUUID, err := utils.StringToUUID(ownerID)
+	if err != nil {
+		return fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+	linkUUID, err := utils.StringToUUID(linkID)
+	if err != nil {
+		return fmt.Errorf("ID do link inválido: %w", err)
+	}
+
+	rows, err := s.queries.RevokeShareLink(ctx, repository.RevokeShareLinkParams{ID: linkUUID, OwnerID: ownerUUID})
+	if err != nil {
+		return fmt.Errorf("erro ao revogar link: %w", err)
+	}
+	if rows == 0 {
+		return ErrShareLinkInvalid
+	}
+	return nil
+}
+
+// History histórico visto pelo dono do link (mesma retenção e ocultações do
+// GET /messages/{peerID}); input.UserID e FriendID vêm do link
+func (s *ShareLinkService) History(ctx context.Context, token string, input types.ListMessagesInput) (*types.PaginatedResponse, error) {
+	link, err := s.queries.GetActiveShareLinkByTokenHash(ctx, utils.HashToken(token))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrShareLinkInvalid
+		}
+		return nil, fmt.Errorf("erro ao buscar link: %w", err)
+	}
+
+	input.UserID = utils.UUIDToString(link.OwnerID)
+	input.FriendID = utils.UUIDToString(link.PeerID)
+	// Quem tem o link lê como o dono (sem ator autenticado)
+	return s.messages.GetMessagesBetween(reqctx.WithSystem(ctx), input)
+}
+
+// toShareLinkResponse link sem token (o hash não sai do banco)
+func toShareLinkResponse(link repository.ConversationShareLink) types.ShareLinkResponse {
+	return types.ShareLinkResponse{
+		ID:        utils.UUIDToString(link.ID),
+		PeerID:    utils.UUIDToString(link.PeerID),
+		ExpiresAt: link.ExpiresAt.Time.Format(time.RFC3339),
+		CreatedAt: link.CreatedAt.Time.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,489 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/metrics"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+	"chat-kafka-go/pkg/validate"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// SupportUserID conversa do cliente com o suporte (criado na migração 026);
+// agentes respondem em nome dele
+const SupportUserID = "00000000-0000-0000-0000-000000000002"
+
+// Estados de um chamado
+const (
+	SupportOpen    = "open"
+	SupportPending = "pending" // Aguardando o cliente
+	SupportClosed  = "closed"
+)
+
+var (
+	// ErrNotSupportAgent usuário sem papel de agente
+	ErrNotSupportAgent = errors.New("apenas agentes de suporte")
+	// ErrSupportTicketNotFound chamado inexistente
+	ErrSupportTicketNotFound = errors.New("chamado não encontrado")
+	// ErrSupportTicketActive cliente já tem chamado aberto
+	ErrSupportTicketActive = errors.New("já existe um chamado aberto; continue a conversa com o suporte")
+	// ErrSupportTicketTaken chamado já assumido por outro agente (ou fechado)
+	ErrSupportTicketTaken = errors.New("chamado já assumido ou fechado")
+	// ErrSupportTicketNotAssigned ação exclusiva do agente responsável
+	ErrSupportTicketNotAssigned = errors.New("chamado não está com este agente")
+)
+
+// SupportService caixa de suporte: chamados sobre a conversa cliente <->
+// support, fila dos agentes, notas internas e prazos (SLA)
+type SupportService struct {
+	queries  *repository.Queries
+	messages *MessageService
+	cfg      *config.Config
+	clock    clock.Clock // Prazos de SLA
+}
+
+// NewSupportService cria nova instância do service
+func NewSupportService(queries *repository.Queries, messages *MessageService, cfg *config.Config) *SupportService {
+	return &SupportService{
+		queries:  queries,
+		messages: messages,
+		cfg:      cfg,
+		clock:    clock.System,
+	}
+}
+
+// SetClock troca o relógio (testes)
+func (s *SupportService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Open abre chamado do cliente com a primeira mensagem ao suporte
+func (s *SupportService) Open(ctx context.Context, input types.OpenSupportTicketInput) (*types.SupportTicketResponse, error) {
+	customerUUID, err := utils.StringToUUID(input.CustomerID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+	input.Subject = strings.TrimSpace(validate.Text(input.Subject))
+	if !validate.Length(input.Subject, 1, 200) {
+		return nil, fmt.Errorf("assunto é obrigatório (máximo 200 caracteres)")
+	}
+	if input.CustomerID == SupportUserID {
+		return nil, fmt.Errorf("usuário inválido")
+	}
+
+	if _, err := s.queries.GetActiveSupportTicket(ctx, customerUUID); err == nil {
+		return nil, ErrSupportTicketActive
+	} else if err != pgx.ErrNoRows {
+		return nil, fmt.Errorf("erro ao buscar chamado: %w", err)
+	}
+
+	// Mensagem primeiro: envio recusado (cota, conteúdo) não abre chamado
+	if _, err := s.messages.SendMessage(ctx, types.SendMessageInput{
+		SenderID:   input.CustomerID,
+		ReceiverID: SupportUserID,
+		Content:    input.Content,
+	}); err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	ticket, err := s.queries.CreateSupportTicket(ctx, repository.CreateSupportTicketParams{
+		CustomerID:         customerUUID,
+		Subject:            input.Subject,
+		FirstResponseDueAt: pgtype.Timestamp{Time: now.Add(s.cfg.Support.FirstResponseSLA), Valid: true},
+		ResolutionDueAt:    pgtype.Timestamp{Time: now.Add(s.cfg.Support.ResolutionSLA), Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir chamado: %w", err)
+	}
+	metrics.SupportTicketsTotal.WithLabelValues("opened").Inc()
+
+	resp := s.toResponse(ticket)
+	resp.AgentID = ""
+	return &resp, nil
+}
+
+// Mine chamados do cliente (sem agente nem notas)
+func (s *SupportService) Mine(ctx context.Context, customerID string) ([]types.SupportTicketResponse, error) {
+	customerUUID, err := utils.StringToUUID(customerID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	tickets, err := s.queries.ListCustomerSupportTickets(ctx, repository.ListCustomerSupportTicketsParams{
+		CustomerID: customerUUID,
+		Limit:      50,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar chamados: %w", err)
+	}
+
+	resp := make([]types.SupportTicketResponse, len(tickets))
+	for i, ticket := range tickets {
+		resp[i] = s.toResponse(ticket)
+		resp[i].AgentID = ""
+	}
+	return resp, nil
+}
+
+// Queue fila dos agentes, dos mais antigos para os mais novos
+func (s *SupportService) Queue(ctx context.Context, agentID string, filter types.SupportTicketFilter) ([]types.SupportTicketResponse, error) {
+	agentUUID, err := s.requireAgent(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PerPage < 1 || filter.PerPage > 100 {
+		filter.PerPage = 50
+	}
+
+	params := repository.ListSupportTicketsParams{
+		Limit:  int32(filter.PerPage),
+		Offset: int32((filter.Page - 1) * filter.PerPage),
+	}
+	switch filter.Status {
+	case "":
+	case SupportOpen, SupportPending, SupportClosed:
+		params.Status = &filter.Status
+	default:
+		return nil, fmt.Errorf("status deve ser %s, %s ou %s", SupportOpen, SupportPending, SupportClosed)
+	}
+	switch filter.Assigned {
+	case "":
+	case "me":
+		params.AgentID = agentUUID
+	case "none":
+		params.Unassigned = true
+	default:
+		return nil, fmt.Errorf("assigned deve ser me ou none")
+	}
+
+	tickets, err := s.queries.ListSupportTickets(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar chamados: %w", err)
+	}
+
+	resp := make([]types.SupportTicketResponse, len(tickets))
+	for i, ticket := range tickets {
+		resp[i] = s.toResponse(ticket)
+	}
+	return resp, nil
+}
+
+// Get chamado com as notas internas (visão do agente)
+func (s *SupportService) Get(ctx context.Context, agentID, ticketID string) (*types.SupportTicketResponse, error) {
+	if _, err := s.requireAgent(ctx, agentID); err != nil {
+		return nil, err
+	}
+	ticket, err := s.ticket(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := s.queries.ListSupportTicketNotes(ctx, ticket.ID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar notas: %w", err)
+	}
+
+	resp := s.toResponse(ticket)
+	resp.Notes = make([]types.SupportNoteResponse, len(notes))
+	for i, note := range notes {
+		resp.Notes[i] = toSupportNoteResponse(note)
+	}
+	return &resp, nil
+}
+
+// Claim agente assume chamado da fila
+func (s *SupportService) Claim(ctx context.Context, agentID, ticketID string) (*types.SupportTicketResponse, error) {
+	agentUUID, err := s.requireAgent(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	ticket, err := s.ticket(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	claimed, err := s.queries.ClaimSupportTicket(ctx, repository.ClaimSupportTicketParams{
+		ID:      ticket.ID,
+		AgentID: agentUUID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrSupportTicketTaken
+		}
+		return nil, fmt.Errorf("erro ao assumir chamado: %w", err)
+	}
+	metrics.SupportTicketsTotal.WithLabelValues("claimed").Inc()
+
+	resp := s.toResponse(claimed)
+	return &resp, nil
+}
+
+// SetStatus troca o estado (só o responsável); closed fecha o chamado e
+// libera o cliente para abrir outro
+func (s *SupportService) SetStatus(ctx context.Context, agentID, ticketID, status string) (*types.SupportTicketResponse, error) {
+	switch status {
+	case SupportOpen, SupportPending, SupportClosed:
+	default:
+		return nil, fmt.Errorf("status deve ser %s, %s ou %s", SupportOpen, SupportPending, SupportClosed)
+	}
+	ticket, err := s.assigned(ctx, agentID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.Status == SupportClosed {
+		return nil, fmt.Errorf("chamado fechado")
+	}
+
+	updated, err := s.queries.UpdateSupportTicketStatus(ctx, repository.UpdateSupportTicketStatusParams{
+		ID:     ticket.ID,
+		Status: status,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao atualizar chamado: %w", err)
+	}
+	if status == SupportClosed {
+		metrics.SupportTicketsTotal.WithLabelValues("closed").Inc()
+	}
+
+	resp := s.toResponse(updated)
+	return &resp, nil
+}
+
+// AddNote nota interna (qualquer agente); nunca vira mensagem ao cliente
+func (s *SupportService) AddNote(ctx context.Context, agentID, ticketID, content string) (*types.SupportNoteResponse, error) {
+	agentUUID, err := s.requireAgent(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	content = strings.TrimSpace(validate.Text(content))
+	if content == "" || !validate.Content(content) {
+		return nil, fmt.Errorf("nota é obrigatória (máximo %d caracteres)", validate.MaxContentLength)
+	}
+	ticket, err := s.ticket(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	note, err := s.queries.CreateSupportTicketNote(ctx, repository.CreateSupportTicketNoteParams{
+		TicketID: ticket.ID,
+		AuthorID: agentUUID,
+		Content:  content,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao salvar nota: %w", err)
+	}
+
+	resp := toSupportNoteResponse(note)
+	return &resp, nil
+}
+
+// Messages histórico da conversa do cliente com o suporte
+func (s *SupportService) Messages(ctx context.Context, agentID, ticketID string, input types.ListMessagesInput) (*types.PaginatedResponse, error) {
+	if _, err := s.requireAgent(ctx, agentID); err != nil {
+		return nil, err
+	}
+	ticket, err := s.ticket(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	input.UserID = SupportUserID
+	input.FriendID = utils.UUIDToString(ticket.CustomerID)
+	// O agente lê como support, não como ele mesmo
+	return s.messages.GetMessagesBetween(reqctx.WithSystem(ctx), input)
+}
+
+// Reply responde ao cliente como support (só o responsável); a primeira
+// resposta encerra o prazo de primeira resposta
+func (s *SupportService) Reply(ctx context.Context, agentID, ticketID, content string) (*types.MessageResponse, error) {
+	ticket, err := s.assigned(ctx, agentID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.Status == SupportClosed {
+		return nil, fmt.Errorf("chamado fechado")
+	}
+
+	message, err := s.messages.SendMessage(ctx, types.SendMessageInput{
+		SenderID:   SupportUserID,
+		ReceiverID: utils.UUIDToString(ticket.CustomerID),
+		Content:    content,
+		System:     true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.queries.MarkSupportTicketResponded(ctx, ticket.ID); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar chamado: %w", err)
+	}
+	metrics.SupportTicketsTotal.WithLabelValues("replied").Inc()
+	return message, nil
+}
+
+// AddAgent concede o papel de agente (admin)
+func (s *SupportService) AddAgent(ctx context.Context, userID string) error {
+	userUUID, err := utils.StringToUUID(userID)
+	if err != nil {
+		return fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+	if _, err := s.queries.GetUserByID(ctx, userUUID); err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("usuário não encontrado")
+		}
+		return fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+
+	if err := s.queries.AddSupportAgent(ctx, userUUID); err != nil {
+		return fmt.Errorf("erro ao adicionar agente: %w", err)
+	}
+	return nil
+}
+
+// RemoveAgent revoga o papel; chamados assumidos continuam com ele até
+// outro agente ser designado
+func (s *SupportService) RemoveAgent(ctx context.Context, userID string) error {
+	userUUID, err := utils.StringToUUID(userID)
+	if err != nil {
+		return fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	rows, err := s.queries.RemoveSupportAgent(ctx, userUUID)
+	if err != nil {
+		return fmt.Errorf("erro ao remover agente: %w", err)
+	}
+	if rows == 0 {
+		return ErrNotSupportAgent
+	}
+	return nil
+}
+
+// ListAgents agentes cadastrados
+func (s *SupportService) ListAgents(ctx context.Context) ([]types.SupportAgentResponse, error) {
+	agents, err := s.queries.ListSupportAgents(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar agentes: %w", err)
+	}
+
+	resp := make([]types.SupportAgentResponse, len(agents))
+	for i, agent := range agents {
+		resp[i] = types.SupportAgentResponse{
+			UserID:    utils.UUIDToString(agent.ID),
+			Username:  agent.Username,
+			CreatedAt: agent.CreatedAt.Time.Format(time.RFC3339),
+		}
+	}
+	return resp, nil
+}
+
+// requireAgent valida o papel de agente e devolve o UUID
+func (s *SupportService) requireAgent(ctx context.Context, userID string) (pgtype.UUID, error) {
+	userUUID, err := utils.StringToUUID(userID)
+	if err != nil {
+		return pgtype.UUID{}, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	agent, err := s.queries.IsSupportAgent(ctx, userUUID)
+	if err != nil {
+		return pgtype.UUID{}, fmt.Errorf("erro ao verificar agente: %w", err)
+	}
+	if !agent {
+		return pgtype.UUID{}, ErrNotSupportAgent
+	}
+	return userUUID, nil
+}
+
+// ticket busca chamado pelo ID
+func (s *SupportService) ticket(ctx context.Context, ticketID string) (repository.SupportTicket, error) {
+	id, err := utils.StringToUUID(ticketID)
+	if err != nil {
+		return repository.SupportTicket{}, fmt.Errorf("ID do chamado inválido: %w", err)
+	}
+
+	ticket, err := s.queries.GetSupportTicket(ctx, id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return repository.SupportTicket{}, ErrSupportTicketNotFound
+		}
+		return repository.SupportTicket{}, fmt.Errorf("erro ao buscar chamado: %w", err)
+	}
+	return ticket, nil
+}
+
+// assigned chamado que está com o agente
+func (s *SupportService) assigned(ctx context.Context, agentID, ticketID string) (repository.SupportTicket, error) {
+	agentUUID, err := s.requireAgent(ctx, agentID)
+	if err != nil {
+		return repository.SupportTicket{}, err
+	}
+	ticket, err := s.ticket(ctx, ticketID)
+	if err != nil {
+		return repository.SupportTicket{}, err
+	}
+	if ticket.AgentID != agentUUID {
+		return repository.SupportTicket{}, ErrSupportTicketNotAssigned
+	}
+	return ticket, nil
+}
+
+// toResponse chamado com o SLA avaliado agora: prazo estourado quando o
+// marco ocorreu depois do prazo ou ainda não ocorreu e o prazo passou
+func (s *SupportService) toResponse(ticket repository.SupportTicket) types.SupportTicketResponse {
+	now := s.clock.Now()
+	breached := func(due, done pgtype.Timestamp) bool {
+		if done.Valid {
+			return done.Time.After(due.Time)
+		}
+		return now.After(due.Time)
+	}
+
+	resp := types.SupportTicketResponse{
+		ID:         utils.UUIDToString(ticket.ID),
+		CustomerID: utils.UUIDToString(ticket.CustomerID),
+		Subject:    ticket.Subject,
+		Status:     ticket.Status,
+		SLA: types.SupportSLA{
+			FirstResponseDueAt:    ticket.FirstResponseDueAt.Time.Format(time.RFC3339),
+			ResolutionDueAt:       ticket.ResolutionDueAt.Time.Format(time.RFC3339),
+			FirstResponseBreached: breached(ticket.FirstResponseDueAt, ticket.FirstResponseAt),
+			ResolutionBreached:    breached(ticket.ResolutionDueAt, ticket.ClosedAt),
+		},
+		CreatedAt: ticket.CreatedAt.Time.Format(time.RFC3339),
+		UpdatedAt: ticket.UpdatedAt.Time.Format(time.RFC3339),
+	}
+	if ticket.AgentID.Valid {
+		resp.AgentID = utils.UUIDToString(ticket.AgentID)
+	}
+	if ticket.FirstResponseAt.Valid {
+		resp.FirstResponseAt = ticket.FirstResponseAt.Time.Format(time.RFC3339)
+	}
+	if ticket.ClosedAt.Valid {
+		resp.ClosedAt = ticket.ClosedAt.Time.Format(time.RFC3339)
+	}
+	return resp
+}
+
+// toSupportNoteResponse nota interna
+func toSupportNoteResponse(note repository.SupportTicketNote) types.SupportNoteResponse {
+	return types.SupportNoteResponse{
+		ID:        utils.UUIDToString(note.ID),
+		AuthorID:  utils.UUIDToString(note.AuthorID),
+		Content:   note.Content,
+		CreatedAt: note.CreatedAt.Time.Format(time.RFC3339),
+	}
+}
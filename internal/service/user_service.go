@@ -2,28 +2,85 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
 	"time"
 
+	"chat-kafka-go/internal/config"
 	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/status"
 	"chat-kafka-go/pkg/types"
 	"chat-kafka-go/pkg/utils"
+	"chat-kafka-go/pkg/validate"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// MaxUserLookupIDs IDs aceitos por busca em lote
+const MaxUserLookupIDs = 100
+
+// Conflitos de cadastro e troca de username (409)
+var (
+	// ErrEmailTaken email já usado por outra conta
+	ErrEmailTaken = errors.New("email já cadastrado")
+	// ErrUsernameTaken username já usado por outra conta
+	ErrUsernameTaken = errors.New("username já cadastrado")
+	// ErrUsernameReserved username antigo de outra conta, ainda reservado após a troca
+	ErrUsernameReserved = errors.New("username reservado")
+)
+
+// uniqueViolation traduz a violação dos índices únicos de contas ativas
+// (cadastro ou troca simultâneos, restauração de conta com email/username
+// reaproveitado) para ErrEmailTaken/ErrUsernameTaken; nil se for outro erro
+func uniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+		return nil
+	}
+	switch pgErr.ConstraintName {
+	case "idx_users_email_active":
+		return ErrEmailTaken
+	case "idx_users_username_lower_active":
+		return ErrUsernameTaken
+	}
+	return nil
+}
+
 // UserService gerencia operações de usuários
 type UserService struct {
-	queries *repository.Queries
+	queries     *repository.Queries
+	readQueries *repository.Queries // Réplica para listagens (pode ser o primário)
+	invitations *InvitationService  // Estatísticas de indicação no perfil
+	cfg         *config.Config
+	clock       clock.Clock // Expiração de reservas e prazo de exclusão
+	usernames   *validate.UsernamePolicy
 }
 
 // NewUserService cria nova instância do service
-func NewUserService(queries *repository.Queries) *UserService {
+// readQueries é usado nas listagens; se nil, usa o primário
+func NewUserService(queries, readQueries *repository.Queries, invitations *InvitationService, cfg *config.Config) *UserService {
+	if readQueries == nil {
+		readQueries = queries
+	}
 	return &UserService{
-		queries: queries,
+		queries:     queries,
+		readQueries: readQueries,
+		invitations: invitations,
+		cfg:         cfg,
+		clock:       clock.System,
+		usernames:   newUsernamePolicy(&cfg.User),
 	}
 }
 
+// SetClock troca o relógio (testes)
+func (s *UserService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
 // GetUserByID busca usuário por ID
 func (s *UserService) GetUserByID(ctx context.Context, userID string) (*types.UserResponse, error) {
 	// Converter string para UUID
@@ -50,9 +107,91 @@ func (s *UserService) GetUserByID(ctx context.Context, userID string) (*types.Us
 	}, nil
 }
 
+// GetUsersByIDs busca usuários em lote com uma única consulta; IDs repetidos
+// contam uma vez e os não encontrados voltam em Missing
+func (s *UserService) GetUsersByIDs(ctx context.Context, ids []string) (*types.UserLookupResponse, error) {
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("informe ao menos um ID")
+	}
+	if len(ids) > MaxUserLookupIDs {
+		return nil, fmt.Errorf("máximo de %d IDs por busca", MaxUserLookupIDs)
+	}
+
+	ordered := make([]string, 0, len(ids))
+	uuids := make([]pgtype.UUID, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		uuid, err := utils.StringToUUID(id)
+		if err != nil {
+			return nil, fmt.Errorf("ID de usuário inválido %q: %w", id, err)
+		}
+		canonical := utils.UUIDToString(uuid)
+		if seen[canonical] {
+			continue
+		}
+		seen[canonical] = true
+		ordered = append(ordered, canonical)
+		uuids = append(uuids, uuid)
+	}
+
+	users, err := s.readQueries.GetUsersByIDs(ctx, uuids)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar usuários: %w", err)
+	}
+
+	byID := make(map[string]repository.User, len(users))
+	for _, user := range users {
+		byID[utils.UUIDToString(user.ID)] = user
+	}
+
+	resp := &types.UserLookupResponse{Users: []types.UserResponse{}, Missing: []string{}}
+	for _, id := range ordered {
+		user, ok := byID[id]
+		if !ok {
+			resp.Missing = append(resp.Missing, id)
+			continue
+		}
+		resp.Users = append(resp.Users, types.UserResponse{
+			ID:        id,
+			Username:  user.Username,
+			Email:     user.Email,
+			CreatedAt: user.CreatedAt.Time.Format(time.RFC3339),
+		})
+	}
+	return resp, nil
+}
+
+// GetProfile retorna perfil do usuário autenticado com estatísticas de indicação
+func (s *UserService) GetProfile(ctx context.Context, userID string) (*types.ProfileResponse, error) {
+	if err := authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	user, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	uuid, _ := utils.StringToUUID(user.ID)
+	referrals, err := s.invitations.GetReferralStats(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	verified, err := s.queries.IsEmailVerified(ctx, uuid)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao verificar confirmação de email: %w", err)
+	}
+
+	return &types.ProfileResponse{
+		UserResponse:  *user,
+		EmailVerified: verified,
+		Referrals:     referrals,
+	}, nil
+}
+
 // GetUserByUsername busca usuário por username
+// Usernames antigos ainda reservados resolvem para o dono atual
 func (s *UserService) GetUserByUsername(ctx context.Context, username string) (*types.UserResponse, error) {
-	user, err := s.queries.GetUserByUsername(ctx, username)
+	user, err := resolveUsername(ctx, s.queries, username)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, fmt.Errorf("usuário não encontrado")
@@ -81,8 +220,8 @@ func (s *UserService) ListUsers(ctx context.Context, input types.ListUsersInput)
 	// Calcular offset
 	offset := (input.Page - 1) * input.PerPage
 
-	// Buscar usuários
-	users, err := s.queries.ListUsers(ctx, repository.ListUsersParams{
+	// Buscar usuários (réplica de leitura)
+	users, err := s.readQueries.ListUsers(ctx, repository.ListUsersParams{
 		Limit:  int32(input.PerPage),
 		Offset: int32(offset),
 	})
@@ -115,51 +254,62 @@ func (s *UserService) ListUsers(ctx context.Context, input types.ListUsersInput)
 	}, nil
 }
 
-// AddFriend envia solicitação de amizade
-func (s *UserService) AddFriend(ctx context.Context, input types.AddFriendInput) error {
-	// Validar IDs
+// AddFriend envia solicitação de amizade; se o outro usuário já tinha pedido,
+// os dois querem a amizade e ela é aceita na hora (retorna o status final)
+func (s *UserService) AddFriend(ctx context.Context, input types.AddFriendInput) (status.Friendship, error) {
+	// Validar IDs; quem pede é o ator autenticado
+	if err := authorize(ctx, input.UserID); err != nil {
+		return "", err
+	}
 	if input.UserID == input.FriendID {
-		return fmt.Errorf("não é possível adicionar a si mesmo como amigo")
+		return "", fmt.Errorf("não é possível adicionar a si mesmo como amigo")
 	}
 
 	// Converter UUIDs
 	userUUID, err := utils.StringToUUID(input.UserID)
 	if err != nil {
-		return fmt.Errorf("ID de usuário inválido: %w", err)
+		return "", fmt.Errorf("ID de usuário inválido: %w", err)
 	}
 
 	friendUUID, err := utils.StringToUUID(input.FriendID)
 	if err != nil {
-		return fmt.Errorf("ID de amigo inválido: %w", err)
+		return "", fmt.Errorf("ID de amigo inválido: %w", err)
 	}
 
-	// Verificar se amizade já existe
-	_, err = s.queries.GetFriendship(ctx, repository.GetFriendshipParams{
+	// Upsert atômico no par (sem verificar antes: dois pedidos simultâneos
+	// disputariam a mesma janela)
+	friendship, err := s.queries.RequestFriendship(ctx, repository.RequestFriendshipParams{
 		UserID:   userUUID,
 		FriendID: friendUUID,
 	})
 	if err == nil {
-		return fmt.Errorf("solicitação de amizade já existe")
+		return friendship.Status, nil
 	}
 	if err != pgx.ErrNoRows {
-		return fmt.Errorf("erro ao verificar amizade: %w", err)
+		return "", fmt.Errorf("erro ao criar solicitação de amizade: %w", err)
 	}
 
-	// Criar solicitação de amizade (status: pending)
-	_, err = s.queries.CreateFriendship(ctx, repository.CreateFriendshipParams{
+	// Conflito sem atualização: pedido repetido ou amizade já aceita
+	existing, err := s.queries.GetFriendship(ctx, repository.GetFriendshipParams{
 		UserID:   userUUID,
 		FriendID: friendUUID,
-		Status:   "pending",
 	})
 	if err != nil {
-		return fmt.Errorf("erro ao criar solicitação de amizade: %w", err)
+		return "", fmt.Errorf("erro ao verificar amizade: %w", err)
 	}
-
-	return nil
+	if existing.Status == status.FriendshipAccepted {
+		return "", fmt.Errorf("amizade já aceita")
+	}
+	return "", fmt.Errorf("solicitação de amizade já existe")
 }
 
 // AcceptFriend aceita solicitação de amizade
 func (s *UserService) AcceptFriend(ctx context.Context, input types.AcceptFriendInput) error {
+	// Quem aceita é o ator autenticado
+	if err := authorize(ctx, input.UserID); err != nil {
+		return err
+	}
+
 	// Converter UUIDs
 	userUUID, err := utils.StringToUUID(input.UserID)
 	if err != nil {
@@ -184,24 +334,38 @@ func (s *UserService) AcceptFriend(ctx context.Context, input types.AcceptFriend
 	}
 
 	// Verificar se já está aceita
-	if friendship.Status == "accepted" {
+	if friendship.Status == status.FriendshipAccepted {
 		return fmt.Errorf("amizade já aceita")
 	}
+	// O par é único nos dois sentidos: só quem recebeu o pedido aceita
+	if friendship.UserID != friendUUID {
+		return fmt.Errorf("solicitação de amizade não encontrada")
+	}
+	if err := friendship.Status.Transition(status.FriendshipAccepted); err != nil {
+		return err
+	}
 
-	// Atualizar status para 'accepted'
-	err = s.queries.UpdateFriendshipStatus(ctx, repository.UpdateFriendshipStatusParams{
-		ID:     friendship.ID,
-		Status: "accepted",
+	rows, err := s.queries.UpdateFriendshipStatus(ctx, repository.UpdateFriendshipStatusParams{
+		ID:      friendship.ID,
+		Status:  status.FriendshipAccepted,
+		Version: friendship.Version,
 	})
 	if err != nil {
 		return fmt.Errorf("erro ao aceitar amizade: %w", err)
 	}
+	if rows == 0 {
+		return &status.ConflictError{Entity: "amizade", ID: utils.UUIDToString(friendship.ID), Version: friendship.Version}
+	}
 
 	return nil
 }
 
 // ListFriends lista amigos aceitos de um usuário
 func (s *UserService) ListFriends(ctx context.Context, userID string) ([]types.UserResponse, error) {
+	if err := authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+
 	// Converter UUID
 	uuid, err := utils.StringToUUID(userID)
 	if err != nil {
@@ -227,3 +391,205 @@ func (s *UserService) ListFriends(ctx context.Context, userID string) ([]types.U
 
 	return friendResponses, nil
 }
+
+// DeleteUser remove usuário (soft delete) e revoga sessões e chaves de API; recusa
+// com ErrLegalHold enquanto houver retenção legal envolvendo o usuário
+func (s *UserService) DeleteUser(ctx context.Context, userID string) error {
+	if err := authorize(ctx, userID); err != nil {
+		return err
+	}
+
+	uuid, err := utils.StringToUUID(userID)
+	if err != nil {
+		return fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	held, err := s.queries.UserHasActiveLegalHold(ctx, uuid)
+	if err != nil {
+		return fmt.Errorf("erro ao consultar retenção legal: %w", err)
+	}
+	if held {
+		return ErrLegalHold
+	}
+
+	// Conta, sessões e chaves de API saem juntas: falha no meio não deixa
+	// conta removida com acesso ativo
+	return s.queries.InTx(ctx, func(q *repository.Queries) error {
+		rows, err := q.SoftDeleteUser(ctx, uuid)
+		if err != nil {
+			return fmt.Errorf("erro ao remover usuário: %w", err)
+		}
+		if rows == 0 {
+			return fmt.Errorf("usuário não encontrado")
+		}
+
+		if err := q.DeleteUserRefreshTokens(ctx, uuid); err != nil {
+			return fmt.Errorf("erro ao revogar sessões: %w", err)
+		}
+		if _, err := q.RevokeUserAPIKeys(ctx, uuid); err != nil {
+			return fmt.Errorf("erro ao revogar chaves de API: %w", err)
+		}
+		return nil
+	})
+}
+
+// RestoreUser restaura usuário removido dentro do período de carência
+func (s *UserService) RestoreUser(ctx context.Context, userID string) (*types.UserResponse, error) {
+	uuid, err := utils.StringToUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	rows, err := s.queries.RestoreUser(ctx, repository.RestoreUserParams{
+		ID: uuid,
+		Cutoff: pgtype.Timestamp{
+			Time:  s.clock.Now().Add(-s.cfg.User.DeletionGracePeriod),
+			Valid: true,
+		},
+	})
+	if conflict := uniqueViolation(err); conflict != nil {
+		return nil, fmt.Errorf("email ou username já usado por outra conta: %w", conflict)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao restaurar usuário: %w", err)
+	}
+	if rows == 0 {
+		return nil, fmt.Errorf("usuário não encontrado ou fora do período de restauração")
+	}
+
+	return s.GetUserByID(ctx, userID)
+}
+
+// ShadowBan ativa shadow ban: as mensagens do usuário continuam sendo aceitas
+// e aparecem entregues para ele, mas não são distribuídas aos destinatários
+func (s *UserService) ShadowBan(ctx context.Context, userID string) error {
+	uuid, err := utils.StringToUUID(userID)
+	if err != nil {
+		return fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	rows, err := s.queries.ShadowBanUser(ctx, uuid)
+	if err != nil {
+		return fmt.Errorf("erro ao aplicar shadow ban: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("usuário não encontrado ou já em shadow ban")
+	}
+	return nil
+}
+
+// LiftShadowBan remove o shadow ban; novas mensagens voltam a ser entregues e
+// as anteriores passam a aparecer no histórico dos destinatários
+func (s *UserService) LiftShadowBan(ctx context.Context, userID string) error {
+	uuid, err := utils.StringToUUID(userID)
+	if err != nil {
+		return fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	rows, err := s.queries.LiftShadowBan(ctx, uuid)
+	if err != nil {
+		return fmt.Errorf("erro ao remover shadow ban: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("usuário não encontrado ou sem shadow ban")
+	}
+	return nil
+}
+
+// ChangeUsername troca o username respeitando cooldown e reservas
+// O username antigo fica reservado (e redirecionando) pelo período configurado
+func (s *UserService) ChangeUsername(ctx context.Context, input types.ChangeUsernameInput) (*types.UserResponse, error) {
+	if err := authorize(ctx, input.UserID); err != nil {
+		return nil, err
+	}
+	if err := s.usernames.Check(input.NewUsername); err != nil {
+		return nil, err
+	}
+
+	uuid, err := utils.StringToUUID(input.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de usuário inválido: %w", err)
+	}
+
+	user, err := s.queries.GetUserByID(ctx, uuid)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("usuário não encontrado")
+		}
+		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+
+	if user.Username == input.NewUsername {
+		return nil, fmt.Errorf("novo username é igual ao atual")
+	}
+
+	// Cooldown entre trocas
+	if user.UsernameChangedAt.Valid {
+		next := user.UsernameChangedAt.Time.Add(s.cfg.User.UsernameChangeCooldown)
+		if s.clock.Now().Before(next) {
+			return nil, fmt.Errorf("username só pode ser alterado novamente após %s", next.Format(time.RFC3339))
+		}
+	}
+
+	// Username em uso (sem diferenciar maiúsculas; trocar só a caixa do próprio é permitido)
+	owner, err := s.queries.GetUserByUsername(ctx, input.NewUsername)
+	if err == nil && owner.ID != uuid {
+		return nil, ErrUsernameTaken
+	}
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, fmt.Errorf("erro ao verificar username: %w", err)
+	}
+
+	// Username reservado por outro usuário (o próprio dono pode voltar ao antigo)
+	reservation, err := s.queries.GetActiveUsernameReservation(ctx, input.NewUsername)
+	if err == nil && reservation.UserID != uuid {
+		return nil, ErrUsernameReserved
+	}
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, fmt.Errorf("erro ao verificar reserva: %w", err)
+	}
+
+	err = s.queries.UpdateUsername(ctx, repository.UpdateUsernameParams{
+		ID:       uuid,
+		Username: input.NewUsername,
+	})
+	if conflict := uniqueViolation(err); conflict != nil {
+		return nil, conflict
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao atualizar username: %w", err)
+	}
+
+	err = s.queries.CreateUsernameHistory(ctx, repository.CreateUsernameHistoryParams{
+		UserID:      uuid,
+		OldUsername: user.Username,
+		ReservedUntil: pgtype.Timestamp{
+			Time:  s.clock.Now().Add(s.cfg.User.UsernameReservationPeriod),
+			Valid: true,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao registrar histórico de username: %w", err)
+	}
+
+	return s.GetUserByID(ctx, input.UserID)
+}
+
+// newUsernamePolicy política de username da config (padrão já validado em Config.Validate)
+func newUsernamePolicy(cfg *config.UserConfig) *validate.UsernamePolicy {
+	return validate.NewUsernamePolicy(regexp.MustCompile(cfg.UsernamePattern), cfg.UsernameLeadingDigit, cfg.ReservedUsernames)
+}
+
+// resolveUsername busca pelo username atual e, se não achar, pelo histórico reservado
+func resolveUsername(ctx context.Context, queries *repository.Queries, username string) (repository.User, error) {
+	user, err := queries.GetUserByUsername(ctx, username)
+	if err != pgx.ErrNoRows {
+		return user, err
+	}
+
+	reservation, err := queries.GetActiveUsernameReservation(ctx, username)
+	if err != nil {
+		return repository.User{}, err
+	}
+	return queries.GetUserByID(ctx, reservation.UserID)
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"chat-kafka-go/internal/config/configtest"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/internal/repository/repotest"
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/pkg/status"
+	"chat-kafka-go/pkg/types"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// friendshipDB banco falso com a tabela friendships e o índice único do
+// par (LEAST/GREATEST): uma linha por par, em qualquer direção
+func friendshipDB() (*repotest.DB, *[]repository.Friendship) {
+	var rows []repository.Friendship
+	find := func(a, b pgtype.UUID) int {
+		for i, f := range rows {
+			if (f.UserID == a && f.FriendID == b) || (f.UserID == b && f.FriendID == a) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	db := repotest.New()
+	db.On("RequestFriendship", func(args []interface{}) repotest.Result {
+		user, friend := args[0].(pgtype.UUID), args[1].(pgtype.UUID)
+		i := find(user, friend)
+		switch {
+		case i < 0:
+			id := pgtype.UUID{Valid: true}
+			id.Bytes[15] = byte(len(rows) + 1)
+			rows = append(rows, repository.Friendship{ID: id, UserID: user, FriendID: friend, Status: status.FriendshipPending, Version: 1})
+			return repotest.Rows(repotest.Row(rows[len(rows)-1]))
+		case rows[i].Status == status.FriendshipPending && rows[i].UserID == friend:
+			rows[i].Status = status.FriendshipAccepted
+			rows[i].Version++
+			return repotest.Rows(repotest.Row(rows[i]))
+		default:
+			return repotest.Rows() // ON CONFLICT sem atualização
+		}
+	})
+	db.On("GetFriendship", func(args []interface{}) repotest.Result {
+		if i := find(args[0].(pgtype.UUID), args[1].(pgtype.UUID)); i >= 0 {
+			return repotest.Rows(repotest.Row(rows[i]))
+		}
+		return repotest.Rows()
+	})
+	db.On("UpdateFriendshipStatus", func(args []interface{}) repotest.Result {
+		for i, f := range rows {
+			if f.ID == args[0].(pgtype.UUID) && f.Version == args[2].(int32) {
+				rows[i].Status = args[1].(status.Friendship)
+				rows[i].Version++
+				return repotest.Result{RowsAffected: 1}
+			}
+		}
+		return repotest.Result{}
+	})
+	return db, &rows
+}
+
+func asUser(userID string) context.Context {
+	return reqctx.WithUserID(context.Background(), userID)
+}
+
+func TestAddFriendMutualRequestAccepts(t *testing.T) {
+	db, rows := friendshipDB()
+	users := NewUserService(repository.New(db), nil, nil, configtest.New(t))
+
+	got, err := users.AddFriend(asUser(testUserID), types.AddFriendInput{UserID: testUserID, FriendID: otherUserID})
+	if err != nil || got != status.FriendshipPending {
+		t.Fatalf("AddFriend(a→b) = %q, %v; esperado pending", got, err)
+	}
+	// O pedido no sentido inverso cai no mesmo par e aceita a amizade
+	got, err = users.AddFriend(asUser(otherUserID), types.AddFriendInput{UserID: otherUserID, FriendID: testUserID})
+	if err != nil || got != status.FriendshipAccepted {
+		t.Fatalf("AddFriend(b→a) = %q, %v; esperado accepted", got, err)
+	}
+	if len(*rows) != 1 {
+		t.Fatalf("%d linhas para o par; esperado 1", len(*rows))
+	}
+
+	// Já amigos: nenhum dos dois lados cria outra linha
+	for _, side := range []struct{ user, friend string }{{testUserID, otherUserID}, {otherUserID, testUserID}} {
+		if _, err := users.AddFriend(asUser(side.user), types.AddFriendInput{UserID: side.user, FriendID: side.friend}); err == nil {
+			t.Errorf("AddFriend(%s) aceito com amizade existente", side.user)
+		}
+	}
+	if len(*rows) != 1 {
+		t.Fatalf("%d linhas para o par; esperado 1", len(*rows))
+	}
+}
+
+func TestAddFriendRepeatedRequest(t *testing.T) {
+	db, rows := friendshipDB()
+	users := NewUserService(repository.New(db), nil, nil, configtest.New(t))
+	input := types.AddFriendInput{UserID: testUserID, FriendID: otherUserID}
+
+	if _, err := users.AddFriend(asUser(testUserID), input); err != nil {
+		t.Fatalf("AddFriend: %v", err)
+	}
+	if _, err := users.AddFriend(asUser(testUserID), input); err == nil {
+		t.Fatal("pedido repetido aceito")
+	}
+	if len(*rows) != 1 || (*rows)[0].Status != status.FriendshipPending {
+		t.Fatalf("linhas = %+v; esperado um pedido pendente", *rows)
+	}
+}
+
+func TestAcceptFriendOnlyByReceiver(t *testing.T) {
+	db, rows := friendshipDB()
+	users := NewUserService(repository.New(db), nil, nil, configtest.New(t))
+
+	if _, err := users.AddFriend(asUser(testUserID), types.AddFriendInput{UserID: testUserID, FriendID: otherUserID}); err != nil {
+		t.Fatalf("AddFriend: %v", err)
+	}
+	// Quem pediu não aceita o próprio pedido, mesmo achando o par
+	if err := users.AcceptFriend(asUser(testUserID), types.AcceptFriendInput{UserID: testUserID, FriendID: otherUserID}); err == nil {
+		t.Fatal("pedido aceito por quem pediu")
+	}
+	if err := users.AcceptFriend(asUser(otherUserID), types.AcceptFriendInput{UserID: otherUserID, FriendID: testUserID}); err != nil {
+		t.Fatalf("AcceptFriend: %v", err)
+	}
+	if (*rows)[0].Status != status.FriendshipAccepted {
+		t.Fatalf("status = %q; esperado accepted", (*rows)[0].Status)
+	}
+}
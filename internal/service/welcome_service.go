@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+)
+
+// defaultWelcomeMessages textos de boas-vindas quando WELCOME_MESSAGES_FILE
+// não está definido
+var defaultWelcomeMessages = []string{
+	"Olá, {{.Username}}! Boas-vindas ao chat 👋",
+	"Esta conversa é com o sistema: anúncios e novidades chegam aqui. " +
+		"Para começar, adicione um amigo pelo username e mande a primeira mensagem.",
+}
+
+// welcomeSeparator linha que separa as mensagens no arquivo de modelos
+const welcomeSeparator = "---"
+
+// WelcomeData campos disponíveis nos modelos de boas-vindas
+type WelcomeData struct {
+	Username string
+	BaseURL  string // APP_BASE_URL
+}
+
+// WelcomeService conversa de boas-vindas: no cadastro o usuário system envia
+// as mensagens de onboarding pelo pipeline normal (banco, barramento,
+// WebSocket). Os anúncios do admin usam o mesmo remetente e caem na mesma conversa
+type WelcomeService struct {
+	messages  *MessageService
+	templates []*template.Template
+	baseURL   string
+}
+
+// NewWelcomeService carrega e valida os modelos (text/template) de
+// WELCOME_MESSAGES_FILE: mensagens separadas por uma linha "---"
+func NewWelcomeService(messages *MessageService, cfg *config.Config) (*WelcomeService, error) {
+	sources := defaultWelcomeMessages
+	if cfg.User.WelcomeMessagesFile != "" {
+		raw, err := os.ReadFile(cfg.User.WelcomeMessagesFile)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler mensagens de boas-vindas: %w", err)
+		}
+		sources = splitWelcomeMessages(string(raw))
+	}
+
+	s := &WelcomeService{messages: messages, baseURL: cfg.Mail.BaseURL}
+	for i, source := range sources {
+		tmpl, err := template.New(fmt.Sprintf("welcome-%d", i+1)).Option("missingkey=error").Parse(source)
+		if err != nil {
+			return nil, fmt.Errorf("modelo de boas-vindas %d inválido: %w", i+1, err)
+		}
+		s.templates = append(s.templates, tmpl)
+	}
+	return s, nil
+}
+
+// Greet envia as mensagens de boas-vindas ao novo usuário, em ordem
+func (s *WelcomeService) Greet(ctx context.Context, user repository.User) error {
+	data := WelcomeData{Username: user.Username, BaseURL: s.baseURL}
+	for _, tmpl := range s.templates {
+		var content strings.Builder
+		if err := tmpl.Execute(&content, data); err != nil {
+			return fmt.Errorf("erro ao montar %s: %w", tmpl.Name(), err)
+		}
+		_, err := s.messages.SendMessage(ctx, types.SendMessageInput{
+			SenderID:   SystemUserID,
+			ReceiverID: utils.UUIDToString(user.ID),
+			Content:    strings.TrimSpace(content.String()),
+			System:     true,
+		})
+		if err != nil {
+			return fmt.Errorf("erro ao enviar %s: %w", tmpl.Name(), err)
+		}
+	}
+	return nil
+}
+
+// splitWelcomeMessages separa o arquivo nas linhas "---", ignorando blocos vazios
+func splitWelcomeMessages(raw string) []string {
+	var messages []string
+	var current []string
+	flush := func() {
+		if msg := strings.TrimSpace(strings.Join(current, "\n")); msg != "" {
+			messages = append(messages, msg)
+		}
+		current = current[:0]
+	}
+	for _, line := range strings.Split(raw, "\n") {
+		if strings.TrimSpace(line) == welcomeSeparator {
+			flush()
+			continue
+		}
+		current = append(current, strings.TrimRight(line, "\r"))
+	}
+	flush()
+	return messages
+}
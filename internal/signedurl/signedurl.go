@@ -0,0 +1,69 @@
+package signedurl
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Signer gera e valida URLs com expiração assinadas com HMAC-SHA256
+// A assinatura cobre caminho + expiração, então a URL pode ser servida por CDN
+// (a query string faz parte da chave de cache) sem expor o anexo
+type Signer struct {
+	secret  []byte
+	baseURL string
+	ttl     time.Duration
+}
+
+// New cria signer; baseURL é o host público (CDN ou a própria API)
+func New(secret, baseURL string, ttl time.Duration) *Signer {
+	return &Signer{
+		secret:  []byte(secret),
+		baseURL: baseURL,
+		ttl:     ttl,
+	}
+}
+
+// Sign retorna URL assinada para o caminho e quando ela expira
+// A expiração é arredondada para cima em múltiplos do TTL: assinaturas do mesmo
+// arquivo na mesma janela geram a mesma URL (melhor taxa de acerto no CDN)
+func (s *Signer) Sign(path string, now time.Time) (string, time.Time) {
+	window := int64(s.ttl / time.Second)
+	if window <= 0 {
+		window = 1
+	}
+	expires := (now.Add(s.ttl).Unix()/window + 1) * window
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", s.signature(path, expires))
+	return s.baseURL + path + "?" + query.Encode(), time.Unix(expires, 0)
+}
+
+// Verify valida assinatura e expiração recebidas na query
+func (s *Signer) Verify(path, expires, signature string, now time.Time) error {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return fmt.Errorf("expiração inválida")
+	}
+
+	expected := s.signature(path, exp)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return fmt.Errorf("assinatura inválida")
+	}
+	if now.Unix() > exp {
+		return fmt.Errorf("URL expirada")
+	}
+	return nil
+}
+
+// signature HMAC-SHA256 de "caminho\nexpiração" em hex
+func (s *Signer) signature(path string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "%s\n%d", path, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
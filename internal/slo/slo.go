@@ -0,0 +1,207 @@
+// Package slo objetivos de latência com SLI contado em memória por minuto:
+// o burn rate de cada janela sai direto de GET /admin/slo, sem recalcular
+// no Prometheus (que recebe os mesmos eventos em chat_slo_events_total)
+package slo
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"chat-kafka-go/internal/metrics"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/idgen"
+
+	"github.com/google/uuid"
+)
+
+const (
+	bucketWidth = time.Minute
+	bucketCount = 6 * 60 // Maior janela avaliada: 6h
+)
+
+// windows janelas avaliadas em Report
+var windows = []time.Duration{5 * time.Minute, 30 * time.Minute, time.Hour, 6 * time.Hour}
+
+// alertRule alerta de burn rate em duas janelas (longa confirma, curta
+// garante que ainda está queimando); limiares do SRE Workbook para 30 dias
+type alertRule struct {
+	severity  string
+	long      time.Duration
+	short     time.Duration
+	threshold float64
+}
+
+var alertRules = []alertRule{
+	{severity: "page", long: time.Hour, short: 5 * time.Minute, threshold: 14.4},     // 2% do orçamento em 1h
+	{severity: "ticket", long: 6 * time.Hour, short: 30 * time.Minute, threshold: 6}, // 5% do orçamento em 6h
+}
+
+// WindowReport SLI e burn rate de uma janela
+type WindowReport struct {
+	Window     string  `json:"window"`
+	Total      int64   `json:"total"`
+	Bad        int64   `json:"bad"`
+	ErrorRatio float64 `json:"error_ratio"`
+	BurnRate   float64 `json:"burn_rate"` // 1 = consome o orçamento exatamente no período do SLO
+}
+
+// AlertReport estado de uma regra de alerta
+type AlertReport struct {
+	Severity    string  `json:"severity"`
+	LongWindow  string  `json:"long_window"`
+	ShortWindow string  `json:"short_window"`
+	Threshold   float64 `json:"threshold"`
+	Firing      bool    `json:"firing"`
+}
+
+// Report avaliação atual do objetivo nesta instância
+type Report struct {
+	SLO         string         `json:"slo"`
+	Description string         `json:"description"`
+	Target      float64        `json:"target"`
+	Threshold   string         `json:"threshold"`
+	Since       time.Time      `json:"since"` // Janelas maiores que o uptime estão incompletas
+	Windows     []WindowReport `json:"windows"`
+	Alerts      []AlertReport  `json:"alerts"`
+}
+
+// bucket eventos de um minuto
+type bucket struct {
+	minute int64
+	total  int64
+	bad    int64
+}
+
+// Tracker SLO de latência: Target dos eventos dentro de Threshold
+// Métodos aceitam receptor nil (SLO desligado no componente)
+type Tracker struct {
+	name        string
+	description string
+	target      float64
+	threshold   time.Duration
+
+	mu      sync.Mutex
+	buckets [bucketCount]bucket
+	since   time.Time
+	clock   clock.Clock
+}
+
+// NewTracker cria o objetivo name (rótulo da métrica)
+func NewTracker(name, description string, target float64, threshold time.Duration) *Tracker {
+	return &Tracker{
+		name:        name,
+		description: description,
+		target:      target,
+		threshold:   threshold,
+		since:       clock.System.Now().UTC(),
+		clock:       clock.System,
+	}
+}
+
+// SetClock troca o relógio (testes)
+func (t *Tracker) SetClock(c clock.Clock) {
+	t.clock = c
+	t.since = c.Now().UTC()
+}
+
+// ObserveMessage registra a entrega da mensagem agora, medindo desde o
+// timestamp do ID; IDs sem timestamp em ms (UUIDv4, ULID) são ignorados
+func (t *Tracker) ObserveMessage(messageID string) {
+	if t == nil {
+		return
+	}
+	id, err := uuid.Parse(messageID)
+	if err != nil {
+		return
+	}
+	sentAt, ok := idgen.Time(id)
+	if !ok {
+		return
+	}
+	t.Observe(t.clock.Now().Sub(sentAt))
+}
+
+// Observe registra um evento com a latência medida
+func (t *Tracker) Observe(latency time.Duration) {
+	if t == nil {
+		return
+	}
+	bad := latency > t.threshold
+	if bad {
+		metrics.SLOEventsTotal.WithLabelValues(t.name, "bad").Inc()
+	} else {
+		metrics.SLOEventsTotal.WithLabelValues(t.name, "good").Inc()
+	}
+
+	minute := t.clock.Now().Unix() / int64(bucketWidth/time.Second)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	b := &t.buckets[minute%bucketCount]
+	if b.minute != minute {
+		*b = bucket{minute: minute}
+	}
+	b.total++
+	if bad {
+		b.bad++
+	}
+}
+
+// Report SLI, burn rate por janela e regras de alerta disparadas
+func (t *Tracker) Report() Report {
+	now := t.clock.Now()
+	report := Report{
+		SLO:         t.name,
+		Description: t.description,
+		Target:      t.target,
+		Threshold:   t.threshold.String(),
+		Since:       t.since,
+	}
+
+	burn := make(map[time.Duration]float64, len(windows))
+	for _, window := range windows {
+		total, bad := t.count(now, window)
+		w := WindowReport{Window: formatWindow(window), Total: total, Bad: bad}
+		if total > 0 {
+			w.ErrorRatio = float64(bad) / float64(total)
+			w.BurnRate = w.ErrorRatio / (1 - t.target)
+		}
+		burn[window] = w.BurnRate
+		report.Windows = append(report.Windows, w)
+	}
+
+	for _, rule := range alertRules {
+		report.Alerts = append(report.Alerts, AlertReport{
+			Severity:    rule.severity,
+			LongWindow:  formatWindow(rule.long),
+			ShortWindow: formatWindow(rule.short),
+			Threshold:   rule.threshold,
+			Firing:      burn[rule.long] >= rule.threshold && burn[rule.short] >= rule.threshold,
+		})
+	}
+	return report
+}
+
+// count soma os minutos da janela terminada em now (inclui o minuto atual)
+func (t *Tracker) count(now time.Time, window time.Duration) (total, bad int64) {
+	last := now.Unix() / int64(bucketWidth/time.Second)
+	first := last - int64(window/bucketWidth) + 1
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, b := range t.buckets {
+		if b.minute >= first && b.minute <= last {
+			total += b.total
+			bad += b.bad
+		}
+	}
+	return total, bad
+}
+
+// formatWindow 5m, 30m, 1h, 6h
+func formatWindow(d time.Duration) string {
+	if d%time.Hour == 0 {
+		return strconv.Itoa(int(d/time.Hour)) + "h"
+	}
+	return strconv.Itoa(int(d/time.Minute)) + "m"
+}
@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotFound objeto inexistente
+var ErrNotFound = errors.New("objeto não encontrado")
+
+// Store armazenamento de objetos (anexos) endereçados por chave
+type Store interface {
+	// Put grava o conteúdo e retorna o número de bytes escritos
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	// PutAt grava a partir do offset, descartando o que houver depois dele
+	// (uploads retomáveis); retorna o número de bytes escritos
+	PutAt(ctx context.Context, key string, offset int64, r io.Reader) (int64, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Move troca a chave do objeto (ex: mover para quarentena)
+	Move(ctx context.Context, from, to string) error
+	Delete(ctx context.Context, key string) error
+}
+
+// LocalStore guarda objetos em um diretório local
+type LocalStore struct {
+	root string
+}
+
+// NewLocal cria store no diretório informado (criado se não existir)
+func NewLocal(root string) (*LocalStore, error) {
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("erro ao criar diretório de armazenamento: %w", err)
+	}
+	return &LocalStore{root: root}, nil
+}
+
+// Put implementa Store; grava em arquivo temporário e renomeia ao final
+func (s *LocalStore) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return 0, fmt.Errorf("erro ao criar diretório: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("erro ao criar arquivo: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("erro ao gravar objeto: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, fmt.Errorf("erro ao gravar objeto: %w", err)
+	}
+	return n, nil
+}
+
+// PutAt implementa Store
+func (s *LocalStore) PutAt(ctx context.Context, key string, offset int64, r io.Reader) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return 0, fmt.Errorf("erro ao criar diretório: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0o640)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao abrir objeto: %w", err)
+	}
+	defer f.Close()
+
+	// Bloco anterior interrompido pode ter deixado bytes além do offset confirmado
+	if err := f.Truncate(offset); err != nil {
+		return 0, fmt.Errorf("erro ao truncar objeto: %w", err)
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("erro ao posicionar objeto: %w", err)
+	}
+
+	n, err := io.Copy(f, r)
+	if err != nil {
+		return n, fmt.Errorf("erro ao gravar objeto: %w", err)
+	}
+	return n, nil
+}
+
+// Open implementa Store
+func (s *LocalStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir objeto: %w", err)
+	}
+	return f, nil
+}
+
+// Move implementa Store
+func (s *LocalStore) Move(ctx context.Context, from, to string) error {
+	src, err := s.path(from)
+	if err != nil {
+		return err
+	}
+	dst, err := s.path(to)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o750); err != nil {
+		return fmt.Errorf("erro ao criar diretório: %w", err)
+	}
+
+	err = os.Rename(src, dst)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao mover objeto: %w", err)
+	}
+	return nil
+}
+
+// Delete implementa Store (objeto inexistente não é erro)
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("erro ao remover objeto: %w", err)
+	}
+	return nil
+}
+
+// path resolve a chave dentro da raiz, rejeitando chaves que escapam dela
+func (s *LocalStore) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if key == "" || strings.Contains(key, "..") || clean == "/" {
+		return "", fmt.Errorf("chave de objeto inválida: %q", key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(clean)), nil
+}
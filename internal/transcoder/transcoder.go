@@ -0,0 +1,108 @@
+package transcoder
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Arquivos gerados no diretório de saída
+const (
+	VideoFile  = "web.mp4"
+	PosterFile = "poster.jpg"
+)
+
+// maxWidth largura máxima das versões para web (mantém proporção)
+const maxWidth = 1280
+
+// Result caminhos dos arquivos gerados
+type Result struct {
+	VideoPath  string // H.264/AAC com faststart (toca em qualquer navegador)
+	PosterPath string // Quadro representativo em JPEG
+}
+
+// Transcoder gera versões para web de um vídeo
+type Transcoder interface {
+	Transcode(ctx context.Context, input, outputDir string) (Result, error)
+	Enabled() bool
+}
+
+// New cria transcoder ffmpeg se o binário estiver configurado, senão um vazio
+func New(ffmpegPath string) Transcoder {
+	if ffmpegPath == "" {
+		return NoopTranscoder{}
+	}
+	return &FFmpeg{path: ffmpegPath}
+}
+
+// NoopTranscoder não transcodifica (vídeos servidos apenas no original)
+type NoopTranscoder struct{}
+
+// Transcode implementa Transcoder
+func (NoopTranscoder) Transcode(ctx context.Context, input, outputDir string) (Result, error) {
+	return Result{}, fmt.Errorf("transcodificação desabilitada")
+}
+
+// Enabled implementa Transcoder
+func (NoopTranscoder) Enabled() bool { return false }
+
+// FFmpeg transcodifica chamando o binário do ffmpeg
+type FFmpeg struct {
+	path string
+}
+
+// Enabled implementa Transcoder
+func (f *FFmpeg) Enabled() bool { return true }
+
+// Transcode implementa Transcoder
+func (f *FFmpeg) Transcode(ctx context.Context, input, outputDir string) (Result, error) {
+	scale := fmt.Sprintf("scale='min(%d,iw)':-2", maxWidth)
+	result := Result{
+		VideoPath:  filepath.Join(outputDir, VideoFile),
+		PosterPath: filepath.Join(outputDir, PosterFile),
+	}
+
+	err := f.run(ctx,
+		"-y", "-i", input,
+		"-c:v", "libx264", "-preset", "veryfast", "-profile:v", "main", "-crf", "23", "-pix_fmt", "yuv420p",
+		"-vf", scale,
+		"-c:a", "aac", "-b:a", "128k",
+		"-movflags", "+faststart",
+		result.VideoPath,
+	)
+	if err != nil {
+		return Result{}, fmt.Errorf("erro ao gerar vídeo para web: %w", err)
+	}
+
+	// Filtro thumbnail escolhe um quadro representativo (evita tela preta inicial)
+	err = f.run(ctx,
+		"-y", "-i", input,
+		"-vf", "thumbnail,"+scale,
+		"-frames:v", "1",
+		result.PosterPath,
+	)
+	if err != nil {
+		return Result{}, fmt.Errorf("erro ao gerar pôster: %w", err)
+	}
+
+	return result, nil
+}
+
+// run executa o ffmpeg e inclui o fim do stderr no erro
+func (f *FFmpeg) run(ctx context.Context, args ...string) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, f.path, append([]string{"-hide_banner", "-loglevel", "error"}, args...)...)
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if len(msg) > 500 {
+			msg = msg[len(msg)-500:]
+		}
+		return fmt.Errorf("%w: %s", err, msg)
+	}
+	return nil
+}
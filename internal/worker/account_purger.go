@@ -0,0 +1,100 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/recovery"
+	"chat-kafka-go/internal/reporter"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/clock"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// AccountPurger encerra de vez contas removidas (DELETE /users/me) depois da
+// janela de restauração. Com DELETED_USER_MESSAGES=hide a conta é apagada e as
+// mensagens saem em cascata (os uploads saem antes, pela coleta de anexos);
+// com anonymize a conta fica sem identidade para o histórico do outro lado.
+// Retenção legal segura a conta nos dois modos
+type AccountPurger struct {
+	queries *repository.Queries
+	cfg     *config.Config
+	clock   clock.Clock // Fim da carência
+}
+
+// NewAccountPurger cria nova instância do worker
+func NewAccountPurger(queries *repository.Queries, cfg *config.Config) *AccountPurger {
+	return &AccountPurger{
+		queries: queries,
+		cfg:     cfg,
+		clock:   clock.System,
+	}
+}
+
+// SetClock troca o relógio (testes)
+func (p *AccountPurger) SetClock(c clock.Clock) {
+	p.clock = c
+}
+
+// Run executa imediatamente e depois a cada intervalo, até o contexto ser cancelado
+func (p *AccountPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.Worker.AccountPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		err := recovery.Guard(ctx, "account_purger", func() error {
+			return p.purge(ctx)
+		})
+		if err != nil {
+			log.Printf("ERRO: purga de contas: %v", err)
+			reporter.CaptureError(ctx, err, map[string]string{"component": "account_purger"})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purge processa lotes até esgotar as contas vencidas
+func (p *AccountPurger) purge(ctx context.Context) error {
+	cutoff := pgtype.Timestamp{Time: p.clock.Now().Add(-p.cfg.User.DeletionGracePeriod), Valid: true}
+	batch := p.cfg.Worker.AccountPurgeBatch
+
+	var total int64
+	for {
+		var n int64
+		if p.cfg.User.DeletedMessagesMode == "hide" {
+			deleted, err := p.queries.PurgeDeletedUsers(ctx, repository.PurgeDeletedUsersParams{
+				Cutoff: cutoff, BatchSize: int32(batch),
+			})
+			if err != nil {
+				return err
+			}
+			n = deleted
+		} else {
+			anonymized, err := p.queries.AnonymizeDeletedUsers(ctx, repository.AnonymizeDeletedUsersParams{
+				Cutoff: cutoff, BatchSize: int32(batch),
+			})
+			if err != nil {
+				return err
+			}
+			n = int64(anonymized)
+		}
+
+		total += n
+		if n < int64(batch) {
+			break
+		}
+	}
+
+	if total > 0 {
+		log.Printf("account_purger: %d contas encerradas (%s)", total, p.cfg.User.DeletedMessagesMode)
+	}
+	return nil
+}
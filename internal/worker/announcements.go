@@ -0,0 +1,238 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/metrics"
+	"chat-kafka-go/internal/recovery"
+	"chat-kafka-go/internal/reporter"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/events"
+	"chat-kafka-go/pkg/idgen"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// JobAnnouncementDeliver job bulk: entrega um lote de destinatários do anúncio
+const JobAnnouncementDeliver = "announcement.deliver"
+
+// announcementJob payload do job: destinatários com ID depois de After
+type announcementJob struct {
+	AnnouncementID string `json:"announcement_id"`
+	After          string `json:"after,omitempty"`
+}
+
+// AnnouncementDispatcher inicia os anúncios agendados vencidos e entrega
+// cada um em lotes pela faixa bulk: cada lote grava as mensagens do usuário
+// system, publica no tópico de mensagens (mesmo pipeline das conversas) e
+// enfileira o lote seguinte
+type AnnouncementDispatcher struct {
+	queries  *repository.Queries
+	producer service.KafkaProducer
+	bulk     *service.BulkPublisher
+	plans    *service.PlanService
+	ids      idgen.Generator
+	system   pgtype.UUID
+	cfg      *config.Config
+	clock    clock.Clock // Vencimento do agendamento
+}
+
+// NewAnnouncementDispatcher cria nova instância do worker
+func NewAnnouncementDispatcher(queries *repository.Queries, producer service.KafkaProducer, bulk *service.BulkPublisher, plans *service.PlanService, cfg *config.Config) *AnnouncementDispatcher {
+	ids, err := idgen.ForMode(cfg.Database.MessageIDMode, clock.System)
+	if err != nil {
+		ids = idgen.Random
+	}
+	system, _ := utils.StringToUUID(service.SystemUserID)
+	return &AnnouncementDispatcher{
+		queries:  queries,
+		producer: producer,
+		bulk:     bulk,
+		plans:    plans,
+		ids:      ids,
+		system:   system,
+		cfg:      cfg,
+		clock:    clock.System,
+	}
+}
+
+// SetClock troca o relógio (testes)
+func (d *AnnouncementDispatcher) SetClock(c clock.Clock) {
+	d.clock = c
+}
+
+// Run inicia anúncios vencidos a cada intervalo, até o contexto ser cancelado
+func (d *AnnouncementDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.Worker.AnnouncementInterval)
+	defer ticker.Stop()
+
+	for {
+		err := recovery.Guard(ctx, "announcement_dispatcher", func() error {
+			return d.startDue(ctx)
+		})
+		if err != nil {
+			log.Printf("ERRO: anúncios agendados: %v", err)
+			reporter.CaptureError(ctx, err, map[string]string{"component": "announcement_dispatcher"})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// startDue reserva os anúncios vencidos e enfileira o primeiro lote de cada;
+// falha ao enfileirar devolve o anúncio ao agendamento
+func (d *AnnouncementDispatcher) startDue(ctx context.Context) error {
+	announcements, err := d.queries.ClaimDueAnnouncements(ctx, repository.ClaimDueAnnouncementsParams{
+		Now:       pgtype.Timestamp{Time: d.clock.Now().UTC(), Valid: true},
+		BatchSize: 10,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao reservar anúncios: %w", err)
+	}
+
+	for _, a := range announcements {
+		id := utils.UUIDToString(a.ID)
+		if err := d.enqueue(ctx, announcementJob{AnnouncementID: id}); err != nil {
+			log.Printf("ERRO: anúncio %s: %v", id, err)
+			_ = d.queries.ReleaseAnnouncement(ctx, a.ID)
+			continue
+		}
+		log.Printf("✓ Anúncio %s em envio", id)
+	}
+	return nil
+}
+
+// DeliverBatch handler do job JobAnnouncementDeliver. Reprocessar o mesmo
+// lote é seguro: quem já recebeu é pulado
+func (d *AnnouncementDispatcher) DeliverBatch(ctx context.Context, payload json.RawMessage) error {
+	var job announcementJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return fmt.Errorf("payload de anúncio inválido: %w", err)
+	}
+	announcementID, err := utils.StringToUUID(job.AnnouncementID)
+	if err != nil {
+		return fmt.Errorf("announcement_id inválido: %w", err)
+	}
+	after := pgtype.UUID{Valid: true} // 00000000-...: antes de todos
+	if job.After != "" {
+		if after, err = utils.StringToUUID(job.After); err != nil {
+			return fmt.Errorf("after inválido: %w", err)
+		}
+	}
+
+	announcement, err := d.queries.GetAnnouncement(ctx, announcementID)
+	if err == pgx.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao buscar anúncio: %w", err)
+	}
+	if announcement.Status != service.AnnouncementSending {
+		return nil // Cancelado no meio do envio
+	}
+
+	recipients, err := d.queries.ListAnnouncementRecipients(ctx, repository.ListAnnouncementRecipientsParams{
+		AfterID:   after,
+		SystemID:  d.system,
+		BatchSize: int32(d.cfg.Worker.AnnouncementBatch),
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao listar destinatários: %w", err)
+	}
+
+	for _, recipient := range recipients {
+		if !d.inSegment(announcement, recipient) {
+			continue
+		}
+		if err := d.deliver(ctx, announcement, recipient.ID); err != nil {
+			return err
+		}
+	}
+
+	if len(recipients) < d.cfg.Worker.AnnouncementBatch {
+		if err := d.queries.FinishAnnouncement(ctx, announcementID); err != nil {
+			return fmt.Errorf("erro ao concluir anúncio: %w", err)
+		}
+		log.Printf("✓ Anúncio %s enviado", job.AnnouncementID)
+		return nil
+	}
+	return d.enqueue(ctx, announcementJob{
+		AnnouncementID: job.AnnouncementID,
+		After:          utils.UUIDToString(recipients[len(recipients)-1].ID),
+	})
+}
+
+// inSegment destinatário está no plano do anúncio (sem plano = todos)
+func (d *AnnouncementDispatcher) inSegment(a repository.Announcement, r repository.ListAnnouncementRecipientsRow) bool {
+	if a.SegmentPlan == nil {
+		return true
+	}
+	plan := d.cfg.Plan.Default
+	if r.Plan != nil && r.Status != nil {
+		plan = d.plans.PlanOf(repository.UserPlan{
+			UserID:           r.ID,
+			Plan:             *r.Plan,
+			Status:           *r.Status,
+			CurrentPeriodEnd: r.CurrentPeriodEnd,
+		})
+	}
+	return plan == *a.SegmentPlan
+}
+
+// deliver grava a mensagem do anúncio para o usuário e publica no tópico de
+// mensagens. A mensagem gravada é a entrega: falha ao publicar só perde o
+// tempo real (aparece no histórico)
+func (d *AnnouncementDispatcher) deliver(ctx context.Context, a repository.Announcement, userID pgtype.UUID) error {
+	messageID := pgtype.UUID{Bytes: d.ids.New(), Valid: true}
+	rows, err := d.queries.CreateAnnouncementMessage(ctx, repository.CreateAnnouncementMessageParams{
+		AnnouncementID: a.ID,
+		UserID:         userID,
+		MessageID:      messageID,
+		SenderID:       d.system,
+		Content:        a.Content,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao gravar anúncio: %w", err)
+	}
+	if rows == 0 {
+		return nil // Já recebeu (lote reprocessado)
+	}
+
+	receiver := utils.UUIDToString(userID)
+	event, err := events.Marshal(events.MessageSent{
+		ID:             utils.UUIDToString(messageID),
+		SenderID:       service.SystemUserID,
+		ReceiverID:     receiver,
+		Content:        a.Content,
+		Timestamp:      d.clock.Now().Unix(),
+		AnnouncementID: utils.UUIDToString(a.ID),
+	})
+	if err == nil {
+		err = d.producer.SendMessage(ctx, d.cfg.Kafka.Topic, utils.ConversationKey(service.SystemUserID, receiver), event)
+	}
+	if err != nil {
+		metrics.AnnouncementMessagesTotal.WithLabelValues("published_error").Inc()
+		log.Printf("WARN: anúncio %s para %s gravado sem tempo real: %v", utils.UUIDToString(a.ID), receiver, err)
+		return nil
+	}
+	metrics.AnnouncementMessagesTotal.WithLabelValues("delivered").Inc()
+	return nil
+}
+
+// enqueue publica o job do lote na faixa bulk (chave: anúncio, lotes em ordem)
+func (d *AnnouncementDispatcher) enqueue(ctx context.Context, job announcementJob) error {
+	return d.bulk.Enqueue(ctx, JobAnnouncementDeliver, job.AnnouncementID, job)
+}
@@ -0,0 +1,191 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/metrics"
+	"chat-kafka-go/internal/recovery"
+	"chat-kafka-go/internal/reporter"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/internal/storage"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Motivos de remoção de anexos órfãos
+const (
+	gcReasonUnfinished     = "unfinished"      // Upload não finalizado/não enviado no prazo
+	gcReasonUploadExpired  = "upload_expired"  // Sessão de upload retomável expirada
+	gcReasonMessageDeleted = "message_deleted" // Mensagem do anexo não existe mais
+	gcReasonRetention      = "retention"       // Mais antigo que a retenção configurada
+	gcReasonAccountPurged  = "account_purged"  // Conta removida a purgar (DELETED_USER_MESSAGES=hide)
+)
+
+// AttachmentGC remove anexos órfãos do armazenamento e da tabela attachments
+type AttachmentGC struct {
+	queries *repository.Queries
+	store   storage.Store
+	cfg     *config.Config
+	clock   clock.Clock // Retenção dos anexos
+}
+
+// NewAttachmentGC cria nova instância do worker
+func NewAttachmentGC(queries *repository.Queries, store storage.Store, cfg *config.Config) *AttachmentGC {
+	return &AttachmentGC{
+		queries: queries,
+		store:   store,
+		cfg:     cfg,
+		clock:   clock.System,
+	}
+}
+
+// SetClock troca o relógio (testes)
+func (g *AttachmentGC) SetClock(c clock.Clock) {
+	g.clock = c
+}
+
+// Run executa imediatamente e depois a cada intervalo, até o contexto ser cancelado
+func (g *AttachmentGC) Run(ctx context.Context) {
+	if g.cfg.Worker.AttachmentGCDryRun {
+		log.Println("✓ Coleta de anexos em modo dry-run (nada será removido)")
+	}
+
+	ticker := time.NewTicker(g.cfg.Worker.AttachmentGCInterval)
+	defer ticker.Stop()
+
+	for {
+		err := recovery.Guard(ctx, "attachment_gc", func() error {
+			return g.collect(ctx)
+		})
+		if err != nil {
+			log.Printf("ERRO: coleta de anexos: %v", err)
+			reporter.CaptureError(ctx, err, map[string]string{"component": "attachment_gc"})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// collect processa cada motivo de remoção
+func (g *AttachmentGC) collect(ctx context.Context) error {
+	now := g.clock.Now()
+	batch := int32(g.cfg.Worker.AttachmentGCBatch)
+
+	err := g.sweep(ctx, gcReasonUnfinished, func() ([]repository.Attachment, error) {
+		return g.queries.ListStaleAttachmentUploads(ctx, repository.ListStaleAttachmentUploadsParams{
+			Cutoff:    pgtype.Timestamp{Time: now.Add(-g.cfg.Storage.UploadTTL), Valid: true},
+			BatchSize: batch,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	err = g.sweep(ctx, gcReasonUploadExpired, func() ([]repository.Attachment, error) {
+		return g.queries.ListExpiredUploadAttachments(ctx, repository.ListExpiredUploadAttachmentsParams{
+			Cutoff:    pgtype.Timestamp{Time: now, Valid: true},
+			BatchSize: batch,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	err = g.sweep(ctx, gcReasonMessageDeleted, func() ([]repository.Attachment, error) {
+		return g.queries.ListAttachmentsWithDeletedMessage(ctx, batch)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Com anonymize as mensagens (e anexos) da conta continuam visíveis
+	if g.cfg.User.DeletedMessagesMode == "hide" {
+		err = g.sweep(ctx, gcReasonAccountPurged, func() ([]repository.Attachment, error) {
+			return g.queries.ListPurgeableUserAttachments(ctx, repository.ListPurgeableUserAttachmentsParams{
+				Cutoff:    pgtype.Timestamp{Time: now.Add(-g.cfg.User.DeletionGracePeriod), Valid: true},
+				BatchSize: batch,
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if g.cfg.Storage.AttachmentRetention <= 0 {
+		return nil
+	}
+	return g.sweep(ctx, gcReasonRetention, func() ([]repository.Attachment, error) {
+		return g.queries.ListExpiredAttachments(ctx, repository.ListExpiredAttachmentsParams{
+			Cutoff:    pgtype.Timestamp{Time: now.Add(-g.cfg.Storage.AttachmentRetention), Valid: true},
+			BatchSize: batch,
+		})
+	})
+}
+
+// sweep remove lotes até esgotar os candidatos; em dry-run olha só o primeiro lote
+func (g *AttachmentGC) sweep(ctx context.Context, reason string, list func() ([]repository.Attachment, error)) error {
+	dryRun := g.cfg.Worker.AttachmentGCDryRun
+
+	for {
+		attachments, err := list()
+		if err != nil {
+			return fmt.Errorf("erro ao listar anexos (%s): %w", reason, err)
+		}
+
+		removed := 0
+		for _, attachment := range attachments {
+			if dryRun {
+				log.Printf("attachment_gc (dry-run): removeria anexo %s (%s, %d bytes)",
+					utils.UUIDToString(attachment.ID), reason, attachment.SizeBytes)
+				metrics.AttachmentGCTotal.WithLabelValues(reason, "dry_run").Inc()
+				continue
+			}
+
+			if err := g.remove(ctx, attachment); err != nil {
+				log.Printf("ERRO: remover anexo %s: %v", utils.UUIDToString(attachment.ID), err)
+				continue
+			}
+			removed++
+			metrics.AttachmentGCTotal.WithLabelValues(reason, "deleted").Inc()
+			metrics.AttachmentGCBytesTotal.WithLabelValues(reason).Add(float64(attachment.SizeBytes))
+		}
+
+		if removed > 0 {
+			log.Printf("attachment_gc: %d anexos removidos (%s)", removed, reason)
+		}
+		// Lote incompleto = acabou; falhas interrompem para não repetir os mesmos itens
+		if dryRun || len(attachments) < g.cfg.Worker.AttachmentGCBatch || removed < len(attachments) {
+			return nil
+		}
+	}
+}
+
+// remove apaga objeto (e versões derivadas) antes da linha: falha no armazenamento mantém
+// a linha para nova tentativa
+func (g *AttachmentGC) remove(ctx context.Context, attachment repository.Attachment) error {
+	if err := g.store.Delete(ctx, attachment.StorageKey); err != nil {
+		return err
+	}
+	for _, key := range []*string{attachment.PreviewKey, attachment.RenditionKey, attachment.PosterKey} {
+		if key == nil {
+			continue
+		}
+		if err := g.store.Delete(ctx, *key); err != nil {
+			return err
+		}
+	}
+	if err := g.queries.DeleteAttachment(ctx, attachment.ID); err != nil {
+		return fmt.Errorf("erro ao remover registro: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,321 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"chat-kafka-go/internal/antivirus"
+	"chat-kafka-go/internal/classifier"
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/media"
+	"chat-kafka-go/internal/metrics"
+	"chat-kafka-go/internal/recovery"
+	"chat-kafka-go/internal/reporter"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/internal/storage"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/status"
+	"chat-kafka-go/pkg/types"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// quarantinePrefix prefixo das chaves de anexos infectados
+const quarantinePrefix = "quarantine/"
+
+// previewSuffix sufixo da chave da prévia desfocada
+const previewSuffix = ".blur.jpg"
+
+// AttachmentScanner verifica anexos finalizados no antivírus; infectados vão
+// para quarentena, a mensagem é marcada e o remetente é notificado.
+// Imagens limpas passam pelo classificador NSFW antes de serem liberadas
+type AttachmentScanner struct {
+	queries       *repository.Queries
+	store         storage.Store
+	scanner       antivirus.Scanner
+	classifier    classifier.Classifier
+	notifications *service.NotificationService
+	cfg           *config.Config
+	timeout       time.Duration
+	clock         clock.Clock // Varreduras travadas
+}
+
+// NewAttachmentScanner cria nova instância do worker
+func NewAttachmentScanner(queries *repository.Queries, store storage.Store, scanner antivirus.Scanner, classifier classifier.Classifier, notifications *service.NotificationService, cfg *config.Config) *AttachmentScanner {
+	return &AttachmentScanner{
+		queries:       queries,
+		store:         store,
+		scanner:       scanner,
+		classifier:    classifier,
+		notifications: notifications,
+		cfg:           cfg,
+		timeout:       cfg.Security.ClamAVTimeout + cfg.Storage.NSFWClassifierTimeout,
+		clock:         clock.System,
+	}
+}
+
+// SetClock troca o relógio (testes)
+func (s *AttachmentScanner) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Run verifica lotes a cada intervalo, até o contexto ser cancelado
+func (s *AttachmentScanner) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Worker.AttachmentScanInterval)
+	defer ticker.Stop()
+
+	for {
+		err := recovery.Guard(ctx, "attachment_scanner", func() error {
+			return s.scanBatch(ctx)
+		})
+		if err != nil {
+			log.Printf("ERRO: varredura de anexos: %v", err)
+			reporter.CaptureError(ctx, err, map[string]string{"component": "attachment_scanner"})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// scanBatch reserva e verifica um lote; falhas individuais devolvem o anexo à fila
+func (s *AttachmentScanner) scanBatch(ctx context.Context) error {
+	attachments, err := s.queries.ClaimAttachmentsForScan(ctx, repository.ClaimAttachmentsForScanParams{
+		// Reserva mais antiga que o dobro do timeout = worker caiu no meio
+		StaleBefore: pgtype.Timestamp{Time: s.clock.Now().Add(-2 * s.timeout), Valid: true},
+		BatchSize:   int32(s.cfg.Worker.AttachmentScanBatch),
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao reservar anexos: %w", err)
+	}
+
+	for _, attachment := range attachments {
+		if err := s.scan(ctx, attachment); err != nil {
+			metrics.AttachmentScansTotal.WithLabelValues(service.ScanError).Inc()
+			log.Printf("ERRO: varredura do anexo %s: %v", utils.UUIDToString(attachment.ID), err)
+			_ = s.queries.ReleaseAttachmentScan(ctx, attachment.ID)
+		}
+	}
+	return nil
+}
+
+// scan verifica um anexo e grava o resultado
+func (s *AttachmentScanner) scan(ctx context.Context, attachment repository.Attachment) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	content, err := s.store.Open(ctx, attachment.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		// Sem conteúdo não há o que tentar de novo
+		metrics.AttachmentScansTotal.WithLabelValues(service.ScanError).Inc()
+		return s.saveResult(ctx, attachment, service.ScanError, "conteúdo não encontrado", attachment.StorageKey)
+	}
+	if err != nil {
+		return err
+	}
+	result, err := s.scanner.Scan(ctx, content)
+	content.Close()
+	if err != nil {
+		return err
+	}
+
+	if result.Infected {
+		metrics.AttachmentScansTotal.WithLabelValues(service.ScanInfected).Inc()
+		return s.quarantine(ctx, attachment, result.Signature)
+	}
+
+	// Classifica antes de liberar: o destinatário não pode baixar o original
+	// enquanto a política ainda não foi aplicada
+	if err := s.classify(ctx, attachment); err != nil {
+		// Classificador indisponível não bloqueia anexos (falha aberta)
+		log.Printf("ERRO: classificação do anexo %s: %v", utils.UUIDToString(attachment.ID), err)
+	}
+
+	status := service.ScanClean
+	if result.Skipped {
+		status = service.ScanSkipped
+	}
+	metrics.AttachmentScansTotal.WithLabelValues(status).Inc()
+	if err := s.saveResult(ctx, attachment, status, "", attachment.StorageKey); err != nil {
+		return err
+	}
+	return s.enqueueTranscode(ctx, attachment)
+}
+
+// enqueueTranscode coloca vídeos liberados na fila de transcodificação
+func (s *AttachmentScanner) enqueueTranscode(ctx context.Context, attachment repository.Attachment) error {
+	if s.cfg.Worker.FFmpegPath == "" || !strings.HasPrefix(attachment.ContentType, "video/") {
+		return nil
+	}
+	if err := s.queries.CreateTranscodeJob(ctx, attachment.ID); err != nil {
+		// Varredura já gravada; o original continua disponível sem as versões derivadas
+		log.Printf("ERRO: enfileirar transcodificação do anexo %s: %v", utils.UUIDToString(attachment.ID), err)
+	}
+	return nil
+}
+
+// classify roda o classificador NSFW em imagens e, com política blur, gera a
+// prévia desfocada das sinalizadas
+func (s *AttachmentScanner) classify(ctx context.Context, attachment repository.Attachment) error {
+	policy := s.cfg.Storage.NSFWPolicy
+	if policy == service.NSFWPolicyOff || !s.classifier.Enabled() || !strings.HasPrefix(attachment.ContentType, "image/") {
+		return nil
+	}
+
+	content, err := s.store.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		return err
+	}
+	result, err := s.classifier.Classify(ctx, attachment.ContentType, content)
+	content.Close()
+	if err != nil {
+		return err
+	}
+
+	flagged := result.Score >= s.cfg.Storage.NSFWThreshold
+	var previewKey *string
+	if flagged && policy == service.NSFWPolicyBlur {
+		key, err := s.blurPreview(ctx, attachment)
+		if err != nil {
+			// Sem prévia o download exige confirmação do mesmo jeito (ver AttachmentService.Open)
+			log.Printf("ERRO: prévia desfocada do anexo %s: %v", utils.UUIDToString(attachment.ID), err)
+		} else {
+			previewKey = &key
+		}
+	}
+
+	labels := result.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+	err = s.queries.UpdateAttachmentClassification(ctx, repository.UpdateAttachmentClassificationParams{
+		ID:          attachment.ID,
+		NsfwScore:   &result.Score,
+		NsfwLabels:  labels,
+		NsfwFlagged: flagged,
+		PreviewKey:  previewKey,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao salvar classificação: %w", err)
+	}
+	return nil
+}
+
+// blurPreview grava a versão desfocada da imagem e retorna sua chave
+func (s *AttachmentScanner) blurPreview(ctx context.Context, attachment repository.Attachment) (string, error) {
+	content, err := s.store.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		return "", err
+	}
+	defer content.Close()
+
+	preview, err := media.BlurPreview(content)
+	if err != nil {
+		return "", err
+	}
+
+	key := attachment.StorageKey + previewSuffix
+	if _, err := s.store.Put(ctx, key, bytes.NewReader(preview)); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// quarantine move o arquivo infectado, marca a mensagem e avisa o remetente
+func (s *AttachmentScanner) quarantine(ctx context.Context, attachment repository.Attachment, signature string) error {
+	key := quarantinePrefix + attachment.StorageKey
+	if err := s.store.Move(ctx, attachment.StorageKey, key); err != nil {
+		return fmt.Errorf("erro ao mover anexo para quarentena: %w", err)
+	}
+	if err := s.saveResult(ctx, attachment, service.ScanInfected, signature, key); err != nil {
+		return err
+	}
+	log.Printf("anexo %s em quarentena: %s", utils.UUIDToString(attachment.ID), signature)
+
+	// Relê: o anexo pode ter sido vinculado a uma mensagem durante a varredura
+	attachment, err := s.queries.GetAttachmentByID(ctx, attachment.ID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar anexo: %w", err)
+	}
+	if attachment.MessageID.Valid {
+		if err := s.quarantineMessage(ctx, attachment.MessageID); err != nil {
+			return err
+		}
+	}
+
+	err = s.notifications.Notify(ctx, attachment.UploaderID, service.NotificationAttachmentInfected, types.AttachmentInfectedAlert{
+		AttachmentID: utils.UUIDToString(attachment.ID),
+		MessageID:    utils.UUIDToString(attachment.MessageID),
+		FileName:     attachment.FileName,
+		Signature:    signature,
+	})
+	if err != nil {
+		// Quarentena já aplicada; notificação perdida não justifica nova varredura
+		log.Printf("ERRO: notificar anexo infectado: %v", err)
+	}
+	return nil
+}
+
+// quarantineAttempts releituras quando um ack concorrente avança a versão
+const quarantineAttempts = 3
+
+// quarantineMessage marca a mensagem do anexo infectado; quarentena vale a
+// partir de qualquer etapa, então um conflito de versão só exige reler
+func (s *AttachmentScanner) quarantineMessage(ctx context.Context, id pgtype.UUID) error {
+	var message repository.Message
+	for attempt := 0; attempt < quarantineAttempts; attempt++ {
+		var err error
+		message, err = s.queries.GetMessageByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar mensagem: %w", err)
+		}
+		if message.Status == status.MessageQuarantined {
+			return nil
+		}
+		if err := message.Status.Transition(status.MessageQuarantined); err != nil {
+			return err
+		}
+
+		rows, err := s.queries.UpdateMessageStatus(ctx, repository.UpdateMessageStatusParams{
+			ID:      id,
+			Status:  status.MessageQuarantined,
+			Version: message.Version,
+		})
+		if err != nil {
+			return fmt.Errorf("erro ao marcar mensagem: %w", err)
+		}
+		if rows > 0 {
+			return nil
+		}
+	}
+	return &status.ConflictError{Entity: "mensagem", ID: utils.UUIDToString(id), Version: message.Version, Current: string(message.Status)}
+}
+
+// saveResult grava estado da varredura
+func (s *AttachmentScanner) saveResult(ctx context.Context, attachment repository.Attachment, status, result, key string) error {
+	var scanResult *string
+	if result != "" {
+		scanResult = &result
+	}
+
+	err := s.queries.UpdateAttachmentScan(ctx, repository.UpdateAttachmentScanParams{
+		ID:         attachment.ID,
+		ScanStatus: status,
+		ScanResult: scanResult,
+		StorageKey: key,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao salvar resultado da varredura: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package worker
+
+import (
+	"context"
+
+	"chat-kafka-go/internal/eventbus"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/events"
+)
+
+// AutoResponder envia as respostas automáticas (férias/ausência) das DMs
+// recebidas (consumer group próprio: uma resposta lenta não atrasa a entrega)
+type AutoResponder struct {
+	replies *service.AutoReplyService
+}
+
+// NewAutoResponder cria nova instância do respondedor
+func NewAutoResponder(replies *service.AutoReplyService) *AutoResponder {
+	return &AutoResponder{replies: replies}
+}
+
+// Handle implementa eventbus.Handler
+func (a *AutoResponder) Handle(ctx context.Context, msg *eventbus.Message) error {
+	// O tópico também leva message.status: só mensagens são respondidas
+	if t := events.TypeOf(msg.Value); t != "" && t != events.TypeMessageSent {
+		return nil
+	}
+
+	var event events.MessageSent
+	if err := events.Decode(msg.Value, &event); err != nil {
+		return err
+	}
+	return a.replies.Respond(ctx, event)
+}
@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"chat-kafka-go/internal/eventbus"
+	"chat-kafka-go/internal/metrics"
+	"chat-kafka-go/pkg/events"
+)
+
+// BulkJobHandler processa o payload de um tipo de job bulk
+type BulkJobHandler func(ctx context.Context, payload json.RawMessage) error
+
+// BulkDispatcher consome o tópico bulk e encaminha cada job ao handler do tipo
+type BulkDispatcher struct {
+	handlers map[string]BulkJobHandler
+}
+
+// NewBulkDispatcher cria dispatcher sem tipos registrados
+func NewBulkDispatcher() *BulkDispatcher {
+	return &BulkDispatcher{handlers: map[string]BulkJobHandler{}}
+}
+
+// Register associa o tipo de job ao handler (chamar antes de consumir)
+func (d *BulkDispatcher) Register(jobType string, handler BulkJobHandler) {
+	d.handlers[jobType] = handler
+}
+
+// Handle implementa eventbus.Handler
+func (d *BulkDispatcher) Handle(ctx context.Context, msg *eventbus.Message) error {
+	var job events.BulkJob
+	if err := events.Decode(msg.Value, &job); err != nil {
+		return err
+	}
+
+	handler, ok := d.handlers[job.Type]
+	if !ok {
+		metrics.BulkJobsTotal.WithLabelValues(job.Type, "unknown").Inc()
+		return fmt.Errorf("tipo de job bulk desconhecido: %s", job.Type)
+	}
+
+	if err := handler(ctx, job.Payload); err != nil {
+		metrics.BulkJobsTotal.WithLabelValues(job.Type, "error").Inc()
+		return fmt.Errorf("erro no job %s: %w", job.Type, err)
+	}
+	metrics.BulkJobsTotal.WithLabelValues(job.Type, "success").Inc()
+	return nil
+}
@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"chat-kafka-go/internal/archive"
+	"chat-kafka-go/internal/eventbus"
+	"chat-kafka-go/internal/metrics"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/events"
+)
+
+// ComplianceArchiver espelha cada mensagem finalizada no arquivo de
+// compliance (consumer group próprio); falhas voltam para as retentativas
+// do eventbus, então nenhuma mensagem é pulada
+type ComplianceArchiver struct {
+	sink  archive.Sink
+	clock clock.Clock
+}
+
+// NewComplianceArchiver cria nova instância do arquivador
+func NewComplianceArchiver(sink archive.Sink) *ComplianceArchiver {
+	return &ComplianceArchiver{sink: sink, clock: clock.System}
+}
+
+// SetClock troca o relógio (testes)
+func (a *ComplianceArchiver) SetClock(c clock.Clock) {
+	a.clock = c
+}
+
+// Handle implementa eventbus.Handler
+func (a *ComplianceArchiver) Handle(ctx context.Context, msg *eventbus.Message) error {
+	// O tópico também leva message.status: só mensagens são arquivadas
+	if t := events.TypeOf(msg.Value); t != "" && t != events.TypeMessageSent {
+		return nil
+	}
+
+	var event events.MessageSent
+	if err := events.Decode(msg.Value, &event); err != nil {
+		return err
+	}
+
+	err := a.sink.Put(ctx, archive.Record{
+		ID:             event.ID,
+		SenderID:       event.SenderID,
+		ReceiverID:     event.ReceiverID,
+		Content:        event.Content,
+		SentAt:         time.Unix(event.Timestamp, 0).UTC().Format(time.RFC3339),
+		Mentions:       event.Mentions,
+		AnnouncementID: event.AnnouncementID,
+		Topic:          msg.Topic,
+		Partition:      msg.Partition,
+		Offset:         msg.Offset,
+		ArchivedAt:     a.clock.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		metrics.ArchivedMessagesTotal.WithLabelValues("error").Inc()
+		return fmt.Errorf("erro ao arquivar mensagem %s: %w", event.ID, err)
+	}
+	metrics.ArchivedMessagesTotal.WithLabelValues("archived").Inc()
+	return nil
+}
@@ -0,0 +1,172 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+	"unicode/utf8"
+
+	"chat-kafka-go/internal/eventbus"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/internal/reqctx"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/internal/slo"
+	"chat-kafka-go/internal/ws"
+	"chat-kafka-go/pkg/events"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// previewLength tamanho máximo (em caracteres) do preview na lista de conversas
+const previewLength = 100
+
+// MessageProcessor processa eventos de mensagem consumidos do Kafka
+type MessageProcessor struct {
+	queries  *repository.Queries
+	notifier *Notifier               // Opcional: push/email respeitando não perturbe
+	hub      ws.Deliverer            // Opcional: entrega em tempo real (hub local ou roteada entre instâncias)
+	delivery *slo.Tracker            // Opcional: SLO de latência das entregas em tempo real
+	receipts *service.MessageService // Opcional: marca delivered ao entregar no WebSocket
+}
+
+// NewMessageProcessor cria nova instância do processor
+func NewMessageProcessor(queries *repository.Queries, notifier *Notifier, hub ws.Deliverer) *MessageProcessor {
+	return &MessageProcessor{
+		queries:  queries,
+		notifier: notifier,
+		hub:      hub,
+	}
+}
+
+// SetDeliverySLO registra a latência das mensagens entregues a destinatários online
+func (p *MessageProcessor) SetDeliverySLO(t *slo.Tracker) {
+	p.delivery = t
+}
+
+// SetDeliveryReceipts avança a mensagem para delivered (e avisa o remetente)
+// quando o frame chega a uma conexão do destinatário
+func (p *MessageProcessor) SetDeliveryReceipts(messages *service.MessageService) {
+	p.receipts = messages
+}
+
+// Handle implementa eventbus.Handler
+func (p *MessageProcessor) Handle(ctx context.Context, msg *eventbus.Message) error {
+	// O tópico também leva message.status (frames já enviados por quem mudou o status)
+	if t := events.TypeOf(msg.Value); t != "" && t != events.TypeMessageSent {
+		return nil
+	}
+
+	var event events.MessageSent
+	if err := events.Decode(msg.Value, &event); err != nil {
+		return err
+	}
+
+	// Shadow ban: remetente vê a mensagem normalmente, destinatário não recebe nada
+	senderID, err := utils.StringToUUID(event.SenderID)
+	if err != nil {
+		return fmt.Errorf("sender_id inválido: %w", err)
+	}
+	shadowBanned, err := p.queries.IsUserShadowBanned(ctx, senderID)
+	if err != nil && err != pgx.ErrNoRows {
+		return fmt.Errorf("erro ao verificar shadow ban: %w", err)
+	}
+
+	if err := p.updateConversationSummaries(ctx, event, !shadowBanned); err != nil {
+		return err
+	}
+	if shadowBanned {
+		return nil
+	}
+
+	if p.hub != nil {
+		delivered, err := p.hub.SendToUser(event.ReceiverID, "message", event)
+		if err != nil {
+			return fmt.Errorf("erro ao entregar via websocket: %w", err)
+		}
+		if delivered > 0 {
+			p.delivery.ObserveMessage(event.ID)
+			p.markDelivered(ctx, event)
+		}
+	}
+
+	if p.notifier != nil {
+		if err := p.notifier.Notify(ctx, event); err != nil {
+			return fmt.Errorf("erro ao notificar: %w", err)
+		}
+	}
+	return nil
+}
+
+// markDelivered grava a entrega; falha só é logada (reprocessar o evento
+// entregaria a mensagem de novo)
+func (p *MessageProcessor) markDelivered(ctx context.Context, event events.MessageSent) {
+	if p.receipts == nil {
+		return
+	}
+	if _, err := p.receipts.MarkAsDelivered(reqctx.WithSystem(ctx), event.ID, event.ReceiverID); err != nil {
+		log.Printf("WARN: marcar mensagem %s como entregue: %v", event.ID, err)
+	}
+}
+
+// updateConversationSummaries atualiza o resumo dos dois lados da conversa
+// (apenas do remetente se fanOut for false)
+func (p *MessageProcessor) updateConversationSummaries(ctx context.Context, event events.MessageSent, fanOut bool) error {
+	messageID, err := utils.StringToUUID(event.ID)
+	if err != nil {
+		return fmt.Errorf("id inválido: %w", err)
+	}
+	senderID, err := utils.StringToUUID(event.SenderID)
+	if err != nil {
+		return fmt.Errorf("sender_id inválido: %w", err)
+	}
+	receiverID, err := utils.StringToUUID(event.ReceiverID)
+	if err != nil {
+		return fmt.Errorf("receiver_id inválido: %w", err)
+	}
+
+	sentAt := pgtype.Timestamp{Time: time.Unix(event.Timestamp, 0), Valid: true}
+	preview := truncate(event.Content, previewLength)
+
+	// Remetente: atualiza última mensagem sem incrementar não lidas
+	err = p.queries.UpsertConversationSummary(ctx, repository.UpsertConversationSummaryParams{
+		UserID:             senderID,
+		PeerID:             receiverID,
+		LastMessageID:      messageID,
+		LastMessagePreview: preview,
+		LastMessageAt:      sentAt,
+		UnreadCount:        0,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar resumo do remetente: %w", err)
+	}
+
+	if !fanOut {
+		return nil
+	}
+
+	// Destinatário: +1 não lida
+	err = p.queries.UpsertConversationSummary(ctx, repository.UpsertConversationSummaryParams{
+		UserID:             receiverID,
+		PeerID:             senderID,
+		LastMessageID:      messageID,
+		LastMessagePreview: preview,
+		LastMessageAt:      sentAt,
+		UnreadCount:        1,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar resumo do destinatário: %w", err)
+	}
+
+	return nil
+}
+
+// truncate corta a string em n caracteres (runes), não bytes
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
@@ -0,0 +1,198 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"chat-kafka-go/internal/database"
+	"chat-kafka-go/internal/eventbus"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/events"
+	"chat-kafka-go/pkg/status"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// BulkWriter gravação em massa de mensagens (implementado por *database.DB)
+type BulkWriter interface {
+	CopyMessages(ctx context.Context, messages []repository.Message, opts database.BulkOptions) (database.BulkResult, error)
+	CopyMessageStatuses(ctx context.Context, statuses []database.MessageStatus, opts database.BulkOptions) (database.BulkResult, error)
+}
+
+// MessageRestorer regrava a tabela messages a partir do tópico (replay do
+// rebuild, ex: depois de restaurar um backup antigo). Acumula message.sent
+// e message.status e grava em lotes via COPY com ConflictMerge: mensagens
+// existentes só avançam, nunca voltam de status. Menções e anexos não fazem
+// parte do evento e não são restaurados
+type MessageRestorer struct {
+	db        BulkWriter
+	batchSize int
+
+	mu       sync.Mutex
+	messages []repository.Message
+	index    map[pgtype.UUID]int // ID → posição em messages
+	statuses []database.MessageStatus
+	result   database.BulkResult
+}
+
+// NewMessageRestorer cria nova instância do restaurador
+func NewMessageRestorer(db BulkWriter, batchSize int) *MessageRestorer {
+	if batchSize <= 0 {
+		batchSize = 5000
+	}
+	return &MessageRestorer{
+		db:        db,
+		batchSize: batchSize,
+		index:     make(map[pgtype.UUID]int),
+	}
+}
+
+// Handle implementa eventbus.Handler; grava sozinho ao encher um lote
+func (r *MessageRestorer) Handle(ctx context.Context, msg *eventbus.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch events.TypeOf(msg.Value) {
+	case "", events.TypeMessageSent:
+		var event events.MessageSent
+		if err := events.Decode(msg.Value, &event); err != nil {
+			return err
+		}
+		if err := r.addMessage(event); err != nil {
+			return err
+		}
+	case events.TypeMessageStatus:
+		var event events.MessageStatusChanged
+		if err := events.Decode(msg.Value, &event); err != nil {
+			return err
+		}
+		if err := r.addStatus(event); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+
+	if len(r.messages)+len(r.statuses) >= r.batchSize {
+		return r.flush(ctx)
+	}
+	return nil
+}
+
+// Flush grava o que está acumulado; chame antes de salvar o checkpoint
+func (r *MessageRestorer) Flush(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.flush(ctx)
+}
+
+// Result totais gravados até agora
+func (r *MessageRestorer) Result() database.BulkResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.result
+}
+
+// flush grava mensagens antes dos status (status de mensagem do mesmo lote
+// já foi aplicado na linha)
+func (r *MessageRestorer) flush(ctx context.Context) error {
+	opts := database.BulkOptions{BatchSize: r.batchSize, OnConflict: database.ConflictMerge}
+	if len(r.messages) > 0 {
+		res, err := r.db.CopyMessages(ctx, r.messages, opts)
+		r.add(res)
+		if err != nil {
+			return fmt.Errorf("erro ao restaurar mensagens: %w", err)
+		}
+	}
+	if len(r.statuses) > 0 {
+		res, err := r.db.CopyMessageStatuses(ctx, r.statuses, opts)
+		r.add(res)
+		if err != nil {
+			return fmt.Errorf("erro ao restaurar status: %w", err)
+		}
+	}
+
+	r.messages = r.messages[:0]
+	r.statuses = r.statuses[:0]
+	clear(r.index)
+	return nil
+}
+
+func (r *MessageRestorer) add(res database.BulkResult) {
+	r.result.Processed += res.Processed
+	r.result.Inserted += res.Inserted
+	r.result.Updated += res.Updated
+}
+
+// addMessage acumula a mensagem como accepted na versão 1
+func (r *MessageRestorer) addMessage(event events.MessageSent) error {
+	id, err := utils.StringToUUID(event.ID)
+	if err != nil {
+		return fmt.Errorf("id inválido: %w", err)
+	}
+	senderID, err := utils.StringToUUID(event.SenderID)
+	if err != nil {
+		return fmt.Errorf("sender_id inválido: %w", err)
+	}
+	receiverID, err := utils.StringToUUID(event.ReceiverID)
+	if err != nil {
+		return fmt.Errorf("receiver_id inválido: %w", err)
+	}
+	if _, ok := r.index[id]; ok {
+		return nil // Reentrega: a primeira já carrega os status seguintes
+	}
+
+	r.index[id] = len(r.messages)
+	r.messages = append(r.messages, repository.Message{
+		ID:         id,
+		SenderID:   senderID,
+		ReceiverID: receiverID,
+		Content:    event.Content,
+		Status:     status.MessageAccepted,
+		CreatedAt:  unixTimestamp(event.Timestamp),
+		Version:    1,
+	})
+	return nil
+}
+
+// addStatus aplica a etapa na mensagem do lote ou acumula para mesclar na
+// linha já gravada
+func (r *MessageRestorer) addStatus(event events.MessageStatusChanged) error {
+	id, err := utils.StringToUUID(event.MessageID)
+	if err != nil {
+		return fmt.Errorf("message_id inválido: %w", err)
+	}
+	at := unixTimestamp(event.At)
+
+	i, ok := r.index[id]
+	if !ok {
+		r.statuses = append(r.statuses, database.MessageStatus{
+			ID: id, Status: event.Status, At: at, Version: event.Version,
+		})
+		return nil
+	}
+
+	m := &r.messages[i]
+	if event.Version <= m.Version || m.Status.Transition(event.Status) != nil {
+		return nil // Fora de ordem ou regressão
+	}
+	m.Status, m.Version = event.Status, event.Version
+	switch event.Status {
+	case status.MessageDelivered:
+		m.DeliveredAt = at
+	case status.MessageRead:
+		m.ReadAt = at
+		if !m.DeliveredAt.Valid {
+			m.DeliveredAt = at
+		}
+	}
+	return nil
+}
+
+// unixTimestamp horário Unix (segundos) como timestamp UTC do banco
+func unixTimestamp(sec int64) pgtype.Timestamp {
+	return pgtype.Timestamp{Time: time.Unix(sec, 0).UTC(), Valid: true}
+}
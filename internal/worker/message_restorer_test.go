@@ -0,0 +1,104 @@
+package worker
+
+import (
+	"context"
+	"testing"
+
+	"chat-kafka-go/internal/database"
+	"chat-kafka-go/internal/eventbus"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/events"
+	"chat-kafka-go/pkg/status"
+)
+
+// bulkRecorder BulkWriter que guarda o que seria gravado
+type bulkRecorder struct {
+	messages []repository.Message
+	statuses []database.MessageStatus
+	modes    []database.ConflictMode
+}
+
+func (b *bulkRecorder) CopyMessages(ctx context.Context, messages []repository.Message, opts database.BulkOptions) (database.BulkResult, error) {
+	b.modes = append(b.modes, opts.OnConflict)
+	b.messages = append(b.messages, messages...)
+	return database.BulkResult{Processed: int64(len(messages)), Inserted: int64(len(messages))}, nil
+}
+
+func (b *bulkRecorder) CopyMessageStatuses(ctx context.Context, statuses []database.MessageStatus, opts database.BulkOptions) (database.BulkResult, error) {
+	b.statuses = append(b.statuses, statuses...)
+	return database.BulkResult{Processed: int64(len(statuses)), Updated: int64(len(statuses))}, nil
+}
+
+const (
+	restoreMessageID = "11111111-1111-1111-1111-111111111111"
+	restoreSenderID  = "22222222-2222-2222-2222-222222222222"
+	restoreReceiver  = "33333333-3333-3333-3333-333333333333"
+)
+
+func publish(t *testing.T, r *MessageRestorer, e events.Event) {
+	t.Helper()
+	value, err := events.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Handle(context.Background(), &eventbus.Message{Value: value}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func statusEvent(st status.Message, at int64, version int32) events.MessageStatusChanged {
+	return events.MessageStatusChanged{
+		MessageID: restoreMessageID, SenderID: restoreSenderID, ReceiverID: restoreReceiver,
+		Status: st, At: at, Version: version,
+	}
+}
+
+func TestMessageRestorerFoldsStatusIntoBufferedMessage(t *testing.T) {
+	db := &bulkRecorder{}
+	r := NewMessageRestorer(db, 100)
+
+	publish(t, r, events.MessageSent{
+		ID: restoreMessageID, SenderID: restoreSenderID, ReceiverID: restoreReceiver,
+		Content: "oi", Timestamp: 1000,
+	})
+	publish(t, r, statusEvent(status.MessageRead, 1200, 2))
+	publish(t, r, statusEvent(status.MessageDelivered, 1100, 1)) // Atrasado: não volta
+
+	if err := r.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(db.messages) != 1 || len(db.statuses) != 0 {
+		t.Fatalf("gravadas %d mensagens e %d status, quer 1 e 0", len(db.messages), len(db.statuses))
+	}
+	if db.modes[0] != database.ConflictMerge {
+		t.Errorf("modo = %v, quer ConflictMerge (replay só avança mensagens existentes)", db.modes[0])
+	}
+	m := db.messages[0]
+	if m.Status != status.MessageRead || m.Version != 2 || m.ReadAt.Time.Unix() != 1200 || m.DeliveredAt.Time.Unix() != 1200 {
+		t.Errorf("mensagem = %+v, quer read v2 com read_at e delivered_at = 1200", m)
+	}
+	if m.CreatedAt.Time.Unix() != 1000 {
+		t.Errorf("created_at = %v, quer o timestamp do evento", m.CreatedAt.Time)
+	}
+}
+
+func TestMessageRestorerKeepsStatusOfFlushedMessage(t *testing.T) {
+	db := &bulkRecorder{}
+	r := NewMessageRestorer(db, 1) // Cada evento enche o lote
+
+	publish(t, r, events.MessageSent{
+		ID: restoreMessageID, SenderID: restoreSenderID, ReceiverID: restoreReceiver,
+		Content: "oi", Timestamp: 1000,
+	})
+	publish(t, r, statusEvent(status.MessageDelivered, 1100, 2))
+
+	if len(db.messages) != 1 || len(db.statuses) != 1 {
+		t.Fatalf("gravadas %d mensagens e %d status, quer 1 e 1", len(db.messages), len(db.statuses))
+	}
+	if s := db.statuses[0]; s.Status != status.MessageDelivered || s.Version != 2 {
+		t.Errorf("status = %+v, quer delivered v2", s)
+	}
+	if res := r.Result(); res.Inserted != 1 || res.Updated != 1 {
+		t.Errorf("result = %+v, quer 1 inserida e 1 atualizada", res)
+	}
+}
@@ -0,0 +1,65 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/events"
+	"chat-kafka-go/pkg/utils"
+)
+
+// PushSender envia notificação push/email (FCM, APNs, SMTP...)
+type PushSender interface {
+	Send(ctx context.Context, userID string, event events.MessageSent) error
+}
+
+// LogPushSender apenas loga (desenvolvimento / sem provedor configurado)
+type LogPushSender struct{}
+
+// Send implementa PushSender
+func (LogPushSender) Send(ctx context.Context, userID string, event events.MessageSent) error {
+	log.Printf("push: usuário=%s mensagem=%s", userID, event.ID)
+	return nil
+}
+
+// Notifier decide se o destinatário recebe push/email para uma mensagem
+type Notifier struct {
+	dnd    *service.DNDService
+	sender PushSender
+	clock  clock.Clock // Janelas de não perturbe
+}
+
+// NewNotifier cria nova instância do notifier
+func NewNotifier(dnd *service.DNDService, sender PushSender) *Notifier {
+	return &Notifier{
+		dnd:    dnd,
+		sender: sender,
+		clock:  clock.System,
+	}
+}
+
+// SetClock troca o relógio (testes)
+func (n *Notifier) SetClock(c clock.Clock) {
+	n.clock = c
+}
+
+// Notify envia push ao destinatário, exceto durante não perturbe
+func (n *Notifier) Notify(ctx context.Context, event events.MessageSent) error {
+	receiverID, err := utils.StringToUUID(event.ReceiverID)
+	if err != nil {
+		return fmt.Errorf("receiver_id inválido: %w", err)
+	}
+
+	quiet, err := n.dnd.IsQuiet(ctx, receiverID, n.clock.Now())
+	if err != nil {
+		return err
+	}
+	if quiet {
+		return nil
+	}
+
+	return n.sender.Send(ctx, event.ReceiverID, event)
+}
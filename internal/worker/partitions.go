@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"chat-kafka-go/internal/recovery"
+	"chat-kafka-go/internal/reporter"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/pkg/clock"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// PartitionMaintainer garante que as partições mensais de messages existam
+// antes que as mensagens do mês cheguem (evita cair na partição default)
+type PartitionMaintainer struct {
+	queries     *repository.Queries
+	monthsAhead int
+	interval    time.Duration
+	clock       clock.Clock // Meses das partições
+}
+
+// NewPartitionMaintainer cria nova instância do worker
+func NewPartitionMaintainer(queries *repository.Queries, monthsAhead int, interval time.Duration) *PartitionMaintainer {
+	return &PartitionMaintainer{
+		queries:     queries,
+		monthsAhead: monthsAhead,
+		interval:    interval,
+		clock:       clock.System,
+	}
+}
+
+// SetClock troca o relógio (testes)
+func (m *PartitionMaintainer) SetClock(c clock.Clock) {
+	m.clock = c
+}
+
+// Run executa imediatamente e depois a cada intervalo, até o contexto ser cancelado
+func (m *PartitionMaintainer) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		err := recovery.Guard(ctx, "partition_worker", func() error {
+			return m.ensurePartitions(ctx)
+		})
+		if err != nil {
+			log.Printf("ERRO: manutenção de partições: %v", err)
+			reporter.CaptureError(ctx, err, map[string]string{"component": "partition_worker"})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ensurePartitions cria a partição do mês atual e dos próximos meses
+func (m *PartitionMaintainer) ensurePartitions(ctx context.Context) error {
+	now := m.clock.Now()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i <= m.monthsAhead; i++ {
+		month := pgtype.Date{Time: start.AddDate(0, i, 0), Valid: true}
+		if _, err := m.queries.CreateMessagesPartition(ctx, month); err != nil {
+			return err
+		}
+	}
+	return nil
+}
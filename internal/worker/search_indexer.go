@@ -0,0 +1,122 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/eventbus"
+	"chat-kafka-go/internal/recovery"
+	"chat-kafka-go/internal/reporter"
+	"chat-kafka-go/internal/search"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/events"
+)
+
+// SearchIndexer espelha as mensagens do tópico no índice de busca externo
+// (consumer group próprio) e aplica a retenção apagando índices mensais antigos
+type SearchIndexer struct {
+	index search.Index
+	cfg   *config.SearchConfig
+	clock clock.Clock               // Retenção dos índices
+	holds *service.LegalHoldService // nil = sem consulta a retenções legais
+}
+
+// NewSearchIndexer cria nova instância do indexador
+func NewSearchIndexer(index search.Index, cfg *config.SearchConfig) *SearchIndexer {
+	return &SearchIndexer{
+		index: index,
+		cfg:   cfg,
+		clock: clock.System,
+	}
+}
+
+// SetClock troca o relógio (testes)
+func (i *SearchIndexer) SetClock(c clock.Clock) {
+	i.clock = c
+}
+
+// SetLegalHolds suspende a retenção dos índices enquanto houver retenção
+// legal ativa (índices mensais não permitem apagar só parte dos documentos)
+func (i *SearchIndexer) SetLegalHolds(holds *service.LegalHoldService) {
+	i.holds = holds
+}
+
+// Handle implementa eventbus.Handler
+func (i *SearchIndexer) Handle(ctx context.Context, msg *eventbus.Message) error {
+	// O tópico também leva message.status: só mensagens são indexadas
+	if t := events.TypeOf(msg.Value); t != "" && t != events.TypeMessageSent {
+		return nil
+	}
+
+	var event events.MessageSent
+	if err := events.Decode(msg.Value, &event); err != nil {
+		return err
+	}
+
+	err := i.index.IndexMessage(ctx, search.Document{
+		ID:         event.ID,
+		SenderID:   event.SenderID,
+		ReceiverID: event.ReceiverID,
+		Content:    event.Content,
+		CreatedAt:  time.Unix(event.Timestamp, 0),
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao indexar mensagem %s: %w", event.ID, err)
+	}
+	return nil
+}
+
+// Run garante o template dos índices e aplica a retenção a cada intervalo,
+// até o contexto ser cancelado
+func (i *SearchIndexer) Run(ctx context.Context) {
+	if err := i.index.EnsureTemplate(ctx); err != nil {
+		log.Printf("ERRO: template do índice de busca: %v", err)
+		reporter.CaptureError(ctx, err, map[string]string{"component": "search_indexer"})
+	}
+	if i.cfg.Retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(i.cfg.RetentionCheck)
+	defer ticker.Stop()
+
+	for {
+		err := recovery.Guard(ctx, "search_indexer", func() error {
+			return i.applyRetention(ctx)
+		})
+		if err != nil {
+			log.Printf("ERRO: retenção do índice de busca: %v", err)
+			reporter.CaptureError(ctx, err, map[string]string{"component": "search_indexer"})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// applyRetention apaga índices cujo mês inteiro é mais antigo que a retenção
+func (i *SearchIndexer) applyRetention(ctx context.Context) error {
+	if i.holds != nil {
+		held, err := i.holds.AnyActive(ctx)
+		if err != nil {
+			return err
+		}
+		if held {
+			log.Println("WARN: retenção do índice de busca suspensa (retenção legal ativa)")
+			return nil
+		}
+	}
+
+	deleted, err := i.index.DeleteIndicesBefore(ctx, i.clock.Now().Add(-i.cfg.Retention))
+	for _, name := range deleted {
+		log.Printf("✓ Índice de busca %s removido (retenção)", name)
+	}
+	return err
+}
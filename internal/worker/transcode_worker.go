@@ -0,0 +1,249 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"chat-kafka-go/internal/config"
+	"chat-kafka-go/internal/metrics"
+	"chat-kafka-go/internal/recovery"
+	"chat-kafka-go/internal/reporter"
+	"chat-kafka-go/internal/repository"
+	"chat-kafka-go/internal/service"
+	"chat-kafka-go/internal/storage"
+	"chat-kafka-go/internal/transcoder"
+	"chat-kafka-go/internal/ws"
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/events"
+	"chat-kafka-go/pkg/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Sufixos das chaves das versões derivadas do vídeo
+const (
+	renditionSuffix = ".web.mp4"
+	posterSuffix    = ".poster.jpg"
+)
+
+// TranscodeWorker consome a fila de transcodificação: gera a versão web
+// (H.264/MP4) e o pôster dos vídeos liberados pelo antivírus e avisa os
+// participantes da conversa via WebSocket
+type TranscodeWorker struct {
+	queries    *repository.Queries
+	store      storage.Store
+	transcoder transcoder.Transcoder
+	hub        ws.Deliverer
+	cfg        *config.Config
+	clock      clock.Clock // Jobs travados
+}
+
+// NewTranscodeWorker cria nova instância do worker
+func NewTranscodeWorker(queries *repository.Queries, store storage.Store, transcoder transcoder.Transcoder, hub ws.Deliverer, cfg *config.Config) *TranscodeWorker {
+	return &TranscodeWorker{
+		queries:    queries,
+		store:      store,
+		transcoder: transcoder,
+		hub:        hub,
+		cfg:        cfg,
+		clock:      clock.System,
+	}
+}
+
+// SetClock troca o relógio (testes)
+func (t *TranscodeWorker) SetClock(c clock.Clock) {
+	t.clock = c
+}
+
+// Run processa lotes a cada intervalo, até o contexto ser cancelado
+func (t *TranscodeWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.cfg.Worker.TranscodeInterval)
+	defer ticker.Stop()
+
+	for {
+		err := recovery.Guard(ctx, "transcode_worker", func() error {
+			return t.processBatch(ctx)
+		})
+		if err != nil {
+			log.Printf("ERRO: transcodificação: %v", err)
+			reporter.CaptureError(ctx, err, map[string]string{"component": "transcode_worker"})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processBatch reserva e processa um lote; falhas voltam à fila até o limite de tentativas
+func (t *TranscodeWorker) processBatch(ctx context.Context) error {
+	jobs, err := t.queries.ClaimTranscodeJobs(ctx, repository.ClaimTranscodeJobsParams{
+		// Reserva mais antiga que o dobro do timeout = worker caiu no meio
+		StaleBefore: pgtype.Timestamp{Time: t.clock.Now().Add(-2 * t.cfg.Worker.TranscodeTimeout), Valid: true},
+		BatchSize:   int32(t.cfg.Worker.TranscodeBatch),
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao reservar jobs de transcodificação: %w", err)
+	}
+
+	for _, job := range jobs {
+		if err := t.process(ctx, job); err != nil {
+			t.fail(ctx, job, err)
+			continue
+		}
+		metrics.TranscodeJobsTotal.WithLabelValues("done").Inc()
+	}
+	return nil
+}
+
+// process transcodifica um vídeo, grava as versões derivadas e avisa a conversa
+func (t *TranscodeWorker) process(ctx context.Context, job repository.TranscodeJob) error {
+	ctx, cancel := context.WithTimeout(ctx, t.cfg.Worker.TranscodeTimeout)
+	defer cancel()
+
+	attachment, err := t.queries.GetAttachmentByID(ctx, job.AttachmentID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar anexo: %w", err)
+	}
+	if attachment.ScanStatus == service.ScanInfected {
+		// Foi para a quarentena depois de enfileirado: nada a gerar
+		return t.queries.CompleteTranscodeJob(ctx, job.AttachmentID)
+	}
+
+	dir, err := os.MkdirTemp("", "transcode-")
+	if err != nil {
+		return fmt.Errorf("erro ao criar diretório temporário: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "input"+filepath.Ext(attachment.FileName))
+	if err := t.download(ctx, attachment.StorageKey, input); err != nil {
+		return err
+	}
+
+	result, err := t.transcoder.Transcode(ctx, input, dir)
+	if err != nil {
+		return err
+	}
+
+	renditionKey := attachment.StorageKey + renditionSuffix
+	if err := t.upload(ctx, result.VideoPath, renditionKey); err != nil {
+		return err
+	}
+	posterKey := attachment.StorageKey + posterSuffix
+	if err := t.upload(ctx, result.PosterPath, posterKey); err != nil {
+		return err
+	}
+
+	err = t.queries.SetAttachmentRenditions(ctx, repository.SetAttachmentRenditionsParams{
+		ID:           attachment.ID,
+		RenditionKey: &renditionKey,
+		PosterKey:    &posterKey,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao salvar versões do anexo: %w", err)
+	}
+	if err := t.queries.CompleteTranscodeJob(ctx, job.AttachmentID); err != nil {
+		return fmt.Errorf("erro ao concluir job: %w", err)
+	}
+
+	t.announce(ctx, attachment.ID)
+	return nil
+}
+
+// fail devolve o job à fila ou o encerra ao atingir o limite de tentativas
+func (t *TranscodeWorker) fail(ctx context.Context, job repository.TranscodeJob, cause error) {
+	result := "retry"
+	if int(job.Attempts) >= t.cfg.Worker.TranscodeMaxAttempts {
+		result = "failed"
+	}
+	metrics.TranscodeJobsTotal.WithLabelValues(result).Inc()
+	log.Printf("ERRO: transcodificação do anexo %s (tentativa %d): %v", utils.UUIDToString(job.AttachmentID), job.Attempts, cause)
+
+	message := cause.Error()
+	err := t.queries.FailTranscodeJob(ctx, repository.FailTranscodeJobParams{
+		MaxAttempts:  int32(t.cfg.Worker.TranscodeMaxAttempts),
+		Error:        &message,
+		AttachmentID: job.AttachmentID,
+	})
+	if err != nil {
+		log.Printf("ERRO: registrar falha de transcodificação: %v", err)
+	}
+}
+
+// download copia o original do storage para um arquivo local (ffmpeg precisa de seek)
+func (t *TranscodeWorker) download(ctx context.Context, key, path string) error {
+	content, err := t.store.Open(ctx, key)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir anexo: %w", err)
+	}
+	defer content.Close()
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("erro ao criar arquivo temporário: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, content); err != nil {
+		return fmt.Errorf("erro ao copiar anexo: %w", err)
+	}
+	return f.Close()
+}
+
+// upload grava um arquivo gerado no storage
+func (t *TranscodeWorker) upload(ctx context.Context, path, key string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir arquivo gerado: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := t.store.Put(ctx, key, f); err != nil {
+		return fmt.Errorf("erro ao gravar versão do anexo: %w", err)
+	}
+	return nil
+}
+
+// announce avisa remetente e destinatário das novas versões; antes do envio
+// da mensagem só o remetente conhece o anexo
+func (t *TranscodeWorker) announce(ctx context.Context, attachmentID pgtype.UUID) {
+	// Relê: o anexo pode ter sido vinculado a uma mensagem durante a transcodificação
+	attachment, err := t.queries.GetAttachmentByID(ctx, attachmentID)
+	if err != nil {
+		log.Printf("ERRO: buscar anexo transcodificado: %v", err)
+		return
+	}
+
+	event := events.AttachmentUpdated{
+		AttachmentID: utils.UUIDToString(attachment.ID),
+		Variants:     service.AttachmentVariants(attachment),
+	}
+	uploaderID := utils.UUIDToString(attachment.UploaderID)
+
+	if !attachment.MessageID.Valid {
+		if _, err := t.hub.SendToUser(uploaderID, "attachment.updated", event); err != nil {
+			log.Printf("ERRO: avisar anexo transcodificado: %v", err)
+		}
+		return
+	}
+
+	message, err := t.queries.GetMessageByID(ctx, attachment.MessageID)
+	if err != nil {
+		log.Printf("ERRO: buscar mensagem do anexo transcodificado: %v", err)
+		return
+	}
+	event.MessageID = utils.UUIDToString(message.ID)
+	for _, userID := range []pgtype.UUID{message.SenderID, message.ReceiverID} {
+		if _, err := t.hub.SendToUser(utils.UUIDToString(userID), "message.updated", event); err != nil {
+			log.Printf("ERRO: avisar anexo transcodificado: %v", err)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package ws
+
+import (
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait      = 10 * time.Second    // Tempo máximo para escrever um frame
+	pongWait       = 60 * time.Second    // Sem pong nesse intervalo = conexão morta
+	pingPeriod     = (pongWait * 9) / 10 // Deve ser menor que pongWait
+	maxMessageSize = 64 << 10            // Tamanho máximo de frame recebido
+	sendBuffer     = 256                 // Frames enfileirados por conexão
+)
+
+// Client conexão WebSocket de um usuário
+type Client struct {
+	hub    *Hub
+	conn   *websocket.Conn
+	userID string
+	send   chan []byte
+}
+
+// Serve registra a conexão no hub e bloqueia até ela ser encerrada
+func Serve(hub *Hub, conn *websocket.Conn, userID string) {
+	c := &Client{
+		hub:    hub,
+		conn:   conn,
+		userID: userID,
+		send:   make(chan []byte, sendBuffer),
+	}
+	hub.register(c)
+	hub.changed(userID)
+
+	go c.writePump()
+	c.readPump()
+}
+
+// WriteFrame envia frame direto na conexão recém-aberta (antes de Serve,
+// que passa a ser o único escritor)
+func WriteFrame(conn *websocket.Conn, frameType string, data interface{}) error {
+	_ = conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return conn.WriteJSON(Envelope{Type: frameType, Data: data})
+}
+
+// readPump lê frames do cliente até erro/fechamento
+func (c *Client) readPump() {
+	defer func() {
+		c.hub.unregister(c)
+		c.hub.changed(c.userID)
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		if _, _, err := c.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// writePump envia frames enfileirados e pings periódicos
+func (c *Client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case payload, ok := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				// Hub fechou a fila
+				_ = c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+				return
+			}
+		case <-ticker.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// drainPoll intervalo de verificação das conexões restantes na drenagem
+const drainPoll = 100 * time.Millisecond
+
+// DrainOptions tempos da drenagem antes de encerrar a instância
+type DrainOptions struct {
+	Delay time.Duration // Espera antes de migrar (balanceador tira a instância após /readyz 503)
+	Grace time.Duration // Prazo para os clientes reconectarem antes do fechamento forçado
+}
+
+// DrainStats resultado da drenagem
+type DrainStats struct {
+	Migrated int `json:"migrated"` // Conexões avisadas com frame "migrate"
+	Closed   int `json:"closed"`   // Conexões fechadas pelo servidor ao fim do prazo
+}
+
+// Motivos do frame "migrate"
+const (
+	MigrateShutdown  = "shutdown"  // Instância encerrando
+	MigrateRebalance = "rebalance" // Usuário pertence a outra instância (hash consistente)
+)
+
+// migrateFrame pede ao cliente que reconecte em outra instância
+type migrateFrame struct {
+	Reason           string `json:"reason"`
+	ReconnectAfterMs int64  `json:"reconnect_after_ms"` // Espera aleatória: evita reconexão em massa
+	URL              string `json:"url,omitempty"`      // Instância de destino (vazio = qualquer uma)
+}
+
+// OnDrain registra função chamada no início da drenagem (ex.: sair do anel
+// do cluster); registrar antes de servir conexões
+func (h *Hub) OnDrain(fn func(ctx context.Context)) {
+	h.onDrain = append(h.onDrain, fn)
+}
+
+// Draining indica se a instância está drenando (novas conexões recusadas)
+func (h *Hub) Draining() bool {
+	return h.draining.Load()
+}
+
+// Drain recusa novas conexões, pede às existentes que migrem e fecha as que
+// restarem depois do prazo. Idempotente: chamadas seguintes (pre-stop e
+// depois SIGTERM) esperam a primeira e retornam o mesmo resultado
+func (h *Hub) Drain(ctx context.Context) DrainStats {
+	h.drainOnce.Do(func() {
+		h.draining.Store(true)
+		for _, fn := range h.onDrain {
+			fn(ctx)
+		}
+		log.Printf("Drenando conexões WebSocket (%d ativas)", h.connections())
+
+		if !sleepCtx(ctx, h.drainOpts.Delay) {
+			h.drainStats.Closed = h.closeAll()
+			return
+		}
+		h.drainStats.Migrated = h.migrateAll()
+
+		deadline := time.Now().Add(h.drainOpts.Grace)
+		for h.connections() > 0 && time.Now().Before(deadline) {
+			if !sleepCtx(ctx, drainPoll) {
+				break
+			}
+		}
+		h.drainStats.Closed = h.closeAll()
+		log.Printf("✓ WebSocket drenado: %d migradas, %d fechadas", h.drainStats.Migrated, h.drainStats.Closed)
+	})
+	return h.drainStats
+}
+
+// migrateAll envia o frame "migrate" a cada conexão com espera aleatória
+// dentro de metade do prazo de drenagem
+func (h *Hub) migrateAll() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	spread := int64(h.drainOpts.Grace / 2 / time.Millisecond)
+	migrated := 0
+	for _, conns := range h.clients {
+		for c := range conns {
+			frame := migrateFrame{Reason: MigrateShutdown}
+			if spread > 0 {
+				frame.ReconnectAfterMs = rand.Int63n(spread)
+			}
+			payload, err := json.Marshal(Envelope{Type: "migrate", Data: frame})
+			if err != nil {
+				continue
+			}
+			select {
+			case c.send <- payload:
+				migrated++
+			default:
+				// Fila cheia: será fechada ao fim do prazo
+			}
+		}
+	}
+	return migrated
+}
+
+// MigrateUser pede às conexões do usuário que reconectem em url
+// Retorna quantas conexões foram avisadas
+func (h *Hub) MigrateUser(userID, url string) int {
+	payload, err := json.Marshal(Envelope{Type: "migrate", Data: migrateFrame{Reason: MigrateRebalance, URL: url}})
+	if err != nil {
+		return 0
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	migrated := 0
+	for c := range h.clients[userID] {
+		select {
+		case c.send <- payload:
+			migrated++
+		default:
+		}
+	}
+	return migrated
+}
+
+// WriteMigrate envia o frame "migrate" direto na conexão recém-aberta
+// (antes de Serve, que passa a ser o único escritor)
+func WriteMigrate(conn *websocket.Conn, url string) error {
+	return WriteFrame(conn, "migrate", migrateFrame{Reason: MigrateRebalance, URL: url})
+}
+
+// closeAll fecha as conexões restantes (frame de close) e recusa novas
+func (h *Hub) closeAll() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	closed := 0
+	for userID, conns := range h.clients {
+		for c := range conns {
+			close(c.send)
+			closed++
+		}
+		delete(h.clients, userID)
+	}
+	h.closed = true
+	return closed
+}
+
+// connections total de conexões ativas
+func (h *Hub) connections() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	total := 0
+	for _, conns := range h.clients {
+		total += len(conns)
+	}
+	return total
+}
+
+// sleepCtx espera d; false se o contexto terminar antes
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,185 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+)
+
+// Envelope formato dos frames enviados ao cliente
+type Envelope struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+// Deliverer entrega frames às conexões de um usuário
+// Implementado por Hub (só esta instância) e cluster.Router (entre instâncias)
+type Deliverer interface {
+	SendToUser(userID, frameType string, data interface{}) (int, error)
+}
+
+// Hub conexões WebSocket ativas desta instância, agrupadas por usuário
+type Hub struct {
+	mu      sync.RWMutex
+	clients map[string]map[*Client]struct{}
+
+	// Drenagem antes de encerrar (ver Drain)
+	drainOpts  DrainOptions
+	onDrain    []func(ctx context.Context)
+	onChange   []func(userID string)
+	draining   atomic.Bool
+	closed     bool // Conexões já fechadas: novas são recusadas no registro
+	drainOnce  sync.Once
+	drainStats DrainStats
+}
+
+// NewHub cria hub vazio
+func NewHub(drain DrainOptions) *Hub {
+	return &Hub{
+		clients:   map[string]map[*Client]struct{}{},
+		drainOpts: drain,
+	}
+}
+
+// register adiciona conexão do usuário
+func (h *Hub) register(c *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		close(c.send) // Handshake concluído durante a drenagem
+		return
+	}
+
+	conns, ok := h.clients[c.userID]
+	if !ok {
+		conns = map[*Client]struct{}{}
+		h.clients[c.userID] = conns
+	}
+	conns[c] = struct{}{}
+}
+
+// unregister remove conexão e fecha a fila de envio
+func (h *Hub) unregister(c *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	conns, ok := h.clients[c.userID]
+	if !ok {
+		return
+	}
+	if _, ok := conns[c]; !ok {
+		return
+	}
+	delete(conns, c)
+	close(c.send)
+	if len(conns) == 0 {
+		delete(h.clients, c.userID)
+	}
+}
+
+// SendToUser envia frame para todas as conexões do usuário
+// Retorna quantas conexões receberam (0 = offline nesta instância)
+func (h *Hub) SendToUser(userID, frameType string, data interface{}) (int, error) {
+	payload, err := json.Marshal(Envelope{Type: frameType, Data: data})
+	if err != nil {
+		return 0, err
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	delivered := 0
+	for c := range h.clients[userID] {
+		select {
+		case c.send <- payload:
+			delivered++
+		default:
+			// Cliente lento: fila cheia, descarta o frame
+		}
+	}
+	return delivered, nil
+}
+
+// Broadcast envia frame a todas as conexões desta instância (ex.: aviso de
+// manutenção). Retorna quantas conexões receberam
+func (h *Hub) Broadcast(frameType string, data interface{}) (int, error) {
+	payload, err := json.Marshal(Envelope{Type: frameType, Data: data})
+	if err != nil {
+		return 0, err
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	delivered := 0
+	for _, conns := range h.clients {
+		for c := range conns {
+			select {
+			case c.send <- payload:
+				delivered++
+			default:
+			}
+		}
+	}
+	return delivered, nil
+}
+
+// OnChange registra função chamada quando as conexões de um usuário mudam
+// (conexão aberta ou fechada); não deve bloquear. Registrar antes de servir conexões
+func (h *Hub) OnChange(fn func(userID string)) {
+	h.onChange = append(h.onChange, fn)
+}
+
+// changed avisa os interessados (fora do lock do hub)
+func (h *Hub) changed(userID string) {
+	for _, fn := range h.onChange {
+		fn(userID)
+	}
+}
+
+// Count conexões do usuário nesta instância
+func (h *Hub) Count(userID string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients[userID])
+}
+
+// IsOnline verifica se o usuário tem conexão nesta instância
+func (h *Hub) IsOnline(userID string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients[userID]) > 0
+}
+
+// Online implementa service.OnlineChecker sem cluster (só esta instância)
+func (h *Hub) Online(_ context.Context, userID string) (bool, error) {
+	return h.IsOnline(userID), nil
+}
+
+// Users usuários com conexão nesta instância
+func (h *Hub) Users() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	users := make([]string, 0, len(h.clients))
+	for userID := range h.clients {
+		users = append(users, userID)
+	}
+	return users
+}
+
+// Stats resumo para /debug/dump
+func (h *Hub) Stats() map[string]int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	conns := 0
+	for _, c := range h.clients {
+		conns += len(c)
+	}
+	return map[string]int{
+		"users":       len(h.clients),
+		"connections": conns,
+	}
+}
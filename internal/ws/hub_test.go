@@ -0,0 +1,59 @@
+package ws
+
+import (
+	"fmt"
+	"testing"
+)
+
+// benchHub hub com users usuários de conns conexões cada; as filas de envio
+// são esvaziadas em background como faria o writePump
+func benchHub(b *testing.B, users, conns int) *Hub {
+	b.Helper()
+	hub := NewHub(DrainOptions{})
+	for u := 0; u < users; u++ {
+		for c := 0; c < conns; c++ {
+			client := &Client{hub: hub, userID: fmt.Sprintf("user-%d", u), send: make(chan []byte, sendBuffer)}
+			hub.register(client)
+			go func() {
+				for range client.send {
+				}
+			}()
+		}
+	}
+	b.Cleanup(func() {
+		for _, conns := range hub.clients {
+			for c := range conns {
+				hub.unregister(c)
+			}
+		}
+	})
+	return hub
+}
+
+var benchFrame = map[string]string{"id": "0190f5e4-7c1a-7b3e-9d2f-1a2b3c4d5e6f", "content": "Oi! Chego às 18h, pode ser?"}
+
+func BenchmarkHubSendToUser(b *testing.B) {
+	for _, conns := range []int{1, 5} {
+		b.Run(fmt.Sprintf("conns=%d", conns), func(b *testing.B) {
+			hub := benchHub(b, 1000, conns)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := hub.SendToUser(fmt.Sprintf("user-%d", i%1000), "message", benchFrame); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkHubBroadcast(b *testing.B) {
+	hub := benchHub(b, 1000, 1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := hub.Broadcast("maintenance", benchFrame); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
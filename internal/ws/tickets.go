@@ -0,0 +1,107 @@
+package ws
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"chat-kafka-go/pkg/clock"
+	"chat-kafka-go/pkg/utils"
+)
+
+// TicketTTL validade do ticket de conexão
+const TicketTTL = 30 * time.Second
+
+type ticket struct {
+	userID    string
+	expiresAt time.Time
+}
+
+// TicketStore tickets de conexão de uso único trocados por um access token.
+// Evita JWT de longa duração na query string do handshake (e nos logs)
+type TicketStore interface {
+	// Issue emite ticket para o usuário (válido por TicketTTL)
+	Issue(ctx context.Context, userID string) (string, time.Time, error)
+	// Redeem consome o ticket (uso único) e retorna o dono
+	Redeem(ctx context.Context, value string) (string, bool)
+}
+
+// MemoryTicketStore tickets em memória: o cliente deve conectar na mesma
+// instância que emitiu o ticket (com cluster, usar o store compartilhado)
+type MemoryTicketStore struct {
+	mu      sync.Mutex
+	tickets map[string]ticket // hash do ticket -> dono
+	clock   clock.Clock       // Expiração dos tickets
+}
+
+// NewMemoryTicketStore cria store vazio
+func NewMemoryTicketStore() *MemoryTicketStore {
+	return &MemoryTicketStore{tickets: map[string]ticket{}, clock: clock.System}
+}
+
+// SetClock troca o relógio (testes)
+func (s *MemoryTicketStore) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Issue emite ticket para o usuário
+func (s *MemoryTicketStore) Issue(_ context.Context, userID string) (string, time.Time, error) {
+	value, err := utils.GenerateSecureToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expiresAt := s.clock.Now().Add(TicketTTL)
+
+	s.mu.Lock()
+	s.tickets[utils.HashToken(value)] = ticket{userID: userID, expiresAt: expiresAt}
+	s.mu.Unlock()
+
+	return value, expiresAt, nil
+}
+
+// Redeem consome o ticket (uso único) e retorna o dono
+func (s *MemoryTicketStore) Redeem(_ context.Context, value string) (string, bool) {
+	key := utils.HashToken(value)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tickets[key]
+	if !ok {
+		return "", false
+	}
+	delete(s.tickets, key)
+
+	if s.clock.Now().After(t.expiresAt) {
+		return "", false
+	}
+	return t.userID, true
+}
+
+// Len quantidade de tickets pendentes
+func (s *MemoryTicketStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.tickets)
+}
+
+// Run remove tickets expirados periodicamente
+func (s *MemoryTicketStore) Run(ctx context.Context) {
+	ticker := time.NewTicker(TicketTTL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			for key, t := range s.tickets {
+				if now.After(t.expiresAt) {
+					delete(s.tickets, key)
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package ws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"chat-kafka-go/pkg/clock"
+)
+
+func TestMemoryTicketStoreSingleUse(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryTicketStore()
+
+	value, _, err := store.Issue(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	userID, ok := store.Redeem(ctx, value)
+	if !ok || userID != "user-1" {
+		t.Fatalf("Redeem = %q, %v; esperado user-1, true", userID, ok)
+	}
+	if _, ok := store.Redeem(ctx, value); ok {
+		t.Fatal("ticket resgatado duas vezes")
+	}
+	if store.Len() != 0 {
+		t.Fatalf("Len = %d; esperado 0", store.Len())
+	}
+}
+
+func TestMemoryTicketStoreExpiry(t *testing.T) {
+	ctx := context.Background()
+	manual := clock.NewManual(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	store := NewMemoryTicketStore()
+	store.SetClock(manual)
+
+	value, expiresAt, err := store.Issue(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if want := manual.Now().Add(TicketTTL); !expiresAt.Equal(want) {
+		t.Fatalf("expiresAt = %v; esperado %v", expiresAt, want)
+	}
+
+	manual.Advance(TicketTTL + time.Second)
+	if _, ok := store.Redeem(ctx, value); ok {
+		t.Fatal("ticket expirado aceito")
+	}
+}
+
+func TestMemoryTicketStoreUnknown(t *testing.T) {
+	if _, ok := NewMemoryTicketStore().Redeem(context.Background(), "desconhecido"); ok {
+		t.Fatal("ticket desconhecido aceito")
+	}
+}
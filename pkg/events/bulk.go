@@ -0,0 +1,14 @@
+package events
+
+import "encoding/json"
+
+// BulkJob job de baixa prioridade publicado no tópico bulk (digests,
+// importações, exportações); nunca disputa workers com a entrega de mensagens
+type BulkJob struct {
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt int64           `json:"created_at"` // Unix (segundos)
+}
+
+// EventType implementa Event
+func (BulkJob) EventType() string { return TypeBulkJob }
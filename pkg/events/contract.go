@@ -0,0 +1,340 @@
+package events
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Contratos versionados no repositório (pkg/events/schemas/<tipo>.v<N>.json),
+// conferidos por TestContracts (go test ./pkg/events) e gravados com
+// `make event-contracts-write`
+//
+//go:embed schemas/*.json
+var committedFS embed.FS
+
+// Contract campos publicados de uma versão de evento
+type Contract struct {
+	Type    string  `json:"type"`
+	Version int     `json:"version"`
+	Fields  []Field `json:"fields"`
+}
+
+// Field campo do payload; Type segue o JSON (string, integer, number,
+// boolean, array<...>, object, any)
+type Field struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Optional bool   `json:"optional,omitempty"` // omitempty ou ponteiro
+}
+
+// FileName nome do arquivo do contrato
+func (c Contract) FileName() string {
+	return fmt.Sprintf("%s.v%d.json", c.Type, c.Version)
+}
+
+// Contracts contratos atuais (gerados dos structs) de todos os tipos
+func Contracts() []Contract {
+	types := make([]string, 0, len(schemas))
+	for t := range schemas {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	contracts := make([]Contract, 0, len(types))
+	for _, t := range types {
+		s := schemas[t]
+		contracts = append(contracts, Contract{Type: t, Version: s.version, Fields: describe(reflect.TypeOf(s.newEvent()))})
+	}
+	return contracts
+}
+
+// Committed contratos versionados, por arquivo
+func Committed() (map[string]Contract, error) {
+	entries, err := committedFS.ReadDir("schemas")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar contratos: %w", err)
+	}
+
+	committed := make(map[string]Contract, len(entries))
+	for _, entry := range entries {
+		data, err := committedFS.ReadFile(path.Join("schemas", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler contrato %s: %w", entry.Name(), err)
+		}
+		var c Contract
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, fmt.Errorf("contrato %s inválido: %w", entry.Name(), err)
+		}
+		if c.FileName() != entry.Name() {
+			return nil, fmt.Errorf("contrato %s declara %s v%d", entry.Name(), c.Type, c.Version)
+		}
+		committed[entry.Name()] = c
+	}
+	return committed, nil
+}
+
+// Compare diferenças entre o contrato versionado e o atual da mesma versão:
+// breaking (campo removido, tipo alterado, campo passou a ser obrigatório)
+// exige nova versão; added são campos novos, compatíveis
+func Compare(committed, current Contract) (breaking, added []string) {
+	fields := make(map[string]Field, len(current.Fields))
+	for _, f := range current.Fields {
+		fields[f.Name] = f
+	}
+
+	for _, old := range committed.Fields {
+		f, ok := fields[old.Name]
+		switch {
+		case !ok:
+			breaking = append(breaking, fmt.Sprintf("%s v%d: campo %q removido", current.Type, current.Version, old.Name))
+		case f.Type != old.Type:
+			breaking = append(breaking, fmt.Sprintf("%s v%d: campo %q mudou de %s para %s", current.Type, current.Version, old.Name, old.Type, f.Type))
+		case old.Optional && !f.Optional:
+			breaking = append(breaking, fmt.Sprintf("%s v%d: campo %q passou a ser obrigatório", current.Type, current.Version, old.Name))
+		}
+		delete(fields, old.Name)
+	}
+	for _, f := range current.Fields {
+		if _, isNew := fields[f.Name]; isNew {
+			added = append(added, f.Name)
+		}
+	}
+	return breaking, added
+}
+
+// CheckContracts confere os structs contra os contratos versionados e o
+// payload publicado de cada tipo contra o seu contrato; retorna as violações
+func CheckContracts() ([]string, error) {
+	committed, err := Committed()
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	for _, current := range Contracts() {
+		s := schemas[current.Type]
+
+		c, ok := committed[current.FileName()]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s v%d: contrato não versionado (make event-contracts-write)", current.Type, current.Version))
+		} else {
+			breaking, added := Compare(c, current)
+			problems = append(problems, breaking...)
+			if len(added) > 0 {
+				problems = append(problems, fmt.Sprintf("%s v%d: campos novos %v fora do contrato (make event-contracts-write)", current.Type, current.Version, added))
+			}
+			if err := validatePublished(c, s); err != nil {
+				problems = append(problems, err.Error())
+			}
+		}
+
+		// Versões antigas ainda aceitas precisam converter para a seguinte
+		for v := s.min; v < s.version; v++ {
+			if _, ok := s.upgrades[v]; !ok {
+				problems = append(problems, fmt.Sprintf("%s v%d: aceita sem conversão para v%d (registre o Upgrade ou suba min)", current.Type, v, v+1))
+			}
+		}
+	}
+
+	for name, c := range committed {
+		s, ok := schemas[c.Type]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: contrato de tipo que não existe mais (%s)", c.Type, name))
+		} else if c.Version > s.version {
+			problems = append(problems, fmt.Sprintf("%s v%d: contrato mais novo que a versão do código (v%d)", c.Type, c.Version, s.version))
+		}
+	}
+	sort.Strings(problems)
+	return problems, nil
+}
+
+// Validate confere o payload (data do envelope) contra o contrato
+func (c Contract) Validate(data json.RawMessage) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("%s v%d: payload não é objeto: %w", c.Type, c.Version, err)
+	}
+
+	for _, f := range c.Fields {
+		value, ok := fields[f.Name]
+		if !ok {
+			if !f.Optional {
+				return fmt.Errorf("%s v%d: campo obrigatório %q ausente", c.Type, c.Version, f.Name)
+			}
+			continue
+		}
+		if !matchesType(value, f.Type) {
+			return fmt.Errorf("%s v%d: campo %q não é %s: %s", c.Type, c.Version, f.Name, f.Type, value)
+		}
+	}
+	return nil
+}
+
+// validatePublished serializa um evento do tipo como o producer e confere
+// o envelope contra o contrato (pega MarshalJSON e tags fora do struct)
+func validatePublished(c Contract, s schema) error {
+	raw, err := Marshal(sample(s.newEvent()))
+	if err != nil {
+		return err
+	}
+	var env Envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return fmt.Errorf("%s: envelope inválido: %w", c.Type, err)
+	}
+	if env.Type != c.Type || env.Version != c.Version {
+		return fmt.Errorf("%s v%d: publicado como %s v%d", c.Type, c.Version, env.Type, env.Version)
+	}
+	return c.Validate(env.Data)
+}
+
+// sample evento com todos os campos preenchidos (omitempty não some)
+func sample(e Event) Event {
+	v := reflect.ValueOf(e).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		fill(v.Field(i))
+	}
+	return v.Addr().Interface().(Event)
+}
+
+// fill valor não zero para o campo
+func fill(v reflect.Value) {
+	if !v.CanSet() {
+		return
+	}
+	if v.Type() == reflect.TypeOf(json.RawMessage(nil)) {
+		v.SetBytes([]byte(`{}`))
+		return
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString("x")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1.5)
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fill(v.Index(0))
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		fill(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			fill(v.Field(i))
+		}
+	}
+}
+
+// describe campos JSON do struct do evento
+func describe(t reflect.Type) []Field {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	fields := []Field{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields = append(fields, Field{
+			Name:     name,
+			Type:     jsonType(sf.Type),
+			Optional: strings.Contains(opts, "omitempty") || sf.Type.Kind() == reflect.Pointer,
+		})
+	}
+	return fields
+}
+
+// jsonType tipo JSON do campo Go
+func jsonType(t reflect.Type) string {
+	if t == reflect.TypeOf(json.RawMessage(nil)) {
+		return "any"
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return jsonType(t.Elem())
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string" // []byte vira base64
+		}
+		return "array<" + jsonType(t.Elem()) + ">"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	default:
+		return "any"
+	}
+}
+
+// matchesType confere o valor JSON contra o tipo do contrato
+func matchesType(value json.RawMessage, fieldType string) bool {
+	value = json.RawMessage(strings.TrimSpace(string(value)))
+	if len(value) == 0 {
+		return false
+	}
+	if string(value) == "null" {
+		return true // Slices/mapas nil e ponteiros
+	}
+
+	switch {
+	case fieldType == "any":
+		return true
+	case fieldType == "string":
+		return value[0] == '"'
+	case fieldType == "boolean":
+		return string(value) == "true" || string(value) == "false"
+	case fieldType == "integer":
+		var n json.Number
+		if json.Unmarshal(value, &n) != nil {
+			return false
+		}
+		_, err := n.Int64()
+		return err == nil
+	case fieldType == "number":
+		var n float64
+		return json.Unmarshal(value, &n) == nil
+	case fieldType == "object":
+		return value[0] == '{'
+	case strings.HasPrefix(fieldType, "array<"):
+		var items []json.RawMessage
+		if json.Unmarshal(value, &items) != nil {
+			return false
+		}
+		elem := strings.TrimSuffix(strings.TrimPrefix(fieldType, "array<"), ">")
+		for _, item := range items {
+			if !matchesType(item, elem) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,122 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "grava em schemas/ os contratos novos ou com campos acrescentados")
+
+// TestContracts confere os structs dos eventos contra os contratos
+// versionados em schemas/ (golden files): campo removido, tipo alterado ou
+// campo que passou a ser obrigatório sem nova versão do evento falham o CI.
+// Com -update grava contratos novos ou que só ganharam campos; mudança
+// incompatível nunca é gravada: suba a versão do tipo e registre a conversão
+func TestContracts(t *testing.T) {
+	if *update {
+		writeContracts(t)
+	}
+
+	problems, err := CheckContracts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range problems {
+		t.Error(p)
+	}
+}
+
+// writeContracts grava os contratos que não existem ou só ganharam campos
+func writeContracts(t *testing.T) {
+	t.Helper()
+	committed, err := Committed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, current := range Contracts() {
+		if c, ok := committed[current.FileName()]; ok {
+			breaking, added := Compare(c, current)
+			if len(breaking) > 0 {
+				for _, b := range breaking {
+					t.Errorf("%s (suba a versão do evento)", b)
+				}
+				continue
+			}
+			if len(added) == 0 {
+				continue
+			}
+		}
+
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false) // array<string> legível
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(current); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join("schemas", current.FileName())
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		t.Logf("✓ %s", path)
+	}
+}
+
+func TestCompareRemovedField(t *testing.T) {
+	committed := Contract{Type: "message.sent", Version: 1, Fields: []Field{
+		{Name: "message_id", Type: "string"},
+		{Name: "content", Type: "string"},
+	}}
+	current := Contract{Type: "message.sent", Version: 1, Fields: []Field{
+		{Name: "message_id", Type: "string"},
+	}}
+
+	breaking, added := Compare(committed, current)
+	if len(breaking) != 1 || len(added) != 0 {
+		t.Fatalf("breaking = %v, added = %v; esperado 1 campo removido", breaking, added)
+	}
+}
+
+func TestCompareChangedField(t *testing.T) {
+	committed := Contract{Type: "message.sent", Version: 1, Fields: []Field{
+		{Name: "seq", Type: "integer"},
+		{Name: "reply_to", Type: "string", Optional: true},
+	}}
+	current := Contract{Type: "message.sent", Version: 1, Fields: []Field{
+		{Name: "seq", Type: "string"},
+		{Name: "reply_to", Type: "string"},
+		{Name: "edited", Type: "boolean", Optional: true},
+	}}
+
+	breaking, added := Compare(committed, current)
+	if len(breaking) != 2 {
+		t.Fatalf("breaking = %v; esperado tipo alterado e campo obrigatório", breaking)
+	}
+	if len(added) != 1 || added[0] != "edited" {
+		t.Fatalf("added = %v; esperado [edited]", added)
+	}
+}
+
+// TestContractsDetectRemovedField o contrato versionado de cada tipo falha
+// contra o struct atual se perder um campo
+func TestContractsDetectRemovedField(t *testing.T) {
+	committed, err := Committed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, current := range Contracts() {
+		c, ok := committed[current.FileName()]
+		if !ok || len(current.Fields) == 0 {
+			continue
+		}
+		current.Fields = current.Fields[1:]
+		if breaking, _ := Compare(c, current); len(breaking) == 0 {
+			t.Errorf("%s v%d: remoção de campo não detectada", current.Type, current.Version)
+		}
+	}
+}
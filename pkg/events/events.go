@@ -0,0 +1,180 @@
+// Package events eventos de domínio versionados, compartilhados por quem
+// publica no barramento, pelos consumers e pelos frames WebSocket
+//
+// No barramento cada evento vai num envelope {type, version, data}. Quem lê
+// aceita as versões entre a mínima e a atual do tipo (as antigas são
+// atualizadas em sequência); versão mais nova que a conhecida é recusada com
+// ErrUnsupportedVersion e o evento segue para retry/DLQ até o deploy chegar.
+// Payloads sem envelope (publicados antes deste pacote) são lidos como a
+// versão 1 do tipo esperado
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Tipos de evento
+const (
+	TypeMessageSent       = "message.sent"
+	TypeMessageStatus     = "message.status"
+	TypeFriendRequested   = "friend.requested"
+	TypePresenceChanged   = "presence.changed"
+	TypeAttachmentUpdated = "attachment.updated"
+	TypeBulkJob           = "bulk.job"
+
+	TypePreferencesUpdated = "preferences.updated"
+)
+
+var (
+	// ErrUnknownType tipo sem schema registrado
+	ErrUnknownType = errors.New("tipo de evento desconhecido")
+	// ErrUnsupportedVersion versão fora do intervalo aceito por esta instância
+	ErrUnsupportedVersion = errors.New("versão de evento não suportada")
+	// ErrUnexpectedType envelope de tipo diferente do esperado
+	ErrUnexpectedType = errors.New("tipo de evento inesperado")
+)
+
+// Event evento de domínio com schema registrado
+type Event interface {
+	EventType() string
+}
+
+// Envelope formato no barramento
+type Envelope struct {
+	Type    string          `json:"type"`
+	Version int             `json:"version"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// Upgrade converte o payload da versão N para N+1
+type Upgrade func(data json.RawMessage) (json.RawMessage, error)
+
+// schema versões aceitas de um tipo
+type schema struct {
+	version  int             // Atual (publicada)
+	min      int             // Mais antiga ainda aceita
+	upgrades map[int]Upgrade // Versão de origem → conversão para a seguinte
+	newEvent func() Event
+}
+
+// schemas tipos conhecidos; nova versão: incrementar version e registrar o
+// Upgrade da anterior (ou subir min para parar de aceitá-la)
+var schemas = map[string]schema{
+	TypeMessageSent:       {version: 1, min: 1, newEvent: func() Event { return &MessageSent{} }},
+	TypeMessageStatus:     {version: 1, min: 1, newEvent: func() Event { return &MessageStatusChanged{} }},
+	TypeFriendRequested:   {version: 1, min: 1, newEvent: func() Event { return &FriendRequested{} }},
+	TypePresenceChanged:   {version: 1, min: 1, newEvent: func() Event { return &PresenceChanged{} }},
+	TypeAttachmentUpdated: {version: 1, min: 1, newEvent: func() Event { return &AttachmentUpdated{} }},
+	TypeBulkJob:           {version: 1, min: 1, newEvent: func() Event { return &BulkJob{} }},
+
+	TypePreferencesUpdated: {version: 1, min: 1, newEvent: func() Event { return &PreferencesUpdated{} }},
+}
+
+// Version versão atual do tipo (0 se desconhecido)
+func Version(eventType string) int {
+	return schemas[eventType].version
+}
+
+// Marshal serializa o evento no envelope da versão atual
+func Marshal(e Event) ([]byte, error) {
+	s, ok := schemas[e.EventType()]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownType, e.EventType())
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar evento %s: %w", e.EventType(), err)
+	}
+	return json.Marshal(Envelope{Type: e.EventType(), Version: s.version, Data: data})
+}
+
+// Unmarshal lê um envelope de qualquer tipo conhecido
+func Unmarshal(raw []byte) (Event, error) {
+	env, ok, err := parseEnvelope(raw)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("evento sem envelope")
+	}
+	s, known := schemas[env.Type]
+	if !known {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownType, env.Type)
+	}
+
+	e := s.newEvent()
+	if err := decodeData(env, s, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Decode lê o evento no tipo esperado por target (ponteiro); payload sem
+// envelope é lido como versão 1 desse tipo
+func Decode(raw []byte, target Event) error {
+	s, known := schemas[target.EventType()]
+	if !known {
+		return fmt.Errorf("%w: %s", ErrUnknownType, target.EventType())
+	}
+
+	env, ok, err := parseEnvelope(raw)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		env = Envelope{Type: target.EventType(), Version: 1, Data: raw}
+	}
+	if env.Type != target.EventType() {
+		return fmt.Errorf("%w: %s (esperado %s)", ErrUnexpectedType, env.Type, target.EventType())
+	}
+	return decodeData(env, s, target)
+}
+
+// TypeOf tipo do envelope; "" para payload sem envelope (ou inválido), que
+// Decode lê como a versão 1 do tipo esperado. Consumidores de tópicos com
+// mais de um tipo usam para ignorar os que não tratam
+func TypeOf(raw []byte) string {
+	env, ok, err := parseEnvelope(raw)
+	if err != nil || !ok {
+		return ""
+	}
+	return env.Type
+}
+
+// parseEnvelope separa envelope de payload antigo (sem version/data)
+func parseEnvelope(raw []byte) (Envelope, bool, error) {
+	var env Envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return Envelope{}, false, fmt.Errorf("payload inválido: %w", err)
+	}
+	if env.Type == "" || env.Version == 0 || len(env.Data) == 0 {
+		return Envelope{}, false, nil
+	}
+	return env, true, nil
+}
+
+// decodeData atualiza o payload até a versão atual e preenche o evento
+func decodeData(env Envelope, s schema, target Event) error {
+	if env.Version < s.min || env.Version > s.version {
+		return fmt.Errorf("%w: %s v%d (aceitas v%d a v%d)", ErrUnsupportedVersion, env.Type, env.Version, s.min, s.version)
+	}
+
+	data := env.Data
+	for v := env.Version; v < s.version; v++ {
+		upgrade, ok := s.upgrades[v]
+		if !ok {
+			return fmt.Errorf("%w: %s v%d sem conversão para v%d", ErrUnsupportedVersion, env.Type, v, v+1)
+		}
+		var err error
+		if data, err = upgrade(data); err != nil {
+			return fmt.Errorf("erro ao converter %s v%d: %w", env.Type, v, err)
+		}
+	}
+
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("payload inválido (%s v%d): %w", env.Type, env.Version, err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package events
+
+import (
+	"bytes"
+	"testing"
+)
+
+// FuzzDecode envelopes vindos do barramento e de frames WebSocket são
+// entrada não confiável: Unmarshal, Decode, TypeOf e parseEnvelope nunca
+// entram em pânico, e todo evento lido volta igual por Marshal
+func FuzzDecode(f *testing.F) {
+	for _, seed := range []string{
+		// v1 válidos
+		`{"type":"message.sent","version":1,"data":{"id":"m1","sender_id":"a","receiver_id":"b","content":"oi @ana","timestamp":1700000000,"mentions":["u1"]}}`,
+		`{"type":"message.status","version":1,"data":{"message_id":"m1","sender_id":"a","receiver_id":"b","status":"read","at":1700000001,"version":3}}`,
+		`{"type":"bulk.job","version":1,"data":{"type":"announcement","payload":{"id":"x", "n": [1, 2]},"created_at":1}}`,
+		`{"type":"presence.changed","version":1,"data":{}}`,
+		// v2: mais nova que a conhecida
+		`{"type":"message.sent","version":2,"data":{"id":"m1","content":"oi"}}`,
+		`{"type":"message.status","version":2,"data":{"message_id":"m1"}}`,
+		// Tipos desconhecidos e versões inválidas
+		`{"type":"message.deleted","version":1,"data":{"id":"m1"}}`,
+		`{"type":"","version":1,"data":{}}`,
+		`{"type":"message.sent","version":-1,"data":{}}`,
+		`{"type":"message.sent","version":1,"data":null}`,
+		`{"type":"message.sent","version":1,"data":"texto"}`,
+		// Payload sem envelope (antes do pacote)
+		`{"id":"m1","sender_id":"a","receiver_id":"b","content":"oi","timestamp":1}`,
+		// JSON truncado ou inválido
+		`{"type":"message.sent","version":1,"data":{"id":"m1","content":"o`,
+		`{"type":"message.sent","vers`,
+		`{`,
+		``,
+		`null`,
+		`[]`,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, raw []byte) {
+		TypeOf(raw)
+		parseEnvelope(raw)
+
+		if e, err := Unmarshal(raw); err == nil {
+			assertRoundTrip(t, e)
+		}
+		for eventType, s := range schemas {
+			target := s.newEvent()
+			if err := Decode(raw, target); err != nil {
+				continue
+			}
+			if target.EventType() != eventType {
+				t.Fatalf("Decode em %s devolveu %s", eventType, target.EventType())
+			}
+			assertRoundTrip(t, target)
+		}
+	})
+}
+
+// assertRoundTrip o envelope de Marshal é lido de volta no mesmo evento
+// (comparado pela serialização: RawMessage e UTF-8 inválido são normalizados
+// na primeira passagem)
+func assertRoundTrip(t *testing.T, e Event) {
+	t.Helper()
+	first, err := Marshal(e)
+	if err != nil {
+		t.Fatalf("Marshal(%T) de evento lido: %v", e, err)
+	}
+
+	again := schemas[e.EventType()].newEvent()
+	if err := Decode(first, again); err != nil {
+		t.Fatalf("Decode do próprio Marshal: %v\n%s", err, first)
+	}
+	second, err := Marshal(again)
+	if err != nil {
+		t.Fatalf("Marshal(%T) na segunda passagem: %v", again, err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Fatalf("ida e volta mudou o evento:\n%s\n%s", first, second)
+	}
+}
@@ -0,0 +1,50 @@
+package events
+
+import "chat-kafka-go/pkg/status"
+
+// MessageSent mensagem enviada: tópico de mensagens e frame WebSocket "message"
+type MessageSent struct {
+	ID         string `json:"id"`
+	SenderID   string `json:"sender_id"`
+	ReceiverID string `json:"receiver_id"`
+	Content    string `json:"content"`
+	Timestamp  int64  `json:"timestamp"` // Unix (segundos)
+
+	// Mentions IDs dos usuários mencionados (não usernames, que podem mudar)
+	Mentions []string `json:"mentions,omitempty"`
+
+	// AnnouncementID anúncio do admin (remetente é o usuário system)
+	AnnouncementID string `json:"announcement_id,omitempty"`
+
+	// AutoReply resposta automática (férias/ausência); nunca é respondida
+	AutoReply bool `json:"auto_reply,omitempty"`
+}
+
+// MessageStatusChanged mensagem avançou de etapa: tópico de mensagens (mesma
+// chave de conversa, depois do message.sent) e frame WebSocket
+// "message.status" para o remetente
+type MessageStatusChanged struct {
+	MessageID  string         `json:"message_id"`
+	SenderID   string         `json:"sender_id"`
+	ReceiverID string         `json:"receiver_id"`
+	Status     status.Message `json:"status"`  // delivered ou read
+	At         int64          `json:"at"`      // Horário do servidor na etapa, Unix (segundos)
+	Version    int32          `json:"version"` // Versão da mensagem após a mudança (descarta frames fora de ordem)
+}
+
+// AttachmentUpdated frame WebSocket "message.updated" (ou
+// "attachment.updated" antes do envio): novas versões do anexo disponíveis
+type AttachmentUpdated struct {
+	AttachmentID string   `json:"attachment_id"`
+	MessageID    string   `json:"message_id,omitempty"`
+	Variants     []string `json:"variants"`
+}
+
+// EventType implementa Event
+func (MessageSent) EventType() string { return TypeMessageSent }
+
+// EventType implementa Event
+func (MessageStatusChanged) EventType() string { return TypeMessageStatus }
+
+// EventType implementa Event
+func (AttachmentUpdated) EventType() string { return TypeAttachmentUpdated }
@@ -0,0 +1,15 @@
+package events
+
+import "encoding/json"
+
+// PreferencesUpdated frame WebSocket "preferences.updated" para todos os
+// dispositivos do usuário: preferências completas (com padrões) após a mudança
+type PreferencesUpdated struct {
+	UserID      string                     `json:"user_id"`
+	Preferences map[string]json.RawMessage `json:"preferences"`
+	Version     int32                      `json:"version"`    // Descarta frames fora de ordem
+	UpdatedAt   int64                      `json:"updated_at"` // Unix (segundos)
+}
+
+// EventType implementa Event
+func (PreferencesUpdated) EventType() string { return TypePreferencesUpdated }
@@ -0,0 +1,22 @@
+package events
+
+// FriendRequested pedido de amizade enviado
+type FriendRequested struct {
+	FriendshipID string `json:"friendship_id"`
+	RequesterID  string `json:"requester_id"`
+	AddresseeID  string `json:"addressee_id"`
+	CreatedAt    int64  `json:"created_at"` // Unix (segundos)
+}
+
+// PresenceChanged usuário ficou online ou offline (todas as instâncias)
+type PresenceChanged struct {
+	UserID    string `json:"user_id"`
+	Online    bool   `json:"online"`
+	ChangedAt int64  `json:"changed_at"` // Unix (segundos)
+}
+
+// EventType implementa Event
+func (FriendRequested) EventType() string { return TypeFriendRequested }
+
+// EventType implementa Event
+func (PresenceChanged) EventType() string { return TypePresenceChanged }
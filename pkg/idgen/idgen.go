@@ -0,0 +1,189 @@
+// Package idgen geradores de IDs de 128 bits gravados em colunas UUID:
+// aleatório (UUIDv4), ordenados pelo tempo (UUIDv7 e ULID) e sequencial
+// determinístico para testes
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"chat-kafka-go/pkg/clock"
+
+	"github.com/google/uuid"
+)
+
+// Modos de geração (MESSAGE_ID_MODE)
+const (
+	ModeUUID   = "uuid"   // UUIDv4 aleatório
+	ModeUUIDv7 = "uuidv7" // RFC 9562: 48 bits de milissegundos + contador + aleatórios
+	ModeULID   = "ulid"   // 48 bits de milissegundos + 80 aleatórios: ordena pelo tempo
+)
+
+// Generator gera IDs únicos
+type Generator interface {
+	New() uuid.UUID
+}
+
+// Random UUIDv4 (uuid.New)
+var Random Generator = randomGenerator{}
+
+type randomGenerator struct{}
+
+func (randomGenerator) New() uuid.UUID { return uuid.New() }
+
+// ForMode gerador do modo configurado; modo desconhecido é erro
+func ForMode(mode string, c clock.Clock) (Generator, error) {
+	switch mode {
+	case "", ModeUUID:
+		return Random, nil
+	case ModeUUIDv7:
+		return NewUUIDv7(c), nil
+	case ModeULID:
+		return NewULID(c), nil
+	default:
+		return nil, fmt.Errorf("modo de ID desconhecido: %s (uuid, uuidv7 ou ulid)", mode)
+	}
+}
+
+// Time instante de criação embutido no ID; só UUIDv7 (versão e variante
+// marcadas) — UUIDv4 e ULID retornam false
+func Time(id uuid.UUID) (time.Time, bool) {
+	if id.Version() != 7 || id.Variant() != uuid.RFC4122 {
+		return time.Time{}, false
+	}
+	var ts [8]byte
+	copy(ts[2:], id[:6])
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(ts[:]))), true
+}
+
+// UUIDv7 IDs ordenáveis pelo tempo no formato padrão (versão 7): o banco e
+// outras linguagens reconhecem o timestamp. Monotônico nesta instância: no
+// mesmo milissegundo os 12 bits rand_a funcionam como contador e, se
+// estourarem, o timestamp avança 1ms (permitido pela RFC 9562)
+type UUIDv7 struct {
+	mu    sync.Mutex
+	clock clock.Clock
+	lastT uint64
+	seq   uint16
+}
+
+// NewUUIDv7 cria gerador UUIDv7
+func NewUUIDv7(c clock.Clock) *UUIDv7 {
+	return &UUIDv7{clock: c}
+}
+
+// New implementa Generator
+func (g *UUIDv7) New() uuid.UUID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := uint64(g.clock.Now().UnixMilli())
+	if ms <= g.lastT {
+		// Mesmo milissegundo (ou relógio voltou): contador no último timestamp
+		ms = g.lastT
+		g.seq++
+		if g.seq > 0x0fff {
+			ms++
+			g.seq = 0
+		}
+	} else {
+		g.seq = 0
+	}
+	g.lastT = ms
+
+	var id uuid.UUID
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], ms)
+	copy(id[:6], ts[2:])
+	if _, err := rand.Read(id[8:]); err != nil {
+		panic(fmt.Sprintf("idgen: falha ao ler aleatoriedade: %v", err))
+	}
+	id[6] = 0x70 | byte(g.seq>>8) // versão 7 + 4 bits altos do contador
+	id[7] = byte(g.seq)
+	id[8] = 0x80 | id[8]&0x3f // variante RFC 4122/9562
+	return id
+}
+
+// ULID IDs ordenáveis pelo tempo de criação: inserts caem no fim do índice
+// em vez de espalhados pela B-tree. Monotônico dentro do mesmo milissegundo
+// (a parte aleatória é incrementada), então a ordem do ID segue a de geração
+// nesta instância. A string continua no formato UUID
+type ULID struct {
+	mu    sync.Mutex
+	clock clock.Clock
+	lastT uint64
+	last  uuid.UUID
+}
+
+// NewULID cria gerador ULID
+func NewULID(c clock.Clock) *ULID {
+	return &ULID{clock: c}
+}
+
+// New implementa Generator
+func (g *ULID) New() uuid.UUID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := uint64(g.clock.Now().UnixMilli())
+	if ms <= g.lastT {
+		// Mesmo milissegundo (ou relógio voltou): incrementa a parte aleatória
+		if increment(g.last[6:]) {
+			g.last = g.next(ms)
+		}
+		return g.last
+	}
+	g.last = g.next(ms)
+	return g.last
+}
+
+// next ID novo com o timestamp e 80 bits aleatórios
+func (g *ULID) next(ms uint64) uuid.UUID {
+	var id uuid.UUID
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], ms)
+	copy(id[:6], ts[2:])
+	if _, err := rand.Read(id[6:]); err != nil {
+		panic(fmt.Sprintf("idgen: falha ao ler aleatoriedade: %v", err))
+	}
+	if ms > g.lastT {
+		g.lastT = ms
+	}
+	return id
+}
+
+// increment soma 1 ao número big-endian; true se estourou
+func increment(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Sequence IDs 00000000-0000-0000-0000-000000000001, ...002 em ordem:
+// testes comparam a saída exata
+type Sequence struct {
+	mu sync.Mutex
+	n  uint64
+}
+
+// NewSequence cria gerador sequencial começando em 1
+func NewSequence() *Sequence {
+	return &Sequence{}
+}
+
+// New implementa Generator
+func (s *Sequence) New() uuid.UUID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.n++
+	var id uuid.UUID
+	binary.BigEndian.PutUint64(id[8:], s.n)
+	return id
+}
@@ -0,0 +1,145 @@
+// Package status estados de mensagens e amizades: tipos próprios em vez de
+// strings soltas (o banco restringe os mesmos valores com CHECK) e a tabela
+// única de transições permitidas, consultada antes de qualquer mudança
+package status
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknown valor fora do enum
+var ErrUnknown = errors.New("status desconhecido")
+
+// ErrInvalidTransition mudança de status não permitida (ex: read → accepted)
+var ErrInvalidTransition = errors.New("transição de status inválida")
+
+// ErrConflict a linha mudou entre a leitura e a escrita (concorrência otimista)
+var ErrConflict = errors.New("conflito de versão")
+
+// ConflictError escrita perdeu o compare-and-set: outra escrita gravou um
+// estado mais novo; Current é o status que venceu (o cliente relê e decide)
+type ConflictError struct {
+	Entity  string // "mensagem" ou "amizade"
+	ID      string
+	Version int32  // Versão lida antes da escrita
+	Current string // Status atual, quando conhecido
+}
+
+func (e *ConflictError) Error() string {
+	msg := fmt.Sprintf("%s: %s %s mudou desde a versão %d", ErrConflict, e.Entity, e.ID, e.Version)
+	if e.Current != "" {
+		msg += " (status atual: " + e.Current + ")"
+	}
+	return msg
+}
+
+// Is permite errors.Is(err, ErrConflict)
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// Message status da mensagem
+type Message string
+
+// Etapas da mensagem, iguais no HTTP (campo status), nos frames WebSocket e
+// no barramento; as três primeiras só avançam nessa ordem
+const (
+	MessageAccepted    Message = "accepted"    // Persistida pelo servidor (resposta do envio)
+	MessageDelivered   Message = "delivered"   // Chegou a um dispositivo do destinatário
+	MessageRead        Message = "read"        // Lida (só com confirmação de leitura)
+	MessageQuarantined Message = "quarantined" // Anexo infectado; estado final
+)
+
+// messageTransitions destinos permitidos a partir de cada status
+var messageTransitions = map[Message][]Message{
+	MessageAccepted:    {MessageDelivered, MessageRead, MessageQuarantined},
+	MessageDelivered:   {MessageRead, MessageQuarantined},
+	MessageRead:        {MessageQuarantined},
+	MessageQuarantined: nil,
+}
+
+// messageRank posição na sequência de entrega (quarantined fica fora)
+var messageRank = map[Message]int{
+	MessageAccepted:  1,
+	MessageDelivered: 2,
+	MessageRead:      3,
+}
+
+// ParseMessage valida status vindo de fora (query string, payload)
+func ParseMessage(s string) (Message, error) {
+	m := Message(s)
+	if !m.Valid() {
+		return "", fmt.Errorf("%w: %q", ErrUnknown, s)
+	}
+	return m, nil
+}
+
+// Valid indica se o valor pertence ao enum
+func (m Message) Valid() bool {
+	_, ok := messageTransitions[m]
+	return ok
+}
+
+// Reached indica se a mensagem já está em target ou depois dele na sequência
+// de entrega (ack repetido ou atrasado não é erro)
+func (m Message) Reached(target Message) bool {
+	rank, ok := messageRank[m]
+	return ok && rank >= messageRank[target] && messageRank[target] > 0
+}
+
+// Transition valida a mudança m → to
+func (m Message) Transition(to Message) error {
+	if !to.Valid() {
+		return fmt.Errorf("%w: %q", ErrUnknown, to)
+	}
+	for _, allowed := range messageTransitions[m] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: mensagem %s → %s", ErrInvalidTransition, m, to)
+}
+
+// Friendship status da amizade
+type Friendship string
+
+// Estados da amizade
+const (
+	FriendshipPending  Friendship = "pending"  // Solicitada, aguardando o destinatário
+	FriendshipAccepted Friendship = "accepted" // Aceita; estado final
+)
+
+// friendshipTransitions destinos permitidos a partir de cada status
+var friendshipTransitions = map[Friendship][]Friendship{
+	FriendshipPending:  {FriendshipAccepted},
+	FriendshipAccepted: nil,
+}
+
+// ParseFriendship valida status vindo de fora
+func ParseFriendship(s string) (Friendship, error) {
+	f := Friendship(s)
+	if !f.Valid() {
+		return "", fmt.Errorf("%w: %q", ErrUnknown, s)
+	}
+	return f, nil
+}
+
+// Valid indica se o valor pertence ao enum
+func (f Friendship) Valid() bool {
+	_, ok := friendshipTransitions[f]
+	return ok
+}
+
+// Transition valida a mudança f → to
+func (f Friendship) Transition(to Friendship) error {
+	if !to.Valid() {
+		return fmt.Errorf("%w: %q", ErrUnknown, to)
+	}
+	for _, allowed := range friendshipTransitions[f] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: amizade %s → %s", ErrInvalidTransition, f, to)
+}
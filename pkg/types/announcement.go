@@ -0,0 +1,32 @@
+package types
+
+// CreateAnnouncementInput anúncio do admin
+type CreateAnnouncementInput struct {
+	Content     string `json:"content"`
+	Plan        string `json:"plan,omitempty"`         // Só usuários do plano (vazio = todos)
+	ScheduledAt string `json:"scheduled_at,omitempty"` // RFC3339 (vazio = agora)
+	RequiresAck bool   `json:"requires_ack"`
+}
+
+// AnnouncementResponse anúncio com o andamento da entrega
+type AnnouncementResponse struct {
+	ID           string `json:"id"`
+	Content      string `json:"content"`
+	Plan         string `json:"plan,omitempty"`
+	RequiresAck  bool   `json:"requires_ack"`
+	ScheduledAt  string `json:"scheduled_at"`
+	Status       string `json:"status"`
+	Delivered    int    `json:"delivered"`
+	Acknowledged int    `json:"acknowledged"`
+	CreatedAt    string `json:"created_at"`
+	StartedAt    string `json:"started_at,omitempty"`
+	FinishedAt   string `json:"finished_at,omitempty"`
+}
+
+// PendingAnnouncementResponse anúncio entregue aguardando confirmação do usuário
+type PendingAnnouncementResponse struct {
+	ID          string `json:"id"`
+	MessageID   string `json:"message_id"`
+	Content     string `json:"content"`
+	DeliveredAt string `json:"delivered_at"`
+}
@@ -0,0 +1,21 @@
+package types
+
+// CreateAPIKeyInput dados para criar chave de API
+type CreateAPIKeyInput struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	UserID string   `json:"user_id,omitempty"` // Usuário (bot) em nome de quem a chave age
+}
+
+// APIKeyResponse chave de API (a chave completa só aparece na criação)
+type APIKeyResponse struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Key        string   `json:"key,omitempty"`
+	Scopes     []string `json:"scopes"`
+	UserID     string   `json:"user_id,omitempty"`
+	LastUsedAt string   `json:"last_used_at,omitempty"`
+	RevokedAt  string   `json:"revoked_at,omitempty"`
+	CreatedAt  string   `json:"created_at"`
+}
@@ -0,0 +1,65 @@
+package types
+
+import "time"
+
+// AttachmentResponse metadados do anexo (inclui o estado da varredura antivírus)
+type AttachmentResponse struct {
+	ID          string `json:"id"`
+	MessageID   string `json:"message_id,omitempty"`
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Status      string `json:"status"`      // pending ou uploaded
+	ScanStatus  string `json:"scan_status"` // pending, scanning, clean, infected, skipped ou error
+	ScanResult  string `json:"scan_result,omitempty"`
+	ScannedAt   string `json:"scanned_at,omitempty"`
+	CreatedAt   string `json:"created_at"`
+
+	// NSFW imagem sinalizada pelo classificador; com Blurred o download sem
+	// confirm_nsfw=true devolve apenas a prévia desfocada
+	NSFW       bool     `json:"nsfw,omitempty"`
+	NSFWLabels []string `json:"nsfw_labels,omitempty"`
+	Blurred    bool     `json:"blurred,omitempty"`
+
+	// Variants versões disponíveis para download (original, web, poster...)
+	Variants []string `json:"variants,omitempty"`
+}
+
+// CreateAttachmentInput dados para iniciar upload de anexo
+type CreateAttachmentInput struct {
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"` // Tamanho declarado em bytes
+}
+
+// AttachmentUploadResponse anexo criado + URL para envio do conteúdo
+type AttachmentUploadResponse struct {
+	Attachment AttachmentResponse `json:"attachment"`
+	UploadURL  string             `json:"upload_url"` // PUT com o conteúdo, depois POST .../finalize
+}
+
+// AttachmentInfectedAlert payload da notificação de anexo em quarentena
+type AttachmentInfectedAlert struct {
+	AttachmentID string `json:"attachment_id"`
+	MessageID    string `json:"message_id,omitempty"`
+	FileName     string `json:"file_name"`
+	Signature    string `json:"signature"`
+}
+
+// UploadSessionResponse estado de um upload retomável (tus)
+type UploadSessionResponse struct {
+	AttachmentID string
+	Offset       int64
+	Length       int64
+	ExpiresAt    time.Time
+
+	// Attachment preenchido quando o último bloco completa o upload
+	Attachment *AttachmentResponse
+}
+
+// DownloadURLResponse URL assinada e temporária para baixar o anexo
+type DownloadURLResponse struct {
+	URL       string `json:"url"`
+	ExpiresAt string `json:"expires_at"`
+	Blurred   bool   `json:"blurred,omitempty"` // URL aponta para a prévia desfocada
+}
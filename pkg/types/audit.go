@@ -0,0 +1,14 @@
+package types
+
+import "encoding/json"
+
+// AuditEventResponse evento do log de auditoria
+type AuditEventResponse struct {
+	ID        string          `json:"id"`
+	UserID    string          `json:"user_id,omitempty"`
+	Action    string          `json:"action"`
+	IP        string          `json:"ip"`
+	RiskScore int             `json:"risk_score"`
+	Metadata  json.RawMessage `json:"metadata"`
+	CreatedAt string          `json:"created_at"`
+}
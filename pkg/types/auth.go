@@ -8,39 +8,129 @@ type Claims struct {
 	Username string `json:"username"`
 	Email    string `json:"email"`
 	jwt.RegisteredClaims
+
+	// Impersonation presente = token do suporte agindo como o usuário
+	// (somente leitura, sem refresh)
+	Impersonation *ImpersonationClaim `json:"imp,omitempty"`
+}
+
+// ImpersonationClaim marca o token emitido pelo admin para o suporte
+type ImpersonationClaim struct {
+	SessionID string `json:"sid"`      // impersonation_sessions.id (revogável)
+	Operator  string `json:"operator"` // Quem do suporte está vendo
+	ReadOnly  bool   `json:"read_only"`
 }
 
 // TokenPair par de tokens (access + refresh)
 type TokenPair struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"` // Segundos até o access token expirar (JWT_ACCESS_TTL)
 }
 
 // AuthResponse resposta completa de autenticação
 type AuthResponse struct {
-	User   *UserResponse `json:"user"`
-	Tokens *TokenPair    `json:"tokens"`
+	User      *UserResponse           `json:"user,omitempty"`
+	Tokens    *TokenPair              `json:"tokens,omitempty"`
+	Challenge *LoginChallengeResponse `json:"challenge,omitempty"` // Login de risco: confirmar código
+
+	// Cadastro com EMAIL_VERIFICATION_REQUIRED=login: sem tokens até confirmar o email
+	VerificationRequired bool `json:"email_verification_required,omitempty"`
+}
+
+// LoginChallengeResponse confirmação exigida antes de emitir os tokens
+type LoginChallengeResponse struct {
+	ID        string `json:"id"`
+	Method    string `json:"method"` // email
+	ExpiresAt string `json:"expires_at"`
+}
+
+// VerifyLoginInput código recebido por email para concluir o login
+type VerifyLoginInput struct {
+	ChallengeID string `json:"challenge_id"`
+	Code        string `json:"code"`
 }
 
 // UserResponse dados públicos do usuário (sem password_hash)
 type UserResponse struct {
 	ID        string `json:"id"`
 	Username  string `json:"username"`
-	Email     string `json:"email"`
+	Email     string `json:"email,omitempty"`
 	CreatedAt string `json:"created_at"`
 }
 
 // RegisterInput dados necessários para registro
 type RegisterInput struct {
-	Username string `json:"username"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Username    string `json:"username"`
+	Email       string `json:"email"`
+	Password    string `json:"password"`
+	InviteToken string `json:"invite_token,omitempty"` // Convite (opcional)
+	DeviceID    string `json:"device_id,omitempty"`    // Identificador estável do app (opcional)
 }
 
 // LoginInput dados necessários para login
 type LoginInput struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	DeviceID string `json:"device_id,omitempty"` // Identificador estável do app (opcional)
+}
+
+// ClientInfo origem da requisição de login/registro
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+	Country   string // Preenchido pela geolocalização
+	City      string
+}
+
+// NotMeInput token do alerta de login ("não fui eu")
+type NotMeInput struct {
+	Token string `json:"token"`
+}
+
+// VerifyEmailInput token do link de confirmação de email
+type VerifyEmailInput struct {
+	Token string `json:"token"`
+}
+
+// ResendVerificationInput email que deve receber um novo link
+type ResendVerificationInput struct {
+	Email string `json:"email"`
+}
+
+// ForgotPasswordInput email que deve receber o link de redefinição
+type ForgotPasswordInput struct {
+	Email string `json:"email"`
+}
+
+// ResetPasswordInput token do link de redefinição e nova senha
+type ResetPasswordInput struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// IntrospectInput token apresentado a um serviço (RFC 7662); o hint
+// (access_token ou refresh_token) só muda a ordem das tentativas
+type IntrospectInput struct {
+	Token         string `json:"token"`
+	TokenTypeHint string `json:"token_type_hint"`
+}
+
+// IntrospectionResponse estado do token (RFC 7662 §2.2); inativo traz só active=false
+type IntrospectionResponse struct {
+	Active    bool     `json:"active"`
+	TokenType string   `json:"token_type,omitempty"` // access_token ou refresh_token
+	Subject   string   `json:"sub,omitempty"`
+	Username  string   `json:"username,omitempty"`
+	Email     string   `json:"email,omitempty"`
+	Issuer    string   `json:"iss,omitempty"`
+	Audience  []string `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"` // Unix (segundos)
+	IssuedAt  int64    `json:"iat,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	JTI       string   `json:"jti,omitempty"`
+
+	Impersonation *ImpersonationClaim `json:"imp,omitempty"` // Token de suporte
 }
 
 // RefreshTokenInput dados para refresh
@@ -0,0 +1,24 @@
+package types
+
+import "encoding/json"
+
+// BatchInput corpo de POST /batch
+type BatchInput struct {
+	Requests []BatchRequest `json:"requests"`
+}
+
+// BatchRequest sub-requisição (só GET); herda a autenticação do lote
+type BatchRequest struct {
+	ID      string            `json:"id,omitempty"` // Devolvido no resultado para correlação
+	Method  string            `json:"method,omitempty"`
+	Path    string            `json:"path"`              // Com query, ex.: /users/{id}/presence
+	Headers map[string]string `json:"headers,omitempty"` // Ex.: If-None-Match por item
+}
+
+// BatchResult resultado de um item, na mesma posição da requisição
+type BatchResult struct {
+	ID      string            `json:"id,omitempty"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"` // Corpo JSON da rota (sucesso ou erro)
+}
@@ -20,6 +20,9 @@ type PaginationMeta struct {
 	PerPage    int `json:"per_page"`
 	Total      int `json:"total"`
 	TotalPages int `json:"total_pages"`
+
+	// NextCursor valor de ?before= para a próxima página (vazio = fim)
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // PaginatedResponse resposta com paginação
@@ -0,0 +1,20 @@
+package types
+
+// PlaceLegalHoldInput retenção legal de um usuário ou, com PeerID, só da
+// conversa entre os dois
+type PlaceLegalHoldInput struct {
+	UserID string `json:"user_id"`
+	PeerID string `json:"peer_id,omitempty"`
+	Reason string `json:"reason"` // Referência do processo/pedido (obrigatória)
+}
+
+// LegalHoldResponse retenção legal (ativa enquanto ReleasedAt vazio)
+type LegalHoldResponse struct {
+	ID         string `json:"id"`
+	UserID     string `json:"user_id"`
+	PeerID     string `json:"peer_id,omitempty"`
+	Reason     string `json:"reason"`
+	Active     bool   `json:"active"`
+	ReleasedAt string `json:"released_at,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
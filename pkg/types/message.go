@@ -1,13 +1,45 @@
 package types
 
+import "chat-kafka-go/pkg/status"
+
 // MessageResponse resposta de mensagem
 type MessageResponse struct {
-	ID         string `json:"id"`
-	SenderID   string `json:"sender_id"`
-	ReceiverID string `json:"receiver_id"`
-	Content    string `json:"content"`
-	Status     string `json:"status"`
-	CreatedAt  string `json:"created_at"`
+	ID         string         `json:"id"`
+	SenderID   string         `json:"sender_id"`
+	ReceiverID string         `json:"receiver_id"`
+	Content    string         `json:"content"`
+	Status     status.Message `json:"status"`
+	CreatedAt  string         `json:"created_at"`
+
+	// Horário do servidor em cada etapa (vazio = ainda não alcançada)
+	AcceptedAt  string `json:"accepted_at"`
+	DeliveredAt string `json:"delivered_at,omitempty"`
+	ReadAt      string `json:"read_at,omitempty"`
+
+	// ClientSentAt horário do dispositivo no envio (informativo: a ordem é a
+	// do servidor); ClockSkewSeconds vem no envio quando a diferença passa do
+	// limite (cliente < 0 = atrasado), para o app avisar ou corrigir o relógio
+	ClientSentAt     string `json:"client_sent_at,omitempty"`
+	ClockSkewSeconds int64  `json:"clock_skew_seconds,omitempty"`
+
+	// SenderDeleted indica remetente removido (cliente exibe "usuário removido")
+	SenderDeleted bool `json:"sender_deleted,omitempty"`
+
+	Attachments []AttachmentResponse `json:"attachments,omitempty"`
+}
+
+// MessageStatusResponse etapa atual da mensagem (POST /messages/{id}/delivered
+// e /read); mesmos nomes do frame e do evento "message.status"
+type MessageStatusResponse struct {
+	MessageID   string         `json:"message_id"`
+	Status      status.Message `json:"status"`
+	AcceptedAt  string         `json:"accepted_at"`
+	DeliveredAt string         `json:"delivered_at,omitempty"`
+	ReadAt      string         `json:"read_at,omitempty"`
+
+	// Version cresce a cada mudança: frame "message.status" com versão menor
+	// que a já exibida chegou atrasado e deve ser descartado
+	Version int32 `json:"version"`
 }
 
 // SendMessageInput dados para enviar mensagem
@@ -15,6 +47,23 @@ type SendMessageInput struct {
 	SenderID   string `json:"sender_id"`
 	ReceiverID string `json:"receiver_id"`
 	Content    string `json:"content"`
+
+	// AttachmentID anexo já finalizado pelo remetente (opcional)
+	AttachmentID string `json:"attachment_id,omitempty"`
+
+	// ClientSentAt horário do dispositivo (RFC 3339, opcional)
+	ClientSentAt string `json:"client_sent_at,omitempty"`
+
+	// ViaAPIKey enviada com chave de API (bot): cota diária própria
+	ViaAPIKey bool `json:"-"`
+
+	// System enviada pelo próprio serviço (resposta do suporte): ignora a
+	// privacidade do destinatário e a cota do remetente
+	System bool `json:"-"`
+
+	// AutoReply resposta automática do destinatário original: não consome
+	// cota e não dispara outra resposta automática
+	AutoReply bool `json:"-"`
 }
 
 // ListMessagesInput dados para listar mensagens
@@ -23,4 +72,56 @@ type ListMessagesInput struct {
 	FriendID string `json:"friend_id"`
 	Page     int    `json:"page"`
 	PerPage  int    `json:"per_page"`
+
+	// Before ID da última mensagem da página anterior (meta.next_cursor):
+	// paginação por cursor, estável com mensagens novas chegando. Ignora Page
+	Before string `json:"before,omitempty"`
+}
+
+// ConversationResponse item da lista de conversas
+type ConversationResponse struct {
+	PeerID             string `json:"peer_id"`
+	LastMessageID      string `json:"last_message_id"`
+	LastMessagePreview string `json:"last_message_preview"`
+	LastMessageAt      string `json:"last_message_at"`
+	UnreadCount        int    `json:"unread_count"`
+	LastReadMessageID  string `json:"last_read_message_id,omitempty"`
+}
+
+// ConversationMember participante de uma conversa direta; Online vem vazio
+// quando o participante não compartilha presença com quem consulta
+type ConversationMember struct {
+	UserResponse
+	Role   string `json:"role"` // Conversas diretas: sempre member
+	Online *bool  `json:"online,omitempty"`
+}
+
+// ConversationMembersResponse participantes da conversa (ID = ID do par)
+type ConversationMembersResponse struct {
+	ConversationID string               `json:"conversation_id"`
+	Members        []ConversationMember `json:"members"`
+}
+
+// CreateShareLinkInput dados para criar link somente leitura da conversa
+type CreateShareLinkInput struct {
+	OwnerID   string `json:"-"`
+	PeerID    string `json:"-"`
+	ExpiresIn string `json:"expires_in,omitempty"` // Duração (ex.: 24h); vazio = SHARE_LINK_TTL
+}
+
+// ShareLinkResponse link de leitura; Token e Link só na criação
+type ShareLinkResponse struct {
+	ID        string `json:"id"`
+	PeerID    string `json:"peer_id"`
+	Token     string `json:"token,omitempty"`
+	Link      string `json:"link,omitempty"`
+	ExpiresAt string `json:"expires_at"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ListConversationsInput dados para listar conversas
+type ListConversationsInput struct {
+	UserID  string `json:"user_id"`
+	Page    int    `json:"page"`
+	PerPage int    `json:"per_page"`
 }
@@ -0,0 +1,24 @@
+package types
+
+import "encoding/json"
+
+// NotificationResponse notificação in-app
+type NotificationResponse struct {
+	ID        string          `json:"id"`
+	Kind      string          `json:"kind"`
+	Payload   json.RawMessage `json:"payload"`
+	Read      bool            `json:"read"`
+	CreatedAt string          `json:"created_at"`
+}
+
+// NewLoginAlert payload da notificação de login em novo dispositivo/local
+type NewLoginAlert struct {
+	IP          string `json:"ip"`
+	Country     string `json:"country,omitempty"`
+	City        string `json:"city,omitempty"`
+	UserAgent   string `json:"user_agent"`
+	NewDevice   bool   `json:"new_device"`
+	NewLocation bool   `json:"new_location"`
+	NotMeURL    string `json:"not_me_url"`
+	LoggedInAt  string `json:"logged_in_at"`
+}
@@ -0,0 +1,25 @@
+package types
+
+// Entitlements limites do plano (0 = sem limite)
+type Entitlements struct {
+	DailyMessages      int   `json:"daily_messages"`
+	BotDailyMessages   int   `json:"bot_daily_messages"` // Enviadas com chave de API
+	StorageBytes       int64 `json:"storage_bytes"`
+	MaxAttachmentBytes int64 `json:"max_attachment_bytes"`
+	HistoryDays        int   `json:"history_days"`      // Histórico visível
+	MaxGroupMembers    int   `json:"max_group_members"` // Para quando houver grupos
+}
+
+// PlanResponse plano efetivo do usuário
+type PlanResponse struct {
+	Plan         string       `json:"plan"`
+	Status       string       `json:"status,omitempty"`     // Da assinatura ou manual (vazio = padrão)
+	PeriodEnd    string       `json:"period_end,omitempty"` // RFC3339
+	Entitlements Entitlements `json:"entitlements"`
+}
+
+// SetPlanInput plano atribuído pelo admin (sem cobrança)
+type SetPlanInput struct {
+	Plan  string `json:"plan"`
+	Until string `json:"until,omitempty"` // RFC3339; vazio = sem vencimento
+}
@@ -0,0 +1,44 @@
+package types
+
+// SearchInput dados da busca global
+// Cada tipo tem sua própria página; Types vazio = todos
+type SearchInput struct {
+	UserID  string         `json:"user_id"`
+	Query   string         `json:"query"`
+	Types   []string       `json:"types"`
+	Pages   map[string]int `json:"pages"`
+	PerPage int            `json:"per_page"`
+}
+
+// SearchResponse resultado da busca global (só os tipos pedidos aparecem)
+type SearchResponse struct {
+	Query         string         `json:"query"`
+	Users         *SearchSection `json:"users,omitempty"`
+	Conversations *SearchSection `json:"conversations,omitempty"`
+	Messages      *SearchSection `json:"messages,omitempty"`
+}
+
+// SearchSection página de resultados de um tipo
+type SearchSection struct {
+	Items   interface{} `json:"items"`
+	Page    int         `json:"page"`
+	PerPage int         `json:"per_page"`
+	HasMore bool        `json:"has_more"`
+}
+
+// SearchUserResult usuário encontrado (sem email: a busca alcança desconhecidos)
+type SearchUserResult struct {
+	ID        string `json:"id"`
+	Username  string `json:"username"`
+	IsFriend  bool   `json:"is_friend"`
+	CreatedAt string `json:"created_at"`
+}
+
+// SearchConversationResult conversa cujo nome (username do par) casou
+type SearchConversationResult struct {
+	PeerID             string `json:"peer_id"`
+	PeerUsername       string `json:"peer_username"`
+	LastMessagePreview string `json:"last_message_preview"`
+	LastMessageAt      string `json:"last_message_at"`
+	UnreadCount        int    `json:"unread_count"`
+}
@@ -0,0 +1,96 @@
+package types
+
+// OpenSupportTicketInput abertura de chamado pelo cliente (primeira mensagem)
+type OpenSupportTicketInput struct {
+	CustomerID string `json:"-"`
+	Subject    string `json:"subject"`
+	Content    string `json:"content"`
+}
+
+// SupportTicketFilter filtros da fila dos agentes
+type SupportTicketFilter struct {
+	Status   string // open, pending, closed (vazio = todos)
+	Assigned string // me (do agente), none (na fila) ou vazio (todos)
+	Page     int
+	PerPage  int
+}
+
+// SupportSLA prazos do chamado e se foram estourados
+type SupportSLA struct {
+	FirstResponseDueAt    string `json:"first_response_due_at"`
+	ResolutionDueAt       string `json:"resolution_due_at"`
+	FirstResponseBreached bool   `json:"first_response_breached"`
+	ResolutionBreached    bool   `json:"resolution_breached"`
+}
+
+// SupportTicketResponse chamado; AgentID não aparece para o cliente
+type SupportTicketResponse struct {
+	ID              string     `json:"id"`
+	CustomerID      string     `json:"customer_id"`
+	AgentID         string     `json:"agent_id,omitempty"`
+	Subject         string     `json:"subject"`
+	Status          string     `json:"status"`
+	SLA             SupportSLA `json:"sla"`
+	FirstResponseAt string     `json:"first_response_at,omitempty"`
+	ClosedAt        string     `json:"closed_at,omitempty"`
+	CreatedAt       string     `json:"created_at"`
+	UpdatedAt       string     `json:"updated_at"`
+
+	// Notes notas internas (só na visão do agente)
+	Notes []SupportNoteResponse `json:"notes,omitempty"`
+}
+
+// SupportNoteResponse nota interna de agente
+type SupportNoteResponse struct {
+	ID        string `json:"id"`
+	AuthorID  string `json:"author_id"`
+	Content   string `json:"content"`
+	CreatedAt string `json:"created_at"`
+}
+
+// SupportAgentResponse usuário com papel de agente
+type SupportAgentResponse struct {
+	UserID    string `json:"user_id"`
+	Username  string `json:"username"`
+	CreatedAt string `json:"created_at"`
+}
+
+// GrantSupportAccessInput usuário autoriza o suporte a ver suas conversas
+// (somente leitura)
+type GrantSupportAccessInput struct {
+	ExpiresIn string `json:"expires_in,omitempty"` // Duração (ex.: 24h); vazio = SUPPORT_ACCESS_MAX_TTL
+}
+
+// SupportAccessResponse autorização atual e os acessos do suporte (transparência)
+type SupportAccessResponse struct {
+	Active    bool                           `json:"active"`
+	ExpiresAt string                         `json:"expires_at,omitempty"`
+	Sessions  []ImpersonationSessionResponse `json:"sessions"`
+}
+
+// StartImpersonationInput admin emite token de suporte para um usuário
+type StartImpersonationInput struct {
+	Operator  string `json:"operator"`             // Quem do suporte vai usar o token
+	Reason    string `json:"reason"`               // Chamado/motivo (obrigatório)
+	ExpiresIn string `json:"expires_in,omitempty"` // Duração; vazio = SUPPORT_IMPERSONATION_MAX_TTL
+}
+
+// ImpersonationResponse token de suporte recém-emitido (exibido uma vez)
+type ImpersonationResponse struct {
+	Session     ImpersonationSessionResponse `json:"session"`
+	AccessToken string                       `json:"access_token"`
+	ExpiresIn   int64                        `json:"expires_in"` // Segundos
+}
+
+// ImpersonationSessionResponse sessão de suporte (ativa enquanto não
+// revogada nem expirada)
+type ImpersonationSessionResponse struct {
+	ID        string `json:"id"`
+	UserID    string `json:"user_id"`
+	Operator  string `json:"operator"`
+	Reason    string `json:"reason"`
+	Active    bool   `json:"active"`
+	ExpiresAt string `json:"expires_at"`
+	RevokedAt string `json:"revoked_at,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
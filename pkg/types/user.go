@@ -1,5 +1,7 @@
 package types
 
+import "encoding/json"
+
 // ListUsersInput parâmetros para listar usuários
 type ListUsersInput struct {
 	Page    int // Página atual (1, 2, 3...)
@@ -17,3 +19,157 @@ type AcceptFriendInput struct {
 	UserID   string // Quem está aceitando
 	FriendID string // Quem enviou a solicitação
 }
+
+// UserLookupResponse usuários buscados em lote (GET /users?ids=...)
+type UserLookupResponse struct {
+	Users   []UserResponse `json:"users"`   // Na ordem dos IDs pedidos
+	Missing []string       `json:"missing"` // Inexistentes ou excluídos
+}
+
+// ChangeUsernameInput dados para trocar username
+type ChangeUsernameInput struct {
+	UserID      string `json:"-"`
+	NewUsername string `json:"username"`
+}
+
+// PrivacySettingsResponse configurações de privacidade do usuário
+type PrivacySettingsResponse struct {
+	MessagePolicy      string `json:"message_policy"`      // everyone | friends
+	PresenceVisibility string `json:"presence_visibility"` // everyone | friends | nobody
+	ShareReadReceipts  bool   `json:"share_read_receipts"`
+	UpdatedAt          string `json:"updated_at,omitempty"`
+}
+
+// PresenceResponse presença de um usuário
+type PresenceResponse struct {
+	UserID string `json:"user_id"`
+	Online bool   `json:"online"`
+}
+
+// UsageResponse uso do usuário e cotas (limit 0 = sem limite)
+type UsageResponse struct {
+	Plan     string     `json:"plan"`
+	Day      string     `json:"day"`       // Dia da contagem de mensagens (UTC, YYYY-MM-DD)
+	ResetsAt string     `json:"resets_at"` // Quando a contagem de mensagens zera
+	Messages QuotaUsage `json:"messages"`
+	Storage  QuotaUsage `json:"storage_bytes"`
+}
+
+// QuotaUsage uso e limite de uma cota
+type QuotaUsage struct {
+	Used  int64 `json:"used"`
+	Limit int64 `json:"limit"`
+}
+
+// UpdatePrivacySettingsInput dados para atualizar privacidade
+type UpdatePrivacySettingsInput struct {
+	UserID             string `json:"-"`
+	MessagePolicy      string `json:"message_policy"`
+	PresenceVisibility string `json:"presence_visibility"`
+	ShareReadReceipts  bool   `json:"share_read_receipts"`
+}
+
+// DNDWindow janela de silêncio semanal no fuso do usuário
+// Se End <= Start a janela atravessa a meia-noite (ex: 22:00 -> 07:00)
+type DNDWindow struct {
+	Weekday int    `json:"weekday"` // 0 = domingo ... 6 = sábado
+	Start   string `json:"start"`   // HH:MM
+	End     string `json:"end"`     // HH:MM
+}
+
+// DNDSettingsResponse configurações de não perturbe
+type DNDSettingsResponse struct {
+	Timezone     string      `json:"timezone"`
+	Windows      []DNDWindow `json:"windows"`
+	SnoozedUntil string      `json:"snoozed_until,omitempty"`
+}
+
+// UpdateDNDScheduleInput dados para atualizar janelas de não perturbe
+type UpdateDNDScheduleInput struct {
+	UserID   string      `json:"-"`
+	Timezone string      `json:"timezone"`
+	Windows  []DNDWindow `json:"windows"`
+}
+
+// SnoozeInput silencia notificações por N horas (0 = cancela)
+type SnoozeInput struct {
+	UserID string `json:"-"`
+	Hours  int    `json:"hours"`
+}
+
+// AutoReplySettingsResponse resposta automática (férias/ausência)
+type AutoReplySettingsResponse struct {
+	Message     string `json:"message"`
+	StartsAt    string `json:"starts_at,omitempty"` // RFC 3339; vazio = desde a configuração
+	EndsAt      string `json:"ends_at,omitempty"`   // RFC 3339; vazio = até desligar
+	FriendsOnly bool   `json:"friends_only"`
+	Active      bool   `json:"active"` // Dentro da janela agora
+	UpdatedAt   string `json:"updated_at"`
+}
+
+// UpdateAutoReplyInput dados para ligar/atualizar a resposta automática
+type UpdateAutoReplyInput struct {
+	UserID      string `json:"-"`
+	Message     string `json:"message"`
+	StartsAt    string `json:"starts_at,omitempty"` // RFC 3339 (opcional)
+	EndsAt      string `json:"ends_at,omitempty"`   // RFC 3339 (opcional)
+	FriendsOnly bool   `json:"friends_only"`
+}
+
+// PreferencesResponse preferências de interface (padrões + escolhas do usuário)
+type PreferencesResponse struct {
+	Preferences map[string]json.RawMessage `json:"preferences"`
+	Version     int32                      `json:"version"` // 0 = nunca alteradas
+	UpdatedAt   string                     `json:"updated_at,omitempty"`
+}
+
+// UpdatePreferencesInput mescla chaves nas preferências; valor null volta ao padrão
+type UpdatePreferencesInput struct {
+	UserID      string                     `json:"-"`
+	Preferences map[string]json.RawMessage `json:"preferences"`
+
+	// Version versão lida pelo cliente (opcional): se outra escrita venceu,
+	// o service responde conflito em vez de mesclar por cima
+	Version *int32 `json:"version,omitempty"`
+}
+
+// ContactSyncInput hashes SHA-256 (hex) dos emails da agenda do cliente
+type ContactSyncInput struct {
+	UserID      string   `json:"-"`
+	EmailHashes []string `json:"email_hashes"`
+}
+
+// ContactMatch contato da agenda que já está na plataforma
+type ContactMatch struct {
+	Kind     string `json:"kind"` // email
+	Hash     string `json:"hash"` // Como enviado pelo cliente (minúsculo)
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// CreateInvitationInput dados para criar convite (sem email = convite por link)
+type CreateInvitationInput struct {
+	InviterID string `json:"-"`
+	Email     string `json:"email,omitempty"`
+}
+
+// InvitationResponse convite criado (token só é exibido na criação)
+type InvitationResponse struct {
+	ID        string `json:"id"`
+	Email     string `json:"email,omitempty"`
+	Link      string `json:"link"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// ReferralStats estatísticas de indicação do usuário
+type ReferralStats struct {
+	InvitationsSent   int `json:"invitations_sent"`
+	ReferralsAccepted int `json:"referrals_accepted"`
+}
+
+// ProfileResponse perfil do usuário autenticado
+type ProfileResponse struct {
+	UserResponse
+	EmailVerified bool          `json:"email_verified"`
+	Referrals     ReferralStats `json:"referrals"`
+}
@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"net"
+	"net/http"
+)
+
+// ClientIP retorna o IP de origem da requisição (sem a porta)
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// DeviceFingerprint identifica o dispositivo: device_id enviado pelo app
+// ou, na falta dele, o user agent
+func DeviceFingerprint(deviceID, userAgent string) string {
+	if deviceID != "" {
+		return HashToken("device:" + deviceID)
+	}
+	return HashToken("ua:" + userAgent)
+}
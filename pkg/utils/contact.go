@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// ContactKindEmail único tipo de contato aceito na sincronização
+const ContactKindEmail = "email"
+
+// HashEmail SHA-256 (hex minúsculo) do email normalizado (trim + minúsculas):
+// formato que os clientes enviam na sincronização
+func HashEmail(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(sum[:])
+}
+
+// KeyContactHash HMAC-SHA256 (hex) do hash enviado pelo cliente com o pepper
+// do servidor: é o que fica em contact_hashes, então a tabela sozinha não
+// permite confirmar emails por força bruta
+func KeyContactHash(pepper, clientHash string) string {
+	mac := hmac.New(sha256.New, []byte(pepper))
+	mac.Write([]byte(strings.ToLower(clientHash)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
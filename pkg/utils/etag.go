@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"chat-kafka-go/pkg/types"
+)
+
+// ETag validador fraco (W/"...") a partir das partes informadas: o corpo
+// pode mudar de bytes (compressão, ordem de campos) sem mudar o recurso
+func ETag(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return `W/"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// NotModified define ETag e Cache-Control (o cliente sempre revalida) e
+// responde 304 quando If-None-Match casa; true = resposta já enviada
+func NotModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	if !etagMatch(r.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// SuccessWithETag como Success, com ETag do próprio corpo: poupa a banda
+// (304 sem corpo), não a consulta; recursos com versão barata usam NotModified
+func SuccessWithETag(w http.ResponseWriter, r *http.Request, statusCode int, data interface{}, message string) {
+	body, err := json.Marshal(types.SuccessResponse{
+		Success: true,
+		Data:    data,
+		Message: message,
+	})
+	if err != nil {
+		Success(w, statusCode, data, message)
+		return
+	}
+	if NotModified(w, r, ETag(string(body))) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(append(body, '\n'))
+}
+
+// etagMatch comparação fraca (RFC 9110 13.1.2) contra a lista de If-None-Match
+func etagMatch(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
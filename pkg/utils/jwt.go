@@ -2,6 +2,7 @@ package utils
 
 import (
 	"chat-kafka-go/pkg/types"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,16 +10,27 @@ import (
 	"github.com/google/uuid"
 )
 
-// GenerateAccessToken cria um token de acesso (1 hora por padrão)
-func GenerateAccessToken(userID, username, email, secret string, duration time.Duration) (string, error) {
+// Falhas de validação distinguíveis pelo cliente: expirado pede refresh,
+// inválido pede novo login
+var (
+	ErrTokenExpired = errors.New("token expirado")
+	ErrTokenInvalid = errors.New("token inválido")
+)
+
+// GenerateAccessToken cria um token de acesso válido por duration (JWT_ACCESS_TTL)
+// issuer/audience identificam o ambiente e o serviço para o qual o token foi emitido
+// now é a hora de emissão (relógio do serviço)
+func GenerateAccessToken(userID, username, email, secret, issuer, audience string, duration time.Duration, now time.Time) (string, error) {
 	claims := &types.Claims{
 		UserID:   userID,
 		Username: username,
 		Email:    email,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			Issuer:    issuer,
+			Audience:  audienceClaim(audience),
+			ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 			ID:        uuid.New().String(), // jti - JWT ID (pode ser usado para revogação)
 		},
 	}
@@ -27,12 +39,35 @@ func GenerateAccessToken(userID, username, email, secret string, duration time.D
 	return token.SignedString([]byte(secret))
 }
 
-// GenerateRefreshToken cria um token de refresh (7 dias por padrão)
-func GenerateRefreshToken(userID, secret string, duration time.Duration) (string, error) {
+// GenerateImpersonationToken access token do suporte agindo como o usuário:
+// mesmos claims e validação, marcado com imp (o middleware restringe a
+// leitura e confere se a sessão continua ativa)
+func GenerateImpersonationToken(userID, username, email string, imp types.ImpersonationClaim, secret, issuer, audience string, duration time.Duration, now time.Time) (string, error) {
+	claims := &types.Claims{
+		UserID:        userID,
+		Username:      username,
+		Email:         email,
+		Impersonation: &imp,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Audience:  audienceClaim(audience),
+			ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ID:        imp.SessionID,
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(secret))
+}
+
+// GenerateRefreshToken cria um token de refresh válido por duration (JWT_REFRESH_TTL)
+func GenerateRefreshToken(userID, secret string, duration time.Duration, now time.Time) (string, error) {
 	claims := &jwt.RegisteredClaims{
 		Subject:   userID, // sub - Subject (ID do usuário)
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
+		IssuedAt:  jwt.NewNumericDate(now),
 		ID:        uuid.New().String(),
 	}
 
@@ -41,42 +76,72 @@ func GenerateRefreshToken(userID, secret string, duration time.Duration) (string
 }
 
 // ValidateAccessToken valida um access token e retorna os claims
-func ValidateAccessToken(tokenString, secret string) (*types.Claims, error) {
+// iss e aud são obrigatórios e precisam bater (quando configurados), impedindo
+// replay de tokens emitidos para outros ambientes ou serviços
+// exp/nbf são conferidos contra now com tolerância leeway (relógios das
+// instâncias fora de sincronia); erro envolve ErrTokenExpired ou ErrTokenInvalid
+func ValidateAccessToken(tokenString, secret, issuer, audience string, leeway time.Duration, now time.Time) (*types.Claims, error) {
+	opts := []jwt.ParserOption{jwt.WithTimeFunc(func() time.Time { return now }), jwt.WithLeeway(leeway)}
+	if issuer != "" {
+		opts = append(opts, jwt.WithIssuer(issuer))
+	}
+	if audience != "" {
+		opts = append(opts, jwt.WithAudience(audience))
+	}
+
 	token, err := jwt.ParseWithClaims(tokenString, &types.Claims{}, func(token *jwt.Token) (interface{}, error) {
 		// Verificar se o método de assinatura é HMAC
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("método de assinatura inesperado: %v", token.Header["alg"])
 		}
 		return []byte(secret), nil
-	})
+	}, opts...)
 
 	if err != nil {
-		return nil, fmt.Errorf("erro ao parsear token: %w", err)
+		return nil, classifyTokenError(err)
 	}
 
 	if claims, ok := token.Claims.(*types.Claims); ok && token.Valid {
 		return claims, nil
 	}
 
-	return nil, fmt.Errorf("token inválido")
+	return nil, ErrTokenInvalid
 }
 
 // ValidateRefreshToken valida um refresh token e retorna o userID
-func ValidateRefreshToken(tokenString, secret string) (string, error) {
+// (mesma tolerância e erros de ValidateAccessToken)
+func ValidateRefreshToken(tokenString, secret string, leeway time.Duration, now time.Time) (string, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("método de assinatura inesperado: %v", token.Header["alg"])
 		}
 		return []byte(secret), nil
-	})
+	}, jwt.WithTimeFunc(func() time.Time { return now }), jwt.WithLeeway(leeway))
 
 	if err != nil {
-		return "", fmt.Errorf("erro ao parsear refresh token: %w", err)
+		return "", classifyTokenError(err)
 	}
 
 	if claims, ok := token.Claims.(*jwt.RegisteredClaims); ok && token.Valid {
 		return claims.Subject, nil // Retorna o userID
 	}
 
-	return "", fmt.Errorf("refresh token inválido")
+	return "", ErrTokenInvalid
+}
+
+// classifyTokenError separa expiração (mesmo além da tolerância) dos demais
+// problemas: assinatura, formato, iss/aud, nbf no futuro
+func classifyTokenError(err error) error {
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return fmt.Errorf("%w: %v", ErrTokenExpired, err)
+	}
+	return fmt.Errorf("%w: %v", ErrTokenInvalid, err)
+}
+
+// audienceClaim omite aud quando não configurado
+func audienceClaim(audience string) jwt.ClaimStrings {
+	if audience == "" {
+		return nil
+	}
+	return jwt.ClaimStrings{audience}
 }
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"chat-kafka-go/pkg/clock"
+)
+
+const (
+	testSecret   = "access-secret-de-teste-com-32-bytes!"
+	testIssuer   = "chat-kafka-go"
+	testAudience = "chat-kafka-go-api"
+)
+
+func BenchmarkValidateAccessToken(b *testing.B) {
+	now := time.Now()
+	token, err := GenerateAccessToken("8f14e45f-ceea-4e67-a5c9-7b1a2d3e4f50", "maria", "maria@example.com",
+		testSecret, testIssuer, testAudience, time.Hour, now)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ValidateAccessToken(token, testSecret, testIssuer, testAudience, 30*time.Second, now); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestAccessTokenExpiry(t *testing.T) {
+	const (
+		ttl    = 15 * time.Minute
+		leeway = 30 * time.Second
+	)
+	manual := clock.NewManual(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	token, err := GenerateAccessToken("8f14e45f-ceea-4e67-a5c9-7b1a2d3e4f50", "maria", "maria@example.com",
+		testSecret, testIssuer, testAudience, ttl, manual.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		advance time.Duration
+		wantErr error
+	}{
+		{"válido", ttl - time.Second, nil},
+		{"dentro da tolerância", ttl + leeway - time.Second, nil},
+		{"expirado", ttl + leeway + time.Second, ErrTokenExpired},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ValidateAccessToken(token, testSecret, testIssuer, testAudience, leeway, manual.Now().Add(tc.advance))
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v; esperado %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestRefreshTokenExpiry(t *testing.T) {
+	const ttl = 7 * 24 * time.Hour
+	manual := clock.NewManual(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	token, err := GenerateRefreshToken("8f14e45f-ceea-4e67-a5c9-7b1a2d3e4f50", testSecret, ttl, manual.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manual.Advance(ttl - time.Minute)
+	if _, err := ValidateRefreshToken(token, testSecret, 0, manual.Now()); err != nil {
+		t.Fatalf("antes do TTL: %v", err)
+	}
+	manual.Advance(2 * time.Minute)
+	if _, err := ValidateRefreshToken(token, testSecret, 0, manual.Now()); !errors.Is(err, ErrTokenExpired) {
+		t.Fatalf("depois do TTL: err = %v; esperado %v", err, ErrTokenExpired)
+	}
+}
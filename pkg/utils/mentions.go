@@ -0,0 +1,21 @@
+package utils
+
+import "regexp"
+
+// mentionRegex @username (mesmos caracteres aceitos no username)
+var mentionRegex = regexp.MustCompile(`(?:^|[^\w@])@([A-Za-z0-9_.]{3,50})`)
+
+// ExtractMentions retorna usernames mencionados (sem @ e sem repetição)
+func ExtractMentions(content string) []string {
+	matches := mentionRegex.FindAllStringSubmatch(content, -1)
+
+	seen := make(map[string]bool, len(matches))
+	mentions := make([]string, 0, len(matches))
+	for _, m := range matches {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			mentions = append(mentions, m[1])
+		}
+	}
+	return mentions
+}
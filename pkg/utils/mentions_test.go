@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var mentionName = regexp.MustCompile(`^[A-Za-z0-9_.]{3,50}$`)
+
+func FuzzExtractMentions(f *testing.F) {
+	for _, seed := range []string{
+		"oi @maria, tudo bem?",
+		"@joao.silva @joao.silva @ana_b",
+		"email@exemplo.com não é menção",
+		"@@duplo @ab @" + strings.Repeat("x", 60),
+		"\u202E@invertido\u202C e @fim",
+		"",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, content string) {
+		mentions := ExtractMentions(content)
+
+		seen := make(map[string]bool, len(mentions))
+		for _, m := range mentions {
+			if !mentionName.MatchString(m) {
+				t.Fatalf("menção inválida %q em %q", m, content)
+			}
+			if !strings.Contains(content, "@"+m) {
+				t.Fatalf("menção %q não está no conteúdo %q", m, content)
+			}
+			if seen[m] {
+				t.Fatalf("menção repetida %q em %q", m, content)
+			}
+			seen[m] = true
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+)
+
+// APIKeyPrefix prefixo das chaves de API (distingue de JWT no header Authorization)
+const APIKeyPrefix = "ck_"
+
+// GenerateSecureToken gera token aleatório (32 bytes em hex = 64 caracteres)
+func GenerateSecureToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("falha ao gerar token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// HashToken SHA-256 (hex) de um token; apenas o hash é salvo no banco
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateNumericCode gera código numérico com n dígitos (ex: confirmação por email)
+func GenerateNumericCode(n int) (string, error) {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+	v, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", fmt.Errorf("falha ao gerar código: %w", err)
+	}
+	return fmt.Sprintf("%0*d", n, v), nil
+}
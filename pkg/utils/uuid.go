@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -29,3 +30,12 @@ func StringToUUID(s string) (pgtype.UUID, error) {
 		Valid: true,
 	}, nil
 }
+
+// ConversationKey identificador estável da conversa entre dois usuários
+// (mesmo valor nos dois sentidos); usado como chave dos eventos de mensagem
+func ConversationKey(userA, userB string) string {
+	if strings.Compare(userA, userB) > 0 {
+		userA, userB = userB, userA
+	}
+	return userA + ":" + userB
+}
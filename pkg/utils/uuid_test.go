@@ -0,0 +1,21 @@
+package utils
+
+import "testing"
+
+func TestConversationKeySymmetric(t *testing.T) {
+	const (
+		a = "8f14e45f-ceea-4e67-a5c9-7b1a2d3e4f50"
+		b = "c9f0f895-fb98-4b91-9d2e-6f1a3c5b7d80"
+	)
+
+	if ConversationKey(a, b) != ConversationKey(b, a) {
+		t.Fatalf("ConversationKey(a, b) = %q, ConversationKey(b, a) = %q", ConversationKey(a, b), ConversationKey(b, a))
+	}
+	// Menor ID primeiro, como LEAST/GREATEST nas consultas por par
+	if want := a + ":" + b; ConversationKey(b, a) != want {
+		t.Fatalf("ConversationKey(b, a) = %q; esperado %q", ConversationKey(b, a), want)
+	}
+	if ConversationKey(a, b) == ConversationKey(a, "45c48cce-2e2d-4fbd-8f6e-0a1b2c3d4e5f") {
+		t.Fatal("conversas diferentes com a mesma chave")
+	}
+}
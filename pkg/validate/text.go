@@ -0,0 +1,114 @@
+package validate
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxContentBytes teto em bytes do conteúdo: MaxContentLength caracteres de
+// emoji compostos (família, bandeira) passariam de 100 KB
+const MaxContentBytes = 64 << 10
+
+const (
+	zeroWidthJoiner = '\u200D'
+	lineSeparator   = '\u2028'
+	paraSeparator   = '\u2029'
+)
+
+// Text normaliza texto livre vindo do cliente: NFC (o "é" composto e o
+// decomposto viram o mesmo texto), quebras de linha em \n e remoção de
+// controles e dos marcadores bidi de override/isolamento (texto que aparece
+// numa ordem e é lido em outra). LRM/RLM e ZWJ/ZWNJ ficam: fazem parte de
+// texto RTL, emoji e escritas como persa
+func Text(s string) string {
+	s = norm.NFC.String(s)
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		switch {
+		case r == '\n' || r == '\t':
+			b.WriteRune(r)
+		case r == '\r' || r == lineSeparator || r == paraSeparator:
+			b.WriteRune('\n')
+		case r == utf8.RuneError, unicode.IsControl(r), dangerousFormat(r):
+			// descartado
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// dangerousFormat embeddings/overrides (U+202A-U+202E), isolates
+// (U+2066-U+2069), BOM no meio do texto e anotações interlineares
+func dangerousFormat(r rune) bool {
+	switch {
+	case r >= '\u202A' && r <= '\u202E':
+		return true
+	case r >= '\u2066' && r <= '\u2069':
+		return true
+	case r == '\uFEFF':
+		return true
+	case r >= '\uFFF9' && r <= '\uFFFB':
+		return true
+	}
+	return false
+}
+
+// Graphemes conta caracteres como o usuário os vê (aproximação dos grapheme
+// clusters do UAX #29): marcas combinantes, seletores de variação, tons de
+// pele e tags ficam com o caractere anterior; sequências com ZWJ (família,
+// profissões) e pares de indicadores regionais (bandeiras) contam uma vez
+func Graphemes(s string) int {
+	count := 0
+	joinNext := false     // Último rune foi ZWJ: o próximo faz parte do mesmo caractere
+	regionalOpen := false // Indicador regional aguardando o par
+	for _, r := range s {
+		switch {
+		case joinNext:
+			joinNext = false
+		case r == zeroWidthJoiner:
+			joinNext = count > 0
+			if count == 0 {
+				count++
+			}
+		case extendsPrevious(r) && count > 0:
+		case isRegionalIndicator(r):
+			if regionalOpen {
+				regionalOpen = false
+				continue
+			}
+			regionalOpen = true
+			count++
+			continue
+		default:
+			count++
+		}
+		regionalOpen = false
+	}
+	return count
+}
+
+// extendsPrevious rune que não forma caractere sozinho
+func extendsPrevious(r rune) bool {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return true
+	case r >= '\uFE00' && r <= '\uFE0F', r >= 0xE0100 && r <= 0xE01EF: // Seletores de variação
+		return true
+	case r >= 0x1F3FB && r <= 0x1F3FF: // Tons de pele
+		return true
+	case r >= 0xE0020 && r <= 0xE007F: // Tags (bandeiras de subdivisões)
+		return true
+	}
+	return false
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
@@ -0,0 +1,50 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+func FuzzValidateText(f *testing.F) {
+	for _, seed := range []string{
+		"Olá, tudo bem?\r\nAté já",
+		"é composto",
+		"\u202Egnp.exe\u202C",
+		"\u2066isolado\u2069 \uFEFF bom",
+		"👨\u200D👩\u200D👧 🇧🇷 👍🏽",
+		"\x00\x1b[31mvermelho\x7f",
+		"\xff\xfe inválido",
+		"",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		out := Text(s)
+
+		if !utf8.ValidString(out) {
+			t.Fatalf("Text(%q) = %q: UTF-8 inválido", s, out)
+		}
+		if strings.ContainsRune(out, '\r') {
+			t.Fatalf("Text(%q) = %q: \\r não normalizado", s, out)
+		}
+		for _, r := range out {
+			switch {
+			case r == '\n' || r == '\t':
+			case r == utf8.RuneError, unicode.IsControl(r), dangerousFormat(r):
+				t.Fatalf("Text(%q) = %q: rune %U não removido", s, out, r)
+			case r == lineSeparator || r == paraSeparator:
+				t.Fatalf("Text(%q) = %q: separador %U não virou \\n", s, out, r)
+			}
+		}
+
+		if n := Graphemes(out); n < 0 || n > utf8.RuneCountInString(out) {
+			t.Fatalf("Graphemes(%q) = %d; esperado entre 0 e %d", out, n, utf8.RuneCountInString(out))
+		}
+		if len(out) <= MaxContentBytes && Graphemes(out) <= MaxContentLength && !Content(out) {
+			t.Fatalf("Content(%q) recusou texto dentro dos limites", out)
+		}
+	})
+}
@@ -0,0 +1,110 @@
+// Package validate regras de entrada compartilhadas pelos services (email,
+// username, IDs, tamanho de conteúdo); fora a política de username, os
+// services montam as mensagens de erro
+package validate
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	UsernameMinLength = 3
+	UsernameMaxLength = 50
+
+	MaxContentLength = 5000 // Mensagens, anúncios e notas de suporte
+	MaxEmailLength   = 254  // RFC 5321 (caminho completo)
+)
+
+// DefaultUsernamePattern mesmos caracteres aceitos nas menções (utils.ExtractMentions)
+const DefaultUsernamePattern = `^[A-Za-z0-9_.]+$`
+
+// Email endereço simples (sem nome de exibição), com domínio contendo ponto
+func Email(email string) bool {
+	if email == "" || len(email) > MaxEmailLength {
+		return false
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return false
+	}
+	at := strings.LastIndex(email, "@")
+	domain := email[at+1:]
+	return strings.Contains(domain, ".") && !strings.HasPrefix(domain, ".") && !strings.HasSuffix(domain, ".")
+}
+
+// UsernameLength tamanho entre UsernameMinLength e UsernameMaxLength
+func UsernameLength(username string) bool {
+	return Length(username, UsernameMinLength, UsernameMaxLength)
+}
+
+// UsernamePolicy regras de username do cadastro e da troca
+type UsernamePolicy struct {
+	pattern      *regexp.Regexp
+	leadingDigit bool
+	reserved     map[string]bool
+}
+
+// NewUsernamePolicy cria política com o padrão de caracteres, permissão de
+// começar com número e nomes reservados
+func NewUsernamePolicy(pattern *regexp.Regexp, leadingDigit bool, reserved []string) *UsernamePolicy {
+	p := &UsernamePolicy{pattern: pattern, leadingDigit: leadingDigit, reserved: make(map[string]bool, len(reserved))}
+	for _, name := range reserved {
+		p.reserved[reservedKey(name)] = true
+	}
+	return p
+}
+
+// Check valida o username; o erro é a mensagem exibida ao usuário
+func (p *UsernamePolicy) Check(username string) error {
+	if !UsernameLength(username) {
+		return fmt.Errorf("username deve ter entre %d e %d caracteres", UsernameMinLength, UsernameMaxLength)
+	}
+	if !p.pattern.MatchString(username) {
+		return fmt.Errorf("username contém caracteres não permitidos")
+	}
+	if !p.leadingDigit && username[0] >= '0' && username[0] <= '9' {
+		return fmt.Errorf("username não pode começar com número")
+	}
+	if p.Reserved(username) {
+		return fmt.Errorf("username reservado")
+	}
+	return nil
+}
+
+// Reserved indica nome reservado, sem diferenciar maiúsculas, _ e .
+// ("Ad.Min" e "admin_" batem com "admin")
+func (p *UsernamePolicy) Reserved(username string) bool {
+	return p.reserved[reservedKey(username)]
+}
+
+func reservedKey(name string) string {
+	return strings.NewReplacer("_", "", ".", "").Replace(strings.ToLower(name))
+}
+
+// UUID identificador no formato canônico (com hífens)
+func UUID(id string) bool {
+	if len(id) != 36 {
+		return false
+	}
+	_, err := uuid.Parse(id)
+	return err == nil
+}
+
+// Length tamanho do texto entre min e max caracteres (inclusive), contados
+// como o usuário os vê (Graphemes): emoji e CJK valem um cada
+func Length(s string, min, max int) bool {
+	n := Graphemes(s)
+	return n >= min && n <= max
+}
+
+// Content texto de no máximo MaxContentLength caracteres e MaxContentBytes
+// (vazio é aceito: obrigatoriedade depende do contexto, ex.: mensagem só com
+// anexo). Normalize antes com Text
+func Content(s string) bool {
+	return len(s) <= MaxContentBytes && Length(s, 0, MaxContentLength)
+}